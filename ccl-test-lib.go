@@ -3,53 +3,158 @@
 package ccl_test_lib
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/internal/version"
 	"github.com/CatConfLang/ccl-test-lib/loader"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
-// Version of the ccl-test-lib package
-const Version = "v0.1.0"
+// Version of the ccl-test-lib package, stamped into generated files
+const Version = version.Version
 
 // Quick constructor functions for common use cases
 
-// NewLoader creates a test loader with sensible defaults
-func NewLoader(testDataPath string, cfg config.ImplementationConfig) *loader.TestLoader {
-	return loader.NewTestLoader(testDataPath, cfg)
+// NewLoader creates a test loader with sensible defaults. The load options
+// become its Defaults, used by methods passed the zero LoadOptions.
+func NewLoader(testDataPath string, cfg config.ImplementationConfig, opts ...Option) *loader.TestLoader {
+	o := applyOptions(defaultOptions(), opts)
+	testLoader := loader.NewTestLoaderWithOptions(testDataPath, cfg, o.load)
+	testLoader.Logger = o.logger
+	if o.sourceFallback {
+		generateOpts := o.generate
+		generateOpts.SourceFormat = generator.FormatCompact
+		gen := generator.NewFlatGenerator(testLayout(testDataPath).Dir(loader.FormatCompact), "", generateOpts)
+		testLoader.Flatten = gen.FlattenFile
+	}
+	return testLoader
+}
+
+// testLayout resolves testDataPath's test directories. A root without any
+// resolves to the default names, so loading reports them missing.
+func testLayout(testDataPath string) loader.Layout {
+	layout, err := loader.ResolveLayout(testDataPath)
+	if err != nil && layout.Root == "" {
+		layout.Root = testDataPath
+	}
+	return layout
 }
 
 // NewGenerator creates a flat format generator with sensible defaults
-func NewGenerator(sourceDir, outputDir string) *generator.FlatGenerator {
-	return generator.NewFlatGenerator(sourceDir, outputDir, generator.GenerateOptions{
-		Verbose: true,
-	})
+func NewGenerator(sourceDir, outputDir string, opts ...Option) *generator.FlatGenerator {
+	o := applyOptions(defaultOptions(), opts)
+	return generator.NewFlatGenerator(sourceDir, outputDir, o.generate)
 }
 
-// LoadCompatibleTests is a convenience function for the most common use case
-func LoadCompatibleTests(testDataPath string, cfg config.ImplementationConfig) ([]types.TestCase, error) {
-	testLoader := NewLoader(testDataPath, cfg)
-	return testLoader.LoadAllTests(loader.LoadOptions{
-		Format:     loader.FormatFlat,
-		FilterMode: loader.FilterCompatible,
-	})
+// LoadCompatibleTests is a convenience function for the most common use case.
+// It returns ErrNoTestData if the test data is missing, loader.ErrNoTests if
+// there are no test files or none are compatible, and loader.ErrParse if a
+// file is invalid.
+func LoadCompatibleTests(testDataPath string, cfg config.ImplementationConfig, opts ...Option) ([]types.TestCase, error) {
+	o := applyOptions(defaultOptions(), opts)
+	testLoader := NewLoader(testDataPath, cfg, opts...)
+	tests, err := loadTests(testLoader, o)
+	if err != nil {
+		return nil, err
+	}
+	if len(tests) == 0 && !o.allowEmpty {
+		return nil, fmt.Errorf("%w: none compatible with %s", loader.ErrNoTests, cfg.Name)
+	}
+	return tests, nil
 }
 
-// GenerateFlat is a convenience function for generating flat format from source
-func GenerateFlat(sourceDir, outputDir string) error {
-	gen := NewGenerator(sourceDir, outputDir)
+// GenerateFlat is a convenience function for generating flat format from
+// source. It returns generator.ErrSourceMissing if sourceDir does not exist.
+func GenerateFlat(sourceDir, outputDir string, opts ...Option) error {
+	gen := NewGenerator(sourceDir, outputDir, opts...)
 	return gen.GenerateAll()
 }
 
-// GetTestStats provides quick statistics for a test set
-func GetTestStats(testDataPath string, cfg config.ImplementationConfig) (types.TestStatistics, error) {
-	testLoader := NewLoader(testDataPath, cfg)
-	tests, err := testLoader.LoadAllTests(loader.LoadOptions{
-		Format:     loader.FormatFlat,
-		FilterMode: loader.FilterAll,
-	})
+// GetTestStats provides quick statistics for a test set. It returns
+// ErrNoTestData if the test data is missing, loader.ErrNoTests if there are
+// no test files, and loader.ErrParse if a file is invalid. A current
+// StatsCacheFile (see WriteStatsCache) is used instead of the test files
+// when the options do not filter or rewrite tests.
+func GetTestStats(testDataPath string, cfg config.ImplementationConfig, opts ...Option) (types.TestStatistics, error) {
+	o := statsOptions(opts)
+	testLoader := NewLoader(testDataPath, cfg, opts...)
+	if tests, ok := cachedStatsTests(testDataPath, o); ok {
+		return testLoader.GetTestStatistics(tests), nil
+	}
+
+	tests, err := loadTests(testLoader, o)
 	if err != nil {
 		return types.TestStatistics{}, err
 	}
 	return testLoader.GetTestStatistics(tests), nil
 }
+
+// GetTestStatsByFile provides GetTestStats for each test file separately,
+// in file name order (see loader.TestLoader.GetStatisticsByFile). The stats
+// cache is not used. Errors are as for GetTestStats.
+func GetTestStatsByFile(testDataPath string, cfg config.ImplementationConfig, opts ...Option) ([]types.FileStatistics, error) {
+	o := statsOptions(opts)
+	stats, err := NewLoader(testDataPath, cfg, opts...).GetStatisticsByFile(o.load)
+	if errors.Is(err, loader.ErrNoTests) && o.allowEmpty {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, noTestData(err)
+	}
+	return stats, nil
+}
+
+// GetIncompatibleTests returns the tests an implementation cannot run, each
+// with the reason it is rejected. Together with LoadCompatibleTests it
+// partitions the test set. Errors are as for GetTestStats.
+func GetIncompatibleTests(testDataPath string, cfg config.ImplementationConfig, opts ...Option) ([]loader.IncompatibleTest, error) {
+	return loadIncompatibleTests(testDataPath, cfg, false, opts)
+}
+
+// CountIncompatibleTests returns how many tests an implementation cannot
+// run, by rejection reason
+func CountIncompatibleTests(testDataPath string, cfg config.ImplementationConfig, opts ...Option) (map[string]int, error) {
+	incompatible, err := loadIncompatibleTests(testDataPath, cfg, true, opts)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, test := range incompatible {
+		counts[test.Reason]++
+	}
+	return counts, nil
+}
+
+// loadIncompatibleTests loads every test and keeps the incompatible ones
+func loadIncompatibleTests(testDataPath string, cfg config.ImplementationConfig, lazyExpected bool, opts []Option) ([]loader.IncompatibleTest, error) {
+	defaults := defaultOptions()
+	defaults.load.FilterMode = loader.FilterAll
+	defaults.load.LazyExpected = lazyExpected
+	o := applyOptions(defaults, opts)
+
+	testLoader := NewLoader(testDataPath, cfg, opts...)
+	tests, err := loadTests(testLoader, o)
+	if err != nil {
+		return nil, err
+	}
+	return testLoader.IncompatibleTests(tests), nil
+}
+
+// FindTest loads the flat test with the given name from any test file,
+// whether or not it is compatible with an implementation. It returns
+// ErrNoTestData if the test data is missing and a *loader.AmbiguousTestError
+// if several tests share the name, unless WithDedup is given.
+func FindTest(testDataPath, name string, opts ...Option) (types.TestCase, bool, error) {
+	defaults := defaultOptions()
+	defaults.load.FilterMode = loader.FilterAll
+	o := applyOptions(defaults, opts)
+
+	test, ok, err := NewLoader(testDataPath, config.ImplementationConfig{}, opts...).GetTestByName(name, o.load)
+	if err != nil {
+		return types.TestCase{}, false, noTestData(err)
+	}
+	return test, ok, nil
+}
@@ -2,6 +2,8 @@ package ccl_test_lib
 
 import (
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,6 +11,7 @@ import (
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/generator"
 	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
@@ -23,25 +26,13 @@ const (
 // Re-export loader types
 type LoadOptions = loader.LoadOptions
 
-// Test data setup for integration testing
+// setupIntegrationTestData builds source tests in source_tests and
+// pre-generated flat tests in generated_tests
 func setupIntegrationTestData(t *testing.T) string {
-	tmpDir := t.TempDir()
-
-	// Create test directories
-	testsDir := filepath.Join(tmpDir, "tests")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-	if err := os.MkdirAll(testsDir, 0755); err != nil {
-		t.Fatalf("Failed to create tests directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
-	// Create compact format test data wrapped in CompactTestFile structure
 	compactTests := []loader.CompactTest{
 		{
 			Name:     "integration_test_1",
-			Input:    "name = Alice\nage = 25",
+			Inputs:   []string{"name = Alice\nage = 25"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{
@@ -72,7 +63,7 @@ func setupIntegrationTestData(t *testing.T) string {
 		},
 		{
 			Name:     "integration_test_2",
-			Input:    "enabled = true\ncount = 42",
+			Inputs:   []string{"enabled = true\ncount = 42"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{
@@ -89,22 +80,10 @@ func setupIntegrationTestData(t *testing.T) string {
 		},
 	}
 
-	// Wrap in CompactTestFile structure for correct parsing
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  compactTests,
-	}
-
-	sourceData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	if err := os.WriteFile(filepath.Join(testsDir, "integration.json"), sourceData, 0644); err != nil {
-		t.Fatalf("Failed to write source test file: %v", err)
-	}
-
-	// Create flat format test data (pre-generated)
 	flatTests := []types.TestCase{
 		{
 			Name:       "flat_test_parse",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
 			Functions:  []string{"parse"},
@@ -115,7 +94,7 @@ func setupIntegrationTestData(t *testing.T) string {
 		},
 		{
 			Name:       "flat_test_get_string",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "get_string",
 			Expected:   "value",
 			Args:       []string{"key"},
@@ -127,18 +106,10 @@ func setupIntegrationTestData(t *testing.T) string {
 		},
 	}
 
-	// Wrap flat tests in object format like real ccl-test-data files
-	flatSuite := types.TestSuite{
-		Suite:   "Integration Test Suite",
-		Version: "1.0",
-		Tests:   flatTests,
-	}
-	flatData, _ := json.MarshalIndent(flatSuite, "", "  ")
-	if err := os.WriteFile(filepath.Join(generatedDir, "integration.json"), flatData, 0644); err != nil {
-		t.Fatalf("Failed to write flat test file: %v", err)
-	}
-
-	return tmpDir
+	return testsupport.NewFixture(t).
+		WithCompactFile("integration.json", compactTests...).
+		WithFlatFile("integration.json", flatTests...).
+		Build()
 }
 
 func createTestImplementationConfig() config.ImplementationConfig {
@@ -268,11 +239,15 @@ func TestLoadCompatibleTests_NoTestData(t *testing.T) {
 	// Test with non-existent directory
 	cfg := createTestImplementationConfig()
 
-	tests, err := LoadCompatibleTests("/nonexistent", cfg)
-	if err != nil {
-		t.Fatalf("LoadCompatibleTests should not error on missing directory: %v", err)
+	_, err := LoadCompatibleTests("/nonexistent", cfg)
+	if !errors.Is(err, ErrNoTestData) || !errors.Is(err, loader.ErrNoTests) || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected ErrNoTestData for missing directory, got %v", err)
 	}
 
+	tests, err := LoadCompatibleTests("/nonexistent", cfg, WithAllowEmpty())
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests should not error on missing directory with WithAllowEmpty: %v", err)
+	}
 	if len(tests) != 0 {
 		t.Errorf("Expected 0 tests for non-existent directory, got %d", len(tests))
 	}
@@ -280,7 +255,7 @@ func TestLoadCompatibleTests_NoTestData(t *testing.T) {
 
 func TestGenerateFlat(t *testing.T) {
 	testDataPath := setupIntegrationTestData(t)
-	sourceDir := filepath.Join(testDataPath, "tests")
+	sourceDir := filepath.Join(testDataPath, testsupport.SourceDir)
 	outputDir := filepath.Join(testDataPath, "output")
 
 	err := GenerateFlat(sourceDir, outputDir)
@@ -314,10 +289,10 @@ func TestGenerateFlat(t *testing.T) {
 }
 
 func TestGenerateFlat_NonexistentSource(t *testing.T) {
-	err := GenerateFlat("/nonexistent/source", "/tmp/output")
-	// May not error immediately if no files found, just return without processing
-	// This is acceptable behavior
-	_ = err
+	err := GenerateFlat("/nonexistent/source", t.TempDir())
+	if !errors.Is(err, generator.ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing, got %v", err)
+	}
 }
 
 func TestGetTestStats(t *testing.T) {
@@ -360,12 +335,45 @@ func TestGetTestStats(t *testing.T) {
 	}
 }
 
+func TestGetTestStatsByFile(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+	cfg := parseOnlyConfig()
+
+	stats, err := GetTestStats(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("GetTestStats failed: %v", err)
+	}
+	byFile, err := GetTestStatsByFile(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("GetTestStatsByFile failed: %v", err)
+	}
+	total, compatible := 0, 0
+	for _, file := range byFile {
+		total += file.Stats.TotalTests
+		compatible += file.Stats.CompatibleTests
+	}
+	if len(byFile) == 0 || total != stats.TotalTests || compatible != stats.CompatibleTests {
+		t.Errorf("Expected per-file statistics adding up to %+v, got %+v", stats, byFile)
+	}
+
+	if _, err := GetTestStatsByFile("/nonexistent", cfg); !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData for missing directory, got %v", err)
+	}
+	if files, err := GetTestStatsByFile("/nonexistent", cfg, WithAllowEmpty()); err != nil || len(files) != 0 {
+		t.Errorf("Expected no files with WithAllowEmpty, got %v, %v", files, err)
+	}
+}
+
 func TestGetTestStats_NoTestData(t *testing.T) {
 	cfg := createTestImplementationConfig()
 
-	stats, err := GetTestStats("/nonexistent", cfg)
+	if _, err := GetTestStats("/nonexistent", cfg); !errors.Is(err, ErrNoTestData) {
+		t.Fatalf("Expected ErrNoTestData for missing directory, got %v", err)
+	}
+
+	stats, err := GetTestStats("/nonexistent", cfg, WithAllowEmpty())
 	if err != nil {
-		t.Fatalf("GetTestStats should not error on missing directory: %v", err)
+		t.Fatalf("GetTestStats should not error on missing directory with WithAllowEmpty: %v", err)
 	}
 
 	// Should return empty statistics
@@ -383,7 +391,7 @@ func TestIntegrationWorkflow(t *testing.T) {
 	cfg := createTestImplementationConfig()
 
 	// Step 1: Generate flat format from source
-	sourceDir := filepath.Join(testDataPath, "tests")
+	sourceDir := filepath.Join(testDataPath, testsupport.SourceDir)
 	generatedDir := filepath.Join(testDataPath, "integration-generated")
 
 	err := GenerateFlat(sourceDir, generatedDir)
@@ -486,22 +494,20 @@ func TestConvenienceFunctionsErrorHandling(t *testing.T) {
 
 	// Test LoadCompatibleTests with invalid path
 	_, err := LoadCompatibleTests("/definitely/nonexistent/path", cfg)
-	if err != nil {
-		// This should not error, just return empty results
-		t.Errorf("LoadCompatibleTests should handle missing paths gracefully: %v", err)
+	if !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData for a missing path, got %v", err)
 	}
 
 	// Test GenerateFlat with invalid source
-	err = GenerateFlat("/nonexistent/source", "/tmp/output")
-	// May not error immediately if no files found, just return without processing
-	// This is acceptable behavior
-	_ = err
+	err = GenerateFlat("/nonexistent/source", t.TempDir())
+	if !errors.Is(err, generator.ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing for a missing source, got %v", err)
+	}
 
 	// Test GetTestStats with invalid path
 	_, err = GetTestStats("/nonexistent/path", cfg)
-	if err != nil {
-		// This should not error, just return empty stats
-		t.Errorf("GetTestStats should handle missing paths gracefully: %v", err)
+	if !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData for a missing path, got %v", err)
 	}
 }
 
@@ -519,8 +525,11 @@ func TestMinimalConfiguration(t *testing.T) {
 		VariantChoice:      config.VariantProposed,
 	}
 
-	// Should not error, but should return no compatible tests
-	tests, err := LoadCompatibleTests(testDataPath, minimalConfig)
+	// No compatible tests is reported as ErrNoTests, or an empty result with WithAllowEmpty
+	if _, err := LoadCompatibleTests(testDataPath, minimalConfig); !errors.Is(err, loader.ErrNoTests) || errors.Is(err, ErrNoTestData) {
+		t.Fatalf("Expected ErrNoTests with minimal config, got %v", err)
+	}
+	tests, err := LoadCompatibleTests(testDataPath, minimalConfig, WithAllowEmpty())
 	if err != nil {
 		t.Fatalf("LoadCompatibleTests with minimal config failed: %v", err)
 	}
@@ -584,9 +593,89 @@ func TestEdgeCases(t *testing.T) {
 	}
 
 	// Test error propagation
-	_, err := LoadCompatibleTests("", emptyConfig)
+	_, err := LoadCompatibleTests("", emptyConfig, WithAllowEmpty())
+	if err != nil {
+		t.Errorf("LoadCompatibleTests should handle empty path with WithAllowEmpty: %v", err)
+	}
+}
+
+func TestFindTest(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+
+	test, ok, err := FindTest(testDataPath, "flat_test_get_string")
+	if err != nil || !ok || test.Validation != "get_string" {
+		t.Errorf("Expected flat_test_get_string, got %+v, %v, %v", test, ok, err)
+	}
+	if _, ok, err := FindTest(testDataPath, "missing"); ok || err != nil {
+		t.Errorf("Expected missing not to be found, got %v, %v", ok, err)
+	}
+	if _, _, err := FindTest(filepath.Join(testDataPath, "missing"), "flat_test_parse"); !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData, got %v", err)
+	}
+
+	duplicate, _ := os.ReadFile(filepath.Join(testDataPath, "generated_tests", "integration.json"))
+	os.WriteFile(filepath.Join(testDataPath, "generated_tests", "copy.json"), duplicate, 0644)
+	var ambiguous *loader.AmbiguousTestError
+	if _, _, err := FindTest(testDataPath, "flat_test_parse"); !errors.As(err, &ambiguous) {
+		t.Errorf("Expected AmbiguousTestError, got %v", err)
+	}
+	if _, ok, err := FindTest(testDataPath, "flat_test_parse", WithDedup()); !ok || err != nil {
+		t.Errorf("Expected WithDedup to pick the first, got %v, %v", ok, err)
+	}
+}
+
+func TestGetIncompatibleTests_Partition(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "parse_ok", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "filter_blocked", "inputs": ["a = 1"], "validation": "filter", "expected": {"count": 1}},
+		{"name": "filter_blocked_too", "inputs": ["a = 1"], "validation": "filter", "expected": {"count": 1}},
+		{"name": "comments_blocked", "inputs": ["a = 1"], "validation": "parse", "features": ["comments"], "expected": {"count": 1}},
+		{"name": "crlf_ok", "inputs": ["a = 1"], "validation": "parse", "behaviors": ["crlf_normalize_to_lf"], "expected": {"count": 1}}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(flat), 0644)
+	cfg := parseOnlyConfig()
+	cfg.BehaviorChoices = []config.CCLBehavior{config.BehaviorCRLFNormalize}
+
+	compatible, err := LoadCompatibleTests(dir, cfg)
 	if err != nil {
-		// Should not error, just return empty results
-		t.Errorf("LoadCompatibleTests should handle empty path gracefully: %v", err)
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	incompatible, err := GetIncompatibleTests(dir, cfg)
+	if err != nil {
+		t.Fatalf("GetIncompatibleTests failed: %v", err)
+	}
+	all, err := LoadCompatibleTests(dir, cfg, WithFilterMode(loader.FilterAll))
+	if err != nil {
+		t.Fatalf("Failed to load all tests: %v", err)
+	}
+
+	seen := make(map[string]int)
+	for _, test := range compatible {
+		seen[test.Name]++
+	}
+	for _, test := range incompatible {
+		seen[test.Test.Name]++
+		if test.Reason == "" {
+			t.Errorf("Expected a reason for %s", test.Test.Name)
+		}
+	}
+	if len(seen) != len(all) || len(compatible)+len(incompatible) != len(all) {
+		t.Errorf("Expected compatible and incompatible to partition %d tests, got %d and %d", len(all), len(compatible), len(incompatible))
+	}
+	for name, count := range seen {
+		if count != 1 {
+			t.Errorf("Expected %s in exactly one partition, got %d", name, count)
+		}
+	}
+
+	counts, err := CountIncompatibleTests(dir, cfg)
+	if err != nil {
+		t.Fatalf("CountIncompatibleTests failed: %v", err)
+	}
+	want := map[string]int{"unsupported function filter": 2, "unsupported feature comments": 1}
+	if len(counts) != len(want) || counts["unsupported function filter"] != 2 || counts["unsupported feature comments"] != 1 {
+		t.Errorf("Expected counts %v, got %v", want, counts)
 	}
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/report"
+)
+
+// runCoverage implements "ccltest coverage <dir> --config ccl-impl.json"
+func runCoverage(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("coverage", "<dir> --config ccl-impl.json [flags]", stdout, stderr)
+	configPath := fs.String("config", "", "Implementation config file (required)")
+	format := fs.String("format", formatMarkdown, "Output format: md, json, or csv")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if err := checkReportArgs(positional[0], *configPath, *format); err != nil {
+		return usageError(stderr, err)
+	}
+	cfg, err := config.LoadFile(*configPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	out.debugf("Analyzing coverage of %s for %s", positional[0], cfg.Name)
+	coverage := ccl.NewLoader(positional[0], cfg).GetCapabilityCoverage()
+
+	switch *format {
+	case formatJSON:
+		return writeJSON(out, coverage)
+	case formatCSV:
+		fmt.Fprint(stdout, report.CoverageCSV(coverage))
+	default:
+		fmt.Fprint(stdout, report.ConfigWarningsMarkdown(cfg)+report.CoverageMarkdown(coverage))
+	}
+	return exitOK
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+func TestCoverage(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("coverage", root, "--config", configPath)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "| parse | function | 1 | 1 |") || !strings.Contains(stdout, "| get_string | function | 1 | 1 |") {
+		t.Errorf("Unexpected markdown output:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCommand("coverage", root, "--config", configPath, "--format", "json")
+	if code != exitOK {
+		t.Fatalf("Expected success for json, got %d", code)
+	}
+	var coverage loader.CapabilityCoverage
+	if err := json.Unmarshal([]byte(stdout), &coverage); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(coverage.Functions) != 2 {
+		t.Errorf("Expected 2 functions, got %v", coverage.Functions)
+	}
+}
+
+func TestCoverage_RequiresConfig(t *testing.T) {
+	root, _ := generateCLITestData(t)
+	if code, _, _ := runCommand("coverage", root); code != exitUsage {
+		t.Errorf("Expected usage error without --config, got %d", code)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/CatConfLang/ccl-test-lib/report"
+)
+
+// formatText is the human-readable diff output
+const formatText = "text"
+
+// runDiff implements "ccltest diff <dirA> <dirB>"
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("diff", "<dirA> <dirB> [flags]", stdout, stderr)
+	format := fs.String("format", formatText, "Output format: text or json")
+
+	positional, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return usageError(stderr, fmt.Errorf("unknown format %q (want text or json)", *format))
+	}
+
+	before, err := report.LoadFlatDir(positional[0])
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	after, err := report.LoadFlatDir(positional[1])
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	out.debugf("Comparing %d tests with %d tests", len(before), len(after))
+
+	diff := report.DiffTests(before, after)
+	if *format == formatJSON {
+		if code := writeJSON(out, diff); code != exitOK {
+			return code
+		}
+	} else {
+		fmt.Fprint(stdout, diff.String())
+	}
+	if diff.Empty() {
+		return exitOK
+	}
+	return exitFailure
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/report"
+)
+
+// writeFlatDir writes flat test files into a new directory and returns it
+func writeFlatDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const diffBefore = `{"tests": [
+	{"name": "same_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "b"}]}},
+	{"name": "gone_parse", "inputs": ["x = y"], "validation": "parse", "expected": {"count": 1}},
+	{"name": "value_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "b"}},
+	{"name": "meta_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_preserve_literal"], "features": []}
+]}`
+
+const diffAfter = `{"tests": [
+	{"name": "same_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"entries": [{"key": "a", "value": "b"}], "count": 1}},
+	{"name": "new_parse", "inputs": ["n = 1"], "validation": "parse", "expected": {"count": 1}},
+	{"name": "value_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["b"], "expected": {"count": 1, "value": "c"}},
+	{"name": "meta_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_normalize_to_lf"]}
+]}`
+
+func TestDiff_Command(t *testing.T) {
+	dirA := writeFlatDir(t, map[string]string{"api.json": diffBefore})
+	dirB := writeFlatDir(t, map[string]string{"api.json": diffAfter})
+
+	code, stdout, _ := runCommand("diff", dirA, dirB)
+	if code != exitFailure {
+		t.Fatalf("Expected exit 1 for differences, got %d", code)
+	}
+	golden := `- gone_parse (parse) api.json
++ new_parse (parse) api.json
+~ meta_parse (parse) api.json: behaviors
+~ value_get_string (get_string) api.json: expected, args
+1 added, 1 removed, 2 changed
+`
+	if stdout != golden {
+		t.Errorf("Text output mismatch.\nGot:\n%s\nWant:\n%s", stdout, golden)
+	}
+
+	code, stdout, _ = runCommand("diff", dirA, dirB, "--format", "json")
+	if code != exitFailure {
+		t.Fatalf("Expected exit 1 for differences, got %d", code)
+	}
+	var diff report.TestDiff
+	if err := json.Unmarshal([]byte(stdout), &diff); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 2 {
+		t.Errorf("Unexpected JSON diff: %+v", diff)
+	}
+
+	code, stdout, _ = runCommand("diff", dirA, dirA)
+	if code != exitOK || !strings.Contains(stdout, "0 added, 0 removed, 0 changed") {
+		t.Errorf("Expected no differences, got %d: %s", code, stdout)
+	}
+}
+
+func TestDiff_UsageErrors(t *testing.T) {
+	dir := t.TempDir()
+	if code, _, _ := runCommand("diff", dir); code != exitUsage {
+		t.Errorf("Expected usage error for one directory, got %d", code)
+	}
+	if code, _, _ := runCommand("diff", dir, dir, "--format", "md"); code != exitUsage {
+		t.Errorf("Expected usage error for unknown format, got %d", code)
+	}
+	if code, _, stderr := runCommand("diff", dir, dir); code != exitFailure || !strings.Contains(stderr, "no test files") {
+		t.Errorf("Expected failure for empty directory, got %d: %s", code, stderr)
+	}
+}
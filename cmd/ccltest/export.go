@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// runExport implements "ccltest export <dir> --config ccl-impl.json --out <dir>"
+func runExport(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("export", "<dir> --config ccl-impl.json --out <dir> [flags]", stdout, stderr)
+	configPath := fs.String("config", "", "Implementation config file (required)")
+	outDir := fs.String("out", "", "Directory to write the compatible tests to (required)")
+	check := fs.Bool("check", false, "Only report whether the export in --out is stale")
+	sanitizePath := fs.String("sanitize", "", "Sanitize policy file (JSON types.SanitizePolicy) to strip test metadata with")
+	shardIndex := fs.Int("shard-index", 0, "Export only this shard of the tests, from 0 (requires --shard-count)")
+	shardCount := fs.Int("shard-count", 0, "Split the tests into this many shards by test ID")
+	sample := fs.Int("sample", 0, "Export only a stratified sample of this many tests, at least one per function when it allows")
+	sampleSeed := fs.Int64("sample-seed", 0, "Seed choosing the --sample tests")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if *configPath == "" || *outDir == "" {
+		return usageError(stderr, fmt.Errorf("--config and --out are required"))
+	}
+	if err := loader.CheckShard(*shardIndex, *shardCount); err != nil {
+		return usageError(stderr, fmt.Errorf("--shard-index and --shard-count: %w", err))
+	}
+	if *sample < 0 {
+		return usageError(stderr, fmt.Errorf("--sample must not be negative"))
+	}
+	cfg, err := config.LoadFile(*configPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	dir := positional[0]
+
+	if *check {
+		current, err := ccl.ExportSourceFingerprint(dir, cfg)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		manifest, err := ccl.ReadExportManifest(*outDir)
+		if err != nil {
+			return out.errorf("no export in %s: %v", *outDir, err)
+		}
+		if manifest.SourceFingerprint != current || manifest.ShardIndex != *shardIndex || manifest.ShardCount != *shardCount ||
+			manifest.SampleSize != *sample || manifest.SampleSeed != *sampleSeed {
+			return out.errorf("export in %s is stale; rerun ccltest export", *outDir)
+		}
+		out.infof("Export in %s is up to date (%d tests)", *outDir, manifest.Tests)
+		return exitOK
+	}
+
+	opts := []ccl.Option{ccl.WithShard(*shardIndex, *shardCount), ccl.WithSample(*sample, *sampleSeed)}
+	if *sanitizePath != "" {
+		policy, err := readSanitizePolicy(*sanitizePath)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		opts = append(opts, ccl.WithSanitize(policy))
+	}
+
+	out.debugf("Exporting tests from %s for %s", dir, cfg.Name)
+	manifest, err := ccl.ExportCompatibleTests(dir, cfg, *outDir, opts...)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	out.infof("Exported %d tests in %d files to %s", manifest.Tests, len(manifest.Files), *outDir)
+	if manifest.Sanitized {
+		out.infof("Tests were sanitized; keep %s private if it was written", ccl.ExportNamesFile)
+	}
+	return exitOK
+}
+
+// readSanitizePolicy reads a JSON types.SanitizePolicy, rejecting unknown
+// keys so a misspelled option cannot silently leave metadata in place
+func readSanitizePolicy(path string) (types.SanitizePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.SanitizePolicy{}, fmt.Errorf("failed to read sanitize policy: %w", err)
+	}
+	var policy types.SanitizePolicy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return types.SanitizePolicy{}, fmt.Errorf("failed to parse sanitize policy %s: %w", path, err)
+	}
+	return policy, nil
+}
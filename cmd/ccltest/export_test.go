@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExport_WritesAndChecks(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+	outDir := filepath.Join(root, "vendor", "ccl-tests")
+
+	code, stdout, stderr := runCommand("export", root, "--config", configPath, "--out", outDir)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Exported 2 tests in 2 files") {
+		t.Errorf("Expected summary, got %q", stdout)
+	}
+	for _, name := range []string{"parse.json", "get_string.json", "manifest.json"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("Expected %s: %v", name, err)
+		}
+	}
+	capabilities, err := os.ReadFile(filepath.Join(outDir, "capabilities.json"))
+	if err != nil || !strings.Contains(string(capabilities), `"tests": 2`) || !strings.Contains(string(capabilities), `"variants": {
+    "names": [],`) {
+		t.Errorf("Expected a capability summary, got %s: %v", capabilities, err)
+	}
+
+	if code, stdout, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--check"); code != exitOK || !strings.Contains(stdout, "up to date") {
+		t.Errorf("Expected up-to-date export, got %d: %s%s", code, stdout, stderr)
+	}
+
+	extra := `{"tests": [{"name": "new_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 0}}]}`
+	if err := os.WriteFile(filepath.Join(root, "generated_tests", "api_new.json"), []byte(extra), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--check"); code != exitFailure || !strings.Contains(stderr, "stale") {
+		t.Errorf("Expected stale export, got %d: %s", code, stderr)
+	}
+}
+
+func TestExport_Shard(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+	outDir := filepath.Join(root, "vendor", "ccl-tests")
+
+	total := 0
+	for _, index := range []string{"0", "1"} {
+		code, stdout, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--shard-index", index, "--shard-count", "2")
+		if code != exitOK {
+			t.Fatalf("Expected success for shard %s, got %d: %s", index, code, stderr)
+		}
+		var tests, files int
+		fmt.Sscanf(stdout, "Exported %d tests in %d files", &tests, &files)
+		total += tests
+	}
+	if total != 2 {
+		t.Errorf("Expected the two shards to export 2 tests together, got %d", total)
+	}
+
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--check"); code != exitFailure || !strings.Contains(stderr, "stale") {
+		t.Errorf("Expected a shard export to be stale for an unsharded check, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--shard-index", "1", "--shard-count", "2", "--check"); code != exitOK {
+		t.Errorf("Expected shard 1 of 2 to be up to date, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("export", root, "--config", configPath, "--out", outDir, "--shard-index", "2", "--shard-count", "2"); code != exitUsage {
+		t.Errorf("Expected usage error for shard 2 of 2, got %d", code)
+	}
+}
+
+func TestExport_Sample(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+	outDir := filepath.Join(root, "vendor", "ccl-tests")
+
+	code, stdout, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--sample", "1", "--sample-seed", "9")
+	if code != exitOK || !strings.Contains(stdout, "Exported 1 tests in 1 files") {
+		t.Fatalf("Expected one sampled test, got %d: %s%s", code, stdout, stderr)
+	}
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--sample", "1", "--sample-seed", "9", "--check"); code != exitOK {
+		t.Errorf("Expected the sample to be up to date, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--sample", "1", "--check"); code != exitFailure || !strings.Contains(stderr, "stale") {
+		t.Errorf("Expected another seed to make the sample stale, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("export", root, "--config", configPath, "--out", outDir, "--sample", "-1"); code != exitUsage {
+		t.Errorf("Expected usage error for a negative sample, got %d", code)
+	}
+}
+
+func TestExport_RequiresConfigAndOut(t *testing.T) {
+	if code, _, _ := runCommand("export", t.TempDir(), "--out", t.TempDir()); code != exitUsage {
+		t.Errorf("Expected usage error without --config, got %d", code)
+	}
+}
+
+func TestExport_Sanitize(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+	outDir := filepath.Join(root, "shared")
+	policyPath := filepath.Join(root, "sanitize.json")
+	if err := os.WriteFile(policyPath, []byte(`{"meta_fields": ["tags"], "hash_names": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+
+	code, stdout, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--sanitize", policyPath)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "sanitized") {
+		t.Errorf("Expected a sanitize note, got %q", stdout)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "parse.json"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if strings.Contains(string(data), "basic") || strings.Contains(string(data), `"tags"`) {
+		t.Errorf("Expected names and tags stripped, got %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "names.json")); err != nil {
+		t.Errorf("Expected names.json: %v", err)
+	}
+
+	if err := os.WriteFile(policyPath, []byte(`{"hash_name": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+	if code, _, stderr := runCommand("export", root, "--config", configPath, "--out", outDir, "--sanitize", policyPath); code != exitFailure || !strings.Contains(stderr, "hash_name") {
+		t.Errorf("Expected a misspelled policy key to fail, got %d: %s", code, stderr)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+)
+
+// runGenerate implements "ccltest generate <src> <out>"
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("generate", "<src> <out> [flags]", stdout, stderr)
+	var onlyFunctions listFlag
+	fs.Var(&onlyFunctions, "only-functions", "Comma-separated functions to generate (default all)")
+	skipProperty := fs.Bool("skip-property", false, "Skip property-*.json source files")
+	incremental := fs.Bool("incremental", false, "Only regenerate sources newer than their output")
+	levels := fs.Bool("levels", false, "Write each test's difficulty level into the flat tests")
+	legacyTags := fs.Bool("legacy-tags", false, "Also write function:/feature:/behavior:/variant: tags for old harnesses")
+	entryProvenance := fs.Bool("entry-provenance", false, "Write each expected entry's index in the source expect array")
+	strictArgs := fs.Bool("strict-args", false, "Fail on args a validation does not take instead of dropping them")
+	keepGoing := fs.Bool("keep-going", false, "Generate every source file and report all failures instead of stopping at the first")
+	budgetMode := fs.String("budgets", "off", "Enforce size budgets: off, warn, or error")
+	budgetsFile := fs.String("budgets-file", "", "Size budgets (default "+config.BudgetsFile+" in <src> or a parent, else built-in limits)")
+	watch := fs.Bool("watch", false, "Keep running and regenerate each source file when it changes")
+	interval := fs.Duration("interval", generator.DefaultWatchInterval, "Polling interval for --watch")
+
+	positional, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	sourceDir, outputDir := positional[0], positional[1]
+
+	functions, err := parseFunctions(onlyFunctions)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if _, err := os.Stat(sourceDir); err != nil {
+		return out.errorf("source directory: %v", err)
+	}
+
+	var budgets *config.Budgets
+	var logger *slog.Logger
+	switch *budgetMode {
+	case "off":
+	case "warn", "error":
+		loaded, err := loadBudgets(*budgetsFile, sourceDir)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		budgets = &loaded
+		if *budgetMode == "warn" {
+			logger = slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		}
+	default:
+		return usageError(stderr, fmt.Errorf("unknown --budgets mode %q (want off, warn, or error)", *budgetMode))
+	}
+
+	gen := generator.NewFlatGenerator(sourceDir, outputDir, generator.GenerateOptions{
+		SkipPropertyTests: *skipProperty,
+		OnlyFunctions:     functions,
+		SourceFormat:      generator.FormatCompact,
+		Incremental:       *incremental,
+		EmitLevels:        *levels,
+		EmitLegacyTags:    *legacyTags,
+		EntryProvenance:   *entryProvenance,
+		StrictArgs:        *strictArgs,
+		Budgets:           budgets,
+		LenientBudgets:    *budgetMode == "warn",
+		Logger:            logger,
+		Verbose:           out.verbose && !out.quiet,
+		Progress:          out.progress(),
+		ContinueOnError:   *keepGoing,
+	})
+	generateErr := gen.GenerateAll()
+	if generateErr != nil && !*keepGoing {
+		return out.errorf("%v", generateErr)
+	}
+	if generateErr == nil {
+		if err := gen.ValidateGenerated(); err != nil {
+			return out.errorf("%v", err)
+		}
+	}
+
+	for _, file := range gen.Report().Files {
+		if len(file.SkipFunctions) > 0 {
+			out.infof("%s: skipped %d tests for file-level skip_functions %v", filepath.Base(file.Source), file.FileSkipped, file.SkipFunctions)
+		}
+		for _, dropped := range file.DroppedArgs {
+			out.warnf("%s: dropped args %v from %s validation %s, which takes none", filepath.Base(file.Source), dropped.Args, dropped.Test, dropped.Validation)
+		}
+	}
+	if generateErr != nil {
+		errs := []error{generateErr}
+		if joined, ok := generateErr.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+		for _, err := range errs {
+			out.errorf("%v", err)
+		}
+		return out.errorf("%d source files failed to generate", len(gen.Report().Failures))
+	}
+	out.infof("Generated flat tests in %s", outputDir)
+	if !*watch {
+		return exitOK
+	}
+
+	ctx, stop := watchContext()
+	defer stop()
+	out.infof("Watching %s (Ctrl-C to stop)", sourceDir)
+	err = gen.Watch(ctx, generator.WatchOptions{Interval: *interval}, func(event generator.WatchEvent) {
+		name := filepath.Base(event.Source)
+		if event.Err != nil {
+			out.errorf("%s: %v", name, event.Err)
+			return
+		}
+		out.infof("%s %s: %d flat tests (%+d)", time.Now().Format("15:04:05"), name, event.Tests, event.Tests-event.Previous)
+	})
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	return exitOK
+}
+
+// watchContext returns the context that stops --watch and serve; tests
+// replace it
+var watchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// parseFunctions converts function names, rejecting unknown ones
+func parseFunctions(names []string) ([]config.CCLFunction, error) {
+	known := make(map[config.CCLFunction]bool)
+	for _, fn := range config.AllFunctions() {
+		known[fn] = true
+	}
+
+	var functions []config.CCLFunction
+	for _, name := range names {
+		fn := config.CCLFunction(name)
+		if !known[fn] {
+			return nil, fmt.Errorf("unknown function %q", name)
+		}
+		functions = append(functions, fn)
+	}
+	return functions, nil
+}
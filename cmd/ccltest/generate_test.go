@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func readGeneratedValidations(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	var suite types.TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("Failed to parse %s: %v", path, err)
+	}
+	var validations []string
+	for _, test := range suite.Tests {
+		validations = append(validations, test.Validation)
+	}
+	return validations
+}
+
+func TestGenerate(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	outDir := filepath.Join(root, "generated_tests")
+
+	code, stdout, stderr := runCommand("generate", filepath.Join(root, "tests"), outDir)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Generated flat tests in "+outDir) {
+		t.Errorf("Expected summary line, got %q", stdout)
+	}
+
+	validations := readGeneratedValidations(t, filepath.Join(outDir, "api_basic.json"))
+	if strings.Join(validations, ",") != "parse,get_string" {
+		t.Errorf("Unexpected validations: %v", validations)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "property-roundtrip.json")); err != nil {
+		t.Errorf("Expected property file to be generated: %v", err)
+	}
+}
+
+func TestGenerate_Flags(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	outDir := filepath.Join(root, "generated_tests")
+
+	code, stdout, stderr := runCommand("generate", filepath.Join(root, "tests"), outDir,
+		"--only-functions", "parse", "--skip-property", "--quiet")
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("Expected no output with --quiet, got %q", stdout)
+	}
+
+	validations := readGeneratedValidations(t, filepath.Join(outDir, "api_basic.json"))
+	if strings.Join(validations, ",") != "parse" {
+		t.Errorf("Expected only parse, got %v", validations)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "property-roundtrip.json")); !os.IsNotExist(err) {
+		t.Error("Expected property file to be skipped")
+	}
+}
+
+func TestGenerate_Progress(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	args := []string{"generate", filepath.Join(root, "tests"), filepath.Join(root, "generated_tests")}
+
+	code, _, stderr := runCommand(args...)
+	if code != exitOK || stderr != "" {
+		t.Fatalf("Expected no progress without a terminal, got %d: %q", code, stderr)
+	}
+
+	defer func(orig func(io.Writer) bool) { isTerminal = orig }(isTerminal)
+	isTerminal = func(io.Writer) bool { return true }
+	code, _, stderr = runCommand(args...)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if want := "\rgenerate 0/2\rgenerate 1/2\rgenerate 2/2\n"; stderr != want {
+		t.Errorf("Expected progress line %q, got %q", want, stderr)
+	}
+
+	if code, _, stderr = runCommand(append(args, "--quiet")...); stderr != "" {
+		t.Errorf("Expected no progress with --quiet, got %d: %q", code, stderr)
+	}
+}
+
+func TestGenerate_Incremental(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	outDir := filepath.Join(root, "generated_tests")
+
+	if code, _, stderr := runCommand("generate", sourceDir, outDir, "--quiet"); code != exitOK {
+		t.Fatalf("Initial generate failed: %s", stderr)
+	}
+	output := filepath.Join(outDir, "api_basic.json")
+	if err := os.WriteFile(output, []byte(`{"tests": []}`), 0644); err != nil {
+		t.Fatalf("Failed to overwrite output: %v", err)
+	}
+
+	// The edited output is newer than its source, so it is left alone
+	code, _, stderr := runCommand("generate", sourceDir, outDir, "--incremental", "--quiet")
+	if code != exitOK {
+		t.Fatalf("Incremental generate failed: %s", stderr)
+	}
+	if data, _ := os.ReadFile(output); string(data) != `{"tests": []}` {
+		t.Error("Expected up-to-date output to be skipped")
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	root, _ := setupCLITestData(t)
+
+	if code, _, _ := runCommand("generate", filepath.Join(root, "tests")); code != exitUsage {
+		t.Errorf("Expected usage error for missing output, got %d", code)
+	}
+	if code, _, stderr := runCommand("generate", filepath.Join(root, "tests"), filepath.Join(root, "out"), "--only-functions", "nope"); code != exitUsage || !strings.Contains(stderr, `unknown function "nope"`) {
+		t.Errorf("Expected usage error for unknown function, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("generate", filepath.Join(root, "missing"), filepath.Join(root, "out")); code != exitFailure {
+		t.Errorf("Expected failure for missing source directory, got %d", code)
+	}
+}
+
+func TestGenerate_FileSkipFunctions(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	data, err := os.ReadFile(filepath.Join(sourceDir, "api_basic.json"))
+	if err != nil {
+		t.Fatalf("Failed to read source: %v", err)
+	}
+	data = []byte(strings.Replace(string(data), "{", `{"generator": {"skip_functions": ["get_string"]},`, 1))
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_basic.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	outDir := filepath.Join(root, "generated_tests")
+	code, stdout, stderr := runCommand("generate", sourceDir, outDir)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "api_basic.json: skipped 1 tests for file-level skip_functions [get_string]") {
+		t.Errorf("Expected the file's skip to be reported, got %q", stdout)
+	}
+	if validations := readGeneratedValidations(t, filepath.Join(outDir, "api_basic.json")); strings.Join(validations, ",") != "parse" {
+		t.Errorf("Expected only parse, got %v", validations)
+	}
+}
+
+func TestGenerate_DroppedArgs(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	source := `{"tests": [{"name": "stray", "inputs": ["a = b"], "tests": [{"function": "parse", "args": ["a"], "expect": [{"key": "a", "value": "b"}]}]}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_stray.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	outDir := filepath.Join(root, "generated_tests")
+	code, _, stderr := runCommand("generate", sourceDir, outDir)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "api_stray.json: dropped args [a] from stray validation parse") {
+		t.Errorf("Expected a dropped args warning, got %q", stderr)
+	}
+
+	code, _, stderr = runCommand("generate", "--strict-args", sourceDir, outDir)
+	if code != exitFailure || !strings.Contains(stderr, "source test stray, validation parse") {
+		t.Errorf("Expected --strict-args to fail naming the test, got %d: %s", code, stderr)
+	}
+}
+
+func TestGenerate_KeepGoing(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	for name, content := range map[string]string{"api_broken.json": `{"tests": [`, "api_wrong.json": `{"tests": "none"}`} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+	}
+
+	code, _, stderr := runCommand("generate", sourceDir, filepath.Join(root, "fail-fast"))
+	if code != exitFailure || !strings.Contains(stderr, "api_broken.json") || strings.Contains(stderr, "api_wrong.json") {
+		t.Errorf("Expected only the first failure by default, got %d: %s", code, stderr)
+	}
+
+	outDir := filepath.Join(root, "keep-going")
+	code, _, stderr = runCommand("generate", "--keep-going", sourceDir, outDir)
+	if code != exitFailure {
+		t.Fatalf("Expected failure, got %d: %s", code, stderr)
+	}
+	for _, want := range []string{"api_broken.json", "api_wrong.json", "2 source files failed to generate"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q in %q", want, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "api_basic.json")); err != nil {
+		t.Errorf("Expected the good sources to be generated: %v", err)
+	}
+}
+
+func TestGenerate_Budgets(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	if err := os.WriteFile(filepath.Join(root, "ccl-budgets.json"), []byte(`{"max_input_bytes": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+
+	if code, _, stderr := runCommand("generate", sourceDir, filepath.Join(root, "off")); code != exitOK {
+		t.Errorf("Expected budgets to be off by default, got %d: %s", code, stderr)
+	}
+	code, _, stderr := runCommand("generate", sourceDir, filepath.Join(root, "error"), "--budgets", "error")
+	if code != exitFailure || !strings.Contains(stderr, "over max_input_bytes (1)") {
+		t.Errorf("Expected a budget failure, got %d: %s", code, stderr)
+	}
+	code, _, stderr = runCommand("generate", sourceDir, filepath.Join(root, "warn"), "--budgets", "warn")
+	if code != exitOK || !strings.Contains(stderr, "over budget") {
+		t.Errorf("Expected a budget warning, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("generate", sourceDir, filepath.Join(root, "bad"), "--budgets", "maybe"); code != exitUsage {
+		t.Errorf("Expected usage error for an unknown mode, got %d", code)
+	}
+}
+
+func TestGenerate_Watch(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+
+	original := watchContext
+	defer func() { watchContext = original }()
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 500*time.Millisecond)
+	}
+
+	type result struct {
+		code           int
+		stdout, stderr string
+	}
+	done := make(chan result, 1)
+	go func() {
+		code, stdout, stderr := runCommand("generate", sourceDir, filepath.Join(root, "generated_tests"), "--watch", "--interval", "10ms")
+		done <- result{code, stdout, stderr}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	added := `{"tests": [{"name": "added", "inputs": ["x = 1"], "tests": [{"function": "parse", "expect": []}, {"function": "get_string", "args": ["x"], "expect": "1"}]}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_added.json"), []byte(added), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	r := <-done
+	if r.code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", r.code, r.stderr)
+	}
+	if !strings.Contains(r.stdout, "Watching "+sourceDir) || !strings.Contains(r.stdout, "api_added.json: 2 flat tests (+2)") {
+		t.Errorf("Expected watch summary, got %q", r.stdout)
+	}
+}
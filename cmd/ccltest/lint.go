@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/lint"
+)
+
+// runLint implements "ccltest lint <dir>"
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("lint", "<dir> [flags]", stdout, stderr)
+	var disabled listFlag
+	fs.Var(&disabled, "disable", "Comma-separated rule IDs to skip (repeatable)")
+	sourceDir := fs.String("source-dir", "", "Source tests checked by orphan-source-test")
+	lenientBehaviors := fs.Bool("lenient-behaviors", false, "Report unknown behaviors as warnings")
+	budgetsFile := fs.String("budgets-file", "", "Size budgets (default "+config.BudgetsFile+" in <dir> or a parent, else built-in limits)")
+	flagUsage := fs.Usage
+	fs.Usage = func() {
+		flagUsage()
+		fmt.Fprintln(fs.Output(), "\nRules:")
+		for _, rule := range lint.Rules() {
+			fmt.Fprintf(fs.Output(), "  %-20s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+		}
+	}
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	for _, id := range disabled {
+		if _, ok := lint.LookupRule(id); !ok {
+			return usageError(stderr, fmt.Errorf("unknown rule %q (see -h)", id))
+		}
+	}
+
+	budgets, err := loadBudgets(*budgetsFile, positional[0])
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	findings, err := lint.LintDir(positional[0], lint.Options{
+		Disabled:         disabled,
+		SourceDir:        *sourceDir,
+		Budgets:          &budgets,
+		LenientBehaviors: *lenientBehaviors,
+	})
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	errors := 0
+	for _, finding := range findings {
+		if finding.Severity == lint.SeverityError {
+			errors++
+		}
+		fmt.Fprintln(stderr, finding)
+	}
+	if lint.HasErrors(findings) {
+		return out.errorf("%d errors, %d warnings", errors, len(findings)-errors)
+	}
+
+	out.infof("%d warnings", len(findings))
+	return exitOK
+}
+
+// loadBudgets reads path if set, or else finds the budgets file for dir
+func loadBudgets(path, dir string) (config.Budgets, error) {
+	if path != "" {
+		return config.LoadBudgets(path)
+	}
+	return config.FindBudgets(dir)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_GeneratedTestsClean(t *testing.T) {
+	root, _ := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("lint", filepath.Join(root, "generated_tests"), "--source-dir", filepath.Join(root, "tests"))
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "0 warnings") {
+		t.Errorf("Expected summary, got %q", stdout)
+	}
+}
+
+func TestLint_ErrorsAndDisable(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	bad := `{"tests": [{"name": "basic", "inputs": ["a"], "tests": [{"function": "get_int", "expect": 1}]}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_bad.json"), []byte(bad), 0644); err != nil {
+		t.Fatalf("Failed to write bad file: %v", err)
+	}
+
+	code, _, stderr := runCommand("lint", sourceDir)
+	if code != exitFailure {
+		t.Fatalf("Expected failure, got %d", code)
+	}
+	for _, want := range []string{
+		"api_basic.json: basic: error duplicate-name: also defined in api_bad.json",
+		"api_bad.json: basic: error missing-args: get_int requires args",
+		"2 errors, 0 warnings",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q in output, got %q", want, stderr)
+		}
+	}
+
+	if code, _, stderr := runCommand("lint", sourceDir, "--disable", "duplicate-name,missing-args"); code != exitOK {
+		t.Errorf("Expected success with rules disabled, got %d: %s", code, stderr)
+	}
+}
+
+func TestLint_Budgets(t *testing.T) {
+	root, _ := generateCLITestData(t)
+	generated := filepath.Join(root, "generated_tests")
+	budgetsFile := filepath.Join(t.TempDir(), "budgets.json")
+	if err := os.WriteFile(budgetsFile, []byte(`{"max_tests_per_file": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+
+	code, _, stderr := runCommand("lint", generated, "--budgets-file", budgetsFile)
+	if code != exitFailure || !strings.Contains(stderr, "file-budget") {
+		t.Errorf("Expected a file-budget error, got %d: %s", code, stderr)
+	}
+
+	// A budgets file in a parent directory applies without the flag
+	if err := os.WriteFile(filepath.Join(root, "ccl-budgets.json"), []byte(`{"max_input_bytes": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+	code, _, stderr = runCommand("lint", generated)
+	if code != exitFailure || !strings.Contains(stderr, "input-budget") {
+		t.Errorf("Expected an input-budget error, got %d: %s", code, stderr)
+	}
+}
+
+func TestLint_UnknownRule(t *testing.T) {
+	if code, _, stderr := runCommand("lint", t.TempDir(), "--disable", "nonsense"); code != exitUsage || !strings.Contains(stderr, `unknown rule "nonsense"`) {
+		t.Errorf("Expected usage error, got %d: %s", code, stderr)
+	}
+}
+
+func TestLint_HelpListsRules(t *testing.T) {
+	code, _, stderr := runCommand("lint", "-h")
+	if code != exitOK || !strings.Contains(stderr, "orphan-source-test") {
+		t.Errorf("Expected rules in help, got %d: %s", code, stderr)
+	}
+}
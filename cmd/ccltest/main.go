@@ -0,0 +1,199 @@
+// Command ccltest runs the common CCL test data workflows without writing Go:
+// generating flat tests, validating and linting test files, and reporting
+// statistics and coverage for an implementation config.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// Exit codes
+const (
+	exitOK      = 0 // Success
+	exitFailure = 1 // The command ran and found problems or failed
+	exitUsage   = 2 // Invalid arguments
+)
+
+const usage = `Usage: ccltest <command> [arguments]
+
+Commands:
+  generate <src> <out>   Generate flat format tests from compact source tests
+  validate <dir>         Check test files against the schema and for structural problems
+  lint <dir>             Check test data quality with individually toggleable rules
+  diff <dirA> <dirB>     Report added, removed, and changed flat tests between two directories
+  stats <dir>            Print test statistics for an implementation config
+  coverage <dir>         Print capability coverage for an implementation config
+  export <dir>           Write the tests compatible with a config to another directory
+  preview <dir>          Show which tests a proposed config change would gain and lose
+  verify <dir>           Check a test data repository: sources, generation, schema, and stats
+  repro <results> <test> Write a self-contained bug report for one failing test
+  serve <dir>            Serve read-only JSON endpoints and a summary page for test data
+
+Run 'ccltest <command> -h' for command flags.
+`
+
+// command runs one subcommand and returns its exit code
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"generate": runGenerate,
+	"validate": runValidate,
+	"lint":     runLint,
+	"diff":     runDiff,
+	"stats":    runStats,
+	"coverage": runCoverage,
+	"export":   runExport,
+	"preview":  runPreview,
+	"verify":   runVerify,
+	"repro":    runRepro,
+	"serve":    runServe,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "ccltest: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	return cmd(args[1:], stdout, stderr)
+}
+
+// output writes informational messages according to --quiet and --verbose
+type output struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	quiet   bool
+	verbose bool
+}
+
+// newFlagSet creates a subcommand flag set with the shared --quiet and --verbose flags
+func newFlagSet(name, usageLine string, stdout, stderr io.Writer) (*flag.FlagSet, *output) {
+	out := &output{stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&out.quiet, "quiet", false, "Only print errors")
+	fs.BoolVar(&out.verbose, "verbose", false, "Print progress details")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: ccltest %s %s\n\nFlags:\n", name, usageLine)
+		fs.PrintDefaults()
+	}
+	return fs, out
+}
+
+// infof prints a message unless --quiet is set
+func (o *output) infof(format string, args ...interface{}) {
+	if !o.quiet {
+		fmt.Fprintf(o.stdout, format+"\n", args...)
+	}
+}
+
+// debugf prints a message only when --verbose is set
+func (o *output) debugf(format string, args ...interface{}) {
+	if o.verbose && !o.quiet {
+		fmt.Fprintf(o.stdout, format+"\n", args...)
+	}
+}
+
+// warnf prints a warning to stderr unless --quiet is set
+func (o *output) warnf(format string, args ...interface{}) {
+	if !o.quiet {
+		fmt.Fprintf(o.stderr, "ccltest: warning: "+format+"\n", args...)
+	}
+}
+
+// errorf prints an error and returns exitFailure
+func (o *output) errorf(format string, args ...interface{}) int {
+	fmt.Fprintf(o.stderr, "ccltest: "+format+"\n", args...)
+	return exitFailure
+}
+
+// progress returns a callback that keeps a "stage done/total" line updated
+// on stderr, or nil when stderr is not a terminal or --quiet is set
+func (o *output) progress() loader.ProgressFunc {
+	if o.quiet || !isTerminal(o.stderr) {
+		return nil
+	}
+	return func(stage string, done, total int) {
+		fmt.Fprintf(o.stderr, "\r%s %d/%d", stage, done, total)
+		if done == total {
+			fmt.Fprintln(o.stderr)
+		}
+	}
+}
+
+// isTerminal reports whether w is a terminal; tests replace it
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// parseArgs parses flags that may appear before, between, or after positional
+// arguments and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string, positional int) ([]string, error) {
+	var values []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		values = append(values, args[0])
+		args = args[1:]
+	}
+	if len(values) != positional {
+		fs.Usage()
+		return nil, fmt.Errorf("expected %d arguments, got %d", positional, len(values))
+	}
+	return values, nil
+}
+
+// usageError reports an argument problem and returns the usage exit code
+func usageError(stderr io.Writer, err error) int {
+	if err != flag.ErrHelp {
+		fmt.Fprintf(stderr, "ccltest: %v\n", err)
+		return exitUsage
+	}
+	return exitOK
+}
+
+// listFlag collects comma-separated values from one or more uses of a flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
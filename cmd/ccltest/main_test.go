@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// runCommand runs the CLI with args and returns the exit code and outputs
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// setupCLITestData writes compact sources to <root>/tests and a config file,
+// returning the test data root and config path
+func setupCLITestData(t *testing.T) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "tests")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatalf("Failed to create source directory: %v", err)
+	}
+
+	sources := map[string][]loader.CompactTest{
+		"api_basic.json": {
+			{
+				Name:   "basic",
+				Inputs: []string{"a = b"},
+				Tests: []loader.CompactValidation{
+					{Function: "parse", Expect: []map[string]interface{}{{"key": "a", "value": "b"}}},
+					{Function: "get_string", Args: []string{"a"}, Expect: "b"},
+				},
+			},
+		},
+		"property-roundtrip.json": {
+			{
+				Name:     "comments",
+				Inputs:   []string{"/= note\na = b"},
+				Features: []string{"comments"},
+				Tests: []loader.CompactValidation{
+					{Function: "filter", Expect: []map[string]interface{}{{"key": "a", "value": "b"}}},
+				},
+			},
+		},
+	}
+	for name, tests := range sources {
+		data, _ := json.MarshalIndent(loader.CompactTestFile{Schema: "../schemas/source-format.json", Tests: tests}, "", "  ")
+		if err := os.WriteFile(filepath.Join(sourceDir, name), data, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	configPath := filepath.Join(root, "ccl-impl.json")
+	configData := `{"name": "cli-ccl", "version": "v1.0.0", "supported_functions": ["parse", "get_string"]}`
+	if err := os.WriteFile(configPath, []byte(configData), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return root, configPath
+}
+
+// generateCLITestData runs generate so stats and coverage have flat tests to read
+func generateCLITestData(t *testing.T) (string, string) {
+	t.Helper()
+	root, configPath := setupCLITestData(t)
+	code, _, stderr := runCommand("generate", filepath.Join(root, "tests"), filepath.Join(root, "generated_tests"), "--quiet")
+	if code != exitOK {
+		t.Fatalf("generate failed with %d: %s", code, stderr)
+	}
+	return root, configPath
+}
+
+func TestRun_Usage(t *testing.T) {
+	if code, _, stderr := runCommand(); code != exitUsage || !strings.Contains(stderr, "Usage: ccltest") {
+		t.Errorf("Expected usage error, got %d: %s", code, stderr)
+	}
+	if code, stdout, _ := runCommand("--help"); code != exitOK || !strings.Contains(stdout, "generate <src> <out>") {
+		t.Errorf("Expected help output, got %d: %s", code, stdout)
+	}
+	if code, _, stderr := runCommand("frobnicate"); code != exitUsage || !strings.Contains(stderr, `unknown command "frobnicate"`) {
+		t.Errorf("Expected unknown command error, got %d: %s", code, stderr)
+	}
+}
+
+func TestParseArgs_InterleavedFlags(t *testing.T) {
+	fs, out := newFlagSet("test", "", &bytes.Buffer{}, &bytes.Buffer{})
+	positional, err := parseArgs(fs, []string{"--verbose", "a", "--quiet", "b"}, 2)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if strings.Join(positional, ",") != "a,b" || !out.quiet || !out.verbose {
+		t.Errorf("Unexpected parse result: %v quiet=%v verbose=%v", positional, out.quiet, out.verbose)
+	}
+
+	if _, err := parseArgs(fs, []string{"a"}, 2); err == nil {
+		t.Error("Expected error for missing argument")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// runPreview implements "ccltest preview <dir> --config old.json --new-config new.json"
+func runPreview(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("preview", "<dir> --config old.json --new-config new.json [flags]", stdout, stderr)
+	configPath := fs.String("config", "", "Current implementation config file (required)")
+	newConfigPath := fs.String("new-config", "", "Proposed implementation config file (required)")
+	format := fs.String("format", formatText, "Output format: text or json")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return usageError(stderr, fmt.Errorf("unknown format %q (want text or json)", *format))
+	}
+	if *configPath == "" || *newConfigPath == "" {
+		return usageError(stderr, fmt.Errorf("--config and --new-config are required"))
+	}
+	before, err := config.LoadFile(*configPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	after, err := config.LoadFile(*newConfigPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	out.debugf("Previewing %s -> %s against %s", *configPath, *newConfigPath, positional[0])
+	preview, err := ccl.PreviewConfigChange(positional[0], before, after, ccl.WithProgress(out.progress()))
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	if *format == formatJSON {
+		if code := writeJSON(out, preview); code != exitOK {
+			return code
+		}
+	} else {
+		fmt.Fprint(stdout, preview.Config.String())
+		for _, test := range preview.Gained {
+			fmt.Fprintf(stdout, "+ %s (was: %s)\n", test.Name, test.Reason)
+		}
+		for _, test := range preview.Lost {
+			fmt.Fprintf(stdout, "- %s (%s)\n", test.Name, test.Reason)
+		}
+		fmt.Fprintf(stdout, "%d newly compatible, %d newly incompatible, %d unchanged, %d incompatible with both\n",
+			len(preview.Gained), len(preview.Lost), preview.Unchanged, preview.Incompatible)
+	}
+	if len(preview.Lost) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
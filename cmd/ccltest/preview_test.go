@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+)
+
+const previewTests = `{"tests": [
+	{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}},
+	{"name": "value_get_float", "inputs": ["a = 1.5"], "validation": "get_float", "args": ["a"], "expected": {"count": 1, "value": 1.5}},
+	{"name": "crlf_keep_parse", "inputs": ["a = b\r\n"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_preserve_literal"]},
+	{"name": "crlf_normalize_parse", "inputs": ["a = b\r\n"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_normalize_to_lf"]},
+	{"name": "comment_parse", "inputs": ["/= note"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"]}
+]}`
+
+// setupPreviewData writes previewTests and the current config, returning the
+// test data root and the config path
+func setupPreviewData(t *testing.T) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "generated_tests"), 0755)
+	os.WriteFile(filepath.Join(root, "generated_tests", "api.json"), []byte(previewTests), 0644)
+	return root, writePreviewConfig(t, root, "old.json", `{"name": "impl", "supported_functions": ["parse"], "behavior_choices": ["crlf_preserve_literal"]}`)
+}
+
+func writePreviewConfig(t *testing.T, root, name, content string) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestPreview_Addition(t *testing.T) {
+	root, oldConfig := setupPreviewData(t)
+	newConfig := writePreviewConfig(t, root, "new.json", `{"name": "impl", "supported_functions": ["parse", "get_float"], "behavior_choices": ["crlf_preserve_literal"]}`)
+
+	code, stdout, stderr := runCommand("preview", root, "--config", oldConfig, "--new-config", newConfig)
+	if code != exitOK {
+		t.Fatalf("Expected success when no tests are lost, got %d: %s", code, stderr)
+	}
+	golden := `+ function get_float
++ value_get_float (was: unsupported function get_float)
+1 newly compatible, 0 newly incompatible, 2 unchanged, 2 incompatible with both
+`
+	if stdout != golden {
+		t.Errorf("Text output mismatch.\nGot:\n%s\nWant:\n%s", stdout, golden)
+	}
+}
+
+func TestPreview_BehaviorSwitch(t *testing.T) {
+	root, oldConfig := setupPreviewData(t)
+	newConfig := writePreviewConfig(t, root, "new.json", `{"name": "impl", "supported_functions": ["parse", "get_float"], "behavior_choices": ["crlf_normalize_to_lf"]}`)
+
+	code, stdout, _ := runCommand("preview", root, "--config", oldConfig, "--new-config", newConfig)
+	if code != exitFailure {
+		t.Fatalf("Expected exit 1 when tests are lost, got %d", code)
+	}
+	golden := `+ function get_float
++ behavior crlf_normalize_to_lf
+- behavior crlf_preserve_literal
++ value_get_float (was: unsupported function get_float)
++ crlf_normalize_parse (was: requires behavior crlf_normalize_to_lf)
+- crlf_keep_parse (requires behavior crlf_preserve_literal)
+2 newly compatible, 1 newly incompatible, 1 unchanged, 1 incompatible with both
+`
+	if stdout != golden {
+		t.Errorf("Text output mismatch.\nGot:\n%s\nWant:\n%s", stdout, golden)
+	}
+
+	code, stdout, _ = runCommand("preview", root, "--config", oldConfig, "--new-config", newConfig, "--format", "json")
+	if code != exitFailure {
+		t.Fatalf("Expected exit 1 for json, got %d", code)
+	}
+	var preview ccl.ConfigPreview
+	if err := json.Unmarshal([]byte(stdout), &preview); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(preview.Gained) != 2 || len(preview.Lost) != 1 || preview.Lost[0].Name != "crlf_keep_parse" || preview.Unchanged != 1 {
+		t.Errorf("Unexpected preview: %+v", preview)
+	}
+}
+
+func TestPreview_RequiresConfigs(t *testing.T) {
+	root, oldConfig := setupPreviewData(t)
+	if code, _, _ := runCommand("preview", root, "--config", oldConfig); code != exitUsage {
+		t.Errorf("Expected usage error without --new-config, got %d", code)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// runRepro implements "ccltest repro <results.json> <test-name>"
+func runRepro(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("repro", "<results.json> <test-name> [flags]", stdout, stderr)
+	sourceDir := fs.String("source-dir", loader.DefaultSourceDir, "Directory of compact source tests")
+	output := fs.String("o", "", "Write the repro to this file instead of stdout")
+
+	positional, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	resultsPath, name := positional[0], positional[1]
+
+	results, err := runner.LoadResults(resultsPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	var result *runner.TestResult
+	for i := range results.Results {
+		if results.Results[i].Name == name {
+			result = &results.Results[i]
+			break
+		}
+	}
+	if result == nil {
+		return out.errorf("no result for %q in %s", name, resultsPath)
+	}
+	if result.Status != runner.StatusFail {
+		out.warnf("%s did not fail (%s)", name, result.Status)
+	}
+
+	sources, err := loadSourceTests(*sourceDir)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	lookup := func(name string) (loader.CompactTest, bool) {
+		test, ok := sources[name]
+		return test, ok
+	}
+
+	w := stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := runner.ExportRepro(*result, lookup, w); err != nil {
+		return out.errorf("%v", err)
+	}
+	if *output != "" {
+		out.infof("Wrote repro for %s to %s", name, *output)
+	}
+	return exitOK
+}
+
+// loadSourceTests reads every compact source test in dir by name; the first
+// of several tests with one name wins
+func loadSourceTests(dir string) (map[string]loader.CompactTest, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no source files in %s", dir)
+	}
+	sort.Strings(files)
+
+	tests := make(map[string]loader.CompactTest)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		suites, err := loader.SplitSuites(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		for _, suite := range suites {
+			var source loader.CompactTestFile
+			if err := json.Unmarshal(loader.StripBOM(suite), &source); err != nil {
+				return nil, fmt.Errorf("%s: failed to parse source tests: %w", filepath.Base(file), err)
+			}
+			for _, test := range source.Tests {
+				if _, ok := tests[test.Name]; !ok {
+					tests[test.Name] = test
+				}
+			}
+		}
+	}
+	return tests, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// writeReproData writes a source test and the results of running its
+// get_int validation against the toy implementation with a wrong expectation
+func writeReproData(t *testing.T) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	if err := os.Mkdir(sourceDir, 0755); err != nil {
+		t.Fatalf("Failed to create source directory: %v", err)
+	}
+	source := `{"tests": [{"name": "port", "inputs": ["port = 8080"], "tests": [{"function": "get_int", "args": ["port"], "expect": 80}]}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_typed.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	cfg := toyccl.Config()
+	flat := types.TestCase{Name: "port_get_int", Inputs: []string{"port = 8080"}, Validation: "get_int", Args: []string{"port"}, Expected: float64(80)}
+	resultsPath := filepath.Join(root, "results.json")
+	if err := runner.SaveResults(resultsPath, runner.Run(toyccl.New(), []types.TestCase{flat}, runner.RunOptions{Config: &cfg})); err != nil {
+		t.Fatalf("Failed to save results: %v", err)
+	}
+	return resultsPath, sourceDir
+}
+
+func TestRepro_Command(t *testing.T) {
+	resultsPath, sourceDir := writeReproData(t)
+
+	code, stdout, stderr := runCommand("repro", resultsPath, "port_get_int", "--source-dir", sourceDir)
+	if code != exitOK {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr)
+	}
+	var repro runner.Repro
+	if err := json.Unmarshal([]byte(stdout), &repro); err != nil {
+		t.Fatalf("Invalid repro JSON: %v\n%s", err, stdout)
+	}
+	if repro.Source.Name != "port" || repro.Flat.Inputs[0] != "port = 8080" || repro.Result.Actual != float64(8080) {
+		t.Errorf("Incomplete repro: %+v", repro)
+	}
+	if cfg := toyccl.Config(); repro.ConfigFingerprint != cfg.Fingerprint() {
+		t.Errorf("Expected the config fingerprint, got %q", repro.ConfigFingerprint)
+	}
+
+	output := filepath.Join(t.TempDir(), "repro.json")
+	if code, stdout, _ := runCommand("repro", resultsPath, "port_get_int", "--source-dir", sourceDir, "-o", output); code != exitOK || !strings.Contains(stdout, "Wrote repro") {
+		t.Errorf("Expected the repro written to a file, got %d: %s", code, stdout)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("Expected %s to exist: %v", output, err)
+	}
+}
+
+func TestRepro_Errors(t *testing.T) {
+	resultsPath, sourceDir := writeReproData(t)
+	if code, _, _ := runCommand("repro", resultsPath); code != exitUsage {
+		t.Errorf("Expected usage error for a missing test name, got %d", code)
+	}
+	if code, _, stderr := runCommand("repro", resultsPath, "nope", "--source-dir", sourceDir); code != exitFailure || !strings.Contains(stderr, `no result for "nope"`) {
+		t.Errorf("Expected failure for an unknown test, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCommand("repro", resultsPath, "port_get_int", "--source-dir", t.TempDir()); code != exitFailure || !strings.Contains(stderr, "no source files") {
+		t.Errorf("Expected failure without sources, got %d: %s", code, stderr)
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// runServe implements "ccltest serve <dir> --addr :8080"
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("serve", "<dir> [--addr :8080] [--config ccl-impl.json ...] [flags]", stdout, stderr)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	var configPaths listFlag
+	fs.Var(&configPaths, "config", "Implementation config files the config parameter can name (repeatable)")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	dir := positional[0]
+	if _, err := generatedDir(dir); err != nil {
+		return usageError(stderr, err)
+	}
+	configs := make(map[string]config.ImplementationConfig)
+	for _, path := range configPaths {
+		cfg, err := config.LoadFile(path)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		if _, ok := configs[cfg.Name]; ok {
+			return out.errorf("%s: config %q is already loaded", path, cfg.Name)
+		}
+		configs[cfg.Name] = cfg
+	}
+
+	server := &http.Server{Addr: *addr, Handler: newServeHandler(dir, configs), ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := watchContext()
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	out.infof("Serving %s on %s (Ctrl-C to stop)", dir, *addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return out.errorf("%v", err)
+	}
+	return exitOK
+}
+
+// testCorpus holds every flat test in a directory, reloading them only
+// when the generated files change
+type testCorpus struct {
+	dir string
+
+	mu    sync.Mutex
+	stamp string
+	tests []types.TestCase
+}
+
+// load returns the current tests
+func (c *testCorpus) load() ([]types.TestCase, error) {
+	stamp, err := c.currentStamp()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tests != nil && stamp == c.stamp {
+		return c.tests, nil
+	}
+	tests, err := ccl.NewLoader(c.dir, config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{
+		Format:     loader.FormatFlat,
+		FilterMode: loader.FilterAll,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.stamp, c.tests = stamp, tests
+	return tests, nil
+}
+
+// currentStamp identifies the generated files by name, size, and
+// modification time, which is enough to notice regeneration
+func (c *testCorpus) currentStamp() (string, error) {
+	generated, err := generatedDir(c.dir)
+	if err != nil {
+		return "", err
+	}
+	files, err := loader.ScanDir(generated, loader.DefaultScanOptions())
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// testSummary is a test as /tests lists it; /test/{id} returns the whole test
+type testSummary struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Validation string   `json:"validation"`
+	Functions  []string `json:"functions,omitempty"`
+	Features   []string `json:"features,omitempty"`
+	Behaviors  []string `json:"behaviors,omitempty"`
+	Variants   []string `json:"variants,omitempty"`
+	SourceTest string   `json:"source_test,omitempty"`
+}
+
+// serveHandler answers the read-only endpoints of ccltest serve
+type serveHandler struct {
+	corpus  *testCorpus
+	configs map[string]config.ImplementationConfig
+}
+
+// newServeHandler returns the handler for dir's flat tests; the config
+// query parameter names one of configs
+func newServeHandler(dir string, configs map[string]config.ImplementationConfig) http.Handler {
+	h := &serveHandler{corpus: &testCorpus{dir: dir}, configs: configs}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.index)
+	mux.HandleFunc("GET /stats", h.stats)
+	mux.HandleFunc("GET /tests", h.list)
+	mux.HandleFunc("GET /test/{id}", h.test)
+	mux.HandleFunc("GET /coverage", h.coverage)
+	return mux
+}
+
+// config returns the config the request names, the empty config when it
+// names none and required is false, or an error for the client
+func (h *serveHandler) config(r *http.Request, required bool) (config.ImplementationConfig, error) {
+	name := r.URL.Query().Get("config")
+	if name == "" && !required {
+		return config.ImplementationConfig{}, nil
+	}
+	cfg, ok := h.configs[name]
+	if !ok {
+		known := make([]string, 0, len(h.configs))
+		for configName := range h.configs {
+			known = append(known, configName)
+		}
+		slices.Sort(known)
+		if name == "" {
+			return cfg, fmt.Errorf("config is required (one of: %s)", strings.Join(known, ", "))
+		}
+		return cfg, fmt.Errorf("unknown config %q (one of: %s)", name, strings.Join(known, ", "))
+	}
+	return cfg, nil
+}
+
+func (h *serveHandler) stats(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.config(r, false)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tests, err := h.corpus.load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, loader.NewTestLoader(h.corpus.dir, cfg).GetTestStatistics(tests))
+}
+
+func (h *serveHandler) list(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.config(r, false)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query := r.URL.Query()
+	function, feature := query.Get("function"), query.Get("feature")
+	if function != "" && !slices.Contains(config.AllFunctions(), config.CCLFunction(function)) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown function %q", function))
+		return
+	}
+	if feature != "" && !slices.Contains(config.AllFeatures(), config.CCLFeature(feature)) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown feature %q", feature))
+		return
+	}
+	tests, err := h.corpus.load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	compat := loader.NewTestLoader(h.corpus.dir, cfg)
+	summaries := []testSummary{}
+	for _, test := range tests {
+		if function != "" && test.Validation != function && !slices.Contains(test.Functions, function) {
+			continue
+		}
+		if feature != "" && !slices.Contains(test.Features, feature) {
+			continue
+		}
+		if query.Get("config") != "" && !compat.IsTestCompatible(test) {
+			continue
+		}
+		summaries = append(summaries, testSummary{
+			ID:         test.ID(),
+			Name:       test.Name,
+			Validation: test.Validation,
+			Functions:  test.Functions,
+			Features:   test.Features,
+			Behaviors:  test.Behaviors,
+			Variants:   test.Variants,
+			SourceTest: test.SourceTest,
+		})
+	}
+	writeResponse(w, map[string]interface{}{"count": len(summaries), "tests": summaries})
+}
+
+func (h *serveHandler) test(w http.ResponseWriter, r *http.Request) {
+	tests, err := h.corpus.load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	id := r.PathValue("id")
+	for _, test := range tests {
+		if test.ID() == id {
+			writeResponse(w, test)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("no test with id %q", id))
+}
+
+func (h *serveHandler) coverage(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.config(r, true)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tests, err := h.corpus.load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, loader.NewTestLoader(h.corpus.dir, cfg).CoverageOf(tests))
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>ccltest: {{.Dir}}</title></head>
+<body>
+<h1>{{.Dir}}</h1>
+<p>JSON: <a href="stats">/stats</a>, <a href="tests">/tests</a>, /test/{id}, /coverage?config=name{{range .Configs}} (<a href="coverage?config={{.}}">{{.}}</a>){{end}}</p>
+<pre>{{.Report}}</pre>
+</body>
+</html>
+`))
+
+func (h *serveHandler) index(w http.ResponseWriter, r *http.Request) {
+	tests, err := h.corpus.load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	configs := make([]string, 0, len(h.configs))
+	for name := range h.configs {
+		configs = append(configs, name)
+	}
+	slices.Sort(configs)
+	stats := loader.NewTestLoader(h.corpus.dir, config.ImplementationConfig{}).GetTestStatistics(tests)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	indexTemplate.Execute(w, struct {
+		Dir     string
+		Configs []string
+		Report  string
+	}{filepath.Base(filepath.Clean(h.corpus.dir)), configs, report.StatisticsMarkdown(stats)})
+}
+
+func writeResponse(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
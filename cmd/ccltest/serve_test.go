@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// newTestServeHandler serves the generated CLI test data with its config
+func newTestServeHandler(t *testing.T) (http.Handler, string) {
+	t.Helper()
+	root, configPath := generateCLITestData(t)
+	cfg, err := config.LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return newServeHandler(root, map[string]config.ImplementationConfig{cfg.Name: cfg}), root
+}
+
+// serveGet requests path and decodes a JSON response into value
+func serveGet(t *testing.T, handler http.Handler, path string, value interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if value != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), value); err != nil {
+			t.Fatalf("%s: invalid JSON: %v\n%s", path, err, rec.Body)
+		}
+	}
+	return rec
+}
+
+type testList struct {
+	Count int           `json:"count"`
+	Tests []testSummary `json:"tests"`
+}
+
+func TestServe_Stats(t *testing.T) {
+	handler, _ := newTestServeHandler(t)
+
+	var all types.TestStatistics
+	if rec := serveGet(t, handler, "/stats", &all); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if all.TotalTests != 3 || all.CompatibleTests != 0 {
+		t.Errorf("Expected 3 tests and none compatible without a config, got %+v", all)
+	}
+
+	var stats types.TestStatistics
+	serveGet(t, handler, "/stats?config=cli-ccl", &stats)
+	if stats.TotalTests != 3 || stats.CompatibleTests != 2 {
+		t.Errorf("Expected 2 of 3 tests compatible with cli-ccl, got %+v", stats)
+	}
+
+	rec := serveGet(t, handler, "/stats?config=nope", nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `unknown config \"nope\" (one of: cli-ccl)`) {
+		t.Errorf("Expected 400 for an unknown config, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestServe_Tests(t *testing.T) {
+	handler, _ := newTestServeHandler(t)
+
+	var list testList
+	serveGet(t, handler, "/tests", &list)
+	if list.Count != 3 || len(list.Tests) != 3 {
+		t.Errorf("Expected all 3 tests, got %+v", list)
+	}
+
+	serveGet(t, handler, "/tests?function=filter&feature=comments", &list)
+	if list.Count != 1 || list.Tests[0].Name != "comments_filter" {
+		t.Errorf("Expected only comments_filter, got %+v", list)
+	}
+
+	serveGet(t, handler, "/tests?config=cli-ccl&function=filter", &list)
+	if list.Count != 0 || list.Tests == nil {
+		t.Errorf("Expected an empty list for filter with cli-ccl, got %+v", list)
+	}
+
+	for path, want := range map[string]string{
+		"/tests?function=frobnicate": `unknown function \"frobnicate\"`,
+		"/tests?feature=telepathy":   `unknown feature \"telepathy\"`,
+		"/tests?config=nope":         `unknown config \"nope\"`,
+	} {
+		if rec := serveGet(t, handler, path, nil); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("%s: expected 400 with %s, got %d: %s", path, want, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestServe_Test(t *testing.T) {
+	handler, _ := newTestServeHandler(t)
+
+	var list testList
+	serveGet(t, handler, "/tests?function=get_string", &list)
+	if list.Count != 1 {
+		t.Fatalf("Expected one get_string test, got %+v", list)
+	}
+	var test types.TestCase
+	if rec := serveGet(t, handler, "/test/"+list.Tests[0].ID, &test); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if test.Name != "basic_get_string" || strings.Join(test.Args, ",") != "a" {
+		t.Errorf("Expected the whole basic_get_string test, got %+v", test)
+	}
+
+	if rec := serveGet(t, handler, "/test/0000", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown id, got %d", rec.Code)
+	}
+}
+
+func TestServe_Coverage(t *testing.T) {
+	handler, _ := newTestServeHandler(t)
+
+	var coverage map[string]map[string]map[string]int
+	if rec := serveGet(t, handler, "/coverage?config=cli-ccl", &coverage); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if parse := coverage["Functions"]["parse"]; parse["Available"] != 1 || parse["Compatible"] != 1 {
+		t.Errorf("Expected parse fully covered, got %v", coverage)
+	}
+
+	rec := serveGet(t, handler, "/coverage", nil)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "config is required (one of: cli-ccl)") {
+		t.Errorf("Expected 400 without a config, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestServe_IndexAndReadOnly(t *testing.T) {
+	handler, _ := newTestServeHandler(t)
+
+	rec := serveGet(t, handler, "/", nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected an HTML index, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "| Total tests | 3 |") || !strings.Contains(body, `coverage?config=cli-ccl`) {
+		t.Errorf("Expected the statistics report and config links, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/stats", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+	if rec := serveGet(t, handler, "/nowhere", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown path, got %d", rec.Code)
+	}
+}
+
+func TestServe_ReloadsRegeneratedTests(t *testing.T) {
+	handler, root := newTestServeHandler(t)
+	var list testList
+	serveGet(t, handler, "/tests", &list)
+	if list.Count != 3 {
+		t.Fatalf("Expected 3 tests, got %d", list.Count)
+	}
+
+	if err := os.Remove(filepath.Join(root, "generated_tests", "property-roundtrip.json")); err != nil {
+		t.Fatalf("Failed to remove generated file: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(root, "generated_tests"), later, later)
+	serveGet(t, handler, "/tests", &list)
+	if list.Count != 2 {
+		t.Errorf("Expected 2 tests after a generated file was removed, got %d", list.Count)
+	}
+}
+
+func TestServe_Usage(t *testing.T) {
+	if code, _, stderr := runCommand("serve", t.TempDir()); code != exitUsage || !strings.Contains(stderr, "has no generated_tests directory") {
+		t.Errorf("Expected usage error without generated tests, got %d: %s", code, stderr)
+	}
+	root, _ := generateCLITestData(t)
+	if code, _, stderr := runCommand("serve", root, "--config", filepath.Join(root, "missing.json")); code != exitFailure {
+		t.Errorf("Expected failure for a missing config, got %d: %s", code, stderr)
+	}
+}
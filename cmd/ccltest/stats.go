@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+)
+
+// Output formats for stats and coverage
+const (
+	formatMarkdown = "md"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+)
+
+// topBlocked is how many rejection reasons the markdown stats report lists
+const topBlocked = 10
+
+// topExpectedSizes is how many of the largest expected values it lists
+const topExpectedSizes = 5
+
+// runStats implements "ccltest stats <dir> --config ccl-impl.json"
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("stats", "<dir> --config ccl-impl.json [flags]", stdout, stderr)
+	configPath := fs.String("config", "", "Implementation config file (required)")
+	format := fs.String("format", formatMarkdown, "Output format: md, json, or csv")
+	selectors := addInputSelectors(fs)
+	writeCache := fs.Bool("write-cache", false, "Write "+ccl.StatsCacheFile+" so later stats skip parsing unchanged tests")
+	byFile := fs.Bool("by-file", false, "Group statistics by test file (md and json)")
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	if err := checkReportArgs(positional[0], *configPath, *format); err != nil {
+		return usageError(stderr, err)
+	}
+	if *byFile && *format == formatCSV {
+		return usageError(stderr, fmt.Errorf("--by-file does not support csv"))
+	}
+	cfg, err := config.LoadFile(*configPath)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	if *writeCache {
+		cache, err := ccl.WriteStatsCache(positional[0])
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		out.debugf("Cached metadata of %d tests in %s", len(cache.Tests), filepath.Join(positional[0], ccl.StatsCacheFile))
+	}
+
+	out.debugf("Loading tests from %s for %s", positional[0], cfg.Name)
+	opts := []ccl.Option{ccl.WithInputPredicates(selectors.predicates()...), ccl.WithProgress(out.progress())}
+	stats, err := ccl.GetTestStats(positional[0], cfg, opts...)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+
+	var grouped report.StatisticsByFile
+	if *byFile {
+		files, err := ccl.GetTestStatsByFile(positional[0], cfg, opts...)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		grouped = report.StatisticsByFile{Overall: stats, Files: files}
+	}
+
+	switch *format {
+	case formatJSON:
+		if *byFile {
+			return writeJSON(out, grouped)
+		}
+		return writeJSON(out, stats)
+	case formatCSV:
+		fmt.Fprint(stdout, report.StatisticsCSV(stats))
+	default:
+		incompatible, err := ccl.GetIncompatibleTests(positional[0], cfg, opts...)
+		if err != nil {
+			return out.errorf("%v", err)
+		}
+		summary := report.StatisticsMarkdown(stats)
+		if *byFile {
+			summary = report.StatisticsByFileMarkdown(grouped)
+		}
+		fmt.Fprint(stdout, report.ConfigWarningsMarkdown(cfg)+summary+report.BlockedMarkdown(incompatible, topBlocked)+
+			report.ExpectedSizesMarkdown(stats.LargestExpected, topExpectedSizes))
+	}
+	return exitOK
+}
+
+// inputSelectors are the flags selecting tests by input characteristics
+type inputSelectors struct {
+	crlf, tabs, nonASCII bool
+	minLength, maxLength int
+}
+
+func addInputSelectors(fs *flag.FlagSet) *inputSelectors {
+	s := &inputSelectors{}
+	fs.BoolVar(&s.crlf, "crlf", false, "Only tests with an input containing CRLF")
+	fs.BoolVar(&s.tabs, "tabs", false, "Only tests with an input containing tabs")
+	fs.BoolVar(&s.nonASCII, "non-ascii", false, "Only tests with an input containing non-ASCII bytes")
+	fs.IntVar(&s.minLength, "min-length", 0, "Only tests with an input of at least this many bytes")
+	fs.IntVar(&s.maxLength, "max-length", 0, "Only tests with an input of at most this many bytes (0 for no limit)")
+	return s
+}
+
+// predicates converts the selectors that were set; a test must have one
+// input matching all of them
+func (s *inputSelectors) predicates() []loader.InputPredicate {
+	var predicates []loader.InputPredicate
+	if s.crlf {
+		predicates = append(predicates, loader.ContainsCRLF())
+	}
+	if s.tabs {
+		predicates = append(predicates, loader.ContainsTabs())
+	}
+	if s.nonASCII {
+		predicates = append(predicates, loader.NonASCII())
+	}
+	if s.minLength > 0 {
+		predicates = append(predicates, loader.MinLength(s.minLength))
+	}
+	if s.maxLength > 0 {
+		predicates = append(predicates, loader.MaxLength(s.maxLength))
+	}
+	return predicates
+}
+
+// checkReportArgs checks the arguments shared by stats and coverage
+func checkReportArgs(dir, configPath, format string) error {
+	switch format {
+	case formatMarkdown, formatJSON, formatCSV:
+	default:
+		return fmt.Errorf("unknown format %q (want md, json, or csv)", format)
+	}
+	if configPath == "" {
+		return fmt.Errorf("--config is required")
+	}
+	_, err := generatedDir(dir)
+	return err
+}
+
+// generatedDir resolves the generated tests directory of dir's layout,
+// failing when it has none
+func generatedDir(dir string) (string, error) {
+	layout, err := loader.ResolveLayout(dir)
+	if err != nil || layout.GeneratedDir == "" {
+		return "", fmt.Errorf("%s has no %s directory", dir, loader.DefaultGeneratedDir)
+	}
+	return layout.GeneratedDir, nil
+}
+
+func writeJSON(out *output, value interface{}) int {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	fmt.Fprintln(out.stdout, string(data))
+	return exitOK
+}
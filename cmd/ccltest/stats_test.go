@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestStats_Formats(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("stats", root, "--config", configPath)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "| Total tests | 3 |") || !strings.Contains(stdout, "| Compatible tests | 2 |") {
+		t.Errorf("Unexpected markdown output:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "## Top blocked capabilities") {
+		t.Errorf("Expected the blocked capabilities section:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCommand("stats", root, "--config", configPath, "--format", "json")
+	if code != exitOK {
+		t.Fatalf("Expected success for json, got %d", code)
+	}
+	var stats types.TestStatistics
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if stats.TotalTests != 3 || stats.CompatibleTests != 2 {
+		t.Errorf("Unexpected JSON statistics: %+v", stats)
+	}
+
+	code, stdout, _ = runCommand("stats", root, "--config", configPath, "--format", "csv")
+	if code != exitOK || !strings.HasPrefix(stdout, "category,name,count\nsummary,total_tests,3\n") {
+		t.Errorf("Unexpected CSV output (%d):\n%s", code, stdout)
+	}
+}
+
+func TestStats_ByFile(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("stats", root, "--config", configPath, "--by-file")
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "| File | Tests | Compatible |") || !strings.Contains(stdout, "| **Total** | 3 | 2 |") || !strings.Contains(stdout, "<details>") {
+		t.Errorf("Unexpected markdown output:\n%s", stdout)
+	}
+
+	code, stdout, _ = runCommand("stats", root, "--config", configPath, "--by-file", "--format", "json")
+	if code != exitOK {
+		t.Fatalf("Expected success for json, got %d", code)
+	}
+	var grouped struct {
+		Overall types.TestStatistics   `json:"overall"`
+		Files   []types.FileStatistics `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &grouped); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	total := 0
+	for _, file := range grouped.Files {
+		total += file.Stats.TotalTests
+	}
+	if len(grouped.Files) == 0 || total != grouped.Overall.TotalTests || grouped.Overall.TotalTests != 3 {
+		t.Errorf("Expected file subtotals adding up to 3 tests, got %+v", grouped)
+	}
+
+	if code, _, stderr := runCommand("stats", root, "--config", configPath, "--by-file", "--format", "csv"); code != exitUsage || !strings.Contains(stderr, "csv") {
+		t.Errorf("Expected usage error for csv, got %d: %s", code, stderr)
+	}
+}
+
+func TestStats_Errors(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	if code, _, stderr := runCommand("stats", root); code != exitUsage || !strings.Contains(stderr, "--config is required") {
+		t.Errorf("Expected usage error without --config, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("stats", root, "--config", configPath, "--format", "xml"); code != exitUsage {
+		t.Errorf("Expected usage error for unknown format, got %d", code)
+	}
+	if code, _, _ := runCommand("stats", t.TempDir(), "--config", configPath); code != exitUsage {
+		t.Errorf("Expected usage error without generated_tests, got %d", code)
+	}
+	notDir := t.TempDir()
+	os.WriteFile(filepath.Join(notDir, "generated_tests"), []byte("{}"), 0644)
+	if code, _, stderr := runCommand("stats", notDir, "--config", configPath); code != exitUsage || !strings.Contains(stderr, "has no generated_tests directory") {
+		t.Errorf("Expected usage error for a generated_tests file, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("stats", root, "--config", root+"/missing.json"); code != exitFailure {
+		t.Errorf("Expected failure for missing config, got %d", code)
+	}
+}
+
+func TestStats_InputSelectors(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("stats", root, "--config", configPath, "--format", "json", "--min-length", "10")
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	var stats types.TestStatistics
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if stats.TotalTests != 1 || stats.ByValidation["filter"] != 1 {
+		t.Errorf("Expected only the long comments input, got %+v", stats)
+	}
+
+	code, stdout, _ = runCommand("stats", root, "--config", configPath, "--crlf")
+	if code != exitOK || !strings.Contains(stdout, "| Total tests | 0 |") {
+		t.Errorf("Expected no CRLF inputs (%d):\n%s", code, stdout)
+	}
+}
+
+func TestStats_WriteCache(t *testing.T) {
+	root, configPath := generateCLITestData(t)
+
+	code, stdout, stderr := runCommand("stats", root, "--config", configPath, "--format", "json", "--write-cache")
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(root, "stats-cache.json")); err != nil {
+		t.Errorf("Expected the stats cache: %v", err)
+	}
+	var stats types.TestStatistics
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil || stats.TotalTests != 3 {
+		t.Errorf("Expected statistics after writing the cache, got %+v, %v", stats, err)
+	}
+}
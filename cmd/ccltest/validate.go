@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// runValidate implements "ccltest validate <dir>"
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("validate", "<dir> [flags]", stdout, stderr)
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	dir := positional[0]
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	if len(files) == 0 {
+		return out.errorf("no test files in %s", dir)
+	}
+	for _, file := range files {
+		out.debugf("Checking %s", filepath.Base(file))
+	}
+
+	issues, err := loader.ValidateTestDir(dir)
+	if err != nil {
+		return out.errorf("%v", err)
+	}
+	for _, issue := range issues {
+		out.errorf("%s", issue)
+	}
+	if len(issues) > 0 {
+		return out.errorf("%d issues in %d files", len(issues), len(files))
+	}
+
+	out.infof("%d files valid", len(files))
+	return exitOK
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_SourceAndGenerated(t *testing.T) {
+	root, _ := generateCLITestData(t)
+
+	for _, dir := range []string{"tests", "generated_tests"} {
+		code, stdout, stderr := runCommand("validate", filepath.Join(root, dir))
+		if code != exitOK {
+			t.Errorf("%s: expected success, got %d: %s", dir, code, stderr)
+		}
+		if !strings.Contains(stdout, "2 files valid") {
+			t.Errorf("%s: expected summary, got %q", dir, stdout)
+		}
+	}
+}
+
+func TestValidate_ReportsIssues(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	sourceDir := filepath.Join(root, "tests")
+	bad := `{"tests": [{"name": "x", "inputs": ["a"], "tests": [{"function": "get_int", "expect": 1}]}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "api_bad.json"), []byte(bad), 0644); err != nil {
+		t.Fatalf("Failed to write bad file: %v", err)
+	}
+
+	code, _, stderr := runCommand("validate", sourceDir, "--quiet")
+	if code != exitFailure {
+		t.Fatalf("Expected failure, got %d", code)
+	}
+	if !strings.Contains(stderr, "api_bad.json: x: get_int requires args") {
+		t.Errorf("Expected issue in output, got %q", stderr)
+	}
+	if !strings.Contains(stderr, "1 issues in 3 files") {
+		t.Errorf("Expected issue count, got %q", stderr)
+	}
+}
+
+func TestValidate_EmptyDirectory(t *testing.T) {
+	if code, _, stderr := runCommand("validate", t.TempDir()); code != exitFailure || !strings.Contains(stderr, "no test files") {
+		t.Errorf("Expected failure for empty directory, got %d: %s", code, stderr)
+	}
+}
+
+func TestValidate_Verbose(t *testing.T) {
+	root, _ := setupCLITestData(t)
+	code, stdout, _ := runCommand("validate", filepath.Join(root, "tests"), "--verbose")
+	if code != exitOK || !strings.Contains(stdout, "Checking api_basic.json") {
+		t.Errorf("Expected per-file progress, got %d: %q", code, stdout)
+	}
+}
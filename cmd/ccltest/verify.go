@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+)
+
+// Exit codes for verify; when several checks fail the first one's code is used
+const (
+	exitSourcesInvalid  = 3 // source_tests fail validation
+	exitGenerationStale = 4 // generated_tests differ from a fresh generation
+	exitSchemaInvalid   = 5 // generated_tests fail validation or schemas drifted
+	exitStatsRegressed  = 6 // statistics fell below the baseline
+	exitLinksBroken     = 7 // source_test links dangle or source tests generate nothing
+	exitDuplicateNames  = 8 // a test name is used in several generated files
+	exitManifestInvalid = 9 // generated_tests differ from manifest.json
+)
+
+// verifyExitCodes maps each check to the exit code of its failure
+var verifyExitCodes = map[ccl.VerifyCheck]int{
+	ccl.VerifySources:    exitSourcesInvalid,
+	ccl.VerifyGeneration: exitGenerationStale,
+	ccl.VerifySchema:     exitSchemaInvalid,
+	ccl.VerifyDuplicates: exitDuplicateNames,
+	ccl.VerifyManifest:   exitManifestInvalid,
+	ccl.VerifyStats:      exitStatsRegressed,
+	ccl.VerifyLinks:      exitLinksBroken,
+}
+
+// runVerify implements "ccltest verify <dir>"
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	fs, out := newFlagSet("verify", "<dir> [flags]", stdout, stderr)
+	baseline := fs.String("baseline", "", "Statistics snapshot (ccltest stats --format json) that must not regress")
+	schemaDir := fs.String("schema-dir", "", "Schemas to compare with the ones ccl-test-lib was built from")
+	fix := fs.Bool("fix", false, "Regenerate generated_tests when stale")
+	var skip listFlag
+	fs.Var(&skip, "skip", "Comma-separated checks to skip: sources, generation, schema, duplicates, manifest, stats, links")
+	flagUsage := fs.Usage
+	fs.Usage = func() {
+		flagUsage()
+		fmt.Fprintf(fs.Output(), "\nExit codes: %d sources invalid, %d generation stale, %d schema invalid, %d stats regressed, %d links broken, %d duplicate names, %d manifest mismatch\n",
+			exitSourcesInvalid, exitGenerationStale, exitSchemaInvalid, exitStatsRegressed, exitLinksBroken, exitDuplicateNames, exitManifestInvalid)
+	}
+
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	var skipped []ccl.VerifyCheck
+	for _, name := range skip {
+		check := ccl.VerifyCheck(name)
+		if _, ok := verifyExitCodes[check]; !ok {
+			return usageError(stderr, fmt.Errorf("unknown check %q", name))
+		}
+		skipped = append(skipped, check)
+	}
+
+	opts := []ccl.VerifyOption{
+		ccl.WithVerifySkip(skipped...),
+		ccl.WithVerifyBaseline(*baseline),
+		ccl.WithVerifySchemaDir(*schemaDir),
+		ccl.WithVerifyReport(func(result ccl.VerifyResult) {
+			for _, warning := range result.Warnings {
+				out.warnf("%s", warning)
+			}
+			switch {
+			case result.Err != nil:
+				out.errorf("FAIL %s: %v", result.Check, result.Err)
+			case result.Skipped && result.Summary == "":
+				out.infof("SKIP %s", result.Check)
+			case result.Skipped:
+				out.infof("SKIP %s: %s", result.Check, result.Summary)
+			default:
+				out.infof("PASS %s: %s", result.Check, result.Summary)
+			}
+		}),
+	}
+	if *fix {
+		opts = append(opts, ccl.WithVerifyFix())
+	}
+
+	err = ccl.VerifyTestData(positional[0], opts...)
+	var failed *ccl.VerifyError
+	if errors.As(err, &failed) {
+		return verifyExitCodes[failed.Failures[0].Check]
+	}
+	if err != nil {
+		out.errorf("%v", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+)
+
+// setupVerifyRepo lays out a test data repository with source_tests,
+// up-to-date generated_tests, schemas, and a stats baseline
+func setupVerifyRepo(t *testing.T) string {
+	t.Helper()
+	root, configPath := setupCLITestData(t)
+	if err := os.Rename(filepath.Join(root, "tests"), filepath.Join(root, "source_tests")); err != nil {
+		t.Fatalf("Failed to move sources: %v", err)
+	}
+	if code, _, stderr := runCommand("generate", filepath.Join(root, "source_tests"), filepath.Join(root, "generated_tests"), "--quiet"); code != exitOK {
+		t.Fatalf("generate failed with %d: %s", code, stderr)
+	}
+
+	schemaDir := filepath.Join(root, "schemas")
+	if err := os.MkdirAll(schemaDir, 0755); err != nil {
+		t.Fatalf("Failed to create schema directory: %v", err)
+	}
+	for _, name := range []string{"source-format.json", "generated-format.json"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "schemas", name))
+		if err != nil {
+			t.Fatalf("Failed to read schema: %v", err)
+		}
+		os.WriteFile(filepath.Join(schemaDir, name), data, 0644)
+	}
+
+	code, stdout, stderr := runCommand("stats", root, "--config", configPath, "--format", "json")
+	if code != exitOK {
+		t.Fatalf("stats failed with %d: %s", code, stderr)
+	}
+	os.WriteFile(filepath.Join(root, "stats.json"), []byte(stdout), 0644)
+	return root
+}
+
+func verifyArgs(root string, extra ...string) []string {
+	args := []string{"verify", root, "--baseline", filepath.Join(root, "stats.json"), "--schema-dir", filepath.Join(root, "schemas")}
+	return append(args, extra...)
+}
+
+func TestVerify_Pass(t *testing.T) {
+	root := setupVerifyRepo(t)
+
+	code, stdout, stderr := runCommand(verifyArgs(root)...)
+	if code != exitOK {
+		t.Fatalf("Expected success, got %d: %s", code, stderr)
+	}
+	for _, want := range []string{"PASS sources: 2 files valid", "PASS generation: 3 tests up to date", "PASS schema: 2 files valid, schemas match", "PASS stats: 3 tests, no regressions", "PASS links: all source links intact"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestVerify_StaleGeneration(t *testing.T) {
+	root := setupVerifyRepo(t)
+	extra := `{"tests": [{"name": "added", "inputs": ["x = 1"], "tests": [{"function": "parse", "expect": [{"key": "x", "value": "1"}]}]}]}`
+	if err := os.WriteFile(filepath.Join(root, "source_tests", "api_added.json"), []byte(extra), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	code, _, stderr := runCommand(verifyArgs(root)...)
+	if code != exitGenerationStale {
+		t.Fatalf("Expected exit %d, got %d: %s", exitGenerationStale, code, stderr)
+	}
+	if !strings.Contains(stderr, "FAIL generation: generated_tests is stale") || !strings.Contains(stderr, "+ added_parse (parse) api_added.json") {
+		t.Errorf("Expected stale generation reported, got:\n%s", stderr)
+	}
+
+	// The ungenerated source test also has no generated tests to link to
+	if code, _, _ := runCommand(verifyArgs(root, "--skip", "generation,links")...); code != exitOK {
+		t.Errorf("Expected success with generation and links skipped, got %d", code)
+	}
+
+	code, stdout, stderr := runCommand(verifyArgs(root, "--fix")...)
+	if code != exitOK || !strings.Contains(stdout, "PASS generation: regenerated (1 added, 0 removed, 0 changed)") {
+		t.Fatalf("Expected --fix to regenerate, got %d: %s%s", code, stdout, stderr)
+	}
+	if code, _, stderr := runCommand(verifyArgs(root)...); code != exitOK {
+		t.Errorf("Expected success after --fix, got %d: %s", code, stderr)
+	}
+}
+
+func TestVerify_WarnsAboutOtherMajorVersion(t *testing.T) {
+	root := setupVerifyRepo(t)
+	files, _ := filepath.Glob(filepath.Join(root, "generated_tests", "*.json"))
+	if len(files) == 0 {
+		t.Fatal("Expected generated files")
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	old := strings.Replace(string(data), `"version": "`+ccl.Version+`"`, `"version": "v99.0.0"`, 1)
+	if old == string(data) {
+		t.Fatal("Expected a generator version to replace")
+	}
+	os.WriteFile(files[0], []byte(old), 0644)
+
+	code, _, stderr := runCommand(verifyArgs(root)...)
+	if code != exitOK {
+		t.Fatalf("Expected the warning not to fail verify, got %d: %s", code, stderr)
+	}
+	if want := filepath.Base(files[0]) + " was generated by ccl-test-lib v99.0.0"; !strings.Contains(stderr, want) {
+		t.Errorf("Expected %q, got %q", want, stderr)
+	}
+}
+
+func TestVerify_StatsRegression(t *testing.T) {
+	root := setupVerifyRepo(t)
+	os.Remove(filepath.Join(root, "source_tests", "property-roundtrip.json"))
+	os.Remove(filepath.Join(root, "generated_tests", "property-roundtrip.json"))
+
+	code, _, stderr := runCommand(verifyArgs(root)...)
+	if code != exitStatsRegressed {
+		t.Fatalf("Expected exit %d, got %d: %s", exitStatsRegressed, code, stderr)
+	}
+	for _, want := range []string{"total tests: 3 -> 2", "function filter: 1 -> 0", "feature comments: 1 -> 0"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q, got:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestVerify_BrokenLinks(t *testing.T) {
+	root := setupVerifyRepo(t)
+	generated := filepath.Join(root, "generated_tests", "api_basic.json")
+	data, err := os.ReadFile(generated)
+	if err != nil {
+		t.Fatalf("Failed to read generated tests: %v", err)
+	}
+	// Renaming the link breaks it both ways: the flat test dangles and its
+	// source test is left without generated tests
+	renamed := strings.ReplaceAll(string(data), `"source_test": "basic"`, `"source_test": "renamed"`)
+	os.WriteFile(generated, []byte(renamed), 0644)
+
+	code, _, stderr := runCommand(verifyArgs(root, "--skip", "generation")...)
+	if code != exitLinksBroken {
+		t.Fatalf("Expected exit %d, got %d: %s", exitLinksBroken, code, stderr)
+	}
+	for _, want := range []string{`basic_parse: source test "renamed" does not exist`, "basic: no generated tests"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q, got:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestVerify_SchemaDriftAndSkip(t *testing.T) {
+	root := setupVerifyRepo(t)
+	os.WriteFile(filepath.Join(root, "schemas", "source-format.json"), []byte(`{"type": "object"}`), 0644)
+
+	if code, _, stderr := runCommand(verifyArgs(root)...); code != exitSchemaInvalid || !strings.Contains(stderr, "source-format.json: differs") {
+		t.Errorf("Expected schema drift, got %d: %s", code, stderr)
+	}
+
+	code, stdout, _ := runCommand(verifyArgs(root, "--skip", "schema,stats")...)
+	if code != exitOK || !strings.Contains(stdout, "SKIP schema") || !strings.Contains(stdout, "SKIP stats") {
+		t.Errorf("Expected skipped checks, got %d: %s", code, stdout)
+	}
+
+	if code, _, _ := runCommand(verifyArgs(root, "--skip", "nonsense")...); code != exitUsage {
+		t.Errorf("Expected usage error for unknown check, got %d", code)
+	}
+}
+
+func TestVerify_InvalidSources(t *testing.T) {
+	root := setupVerifyRepo(t)
+	bad := `{"tests": [{"name": "x", "inputs": ["a"], "tests": [{"function": "get_int", "expect": 1}]}]}`
+	os.WriteFile(filepath.Join(root, "source_tests", "api_bad.json"), []byte(bad), 0644)
+
+	code, _, stderr := runCommand(verifyArgs(root, "--skip", "generation")...)
+	if code != exitSourcesInvalid || !strings.Contains(stderr, "api_bad.json: x: get_int requires args") {
+		t.Errorf("Expected invalid sources, got %d: %s", code, stderr)
+	}
+}
+
+func TestVerify_DuplicateNames(t *testing.T) {
+	root := setupVerifyRepo(t)
+	data, err := os.ReadFile(filepath.Join(root, "generated_tests", "api_basic.json"))
+	if err != nil {
+		t.Fatalf("Failed to read generated tests: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "generated_tests", "api_copy.json"), data, 0644)
+
+	code, _, stderr := runCommand(verifyArgs(root, "--skip", "generation")...)
+	if code != exitDuplicateNames || !strings.Contains(stderr, "basic_parse: in api_basic.json, api_copy.json") {
+		t.Errorf("Expected duplicate names, got %d: %s", code, stderr)
+	}
+}
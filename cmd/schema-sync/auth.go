@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// githubAPIURL serves repository contents for the authenticated fallback
+	githubAPIURL = "https://api.github.com"
+
+	// githubRawMediaType asks the contents API for the raw file body
+	githubRawMediaType = "application/vnd.github.raw+json"
+
+	// redactedToken replaces the token wherever it would be printed
+	redactedToken = "[REDACTED]"
+)
+
+// schemaAPIURL builds the GitHub contents API URL of the schemas directory
+func schemaAPIURL(repo string) (string, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/contents/schemas", githubAPIURL,
+		url.PathEscape(owner), url.PathEscape(name)), nil
+}
+
+// fetchFile downloads a file from the raw content URL. With a token, a file
+// the raw host does not serve is retried through the contents API, which
+// serves private repositories.
+func fetchFile(opts syncOptions, name, outputPath, etag string) ([]byte, string, error) {
+	rawURL := fmt.Sprintf("%s/%s", opts.BaseURL, name)
+	data, newETag, err := downloadSchema(opts, rawURL, "", outputPath, etag)
+	if !errors.Is(err, errNotFound) || opts.Token == "" || opts.APIURL == "" {
+		return data, newETag, err
+	}
+
+	apiURL := fmt.Sprintf("%s/%s", opts.APIURL, url.PathEscape(name))
+	if opts.Ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(opts.Ref)
+	}
+	fmt.Fprintf(opts.Stdout, "  %s not found at %s, trying the contents API\n", name, opts.BaseURL)
+	return downloadSchema(opts, apiURL, githubRawMediaType, outputPath, etag)
+}
+
+// redact removes the token from text that is about to be printed
+func (opts syncOptions) redact(text string) string {
+	if opts.Token == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, opts.Token, redactedToken)
+}
+
+// redactError removes the token from an error message. Errors that do not
+// mention the token are returned unchanged so errors.Is keeps working.
+func (opts syncOptions) redactError(err error) error {
+	if err == nil || opts.Token == "" || !strings.Contains(err.Error(), opts.Token) {
+		return err
+	}
+	return errors.New(opts.redact(err.Error()))
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testToken = "ghp_secretTestToken123"
+
+// privateRepoServer serves schemas only through the contents API and only
+// with the test token, like a private GitHub repository
+type privateRepoServer struct {
+	content  map[string]string
+	requests []*http.Request
+}
+
+func (s *privateRepoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r)
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/raw/") {
+		http.NotFound(w, r)
+		return
+	}
+	body, ok := s.content[filepath.Base(r.URL.Path)]
+	if !ok || r.Header.Get("Accept") != githubRawMediaType || r.URL.Query().Get("ref") != "v1.0.0" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write([]byte(body))
+}
+
+func privateSyncOptions(t *testing.T, server *httptest.Server, out *bytes.Buffer) syncOptions {
+	t.Helper()
+	return syncOptions{
+		OutputDir: filepath.Join(t.TempDir(), "schemas"),
+		BaseURL:   server.URL + "/raw/schemas",
+		APIURL:    server.URL + "/api/schemas",
+		Ref:       "v1.0.0",
+		LocalPath: filepath.Join(t.TempDir(), "no-local-schemas"),
+		Schemas:   defaultSchemas,
+		Client:    server.Client(),
+		Stdout:    out,
+		Token:     testToken,
+	}
+}
+
+func TestSyncSchemas_TokenAndContentsAPI(t *testing.T) {
+	handler := &privateRepoServer{content: map[string]string{
+		"source-format.json":    `{"title": "source"}`,
+		"generated-format.json": `{"title": "generated"}`,
+	}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	var out bytes.Buffer
+	opts := privateSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+	for _, r := range handler.requests {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			t.Errorf("Expected bearer token on %s", r.URL.Path)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(opts.OutputDir, "generated-format.json"))
+	if err != nil || string(data) != `{"title": "generated"}` {
+		t.Errorf("Expected schema from contents API, got %q (%v)", data, err)
+	}
+	if strings.Contains(out.String(), testToken) {
+		t.Errorf("Token leaked into output:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_NoTokenNoFallback(t *testing.T) {
+	handler := &privateRepoServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	var out bytes.Buffer
+	opts := privateSyncOptions(t, server, &out)
+	opts.Token = ""
+
+	err := syncSchemas(opts)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Expected unauthorized error, got %v", err)
+	}
+	for _, r := range handler.requests {
+		if r.Header.Get("Authorization") != "" || strings.HasPrefix(r.URL.Path, "/api/") {
+			t.Errorf("Expected unauthenticated raw requests only, got %s %v", r.URL.Path, r.Header)
+		}
+	}
+}
+
+// leakyTransport fails with an error that echoes the Authorization header
+type leakyTransport struct{}
+
+func (leakyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("proxy rejected credentials %q", r.Header.Get("Authorization"))
+}
+
+func TestSyncSchemas_TokenRedactedFromErrors(t *testing.T) {
+	var out bytes.Buffer
+	opts := syncOptions{
+		OutputDir:   t.TempDir(),
+		BaseURL:     "http://schemas.invalid",
+		LocalPath:   t.TempDir(),
+		Schemas:     defaultSchemas,
+		Client:      &http.Client{Transport: leakyTransport{}},
+		Stdout:      &out,
+		Token:       testToken,
+		MaxAttempts: 2,
+	}
+
+	err := syncSchemas(opts)
+	if err == nil {
+		t.Fatal("Expected transport error")
+	}
+	if strings.Contains(err.Error(), testToken) || !strings.Contains(err.Error(), redactedToken) {
+		t.Errorf("Expected token redacted from error, got %v", err)
+	}
+	if strings.Contains(out.String(), testToken) {
+		t.Errorf("Token leaked into retry output:\n%s", out.String())
+	}
+}
+
+func TestRun_TokenFromEnvironmentRedacted(t *testing.T) {
+	t.Setenv("CCL_SCHEMA_TOKEN", testToken)
+
+	var stdout, stderr bytes.Buffer
+	// An invalid repo fails before any request; the token must not be echoed
+	code := run([]string{"--repo", "bad", t.TempDir()}, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("Expected failure for invalid repo")
+	}
+	if strings.Contains(stdout.String()+stderr.String(), testToken) {
+		t.Errorf("Token leaked into output")
+	}
+}
+
+func TestRedactError_KeepsUnrelatedErrors(t *testing.T) {
+	opts := syncOptions{Token: testToken}
+	if err := opts.redactError(errNotFound); !errors.Is(err, errNotFound) {
+		t.Errorf("Expected errors without the token to be unchanged, got %v", err)
+	}
+}
+
+func TestSchemaAPIURL(t *testing.T) {
+	got, err := schemaAPIURL("tylerbutler/ccl-test-data")
+	if err != nil {
+		t.Fatalf("schemaAPIURL failed: %v", err)
+	}
+	if want := "https://api.github.com/repos/tylerbutler/ccl-test-data/contents/schemas"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestRun_HelpOmitsToken(t *testing.T) {
+	t.Setenv("CCL_SCHEMA_TOKEN", testToken)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-h"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit 0 for -h, got %d", code)
+	}
+	if strings.Contains(stderr.String(), testToken) {
+		t.Errorf("Token leaked into usage:\n%s", stderr.String())
+	}
+}
@@ -1,119 +1,177 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 )
 
 const (
-	// TODO: Update this URL when ccl-test-data repository is public
-	baseURL = "https://raw.githubusercontent.com/tylerbutler/ccl-test-data/main/schemas"
+	// rawContentURL serves repository files by owner/repo/ref/path
+	rawContentURL = "https://raw.githubusercontent.com"
+
+	// Default schema source, overridable with --repo/--ref or CCL_SCHEMA_REPO/CCL_SCHEMA_REF
+	defaultRepo = "tylerbutler/ccl-test-data"
+	defaultRef  = "main"
 
 	// Local fallback for development
 	localSchemaPath = "../ccl-test-data/schemas"
 )
 
+// defaultSchemas are the schema files synced from ccl-test-data
+var defaultSchemas = []string{
+	"source-format.json",
+	"generated-format.json",
+}
+
 func main() {
-	if len(os.Args) > 1 && (os.Args[1] == "-h" || os.Args[1] == "--help") {
-		fmt.Printf("Usage: %s [output-dir]\n", os.Args[0])
-		fmt.Println("Downloads CCL JSON schemas from ccl-test-data repository")
-		fmt.Println()
-		fmt.Println("Arguments:")
-		fmt.Println("  output-dir    Directory to save schemas (default: schemas)")
-		os.Exit(0)
-	}
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-	outputDir := "schemas"
-	if len(os.Args) > 1 {
-		outputDir = os.Args[1]
+// run parses arguments, syncs schemas, and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("schema-sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	force := fs.Bool("force", false, "Download and write schemas even when unchanged")
+	verify := fs.String("verify", "", "Sums file of pinned sha256 checksums that downloads must match")
+	repo := fs.String("repo", envOr("CCL_SCHEMA_REPO", defaultRepo), "Schema repository as owner/repo (env CCL_SCHEMA_REPO)")
+	ref := fs.String("ref", envOr("CCL_SCHEMA_REF", defaultRef), "Branch, tag, or commit SHA to sync from (env CCL_SCHEMA_REF)")
+	localPath := fs.String("local-path", localSchemaPath, "Local schema directory tried before downloading")
+	timeout := fs.Duration("timeout", 30*time.Second, "Timeout for each HTTP request")
+	maxAttempts := fs.Int("max-attempts", 3, "Download attempts per file before giving up")
+	token := fs.String("token", "", "Bearer token for private repositories (env CCL_SCHEMA_TOKEN)")
+	only := fs.String("only", "", "Comma-separated schema files to sync (default: all in the manifest)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: schema-sync [flags] [output-dir]\n")
+		fmt.Fprintln(fs.Output(), "Downloads CCL JSON schemas from ccl-test-data repository")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Arguments:")
+		fmt.Fprintln(fs.Output(), "  output-dir    Directory to save schemas (default: schemas)")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
 	}
-
-	// Create output directory
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating directory %s: %v\n", outputDir, err)
-		os.Exit(1)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
 	}
 
-	schemas := []string{
-		"source-format.json",
-		"generated-format.json",
+	// Not a flag default, so -h never prints the token
+	if *token == "" {
+		*token = os.Getenv("CCL_SCHEMA_TOKEN")
 	}
 
-	fmt.Printf("Syncing schemas to %s/\n", outputDir)
-
-	for _, schema := range schemas {
-		outputPath := filepath.Join(outputDir, schema)
-
-		// Try local file first (for development)
-		localPath := filepath.Join(localSchemaPath, schema)
-		if _, err := os.Stat(localPath); err == nil {
-			fmt.Printf("  %s (local) -> %s\n", schema, outputPath)
-			if err := copyFile(localPath, outputPath); err != nil {
-				fmt.Fprintf(os.Stderr, "Error copying local file %s: %v\n", schema, err)
-				os.Exit(1)
-			}
-			continue
-		}
-
-		// Fall back to remote download
-		url := fmt.Sprintf("%s/%s", baseURL, schema)
-		fmt.Printf("  %s (remote) -> %s\n", schema, outputPath)
-
-		if err := downloadFile(url, outputPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error downloading %s: %v\n", schema, err)
-			fmt.Fprintf(os.Stderr, "Tried local path: %s (not found)\n", localPath)
-			os.Exit(1)
-		}
+	outputDir := "schemas"
+	if fs.NArg() > 0 {
+		outputDir = fs.Arg(0)
 	}
 
-	fmt.Println("Schema download complete!")
-}
+	if *maxAttempts < 1 {
+		fmt.Fprintln(stderr, "Error: --max-attempts must be at least 1")
+		return 2
+	}
 
-func downloadFile(url, outputPath string) error {
-	resp, err := http.Get(url)
+	base, err := schemaBaseURL(*repo, *ref)
 	if err != nil {
-		return fmt.Errorf("HTTP GET failed: %w", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	apiURL, err := schemaAPIURL(*repo)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	auth := ""
+	if *token != "" {
+		auth = ", authenticated"
+	}
+	fmt.Fprintf(stdout, "Schema source: %s@%s (%s%s)\n", *repo, *ref, base, auth)
+
+	opts := syncOptions{
+		OutputDir: outputDir,
+		BaseURL:   base,
+		LocalPath: *localPath,
+		Schemas:   defaultSchemas,
+		Only:      splitList(*only),
+		Force:     *force,
+		Client:    &http.Client{Timeout: *timeout},
+		Stdout:    stdout,
+
+		Token:  *token,
+		APIURL: apiURL,
+		Ref:    *ref,
+
+		MaxAttempts: *maxAttempts,
+		RetryDelay:  time.Second,
+	}
+	if *verify != "" {
+		pinned, err := readSums(*verify)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error reading pinned checksums: %v\n", err)
+			return 1
+		}
+		opts.Expected = pinned
 	}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("create file failed: %w", err)
+	if err := syncSchemas(opts); err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", opts.redact(err.Error()))
+		return 1
 	}
-	defer file.Close()
+	return 0
+}
 
-	_, err = io.Copy(file, resp.Body)
+// schemaBaseURL builds the raw content URL of the schemas directory for a
+// repository at a branch, tag, or commit SHA
+func schemaBaseURL(repo, ref string) (string, error) {
+	owner, name, err := splitRepo(repo)
 	if err != nil {
-		return fmt.Errorf("copy failed: %w", err)
+		return "", err
+	}
+	if ref == "" || strings.HasPrefix(ref, "/") || strings.HasSuffix(ref, "/") {
+		return "", fmt.Errorf("invalid ref %q", ref)
 	}
 
-	return nil
+	// Branch names may contain slashes; escape each segment separately
+	segments := strings.Split(ref, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/schemas", rawContentURL,
+		url.PathEscape(owner), url.PathEscape(name), strings.Join(segments, "/")), nil
 }
 
-func copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("open source file failed: %w", err)
+// splitRepo splits an owner/repo name
+func splitRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q (want owner/repo)", repo)
 	}
-	defer srcFile.Close()
+	return owner, name, nil
+}
 
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("create destination file failed: %w", err)
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	defer dstFile.Close()
+	return items
+}
 
-	_, err = io.Copy(dstFile, srcFile)
-	if err != nil {
-		return fmt.Errorf("copy failed: %w", err)
+// envOr returns the environment variable value, or fallback when unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
-
-	return nil
+	return fallback
 }
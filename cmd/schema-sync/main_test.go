@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		repo string
+		ref  string
+		want string
+	}{
+		{"default branch", "tylerbutler/ccl-test-data", "main",
+			"https://raw.githubusercontent.com/tylerbutler/ccl-test-data/main/schemas"},
+		{"branch with slash", "someone/ccl-test-data", "feature/new-schema",
+			"https://raw.githubusercontent.com/someone/ccl-test-data/feature/new-schema/schemas"},
+		{"tag", "tylerbutler/ccl-test-data", "v1.2.0",
+			"https://raw.githubusercontent.com/tylerbutler/ccl-test-data/v1.2.0/schemas"},
+		{"full tag ref", "tylerbutler/ccl-test-data", "refs/tags/v1.2.0",
+			"https://raw.githubusercontent.com/tylerbutler/ccl-test-data/refs/tags/v1.2.0/schemas"},
+		{"commit sha", "tylerbutler/ccl-test-data", "3f2a9c1d4e5b6a7980c1d2e3f4a5b6c7d8e9f0a1",
+			"https://raw.githubusercontent.com/tylerbutler/ccl-test-data/3f2a9c1d4e5b6a7980c1d2e3f4a5b6c7d8e9f0a1/schemas"},
+		{"escaped segment", "tylerbutler/ccl-test-data", "release#1",
+			"https://raw.githubusercontent.com/tylerbutler/ccl-test-data/release%231/schemas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schemaBaseURL(tt.repo, tt.ref)
+			if err != nil {
+				t.Fatalf("schemaBaseURL failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSchemaBaseURL_Invalid(t *testing.T) {
+	invalid := []struct{ repo, ref string }{
+		{"ccl-test-data", "main"},
+		{"/ccl-test-data", "main"},
+		{"owner/repo/extra", "main"},
+		{"owner/repo", ""},
+		{"owner/repo", "feature/"},
+	}
+	for _, tt := range invalid {
+		if _, err := schemaBaseURL(tt.repo, tt.ref); err == nil {
+			t.Errorf("Expected error for repo %q ref %q", tt.repo, tt.ref)
+		}
+	}
+}
+
+func TestRun_SourceFromEnvironment(t *testing.T) {
+	t.Setenv("CCL_SCHEMA_REPO", "fork/ccl-test-data")
+	t.Setenv("CCL_SCHEMA_REF", "v2.0.0")
+
+	localDir := t.TempDir()
+	for _, schema := range defaultSchemas {
+		os.WriteFile(filepath.Join(localDir, schema), []byte(`{}`), 0644)
+	}
+	manifest := `{"schemas": [{"file": "source-format.json"}, {"file": "generated-format.json"}]}`
+	os.WriteFile(filepath.Join(localDir, manifestFileName), []byte(manifest), 0644)
+
+	var stdout, stderr bytes.Buffer
+	outputDir := filepath.Join(t.TempDir(), "schemas")
+	code := run([]string{"--local-path", localDir, "--ref", "v2.1.0", outputDir}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr.String())
+	}
+
+	// The flag overrides the environment
+	want := "Schema source: fork/ccl-test-data@v2.1.0 (https://raw.githubusercontent.com/fork/ccl-test-data/v2.1.0/schemas)"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("Expected resolved source %q, got:\n%s", want, stdout.String())
+	}
+	if strings.Count(stdout.String(), "(local)") != len(defaultSchemas) {
+		t.Errorf("Expected schemas copied from --local-path, got:\n%s", stdout.String())
+	}
+}
+
+func TestRun_InvalidRepo(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--repo", "not-a-repo", t.TempDir()}, &stdout, &stderr)
+	if code != 2 || !strings.Contains(stderr.String(), "owner/repo") {
+		t.Errorf("Expected usage error for invalid repo, got %d: %s", code, stderr.String())
+	}
+}
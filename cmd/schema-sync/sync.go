@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// sumsFileName holds the sha256 of each synced schema (sha256sum format)
+	sumsFileName = "SHA256SUMS"
+
+	// etagsFileName remembers ETags for conditional requests
+	etagsFileName = ".etags"
+
+	// manifestFileName lists the schemas published by ccl-test-data
+	manifestFileName = "manifest.json"
+)
+
+// errNotFound reports a schema or manifest missing from the remote
+var errNotFound = errors.New("HTTP 404: not found")
+
+// schemaManifest is the schemas/manifest.json format. Unknown fields are
+// ignored so ccl-test-data can extend it.
+type schemaManifest struct {
+	Schemas []manifestEntry `json:"schemas"`
+}
+
+// manifestEntry is one schema listed in the manifest
+type manifestEntry struct {
+	File    string `json:"file"`
+	Version string `json:"version,omitempty"`
+}
+
+// label names the entry in sync output
+func (e manifestEntry) label() string {
+	if e.Version == "" {
+		return e.File
+	}
+	return e.File + "@" + e.Version
+}
+
+// syncOptions controls a schema sync run
+type syncOptions struct {
+	OutputDir string
+	BaseURL   string
+	LocalPath string            // Local schema directory tried before downloading
+	Schemas   []string          // Schemas synced when no manifest is published
+	Only      []string          // Restrict the sync to these schema files
+	Force     bool              // Skip conditional requests and always write
+	Expected  map[string]string // Pinned sha256 sums by file name
+	Client    *http.Client
+	Stdout    io.Writer
+
+	Token  string // Bearer token for private repositories; never printed
+	APIURL string // Contents API URL of the schemas directory, tried with a token
+	Ref    string // Ref passed to the contents API
+
+	MaxAttempts int           // Download attempts per file (at least one)
+	RetryDelay  time.Duration // Delay before the first retry, doubled after each attempt
+}
+
+// checksumError reports content that does not match its pinned checksum
+type checksumError struct {
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (e *checksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.File, e.Expected, e.Actual)
+}
+
+// syncSchemas copies or downloads each schema into the output directory and
+// records their checksums
+func syncSchemas(opts syncOptions) error {
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("creating directory %s: %w", opts.OutputDir, err)
+	}
+
+	sums, err := readSums(filepath.Join(opts.OutputDir, sumsFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if sums == nil {
+		sums = make(map[string]string)
+	}
+	etags, err := readSums(filepath.Join(opts.OutputDir, etagsFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if etags == nil {
+		etags = make(map[string]string)
+	}
+
+	entries, err := resolveSchemas(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.Stdout, "Syncing schemas to %s/\n", opts.OutputDir)
+
+	for _, entry := range entries {
+		schema := entry.File
+		outputPath := filepath.Join(opts.OutputDir, schema)
+
+		// Try local file first (for development)
+		localPath := filepath.Join(opts.LocalPath, schema)
+		if data, err := os.ReadFile(localPath); err == nil {
+			status, err := storeSchema(opts, schema, data)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Stdout, "  %s (local) -> %s [%s]\n", entry.label(), outputPath, status)
+			sums[schema] = checksum(data)
+			continue
+		}
+
+		// Fall back to remote download
+		data, etag, err := fetchFile(opts, schema, outputPath, etags[schema])
+		if err != nil {
+			return fmt.Errorf("downloading %s: %w (tried local path: %s)", schema, err, localPath)
+		}
+		if etag != "" {
+			etags[schema] = etag
+		}
+
+		if data == nil {
+			// Not modified: verify what is already on disk
+			existing, err := os.ReadFile(outputPath)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", outputPath, err)
+			}
+			if err := verifyChecksum(opts, schema, existing); err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Stdout, "  %s (remote) -> %s [not modified]\n", entry.label(), outputPath)
+			sums[schema] = checksum(existing)
+			continue
+		}
+
+		status, err := storeSchema(opts, schema, data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(opts.Stdout, "  %s (remote) -> %s [%s]\n", entry.label(), outputPath, status)
+		sums[schema] = checksum(data)
+	}
+
+	if err := writeSums(filepath.Join(opts.OutputDir, sumsFileName), sums); err != nil {
+		return err
+	}
+	if len(etags) > 0 {
+		if err := writeSums(filepath.Join(opts.OutputDir, etagsFileName), etags); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(opts.Stdout, "Schema download complete!")
+	return nil
+}
+
+// resolveSchemas lists the schemas to sync from the manifest, falling back
+// to opts.Schemas when none is published, and applies the --only filter
+func resolveSchemas(opts syncOptions) ([]manifestEntry, error) {
+	manifest, source, err := loadManifest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []manifestEntry
+	if manifest != nil {
+		fmt.Fprintf(opts.Stdout, "Using manifest from %s (%d schemas)\n", source, len(manifest.Schemas))
+		entries = manifest.Schemas
+	} else {
+		fmt.Fprintln(opts.Stdout, "No schema manifest found, using default schema list")
+		for _, schema := range opts.Schemas {
+			entries = append(entries, manifestEntry{File: schema})
+		}
+	}
+
+	if len(opts.Only) == 0 {
+		return entries, nil
+	}
+	available := make(map[string]manifestEntry, len(entries))
+	for _, entry := range entries {
+		available[entry.File] = entry
+	}
+	var filtered []manifestEntry
+	for _, name := range opts.Only {
+		entry, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("--only: unknown schema %s", name)
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+// loadManifest reads the manifest from the local path or the remote. A nil
+// manifest means none is published.
+func loadManifest(opts syncOptions) (*schemaManifest, string, error) {
+	source := filepath.Join(opts.LocalPath, manifestFileName)
+	data, err := os.ReadFile(source)
+	if err != nil {
+		source = fmt.Sprintf("%s/%s", opts.BaseURL, manifestFileName)
+		data, _, err = fetchFile(opts, manifestFileName, "", "")
+		if errors.Is(err, errNotFound) {
+			return nil, "", nil
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("downloading %s: %w", manifestFileName, err)
+		}
+	}
+
+	var manifest schemaManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, "", fmt.Errorf("parsing %s: %w", source, err)
+	}
+	for _, entry := range manifest.Schemas {
+		if entry.File == "" || filepath.Base(entry.File) != entry.File {
+			return nil, "", fmt.Errorf("%s: invalid schema file name %q", source, entry.File)
+		}
+	}
+	return &manifest, source, nil
+}
+
+// storeSchema verifies content and writes it unless the file already has it
+func storeSchema(opts syncOptions, schema string, data []byte) (string, error) {
+	if !json.Valid(data) {
+		return "", fmt.Errorf("%s is not valid JSON (%d bytes)", schema, len(data))
+	}
+	if err := verifyChecksum(opts, schema, data); err != nil {
+		return "", err
+	}
+
+	outputPath := filepath.Join(opts.OutputDir, schema)
+	if !opts.Force {
+		if existing, err := os.ReadFile(outputPath); err == nil && bytes.Equal(existing, data) {
+			return "unchanged", nil
+		}
+	}
+	if err := writeFileAtomic(outputPath, data); err != nil {
+		return "", err
+	}
+	return "updated", nil
+}
+
+// verifyChecksum checks content against its pinned checksum, if any
+func verifyChecksum(opts syncOptions, schema string, data []byte) error {
+	expected, ok := opts.Expected[schema]
+	if !ok {
+		return nil
+	}
+	if actual := checksum(data); actual != expected {
+		return &checksumError{File: schema, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// downloadSchema fetches a URL, retrying transport errors and 5xx
+// responses with exponential backoff. When the output exists and --force is
+// not set the request is conditional, and a nil body means not modified.
+func downloadSchema(opts syncOptions, url, accept, outputPath, etag string) ([]byte, string, error) {
+	attempts := max(opts.MaxAttempts, 1)
+	delay := opts.RetryDelay
+	for attempt := 1; ; attempt++ {
+		data, newETag, retry, err := fetchOnce(opts, url, accept, outputPath, etag)
+		if err == nil || !retry || attempt == attempts {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return data, newETag, opts.redactError(err)
+		}
+		fmt.Fprintf(opts.Stdout, "  retrying %s in %s: %s\n", opts.redact(url), delay, opts.redact(err.Error()))
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// fetchOnce makes a single request and reports whether a failure is worth
+// retrying
+func fetchOnce(opts syncOptions, url, accept, outputPath, etag string) ([]byte, string, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("creating request: %w", err)
+	}
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if !opts.Force {
+		if info, err := os.Stat(outputPath); err == nil {
+			req.Header.Set("If-Modified-Since", info.ModTime().UTC().Format(http.TimeFormat))
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+		}
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, "", true, fmt.Errorf("HTTP GET failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, resp.Header.Get("ETag"), false, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", false, errNotFound
+	case resp.StatusCode >= 500:
+		return nil, "", true, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", false, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", true, fmt.Errorf("reading response failed: %w", err)
+	}
+	return data, resp.Header.Get("ETag"), false, nil
+}
+
+// writeFileAtomic writes through a temporary file in the same directory and
+// renames it into place, so readers never see partial content
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// readSums reads "value  name" lines (sha256sum format)
+func readSums(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line in %s: %q", path, line)
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return sums, scanner.Err()
+}
+
+// writeSums writes "value  name" lines sorted by name
+func writeSums(path string, sums map[string]string) error {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s  %s\n", sums[name], name)
+	}
+	return writeFileAtomic(path, []byte(b.String()))
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// schemaServer serves fixed schema content with ETags and records request headers
+type schemaServer struct {
+	mu              sync.Mutex
+	content         map[string]string
+	ignoreCondition bool
+	requests        []*http.Request
+}
+
+func (s *schemaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+
+	body, ok := s.content[filepath.Base(r.URL.Path)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	etag := `"` + checksum([]byte(body))[:16] + `"`
+	w.Header().Set("ETag", etag)
+	if !s.ignoreCondition && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Write([]byte(body))
+}
+
+func newSchemaServer(t *testing.T) (*schemaServer, *httptest.Server) {
+	t.Helper()
+	handler := &schemaServer{content: map[string]string{
+		"source-format.json":    `{"title": "source"}`,
+		"generated-format.json": `{"title": "generated"}`,
+	}}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return handler, server
+}
+
+func testSyncOptions(t *testing.T, server *httptest.Server, out *bytes.Buffer) syncOptions {
+	t.Helper()
+	return syncOptions{
+		OutputDir: filepath.Join(t.TempDir(), "schemas"),
+		BaseURL:   server.URL + "/schemas",
+		LocalPath: filepath.Join(t.TempDir(), "no-local-schemas"),
+		Schemas:   defaultSchemas,
+		Client:    server.Client(),
+		Stdout:    out,
+	}
+}
+
+func TestSyncSchemas_WritesSumsFile(t *testing.T) {
+	_, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+
+	sums, err := readSums(filepath.Join(opts.OutputDir, sumsFileName))
+	if err != nil {
+		t.Fatalf("Failed to read sums file: %v", err)
+	}
+	if sums["source-format.json"] != checksum([]byte(`{"title": "source"}`)) {
+		t.Errorf("Unexpected checksum for source-format.json: %s", sums["source-format.json"])
+	}
+	if len(sums) != 2 {
+		t.Errorf("Expected 2 sums, got %d", len(sums))
+	}
+	if !strings.Contains(out.String(), "[updated]") {
+		t.Errorf("Expected updated status, got:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_ConditionalRequestNotModified(t *testing.T) {
+	handler, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+	handler.requests = nil
+	out.Reset()
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	for _, r := range handler.requests {
+		if filepath.Base(r.URL.Path) == manifestFileName {
+			continue
+		}
+		if r.Header.Get("If-None-Match") == "" || r.Header.Get("If-Modified-Since") == "" {
+			t.Errorf("Expected conditional headers for %s, got %v", r.URL.Path, r.Header)
+		}
+	}
+	if strings.Count(out.String(), "[not modified]") != 2 {
+		t.Errorf("Expected both schemas not modified, got:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_SkipsWriteWhenUnchanged(t *testing.T) {
+	handler, server := newSchemaServer(t)
+	handler.ignoreCondition = true
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+	path := filepath.Join(opts.OutputDir, "source-format.json")
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	out.Reset()
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	info, _ := os.Stat(path)
+	if !info.ModTime().Equal(past) {
+		t.Error("Expected unchanged schema not to be rewritten")
+	}
+	if !strings.Contains(out.String(), "[unchanged]") {
+		t.Errorf("Expected unchanged status, got:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_ForceBypassesConditions(t *testing.T) {
+	handler, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+	handler.requests = nil
+	out.Reset()
+
+	opts.Force = true
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("Forced sync failed: %v", err)
+	}
+	for _, r := range handler.requests {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			t.Errorf("Expected no conditional headers with --force, got %v", r.Header)
+		}
+	}
+	if strings.Count(out.String(), "[updated]") != 2 {
+		t.Errorf("Expected both schemas written with --force, got:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_ChecksumMismatch(t *testing.T) {
+	_, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+	opts.Expected = map[string]string{"generated-format.json": strings.Repeat("0", 64)}
+
+	err := syncSchemas(opts)
+	var mismatch *checksumError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected checksum error, got %v", err)
+	}
+	if mismatch.File != "generated-format.json" || !strings.Contains(err.Error(), "generated-format.json") {
+		t.Errorf("Expected error naming the file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(opts.OutputDir, "generated-format.json")); !os.IsNotExist(err) {
+		t.Error("Expected mismatched schema not to be written")
+	}
+}
+
+func TestRun_VerifyFileErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--verify", filepath.Join(t.TempDir(), "missing.sums"), t.TempDir()}, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "pinned checksums") {
+		t.Errorf("Expected failure reading pinned checksums, got %d: %s", code, stderr.String())
+	}
+}
+
+func TestReadSums_Format(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sums")
+	content := "# pinned\nabc123  source-format.json\ndef456 *generated-format.json\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write sums: %v", err)
+	}
+	sums, err := readSums(path)
+	if err != nil {
+		t.Fatalf("readSums failed: %v", err)
+	}
+	if sums["source-format.json"] != "abc123" || sums["generated-format.json"] != "def456" {
+		t.Errorf("Unexpected sums: %v", sums)
+	}
+
+	os.WriteFile(path, []byte("abc123\n"), 0644)
+	if _, err := readSums(path); err == nil {
+		t.Error("Expected error for a line without a file name")
+	}
+}
+
+const testManifest = `{
+  "version": 2,
+  "schemas": [
+    {"file": "source-format.json", "version": "1.0", "description": "Source test format"},
+    {"file": "generated-format.json", "version": "1.0"},
+    {"file": "implementation-config.json", "version": "0.3", "deprecated": false}
+  ]
+}`
+
+func newManifestServer(t *testing.T) (*schemaServer, *httptest.Server) {
+	t.Helper()
+	handler, server := newSchemaServer(t)
+	handler.content[manifestFileName] = testManifest
+	handler.content["implementation-config.json"] = `{"title": "config"}`
+	return handler, server
+}
+
+func TestSyncSchemas_Manifest(t *testing.T) {
+	handler, server := newManifestServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+
+	fetched := make(map[string]bool)
+	for _, r := range handler.requests {
+		fetched[filepath.Base(r.URL.Path)] = true
+	}
+	for _, schema := range []string{manifestFileName, "source-format.json", "generated-format.json", "implementation-config.json"} {
+		if !fetched[schema] {
+			t.Errorf("Expected %s to be fetched", schema)
+		}
+	}
+	for _, schema := range []string{"source-format.json", "generated-format.json", "implementation-config.json"} {
+		if _, err := os.Stat(filepath.Join(opts.OutputDir, schema)); err != nil {
+			t.Errorf("Expected %s to be written: %v", schema, err)
+		}
+	}
+	if !strings.Contains(out.String(), "implementation-config.json@0.3 (remote)") {
+		t.Errorf("Expected manifest version in output, got:\n%s", out.String())
+	}
+}
+
+func TestSyncSchemas_ManifestAbsent(t *testing.T) {
+	_, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "using default schema list") {
+		t.Errorf("Expected fallback to default schemas, got:\n%s", out.String())
+	}
+	if strings.Count(out.String(), "(remote)") != len(defaultSchemas) {
+		t.Errorf("Expected %d schemas synced, got:\n%s", len(defaultSchemas), out.String())
+	}
+}
+
+func TestSyncSchemas_LocalManifest(t *testing.T) {
+	handler, server := newSchemaServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+	opts.LocalPath = t.TempDir()
+	manifest := `{"schemas": [{"file": "source-format.json"}]}`
+	os.WriteFile(filepath.Join(opts.LocalPath, manifestFileName), []byte(manifest), 0644)
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+	if len(handler.requests) != 1 || filepath.Base(handler.requests[0].URL.Path) != "source-format.json" {
+		t.Errorf("Expected only source-format.json fetched, got %d requests", len(handler.requests))
+	}
+}
+
+func TestSyncSchemas_Only(t *testing.T) {
+	_, server := newManifestServer(t)
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+	opts.Only = []string{"implementation-config.json"}
+
+	if err := syncSchemas(opts); err != nil {
+		t.Fatalf("syncSchemas failed: %v", err)
+	}
+	if strings.Count(out.String(), "(remote)") != 1 {
+		t.Errorf("Expected one schema synced, got:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(opts.OutputDir, "source-format.json")); !os.IsNotExist(err) {
+		t.Error("Expected schemas outside --only to be skipped")
+	}
+
+	opts.Only = []string{"missing.json"}
+	if err := syncSchemas(opts); err == nil || !strings.Contains(err.Error(), "missing.json") {
+		t.Errorf("Expected error for unknown --only schema, got %v", err)
+	}
+}
+
+func TestLoadManifest_InvalidFileName(t *testing.T) {
+	opts := syncOptions{LocalPath: t.TempDir(), Stdout: &bytes.Buffer{}}
+	manifest := `{"schemas": [{"file": "../escape.json"}]}`
+	os.WriteFile(filepath.Join(opts.LocalPath, manifestFileName), []byte(manifest), 0644)
+
+	if _, _, err := loadManifest(opts); err == nil {
+		t.Error("Expected error for schema path outside the output directory")
+	}
+}
+
+// flakyServer fails the first requests with fail, then serves body
+func flakyServer(t *testing.T, failures int, fail func(w http.ResponseWriter), body string) (*httptest.Server, *int) {
+	t.Helper()
+	var mu sync.Mutex
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		count++
+		n := count
+		mu.Unlock()
+		if n <= failures {
+			fail(w)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+func retryOptions(server *httptest.Server) syncOptions {
+	return syncOptions{
+		Client:      server.Client(),
+		Stdout:      &bytes.Buffer{},
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+	}
+}
+
+func TestDownloadSchema_RetriesServerError(t *testing.T) {
+	server, count := flakyServer(t, 1, func(w http.ResponseWriter) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}, `{"title": "source"}`)
+
+	data, _, err := downloadSchema(retryOptions(server), server.URL+"/source-format.json", "", "", "")
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if string(data) != `{"title": "source"}` || *count != 2 {
+		t.Errorf("Expected success on second attempt, got %q after %d requests", data, *count)
+	}
+}
+
+func TestDownloadSchema_RetriesConnectionReset(t *testing.T) {
+	server, count := flakyServer(t, 2, func(w http.ResponseWriter) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		if tcp, ok := conn.(interface{ SetLinger(int) error }); ok {
+			tcp.SetLinger(0)
+		}
+		conn.Close()
+	}, `{"title": "source"}`)
+
+	data, _, err := downloadSchema(retryOptions(server), server.URL+"/source-format.json", "", "", "")
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if string(data) != `{"title": "source"}` || *count != 3 {
+		t.Errorf("Expected success on third attempt, got %q after %d requests", data, *count)
+	}
+}
+
+func TestDownloadSchema_NoRetryOnClientError(t *testing.T) {
+	server, count := flakyServer(t, 5, func(w http.ResponseWriter) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}, `{}`)
+
+	if _, _, err := downloadSchema(retryOptions(server), server.URL+"/source-format.json", "", "", ""); err == nil {
+		t.Fatal("Expected error for 403")
+	}
+	if *count != 1 {
+		t.Errorf("Expected a single attempt for 403, got %d", *count)
+	}
+}
+
+func TestSyncSchemas_FailedDownloadKeepsExisting(t *testing.T) {
+	server, count := flakyServer(t, 100, func(w http.ResponseWriter) {
+		http.Error(w, "unavailable", http.StatusBadGateway)
+	}, `{}`)
+	opts := retryOptions(server)
+	opts.OutputDir = t.TempDir()
+	opts.BaseURL = server.URL
+	opts.LocalPath = filepath.Join(t.TempDir(), "none")
+	opts.Schemas = []string{"source-format.json"}
+
+	existing := filepath.Join(opts.OutputDir, "source-format.json")
+	os.WriteFile(existing, []byte(`{"title": "old"}`), 0644)
+
+	err := syncSchemas(opts)
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("Expected error after 3 attempts, got %v", err)
+	}
+	// Manifest lookup and schema download both exhaust their attempts
+	if *count != 3 {
+		t.Errorf("Expected 3 requests, got %d", *count)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != `{"title": "old"}` {
+		t.Errorf("Expected existing schema preserved, got %q", data)
+	}
+}
+
+func TestSyncSchemas_InvalidJSONNotWritten(t *testing.T) {
+	handler, server := newSchemaServer(t)
+	handler.content["generated-format.json"] = `{"title": "gener`
+	var out bytes.Buffer
+	opts := testSyncOptions(t, server, &out)
+
+	err := syncSchemas(opts)
+	if err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Fatalf("Expected invalid JSON error, got %v", err)
+	}
+	entries, _ := os.ReadDir(opts.OutputDir)
+	for _, entry := range entries {
+		if entry.Name() == "generated-format.json" || strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("Expected no partial output, found %s", entry.Name())
+		}
+	}
+}
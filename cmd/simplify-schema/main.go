@@ -1,115 +1,223 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/schemasimplify"
 )
 
-// SchemaSimplifier removes go-jsonschema incompatible features from JSON schemas
-type SchemaSimplifier struct {
-	inputFile  string
-	outputFile string
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
 
-func main() {
-	if len(os.Args) != 3 {
-		fmt.Printf("Usage: %s <input-schema.json> <output-schema.json>\n", os.Args[0])
-		fmt.Println("Removes go-jsonschema incompatible features from JSON schemas")
-		os.Exit(1)
+// run parses arguments, simplifies the requested schemas, and returns the
+// exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("simplify-schema", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keep := fs.String("keep", "", "Comma-separated keywords to retain (e.g. description,default)")
+	remove := fs.String("remove", "", "Comma-separated keywords to remove in addition to the defaults")
+	inDir := fs.String("in-dir", "", "Simplify every *.json file in this directory")
+	outDir := fs.String("out-dir", "", "Write simplified schemas from --in-dir here")
+	useStdin := fs.Bool("stdin", false, "Read the input schema from stdin")
+	useStdout := fs.Bool("stdout", false, "Write the simplified schema to stdout")
+	dropCombinators := fs.Bool("drop-combinators", false, "Drop allOf/anyOf/oneOf instead of flattening them")
+	keepSharedRefs := fs.Int("keep-shared-refs", 0, "Keep definitions whose $ref is used more than N times (0 inlines all)")
+	reportPath := fs.String("report", "", "Write the removed-keyword report to this file instead of stderr")
+	failOn := fs.String("fail-on-removals", "", "Fail when a removed keyword matches this regular expression (e.g. required|enum)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: simplify-schema [flags] <input-schema.json> <output-schema.json>")
+		fmt.Fprintln(fs.Output(), "       simplify-schema [flags] --in-dir <dir> --out-dir <dir>")
+		fmt.Fprintln(fs.Output(), "       simplify-schema [flags] --stdin --stdout")
+		fmt.Fprintln(fs.Output(), "Removes go-jsonschema incompatible features from JSON schemas")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
 	}
 
-	simplifier := &SchemaSimplifier{
-		inputFile:  os.Args[1],
-		outputFile: os.Args[2],
+	j := &job{
+		opts: schemasimplify.Options{
+			Keep:            splitList(*keep),
+			Remove:          splitList(*remove),
+			DropCombinators: *dropCombinators,
+			KeepSharedRefs:  *keepSharedRefs,
+		},
+		messages: stdout,
+		report:   stderr,
+	}
+	// Status messages stay off stdout when it carries the schema
+	if *useStdout {
+		j.messages = stderr
+	}
+	if *failOn != "" {
+		protect, err := regexp.Compile("^(?:" + *failOn + ")$")
+		if err != nil {
+			return usageError(fs, fmt.Sprintf("invalid --fail-on-removals pattern: %v", err))
+		}
+		j.protect = protect
+	}
+	if *reportPath != "" {
+		file, err := os.Create(*reportPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: failed to create report: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		j.report = file
 	}
 
-	if err := simplifier.simplify(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	if *inDir != "" || *outDir != "" {
+		if *inDir == "" || *outDir == "" || *useStdin || *useStdout || fs.NArg() != 0 {
+			return usageError(fs, "--in-dir and --out-dir must be used together, without files or --stdin/--stdout")
+		}
+		if err := j.simplifyDir(*inDir, *outDir); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
 	}
 
-	fmt.Printf("Successfully simplified schema: %s -> %s\n", simplifier.inputFile, simplifier.outputFile)
-}
+	// Positional arguments fill whichever of input and output are not streams
+	want := 2
+	if *useStdin {
+		want--
+	}
+	if *useStdout {
+		want--
+	}
+	if fs.NArg() != want {
+		return usageError(fs, fmt.Sprintf("expected %d file arguments, got %d", want, fs.NArg()))
+	}
+	positional := fs.Args()
 
-func (s *SchemaSimplifier) simplify() error {
-	// Read input schema
-	data, err := os.ReadFile(s.inputFile)
+	var input []byte
+	var err error
+	inputName := "stdin"
+	if *useStdin {
+		input, err = io.ReadAll(stdin)
+	} else {
+		inputName = positional[0]
+		positional = positional[1:]
+		input, err = os.ReadFile(inputName)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read input file: %w", err)
+		fmt.Fprintf(stderr, "Error: failed to read input: %v\n", err)
+		return 1
 	}
 
-	// Parse JSON
-	var schema map[string]interface{}
-	if err := json.Unmarshal(data, &schema); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+	output, err := j.simplify(inputName, input)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
 	}
 
-	// Simplify schema by removing problematic features
-	simplified := s.removeIncompatibleFeatures(schema)
+	outputName := "stdout"
+	if *useStdout {
+		stdout.Write(output)
+		fmt.Fprintln(stdout)
+	} else {
+		outputName = positional[0]
+		if err := os.WriteFile(outputName, output, 0644); err != nil {
+			fmt.Fprintf(stderr, "Error: failed to write output file: %v\n", err)
+			return 1
+		}
+	}
 
-	// Write output schema
-	output, err := json.MarshalIndent(simplified, "", "  ")
+	fmt.Fprintf(j.messages, "Successfully simplified schema: %s -> %s\n", inputName, outputName)
+	return 0
+}
+
+// job carries the settings shared by every schema in a run
+type job struct {
+	opts     schemasimplify.Options
+	protect  *regexp.Regexp // Keywords that must not be removed
+	messages io.Writer
+	report   io.Writer
+}
+
+// simplify simplifies one schema, reports what was removed, and fails when
+// a protected keyword would be removed
+func (j *job) simplify(name string, input []byte) ([]byte, error) {
+	simplifier := schemasimplify.New(j.opts)
+	output, err := simplifier.SimplifyJSON(input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Fprint(j.messages, simplifier.Summary())
+	fmt.Fprintf(j.report, "%s: %s", name, simplifier.RemovalReport())
+
+	if j.protect != nil {
+		if protected := simplifier.ProtectedRemovals(j.protect); len(protected) > 0 {
+			var lines []string
+			for _, removal := range protected {
+				lines = append(lines, fmt.Sprintf("%s: %s", removal.Path, removal.Keyword))
+			}
+			return nil, fmt.Errorf("%s: protected keywords would be removed:\n  %s", name, strings.Join(lines, "\n  "))
+		}
+	}
+	return output, nil
+}
+
+// simplifyDir simplifies every *.json file in inDir into outDir
+func (j *job) simplifyDir(inDir, outDir string) error {
+	inputs, err := filepath.Glob(filepath.Join(inDir, "*.json"))
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no *.json files in %s", inDir)
 	}
+	sort.Strings(inputs)
 
-	if err := os.WriteFile(s.outputFile, output, 0644); err != nil {
-		return fmt.Errorf("failed to write output file: %w", err)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			return fmt.Errorf("failed to read input file: %w", err)
+		}
+		output, err := j.simplify(input, data)
+		if err != nil {
+			return err
+		}
+		outputPath := filepath.Join(outDir, filepath.Base(input))
+		if err := os.WriteFile(outputPath, output, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(j.messages, "Successfully simplified schema: %s -> %s\n", input, outputPath)
+	}
 	return nil
 }
 
-func (s *SchemaSimplifier) removeIncompatibleFeatures(obj interface{}) interface{} {
-	switch v := obj.(type) {
-	case map[string]interface{}:
-		result := make(map[string]interface{})
-		for key, value := range v {
-			switch key {
-			case "allOf", "anyOf", "oneOf":
-				// Skip conditional logic - go-jsonschema can't handle these well
-				continue
-			case "if", "then", "else":
-				// Skip conditional validation - incompatible with go-jsonschema
-				continue
-			case "additionalProperties":
-				// Skip for now - can cause issues in some contexts
-				continue
-			case "pattern":
-				// Skip regex patterns - not needed for type generation
-				continue
-			case "description":
-				// Skip descriptions to reduce output size
-				continue
-			case "default":
-				// Skip defaults - go-jsonschema doesn't use them
-				continue
-			case "enum":
-				// Keep enum values but in simplified form
-				if arr, ok := value.([]interface{}); ok {
-					result[key] = arr
-				}
-			case "items":
-				// Recursively process array items
-				result[key] = s.removeIncompatibleFeatures(value)
-			case "properties":
-				// Recursively process object properties
-				result[key] = s.removeIncompatibleFeatures(value)
-			default:
-				// Keep other properties, recursively processing complex values
-				result[key] = s.removeIncompatibleFeatures(value)
-			}
-		}
-		return result
-	case []interface{}:
-		result := make([]interface{}, len(v))
-		for i, item := range v {
-			result[i] = s.removeIncompatibleFeatures(item)
+func usageError(fs *flag.FlagSet, message string) int {
+	fmt.Fprintf(fs.Output(), "Error: %s\n", message)
+	fs.Usage()
+	return 2
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
-		return result
-	default:
-		// Primitive values (string, number, bool) - keep as-is
-		return v
 	}
+	return items
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{"type": "object", "description": "Root", "properties": {"name": {"type": "string", "default": ""}}}`
+
+func TestRun_Files(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	output := filepath.Join(dir, "output.json")
+	os.WriteFile(input, []byte(testSchema), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--keep", "default", input, output}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr.String())
+	}
+	data, _ := os.ReadFile(output)
+	if !strings.Contains(string(data), `"default": ""`) || strings.Contains(string(data), "Root") {
+		t.Errorf("Expected default kept and description removed, got:\n%s", data)
+	}
+	if !strings.Contains(stdout.String(), "Successfully simplified schema") {
+		t.Errorf("Expected status message, got:\n%s", stdout.String())
+	}
+}
+
+func TestRun_Directory(t *testing.T) {
+	inDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "out")
+	os.WriteFile(filepath.Join(inDir, "a.json"), []byte(testSchema), 0644)
+	os.WriteFile(filepath.Join(inDir, "b.json"), []byte(`{"type": "string", "pattern": "x"}`), 0644)
+	os.WriteFile(filepath.Join(inDir, "notes.txt"), []byte("ignored"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--in-dir", inDir, "--out-dir", outDir}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr.String())
+	}
+	entries, _ := os.ReadDir(outDir)
+	if len(entries) != 2 || entries[0].Name() != "a.json" || entries[1].Name() != "b.json" {
+		t.Errorf("Expected a.json and b.json, got %v", entries)
+	}
+	data, _ := os.ReadFile(filepath.Join(outDir, "b.json"))
+	if strings.Contains(string(data), "pattern") {
+		t.Errorf("Expected pattern removed, got:\n%s", data)
+	}
+}
+
+func TestRun_StdinStdout(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--stdin", "--stdout", "--remove", "type"}, strings.NewReader(testSchema), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr.String())
+	}
+	want := "{\n  \"properties\": {\n    \"name\": {}\n  }\n}\n"
+	if stdout.String() != want {
+		t.Errorf("Expected only the schema on stdout, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "stdin -> stdout") {
+		t.Errorf("Expected status on stderr, got:\n%s", stderr.String())
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	tests := [][]string{
+		{"only-one.json"},
+		{"--in-dir", "schemas"},
+		{"--in-dir", "a", "--out-dir", "b", "extra.json"},
+		{"--stdin", "--stdout", "extra.json"},
+	}
+	for _, args := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, nil, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit 2 for %v, got %d", args, code)
+		}
+	}
+}
+
+func TestRun_ReportFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	report := filepath.Join(dir, "report.txt")
+	os.WriteFile(input, []byte(testSchema), 0644)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--report", report, input, filepath.Join(dir, "output.json")}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit 0, got %d: %s", code, stderr.String())
+	}
+	data, _ := os.ReadFile(report)
+	want := input + ": Removed 2 keywords:\n  #: description\n  #/properties/name: default\nCounts:\n  default: 1\n  description: 1\n"
+	if string(data) != want {
+		t.Errorf("Report mismatch.\nGot:\n%s\nWant:\n%s", data, want)
+	}
+	if strings.Contains(stderr.String(), "Removed") {
+		t.Errorf("Expected report only in the file, got stderr:\n%s", stderr.String())
+	}
+}
+
+func TestRun_FailOnRemovals(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	output := filepath.Join(dir, "output.json")
+	os.WriteFile(input, []byte(testSchema), 0644)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--fail-on-removals", "required|default", input, output}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("Expected exit 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "protected keywords would be removed:\n  #/properties/name: default") {
+		t.Errorf("Expected protected removal named, got:\n%s", stderr.String())
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Error("Expected no output written")
+	}
+
+	if code := run([]string{"--fail-on-removals", "(", input, output}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected usage error for invalid pattern, got %d", code)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"slices"
+	"sync"
+)
+
+var (
+	customBehaviorsMu sync.RWMutex
+	customBehaviors   []CCLBehavior
+)
+
+// AllBehaviors returns the behaviors of every conflict group, in group name
+// order, followed by any registered with RegisterBehavior
+func AllBehaviors() []CCLBehavior {
+	conflicts := GetBehaviorConflicts()
+	groups := make([]string, 0, len(conflicts))
+	for group := range conflicts {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+
+	var behaviors []CCLBehavior
+	for _, group := range groups {
+		behaviors = append(behaviors, conflicts[group]...)
+	}
+	customBehaviorsMu.RLock()
+	defer customBehaviorsMu.RUnlock()
+	return append(behaviors, customBehaviors...)
+}
+
+// RegisterBehavior makes behavior known to IsKnownBehavior, for test data
+// using behaviors this library does not define. Registering a behavior
+// twice has no effect.
+func RegisterBehavior(behavior CCLBehavior) {
+	if IsKnownBehavior(string(behavior)) {
+		return
+	}
+	customBehaviorsMu.Lock()
+	defer customBehaviorsMu.Unlock()
+	if !slices.Contains(customBehaviors, behavior) {
+		customBehaviors = append(customBehaviors, behavior)
+	}
+}
+
+// IsKnownBehavior reports whether name is a behavior constant or was
+// registered with RegisterBehavior
+func IsKnownBehavior(name string) bool {
+	return slices.Contains(AllBehaviors(), CCLBehavior(name))
+}
+
+// maxSuggestDistance is the most edits SuggestBehavior allows between a
+// name and its suggestion
+const maxSuggestDistance = 3
+
+// SuggestBehavior returns the known behavior closest to name by edit
+// distance, if any is within a few edits. Ties go to the first in
+// AllBehaviors order.
+func SuggestBehavior(name string) (CCLBehavior, bool) {
+	var best CCLBehavior
+	bestDistance := maxSuggestDistance + 1
+	for _, behavior := range AllBehaviors() {
+		if distance := editDistance(name, string(behavior)); distance < bestDistance {
+			best, bestDistance = behavior, distance
+		}
+	}
+	return best, bestDistance <= maxSuggestDistance
+}
+
+// editDistance returns the Levenshtein distance between a and b in bytes
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,69 @@
+package config
+
+import "testing"
+
+func TestIsKnownBehavior(t *testing.T) {
+	if !IsKnownBehavior("boolean_lenient") {
+		t.Error("Expected boolean_lenient to be known")
+	}
+	if IsKnownBehavior("boolean_leniant") {
+		t.Error("Expected boolean_leniant to be unknown")
+	}
+	if got := len(AllBehaviors()); got != 10 {
+		t.Errorf("Expected 10 behaviors, got %d", got)
+	}
+}
+
+func TestRegisterBehavior(t *testing.T) {
+	t.Cleanup(func() { customBehaviors = nil })
+
+	RegisterBehavior("unicode_nfc")
+	RegisterBehavior("unicode_nfc")
+	RegisterBehavior(BehaviorBooleanStrict)
+	if !IsKnownBehavior("unicode_nfc") {
+		t.Error("Expected a registered behavior to be known")
+	}
+	if got := len(AllBehaviors()); got != 11 {
+		t.Errorf("Expected registering to add one behavior once, got %d", got)
+	}
+	if got, ok := SuggestBehavior("unicode_nfd"); !ok || got != "unicode_nfc" {
+		t.Errorf("Expected registered behaviors to be suggested, got %q, %v", got, ok)
+	}
+}
+
+func TestSuggestBehavior(t *testing.T) {
+	tests := []struct {
+		name string
+		want CCLBehavior
+		ok   bool
+	}{
+		{"boolean_leniant", BehaviorBooleanLenient, true},
+		{"boolean_strcit", BehaviorBooleanStrict, true},
+		{"tabs_as_whitespce", BehaviorTabsAsWhitespace, true},
+		{"crlf_normalise_to_lf", BehaviorCRLFNormalize, true},
+		{"yaml_anchors", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := SuggestBehavior(tt.name)
+		if got != tt.want && tt.ok || ok != tt.ok {
+			t.Errorf("SuggestBehavior(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"boolean_leniant", "boolean_lenient", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
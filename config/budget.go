@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// BudgetsFile is the repo-level file FindBudgets looks for
+const BudgetsFile = "ccl-budgets.json"
+
+// Budgets caps the size of test data, so one oversized test cannot bloat
+// every clone and slow every load. A zero limit is unlimited.
+type Budgets struct {
+	MaxInputBytes     int64 `json:"max_input_bytes"`     // Combined size of one test's inputs
+	MaxTestsPerFile   int64 `json:"max_tests_per_file"`  // Tests in one test file
+	MaxGeneratedBytes int64 `json:"max_generated_bytes"` // Combined size of the files in a generated tests directory
+}
+
+// DefaultBudgets returns the limits used when no budgets file sets them
+func DefaultBudgets() Budgets {
+	return Budgets{
+		MaxInputBytes:     64 << 10,
+		MaxTestsPerFile:   5000,
+		MaxGeneratedBytes: 50 << 20,
+	}
+}
+
+// LoadBudgets reads budgets from a JSON file; limits the file leaves out
+// keep their defaults
+func LoadBudgets(path string) (Budgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Budgets{}, fmt.Errorf("failed to read budgets: %w", err)
+	}
+
+	budgets := DefaultBudgets()
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return Budgets{}, fmt.Errorf("failed to parse budgets %s: %w", path, err)
+	}
+	if budgets.MaxInputBytes < 0 || budgets.MaxTestsPerFile < 0 || budgets.MaxGeneratedBytes < 0 {
+		return Budgets{}, fmt.Errorf("invalid budgets %s: limits must not be negative", path)
+	}
+	return budgets, nil
+}
+
+// FindBudgets loads the BudgetsFile in dir or its nearest parent that has
+// one, or returns DefaultBudgets if there is none
+func FindBudgets(dir string) (Budgets, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Budgets{}, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		path := filepath.Join(dir, BudgetsFile)
+		if _, err := os.Stat(path); err == nil {
+			return LoadBudgets(path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return Budgets{}, fmt.Errorf("failed to check budgets: %w", err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return DefaultBudgets(), nil
+		}
+		dir = parent
+	}
+}
+
+// BudgetError reports a limit that test data exceeds
+type BudgetError struct {
+	Budget string // JSON name of the limit, such as "max_input_bytes"
+	Actual int64
+	Limit  int64
+}
+
+func (e *BudgetError) Error() string {
+	switch e.Budget {
+	case "max_input_bytes":
+		return fmt.Sprintf("inputs are %d bytes, over max_input_bytes (%d)", e.Actual, e.Limit)
+	case "max_tests_per_file":
+		return fmt.Sprintf("%d tests, over max_tests_per_file (%d)", e.Actual, e.Limit)
+	default:
+		return fmt.Sprintf("files total %d bytes, over %s (%d)", e.Actual, e.Budget, e.Limit)
+	}
+}
+
+// CheckInputs returns an error if a test's inputs exceed MaxInputBytes
+func (b Budgets) CheckInputs(inputs []string) *BudgetError {
+	var size int64
+	for _, input := range inputs {
+		size += int64(len(input))
+	}
+	return check("max_input_bytes", size, b.MaxInputBytes)
+}
+
+// CheckTestCount returns an error if a file's tests exceed MaxTestsPerFile
+func (b Budgets) CheckTestCount(tests int) *BudgetError {
+	return check("max_tests_per_file", int64(tests), b.MaxTestsPerFile)
+}
+
+// CheckGeneratedSize returns an error if a directory's files exceed
+// MaxGeneratedBytes
+func (b Budgets) CheckGeneratedSize(size int64) *BudgetError {
+	return check("max_generated_bytes", size, b.MaxGeneratedBytes)
+}
+
+func check(budget string, actual, limit int64) *BudgetError {
+	if limit == 0 || actual <= limit {
+		return nil
+	}
+	return &BudgetError{Budget: budget, Actual: actual, Limit: limit}
+}
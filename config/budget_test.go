@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBudgets_Checks(t *testing.T) {
+	budgets := Budgets{MaxInputBytes: 10, MaxTestsPerFile: 3, MaxGeneratedBytes: 100}
+
+	if problem := budgets.CheckInputs([]string{"a = b", "c = d"}); problem != nil {
+		t.Errorf("Expected inputs at the limit to pass, got %v", problem)
+	}
+	problem := budgets.CheckInputs([]string{"a = b", "c = dd"})
+	if problem == nil || problem.Actual != 11 || problem.Limit != 10 {
+		t.Errorf("Expected 11 > 10 input bytes, got %v", problem)
+	} else if want := "inputs are 11 bytes, over max_input_bytes (10)"; problem.Error() != want {
+		t.Errorf("Expected %q, got %q", want, problem.Error())
+	}
+
+	if problem := budgets.CheckTestCount(3); problem != nil {
+		t.Errorf("Expected a test count at the limit to pass, got %v", problem)
+	}
+	if problem := budgets.CheckTestCount(4); problem == nil || problem.Error() != "4 tests, over max_tests_per_file (3)" {
+		t.Errorf("Unexpected test count problem %v", problem)
+	}
+
+	if problem := budgets.CheckGeneratedSize(100); problem != nil {
+		t.Errorf("Expected a size at the limit to pass, got %v", problem)
+	}
+	if problem := budgets.CheckGeneratedSize(101); problem == nil || problem.Error() != "files total 101 bytes, over max_generated_bytes (100)" {
+		t.Errorf("Unexpected size problem %v", problem)
+	}
+
+	// Zero limits are unlimited
+	var unlimited Budgets
+	if unlimited.CheckInputs([]string{strings.Repeat("x", 1<<20)}) != nil || unlimited.CheckTestCount(1<<20) != nil || unlimited.CheckGeneratedSize(1<<40) != nil {
+		t.Error("Expected zero budgets to allow anything")
+	}
+}
+
+func TestLoadBudgets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BudgetsFile)
+	if err := os.WriteFile(path, []byte(`{"max_input_bytes": 1024, "max_generated_bytes": 0}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+
+	budgets, err := LoadBudgets(path)
+	if err != nil {
+		t.Fatalf("LoadBudgets failed: %v", err)
+	}
+	want := DefaultBudgets()
+	want.MaxInputBytes = 1024
+	want.MaxGeneratedBytes = 0
+	if budgets != want {
+		t.Errorf("Expected %+v, got %+v", want, budgets)
+	}
+}
+
+func TestLoadBudgets_RejectsNegative(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BudgetsFile)
+	if err := os.WriteFile(path, []byte(`{"max_tests_per_file": -1}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+	if _, err := LoadBudgets(path); err == nil {
+		t.Error("Expected an error for a negative limit")
+	}
+}
+
+func TestFindBudgets(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "generated_tests")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	budgets, err := FindBudgets(dir)
+	if err != nil {
+		t.Fatalf("FindBudgets failed: %v", err)
+	}
+	if budgets != DefaultBudgets() {
+		t.Errorf("Expected defaults without a budgets file, got %+v", budgets)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, BudgetsFile), []byte(`{"max_tests_per_file": 7}`), 0644); err != nil {
+		t.Fatalf("Failed to write budgets: %v", err)
+	}
+	budgets, err = FindBudgets(dir)
+	if err != nil {
+		t.Fatalf("FindBudgets failed: %v", err)
+	}
+	if budgets.MaxTestsPerFile != 7 {
+		t.Errorf("Expected the parent's budgets file to be used, got %+v", budgets)
+	}
+}
@@ -28,18 +28,20 @@ type ImplementationConfig struct {
 type CCLFunction string
 
 const (
-	FunctionParse          CCLFunction = "parse"
-	FunctionParseIndented  CCLFunction = "parse_indented"
-	FunctionFilter         CCLFunction = "filter"
-	FunctionCombine        CCLFunction = "combine"
-	FunctionExpandDotted   CCLFunction = "expand_dotted"
-	FunctionBuildHierarchy CCLFunction = "build_hierarchy"
-	FunctionGetString      CCLFunction = "get_string"
-	FunctionGetInt         CCLFunction = "get_int"
-	FunctionGetBool        CCLFunction = "get_bool"
-	FunctionGetFloat       CCLFunction = "get_float"
-	FunctionGetList        CCLFunction = "get_list"
-	FunctionPrettyPrint    CCLFunction = "pretty_print"
+	FunctionParse           CCLFunction = "parse"
+	FunctionParseIndented   CCLFunction = "parse_indented"
+	FunctionFilter          CCLFunction = "filter"
+	FunctionExtractComments CCLFunction = "extract_comments"
+	FunctionCombine         CCLFunction = "combine"
+	FunctionExpandDotted    CCLFunction = "expand_dotted"
+	FunctionBuildHierarchy  CCLFunction = "build_hierarchy"
+	FunctionGetString       CCLFunction = "get_string"
+	FunctionGetInt          CCLFunction = "get_int"
+	FunctionGetBool         CCLFunction = "get_bool"
+	FunctionGetFloat        CCLFunction = "get_float"
+	FunctionGetList         CCLFunction = "get_list"
+	FunctionPrettyPrint     CCLFunction = "pretty_print"
+	FunctionCanonicalFormat CCLFunction = "canonical_format"
 )
 
 // AllFunctions returns all valid CCL functions
@@ -48,6 +50,7 @@ func AllFunctions() []CCLFunction {
 		FunctionParse,
 		FunctionParseIndented,
 		FunctionFilter,
+		FunctionExtractComments,
 		FunctionCombine,
 		FunctionExpandDotted,
 		FunctionBuildHierarchy,
@@ -57,6 +60,7 @@ func AllFunctions() []CCLFunction {
 		FunctionGetFloat,
 		FunctionGetList,
 		FunctionPrettyPrint,
+		FunctionCanonicalFormat,
 	}
 }
 
@@ -67,6 +67,7 @@ func TestAllFunctions_Completeness(t *testing.T) {
 		FunctionParse,
 		FunctionParseIndented,
 		FunctionFilter,
+		FunctionExtractComments,
 		FunctionCombine,
 		FunctionExpandDotted,
 		FunctionBuildHierarchy,
@@ -76,6 +77,7 @@ func TestAllFunctions_Completeness(t *testing.T) {
 		FunctionGetFloat,
 		FunctionGetList,
 		FunctionPrettyPrint,
+		FunctionCanonicalFormat,
 	}
 
 	if len(functions) != len(expectedFunctions) {
@@ -162,7 +164,7 @@ func TestGetBehaviorConflicts_Structure(t *testing.T) {
 	expectedGroups := []string{
 		"crlf_handling",
 		"tab_handling",
-		"spacing",
+		"indent_output",
 		"boolean",
 		"list_coercion",
 	}
@@ -483,6 +485,7 @@ func TestCCLFunction_StringValues(t *testing.T) {
 		{FunctionParse, "parse"},
 		{FunctionParseIndented, "parse_indented"},
 		{FunctionFilter, "filter"},
+		{FunctionExtractComments, "extract_comments"},
 		{FunctionCombine, "combine"},
 		{FunctionExpandDotted, "expand_dotted"},
 		{FunctionBuildHierarchy, "build_hierarchy"},
@@ -492,6 +495,7 @@ func TestCCLFunction_StringValues(t *testing.T) {
 		{FunctionGetFloat, "get_float"},
 		{FunctionGetList, "get_list"},
 		{FunctionPrettyPrint, "pretty_print"},
+		{FunctionCanonicalFormat, "canonical_format"},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,68 @@
+package config
+
+// functionDescriptions and the tables below document every capability for
+// report.CapabilityReference; a new constant needs an entry here too
+var functionDescriptions = map[CCLFunction]string{
+	FunctionParse:           "Parse CCL text into a flat list of key-value entries, in input order",
+	FunctionParseIndented:   "Parse CCL text whose entries share a common indentation, removing it first",
+	FunctionFilter:          "Remove comment entries (key \"/\") from a list of entries",
+	FunctionExtractComments: "Keep only the comment entries (key \"/\") of a list of entries; the inverse of filter",
+	FunctionCombine:         "Concatenate two entry lists, the monoid operation of CCL",
+	FunctionExpandDotted:    "Expand dotted keys such as a.b = c into nested entries",
+	FunctionBuildHierarchy:  "Build nested objects from entries by recursively parsing their values",
+	FunctionGetString:       "Read a string value at a key path of the hierarchy",
+	FunctionGetInt:          "Read an integer value at a key path of the hierarchy",
+	FunctionGetBool:         "Read a boolean value at a key path of the hierarchy",
+	FunctionGetFloat:        "Read a floating-point value at a key path of the hierarchy",
+	FunctionGetList:         "Read a list at a key path, from repeated keys or empty-key entries",
+	FunctionPrettyPrint:     "Render entries back to CCL text that parses to the same entries",
+	FunctionCanonicalFormat: "Render a hierarchy as CCL text in the canonical layout",
+}
+
+var featureDescriptions = map[CCLFeature]string{
+	FeatureComments:               "Entries with the key \"/\" are comments, which filter removes and extract_comments keeps",
+	FeatureExperimentalDottedKeys: "Keys containing dots are paths into the hierarchy (expand_dotted)",
+	FeatureEmptyKeys:              "Entries with an empty key, as in \"= value\", used for list items",
+	FeatureMultiline:              "Values continued over indented lines, and nested CCL inside values",
+	FeatureUnicode:                "Keys and values with non-ASCII text, compared as UTF-8",
+	FeatureWhitespace:             "Spaces and tabs around keys and values, and blank lines, are trimmed as the spec says",
+}
+
+var behaviorDescriptions = map[CCLBehavior]string{
+	BehaviorCRLFNormalize:    "CRLF line endings in the input are read as LF",
+	BehaviorCRLFPreserve:     "CRLF line endings are kept in values as written",
+	BehaviorTabsAsContent:    "Tabs are part of values rather than whitespace to trim",
+	BehaviorTabsAsWhitespace: "Tabs are whitespace, trimmed and counted as indentation like spaces",
+	BehaviorIndentSpaces:     "Nested output is indented with spaces",
+	BehaviorIndentTabs:       "Nested output is indented with tabs",
+	BehaviorBooleanStrict:    "Only true and false are booleans",
+	BehaviorBooleanLenient:   "Common spellings beyond true and false, such as yes and no, are booleans too",
+	BehaviorListCoercionOn:   "A single value reads as a one-item list",
+	BehaviorListCoercionOff:  "Only repeated keys or empty-key entries form a list",
+}
+
+var variantDescriptions = map[CCLVariant]string{
+	VariantProposed:  "Behavior proposed for the CCL specification, which may differ from the reference implementation",
+	VariantReference: "Behavior of the OCaml reference implementation",
+}
+
+// Description explains what the function does, or is "" if unknown
+func (f CCLFunction) Description() string {
+	return functionDescriptions[f]
+}
+
+// Description explains what the feature gates, or is "" if unknown
+func (f CCLFeature) Description() string {
+	return featureDescriptions[f]
+}
+
+// Description explains the behavior choice, or is "" for a registered or
+// unknown behavior
+func (b CCLBehavior) Description() string {
+	return behaviorDescriptions[b]
+}
+
+// Description explains the variant, or is "" if unknown
+func (v CCLVariant) Description() string {
+	return variantDescriptions[v]
+}
@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestDescriptions_Complete(t *testing.T) {
+	for _, fn := range AllFunctions() {
+		if fn.Description() == "" {
+			t.Errorf("Function %s has no description", fn)
+		}
+	}
+	for _, feature := range AllFeatures() {
+		if feature.Description() == "" {
+			t.Errorf("Feature %s has no description", feature)
+		}
+	}
+	// Registered behaviors have none, so check the constants of each group
+	for group, behaviors := range GetBehaviorConflicts() {
+		for _, behavior := range behaviors {
+			if behavior.Description() == "" {
+				t.Errorf("Behavior %s (%s) has no description", behavior, group)
+			}
+		}
+	}
+	for _, variant := range AllVariants() {
+		if variant.Description() == "" {
+			t.Errorf("Variant %s has no description", variant)
+		}
+	}
+}
+
+func TestDescriptions_OnlyKnown(t *testing.T) {
+	if len(functionDescriptions) != len(AllFunctions()) || len(featureDescriptions) != len(AllFeatures()) || len(variantDescriptions) != len(AllVariants()) {
+		t.Error("Expected a description for each known function, feature, and variant and no others")
+	}
+	behaviors := 0
+	for _, group := range GetBehaviorConflicts() {
+		behaviors += len(group)
+	}
+	if len(behaviorDescriptions) != behaviors {
+		t.Errorf("Expected %d behavior descriptions, got %d", behaviors, len(behaviorDescriptions))
+	}
+	if CCLFunction("load").Description() != "" {
+		t.Error("Expected no description for an unknown function")
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConfigDiff lists the capabilities one config declares that another does
+// not. Each list is sorted.
+type ConfigDiff struct {
+	AddedFunctions   []CCLFunction `json:"added_functions,omitempty"`
+	RemovedFunctions []CCLFunction `json:"removed_functions,omitempty"`
+	AddedFeatures    []CCLFeature  `json:"added_features,omitempty"`
+	RemovedFeatures  []CCLFeature  `json:"removed_features,omitempty"`
+	AddedBehaviors   []CCLBehavior `json:"added_behaviors,omitempty"`
+	RemovedBehaviors []CCLBehavior `json:"removed_behaviors,omitempty"`
+	VariantBefore    CCLVariant    `json:"variant_before,omitempty"` // Set with VariantAfter when the variant choice changed
+	VariantAfter     CCLVariant    `json:"variant_after,omitempty"`
+}
+
+// Diff compares the functions, features, behaviors and variant two configs
+// choose. Names, versions and explicit exclusions are not compared.
+func Diff(before, after ImplementationConfig) ConfigDiff {
+	var diff ConfigDiff
+	diff.AddedFunctions, diff.RemovedFunctions = diffLists(before.SupportedFunctions, after.SupportedFunctions)
+	diff.AddedFeatures, diff.RemovedFeatures = diffLists(before.SupportedFeatures, after.SupportedFeatures)
+	diff.AddedBehaviors, diff.RemovedBehaviors = diffLists(before.BehaviorChoices, after.BehaviorChoices)
+	if before.VariantChoice != after.VariantChoice {
+		diff.VariantBefore, diff.VariantAfter = before.VariantChoice, after.VariantChoice
+	}
+	return diff
+}
+
+// Empty reports whether the configs choose the same capabilities
+func (d ConfigDiff) Empty() bool {
+	return len(d.AddedFunctions)+len(d.RemovedFunctions)+len(d.AddedFeatures)+len(d.RemovedFeatures)+
+		len(d.AddedBehaviors)+len(d.RemovedBehaviors) == 0 && d.VariantBefore == d.VariantAfter
+}
+
+// String lists the changes one per line, "+" for added and "-" for removed
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	writeDiffLines(&b, "function", d.AddedFunctions, d.RemovedFunctions)
+	writeDiffLines(&b, "feature", d.AddedFeatures, d.RemovedFeatures)
+	writeDiffLines(&b, "behavior", d.AddedBehaviors, d.RemovedBehaviors)
+	if d.VariantBefore != d.VariantAfter {
+		fmt.Fprintf(&b, "~ variant %s -> %s\n", d.VariantBefore, d.VariantAfter)
+	}
+	return b.String()
+}
+
+func writeDiffLines[T ~string](b *strings.Builder, kind string, added, removed []T) {
+	for _, name := range added {
+		fmt.Fprintf(b, "+ %s %s\n", kind, name)
+	}
+	for _, name := range removed {
+		fmt.Fprintf(b, "- %s %s\n", kind, name)
+	}
+}
+
+// diffLists returns the sorted values only in after and only in before
+func diffLists[T ~string](before, after []T) (added, removed []T) {
+	for _, value := range after {
+		if !slices.Contains(before, value) && !slices.Contains(added, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range before {
+		if !slices.Contains(after, value) && !slices.Contains(removed, value) {
+			removed = append(removed, value)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := ImplementationConfig{
+		SupportedFunctions: []CCLFunction{FunctionParse, FunctionGetString},
+		SupportedFeatures:  []CCLFeature{FeatureComments},
+		BehaviorChoices:    []CCLBehavior{BehaviorCRLFPreserve, BehaviorTabsAsContent},
+		VariantChoice:      VariantProposed,
+	}
+	after := ImplementationConfig{
+		SupportedFunctions: []CCLFunction{FunctionParse, FunctionGetString, FunctionGetFloat, FunctionGetBool},
+		BehaviorChoices:    []CCLBehavior{BehaviorTabsAsContent, BehaviorCRLFNormalize},
+		VariantChoice:      VariantReference,
+	}
+
+	diff := Diff(before, after)
+	want := ConfigDiff{
+		AddedFunctions:   []CCLFunction{FunctionGetBool, FunctionGetFloat},
+		RemovedFeatures:  []CCLFeature{FeatureComments},
+		AddedBehaviors:   []CCLBehavior{BehaviorCRLFNormalize},
+		RemovedBehaviors: []CCLBehavior{BehaviorCRLFPreserve},
+		VariantBefore:    VariantProposed,
+		VariantAfter:     VariantReference,
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() = %+v, want %+v", diff, want)
+	}
+	golden := `+ function get_bool
++ function get_float
+- feature comments
++ behavior crlf_normalize_to_lf
+- behavior crlf_preserve_literal
+~ variant proposed_behavior -> reference_compliant
+`
+	if diff.String() != golden {
+		t.Errorf("String() mismatch.\nGot:\n%s\nWant:\n%s", diff.String(), golden)
+	}
+	if diff.Empty() || !Diff(before, before).Empty() {
+		t.Error("Expected only identical configs to have an empty diff")
+	}
+}
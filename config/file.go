@@ -0,0 +1,46 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// LoadFile reads an implementation configuration from a JSON file (e.g. ccl-impl.json)
+func LoadFile(path string) (ImplementationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImplementationConfig{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg ImplementationConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ImplementationConfig{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := cfg.IsValid(); err != nil {
+		return ImplementationConfig{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Fingerprint identifies the capabilities a config declares, so results and
+// bug reports can be traced to the config they were produced with. The
+// order of the capability lists does not matter.
+func (c ImplementationConfig) Fingerprint() string {
+	c.SupportedFunctions = slices.Clone(c.SupportedFunctions)
+	slices.Sort(c.SupportedFunctions)
+	c.SupportedFeatures = slices.Clone(c.SupportedFeatures)
+	slices.Sort(c.SupportedFeatures)
+	c.BehaviorChoices = slices.Clone(c.BehaviorChoices)
+	slices.Sort(c.BehaviorChoices)
+	c.UnsupportedFeatures = slices.Clone(c.UnsupportedFeatures)
+	slices.Sort(c.UnsupportedFeatures)
+	c.UnsupportedFunctions = slices.Clone(c.UnsupportedFunctions)
+	slices.Sort(c.UnsupportedFunctions)
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
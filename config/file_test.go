@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ccl-impl.json")
+	data := `{
+  "name": "example-ccl",
+  "version": "v1.2.0",
+  "supported_functions": ["parse", "get_string"],
+  "supported_features": ["comments"],
+  "behavior_choices": ["boolean_strict"],
+  "variant_choice": "reference_compliant"
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if cfg.Name != "example-ccl" || !cfg.HasFunction(FunctionGetString) || !cfg.HasFeature(FeatureComments) {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if !cfg.HasBehavior(BehaviorBooleanStrict) || cfg.VariantChoice != VariantReference {
+		t.Errorf("Unexpected behavior or variant: %+v", cfg)
+	}
+}
+
+func TestLoadFile_RejectsConflictingBehaviors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ccl-impl.json")
+	data := `{"name": "bad", "behavior_choices": ["boolean_strict", "boolean_lenient"]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	_, err := LoadFile(path)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Errorf("Expected ConfigError, got %v", err)
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestImplementationConfig_Fingerprint(t *testing.T) {
+	a := ImplementationConfig{
+		Name:               "impl",
+		SupportedFunctions: []CCLFunction{FunctionParse, FunctionGetString},
+		BehaviorChoices:    []CCLBehavior{BehaviorBooleanStrict, BehaviorCRLFNormalize},
+	}
+	b := a
+	b.SupportedFunctions = []CCLFunction{FunctionGetString, FunctionParse}
+	b.BehaviorChoices = []CCLBehavior{BehaviorCRLFNormalize, BehaviorBooleanStrict}
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("Expected the fingerprint to ignore capability order")
+	}
+	if a.SupportedFunctions[0] != FunctionParse {
+		t.Error("Fingerprint must not reorder the config's lists")
+	}
+
+	b.SupportedFeatures = []CCLFeature{FeatureComments}
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("Expected different capabilities to change the fingerprint")
+	}
+}
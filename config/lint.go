@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BehaviorGroup returns the conflict group a behavior belongs to
+func BehaviorGroup(behavior CCLBehavior) (string, bool) {
+	for group, behaviors := range GetBehaviorConflicts() {
+		for _, b := range behaviors {
+			if b == behavior {
+				return group, true
+			}
+		}
+	}
+	return "", false
+}
+
+// UndecidedGroups returns the behavior conflict groups the config chooses no
+// behavior from, sorted by name. Tests requiring any behavior in such a
+// group are incompatible, which is rarely intended.
+func (c ImplementationConfig) UndecidedGroups() []string {
+	var undecided []string
+	for group, behaviors := range GetBehaviorConflicts() {
+		decided := false
+		for _, behavior := range behaviors {
+			decided = decided || c.HasBehavior(behavior)
+		}
+		if !decided {
+			undecided = append(undecided, group)
+		}
+	}
+	sort.Strings(undecided)
+	return undecided
+}
+
+// Lint returns warnings about a valid config that is probably incomplete
+func (c ImplementationConfig) Lint() []string {
+	var warnings []string
+	for _, group := range c.UndecidedGroups() {
+		var choices []string
+		for _, behavior := range GetBehaviorConflicts()[group] {
+			choices = append(choices, string(behavior))
+		}
+		warnings = append(warnings, fmt.Sprintf("no %s behavior chosen (%s); tests requiring either are incompatible", group, strings.Join(choices, " or ")))
+	}
+	return warnings
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImplementationConfig_UndecidedGroups(t *testing.T) {
+	cfg := ImplementationConfig{BehaviorChoices: []CCLBehavior{
+		BehaviorTabsAsContent, BehaviorIndentSpaces, BehaviorBooleanStrict, BehaviorListCoercionOff,
+	}}
+
+	if got := cfg.UndecidedGroups(); !reflect.DeepEqual(got, []string{"crlf_handling"}) {
+		t.Errorf("Expected only crlf_handling undecided, got %v", got)
+	}
+	warnings := cfg.Lint()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "crlf_normalize_to_lf or crlf_preserve_literal") {
+		t.Errorf("Expected one crlf_handling warning, got %v", warnings)
+	}
+
+	cfg.BehaviorChoices = append(cfg.BehaviorChoices, BehaviorCRLFPreserve)
+	if got := cfg.Lint(); len(got) != 0 {
+		t.Errorf("Expected no warnings once every group is decided, got %v", got)
+	}
+}
+
+func TestBehaviorGroup(t *testing.T) {
+	if group, ok := BehaviorGroup(BehaviorBooleanLenient); !ok || group != "boolean" {
+		t.Errorf("Expected boolean, got %q, %v", group, ok)
+	}
+	if _, ok := BehaviorGroup("telepathy"); ok {
+		t.Error("Expected no group for an unknown behavior")
+	}
+}
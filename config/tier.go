@@ -0,0 +1,68 @@
+package config
+
+import "fmt"
+
+// Tier is a conformance level. Each tier adds its Functions and Features to
+// those of the tiers below it.
+type Tier struct {
+	Level     int           `json:"level"` // 1 and up; 0 means no tier
+	Summary   string        `json:"summary"`
+	Functions []CCLFunction `json:"functions"`
+	Features  []CCLFeature  `json:"features"`
+}
+
+// Name labels the tier, e.g. "Tier 2", or "No tier" for the zero Tier
+func (t Tier) Name() string {
+	if t.Level == 0 {
+		return "No tier"
+	}
+	return fmt.Sprintf("Tier %d", t.Level)
+}
+
+// TierRequirements returns the tiers in ascending order. Each lists only
+// what it adds to the tier below.
+func TierRequirements() []Tier {
+	return []Tier{
+		{
+			Level:     1,
+			Summary:   "parsing and hierarchy",
+			Functions: []CCLFunction{FunctionParse, FunctionBuildHierarchy},
+		},
+		{
+			Level:     2,
+			Summary:   "typed access",
+			Functions: []CCLFunction{FunctionGetString, FunctionGetInt, FunctionGetBool, FunctionGetFloat, FunctionGetList},
+		},
+		{
+			Level:    3,
+			Summary:  "language features",
+			Features: []CCLFeature{FeatureComments, FeatureMultiline, FeatureUnicode, FeatureWhitespace},
+		},
+	}
+}
+
+// HighestTier returns the highest tier whose requirements and those of
+// every lower tier all hold, or the zero Tier if tier 1's do not
+func HighestTier(hasFunction func(CCLFunction) bool, hasFeature func(CCLFeature) bool) Tier {
+	var achieved Tier
+	for _, tier := range TierRequirements() {
+		for _, fn := range tier.Functions {
+			if !hasFunction(fn) {
+				return achieved
+			}
+		}
+		for _, feature := range tier.Features {
+			if !hasFeature(feature) {
+				return achieved
+			}
+		}
+		achieved = tier
+	}
+	return achieved
+}
+
+// ComputeTier returns the tier cfg declares support for. It says nothing
+// about whether the tests pass; see report.ComputeAchievedTier for that.
+func ComputeTier(cfg ImplementationConfig) Tier {
+	return HighestTier(cfg.HasFunction, cfg.HasFeature)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestComputeTier_Boundaries(t *testing.T) {
+	tier1 := []CCLFunction{FunctionParse, FunctionBuildHierarchy}
+	tier2 := append(tier1, FunctionGetString, FunctionGetInt, FunctionGetBool, FunctionGetFloat, FunctionGetList)
+	tier3Features := []CCLFeature{FeatureComments, FeatureMultiline, FeatureUnicode, FeatureWhitespace}
+
+	tests := []struct {
+		name      string
+		functions []CCLFunction
+		features  []CCLFeature
+		want      int
+	}{
+		{"nothing", nil, nil, 0},
+		{"parse only", []CCLFunction{FunctionParse}, nil, 0},
+		{"tier 1 exactly", tier1, nil, 1},
+		{"tier 2 less one function", tier2[:len(tier2)-1], nil, 1},
+		{"tier 2 exactly", tier2, nil, 2},
+		{"tier 3 less one feature", tier2, tier3Features[:3], 2},
+		{"tier 3 exactly", tier2, tier3Features, 3},
+		{"tier 3 features without tier 2", tier1, tier3Features, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ImplementationConfig{SupportedFunctions: tt.functions, SupportedFeatures: tt.features}
+			if got := ComputeTier(cfg); got.Level != tt.want {
+				t.Errorf("Expected tier %d, got %d", tt.want, got.Level)
+			}
+		})
+	}
+}
+
+func TestTierRequirements(t *testing.T) {
+	for i, tier := range TierRequirements() {
+		if tier.Level != i+1 || tier.Summary == "" {
+			t.Errorf("Unexpected tier at %d: %+v", i, tier)
+		}
+		for _, fn := range tier.Functions {
+			if !slices.Contains(AllFunctions(), fn) {
+				t.Errorf("%s requires unknown function %s", tier.Name(), fn)
+			}
+		}
+	}
+	if (Tier{}).Name() != "No tier" || (Tier{Level: 2}).Name() != "Tier 2" {
+		t.Error("Unexpected tier names")
+	}
+}
@@ -0,0 +1,37 @@
+package ccl_test_lib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// ErrNoTestData is returned (wrapped) by the convenience functions when the
+// test data directory does not exist. It also matches loader.ErrNoTests and
+// fs.ErrNotExist.
+var ErrNoTestData = errors.New("test data not found")
+
+// loadTests loads tests with o.load, reporting a missing test directory as
+// ErrNoTestData. With WithAllowEmpty, missing or empty test data yields no
+// tests instead of an error.
+func loadTests(testLoader *loader.TestLoader, o options) ([]types.TestCase, error) {
+	tests, err := testLoader.LoadAllTests(o.load)
+	if errors.Is(err, loader.ErrNoTests) && o.allowEmpty {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, noTestData(err)
+	}
+	return tests, nil
+}
+
+// noTestData wraps err in ErrNoTestData if it reports a missing directory
+func noTestData(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrNoTestData, err)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package ccl_test_lib
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	empty := t.TempDir()
+	os.MkdirAll(filepath.Join(empty, "generated_tests"), 0755)
+	invalid := t.TempDir()
+	os.MkdirAll(filepath.Join(invalid, "generated_tests"), 0755)
+	os.WriteFile(filepath.Join(invalid, "generated_tests", "bad.json"), []byte(`{"tests": [`), 0644)
+	cfg := createTestImplementationConfig()
+
+	calls := map[string]func(dir string) error{
+		"LoadCompatibleTests": func(dir string) error {
+			_, err := LoadCompatibleTests(dir, cfg)
+			return err
+		},
+		"GetTestStats": func(dir string) error {
+			_, err := GetTestStats(dir, cfg)
+			return err
+		},
+		"ExportCompatibleTests": func(dir string) error {
+			_, err := ExportCompatibleTests(dir, cfg, t.TempDir())
+			return err
+		},
+		"ExportFuzzCorpus": func(dir string) error {
+			_, err := ExportFuzzCorpus(dir, t.TempDir(), cfg)
+			return err
+		},
+		"RunMatrix": func(dir string) error {
+			_, err := RunMatrix(dir, nil, nil)
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(missing); !errors.Is(err, ErrNoTestData) {
+				t.Errorf("Expected ErrNoTestData for missing data, got %v", err)
+			}
+			if err := call(empty); !errors.Is(err, loader.ErrNoTests) || errors.Is(err, ErrNoTestData) {
+				t.Errorf("Expected ErrNoTests for empty data, got %v", err)
+			}
+			if err := call(invalid); !errors.Is(err, loader.ErrParse) {
+				t.Errorf("Expected ErrParse for invalid data, got %v", err)
+			}
+		})
+	}
+}
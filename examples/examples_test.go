@@ -0,0 +1,111 @@
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+)
+
+// exampleData is a small ccl-test-data checkout: two tests the toy
+// implementation runs and two it cannot
+func exampleData(t *testing.T) *testsupport.Fixture {
+	return testsupport.NewFixture(t).
+		WithCompactFile("api_basic.json", loader.CompactTest{
+			Name:   "basic",
+			Inputs: []string{"name = toy\nport = 8080"},
+			Tests: []loader.CompactValidation{
+				{Function: "parse", Expect: []map[string]interface{}{{"key": "name", "value": "toy"}, {"key": "port", "value": "8080"}}},
+				{Function: "get_int", Args: []string{"port"}, Expect: 8080},
+			},
+		}, loader.CompactTest{
+			Name:      "crlf_kept",
+			Inputs:    []string{"a = b\r\n"},
+			Behaviors: []string{"crlf_preserve_literal"},
+			Tests: []loader.CompactValidation{
+				{Function: "parse", Expect: []map[string]interface{}{{"key": "a", "value": "b\r"}}},
+			},
+		}).
+		WithCompactFile("api_comments.json", loader.CompactTest{
+			Name:     "comment_filter",
+			Inputs:   []string{"/= note\na = b"},
+			Features: []string{"comments"},
+			Tests: []loader.CompactValidation{
+				{Function: "filter", Expect: []map[string]interface{}{{"key": "a", "value": "b"}}},
+			},
+		}, loader.CompactTest{
+			Name:   "dotted",
+			Inputs: []string{"a.b = c"},
+			Tests: []loader.CompactValidation{
+				{Function: "expand_dotted", Expect: []map[string]interface{}{{"key": "a", "value": "b = c"}}},
+			},
+		})
+}
+
+func TestTestDataProjectUsage(t *testing.T) {
+	root := exampleData(t).Build()
+
+	var out strings.Builder
+	if err := TestDataProjectUsage(&out, root); err != nil {
+		t.Fatalf("TestDataProjectUsage failed: %v", err)
+	}
+	golden := `Generated 3 flat tests from api_basic.json
+Generated 2 flat tests from api_comments.json
+
+| Metric | Count |
+|---|---:|
+| Total tests | 5 |
+| Compatible tests | 4 |
+
+| Function | Tests |
+|---|---:|
+| expand_dotted | 1 |
+| filter | 1 |
+| get_int | 1 |
+| parse | 2 |
+
+| Feature | Tests |
+|---|---:|
+| comments | 1 |
+| experimental_dotted_keys | 1 |
+
+| Level | Tests |
+|---|---:|
+| 2 | 2 |
+| 3 | 2 |
+| 4 | 1 |
+
+| Behavior or variant | Rejection | Tests |
+|---|---|---:|
+| crlf_preserve_literal | requirement | 1 |
+
+| Capability | Kind | Available | Compatible |
+|---|---|---:|---:|
+| build_hierarchy | function | 0 | 0 |
+| parse | function | 2 | 1 |
+`
+	if out.String() != golden {
+		t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", out.String(), golden)
+	}
+}
+
+func TestImplementationUsage(t *testing.T) {
+	root := exampleData(t).WithGenerated().Build()
+
+	var out strings.Builder
+	if err := ImplementationUsage(&out, root, toyccl.Config(), toyccl.New()); err != nil {
+		t.Fatalf("ImplementationUsage failed: %v\n%s", err, out.String())
+	}
+	golden := `Loaded 3 compatible tests
+Loaded 1 parse tests
+Cannot run crlf_kept_parse: requires behavior crlf_preserve_literal
+Cannot run dotted_expand_dotted: unsupported function expand_dotted
+
+3 passed, 0 failed, 0 not run
+`
+	if out.String() != golden {
+		t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", out.String(), golden)
+	}
+}
@@ -0,0 +1,61 @@
+// Package examples walks through the library the way its two kinds of users
+// do: an implementation running the shared tests (ImplementationUsage) and
+// the ccl-test-data project maintaining them (TestDataProjectUsage). Each
+// walkthrough writes what it finds to w. The package tests run them against
+// a testsupport fixture and the toy implementation and compare the output,
+// so an example that drifts from the API fails go test.
+package examples
+
+import (
+	"fmt"
+	"io"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// ImplementationUsage loads the generated tests in testDataPath that cfg
+// can run, runs them against impl, and reports the results
+func ImplementationUsage(w io.Writer, testDataPath string, cfg config.ImplementationConfig, impl runner.CCLImplementation) error {
+	// Behavior choices are mutually exclusive within their groups
+	if err := cfg.IsValid(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// The common case: every test the implementation can run
+	tests, err := ccl.LoadCompatibleTests(testDataPath, cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Loaded %d compatible tests\n", len(tests))
+
+	// Tests of one function, through the loader directly
+	parseTests, err := ccl.NewLoader(testDataPath, cfg).LoadTestsByFunction(config.FunctionParse, loader.LoadOptions{
+		Format:     loader.FormatFlat,
+		FilterMode: loader.FilterCompatible,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Loaded %d parse tests\n", len(parseTests))
+
+	// What the implementation cannot run, and why
+	incompatible, err := ccl.GetIncompatibleTests(testDataPath, cfg)
+	if err != nil {
+		return err
+	}
+	for _, test := range incompatible {
+		fmt.Fprintf(w, "Cannot run %s: %s\n", test.Test.Name, test.Reason)
+	}
+
+	// Run the compatible tests and summarize
+	results := runner.Run(impl, tests, runner.RunOptions{})
+	fmt.Fprintf(w, "\n%s", report.ResultsMarkdown(results))
+	if len(results.Failures()) > 0 {
+		return fmt.Errorf("%d tests failed", len(results.Failures()))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package examples
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	ccl "github.com/CatConfLang/ccl-test-lib"
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+)
+
+// TestDataProjectUsage generates testDataPath's flat tests from its source
+// tests, then reports statistics over all of them and the coverage a
+// minimal implementation would get
+func TestDataProjectUsage(w io.Writer, testDataPath string) error {
+	sourceDir := filepath.Join(testDataPath, loader.DefaultSourceDir)
+	generatedDir := filepath.Join(testDataPath, loader.DefaultGeneratedDir)
+
+	// Generate flat tests, leaving out property tests
+	gen := ccl.NewGenerator(sourceDir, generatedDir, ccl.WithGenerateOptions(generator.GenerateOptions{
+		SourceFormat:      generator.FormatCompact,
+		SkipPropertyTests: true,
+	}))
+	if err := gen.GenerateAll(); err != nil {
+		return err
+	}
+	for _, file := range gen.Report().Files {
+		fmt.Fprintf(w, "Generated %d flat tests from %s\n", file.Tests, filepath.Base(file.Source))
+	}
+
+	// Statistics over every test, with a config supporting everything
+	everything := config.ImplementationConfig{
+		Name:               "analysis",
+		SupportedFunctions: config.AllFunctions(),
+		SupportedFeatures:  config.AllFeatures(),
+		BehaviorChoices:    []config.CCLBehavior{config.BehaviorCRLFNormalize, config.BehaviorBooleanLenient},
+		VariantChoice:      config.VariantProposed,
+	}
+	stats, err := ccl.GetTestStats(testDataPath, everything, ccl.WithFilterMode(loader.FilterAll))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n%s", report.StatisticsMarkdown(stats))
+
+	// Coverage for an implementation of parse and build_hierarchy only
+	minimal := config.ImplementationConfig{
+		Name:               "minimal",
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionBuildHierarchy},
+		BehaviorChoices:    []config.CCLBehavior{config.BehaviorCRLFNormalize},
+		VariantChoice:      config.VariantReference,
+	}
+	coverage := ccl.NewLoader(testDataPath, minimal).GetCapabilityCoverage()
+	fmt.Fprintf(w, "\n%s", report.CoverageMarkdown(coverage))
+	return nil
+}
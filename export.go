@@ -0,0 +1,330 @@
+package ccl_test_lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// ExportManifestFile is written alongside the exported test files
+const ExportManifestFile = "manifest.json"
+
+// ExportCapabilitiesFile summarizes what the exported tests exercise, as a
+// loader.CapabilitySummary
+const ExportCapabilitiesFile = "capabilities.json"
+
+// ExportNamesFile maps hashed test names back to the originals when an
+// export was sanitized with SanitizePolicy.HashNames. Keep it out of
+// anything shared.
+const ExportNamesFile = "names.json"
+
+// ExportManifest records what an export contains and what it was built from
+type ExportManifest struct {
+	LibraryVersion    string         `json:"library_version"`
+	Implementation    string         `json:"implementation"`
+	SourceFingerprint string         `json:"source_fingerprint"` // Config and generated_tests the export was built from
+	Fingerprint       string         `json:"fingerprint"`        // Exported test files
+	Tests             int            `json:"tests"`
+	Files             map[string]int `json:"files"`                 // File name -> test count
+	Sanitized         bool           `json:"sanitized,omitempty"`   // Tests were rewritten by types.Sanitize
+	ShardIndex        int            `json:"shard_index,omitempty"` // Shard exported, from 0 (see WithShard)
+	ShardCount        int            `json:"shard_count,omitempty"` // Shards the tests were split into; 0 if not sharded
+	SampleSize        int            `json:"sample_size,omitempty"` // Size of the stratified sample exported (see WithSample); 0 if not sampled
+	SampleSeed        int64          `json:"sample_seed,omitempty"` // Seed of the sample
+}
+
+// ExportCompatibleTests writes the flat tests in testDataPath/generated_tests
+// that are compatible with cfg to outDir, one file per validation function,
+// plus a manifest and ExportCapabilitiesFile. Tests are copied unchanged, so
+// loading the export yields the same tests as LoadCompatibleTests. Files from
+// a previous export that are no longer needed are removed. It returns ErrNoTestData if
+// generated_tests is missing, loader.ErrNoTests if it is empty, and
+// loader.ErrParse if a file is invalid.
+//
+// With WithSanitize the tests are instead rewritten by types.Sanitize, and
+// hashed names are mapped back in ExportNamesFile. With WithShard only that
+// shard's tests are exported, and with WithSample only a sample of them.
+func ExportCompatibleTests(testDataPath string, cfg config.ImplementationConfig, outDir string, opts ...Option) (*ExportManifest, error) {
+	o := applyOptions(defaultOptions(), opts)
+	if err := loader.CheckShard(o.load.ShardIndex, o.load.ShardCount); err != nil {
+		return nil, err
+	}
+	sourceFingerprint, err := ExportSourceFingerprint(testDataPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	byFunction, err := compatibleRawTests(testDataPath, cfg, o.load)
+	if err != nil {
+		return nil, err
+	}
+	var names types.NameMapping
+	if o.sanitize != nil {
+		if names, err = sanitizeRawTests(byFunction, *o.sanitize); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := ReadExportManifest(outDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	manifest := &ExportManifest{
+		LibraryVersion:    Version,
+		Implementation:    cfg.Name,
+		SourceFingerprint: sourceFingerprint,
+		Files:             make(map[string]int),
+		Sanitized:         o.sanitize != nil,
+		ShardIndex:        o.load.ShardIndex,
+		ShardCount:        o.load.ShardCount,
+		SampleSize:        o.load.SampleSize,
+		SampleSeed:        o.load.SampleSeed,
+	}
+	functions := make([]string, 0, len(byFunction))
+	for fn := range byFunction {
+		functions = append(functions, fn)
+	}
+	sort.Strings(functions)
+
+	var exported []types.TestCase
+	hash := sha256.New()
+	for _, fn := range functions {
+		name := fn + ".json"
+		data, err := json.MarshalIndent(struct {
+			Tests []json.RawMessage `json:"tests"`
+		}{byFunction[fn]}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		data = append(data, '\n')
+		if err := os.WriteFile(filepath.Join(outDir, name), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		hash.Write([]byte(name + "\x00"))
+		hash.Write(data)
+		manifest.Files[name] = len(byFunction[fn])
+		manifest.Tests += len(byFunction[fn])
+		for _, raw := range byFunction[fn] {
+			var test types.TestCase
+			if err := json.Unmarshal(raw, &test); err != nil {
+				return nil, fmt.Errorf("%w: failed to parse exported test: %w", loader.ErrParse, err)
+			}
+			exported = append(exported, test)
+		}
+	}
+	manifest.Fingerprint = hex.EncodeToString(hash.Sum(nil))
+
+	capabilities, err := json.MarshalIndent(loader.SummarizeCapabilities(exported), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal capabilities: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, ExportCapabilitiesFile), append(capabilities, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write capabilities: %w", err)
+	}
+
+	// Prune only files this library wrote, never unrelated vendored files
+	if previous != nil {
+		for name := range previous.Files {
+			if _, ok := manifest.Files[name]; ok {
+				continue
+			}
+			if err := os.Remove(filepath.Join(outDir, name)); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to prune %s: %w", name, err)
+			}
+		}
+	}
+	namesPath := filepath.Join(outDir, ExportNamesFile)
+	if len(names) > 0 {
+		data, err := json.MarshalIndent(names, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal names: %w", err)
+		}
+		if err := os.WriteFile(namesPath, append(data, '\n'), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write names: %w", err)
+		}
+	} else if previous != nil && previous.Sanitized {
+		if err := os.Remove(namesPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to prune %s: %w", ExportNamesFile, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, ExportManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadExportManifest reads the manifest of a previous export
+func ReadExportManifest(outDir string) (*ExportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(outDir, ExportManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ExportManifestFile, err)
+	}
+	return &manifest, nil
+}
+
+// ReadExportNames reads the name mapping of a sanitized export
+func ReadExportNames(outDir string) (types.NameMapping, error) {
+	data, err := os.ReadFile(filepath.Join(outDir, ExportNamesFile))
+	if err != nil {
+		return nil, err
+	}
+	var names types.NameMapping
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ExportNamesFile, err)
+	}
+	return names, nil
+}
+
+// ExportSourceFingerprint hashes the config and generated tests an export
+// depends on; an export is stale when its manifest's SourceFingerprint differs
+func ExportSourceFingerprint(testDataPath string, cfg config.ImplementationConfig) (string, error) {
+	files, err := generatedTestFiles(testDataPath)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	configData, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	hash.Write(configData)
+	if err := hashFiles(hash, files); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFiles writes the name and contents of each file to hash
+func hashFiles(hash io.Writer, files []string) error {
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		hash.Write([]byte("\x00" + filepath.Base(file) + "\x00"))
+		hash.Write(data)
+	}
+	return nil
+}
+
+// generatedTestFiles lists testDataPath/generated_tests/*.json, sorted
+func generatedTestFiles(testDataPath string) ([]string, error) {
+	dir := testLayout(testDataPath).Dir(loader.FormatFlat)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoTestData, err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", loader.ErrNoTests, dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// compatibleRawTests returns the unmodified JSON of each compatible flat
+// test in the shard and sample opts select, grouped by validation function
+func compatibleRawTests(testDataPath string, cfg config.ImplementationConfig, opts loader.LoadOptions) (map[string][]json.RawMessage, error) {
+	files, err := generatedTestFiles(testDataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	testLoader := NewLoader(testDataPath, cfg)
+	var raws []json.RawMessage
+	var tests []types.TestCase
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		var suite struct {
+			Tests []json.RawMessage `json:"tests"`
+		}
+		if err := json.Unmarshal(data, &suite); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s: %w", loader.ErrParse, filepath.Base(file), err)
+		}
+		for _, raw := range suite.Tests {
+			var test types.TestCase
+			if err := json.Unmarshal(raw, &test); err != nil {
+				return nil, fmt.Errorf("%w: failed to parse %s: %w", loader.ErrParse, filepath.Base(file), err)
+			}
+			// Decode the expected value as the loader does, so the test's ID,
+			// and with it its shard and sample, match the loaded test's
+			if tolerance := types.ExtractTolerance(test.Expected); tolerance != 0 {
+				test.Tolerance = tolerance
+			}
+			test.Expected = types.ExtractExpected(test.Validation, test.Expected)
+			if opts.ShardCount > 1 && loader.ShardOf(test, opts.ShardCount) != opts.ShardIndex {
+				continue
+			}
+			if testLoader.IsTestCompatible(test) {
+				raws = append(raws, raw)
+				tests = append(tests, test)
+			}
+		}
+	}
+
+	byFunction := make(map[string][]json.RawMessage)
+	size := opts.SampleSize
+	if size == 0 {
+		size = len(tests) // Every test
+	}
+	for _, i := range loader.SampleIndices(tests, size, opts.SampleSeed, nil) {
+		byFunction[tests[i].Validation] = append(byFunction[tests[i].Validation], raws[i])
+	}
+	return byFunction, nil
+}
+
+// sanitizeRawTests replaces each test in byFunction with its sanitized form
+// and returns the name mapping
+func sanitizeRawTests(byFunction map[string][]json.RawMessage, policy types.SanitizePolicy) (types.NameMapping, error) {
+	names := make(types.NameMapping)
+	for _, raws := range byFunction {
+		tests := make([]types.TestCase, len(raws))
+		for i, raw := range raws {
+			if err := json.Unmarshal(raw, &tests[i]); err != nil {
+				return nil, fmt.Errorf("%w: %w", loader.ErrParse, err)
+			}
+		}
+		sanitized, mapping, err := types.Sanitize(tests, policy)
+		if err != nil {
+			return nil, err
+		}
+		for hashed, name := range mapping {
+			names[hashed] = name
+		}
+		for i, test := range sanitized {
+			if raws[i], err = json.Marshal(test); err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", test.Name, err)
+			}
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,266 @@
+package ccl_test_lib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// setupExportTestData generates flat tests from the integration sources
+func setupExportTestData(t *testing.T) string {
+	t.Helper()
+	testDataPath := setupIntegrationTestData(t)
+	if err := GenerateFlat(filepath.Join(testDataPath, testsupport.SourceDir), filepath.Join(testDataPath, "generated_tests")); err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	return testDataPath
+}
+
+func exportConfig(functions ...config.CCLFunction) config.ImplementationConfig {
+	return config.ImplementationConfig{
+		Name:               "export-test",
+		Version:            "v1.0.0",
+		SupportedFunctions: functions,
+		SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+	}
+}
+
+func sortTests(tests []types.TestCase) {
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Name < tests[j].Name })
+}
+
+func TestExportCompatibleTests_MatchesLoadCompatibleTests(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	cfg := exportConfig(config.FunctionParse, config.FunctionGetString, config.FunctionGetInt)
+	outDir := filepath.Join(t.TempDir(), "vendor")
+
+	manifest, err := ExportCompatibleTests(testDataPath, cfg, outDir)
+	if err != nil {
+		t.Fatalf("ExportCompatibleTests failed: %v", err)
+	}
+
+	want, err := LoadCompatibleTests(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	var got []types.TestCase
+	testLoader := NewLoader(outDir, cfg)
+	for name := range manifest.Files {
+		suite, err := testLoader.LoadTestFile(filepath.Join(outDir, name), loader.LoadOptions{Format: loader.FormatFlat})
+		if err != nil {
+			t.Fatalf("Failed to load exported %s: %v", name, err)
+		}
+		for _, test := range suite.Tests {
+			if name != test.Validation+".json" {
+				t.Errorf("Test %s exported to %s", test.Name, name)
+			}
+		}
+		got = append(got, suite.Tests...)
+	}
+	sortTests(got)
+	sortTests(want)
+
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Exported tests differ from LoadCompatibleTests.\nGot:  %+v\nWant: %+v", got, want)
+	}
+	if manifest.Tests != len(want) || manifest.Implementation != "export-test" || manifest.Fingerprint == "" {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	written, err := ReadExportManifest(outDir)
+	if err != nil || !reflect.DeepEqual(written, manifest) {
+		t.Errorf("Manifest on disk differs: %+v, %v", written, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, ExportCapabilitiesFile))
+	if err != nil {
+		t.Fatalf("Failed to read capabilities: %v", err)
+	}
+	var capabilities loader.CapabilitySummary
+	if err := json.Unmarshal(data, &capabilities); err != nil {
+		t.Fatalf("Failed to parse capabilities: %v", err)
+	}
+	if summary := loader.SummarizeCapabilities(got); !reflect.DeepEqual(capabilities, summary) {
+		t.Errorf("Capabilities differ from the exported tests.\nGot:  %+v\nWant: %+v", capabilities, summary)
+	}
+}
+
+func TestExportCompatibleTests_Shards(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	cfg := exportConfig(config.FunctionParse, config.FunctionGetString, config.FunctionGetInt)
+	all, err := LoadCompatibleTests(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+
+	total := 0
+	for index := 0; index < 2; index++ {
+		want, err := LoadCompatibleTests(testDataPath, cfg, WithShard(index, 2))
+		if err != nil {
+			t.Fatalf("LoadCompatibleTests for shard %d failed: %v", index, err)
+		}
+		manifest, err := ExportCompatibleTests(testDataPath, cfg, t.TempDir(), WithShard(index, 2))
+		if err != nil {
+			t.Fatalf("ExportCompatibleTests for shard %d failed: %v", index, err)
+		}
+		if manifest.Tests != len(want) || manifest.ShardIndex != index || manifest.ShardCount != 2 {
+			t.Errorf("Expected shard %d of 2 with %d tests, got %+v", index, len(want), manifest)
+		}
+		total += manifest.Tests
+	}
+	if total != len(all) {
+		t.Errorf("Expected the shards to hold all %d tests, got %d", len(all), total)
+	}
+
+	if _, err := ExportCompatibleTests(testDataPath, cfg, t.TempDir(), WithShard(2, 2)); err == nil {
+		t.Error("Expected an error for shard 2 of 2")
+	}
+}
+
+func TestExportCompatibleTests_Sample(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	cfg := exportConfig(config.FunctionParse, config.FunctionGetString, config.FunctionGetInt)
+
+	want, err := LoadCompatibleTests(testDataPath, cfg, WithSample(3, 5))
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	outDir := t.TempDir()
+	manifest, err := ExportCompatibleTests(testDataPath, cfg, outDir, WithSample(3, 5))
+	if err != nil {
+		t.Fatalf("ExportCompatibleTests failed: %v", err)
+	}
+	if len(want) != 3 || manifest.Tests != 3 || manifest.SampleSize != 3 || manifest.SampleSeed != 5 {
+		t.Fatalf("Expected a 3 test sample, loaded %d, manifest %+v", len(want), manifest)
+	}
+	var got []string
+	for name := range manifest.Files {
+		suite, err := NewLoader(outDir, cfg).LoadTestFile(filepath.Join(outDir, name), loader.LoadOptions{Format: loader.FormatFlat})
+		if err != nil {
+			t.Fatalf("Failed to load exported %s: %v", name, err)
+		}
+		for _, test := range suite.Tests {
+			got = append(got, test.Name)
+		}
+	}
+	sortTests(want)
+	sort.Strings(got)
+	for i, test := range want {
+		if got[i] != test.Name {
+			t.Errorf("Expected the export to hold the loaded sample %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestExportCompatibleTests_Prunes(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	outDir := t.TempDir()
+	unrelated := filepath.Join(outDir, "local.json")
+	if err := os.WriteFile(unrelated, []byte(`{"tests": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write unrelated file: %v", err)
+	}
+
+	first, err := ExportCompatibleTests(testDataPath, exportConfig(config.FunctionParse, config.FunctionGetInt), outDir)
+	if err != nil {
+		t.Fatalf("First export failed: %v", err)
+	}
+	if _, ok := first.Files["get_int.json"]; !ok {
+		t.Fatalf("Expected get_int.json in first export, got %v", first.Files)
+	}
+
+	second, err := ExportCompatibleTests(testDataPath, exportConfig(config.FunctionParse), outDir)
+	if err != nil {
+		t.Fatalf("Second export failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "get_int.json")); !os.IsNotExist(err) {
+		t.Error("Expected get_int.json pruned")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("Expected unrelated file kept: %v", err)
+	}
+	if first.SourceFingerprint == second.SourceFingerprint {
+		t.Error("Expected source fingerprint to change with the config")
+	}
+}
+
+func TestExportSourceFingerprint_DetectsStaleness(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	cfg := exportConfig(config.FunctionParse)
+
+	before, err := ExportSourceFingerprint(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("ExportSourceFingerprint failed: %v", err)
+	}
+	if again, _ := ExportSourceFingerprint(testDataPath, cfg); again != before {
+		t.Error("Expected a stable fingerprint")
+	}
+
+	extra := `{"tests": [{"name": "new_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 0}}]}`
+	if err := os.WriteFile(filepath.Join(testDataPath, "generated_tests", "new.json"), []byte(extra), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if after, _ := ExportSourceFingerprint(testDataPath, cfg); after == before {
+		t.Error("Expected fingerprint to change with the generated tests")
+	}
+}
+
+func TestExportCompatibleTests_Sanitize(t *testing.T) {
+	testDataPath := setupExportTestData(t)
+	cfg := exportConfig(config.FunctionParse, config.FunctionGetString, config.FunctionGetInt)
+	outDir := t.TempDir()
+
+	manifest, err := ExportCompatibleTests(testDataPath, cfg, outDir, WithSanitize(types.SanitizePolicy{HashNames: true}))
+	if err != nil {
+		t.Fatalf("ExportCompatibleTests failed: %v", err)
+	}
+	if !manifest.Sanitized {
+		t.Error("Expected the manifest to record sanitizing")
+	}
+	names, err := ReadExportNames(outDir)
+	if err != nil {
+		t.Fatalf("ReadExportNames failed: %v", err)
+	}
+
+	want, err := LoadCompatibleTests(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	var got []types.TestCase
+	testLoader := NewLoader(outDir, cfg)
+	for name := range manifest.Files {
+		suite, err := testLoader.LoadTestFile(filepath.Join(outDir, name), loader.LoadOptions{Format: loader.FormatFlat})
+		if err != nil {
+			t.Fatalf("Failed to load exported %s: %v", name, err)
+		}
+		for _, test := range suite.Tests {
+			if strings.HasPrefix(test.Name, "integration_") {
+				t.Errorf("Expected hashed names, got %s", test.Name)
+			}
+		}
+		got = append(got, suite.Tests...)
+	}
+	got = names.Restore(got)
+	sortTests(got)
+	sortTests(want)
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Restored tests differ from LoadCompatibleTests.\nGot:  %+v\nWant: %+v", got, want)
+	}
+
+	// An unsanitized export over it removes the stale mapping
+	if _, err := ExportCompatibleTests(testDataPath, cfg, outDir); err != nil {
+		t.Fatalf("ExportCompatibleTests failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, ExportNamesFile)); !os.IsNotExist(err) {
+		t.Errorf("Expected %s pruned, got %v", ExportNamesFile, err)
+	}
+}
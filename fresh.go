@@ -0,0 +1,34 @@
+package ccl_test_lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/generator"
+)
+
+// RequireFresh fails t, listing every stale file, unless outputDir holds
+// what GenerateFlat would write from sourceDir with the same options (see
+// generator.CheckStale). It lets a repository that commits its generated
+// tests check they were regenerated:
+//
+//	func TestGeneratedTestsFresh(t *testing.T) {
+//		ccl.RequireFresh(t, "source_tests", "generated_tests")
+//	}
+func RequireFresh(t testing.TB, sourceDir, outputDir string, opts ...Option) {
+	t.Helper()
+	o := applyOptions(defaultOptions(), opts)
+	stale, err := generator.CheckStale(sourceDir, outputDir, o.generate)
+	if err != nil {
+		t.Fatalf("failed to check %s: %v", outputDir, err)
+		return
+	}
+	if len(stale) == 0 {
+		return
+	}
+	lines := make([]string, len(stale))
+	for i, file := range stale {
+		lines[i] = "  " + file.String()
+	}
+	t.Fatalf("%s is stale; regenerate it from %s:\n%s", outputDir, sourceDir, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,50 @@
+package ccl_test_lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingTB records Fatalf instead of stopping the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRequireFresh(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	outputDir := filepath.Join(root, "generated_tests")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatalf("Failed to create source directory: %v", err)
+	}
+	source := `{"tests": [{"name": "basic", "inputs": ["key = value"], "tests": [{"function": "parse", "expect": [{"key": "key", "value": "value"}]}]}]}`
+	for _, name := range []string{"api-basic.json", "api-other.json"} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(source), 0644); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+	}
+	if err := GenerateFlat(sourceDir, outputDir); err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+
+	RequireFresh(t, sourceDir, outputDir)
+
+	if err := os.Remove(filepath.Join(outputDir, "api-other.json")); err != nil {
+		t.Fatalf("Failed to remove output: %v", err)
+	}
+	rec := &recordingTB{TB: t}
+	RequireFresh(rec, sourceDir, outputDir)
+	if len(rec.failures) != 1 || !strings.Contains(rec.failures[0], "api-other.json: missing") {
+		t.Errorf("Expected one failure listing api-other.json, got %q", rec.failures)
+	}
+}
@@ -0,0 +1,70 @@
+package ccl_test_lib
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// fuzzCorpusHeader is the first line of every Go native fuzzing corpus file
+const fuzzCorpusHeader = "go test fuzz v1\n"
+
+// ExportFuzzCorpus writes each distinct test input as a Go fuzz corpus file
+// in outDir (typically testdata/fuzz/FuzzParse). Files are named by content
+// hash, so re-exporting the same corpus produces identical filenames.
+// A zero-value config exports inputs from every test; otherwise only inputs
+// from tests compatible with cfg are exported.
+// Returns the number of corpus files written, or ErrNoTestData,
+// loader.ErrNoTests, or loader.ErrParse when the tests cannot be loaded.
+func ExportFuzzCorpus(testDataPath, outDir string, cfg config.ImplementationConfig, opts ...Option) (int, error) {
+	defaults := defaultOptions()
+	if len(cfg.SupportedFunctions) == 0 {
+		defaults.load.FilterMode = loader.FilterAll
+	}
+	o := applyOptions(defaults, opts)
+
+	testLoader := NewLoader(testDataPath, cfg, opts...)
+	tests, err := loadTests(testLoader, o)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create corpus directory: %w", err)
+	}
+
+	// Deduplicate by content hash - the hash is also the filename
+	written := make(map[string]bool)
+	for _, test := range tests {
+		for _, input := range test.Inputs {
+			data := encodeFuzzCorpusEntry(input)
+			name := fuzzCorpusFileName(data)
+			if written[name] {
+				continue
+			}
+			written[name] = true
+
+			path := filepath.Join(outDir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return 0, fmt.Errorf("failed to write corpus file %s: %w", path, err)
+			}
+		}
+	}
+
+	return len(written), nil
+}
+
+// encodeFuzzCorpusEntry encodes a single string argument in the go fuzz v1 format
+func encodeFuzzCorpusEntry(input string) []byte {
+	return []byte(fmt.Sprintf("%sstring(%q)\n", fuzzCorpusHeader, input))
+}
+
+// fuzzCorpusFileName derives a stable filename from the encoded file content,
+// matching the truncated sha256 naming used by `go test -fuzz`
+func fuzzCorpusFileName(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+}
@@ -0,0 +1,181 @@
+package ccl_test_lib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// decodeFuzzCorpusEntry reverses encodeFuzzCorpusEntry for a single string argument
+func decodeFuzzCorpusEntry(t *testing.T, data []byte) string {
+	t.Helper()
+	content := string(data)
+	if !strings.HasPrefix(content, fuzzCorpusHeader) {
+		t.Fatalf("Corpus file missing header: %q", content)
+	}
+	line := strings.TrimSuffix(strings.TrimPrefix(content, fuzzCorpusHeader), "\n")
+	if !strings.HasPrefix(line, "string(") || !strings.HasSuffix(line, ")") {
+		t.Fatalf("Corpus file has unexpected value line: %q", line)
+	}
+	value, err := strconv.Unquote(strings.TrimSuffix(strings.TrimPrefix(line, "string("), ")"))
+	if err != nil {
+		t.Fatalf("Failed to unquote corpus value: %v", err)
+	}
+	return value
+}
+
+func setupFuzzTestData(t *testing.T) string {
+	tmpDir := t.TempDir()
+	generatedDir := filepath.Join(tmpDir, "generated_tests")
+	if err := os.MkdirAll(generatedDir, 0755); err != nil {
+		t.Fatalf("Failed to create generated_tests directory: %v", err)
+	}
+
+	flatSuite := types.TestSuite{
+		Suite:   "Fuzz Suite",
+		Version: "1.0",
+		Tests: []types.TestCase{
+			{
+				Name:       "shared_parse",
+				Inputs:     []string{"key = value"},
+				Validation: "parse",
+				Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
+				Features:   []string{},
+				Behaviors:  []string{},
+				Variants:   []string{},
+			},
+			{
+				Name:       "shared_get_string",
+				Inputs:     []string{"key = value"},
+				Validation: "get_string",
+				Expected:   "value",
+				Args:       []string{"key"},
+				Features:   []string{},
+				Behaviors:  []string{},
+				Variants:   []string{},
+			},
+			{
+				Name:       "quoted_comment_filter",
+				Inputs:     []string{"/= \"quoted\"\n\tkey = tab\\value"},
+				Validation: "filter",
+				Expected:   []map[string]interface{}{{"key": "key", "value": "tab\\value"}},
+				Features:   []string{"comments"},
+				Behaviors:  []string{},
+				Variants:   []string{},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(flatSuite, "", "  ")
+	if err := os.WriteFile(filepath.Join(generatedDir, "fuzz.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write flat test file: %v", err)
+	}
+
+	return tmpDir
+}
+
+func TestEncodeFuzzCorpusEntry_RoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"key = value",
+		"multi\nline = \"quoted\"\r\n",
+		"名前 = 値\t\\",
+	}
+
+	for _, input := range inputs {
+		data := encodeFuzzCorpusEntry(input)
+		if got := decodeFuzzCorpusEntry(t, data); got != input {
+			t.Errorf("Round trip mismatch: expected %q, got %q", input, got)
+		}
+	}
+}
+
+func TestExportFuzzCorpus_DeduplicatesInputs(t *testing.T) {
+	testDataPath := setupFuzzTestData(t)
+	outDir := filepath.Join(t.TempDir(), "testdata", "fuzz", "FuzzParse")
+
+	count, err := ExportFuzzCorpus(testDataPath, outDir, config.ImplementationConfig{})
+	if err != nil {
+		t.Fatalf("ExportFuzzCorpus failed: %v", err)
+	}
+
+	// Three tests but only two distinct inputs
+	if count != 2 {
+		t.Errorf("Expected 2 corpus files, got %d", count)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatalf("Failed to read corpus directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 files on disk, got %d", len(entries))
+	}
+
+	decoded := make(map[string]bool)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(outDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read corpus file: %v", err)
+		}
+		if entry.Name() != fuzzCorpusFileName(data) {
+			t.Errorf("File name %s does not match content hash", entry.Name())
+		}
+		decoded[decodeFuzzCorpusEntry(t, data)] = true
+	}
+
+	if !decoded["key = value"] {
+		t.Error("Expected shared input to be exported")
+	}
+	if !decoded["/= \"quoted\"\n\tkey = tab\\value"] {
+		t.Error("Expected comment input to be exported")
+	}
+}
+
+func TestExportFuzzCorpus_StableFileNames(t *testing.T) {
+	testDataPath := setupFuzzTestData(t)
+	outDirA := filepath.Join(t.TempDir(), "a")
+	outDirB := filepath.Join(t.TempDir(), "b")
+
+	if _, err := ExportFuzzCorpus(testDataPath, outDirA, config.ImplementationConfig{}); err != nil {
+		t.Fatalf("First export failed: %v", err)
+	}
+	if _, err := ExportFuzzCorpus(testDataPath, outDirB, config.ImplementationConfig{}); err != nil {
+		t.Fatalf("Second export failed: %v", err)
+	}
+
+	namesA, _ := os.ReadDir(outDirA)
+	namesB, _ := os.ReadDir(outDirB)
+	if len(namesA) != len(namesB) {
+		t.Fatalf("Export sizes differ: %d vs %d", len(namesA), len(namesB))
+	}
+	for i := range namesA {
+		if namesA[i].Name() != namesB[i].Name() {
+			t.Errorf("File names differ between exports: %s vs %s", namesA[i].Name(), namesB[i].Name())
+		}
+	}
+}
+
+func TestExportFuzzCorpus_CompatibleOnly(t *testing.T) {
+	testDataPath := setupFuzzTestData(t)
+	outDir := t.TempDir()
+
+	// Without the comments feature the filter test is excluded
+	cfg := config.ImplementationConfig{
+		Name:               "parse-only",
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionFilter},
+	}
+
+	count, err := ExportFuzzCorpus(testDataPath, outDir, cfg)
+	if err != nil {
+		t.Fatalf("ExportFuzzCorpus failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 corpus file for compatible tests, got %d", count)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// BundleManifestFile is written into each bundle directory
+const BundleManifestFile = "manifest.json"
+
+// BundleSpec describes one bundle for GenerateBundles
+type BundleSpec struct {
+	Name          string
+	OnlyFunctions []config.CCLFunction // Validations to include (every function if empty)
+	OnlyFeatures  []config.CCLFeature  // Features tests may require; tests requiring others are left out
+	Inherit       string               // An earlier bundle whose functions and features this one adds to
+}
+
+// BundleManifest describes a generated bundle and summarizes its tests
+type BundleManifest struct {
+	Name         string         `json:"name"`
+	ID           string         `json:"id"`           // From Deps.NewID, given the bundle name
+	GeneratedAt  time.Time      `json:"generated_at"` // From Deps.Now
+	Inherit      string         `json:"inherit,omitempty"`
+	Functions    []string       `json:"functions,omitempty"` // After inheritance; empty for every function
+	Features     []string       `json:"features,omitempty"`  // After inheritance
+	Tests        int            `json:"tests"`
+	Files        map[string]int `json:"files"` // File name -> test count
+	ByValidation map[string]int `json:"by_validation"`
+	ByFeature    map[string]int `json:"by_feature"`
+}
+
+// GenerateBundles generates a bundle of flat tests for each spec, in order,
+// as outDir/<name>/generated_tests plus outDir/<name>/manifest.json, so a
+// bundle directory can be loaded like any test data directory. A spec with
+// Inherit includes everything the named earlier bundle does. Files without
+// tests are left out of a bundle. opts applies to every bundle, except that
+// each bundle's functions replace opts.OnlyFunctions.
+func GenerateBundles(sourceDir, outDir string, bundles []BundleSpec, opts GenerateOptions) ([]BundleManifest, error) {
+	resolved := make(map[string]BundleSpec, len(bundles))
+	var manifests []BundleManifest
+	for _, spec := range bundles {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("bundle has no name")
+		}
+		if _, ok := resolved[spec.Name]; ok {
+			return nil, fmt.Errorf("bundle %s is defined twice", spec.Name)
+		}
+		if spec.Inherit != "" {
+			parent, ok := resolved[spec.Inherit]
+			if !ok {
+				return nil, fmt.Errorf("bundle %s inherits from %q, which is not an earlier bundle", spec.Name, spec.Inherit)
+			}
+			spec = inheritBundle(spec, parent)
+		}
+		resolved[spec.Name] = spec
+
+		manifest, err := generateBundle(sourceDir, filepath.Join(outDir, spec.Name), spec, opts)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s: %w", spec.Name, err)
+		}
+		manifests = append(manifests, *manifest)
+	}
+	return manifests, nil
+}
+
+// inheritBundle adds the parent's functions and features to spec
+func inheritBundle(spec, parent BundleSpec) BundleSpec {
+	if len(parent.OnlyFunctions) == 0 || len(spec.OnlyFunctions) == 0 {
+		spec.OnlyFunctions = nil // Either side already allows every function
+	} else {
+		spec.OnlyFunctions = union(parent.OnlyFunctions, spec.OnlyFunctions)
+	}
+	spec.OnlyFeatures = union(parent.OnlyFeatures, spec.OnlyFeatures)
+	return spec
+}
+
+// union returns the values of a followed by those of b not already in a
+func union[T comparable](a, b []T) []T {
+	result := slices.Clone(a)
+	for _, value := range b {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// generateBundle writes one resolved bundle and its manifest to dir
+func generateBundle(sourceDir, dir string, spec BundleSpec, opts GenerateOptions) (*BundleManifest, error) {
+	testsDir := filepath.Join(dir, loader.DefaultGeneratedDir)
+	if err := os.RemoveAll(testsDir); err != nil {
+		return nil, fmt.Errorf("failed to clear previous bundle: %w", err)
+	}
+	opts.OnlyFunctions = spec.OnlyFunctions
+	gen := NewFlatGenerator(sourceDir, testsDir, opts)
+	gen.include = func(test types.TestCase) bool {
+		for _, feature := range test.Features {
+			if !slices.Contains(spec.OnlyFeatures, config.CCLFeature(feature)) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := gen.GenerateAll(); err != nil {
+		return nil, err
+	}
+
+	manifest := &BundleManifest{
+		Name:         spec.Name,
+		ID:           opts.Deps.newID(spec.Name),
+		GeneratedAt:  opts.Deps.now(),
+		Inherit:      spec.Inherit,
+		Files:        make(map[string]int),
+		ByValidation: make(map[string]int),
+		ByFeature:    make(map[string]int),
+	}
+	for _, fn := range spec.OnlyFunctions {
+		manifest.Functions = append(manifest.Functions, string(fn))
+	}
+	for _, feature := range spec.OnlyFeatures {
+		manifest.Features = append(manifest.Features, string(feature))
+	}
+
+	files, err := loader.ScanDir(testsDir, loader.DefaultScanOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bundle files: %w", err)
+	}
+	testLoader := loader.NewTestLoader("", config.ImplementationConfig{})
+	for _, file := range files {
+		suite, err := testLoader.LoadTestFile(file, loader.LoadOptions{Format: loader.FormatFlat, FilterMode: loader.FilterAll, LazyExpected: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle file: %w", err)
+		}
+		if len(suite.Tests) == 0 {
+			if err := os.Remove(file); err != nil {
+				return nil, fmt.Errorf("failed to remove empty bundle file: %w", err)
+			}
+			continue
+		}
+		manifest.Files[filepath.Base(file)] = len(suite.Tests)
+		manifest.Tests += len(suite.Tests)
+		for _, test := range suite.Tests {
+			manifest.ByValidation[test.Validation]++
+			for _, feature := range test.Features {
+				manifest.ByFeature[feature]++
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, BundleManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
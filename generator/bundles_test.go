@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// setupBundleSources writes a parse/build_hierarchy/get_int source test and
+// a parse test needing comments
+func setupBundleSources(t *testing.T) string {
+	t.Helper()
+	sourceDir := t.TempDir()
+	os.WriteFile(filepath.Join(sourceDir, "api_levels.json"), []byte(`{"tests": [
+		{"name": "basic", "inputs": ["a = 1"], "tests": [
+			{"function": "parse", "expect": [{"key": "a", "value": "1"}]},
+			{"function": "build_hierarchy", "expect": {"a": "1"}},
+			{"function": "get_int", "args": ["a"], "expect": 1}
+		]},
+		{"name": "commented", "inputs": ["/= note\na = 1"], "features": ["comments"], "tests": [
+			{"function": "parse", "expect": [{"key": "/", "value": "note"}, {"key": "a", "value": "1"}]}
+		]}
+	]}`), 0644)
+	os.WriteFile(filepath.Join(sourceDir, "api_typed.json"), []byte(`{"tests": [
+		{"name": "typed", "inputs": ["n = 2"], "tests": [{"function": "get_int", "args": ["n"], "expect": 2}]}
+	]}`), 0644)
+	return sourceDir
+}
+
+func TestGenerateBundles_Cumulative(t *testing.T) {
+	sourceDir := setupBundleSources(t)
+	outDir := t.TempDir()
+
+	// The phases of the workflow test: parse only, then object construction,
+	// then typed access with comments
+	manifests, err := GenerateBundles(sourceDir, outDir, []BundleSpec{
+		{Name: "level-1", OnlyFunctions: []config.CCLFunction{config.FunctionParse}},
+		{Name: "level-2", OnlyFunctions: []config.CCLFunction{config.FunctionBuildHierarchy}, Inherit: "level-1"},
+		{Name: "level-3", OnlyFunctions: []config.CCLFunction{config.FunctionGetInt}, OnlyFeatures: []config.CCLFeature{config.FeatureComments}, Inherit: "level-2"},
+	}, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("GenerateBundles failed: %v", err)
+	}
+
+	wantTests := []int{1, 2, 5}
+	for i, manifest := range manifests {
+		if manifest.Tests != wantTests[i] {
+			t.Errorf("%s: expected %d tests, got %d", manifest.Name, wantTests[i], manifest.Tests)
+		}
+		if i > 0 && manifest.Tests < manifests[i-1].Tests {
+			t.Errorf("%s has fewer tests than %s", manifest.Name, manifests[i-1].Name)
+		}
+	}
+
+	level3 := manifests[2]
+	if !reflect.DeepEqual(level3.Functions, []string{"parse", "build_hierarchy", "get_int"}) || !reflect.DeepEqual(level3.Features, []string{"comments"}) {
+		t.Errorf("Expected inherited functions and features, got %v and %v", level3.Functions, level3.Features)
+	}
+	if !reflect.DeepEqual(level3.ByValidation, map[string]int{"parse": 2, "build_hierarchy": 1, "get_int": 2}) || level3.ByFeature["comments"] != 1 {
+		t.Errorf("Unexpected level-3 statistics: %+v", level3)
+	}
+
+	// level-1 has no get_int tests, so api_typed.json is left out
+	if _, err := os.Stat(filepath.Join(outDir, "level-1", "generated_tests", "api_typed.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no empty api_typed.json in level-1, got %v", err)
+	}
+	if !reflect.DeepEqual(manifests[0].Files, map[string]int{"api_levels.json": 1}) {
+		t.Errorf("Unexpected level-1 files: %v", manifests[0].Files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "level-2", BundleManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var onDisk BundleManifest
+	if err := json.Unmarshal(data, &onDisk); err != nil || !reflect.DeepEqual(onDisk, manifests[1]) {
+		t.Errorf("Expected the written manifest to match, got %+v (%v)", onDisk, err)
+	}
+
+	// A bundle directory loads like any test data directory
+	tests, err := loader.NewTestLoader(filepath.Join(outDir, "level-2"), config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{
+		Format: loader.FormatFlat, FilterMode: loader.FilterAll,
+	})
+	if err != nil || len(tests) != 2 {
+		t.Errorf("Expected to load 2 tests from level-2, got %d (%v)", len(tests), err)
+	}
+}
+
+func TestGenerateBundles_Errors(t *testing.T) {
+	sourceDir := setupBundleSources(t)
+	tests := []struct {
+		bundles []BundleSpec
+		want    string
+	}{
+		{[]BundleSpec{{Name: "a", Inherit: "b"}, {Name: "b"}}, `bundle a inherits from "b", which is not an earlier bundle`},
+		{[]BundleSpec{{Name: "a"}, {Name: "a"}}, "bundle a is defined twice"},
+		{[]BundleSpec{{}}, "bundle has no name"},
+	}
+	for _, tt := range tests {
+		if _, err := GenerateBundles(sourceDir, t.TempDir(), tt.bundles, GenerateOptions{}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestGenerateBundles_FixedDeps(t *testing.T) {
+	sourceDir := setupBundleSources(t)
+	bundles := []BundleSpec{{Name: "all"}}
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	read := func() []byte {
+		t.Helper()
+		outDir := t.TempDir()
+		if _, err := GenerateBundles(sourceDir, outDir, bundles, GenerateOptions{Deps: FixedDeps(at)}); err != nil {
+			t.Fatalf("GenerateBundles failed: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(outDir, "all", BundleManifestFile))
+		if err != nil {
+			t.Fatalf("Failed to read manifest: %v", err)
+		}
+		return data
+	}
+
+	first, second := read(), read()
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected byte-identical manifests with fixed deps.\nFirst:\n%s\nSecond:\n%s", first, second)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(first, &manifest); err != nil {
+		t.Fatalf("Invalid manifest: %v", err)
+	}
+	if !manifest.GeneratedAt.Equal(at) || manifest.ID != FixedDeps(at).NewID("all") {
+		t.Errorf("Expected the fixed time and ID, got %v and %q", manifest.GeneratedAt, manifest.ID)
+	}
+}
+
+func TestGenerateBundles_RealDeps(t *testing.T) {
+	sourceDir := setupBundleSources(t)
+	before := time.Now().Add(-time.Second)
+	first, err := GenerateBundles(sourceDir, t.TempDir(), []BundleSpec{{Name: "all"}}, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("GenerateBundles failed: %v", err)
+	}
+	second, err := GenerateBundles(sourceDir, t.TempDir(), []BundleSpec{{Name: "all"}}, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("GenerateBundles failed: %v", err)
+	}
+	if first[0].GeneratedAt.Before(before) || first[0].ID == "" || first[0].ID == second[0].ID {
+		t.Errorf("Expected a current time and distinct random IDs, got %+v and %+v", first[0], second[0])
+	}
+}
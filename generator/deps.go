@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Deps are the generator's sources of nondeterminism: the clock behind
+// manifest GeneratedAt times and the IDs given to generated artifacts. Nil
+// fields use the real clock and random IDs. Golden tests should set
+// GenerateOptions.Deps to FixedDeps so output is byte-identical across runs.
+type Deps struct {
+	Now   func() time.Time         // Current time (time.Now if nil)
+	NewID func(name string) string // ID for the named artifact, such as a bundle (random if nil)
+}
+
+// FixedDeps returns deps whose clock always reads at and whose IDs depend
+// only on the artifact name
+func FixedDeps(at time.Time) Deps {
+	return Deps{
+		Now: func() time.Time { return at },
+		NewID: func(name string) string {
+			sum := sha256.Sum256([]byte(name))
+			return hex.EncodeToString(sum[:8])
+		},
+	}
+}
+
+// now returns the current time in UTC to the second, as manifests record it
+func (d Deps) now() time.Time {
+	now := time.Now
+	if d.Now != nil {
+		now = d.Now
+	}
+	return now().UTC().Truncate(time.Second)
+}
+
+func (d Deps) newID(name string) string {
+	if d.NewID != nil {
+		return d.NewID(name)
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// ValidationFeatureMap maps a validation to the features every flat test of
+// it requires. A source test's own features are always merged in, so an
+// entry only adds requirements.
+type ValidationFeatureMap map[string][]config.CCLFeature
+
+// defaultValidationFeatures has an entry, possibly empty, for every function
+var defaultValidationFeatures = ValidationFeatureMap{
+	string(config.FunctionParse):           nil,
+	string(config.FunctionParseIndented):   nil,
+	string(config.FunctionFilter):          {config.FeatureComments},
+	string(config.FunctionExtractComments): {config.FeatureComments},
+	string(config.FunctionCombine):         nil,
+	string(config.FunctionExpandDotted):    {config.FeatureExperimentalDottedKeys},
+	string(config.FunctionBuildHierarchy):  nil,
+	string(config.FunctionGetString):       nil,
+	string(config.FunctionGetInt):          nil,
+	string(config.FunctionGetBool):         nil,
+	string(config.FunctionGetFloat):        nil,
+	string(config.FunctionGetList):         nil,
+	string(config.FunctionPrettyPrint):     nil,
+	string(config.FunctionCanonicalFormat): nil,
+}
+
+var (
+	validationFeaturesMu sync.RWMutex
+	validationFeatures   = defaultValidationFeatures.clone()
+)
+
+// DefaultValidationFeatureMap returns the built-in validation features
+func DefaultValidationFeatureMap() ValidationFeatureMap {
+	return defaultValidationFeatures.clone()
+}
+
+// ValidationFeatures returns the validation features generators currently
+// use, including changes made with SetValidationFeatureMap
+func ValidationFeatures() ValidationFeatureMap {
+	validationFeaturesMu.RLock()
+	defer validationFeaturesMu.RUnlock()
+	return validationFeatures.clone()
+}
+
+// SetValidationFeatureMap replaces the features of the validations in m for
+// every generator; validations m leaves out keep theirs. It fails, changing
+// nothing, if m names an unknown feature.
+func SetValidationFeatureMap(m ValidationFeatureMap) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	validationFeaturesMu.Lock()
+	defer validationFeaturesMu.Unlock()
+	for validation, features := range m {
+		validationFeatures[validation] = slices.Clone(features)
+	}
+	return nil
+}
+
+// Validate returns an error naming the first validation, in name order,
+// mapped to a feature config.AllFeatures does not list
+func (m ValidationFeatureMap) Validate() error {
+	known := config.AllFeatures()
+	for _, validation := range slices.Sorted(maps.Keys(m)) {
+		for _, feature := range m[validation] {
+			if !slices.Contains(known, feature) {
+				return fmt.Errorf("validation %s: unknown feature %q", validation, feature)
+			}
+		}
+	}
+	return nil
+}
+
+func (m ValidationFeatureMap) clone() ValidationFeatureMap {
+	cloned := make(ValidationFeatureMap, len(m))
+	for validation, features := range m {
+		cloned[validation] = slices.Clone(features)
+	}
+	return cloned
+}
+
+// validationFeatures returns the package table with Options.ValidationFeatures
+// applied over it
+func (o GenerateOptions) validationFeatures() ValidationFeatureMap {
+	table := ValidationFeatures()
+	for validation, features := range o.ValidationFeatures {
+		table[validation] = features
+	}
+	return table
+}
+
+// changedValidationFeatures returns the entries of the effective table that
+// differ from the defaults, or nil
+func (o GenerateOptions) changedValidationFeatures() ValidationFeatureMap {
+	var changed ValidationFeatureMap
+	for validation, features := range o.validationFeatures() {
+		if !slices.Equal(features, defaultValidationFeatures[validation]) {
+			if changed == nil {
+				changed = make(ValidationFeatureMap)
+			}
+			changed[validation] = features
+		}
+	}
+	return changed
+}
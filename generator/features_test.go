@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+func TestDefaultValidationFeatureMap_Complete(t *testing.T) {
+	table := DefaultValidationFeatureMap()
+	for _, fn := range config.AllFunctions() {
+		if _, ok := table[string(fn)]; !ok {
+			t.Errorf("No validation feature entry for %s", fn)
+		}
+	}
+	if err := table.Validate(); err != nil {
+		t.Errorf("Default table is invalid: %v", err)
+	}
+}
+
+func TestSetValidationFeatureMap(t *testing.T) {
+	t.Cleanup(func() { SetValidationFeatureMap(DefaultValidationFeatureMap()) })
+	gen := NewFlatGenerator("", "", GenerateOptions{})
+
+	err := SetValidationFeatureMap(ValidationFeatureMap{
+		"parse_indented": {config.FeatureWhitespace, config.FeatureMultiline},
+		"get_list":       {"list_semantics"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `validation get_list: unknown feature "list_semantics"`) {
+		t.Fatalf("Expected an unknown feature error, got %v", err)
+	}
+	if _, features := gen.GenerateMetadataFromValidation("parse_indented"); len(features) != 0 {
+		t.Errorf("Expected a failed call to change nothing, got %v", features)
+	}
+
+	if err := SetValidationFeatureMap(ValidationFeatureMap{"parse_indented": {config.FeatureWhitespace, config.FeatureMultiline}}); err != nil {
+		t.Fatalf("SetValidationFeatureMap failed: %v", err)
+	}
+	if _, features := gen.GenerateMetadataFromValidation("parse_indented"); !reflect.DeepEqual(features, []string{"whitespace", "multiline"}) {
+		t.Errorf("Expected the override, got %v", features)
+	}
+	// Validations the map leaves out keep their entries
+	if _, features := gen.GenerateMetadataFromValidation("filter"); !reflect.DeepEqual(features, []string{"comments"}) {
+		t.Errorf("Expected filter to keep comments, got %v", features)
+	}
+}
+
+func TestGenerateOptions_ValidationFeatures(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	opts := GenerateOptions{
+		SourceFormat:       FormatCompact,
+		ValidationFeatures: ValidationFeatureMap{"parse": {config.FeatureUnicode}},
+	}
+	if opts.Fingerprint() == (GenerateOptions{SourceFormat: FormatCompact}).Fingerprint() {
+		t.Error("Expected the override to change the fingerprint")
+	}
+	gen := NewFlatGenerator(sourceDir, outputDir, opts)
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	suite, err := loader.NewTestLoader("", config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "test-source.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated file: %v", err)
+	}
+	for _, test := range suite.Tests {
+		// The source's own comments feature merges with the mapped one
+		if test.Validation == "parse" && !reflect.DeepEqual(test.Features, []string{"comments", "unicode"}) {
+			t.Errorf("Expected [comments unicode], got %v", test.Features)
+		}
+	}
+
+	bad := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{
+		SourceFormat:       FormatCompact,
+		ValidationFeatures: ValidationFeatureMap{"parse": {"nonsense"}},
+	})
+	if err := bad.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err == nil || !strings.Contains(err.Error(), "invalid ValidationFeatures") {
+		t.Errorf("Expected an invalid option error, got %v", err)
+	}
+}
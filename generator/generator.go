@@ -3,11 +3,16 @@
 package generator
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/CatConfLang/ccl-test-lib/config"
@@ -22,20 +27,93 @@ const (
 	FormatFlat    = loader.FormatFlat
 )
 
+// ErrSourceMissing is returned (wrapped) when a source directory or file does not exist
+var ErrSourceMissing = errors.New("source tests not found")
+
+// FlatFormatVersion is the generated format version FlatGenerator emits,
+// stamped into each file's $schema
+const FlatFormatVersion = loader.FormatVersion1
+
 // FlatGenerator transforms source format to implementation-friendly flat format
 type FlatGenerator struct {
 	SourceDir string
 	OutputDir string
 	Options   GenerateOptions
+
+	include func(types.TestCase) bool // Extra selection applied after Options (set by GenerateBundles)
+	report  GenerationReport
+}
+
+// GenerationReport records what the generator wrote for each source file
+type GenerationReport struct {
+	Files    []FileReport  `json:"files"`
+	Failures []FileFailure `json:"failures,omitempty"` // Source files GenerateAll failed to generate, in the order attempted
+}
+
+// FileFailure is a source file GenerateAll could not generate. Its
+// previous output, if any, is left as it was.
+type FileFailure struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// FileError is the error GenerateAll returns for a source file that failed,
+// wrapping the cause (such as ErrSourceMissing or loader.ErrParse). With
+// ContinueOnError the FileErrors of all failed files are joined.
+type FileError struct {
+	Source string
+	Err    error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("failed to generate %s: %v", e.Source, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FileReport describes one generated source file
+type FileReport struct {
+	Source        string               `json:"source"`
+	Tests         int                  `json:"tests"`                    // Flat tests written
+	SkipFunctions []config.CCLFunction `json:"skip_functions,omitempty"` // Skipped for this file only, by its generator block
+	FileSkipped   int                  `json:"file_skipped,omitempty"`   // Flat tests SkipFunctions removed
+	DroppedArgs   []DroppedArgs        `json:"dropped_args,omitempty"`   // Args removed from validations that take none
+}
+
+// DroppedArgs records args a source test gave a validation that takes none.
+// The test is generated without them; GenerateOptions.StrictArgs fails instead.
+type DroppedArgs struct {
+	Test       string   `json:"test"`
+	Validation string   `json:"validation"`
+	Args       []string `json:"args"`
 }
 
 // GenerateOptions controls flat format generation behavior
 type GenerateOptions struct {
-	SkipPropertyTests bool                 // Skip property-*.json files
-	SkipFunctions     []config.CCLFunction // Skip specific functions
-	OnlyFunctions     []config.CCLFunction // Generate only these functions
-	SourceFormat      loader.TestFormat    // Input format (compact or flat)
-	Verbose           bool                 // Enable verbose output
+	SkipPropertyTests  bool                      // Skip property-*.json files
+	SkipFunctions      []config.CCLFunction      // Skip specific functions
+	OnlyFunctions      []config.CCLFunction      // Generate only these functions
+	SourceFormat       loader.TestFormat         // Input format (compact or flat)
+	Incremental        bool                      // Skip sources whose output is newer than the source
+	NormalizeInput     loader.InputNormalization // Line ending normalization applied to source inputs
+	Scan               *loader.ScanOptions       // Which files in the source directory are generated (loader.DefaultScanOptions if nil)
+	LenientUTF8        bool                      // Replace invalid UTF-8 in source inputs instead of failing (see LoadOptions.LenientUTF8)
+	StrictArgs         bool                      // Fail on tests with the wrong number of args, or args on a validation that takes none, instead of dropping them
+	LenientShapes      bool                      // Warn instead of failing when an expect value does not match its validation
+	LenientBehaviors   bool                      // Warn instead of failing on behaviors neither defined by config nor registered
+	Verbose            bool                      // Enable verbose output
+	Logger             *slog.Logger              // Structured file, skip, and filtering events (silent if nil)
+	EmitLevels         bool                      // Write each test's level (see loader.TestLevel) into the flat output
+	EmitLegacyTags     bool                      // Also write the typed metadata as function:/feature:/behavior:/variant: Meta.Tags for old harnesses
+	EntryProvenance    bool                      // Write each expected entry's index in the source expect array as "src_index"
+	ValidationFeatures ValidationFeatureMap      // Features required by these validations, over the package table (see SetValidationFeatureMap)
+	Budgets            *config.Budgets           // Size limits enforced on source inputs and generated output (none if nil)
+	LenientBudgets     bool                      // Warn instead of failing when Budgets are exceeded
+	Progress           loader.ProgressFunc       // Called with loader.StageGenerate as GenerateAll handles each source file, including skipped ones (optional)
+	ContinueOnError    bool                      // Make GenerateAll attempt every source file and return all their FileErrors instead of stopping at the first
+	Deps               Deps                      // Clock and ID source for manifests (real ones if unset)
 }
 
 // NewFlatGenerator creates a new flat format generator
@@ -47,52 +125,213 @@ func NewFlatGenerator(sourceDir, outputDir string, opts GenerateOptions) *FlatGe
 	}
 }
 
-// GenerateAll processes all source test files and generates flat format
+// logger returns Options.Logger, or a logger that discards everything
+func (fg *FlatGenerator) logger() *slog.Logger {
+	if fg.Options.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return fg.Options.Logger
+}
+
+// GenerateAll processes all source test files and generates flat format.
+// It returns ErrSourceMissing if SourceDir does not exist and a FileError,
+// wrapping loader.ErrParse for invalid source files, for the first file
+// that fails, or with ContinueOnError for every one. Report lists the
+// failures either way.
 func (fg *FlatGenerator) GenerateAll() error {
+	fg.report = GenerationReport{}
+	if _, err := os.Stat(fg.SourceDir); err != nil {
+		return fmt.Errorf("%w: %w", ErrSourceMissing, err)
+	}
 	if err := os.MkdirAll(fg.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	pattern := filepath.Join(fg.SourceDir, "*.json")
-	files, err := filepath.Glob(pattern)
+	files, err := fg.sourceFiles()
 	if err != nil {
 		return fmt.Errorf("failed to find source files: %w", err)
 	}
 
-	for _, file := range files {
-		basename := filepath.Base(file)
-
-		// Skip property tests if requested
-		if fg.Options.SkipPropertyTests && strings.HasPrefix(basename, "property-") {
-			if fg.Options.Verbose {
-				fmt.Printf("Skipping property test file: %s\n", basename)
+	var failures []error
+	fg.Options.Progress.Report(loader.StageGenerate, 0, len(files))
+	for i, file := range files {
+		if err := fg.generateSource(file); err != nil {
+			fg.report.Failures = append(fg.report.Failures, FileFailure{Source: file, Error: err.Err.Error()})
+			if !fg.Options.ContinueOnError {
+				return err
 			}
-			continue
+			failures = append(failures, err)
 		}
+		fg.Options.Progress.Report(loader.StageGenerate, i+1, len(files))
+	}
 
-		if err := fg.GenerateFile(file); err != nil {
-			return fmt.Errorf("failed to generate %s: %w", file, err)
+	if err := fg.checkGeneratedSize(); err != nil {
+		failures = append(failures, err)
+	}
+	return errors.Join(failures...)
+}
+
+// checkGeneratedSize applies Budgets.MaxGeneratedBytes to the output directory
+func (fg *FlatGenerator) checkGeneratedSize() error {
+	if fg.Options.Budgets == nil {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(fg.OutputDir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to find generated files: %w", err)
+	}
+	var total int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat generated file: %w", err)
+		}
+		total += info.Size()
+	}
+	return fg.overBudget(fg.OutputDir, fg.Options.Budgets.CheckGeneratedSize(total))
+}
+
+// overBudget fails with problem, or only logs it if Options.LenientBudgets
+func (fg *FlatGenerator) overBudget(subject string, problem *config.BudgetError) error {
+	if problem == nil {
+		return nil
+	}
+	if !fg.Options.LenientBudgets {
+		return fmt.Errorf("%s: %w", subject, problem)
+	}
+	fg.logger().Warn("over budget", "subject", subject, "reason", problem.Error())
+	return nil
+}
+
+// generateSource generates one source file unless Options skip it
+func (fg *FlatGenerator) generateSource(file string) *FileError {
+	log := fg.logger()
+	basename := filepath.Base(file)
+
+	// Skip property tests if requested
+	if fg.Options.SkipPropertyTests && strings.HasPrefix(basename, "property-") {
+		log.Debug("skipped source file", "file", file, "reason", "property test")
+		if fg.Options.Verbose {
+			fmt.Printf("Skipping property test file: %s\n", basename)
 		}
+		return nil
+	}
 
+	if fg.Options.Incremental && fg.isUpToDate(file) {
+		log.Debug("skipped source file", "file", file, "reason", "up to date")
 		if fg.Options.Verbose {
-			fmt.Printf("Generated flat format for: %s\n", basename)
+			fmt.Printf("Up to date: %s\n", basename)
 		}
+		return nil
+	}
+
+	if err := fg.GenerateFile(file); err != nil {
+		log.Warn("failed to generate source file", "file", file, "reason", err)
+		return &FileError{Source: file, Err: err}
 	}
 
+	if fg.Options.Verbose {
+		fmt.Printf("Generated flat format for: %s\n", basename)
+	}
 	return nil
 }
 
-// GenerateFile processes a single source file
+// sourceFiles returns the source files in SourceDir selected by Options.Scan
+func (fg *FlatGenerator) sourceFiles() ([]string, error) {
+	scan := loader.DefaultScanOptions()
+	if fg.Options.Scan != nil {
+		scan = *fg.Options.Scan
+	}
+	return loader.ScanDir(fg.SourceDir, scan)
+}
+
+// isUpToDate reports whether a source file's output exists, is not older
+// than it, and was not generated by another version or with other options
+func (fg *FlatGenerator) isUpToDate(sourceFile string) bool {
+	sourceInfo, err := os.Stat(sourceFile)
+	if err != nil {
+		return false
+	}
+	outputFile := filepath.Join(fg.OutputDir, filepath.Base(sourceFile))
+	outputInfo, err := os.Stat(outputFile)
+	if err != nil || outputInfo.ModTime().Before(sourceInfo.ModTime()) {
+		return false
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		return false
+	}
+	// Output without a readable generator block is judged by time alone
+	info, err := loader.ReadGeneratorInfo(data)
+	return err != nil || info == nil || *info == fg.Provenance()
+}
+
+// Report returns the files generated since the last GenerateAll, in the
+// order they were generated. A file generated again replaces its entry.
+func (fg *FlatGenerator) Report() GenerationReport {
+	return fg.report
+}
+
+// record adds or replaces a file's entry in the report
+func (fg *FlatGenerator) record(file FileReport) {
+	for i, existing := range fg.report.Files {
+		if existing.Source == file.Source {
+			fg.report.Files[i] = file
+			return
+		}
+	}
+	fg.report.Files = append(fg.report.Files, file)
+}
+
+// GenerateFile processes a single source file. It returns ErrSourceMissing
+// if the file does not exist and wraps loader.ErrParse if it is invalid.
 func (fg *FlatGenerator) GenerateFile(sourceFile string) error {
+	flatData, file, err := fg.flatten(sourceFile)
+	if err != nil {
+		return err
+	}
+
+	outputFile := filepath.Join(fg.OutputDir, filepath.Base(sourceFile))
+	if err := os.WriteFile(outputFile, flatData, 0644); err != nil {
+		return fmt.Errorf("failed to write flat file: %w", err)
+	}
+	fg.record(file)
+	fg.logger().Debug("generated flat file", "file", outputFile)
+	return nil
+}
+
+// FlattenFile returns the generated flat file GenerateFile would write for
+// a source file, without writing it. Errors are as for GenerateFile.
+func (fg *FlatGenerator) FlattenFile(sourceFile string) ([]byte, error) {
+	flatData, _, err := fg.flatten(sourceFile)
+	return flatData, err
+}
+
+// flatten generates a source file's flat file and its report entry
+func (fg *FlatGenerator) flatten(sourceFile string) ([]byte, FileReport, error) {
+	if err := fg.Options.ValidationFeatures.Validate(); err != nil {
+		return nil, FileReport{}, fmt.Errorf("invalid ValidationFeatures: %w", err)
+	}
+
 	// Use loader to handle format detection and parsing
 	testLoader := loader.NewTestLoader("", config.ImplementationConfig{})
+	testLoader.Logger = fg.Options.Logger
 
 	sourceSuite, err := testLoader.LoadTestFile(sourceFile, loader.LoadOptions{
-		Format:     fg.Options.SourceFormat,
-		FilterMode: loader.FilterAll,
+		Format:         fg.Options.SourceFormat,
+		FilterMode:     loader.FilterAll,
+		NormalizeInput: fg.Options.NormalizeInput,
+		LenientUTF8:    fg.Options.LenientUTF8,
 	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, FileReport{}, fmt.Errorf("%w: %w", ErrSourceMissing, err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to load source file: %w", err)
+		return nil, FileReport{}, fmt.Errorf("failed to load source file: %w", err)
+	}
+	fileSkips, err := fg.fileSkipFunctions(sourceFile)
+	if err != nil {
+		return nil, FileReport{}, err
 	}
 
 	// Transform to flat format
@@ -103,16 +342,82 @@ func (fg *FlatGenerator) GenerateFile(sourceFile string) error {
 		Tests:       []types.TestCase{},
 	}
 
+	var droppedArgs []DroppedArgs
 	for _, sourceTest := range sourceSuite.Tests {
+		if fg.Options.Budgets != nil {
+			if err := fg.overBudget("source test "+sourceTest.Name, fg.Options.Budgets.CheckInputs(sourceTest.Inputs)); err != nil {
+				return nil, FileReport{}, err
+			}
+		}
+		for _, behavior := range sourceTest.Behaviors {
+			if problem := types.CheckBehavior(behavior); problem != nil {
+				if !fg.Options.LenientBehaviors {
+					return nil, FileReport{}, fmt.Errorf("source test %s: %w", sourceTest.Name, problem)
+				}
+				fg.logger().Warn("unknown behavior", "test", sourceTest.Name, "reason", problem.Message)
+			}
+		}
 		flatTests, err := fg.TransformSourceToFlat(sourceTest)
 		if err != nil {
-			return fmt.Errorf("failed to transform test %s: %w", sourceTest.Name, err)
+			return nil, FileReport{}, fmt.Errorf("failed to transform test %s: %w", sourceTest.Name, err)
+		}
+		for _, flatTest := range flatTests {
+			if len(flatTest.Args) > 0 && !types.ArityOf(flatTest.Validation).TakesArgs() {
+				// The expectation cannot depend on args the function never
+				// sees, so the test stands without them
+				if fg.Options.StrictArgs {
+					return nil, FileReport{}, fmt.Errorf("source test %s, validation %s: %w", sourceTest.Name, flatTest.Validation,
+						types.CheckArgs(flatTest.Validation, flatTest.Args))
+				}
+				fg.logger().Warn("dropped args", "test", sourceTest.Name, "validation", flatTest.Validation, "args", flatTest.Args)
+				droppedArgs = append(droppedArgs, DroppedArgs{Test: sourceTest.Name, Validation: flatTest.Validation, Args: flatTest.Args})
+				flatTest.Args = nil
+			}
+			if problem := types.CheckArgs(flatTest.Validation, flatTest.Args); problem != nil {
+				if fg.Options.StrictArgs {
+					return nil, FileReport{}, fmt.Errorf("test %s: %w", flatTest.Name, problem)
+				}
+				fg.logger().Warn("rejected test", "test", flatTest.Name, "reason", problem.Message)
+				continue
+			}
+			if problem := types.CheckInputs(flatTest.Validation, flatTest.Inputs); problem != nil {
+				return nil, FileReport{}, fmt.Errorf("test %s: %w", flatTest.Name, problem)
+			}
+			if !flatTest.ExpectsError() {
+				if problem := types.CheckExpectedShape(flatTest.Validation, flatTest.Expected); problem != nil {
+					if !fg.Options.LenientShapes {
+						return nil, FileReport{}, fmt.Errorf("source test %s, validation %s: %w", sourceTest.Name, flatTest.Validation, problem)
+					}
+					fg.logger().Warn("expected shape mismatch", "test", sourceTest.Name, "validation", flatTest.Validation, "reason", problem.Message)
+				}
+			}
+			flatSuite.Tests = append(flatSuite.Tests, flatTest)
 		}
-		flatSuite.Tests = append(flatSuite.Tests, flatTests...)
 	}
 
-	// Apply filtering options
+	// Apply filtering options, then the file's own skips
 	flatSuite.Tests = fg.applyFiltering(flatSuite.Tests)
+	file := FileReport{Source: sourceFile, SkipFunctions: fileSkips, DroppedArgs: droppedArgs}
+	if len(fileSkips) > 0 {
+		kept := flatSuite.Tests[:0]
+		for _, test := range flatSuite.Tests {
+			if slices.Contains(fileSkips, config.CCLFunction(test.Validation)) {
+				fg.logger().Debug("rejected test", "test", test.Name, "reason", "skipped function "+test.Validation+" by file")
+				file.FileSkipped++
+				continue
+			}
+			kept = append(kept, test)
+		}
+		flatSuite.Tests = kept
+	}
+	file.Tests = len(flatSuite.Tests)
+
+	outputFile := filepath.Join(fg.OutputDir, filepath.Base(sourceFile))
+	if fg.Options.Budgets != nil {
+		if err := fg.overBudget(outputFile, fg.Options.Budgets.CheckTestCount(len(flatSuite.Tests))); err != nil {
+			return nil, FileReport{}, err
+		}
+	}
 
 	// Convert to generated flat format types (array of flat test cases)
 	var flatTests []generated.GeneratedFormatSimpleJsonTestsElem
@@ -122,29 +427,88 @@ func (fg *FlatGenerator) GenerateFile(sourceFile string) error {
 	}
 
 	// Create object format with $schema at top level
+	provenance := fg.Provenance()
 	wrapper := generated.GeneratedFormatSimpleJson{
-		Schema: "http://json-schema.org/draft-07/schema#",
-		Tests:  flatTests,
+		Schema: loader.FlatSchema(FlatFormatVersion),
+		Generator: &generated.GeneratedFormatSimpleJsonGenerator{
+			Version:            provenance.Version,
+			OptionsFingerprint: provenance.OptionsFingerprint,
+		},
+		Tests: flatTests,
 	}
 
-	// Write flat format file
-	outputFile := filepath.Join(fg.OutputDir, filepath.Base(sourceFile))
-	flatData, err := json.MarshalIndent(wrapper, "", "  ")
+	flatData, err := marshalFlatFile(wrapper)
 	if err != nil {
-		return fmt.Errorf("failed to marshal flat JSON: %w", err)
+		return nil, FileReport{}, fmt.Errorf("failed to marshal flat JSON: %w", err)
 	}
+	fg.logger().Debug("flattened source file", "file", sourceFile, "tests", len(flatTests))
+	return flatData, file, nil
+}
 
-	if err := os.WriteFile(outputFile, flatData, 0644); err != nil {
-		return fmt.Errorf("failed to write flat file: %w", err)
+// marshalFlatFile renders a flat file exactly as json.MarshalIndent would,
+// but one test at a time, so a test with a huge expected value is held in
+// memory a few times over rather than the whole file
+func marshalFlatFile(wrapper generated.GeneratedFormatSimpleJson) ([]byte, error) {
+	tests := wrapper.Tests
+	wrapper.Tests = nil
+	skeleton, err := json.MarshalIndent(wrapper, "", "  ")
+	if err != nil || tests == nil {
+		return skeleton, err
 	}
 
-	return nil
+	// Strings are escaped, so only the key itself starts a line this way
+	const placeholder = "\n  \"tests\": null"
+	at := bytes.Index(skeleton, []byte(placeholder))
+	if at < 0 {
+		return nil, fmt.Errorf("no tests field in flat file")
+	}
+	var b bytes.Buffer
+	b.Write(skeleton[:at])
+	b.WriteString("\n  \"tests\": [")
+	for i, test := range tests {
+		data, err := json.MarshalIndent(test, "    ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n    ")
+		b.Write(data)
+	}
+	if len(tests) > 0 {
+		b.WriteString("\n  ")
+	}
+	b.WriteByte(']')
+	b.Write(skeleton[at+len(placeholder):])
+	return b.Bytes(), nil
+}
+
+// fileSkipFunctions returns the functions a source file's generator block
+// skips, or an error if it names an unknown function
+func (fg *FlatGenerator) fileSkipFunctions(sourceFile string) ([]config.CCLFunction, error) {
+	data, err := os.ReadFile(sourceFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source file: %w", err)
+	}
+	opts, err := loader.ReadFileGeneratorOptions(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(sourceFile), err)
+	}
+	functions, err := opts.Functions()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(sourceFile), err)
+	}
+	return functions, nil
 }
 
 // TransformSourceToFlat transforms a source test to multiple flat tests (1:N transformation)
 func (fg *FlatGenerator) TransformSourceToFlat(sourceTest types.TestCase) ([]types.TestCase, error) {
 	if sourceTest.Validations == nil {
 		// Already flat format or no validations
+		if fg.Options.EmitLegacyTags {
+			sourceTest.Meta.Tags = SyncLegacyTags(sourceTest)
+		}
 		return []types.TestCase{sourceTest}, nil
 	}
 
@@ -177,48 +541,49 @@ func (fg *FlatGenerator) TransformSourceToFlat(sourceTest types.TestCase) ([]typ
 			Expected:    validationComponents.Expected,
 			Args:        validationComponents.Args,
 			ExpectError: validationComponents.Error,
+			ErrorType:   validationComponents.ErrorType,
+			Tolerance:   validationComponents.Tolerance,
+			Default:     validationComponents.Default,
 			Meta:        sourceTest.Meta,
 			SourceTest:  sourceTest.Name,
+			Suite:       sourceTest.Suite,
+			Skip:        sourceTest.Skip,
+
+			UnicodeNormalization: sourceTest.UnicodeNormalization,
 		}
 
 		// Extract and populate type-safe metadata
 		generatedFunctions, generatedFeatures := fg.GenerateMetadataFromValidation(validationName)
 		flatTest.Functions = generatedFunctions
 
-		// Merge generated features with source features, ensuring never nil and no duplicates
-		flatTest.Features = make([]string, 0)
-		if sourceTest.Features != nil {
-			flatTest.Features = append(flatTest.Features, sourceTest.Features...)
-		}
-		if generatedFeatures != nil {
-			flatTest.Features = append(flatTest.Features, generatedFeatures...)
-		}
-		// Remove duplicates by using a map
+		// Merge generated features with source features without duplicates
 		seen := make(map[string]bool)
-		uniqueFeatures := make([]string, 0, len(flatTest.Features))
-		for _, feature := range flatTest.Features {
+		for _, feature := range append(slices.Clone(sourceTest.Features), generatedFeatures...) {
 			if !seen[feature] {
 				seen[feature] = true
-				uniqueFeatures = append(uniqueFeatures, feature)
+				flatTest.Features = append(flatTest.Features, feature)
 			}
 		}
-		flatTest.Features = uniqueFeatures
 
 		// Filter behaviors to only include those relevant to this validation function.
 		// This ensures function-specific behaviors (like boolean_strict/lenient) are
 		// only tagged on functions where they actually affect behavior.
 		flatTest.Behaviors = filterBehaviorsForFunction(sourceTest.Behaviors, validationName)
 
-		// Copy variants from source, ensuring never nil
-		if sourceTest.Variants != nil {
-			flatTest.Variants = sourceTest.Variants
-		} else {
-			flatTest.Variants = make([]string, 0)
-		}
+		flatTest.Variants = sourceTest.Variants
 
 		// Filter conflicts to only include behavior conflicts relevant to this function
 		flatTest.Conflicts = filterConflictsForFunction(sourceTest.Conflicts, validationName)
 
+		// The source test's level covers all its validations; classify each on its own
+		flatTest.Meta.Level = 0
+		flatTest.Meta.Level = loader.TestLevel(flatTest)
+
+		flatTest.Normalize()
+		if fg.Options.EmitLegacyTags {
+			flatTest.Meta.Tags = SyncLegacyTags(flatTest)
+		}
+
 		// Validation components are already parsed and applied above
 		// No special case handling needed - all validation types are handled uniformly
 
@@ -228,22 +593,14 @@ func (fg *FlatGenerator) TransformSourceToFlat(sourceTest types.TestCase) ([]typ
 	return flatTests, nil
 }
 
-// GenerateMetadataFromValidation creates type-safe metadata from validation type
+// GenerateMetadataFromValidation creates type-safe metadata from validation
+// type: the validation is the function, and the features are those the
+// validation feature table (see ValidationFeatureMap) maps it to
 func (fg *FlatGenerator) GenerateMetadataFromValidation(validationName string) (functions []string, features []string) {
-	// Map validation names to functions
 	functions = []string{validationName}
-
-	// Initialize features as empty slice, never nil
-	features = make([]string, 0)
-
-	// Map validation names to required features
-	switch validationName {
-	case "filter":
-		features = append(features, string(config.FeatureComments))
-	case "expand_dotted":
-		features = append(features, string(config.FeatureExperimentalDottedKeys))
+	for _, feature := range fg.Options.validationFeatures()[validationName] {
+		features = append(features, string(feature))
 	}
-
 	return functions, features
 }
 
@@ -264,7 +621,51 @@ func ExtractMetadataFromTags(tags []string) (functions, features, behaviors, var
 	return
 }
 
-// ValidateGenerated validates the generated flat format files
+// LegacyTags returns the tags ExtractMetadataFromTags turns back into the
+// given typed metadata
+func LegacyTags(functions, features, behaviors, variants []string) []string {
+	tags := make([]string, 0, len(functions)+len(features)+len(behaviors)+len(variants))
+	for _, group := range []struct {
+		prefix string
+		values []string
+	}{
+		{"function:", functions},
+		{"feature:", features},
+		{"behavior:", behaviors},
+		{"variant:", variants},
+	} {
+		for _, value := range group.values {
+			tags = append(tags, group.prefix+value)
+		}
+	}
+	return tags
+}
+
+// SyncLegacyTags returns a test's Meta.Tags with any legacy tags replaced by
+// ones matching its typed metadata; other tags keep their order
+func SyncLegacyTags(test types.TestCase) []string {
+	var tags []string
+	for _, tag := range test.Meta.Tags {
+		if !IsLegacyTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return append(tags, LegacyTags(test.Functions, test.Features, test.Behaviors, test.Variants)...)
+}
+
+// IsLegacyTag reports whether a tag carries typed metadata in the old
+// prefix form
+func IsLegacyTag(tag string) bool {
+	for _, prefix := range []string{"function:", "feature:", "behavior:", "variant:"} {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGenerated validates the generated flat format files and checks
+// that every test's source_test still exists in SourceDir
 func (fg *FlatGenerator) ValidateGenerated() error {
 	pattern := filepath.Join(fg.OutputDir, "*.json")
 	files, err := filepath.Glob(pattern)
@@ -278,11 +679,56 @@ func (fg *FlatGenerator) ValidateGenerated() error {
 		}
 	}
 
-	return nil
+	return fg.checkSourceLinks(files)
+}
+
+// checkSourceLinks reports generated tests whose source test is gone, such
+// as output left behind after a source file was renamed or deleted
+func (fg *FlatGenerator) checkSourceLinks(generatedFiles []string) error {
+	if _, err := os.Stat(fg.SourceDir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	sourceFiles, err := fg.sourceFiles()
+	if err != nil {
+		return fmt.Errorf("failed to find source files: %w", err)
+	}
+
+	testLoader := loader.NewTestLoader("", config.ImplementationConfig{})
+	testLoader.Logger = fg.Options.Logger
+	load := func(files []string, opts loader.LoadOptions) ([]types.TestCase, error) {
+		var tests []types.TestCase
+		for _, file := range files {
+			suite, err := testLoader.LoadTestFile(file, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", file, err)
+			}
+			tests = append(tests, suite.Tests...)
+		}
+		return tests, nil
+	}
+	sources, err := load(sourceFiles, loader.LoadOptions{Format: fg.Options.SourceFormat, FilterMode: loader.FilterAll, LenientUTF8: fg.Options.LenientUTF8})
+	if err != nil {
+		return err
+	}
+	flat, err := load(generatedFiles, loader.LoadOptions{Format: loader.FormatFlat, FilterMode: loader.FilterAll, LazyExpected: true})
+	if err != nil {
+		return err
+	}
+
+	dangling := loader.CheckSourceLinks(sources, flat).Dangling
+	if len(dangling) == 0 {
+		return nil
+	}
+	names := make([]string, len(dangling))
+	for i, link := range dangling {
+		names[i] = fmt.Sprintf("%s (%s)", link.Test, link.SourceTest)
+	}
+	return fmt.Errorf("%d generated tests refer to missing source tests: %s", len(dangling), strings.Join(names, ", "))
 }
 
 // applyFiltering applies generation options to filter tests
 func (fg *FlatGenerator) applyFiltering(tests []types.TestCase) []types.TestCase {
+	log := fg.logger()
 	var filtered []types.TestCase
 
 	for _, test := range tests {
@@ -298,6 +744,7 @@ func (fg *FlatGenerator) applyFiltering(tests []types.TestCase) []types.TestCase
 				}
 			}
 			if skip {
+				log.Debug("rejected test", "test", test.Name, "reason", "skipped function "+test.Validation)
 				continue
 			}
 		}
@@ -312,10 +759,16 @@ func (fg *FlatGenerator) applyFiltering(tests []types.TestCase) []types.TestCase
 				}
 			}
 			if !include {
+				log.Debug("rejected test", "test", test.Name, "reason", "function "+test.Validation+" not selected")
 				continue
 			}
 		}
 
+		if fg.include != nil && !fg.include(test) {
+			log.Debug("rejected test", "test", test.Name, "reason", "not selected")
+			continue
+		}
+
 		filtered = append(filtered, test)
 	}
 
@@ -351,30 +804,17 @@ func (fg *FlatGenerator) validateFile(filename string) error {
 func (fg *FlatGenerator) convertToFlatFormat(test types.TestCase) generated.GeneratedFormatSimpleJsonTestsElem {
 	// Create the proper Expected structure based on validation type
 	expected := fg.createExpectedStructure(test.Validation, test.Expected)
-
-	// Convert behaviors, features, variants to the generated enum types
-	// Ensure these are never nil - initialize as empty if needed
-	testBehaviors := test.Behaviors
-	if testBehaviors == nil {
-		testBehaviors = make([]string, 0)
-	}
-	testFeatures := test.Features
-	if testFeatures == nil {
-		testFeatures = make([]string, 0)
-	}
-	testVariants := test.Variants
-	if testVariants == nil {
-		testVariants = make([]string, 0)
-	}
-	testFunctions := test.Functions
-	if testFunctions == nil {
-		testFunctions = make([]string, 0)
+	if test.Tolerance != 0 {
+		tolerance := test.Tolerance
+		expected.Tolerance = &tolerance
 	}
 
-	behaviors := fg.convertBehaviors(testBehaviors)
-	features := fg.convertFeatures(testFeatures)
-	variants := fg.convertVariants(testVariants)
-	functions := fg.convertFunctions(testFunctions)
+	// Convert behaviors, features, variants to the generated enum types
+	test.Normalize()
+	behaviors := fg.convertBehaviors(test.Behaviors)
+	features := fg.convertFeatures(test.Features)
+	variants := fg.convertVariants(test.Variants)
+	functions := fg.convertFunctions(test.Functions)
 
 	// Create the flat test directly using the generated type
 	flatTest := generated.GeneratedFormatSimpleJsonTestsElem{
@@ -389,23 +829,53 @@ func (fg *FlatGenerator) convertToFlatFormat(test types.TestCase) generated.Gene
 		Args:       fg.getArgsForValidation(test.Validation, test.Args),
 		SourceTest: &test.SourceTest,
 	}
+	if test.Default != nil && types.IsTypedAccessFunction(test.Validation) {
+		flatTest.Default = test.Default
+	}
+	if test.ExpectError {
+		flatTest.ExpectError = &test.ExpectError
+		flatTest.Expected.Error = &test.ExpectError
+	}
+	if test.ErrorType != "" {
+		flatTest.ErrorType = &test.ErrorType
+	}
+	if test.UnicodeNormalization != "" {
+		normalization := generated.GeneratedFormatSimpleJsonTestsElemUnicodeNormalization(test.UnicodeNormalization)
+		flatTest.UnicodeNormalization = &normalization
+	}
+	if test.Suite != "" {
+		flatTest.Suite = &test.Suite
+	}
+	if fg.Options.EmitLegacyTags && len(test.Meta.Tags) > 0 {
+		flatTest.Meta = &generated.GeneratedFormatSimpleJsonTestsElemMeta{Tags: test.Meta.Tags}
+	}
+	if fg.Options.EmitLevels && test.Meta.Level != 0 {
+		level := test.Meta.Level
+		flatTest.Level = &level
+	}
+	if limits := test.Meta.Limits; limits != nil {
+		flatTest.Limits = &generated.GeneratedFormatSimpleJsonTestsElemLimits{}
+		if limits.TimeoutMS != 0 {
+			flatTest.Limits.TimeoutMs = &limits.TimeoutMS
+		}
+		if limits.Slow {
+			flatTest.Limits.Slow = &limits.Slow
+		}
+	}
+	if test.Skip != nil {
+		flatTest.Skip = &generated.GeneratedFormatSimpleJsonTestsElemSkip{Reason: test.Skip.Reason}
+		if test.Skip.Until != "" {
+			flatTest.Skip.Until = &test.Skip.Until
+		}
+	}
 
 	return flatTest
 }
 
-// getArgsForValidation returns args only for typed access functions, nil for others
+// getArgsForValidation returns args only for functions that take them, nil for others
 func (fg *FlatGenerator) getArgsForValidation(validation string, args []string) []string {
-	// Only typed access functions need args field
-	typedAccessFunctions := map[string]bool{
-		"get_string": true,
-		"get_int":    true,
-		"get_bool":   true,
-		"get_float":  true,
-		"get_list":   true,
-	}
-
-	if typedAccessFunctions[validation] {
-		// For typed access functions, return args (even if empty)
+	if types.ArityOf(validation).TakesArgs() {
+		// For functions taking args, return them (even if empty)
 		return args
 	}
 
@@ -418,19 +888,24 @@ func (fg *FlatGenerator) createExpectedStructure(validation string, data interfa
 	expected := generated.GeneratedFormatSimpleJsonTestsElemExpected{}
 
 	switch validation {
-	case "parse", "parse_indented", "filter", "compose", "expand_dotted":
-		// These validations expect entries (key-value pairs)
+	case "parse", "parse_indented", "filter", "extract_comments", "combine", "compose", "expand_dotted":
+		// These validations expect entries (key-value pairs), kept in order
+		// and never keyed, since CCL allows a key to repeat
 		if entries, ok := data.([]interface{}); ok {
 			expected.Count = len(entries)
 			var entryList []generated.GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem
-			for _, entry := range entries {
+			for i, entry := range entries {
 				if entryMap, ok := entry.(map[string]interface{}); ok {
 					if key, hasKey := entryMap["key"].(string); hasKey {
 						if value, hasValue := entryMap["value"].(string); hasValue {
-							entryList = append(entryList, generated.GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem{
+							elem := generated.GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem{
 								Key:   key,
 								Value: value,
-							})
+							}
+							if fg.Options.EntryProvenance {
+								elem.SrcIndex = &i
+							}
+							entryList = append(entryList, elem)
 						}
 					}
 				}
@@ -451,6 +926,14 @@ func (fg *FlatGenerator) createExpectedStructure(validation string, data interfa
 			expected.Count = len(list)
 			expected.List = list
 		}
+	case "pretty_print", "canonical_format":
+		// Formatting expects the rendered text
+		expected.Count = 1
+		if text, ok := data.(string); ok {
+			expected.Text = &text
+		} else {
+			expected.Value = data
+		}
 	default:
 		// Default case - try to infer from data type
 		expected.Count = 1
@@ -471,9 +954,6 @@ func (fg *FlatGenerator) convertBehaviors(behaviors []string) []generated.Genera
 
 func (fg *FlatGenerator) convertFeatures(features []string) []string {
 	// Features is just []string in the simplified schema (no enum constraints)
-	if features == nil {
-		return make([]string, 0)
-	}
 	return features
 }
 
@@ -523,9 +1003,12 @@ func camelToSnake(s string) string {
 
 // ValidationComponents represents the parsed components of a validation value
 type ValidationComponents struct {
-	Expected interface{}
-	Args     []string
-	Error    bool
+	Expected  interface{}
+	Args      []string
+	Error     bool
+	ErrorType string
+	Tolerance float64
+	Default   *string
 }
 
 // parseValidationValue parses a validation value that may be either:
@@ -536,8 +1019,6 @@ func parseValidationValue(value interface{}) ValidationComponents {
 	if validationMap, ok := value.(map[string]interface{}); ok {
 		result := ValidationComponents{
 			Expected: value, // Default to the whole object
-			Args:     []string{},
-			Error:    false,
 		}
 
 		// Extract expect field if present
@@ -564,6 +1045,17 @@ func parseValidationValue(value interface{}) ValidationComponents {
 				result.Error = errorBool
 			}
 		}
+		if errorType, ok := validationMap["error_type"].(string); ok {
+			result.ErrorType = errorType
+		}
+
+		// Extract get_float tolerance if present
+		if tolerance, ok := validationMap["tolerance"].(float64); ok {
+			result.Tolerance = tolerance
+		}
+		if fallback, ok := validationMap["default"].(string); ok {
+			result.Default = &fallback
+		}
 
 		return result
 	}
@@ -571,7 +1063,6 @@ func parseValidationValue(value interface{}) ValidationComponents {
 	// Fallback to treating value as expected result (legacy format)
 	return ValidationComponents{
 		Expected: value,
-		Args:     []string{},
 		Error:    expectErrorFromValue(value),
 	}
 }
@@ -615,15 +1106,18 @@ var behaviorFunctionMap = map[string][]string{
 	"array_order_lexicographic": {"build_hierarchy", "get_list"},
 }
 
+// BehaviorFunctions returns the functions a behavior is tagged on in
+// generated tests, or false for a global behavior that applies to all
+func BehaviorFunctions(behavior string) ([]string, bool) {
+	functions, ok := behaviorFunctionMap[behavior]
+	return slices.Clone(functions), ok
+}
+
 // filterBehaviorsForFunction filters behaviors to only include those relevant
 // to the given validation function. Behaviors not in behaviorFunctionMap are
 // considered global and always included.
 func filterBehaviorsForFunction(behaviors []string, validationName string) []string {
-	if behaviors == nil {
-		return make([]string, 0)
-	}
-
-	filtered := make([]string, 0, len(behaviors))
+	var filtered []string
 	for _, behavior := range behaviors {
 		applicableFunctions, hasMapping := behaviorFunctionMap[behavior]
 		if !hasMapping {
@@ -1,10 +1,18 @@
 package generator
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/loader"
@@ -29,7 +37,7 @@ func setupGeneratorTestData(t *testing.T) (string, string) {
 	compactTests := []loader.CompactTest{
 		{
 			Name:     "multi_validation_test",
-			Input:    "key = value\ncount = 42",
+			Inputs:   []string{"key = value\ncount = 42"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{
@@ -60,7 +68,7 @@ func setupGeneratorTestData(t *testing.T) (string, string) {
 		},
 		{
 			Name:     "single_validation_test",
-			Input:    "flag = true",
+			Inputs:   []string{"flag = true"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{
@@ -86,7 +94,7 @@ func setupGeneratorTestData(t *testing.T) (string, string) {
 	compactTests2 := []loader.CompactTest{
 		{
 			Name:     "compact_test",
-			Input:    "name = test",
+			Inputs:   []string{"name = test"},
 			Features: []string{"multiline"},
 			Tests: []loader.CompactValidation{
 				{
@@ -116,7 +124,7 @@ func setupGeneratorTestData(t *testing.T) (string, string) {
 	propertyTests := []loader.CompactTest{
 		{
 			Name:     "property_test",
-			Input:    "a = 1",
+			Inputs:   []string{"a = 1"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{
@@ -190,7 +198,7 @@ func TestFlatGenerator_GenerateFile_SourceFormat(t *testing.T) {
 		t.Fatalf("Failed to unmarshal generated JSON: %v", err)
 	}
 
-	if wrapper.Schema != "http://json-schema.org/draft-07/schema#" {
+	if wrapper.Schema != "ccl-test-flat-format/v1" {
 		t.Errorf("Expected schema field, got %s", wrapper.Schema)
 	}
 
@@ -294,13 +302,838 @@ func TestFlatGenerator_GenerateAll_SkipPropertyTests(t *testing.T) {
 	}
 }
 
+// writeMixedSources writes five source files, of which b_bad.json and
+// d_bad.json are malformed
+func writeMixedSources(t *testing.T) (string, string) {
+	t.Helper()
+	sourceDir := t.TempDir()
+	good := `{"tests": [{"name": "t", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]}]}`
+	for name, content := range map[string]string{
+		"a_good.json": good,
+		"b_bad.json":  `{"tests": [`,
+		"c_good.json": good,
+		"d_bad.json":  `{"tests": "none"}`,
+		"e_good.json": good,
+	} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sourceDir, filepath.Join(t.TempDir(), "out")
+}
+
+func TestFlatGenerator_GenerateAll_StopsAtFirstFailure(t *testing.T) {
+	sourceDir, outputDir := writeMixedSources(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+
+	err := gen.GenerateAll()
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) || filepath.Base(fileErr.Source) != "b_bad.json" || !errors.Is(err, loader.ErrParse) {
+		t.Fatalf("Expected a FileError for b_bad.json wrapping ErrParse, got %v", err)
+	}
+	if failures := gen.Report().Failures; len(failures) != 1 {
+		t.Errorf("Expected 1 failure in the report, got %+v", failures)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "c_good.json")); !os.IsNotExist(err) {
+		t.Error("Expected files after the failure not to be generated")
+	}
+}
+
+func TestFlatGenerator_GenerateAll_ContinueOnError(t *testing.T) {
+	sourceDir, outputDir := writeMixedSources(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, ContinueOnError: true})
+
+	err := gen.GenerateAll()
+	if err == nil || !errors.Is(err, loader.ErrParse) {
+		t.Fatalf("Expected an error wrapping ErrParse, got %v", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("Expected two joined errors, got %v", err)
+	}
+	var failed []string
+	for _, e := range joined.Unwrap() {
+		var fileErr *FileError
+		if !errors.As(e, &fileErr) {
+			t.Fatalf("Expected a FileError, got %v", e)
+		}
+		failed = append(failed, filepath.Base(fileErr.Source))
+	}
+	if !slices.Equal(failed, []string{"b_bad.json", "d_bad.json"}) {
+		t.Errorf("Expected b_bad.json and d_bad.json to fail, got %v", failed)
+	}
+
+	report := gen.Report()
+	if len(report.Failures) != 2 || filepath.Base(report.Failures[1].Source) != "d_bad.json" || report.Failures[1].Error == "" {
+		t.Errorf("Expected both failures in the report, got %+v", report.Failures)
+	}
+	if len(report.Files) != 3 {
+		t.Errorf("Expected 3 generated files in the report, got %+v", report.Files)
+	}
+	for _, name := range []string{"a_good.json", "c_good.json", "e_good.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+}
+
+func TestFlatGenerator_GenerateAll_Progress(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	sources, err := filepath.Glob(filepath.Join(sourceDir, "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list sources: %v", err)
+	}
+
+	var done []int
+	opts := GenerateOptions{
+		SourceFormat:      FormatCompact,
+		SkipPropertyTests: true,
+		Progress: func(stage string, n, total int) {
+			if stage != loader.StageGenerate || total != len(sources) {
+				t.Errorf("Unexpected progress %s %d/%d", stage, n, total)
+			}
+			done = append(done, n)
+		},
+	}
+	if err := NewFlatGenerator(sourceDir, outputDir, opts).GenerateAll(); err != nil {
+		t.Fatalf("Failed to generate all files: %v", err)
+	}
+
+	// Skipped property files still count toward the total
+	if len(done) != len(sources)+1 {
+		t.Fatalf("Expected %d calls, got %v", len(sources)+1, done)
+	}
+	for i, n := range done {
+		if n != i {
+			t.Errorf("Expected done values 0..%d in order, got %v", len(sources), done)
+			break
+		}
+	}
+}
+
+func TestFlatGenerator_FlattenFile(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	sourceFile := filepath.Join(sourceDir, "test-source.json")
+
+	flattened, err := gen.FlattenFile(sourceFile)
+	if err != nil {
+		t.Fatalf("FlattenFile failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "test-source.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected FlattenFile not to write output, got %v", err)
+	}
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	written, err := os.ReadFile(filepath.Join(outputDir, "test-source.json"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if string(flattened) != string(written) {
+		t.Error("Expected FlattenFile to return what GenerateFile writes")
+	}
+	if _, err := gen.FlattenFile(filepath.Join(sourceDir, "missing.json")); !errors.Is(err, ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_EmitLevels(t *testing.T) {
+	sourceDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_levels.json")
+	source := `{"tests": [{"name": "basic", "inputs": ["a = b"], "tests": [
+		{"function": "parse", "expect": [{"key": "a", "value": "b"}]},
+		{"function": "get_string", "args": ["a"], "expect": "b"}
+	]}]}`
+	if err := os.WriteFile(sourceFile, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+
+	for _, emit := range []bool{false, true} {
+		outputDir := t.TempDir()
+		gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, EmitLevels: emit})
+		if err := gen.GenerateFile(sourceFile); err != nil {
+			t.Fatalf("Failed to generate: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, "api_levels.json"))
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		var output struct {
+			Tests []map[string]interface{} `json:"tests"`
+		}
+		if err := json.Unmarshal(data, &output); err != nil {
+			t.Fatalf("Failed to parse output: %v", err)
+		}
+
+		var levels []interface{}
+		for _, test := range output.Tests {
+			levels = append(levels, test["level"])
+		}
+		// Each validation is classified on its own: parse is level 1, get_string level 2
+		want := []interface{}{nil, nil}
+		if emit {
+			want = []interface{}{float64(1), float64(2)}
+		}
+		if !reflect.DeepEqual(levels, want) {
+			t.Errorf("EmitLevels %v: expected levels %v, got %v", emit, want, levels)
+		}
+	}
+}
+
+// recordingHandler is a slog.Handler that keeps every record it handles
+type recordingHandler struct {
+	records *[]slog.Record
+}
+
+func (h recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r.Clone())
+	return nil
+}
+
+func (h recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestFlatGenerator_GenerateAll_LogsSkippedFiles(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	var records []slog.Record
+	generator := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{
+		SourceFormat:      FormatCompact,
+		SkipPropertyTests: true,
+		Logger:            slog.New(recordingHandler{records: &records}),
+	})
+	if err := generator.GenerateAll(); err != nil {
+		t.Fatalf("Failed to generate all files: %v", err)
+	}
+
+	var skipped []map[string]string
+	for _, r := range records {
+		if r.Message != "skipped source file" {
+			continue
+		}
+		if r.Level != slog.LevelDebug {
+			t.Errorf("Expected skip at debug, got %v", r.Level)
+		}
+		attrs := make(map[string]string)
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.String()
+			return true
+		})
+		skipped = append(skipped, attrs)
+	}
+	want := []map[string]string{{"file": filepath.Join(sourceDir, "property-test.json"), "reason": "property test"}}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Expected skip records %v, got %v", want, skipped)
+	}
+}
+
+func TestFlatGenerator_GenerateAll_Scan(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source")
+	os.MkdirAll(sourceDir, 0755)
+	source := []byte(`{"tests": [{"name": "a", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": []}]}]}`)
+	os.WriteFile(filepath.Join(sourceDir, "api.json"), source, 0644)
+	os.WriteFile(filepath.Join(sourceDir, ".#api.json"), []byte("{"), 0644)
+	os.WriteFile(filepath.Join(root, "shared.json"), source, 0644)
+	if err := os.Symlink(filepath.Join(root, "shared.json"), filepath.Join(sourceDir, "shared.json")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	os.Symlink(filepath.Join(sourceDir, "loop.json"), filepath.Join(sourceDir, "loop.json"))
+
+	generated := func(scan *loader.ScanOptions) []string {
+		outputDir := t.TempDir()
+		gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, Scan: scan})
+		if err := gen.GenerateAll(); err != nil {
+			t.Fatalf("GenerateAll failed: %v", err)
+		}
+		entries, _ := os.ReadDir(outputDir)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	if got := generated(nil); !reflect.DeepEqual(got, []string{"api.json"}) {
+		t.Errorf("Expected hidden files and symlinks skipped by default, got %v", got)
+	}
+	if got := generated(&loader.ScanOptions{SkipHidden: true, FollowSymlinks: true}); !reflect.DeepEqual(got, []string{"api.json", "shared.json"}) {
+		t.Errorf("Expected the symlinked source generated, got %v", got)
+	}
+}
+
+func TestFlatGenerator_GenerateAll_Incremental(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	opts := GenerateOptions{SourceFormat: FormatCompact, Incremental: true}
+
+	if err := NewFlatGenerator(sourceDir, outputDir, opts).GenerateAll(); err != nil {
+		t.Fatalf("Initial generation failed: %v", err)
+	}
+
+	// Mark one output stale and age the other outputs past their sources
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	staleOutput := filepath.Join(outputDir, "test-compact.json")
+	freshOutput := filepath.Join(outputDir, "test-source.json")
+	if err := os.WriteFile(freshOutput, []byte("sentinel"), 0644); err != nil {
+		t.Fatalf("Failed to write sentinel: %v", err)
+	}
+	if err := os.Chtimes(freshOutput, future, future); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+	if err := os.WriteFile(staleOutput, []byte("stale"), 0644); err != nil {
+		t.Fatalf("Failed to write stale output: %v", err)
+	}
+	if err := os.Chtimes(staleOutput, past, past); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	if err := NewFlatGenerator(sourceDir, outputDir, opts).GenerateAll(); err != nil {
+		t.Fatalf("Incremental generation failed: %v", err)
+	}
+
+	if data, _ := os.ReadFile(freshOutput); string(data) != "sentinel" {
+		t.Error("Expected up-to-date output to be left alone")
+	}
+	if data, _ := os.ReadFile(staleOutput); string(data) == "stale" {
+		t.Error("Expected stale output to be regenerated")
+	}
+}
+
+func TestFlatGenerator_GenerateFile_NormalizeInput(t *testing.T) {
+	sourceDir := t.TempDir()
+	source := `{"tests": [
+		{"name": "plain", "inputs": ["a = 1\r\nb = 2"], "tests": [{"function": "parse", "expect": []}]},
+		{"name": "pinned", "inputs": ["a = 1\r\nb = 2"], "behaviors": ["crlf_preserve_literal"], "tests": [{"function": "parse", "expect": []}]}
+	]}`
+	sourceFile := filepath.Join(sourceDir, "api_crlf.json")
+	os.WriteFile(sourceFile, []byte(source), 0644)
+
+	for mode, want := range map[loader.InputNormalization][]string{
+		loader.NormalizeNone:             {"a = 1\r\nb = 2", "a = 1\r\nb = 2"},
+		loader.NormalizeLFOnly:           {"a = 1\nb = 2", "a = 1\nb = 2"},
+		loader.NormalizePreserveDeclared: {"a = 1\nb = 2", "a = 1\r\nb = 2"},
+	} {
+		outputDir := t.TempDir()
+		gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, NormalizeInput: mode})
+		if err := gen.GenerateFile(sourceFile); err != nil {
+			t.Fatalf("mode %d: GenerateFile failed: %v", mode, err)
+		}
+		data, _ := os.ReadFile(filepath.Join(outputDir, "api_crlf.json"))
+		var suite types.TestSuite
+		if err := json.Unmarshal(data, &suite); err != nil {
+			t.Fatalf("mode %d: failed to parse output: %v", mode, err)
+		}
+		for i, test := range suite.Tests {
+			if test.Inputs[0] != want[i] {
+				t.Errorf("mode %d, %s: expected %q, got %q", mode, test.Name, want[i], test.Inputs[0])
+			}
+		}
+	}
+}
+
+func TestFlatGenerator_GenerateFile_RejectsBadArgs(t *testing.T) {
+	sourceDir := t.TempDir()
+	source := `{"tests": [
+		{"name": "good", "inputs": ["a = 1"], "tests": [{"function": "get_int", "args": ["a"], "expect": 1}]},
+		{"name": "no_path", "inputs": ["a = 1"], "tests": [{"function": "get_int", "expect": 1}]},
+		{"name": "parse_path", "inputs": ["a = 1"], "tests": [{"function": "parse", "args": ["a"], "expect": []}]}
+	]}`
+	sourceFile := filepath.Join(sourceDir, "api_args.json")
+	os.WriteFile(sourceFile, []byte(source), 0644)
+
+	outputDir := t.TempDir()
+	var records []slog.Record
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, Logger: slog.New(recordingHandler{records: &records})})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(outputDir, "api_args.json"))
+	var suite types.TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	if len(suite.Tests) != 2 || suite.Tests[0].Name != "good_get_int" || suite.Tests[1].Name != "parse_path_parse" {
+		t.Errorf("Expected good_get_int and parse_path_parse, got %+v", suite.Tests)
+	}
+	var warned int
+	for _, r := range records {
+		if r.Message == "rejected test" && r.Level == slog.LevelWarn {
+			warned++
+		}
+	}
+	if warned != 1 {
+		t.Errorf("Expected 1 rejected test warned about, got %d", warned)
+	}
+
+	gen.Options.StrictArgs = true
+	err := gen.GenerateFile(sourceFile)
+	if err == nil || !strings.Contains(err.Error(), "get_int requires args") {
+		t.Errorf("Expected a strict arity error, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_DroppedArgs(t *testing.T) {
+	sourceDir := t.TempDir()
+	source := `{"tests": [
+		{"name": "meant_get_string", "inputs": ["a = 1"], "tests": [
+			{"function": "parse", "args": ["a"], "expect": [{"key": "a", "value": "1"}]},
+			{"function": "get_string", "args": ["a"], "expect": "1"}
+		]}
+	]}`
+	sourceFile := filepath.Join(sourceDir, "api_args.json")
+	os.WriteFile(sourceFile, []byte(source), 0644)
+
+	var records []slog.Record
+	gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact, Logger: slog.New(recordingHandler{records: &records})})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	var warning *slog.Record
+	for i, r := range records {
+		if r.Message == "dropped args" && r.Level == slog.LevelWarn {
+			warning = &records[i]
+		}
+	}
+	if warning == nil {
+		t.Fatal("Expected a dropped args warning")
+	}
+	attrs := make(map[string]string)
+	warning.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	if attrs["test"] != "meant_get_string" || attrs["validation"] != "parse" || attrs["args"] != "[a]" {
+		t.Errorf("Expected the warning to name the test, validation, and args, got %v", attrs)
+	}
+
+	files := gen.Report().Files
+	want := []DroppedArgs{{Test: "meant_get_string", Validation: "parse", Args: []string{"a"}}}
+	if len(files) != 1 || !reflect.DeepEqual(files[0].DroppedArgs, want) || files[0].Tests != 2 {
+		t.Errorf("Expected both tests written and the dropped args reported, got %+v", files)
+	}
+
+	gen.Options.StrictArgs = true
+	err := gen.GenerateFile(sourceFile)
+	if err == nil || !strings.Contains(err.Error(), "source test meant_get_string, validation parse: unexpected-args: parse does not take args") {
+		t.Errorf("Expected a strict args error, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_ExpectedShape(t *testing.T) {
+	badSources := map[string]string{
+		"entries":   `{"function": "parse", "expect": "a = 1"}`,
+		"object":    `{"function": "build_hierarchy", "expect": [{"key": "a", "value": "1"}]}`,
+		"array":     `{"function": "get_list", "args": ["a"], "expect": "1"}`,
+		"scalar":    `{"function": "get_int", "args": ["a"], "expect": [1]}`,
+		"bad entry": `{"function": "filter", "expect": ["a"]}`,
+	}
+	for name, validation := range badSources {
+		t.Run(name, func(t *testing.T) {
+			sourceDir := t.TempDir()
+			sourceFile := filepath.Join(sourceDir, "api_shape.json")
+			os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "bad", "inputs": ["a = 1"], "tests": [`+validation+`]}]}`), 0644)
+
+			gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact})
+			err := gen.GenerateFile(sourceFile)
+			if err == nil || !strings.Contains(err.Error(), "source test bad, validation ") {
+				t.Fatalf("Expected a shape error naming the source test, got %v", err)
+			}
+
+			var records []slog.Record
+			gen.Options.LenientShapes = true
+			gen.Options.Logger = slog.New(recordingHandler{records: &records})
+			if err := gen.GenerateFile(sourceFile); err != nil {
+				t.Fatalf("Expected lenient generation to succeed, got %v", err)
+			}
+			warned := false
+			for _, r := range records {
+				warned = warned || (r.Message == "expected shape mismatch" && r.Level == slog.LevelWarn)
+			}
+			if !warned {
+				t.Error("Expected a shape mismatch warning")
+			}
+		})
+	}
+}
+
+func TestFlatGenerator_GenerateFile_UnknownBehavior(t *testing.T) {
+	sourceDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_behavior.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "typo", "inputs": ["a = true"], "behaviors": ["boolean_leniant"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "true"}]}]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact})
+	err := gen.GenerateFile(sourceFile)
+	want := `source test typo: unknown-behavior: unknown behavior "boolean_leniant" (did you mean "boolean_lenient"?)`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("Expected error containing %q, got %v", want, err)
+	}
+
+	var records []slog.Record
+	gen.Options.LenientBehaviors = true
+	gen.Options.Logger = slog.New(recordingHandler{records: &records})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("Expected lenient generation to succeed, got %v", err)
+	}
+	warned := false
+	for _, r := range records {
+		warned = warned || (r.Message == "unknown behavior" && r.Level == slog.LevelWarn)
+	}
+	if !warned {
+		t.Error("Expected an unknown behavior warning")
+	}
+}
+
+func TestFlatGenerator_GenerateFile_Encoding(t *testing.T) {
+	sourceDir := t.TempDir()
+	bom := filepath.Join(sourceDir, "api_bom.json")
+	os.WriteFile(bom, []byte("\xEF\xBB\xBF"+`{"tests": [{"name": "a", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": []}]}]}`), 0644)
+	invalid := filepath.Join(sourceDir, "api_invalid.json")
+	os.WriteFile(invalid, []byte("{\"tests\": [{\"name\": \"a\", \"inputs\": [\"a = \xFF\"], \"tests\": [{\"function\": \"parse\", \"expect\": []}]}]}"), 0644)
+
+	gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(bom); err != nil {
+		t.Errorf("Expected a BOM-prefixed source to generate, got %v", err)
+	}
+	var utf8Err *loader.InvalidUTF8Error
+	if err := gen.GenerateFile(invalid); !errors.As(err, &utf8Err) {
+		t.Errorf("Expected an InvalidUTF8Error, got %v", err)
+	}
+	gen.Options.LenientUTF8 = true
+	if err := gen.GenerateFile(invalid); err != nil {
+		t.Errorf("Expected lenient generation to succeed, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_HierarchyKeyOrder(t *testing.T) {
+	sourceDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_hierarchy.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "order", "inputs": ["z = 1"], "tests": [
+		{"function": "build_hierarchy", "expect": {"zeta": "1", "alpha": {"y": "2", "x": "3"}, "mid": "4"}}
+	]}]}`), 0644)
+
+	var outputs []string
+	for i := 0; i < 2; i++ {
+		outputDir := t.TempDir()
+		gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+		if err := gen.GenerateFile(sourceFile); err != nil {
+			t.Fatalf("GenerateFile failed: %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(outputDir, "api_hierarchy.json"))
+		outputs = append(outputs, string(data))
+	}
+	if outputs[0] != outputs[1] {
+		t.Error("Expected regenerated output to be identical")
+	}
+
+	order := []string{`"zeta"`, `"alpha"`, `"y"`, `"x"`, `"mid"`}
+	last := -1
+	for _, key := range order {
+		index := strings.Index(outputs[0], key)
+		if index <= last {
+			t.Fatalf("Expected keys in source order %v, got:\n%s", order, outputs[0])
+		}
+		last = index
+	}
+}
+
+func TestFlatGenerator_GenerateFile_FloatTolerance(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_float.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "pi", "inputs": ["pi = 3.14159"], "tests": [
+		{"function": "get_float", "args": ["pi"], "expect": 3.14, "tolerance": 0.01},
+		{"function": "get_string", "args": ["pi"], "expect": "3.14159"}
+	]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(outputDir, "api_float.json"))
+	if strings.Count(string(data), `"tolerance"`) != 1 {
+		t.Fatalf("Expected tolerance only on the get_float test, got:\n%s", data)
+	}
+
+	tests, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "api_float.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+	for _, test := range tests.Tests {
+		want := 0.0
+		if test.Validation == "get_float" {
+			want = 0.01
+		}
+		if test.Tolerance != want {
+			t.Errorf("%s: expected tolerance %v, got %v", test.Name, want, test.Tolerance)
+		}
+	}
+}
+
+func TestFlatGenerator_GenerateAll_FileSkipFunctions(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	validations := `[
+		{"function": "parse", "expect": [{"key": "a", "value": "b"}]},
+		{"function": "get_string", "args": ["a"], "expect": "b"}
+	]`
+	os.WriteFile(filepath.Join(sourceDir, "api_internal.json"), []byte(`{"generator": {"skip_functions": ["get_string"]},
+		"tests": [{"name": "diag", "inputs": ["a = b"], "tests": `+validations+`}]}`), 0644)
+	os.WriteFile(filepath.Join(sourceDir, "api_public.json"), []byte(`{
+		"tests": [{"name": "pub", "inputs": ["a = b"], "tests": `+validations+`}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateAll(); err != nil {
+		t.Fatalf("GenerateAll failed: %v", err)
+	}
+
+	for file, want := range map[string]string{"api_internal.json": "parse", "api_public.json": "parse,get_string"} {
+		suite, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, file), loader.LoadOptions{Format: loader.FormatFlat})
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", file, err)
+		}
+		var got []string
+		for _, test := range suite.Tests {
+			got = append(got, test.Validation)
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("%s: expected %s, got %v", file, want, got)
+		}
+	}
+
+	want := []FileReport{
+		{Source: filepath.Join(sourceDir, "api_internal.json"), Tests: 1, SkipFunctions: []config.CCLFunction{config.FunctionGetString}, FileSkipped: 1},
+		{Source: filepath.Join(sourceDir, "api_public.json"), Tests: 2},
+	}
+	if got := gen.Report().Files; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected report %+v, got %+v", want, got)
+	}
+
+	// Regenerating a file replaces its entry
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "api_public.json")); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if got := len(gen.Report().Files); got != 2 {
+		t.Errorf("Expected 2 report entries after regenerating, got %d", got)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_UnknownFileSkipFunction(t *testing.T) {
+	sourceDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_typo.json")
+	os.WriteFile(sourceFile, []byte(`{"generator": {"skip_functions": ["get_strnig"]},
+		"tests": [{"name": "a", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "b"}]}]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact})
+	err := gen.GenerateFile(sourceFile)
+	if err == nil || !strings.Contains(err.Error(), `unknown function "get_strnig"`) {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_CombineInputs(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_combine.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "merge", "inputs": ["a = 1", "b = 2"], "tests": [
+		{"function": "combine", "expect": [{"key": "a", "value": "1"}, {"key": "b", "value": "2"}]}
+	]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(outputDir, "api_combine.json"))
+	var wrapper generated.GeneratedFormatSimpleJson
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		t.Fatalf("Failed to parse generated file: %v", err)
+	}
+	test := wrapper.Tests[0]
+	if len(test.Inputs) != 2 || test.Expected.Count != 2 || len(test.Expected.Entries) != 2 {
+		t.Errorf("Expected both inputs and 2 structured entries, got %+v", test)
+	}
+
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "merge", "inputs": ["a = 1"], "tests": [
+		{"function": "combine", "expect": [{"key": "a", "value": "1"}]}
+	]}]}`), 0644)
+	if err := gen.GenerateFile(sourceFile); err == nil || !strings.Contains(err.Error(), "combine takes 2 inputs, got 1") {
+		t.Errorf("Expected an input count error, got %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_UnicodeNormalization(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_unicode.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [{"name": "accent", "inputs": ["k = caf\u00e9"], "unicode_normalization": "nfc", "tests": [
+		{"function": "get_string", "args": ["k"], "expect": "cafe\u0301"}
+	]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	suite, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "api_unicode.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+	if got := suite.Tests[0].UnicodeNormalization; got != types.NormalizationNFC {
+		t.Errorf("Expected nfc normalization, got %q", got)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_Skip(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_skip.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [
+		{"name": "disputed", "inputs": ["a = 1"], "skip": {"reason": "spec dispute", "until": "2025-06-01"}, "tests": [
+			{"function": "parse", "expect": [{"key": "a", "value": "1"}]},
+			{"function": "get_string", "args": ["a"], "expect": "1"}
+		]},
+		{"name": "settled", "inputs": ["b = 2"], "tests": [{"function": "parse", "expect": [{"key": "b", "value": "2"}]}]}
+	]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	testLoader := loader.NewTestLoader(outputDir, config.ImplementationConfig{
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionGetString},
+	})
+	suite, err := testLoader.LoadTestFile(filepath.Join(outputDir, "api_skip.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+
+	// Skipped tests are still compatible, but counted apart
+	if len(suite.Tests) != 3 {
+		t.Fatalf("Expected skipped tests to be kept, got %d tests", len(suite.Tests))
+	}
+	want := types.SkipInfo{Reason: "spec dispute", Until: "2025-06-01"}
+	for _, test := range suite.Tests {
+		if test.SourceTest == "disputed" && (test.Skip == nil || *test.Skip != want) {
+			t.Errorf("%s: expected skip %+v, got %+v", test.Name, want, test.Skip)
+		}
+		if test.SourceTest == "settled" && test.Skip != nil {
+			t.Errorf("%s: expected no skip, got %+v", test.Name, test.Skip)
+		}
+	}
+	if stats := testLoader.GetTestStatistics(suite.Tests); stats.SkippedTests != 2 || stats.CompatibleTests != 3 {
+		t.Errorf("Expected 2 skipped of 3 compatible tests, got %+v", stats)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_ParseError(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_errors.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [
+		{"name": "no_equals", "inputs": ["just a key"], "expect_parse_error": true, "error_type": "syntax"},
+		{"name": "missing_key", "inputs": ["a = 1"], "tests": [{"function": "get_string", "args": ["b"], "error": true}]}
+	]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	suite, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "api_errors.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+
+	got := make(map[string]types.TestCase)
+	for _, test := range suite.Tests {
+		got[test.Name] = test
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected one flat test per source test, got %d", len(suite.Tests))
+	}
+	parse := got["no_equals_parse"]
+	if parse.Validation != "parse" || !parse.ExpectError || parse.ErrorType != "syntax" {
+		t.Errorf("Expected a parse test expecting a syntax error, got %+v", parse)
+	}
+	if !reflect.DeepEqual(parse.Expected, map[string]interface{}{"count": float64(0), "error": true}) {
+		t.Errorf("Expected no entries, got %v", parse.Expected)
+	}
+	if typed := got["missing_key_get_string"]; !typed.ExpectError || typed.ErrorType != "" {
+		t.Errorf("Expected the validation's error expectation kept, got %+v", typed)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_Limits(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_slow.json")
+	os.WriteFile(sourceFile, []byte(`{"tests": [
+		{"name": "blowup", "inputs": ["a = 1"], "limits": {"timeout_ms": 5000, "slow": true}, "tests": [
+			{"function": "parse", "expect": [{"key": "a", "value": "1"}]},
+			{"function": "get_string", "args": ["a"], "expect": "1"}
+		]},
+		{"name": "quick", "inputs": ["b = 2"], "tests": [{"function": "parse", "expect": [{"key": "b", "value": "2"}]}]}
+	]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if issues, err := loader.ValidateTestFile(filepath.Join(outputDir, "api_slow.json")); err != nil || len(issues) > 0 {
+		t.Fatalf("Expected the generated file to validate, got %v, %v", issues, err)
+	}
+	suite, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "api_slow.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+
+	want := &types.Limits{TimeoutMS: 5000, Slow: true}
+	for _, test := range suite.Tests {
+		if test.SourceTest == "blowup" && !reflect.DeepEqual(test.Meta.Limits, want) {
+			t.Errorf("%s: expected limits %+v, got %+v", test.Name, want, test.Meta.Limits)
+		}
+		if test.SourceTest == "quick" && test.Meta.Limits != nil {
+			t.Errorf("%s: expected no limits, got %+v", test.Name, test.Meta.Limits)
+		}
+	}
+}
+
+func TestFlatGenerator_GenerateFile_Suites(t *testing.T) {
+	sourceDir := t.TempDir()
+	outputDir := t.TempDir()
+	sourceFile := filepath.Join(sourceDir, "api_suites.json")
+	os.WriteFile(sourceFile, []byte(`{"suites": [
+		{"suite": "core", "tests": [{"name": "a", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]}]},
+		{"suite": "typed", "tests": [{"name": "b", "inputs": ["b = 2"], "tests": [{"function": "get_int", "args": ["b"], "expect": 2}]}]}
+	]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(sourceFile); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if err := gen.ValidateGenerated(); err != nil {
+		t.Errorf("Expected generated tests to validate, got %v", err)
+	}
+	suite, err := loader.NewTestLoader(outputDir, config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "api_suites.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+	got := make(map[string]string)
+	for _, test := range suite.Tests {
+		got[test.Name] = test.Suite
+	}
+	if want := map[string]string{"a_parse": "core", "b_get_int": "typed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected suites %v, got %v", want, got)
+	}
+}
+
 func TestFlatGenerator_TransformSourceToFlat(t *testing.T) {
 	sourceDir, outputDir := setupGeneratorTestData(t)
 	generator := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{})
 
 	sourceTest := types.TestCase{
-		Name:  "transform_test",
-		Input: "key = value",
+		Name:   "transform_test",
+		Inputs: []string{"key = value"},
 		Validations: &types.ValidationSet{
 			Parse: []map[string]interface{}{
 				{"key": "key", "value": "value"},
@@ -351,7 +1184,7 @@ func TestFlatGenerator_TransformSourceToFlat_AlreadyFlat(t *testing.T) {
 	// Test with already flat test (no Validations)
 	flatTest := types.TestCase{
 		Name:       "already_flat",
-		Input:      "key = value",
+		Inputs:     []string{"key = value"},
 		Validation: "parse",
 		Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
 	}
@@ -375,8 +1208,8 @@ func TestFlatGenerator_TransformSourceToFlat_WithVariants(t *testing.T) {
 	generator := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{})
 
 	sourceTest := types.TestCase{
-		Name:  "test_with_variants",
-		Input: "key = value",
+		Name:   "test_with_variants",
+		Inputs: []string{"key = value"},
 		Validations: &types.ValidationSet{
 			Parse: []map[string]interface{}{
 				{"key": "key", "value": "value"},
@@ -420,14 +1253,28 @@ func TestFlatGenerator_TransformSourceToFlat_WithVariants(t *testing.T) {
 	}
 }
 
+func TestBehaviorFunctions(t *testing.T) {
+	functions, ok := BehaviorFunctions("boolean_strict")
+	if !ok || !slices.Equal(functions, []string{"get_bool"}) {
+		t.Errorf("Expected boolean_strict to apply to get_bool, got %v (%v)", functions, ok)
+	}
+	functions[0] = "changed"
+	if again, _ := BehaviorFunctions("boolean_strict"); again[0] != "get_bool" {
+		t.Error("Expected a copy of the mapping")
+	}
+	if _, ok := BehaviorFunctions("some_global_behavior"); ok {
+		t.Error("Expected an unmapped behavior to be global")
+	}
+}
+
 func TestFlatGenerator_BehaviorFiltering(t *testing.T) {
 	sourceDir, outputDir := setupGeneratorTestData(t)
 	generator := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{})
 
 	// Test that boolean_strict only applies to get_bool, not parse
 	sourceTest := types.TestCase{
-		Name:  "test_boolean_behavior_filtering",
-		Input: "enabled = true",
+		Name:   "test_boolean_behavior_filtering",
+		Inputs: []string{"enabled = true"},
 		Validations: &types.ValidationSet{
 			Parse: []map[string]interface{}{
 				{"key": "enabled", "value": "true"},
@@ -488,6 +1335,7 @@ func TestFlatGenerator_GenerateMetadataFromValidation(t *testing.T) {
 	}{
 		{"parse", "parse", []string{}},
 		{"filter", "filter", []string{"comments"}},
+		{"extract_comments", "extract_comments", []string{"comments"}},
 		{"expand_dotted", "expand_dotted", []string{"experimental_dotted_keys"}},
 		{"get_string", "get_string", []string{}},
 		{"build_hierarchy", "build_hierarchy", []string{}},
@@ -563,6 +1411,199 @@ func TestExtractMetadataFromTags(t *testing.T) {
 	}
 }
 
+func TestSyncLegacyTags(t *testing.T) {
+	test := types.TestCase{
+		Functions: []string{"get_bool"},
+		Features:  []string{"comments"},
+		Behaviors: []string{"boolean_lenient"},
+		Variants:  []string{"reference_compliant"},
+		Meta:      types.TestMetadata{Tags: []string{"level:2", "function:parse", "regression"}},
+	}
+	want := []string{"level:2", "regression", "function:get_bool", "feature:comments", "behavior:boolean_lenient", "variant:reference_compliant"}
+	if got := SyncLegacyTags(test); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_EmitLegacyTags(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, EmitLegacyTags: true})
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	suite, err := loader.NewTestLoader("", config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "test-source.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated file: %v", err)
+	}
+	if len(suite.Tests) == 0 {
+		t.Fatal("Expected generated tests")
+	}
+	// Empty and nil metadata lists are the same metadata
+	nonEmpty := func(values []string) []string {
+		if len(values) == 0 {
+			return nil
+		}
+		return values
+	}
+	for _, test := range suite.Tests {
+		functions, features, behaviors, variants := ExtractMetadataFromTags(test.Meta.Tags)
+		got := [][]string{functions, features, behaviors, variants}
+		want := [][]string{nonEmpty(test.Functions), nonEmpty(test.Features), nonEmpty(test.Behaviors), nonEmpty(test.Variants)}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: tags %v give %v, typed metadata is %v", test.Name, test.Meta.Tags, got, want)
+		}
+	}
+
+	// Without the option no tags are written
+	plainDir := t.TempDir()
+	plain := NewFlatGenerator(sourceDir, plainDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := plain.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(plainDir, "test-source.json"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if strings.Contains(string(data), `"meta"`) {
+		t.Error("Expected no meta without EmitLegacyTags")
+	}
+}
+
+func TestFlatGenerator_GenerateFile_NormalizedSlices(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "test-source.json"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	var file struct {
+		Tests []map[string]json.RawMessage `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+
+	// single_validation_test has no features, behaviors or variants in the source
+	want := map[string]map[string]string{
+		"multi_validation_test_parse":      {"features": `["comments"]`, "behaviors": "[]", "variants": "[]", "functions": `["parse"]`, "args": ""},
+		"multi_validation_test_get_string": {"features": `["comments"]`, "behaviors": "[]", "variants": "[]", "functions": `["get_string"]`, "args": `["key"]`},
+		"single_validation_test_get_bool":  {"features": "[]", "behaviors": "[]", "variants": "[]", "functions": `["get_bool"]`, "args": `["flag"]`},
+	}
+	for _, test := range file.Tests {
+		var name string
+		json.Unmarshal(test["name"], &name)
+		fields, ok := want[name]
+		if !ok {
+			continue
+		}
+		delete(want, name)
+		for field, value := range fields {
+			var got bytes.Buffer
+			json.Compact(&got, test[field])
+			if got.String() != value {
+				t.Errorf("%s: %s = %q, want %q (empty means absent)", name, field, got.String(), value)
+			}
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("Tests not generated: %v", want)
+	}
+}
+
+func TestFlatGenerator_GenerateFile_EntryProvenance(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, EntryProvenance: true})
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "test-source.json"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	var file generated.GeneratedFormatSimpleJson
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Generated file does not match the schema: %v", err)
+	}
+	var sources []int
+	for _, test := range file.Tests {
+		if test.Validation == "parse" {
+			for _, entry := range test.Expected.Entries {
+				if entry.SrcIndex == nil {
+					t.Fatalf("Expected src_index on every entry, got %+v", entry)
+				}
+				sources = append(sources, *entry.SrcIndex)
+			}
+		}
+	}
+	if !reflect.DeepEqual(sources, []int{0, 1}) {
+		t.Errorf("Expected source indices [0 1], got %v", sources)
+	}
+
+	// Without the option no indices are written
+	plainDir := t.TempDir()
+	plain := NewFlatGenerator(sourceDir, plainDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := plain.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(plainDir, "test-source.json")); err != nil || strings.Contains(string(data), "src_index") {
+		t.Errorf("Expected no src_index without EntryProvenance: %v", err)
+	}
+}
+
+func TestFlatGenerator_GenerateAll_Budgets(t *testing.T) {
+	// test-source.json yields 5 flat tests; its first input is 22 bytes
+	tests := []struct {
+		name    string
+		budgets config.Budgets
+		want    string
+	}{
+		{"within", config.Budgets{MaxInputBytes: 22, MaxTestsPerFile: 5}, ""},
+		{"input", config.Budgets{MaxInputBytes: 21}, "source test multi_validation_test: inputs are 22 bytes, over max_input_bytes (21)"},
+		{"tests per file", config.Budgets{MaxTestsPerFile: 4}, "test-source.json: 5 tests, over max_tests_per_file (4)"},
+		{"total size", config.Budgets{MaxGeneratedBytes: 100}, "over max_generated_bytes (100)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceDir, outputDir := setupGeneratorTestData(t)
+			gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, Budgets: &tt.budgets})
+			err := gen.GenerateAll()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			var budgetErr *config.BudgetError
+			if !errors.As(err, &budgetErr) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected a budget error containing %q, got %v", tt.want, err)
+			}
+
+			// Lenient budgets only warn
+			var records []slog.Record
+			gen = NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{
+				SourceFormat:   FormatCompact,
+				Budgets:        &tt.budgets,
+				LenientBudgets: true,
+				Logger:         slog.New(recordingHandler{&records}),
+			})
+			if err := gen.GenerateAll(); err != nil {
+				t.Fatalf("Expected lenient budgets not to fail, got %v", err)
+			}
+			warned := false
+			for _, record := range records {
+				warned = warned || record.Message == "over budget"
+			}
+			if !warned {
+				t.Error("Expected an over budget warning")
+			}
+		})
+	}
+}
+
 func TestFlatGenerator_ValidateGenerated(t *testing.T) {
 	sourceDir, outputDir := setupGeneratorTestData(t)
 
@@ -608,6 +1649,23 @@ func TestFlatGenerator_ValidateGenerated_InvalidFile(t *testing.T) {
 	}
 }
 
+func TestFlatGenerator_ValidateGenerated_DanglingSource(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	generator := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := generator.GenerateAll(); err != nil {
+		t.Fatalf("Failed to generate files: %v", err)
+	}
+
+	// Output left behind by a source file that no longer exists
+	stale := `{"tests": [{"name": "gone_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "source_test": "gone"}]}`
+	os.WriteFile(filepath.Join(outputDir, "api_removed.json"), []byte(stale), 0644)
+
+	err := generator.ValidateGenerated()
+	if err == nil || !strings.Contains(err.Error(), "gone_parse (gone)") {
+		t.Errorf("Expected the dangling source link to be reported, got %v", err)
+	}
+}
+
 func TestFlatGenerator_ApplyFiltering_SkipFunctions(t *testing.T) {
 	sourceDir, outputDir := setupGeneratorTestData(t)
 
@@ -763,6 +1821,14 @@ func TestCreateExpectedStructure(t *testing.T) {
 	} else if len(expected.List) != 3 {
 		t.Errorf("Expected list with 3 elements, got %d", len(expected.List))
 	}
+
+	// Test formatting validations (expect text)
+	for _, validation := range []string{"pretty_print", "canonical_format"} {
+		expected = generator.createExpectedStructure(validation, "a = b\n")
+		if expected.Count != 1 || expected.Text == nil || *expected.Text != "a = b\n" || expected.Value != nil {
+			t.Errorf("Expected text for %s validation, got %+v", validation, expected)
+		}
+	}
 }
 
 func TestGetArgsForValidation(t *testing.T) {
@@ -800,8 +1866,13 @@ func TestFlatGenerator_GenerateFile_NonexistentFile(t *testing.T) {
 	generator := NewFlatGenerator(tmpDir, tmpDir, GenerateOptions{})
 
 	err := generator.GenerateFile("/nonexistent/file.json")
-	if err == nil {
-		t.Error("Expected error for nonexistent file")
+	if !errors.Is(err, ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing for nonexistent file, got %v", err)
+	}
+
+	missingDir := NewFlatGenerator(filepath.Join(tmpDir, "missing"), tmpDir, GenerateOptions{})
+	if err := missingDir.GenerateAll(); !errors.Is(err, ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing for nonexistent directory, got %v", err)
 	}
 }
 
@@ -817,8 +1888,8 @@ func TestFlatGenerator_GenerateFile_InvalidJSON(t *testing.T) {
 	}
 
 	err := generator.GenerateFile(invalidFile)
-	if err == nil {
-		t.Error("Expected error for invalid JSON")
+	if !errors.Is(err, loader.ErrParse) || errors.Is(err, ErrSourceMissing) {
+		t.Errorf("Expected loader.ErrParse for invalid JSON, got %v", err)
 	}
 }
 
@@ -915,3 +1986,41 @@ func TestValidationComponents_Structure(t *testing.T) {
 		t.Error("Expected error to be true")
 	}
 }
+
+func TestMarshalFlatFile_MatchesMarshalIndent(t *testing.T) {
+	huge := strings.Repeat("line of text\n", 200000) // Over 2 MB
+	test := func(name string, expected interface{}) generated.GeneratedFormatSimpleJsonTestsElem {
+		return generated.GeneratedFormatSimpleJsonTestsElem{
+			Name:       name,
+			Inputs:     []string{"a = b"},
+			Validation: "get_string",
+			Expected:   generated.GeneratedFormatSimpleJsonTestsElemExpected{Count: 1, Value: expected},
+		}
+	}
+	wrappers := map[string]generated.GeneratedFormatSimpleJson{
+		"nil tests":   {Schema: "schema.json"},
+		"empty tests": {Schema: "schema.json", Tests: []generated.GeneratedFormatSimpleJsonTestsElem{}},
+		"several tests": {
+			Schema:    "schema.json",
+			Generator: &generated.GeneratedFormatSimpleJsonGenerator{Version: "v1", OptionsFingerprint: "abc"},
+			Tests:     []generated.GeneratedFormatSimpleJsonTestsElem{test("one", "x"), test("two", "\n  \"tests\": null")},
+			Variants:  []string{"proposed_behavior"},
+		},
+		"huge value": {Schema: "schema.json", Tests: []generated.GeneratedFormatSimpleJsonTestsElem{test("huge", huge)}},
+	}
+	for name, wrapper := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.MarshalIndent(wrapper, "", "  ")
+			if err != nil {
+				t.Fatalf("MarshalIndent failed: %v", err)
+			}
+			got, err := marshalFlatFile(wrapper)
+			if err != nil {
+				t.Fatalf("marshalFlatFile failed: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Output differs from MarshalIndent.\nGot:  %.500s\nWant: %.500s", got, want)
+			}
+		})
+	}
+}
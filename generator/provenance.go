@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/version"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Fingerprint identifies the options that affect generated output, so two
+// generated_tests directories can be traced to the same or different
+// settings. Options that only change logging, progress, scanning, manifests,
+// or which files are regenerated do not contribute.
+func (o GenerateOptions) Fingerprint() string {
+	sorted := func(functions []config.CCLFunction) []config.CCLFunction {
+		functions = slices.Clone(functions)
+		slices.Sort(functions)
+		return functions
+	}
+	data, _ := json.Marshal(struct {
+		SkipPropertyTests bool
+		SkipFunctions     []config.CCLFunction
+		OnlyFunctions     []config.CCLFunction
+		SourceFormat      loader.TestFormat
+		NormalizeInput    loader.InputNormalization
+		LenientUTF8       bool
+		StrictArgs        bool
+		LenientShapes     bool
+		EmitLevels        bool
+		EmitLegacyTags    bool
+		EntryProvenance   bool
+		LenientBehaviors  bool                 `json:",omitempty"`
+		Features          ValidationFeatureMap `json:",omitempty"` // Only changes from the defaults, so default output keeps its fingerprint
+	}{
+		o.SkipPropertyTests, sorted(o.SkipFunctions), sorted(o.OnlyFunctions), o.SourceFormat, o.NormalizeInput,
+		o.LenientUTF8, o.StrictArgs, o.LenientShapes, o.EmitLevels, o.EmitLegacyTags, o.EntryProvenance,
+		o.LenientBehaviors, o.changedValidationFeatures(),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Provenance returns the generator block written into each generated file
+func (fg *FlatGenerator) Provenance() types.GeneratorInfo {
+	return types.GeneratorInfo{Version: version.Version, OptionsFingerprint: fg.Options.Fingerprint()}
+}
+
+// CheckProvenance returns a warning for each generated file in dir written by
+// a different major version of this library. Files without a generator
+// block predate it and are not reported.
+func CheckProvenance(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find generated files: %w", err)
+	}
+
+	var warnings []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		info, err := loader.ReadGeneratorInfo(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		if info != nil && version.Major(info.Version) != version.Major(version.Version) {
+			warnings = append(warnings, fmt.Sprintf("%s was generated by ccl-test-lib %s, this is %s",
+				filepath.Base(file), info.Version, version.Version))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/version"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+func TestGenerateOptions_Fingerprint(t *testing.T) {
+	base := GenerateOptions{SourceFormat: FormatCompact}
+	only := base
+	only.OnlyFunctions = []config.CCLFunction{config.FunctionParse, config.FunctionGetString}
+	reordered := base
+	reordered.OnlyFunctions = []config.CCLFunction{config.FunctionGetString, config.FunctionParse}
+	cosmetic := base
+	cosmetic.Verbose = true
+	cosmetic.Incremental = true
+
+	if base.Fingerprint() == only.Fingerprint() {
+		t.Error("Expected OnlyFunctions to change the fingerprint")
+	}
+	if only.Fingerprint() != reordered.Fingerprint() {
+		t.Error("Expected the order of OnlyFunctions not to matter")
+	}
+	if base.Fingerprint() != cosmetic.Fingerprint() {
+		t.Error("Expected Verbose and Incremental not to change the fingerprint")
+	}
+}
+
+func TestFlatGenerator_GenerateFile_Provenance(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	if err := gen.GenerateFile(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	suite, err := loader.NewTestLoader("", config.ImplementationConfig{}).LoadTestFile(filepath.Join(outputDir, "test-source.json"), loader.LoadOptions{Format: loader.FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load generated file: %v", err)
+	}
+	if suite.Generator == nil {
+		t.Fatal("Expected a generator block")
+	}
+	if *suite.Generator != gen.Provenance() || suite.Generator.Version != version.Version {
+		t.Errorf("Expected %+v, got %+v", gen.Provenance(), *suite.Generator)
+	}
+}
+
+func TestCheckProvenance(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"current.json": `{"generator": {"version": "` + version.Version + `", "options_fingerprint": "x"}, "tests": []}`,
+		"old.json":     `{"generator": {"version": "v99.0.0", "options_fingerprint": "x"}, "tests": []}`,
+		"unknown.json": `{"tests": []}`,
+		"bare.json":    `[]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	warnings, err := CheckProvenance(dir)
+	if err != nil {
+		t.Fatalf("CheckProvenance failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "old.json was generated by ccl-test-lib v99.0.0") {
+		t.Errorf("Expected one warning for old.json, got %v", warnings)
+	}
+}
+
+func TestFlatGenerator_GenerateAll_IncrementalOptionsChange(t *testing.T) {
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	if err := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact, Incremental: true}).GenerateAll(); err != nil {
+		t.Fatalf("Initial generation failed: %v", err)
+	}
+	output := filepath.Join(outputDir, "test-source.json")
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(output, future, future); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	// Newer output generated with other options is regenerated anyway
+	opts := GenerateOptions{SourceFormat: FormatCompact, Incremental: true, OnlyFunctions: []config.CCLFunction{config.FunctionParse}}
+	if err := NewFlatGenerator(sourceDir, outputDir, opts).GenerateAll(); err != nil {
+		t.Fatalf("Incremental generation failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	info, err := loader.ReadGeneratorInfo(data)
+	if err != nil || info == nil || info.OptionsFingerprint != opts.Fingerprint() {
+		t.Errorf("Expected output regenerated with the new options, got %+v (%v)", info, err)
+	}
+}
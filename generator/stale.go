@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// StaleReason says why a generated file is out of date
+type StaleReason string
+
+const (
+	StaleMissing   StaleReason = "missing"   // The source has no generated file
+	StaleChanged   StaleReason = "changed"   // The generated tests differ from a fresh generation
+	StaleGenerator StaleReason = "generator" // The tests match, but another library version or other options wrote them
+	StaleOrphan    StaleReason = "orphan"    // The generator wrote the file, but its source is gone
+)
+
+// StaleFile is a generated file that regenerating would change
+type StaleFile struct {
+	Source string // Source file; empty for StaleOrphan
+	Output string // Generated file, which may not exist
+	Reason StaleReason
+	Detail string // What differs, for StaleChanged and StaleGenerator
+}
+
+func (s StaleFile) String() string {
+	line := filepath.Base(s.Output) + ": " + string(s.Reason)
+	if s.Detail != "" {
+		line += " (" + s.Detail + ")"
+	}
+	return line
+}
+
+// CheckStale regenerates each source file in sourceDir in memory, as
+// GenerateAll would with opts, and compares the result with outputDir. It
+// returns the files regenerating would change, in name order, and no
+// files when outputDir is up to date. Generated files whose source is gone
+// are reported only if they carry a generator block, so other JSON files
+// in outputDir are left alone. It needs no manifest and writes nothing.
+func CheckStale(sourceDir, outputDir string, opts GenerateOptions) ([]StaleFile, error) {
+	fg := NewFlatGenerator(sourceDir, outputDir, opts)
+	if _, err := os.Stat(sourceDir); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSourceMissing, err)
+	}
+	sources, err := fg.sourceFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source files: %w", err)
+	}
+
+	var stale []StaleFile
+	generated := make(map[string]bool)
+	for _, source := range sources {
+		if opts.SkipPropertyTests && strings.HasPrefix(filepath.Base(source), "property-") {
+			continue
+		}
+		output := filepath.Join(outputDir, filepath.Base(source))
+		generated[filepath.Base(source)] = true
+		fresh, err := fg.FlattenFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", source, err)
+		}
+		current, err := os.ReadFile(output)
+		if errors.Is(err, fs.ErrNotExist) {
+			stale = append(stale, StaleFile{Source: source, Output: output, Reason: StaleMissing})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read generated file: %w", err)
+		}
+		if reason, detail := compareGenerated(current, fresh); reason != "" {
+			stale = append(stale, StaleFile{Source: source, Output: output, Reason: reason, Detail: detail})
+		}
+	}
+
+	outputs, err := filepath.Glob(filepath.Join(outputDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find generated files: %w", err)
+	}
+	for _, output := range outputs {
+		if generated[filepath.Base(output)] {
+			continue
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read generated file: %w", err)
+		}
+		if info, err := loader.ReadGeneratorInfo(data); err == nil && info != nil {
+			stale = append(stale, StaleFile{Output: output, Reason: StaleOrphan})
+		}
+	}
+
+	slices.SortFunc(stale, func(a, b StaleFile) int { return strings.Compare(a.Output, b.Output) })
+	return stale, nil
+}
+
+// compareGenerated returns why a generated file differs from a fresh one,
+// or "" if it does not
+func compareGenerated(current, fresh []byte) (StaleReason, string) {
+	if string(current) == string(fresh) {
+		return "", ""
+	}
+	var before, after struct {
+		Generator interface{}              `json:"generator"`
+		Tests     []map[string]interface{} `json:"tests"`
+	}
+	if err := json.Unmarshal(current, &before); err != nil {
+		return StaleChanged, "unreadable: " + err.Error()
+	}
+	json.Unmarshal(fresh, &after)
+
+	if detail := diffTestLists(before.Tests, after.Tests); detail != "" {
+		return StaleChanged, detail
+	}
+	if !reflect.DeepEqual(before.Generator, after.Generator) {
+		return StaleGenerator, "generator block differs"
+	}
+	return StaleChanged, "formatting differs"
+}
+
+// diffTestLists counts the tests, by name, added, removed and changed
+// between two generated files, or returns "" if they hold the same tests
+func diffTestLists(before, after []map[string]interface{}) string {
+	byName := func(tests []map[string]interface{}) map[string]map[string]interface{} {
+		named := make(map[string]map[string]interface{}, len(tests))
+		for _, test := range tests {
+			name, _ := test["name"].(string)
+			named[name] = test
+		}
+		return named
+	}
+	old, fresh := byName(before), byName(after)
+	var added, removed, changed int
+	for name, test := range fresh {
+		previous, ok := old[name]
+		switch {
+		case !ok:
+			added++
+		case !reflect.DeepEqual(previous, test):
+			changed++
+		}
+	}
+	for name := range old {
+		if _, ok := fresh[name]; !ok {
+			removed++
+		}
+	}
+	if added+removed+changed == 0 {
+		if !reflect.DeepEqual(before, after) {
+			return "tests reordered"
+		}
+		return ""
+	}
+	return fmt.Sprintf("%d added, %d removed, %d changed", added, removed, changed)
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+func generateFresh(t *testing.T, opts GenerateOptions) (string, string) {
+	t.Helper()
+	sourceDir, outputDir := setupGeneratorTestData(t)
+	if err := NewFlatGenerator(sourceDir, outputDir, opts).GenerateAll(); err != nil {
+		t.Fatalf("Generation failed: %v", err)
+	}
+	return sourceDir, outputDir
+}
+
+func TestCheckStale_Fresh(t *testing.T) {
+	opts := GenerateOptions{SourceFormat: FormatCompact}
+	sourceDir, outputDir := generateFresh(t, opts)
+
+	stale, err := CheckStale(sourceDir, outputDir, opts)
+	if err != nil {
+		t.Fatalf("CheckStale failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("Expected no stale files, got %v", stale)
+	}
+}
+
+func TestCheckStale_SourceEdited(t *testing.T) {
+	opts := GenerateOptions{SourceFormat: FormatCompact}
+	sourceDir, outputDir := generateFresh(t, opts)
+	source := filepath.Join(sourceDir, "test-source.json")
+	data, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatalf("Failed to read source: %v", err)
+	}
+	edited := strings.Replace(string(data), "flag = true", "flag = yes", 1)
+	if edited == string(data) {
+		t.Fatal("Expected the source to contain the input to edit")
+	}
+	if err := os.WriteFile(source, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	stale, err := CheckStale(sourceDir, outputDir, opts)
+	if err != nil {
+		t.Fatalf("CheckStale failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].Reason != StaleChanged || stale[0].Source != source {
+		t.Fatalf("Expected test-source.json changed, got %v", stale)
+	}
+	if stale[0].Detail != "0 added, 0 removed, 1 changed" {
+		t.Errorf("Expected one changed test, got %q", stale[0].Detail)
+	}
+}
+
+func TestCheckStale_MissingOutput(t *testing.T) {
+	opts := GenerateOptions{SourceFormat: FormatCompact}
+	sourceDir, outputDir := generateFresh(t, opts)
+	output := filepath.Join(outputDir, "test-source.json")
+	if err := os.Remove(output); err != nil {
+		t.Fatalf("Failed to remove output: %v", err)
+	}
+
+	stale, err := CheckStale(sourceDir, outputDir, opts)
+	if err != nil {
+		t.Fatalf("CheckStale failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].Reason != StaleMissing || stale[0].Output != output {
+		t.Errorf("Expected test-source.json missing, got %v", stale)
+	}
+	if got := stale[0].String(); got != "test-source.json: missing" {
+		t.Errorf("Unexpected String() %q", got)
+	}
+}
+
+func TestCheckStale_OtherOptions(t *testing.T) {
+	sourceDir, outputDir := generateFresh(t, GenerateOptions{SourceFormat: FormatCompact})
+
+	// Options that drop no tests from a file still change its generator block
+	opts := GenerateOptions{SourceFormat: FormatCompact, SkipFunctions: []config.CCLFunction{config.FunctionGetFloat}}
+	stale, err := CheckStale(sourceDir, outputDir, opts)
+	if err != nil {
+		t.Fatalf("CheckStale failed: %v", err)
+	}
+	if len(stale) == 0 {
+		t.Fatal("Expected stale files")
+	}
+	for _, file := range stale {
+		if file.Reason != StaleGenerator {
+			t.Errorf("Expected %s stale by generator, got %v", file.Output, file)
+		}
+	}
+}
+
+func TestCheckStale_Orphan(t *testing.T) {
+	opts := GenerateOptions{SourceFormat: FormatCompact}
+	sourceDir, outputDir := generateFresh(t, opts)
+	if err := os.Remove(filepath.Join(sourceDir, "test-source.json")); err != nil {
+		t.Fatalf("Failed to remove source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "handwritten.json"), []byte(`{"tests": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	stale, err := CheckStale(sourceDir, outputDir, opts)
+	if err != nil {
+		t.Fatalf("CheckStale failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].Reason != StaleOrphan || filepath.Base(stale[0].Output) != "test-source.json" {
+		t.Errorf("Expected only test-source.json orphaned, got %v", stale)
+	}
+}
+
+func TestCheckStale_SourceMissing(t *testing.T) {
+	_, err := CheckStale(filepath.Join(t.TempDir(), "absent"), t.TempDir(), GenerateOptions{})
+	if !errors.Is(err, ErrSourceMissing) {
+		t.Errorf("Expected ErrSourceMissing, got %v", err)
+	}
+}
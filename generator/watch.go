@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Watch defaults
+const (
+	DefaultWatchInterval = 500 * time.Millisecond
+	DefaultWatchDebounce = 200 * time.Millisecond
+)
+
+// WatchOptions controls how Watch polls the source directory
+type WatchOptions struct {
+	Interval time.Duration // Time between directory scans (DefaultWatchInterval if zero)
+	Debounce time.Duration // A file must be unchanged this long before regenerating (DefaultWatchDebounce if zero)
+}
+
+// WatchEvent describes one regeneration triggered by a source change
+type WatchEvent struct {
+	Source   string // Source file path
+	Tests    int    // Flat tests generated from it
+	Previous int    // Flat tests in the output before regenerating
+	Err      error  // Set when generation failed; the output is left as it was
+}
+
+// fileState is what polling compares to detect a change
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch polls the source directory and regenerates each source file after
+// it changes, calling onChange once per regeneration. Rapid successive writes
+// to a file are debounced into a single regeneration. Files present when
+// Watch starts are not regenerated until they change. Watch returns when ctx
+// is done. It returns ErrSourceMissing if SourceDir does not exist.
+func (fg *FlatGenerator) Watch(ctx context.Context, opts WatchOptions, onChange func(WatchEvent)) error {
+	if _, err := os.Stat(fg.SourceDir); err != nil {
+		return fmt.Errorf("%w: %w", ErrSourceMissing, err)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	if err := os.MkdirAll(fg.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	known, err := fg.scanSources()
+	if err != nil {
+		return err
+	}
+	pending := make(map[string]time.Time) // Source -> time its last change was seen
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			current, err := fg.scanSources()
+			if err != nil {
+				return err
+			}
+			for path, state := range current {
+				if previous, ok := known[path]; !ok || previous != state {
+					pending[path] = now
+				}
+			}
+			known = current
+
+			for path, changed := range pending {
+				if _, exists := current[path]; !exists {
+					delete(pending, path)
+					continue
+				}
+				if now.Sub(changed) < opts.Debounce {
+					continue
+				}
+				delete(pending, path)
+				onChange(fg.regenerate(path))
+			}
+		}
+	}
+}
+
+// scanSources returns the state of every source file Watch regenerates
+func (fg *FlatGenerator) scanSources() (map[string]fileState, error) {
+	files, err := fg.sourceFiles()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	states := make(map[string]fileState, len(files))
+	for _, file := range files {
+		if fg.Options.SkipPropertyTests && strings.HasPrefix(filepath.Base(file), "property-") {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue // Removed between the scan and Stat
+		}
+		states[file] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return states, nil
+}
+
+// regenerate generates one source file and counts its flat tests
+func (fg *FlatGenerator) regenerate(source string) WatchEvent {
+	output := filepath.Join(fg.OutputDir, filepath.Base(source))
+	event := WatchEvent{Source: source, Previous: countTests(output)}
+	if err := fg.GenerateFile(source); err != nil {
+		event.Err = err
+		return event
+	}
+	event.Tests = countTests(output)
+	return event
+}
+
+// countTests returns the number of tests in a flat file, 0 if unreadable
+func countTests(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var file struct {
+		Tests []json.RawMessage `json:"tests"`
+	}
+	if json.Unmarshal(data, &file) != nil {
+		return 0
+	}
+	return len(file.Tests)
+}
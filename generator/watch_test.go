@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFlatGenerator_Watch_DebouncesChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceDir := filepath.Join(tmpDir, "source")
+	outputDir := filepath.Join(tmpDir, "output")
+	os.MkdirAll(sourceDir, 0755)
+	existing := filepath.Join(sourceDir, "api_existing.json")
+	os.WriteFile(existing, []byte(`{"tests": [{"name": "old", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": []}]}]}`), 0644)
+
+	gen := NewFlatGenerator(sourceDir, outputDir, GenerateOptions{SourceFormat: FormatCompact})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var events []WatchEvent
+	done := make(chan error, 1)
+	go func() {
+		done <- gen.Watch(ctx, WatchOptions{Interval: 5 * time.Millisecond, Debounce: 60 * time.Millisecond}, func(event WatchEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Several quick saves, each growing the file so polling sees every one
+	source := filepath.Join(sourceDir, "api_new.json")
+	for _, validations := range []string{
+		`{"function": "parse", "expect": []}`,
+		`{"function": "parse", "expect": []}, {"function": "build_hierarchy", "expect": {}}`,
+		`{"function": "parse", "expect": []}, {"function": "build_hierarchy", "expect": {}}, {"function": "get_string", "args": ["a"], "expect": "b"}`,
+	} {
+		content := `{"tests": [{"name": "new", "inputs": ["a = b"], "tests": [` + validations + `]}]}`
+		if err := os.WriteFile(source, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+		time.Sleep(15 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("Expected exactly one regeneration, got %d: %+v", len(events), events)
+	}
+	event := events[0]
+	if event.Source != source || event.Err != nil || event.Tests != 3 || event.Previous != 0 {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "api_existing.json")); !os.IsNotExist(err) {
+		t.Error("Expected unchanged sources not to be regenerated")
+	}
+}
+
+func TestFlatGenerator_Watch_ReportsErrors(t *testing.T) {
+	sourceDir := t.TempDir()
+	gen := NewFlatGenerator(sourceDir, t.TempDir(), GenerateOptions{SourceFormat: FormatCompact})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan WatchEvent, 1)
+	go gen.Watch(ctx, WatchOptions{Interval: 5 * time.Millisecond, Debounce: 10 * time.Millisecond}, func(event WatchEvent) {
+		events <- event
+	})
+	time.Sleep(20 * time.Millisecond)
+	os.WriteFile(filepath.Join(sourceDir, "api_bad.json"), []byte(`{"tests": [`), 0644)
+
+	select {
+	case event := <-events:
+		if event.Err == nil {
+			t.Errorf("Expected a generation error, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for regeneration")
+	}
+}
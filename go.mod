@@ -4,6 +4,7 @@ go 1.25.1
 
 require (
 	github.com/atombender/go-jsonschema v0.16.0
+	golang.org/x/text v0.17.0
 	gotest.tools/gotestsum v1.13.0
 )
 
@@ -27,7 +28,6 @@ require (
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/term v0.35.0 // indirect
-	golang.org/x/text v0.17.0 // indirect
 	golang.org/x/tools v0.36.0 // indirect
 	golang.org/x/xerrors v0.0.0-20231012003039-104605ab7028 // indirect
 )
@@ -1,15 +1,21 @@
 package ccl_test_lib
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/generator"
 	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
@@ -17,23 +23,11 @@ import (
 // These tests verify that different packages work together correctly
 
 func TestCrossPackage_LoaderGeneratorRoundTrip(t *testing.T) {
-	tmpDir := t.TempDir()
-	sourceDir := filepath.Join(tmpDir, "source")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	// Create directories
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
-		t.Fatalf("Failed to create source directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create source format test data
 	sourceTests := []loader.CompactTest{
 		{
 			Name:     "roundtrip_test",
-			Input:    "name = Alice\nage = 25\nenabled = true",
+			Inputs:   []string{"name = Alice\nage = 25\nenabled = true"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{
@@ -71,16 +65,9 @@ func TestCrossPackage_LoaderGeneratorRoundTrip(t *testing.T) {
 		},
 	}
 
-	// Write source data wrapped in CompactTestFile structure
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  sourceTests,
-	}
-	sourceData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	sourceFile := filepath.Join(sourceDir, "roundtrip.json")
-	if err := os.WriteFile(sourceFile, sourceData, 0644); err != nil {
-		t.Fatalf("Failed to write source file: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).WithCompactFile("roundtrip.json", sourceTests...).Build()
+	sourceDir := filepath.Join(tmpDir, testsupport.SourceDir)
+	generatedDir := filepath.Join(tmpDir, testsupport.GeneratedDir)
 
 	// Step 1: Generate flat format using generator
 	gen := generator.NewFlatGenerator(sourceDir, generatedDir, generator.GenerateOptions{
@@ -139,7 +126,7 @@ func TestCrossPackage_LoaderGeneratorRoundTrip(t *testing.T) {
 
 		// Verify all tests have the same input
 		expectedInput := "name = Alice\nage = 25\nenabled = true"
-		if test.Input != expectedInput {
+		if len(test.Inputs) != 1 || test.Inputs[0] != expectedInput {
 			t.Errorf("Input mismatch for %s test", test.Validation)
 		}
 	}
@@ -154,18 +141,11 @@ func TestCrossPackage_LoaderGeneratorRoundTrip(t *testing.T) {
 }
 
 func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
-	tmpDir := t.TempDir()
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create directory: %v", err)
-	}
-
 	// Create test data with various compatibility requirements
 	flatTests := []types.TestCase{
 		{
 			Name:       "basic_parse",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
 			Functions:  []string{"parse"},
@@ -175,7 +155,7 @@ func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
 		},
 		{
 			Name:       "comments_parse",
-			Input:      "key = value\n/= comment",
+			Inputs:     []string{"key = value\n/= comment"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
 			Functions:  []string{"parse"},
@@ -185,7 +165,7 @@ func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
 		},
 		{
 			Name:       "unicode_parse",
-			Input:      "名前 = 値",
+			Inputs:     []string{"名前 = 値"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "名前", "value": "値"}},
 			Functions:  []string{"parse"},
@@ -195,7 +175,7 @@ func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
 		},
 		{
 			Name:       "advanced_get_string",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "get_string",
 			Expected:   "value",
 			Args:       []string{"key"},
@@ -206,10 +186,7 @@ func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
 		},
 	}
 
-	flatData, _ := json.MarshalIndent(flatTests, "", "  ")
-	if err := os.WriteFile(filepath.Join(generatedDir, "compatibility.json"), flatData, 0644); err != nil {
-		t.Fatalf("Failed to write test file: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).WithFlatFile("compatibility.json", flatTests...).Build()
 
 	// Test different compatibility configurations
 	testCases := []struct {
@@ -298,22 +275,11 @@ func TestCrossPackage_ConfigCompatibilityFiltering(t *testing.T) {
 }
 
 func TestCrossPackage_StatisticsAccuracy(t *testing.T) {
-	tmpDir := t.TempDir()
-	sourceDir := filepath.Join(tmpDir, "source")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
-		t.Fatalf("Failed to create source directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create source data with known characteristics
 	sourceTests := []loader.CompactTest{
 		{
 			Name:     "test_level_1",
-			Input:    "key1 = value1",
+			Inputs:   []string{"key1 = value1"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "key1", "value": "value1"}}},
@@ -322,7 +288,7 @@ func TestCrossPackage_StatisticsAccuracy(t *testing.T) {
 		},
 		{
 			Name:     "test_level_2",
-			Input:    "key2 = value2\n/= comment",
+			Inputs:   []string{"key2 = value2\n/= comment"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "key2", "value": "value2"}}},
@@ -331,7 +297,7 @@ func TestCrossPackage_StatisticsAccuracy(t *testing.T) {
 		},
 		{
 			Name:     "test_level_3",
-			Input:    "key3 = value3",
+			Inputs:   []string{"key3 = value3"},
 			Features: []string{"unicode"},
 			Tests: []loader.CompactValidation{
 				{Function: "get_int", Args: []string{"key3"}, Expect: 3},
@@ -339,23 +305,7 @@ func TestCrossPackage_StatisticsAccuracy(t *testing.T) {
 		},
 	}
 
-	// Write source data wrapped in CompactTestFile structure
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  sourceTests,
-	}
-	sourceData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	if err := os.WriteFile(filepath.Join(sourceDir, "stats.json"), sourceData, 0644); err != nil {
-		t.Fatalf("Failed to write source file: %v", err)
-	}
-
-	// Generate flat format
-	gen := generator.NewFlatGenerator(sourceDir, generatedDir, generator.GenerateOptions{
-		SourceFormat: generator.FormatCompact,
-	})
-	if err := gen.GenerateAll(); err != nil {
-		t.Fatalf("Generation failed: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).WithCompactFile("stats.json", sourceTests...).WithGenerated().Build()
 
 	// Test statistics with different configurations
 	cfg := config.ImplementationConfig{
@@ -435,44 +385,42 @@ func TestCrossPackage_ErrorPropagation(t *testing.T) {
 		t.Fatalf("Failed to create empty directory: %v", err)
 	}
 
-	// Should not error, just return empty results
-	tests, err := LoadCompatibleTests(emptyDir, cfg)
+	// Missing generated_tests is reported as ErrNoTestData
+	if _, err := LoadCompatibleTests(emptyDir, cfg); !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData from empty directory, got %v", err)
+	}
+	tests, err := LoadCompatibleTests(emptyDir, cfg, WithAllowEmpty())
 	if err != nil {
-		t.Errorf("LoadCompatibleTests should handle empty directory gracefully: %v", err)
+		t.Errorf("LoadCompatibleTests should handle empty directory with WithAllowEmpty: %v", err)
 	}
 	if len(tests) != 0 {
 		t.Errorf("Expected 0 tests from empty directory, got %d", len(tests))
 	}
 
-	stats, err := GetTestStats(emptyDir, cfg)
+	stats, err := GetTestStats(emptyDir, cfg, WithAllowEmpty())
 	if err != nil {
-		t.Errorf("GetTestStats should handle empty directory gracefully: %v", err)
+		t.Errorf("GetTestStats should handle empty directory with WithAllowEmpty: %v", err)
 	}
 	if stats.TotalTests != 0 {
 		t.Errorf("Expected 0 total tests from empty directory, got %d", stats.TotalTests)
 	}
 
 	// Test with directory containing invalid JSON
-	invalidDir := filepath.Join(tmpDir, "invalid")
-	if err := os.MkdirAll(filepath.Join(invalidDir, "generated_tests"), 0755); err != nil {
-		t.Fatalf("Failed to create invalid directory: %v", err)
-	}
+	invalidDir := testsupport.NewFixture(t).
+		WithInvalidFile(filepath.Join(testsupport.GeneratedDir, "invalid.json"), "invalid json content").
+		Build()
 
-	invalidFile := filepath.Join(invalidDir, "generated_tests", "invalid.json")
-	if err := os.WriteFile(invalidFile, []byte("invalid json content"), 0644); err != nil {
-		t.Fatalf("Failed to write invalid JSON: %v", err)
-	}
-
-	// Loader should handle this gracefully by skipping invalid files
 	testLoader := loader.NewTestLoader(invalidDir, cfg)
-	tests, err = testLoader.LoadAllTests(loader.LoadOptions{
+	_, err = testLoader.LoadAllTests(loader.LoadOptions{
 		Format:     loader.FormatFlat,
 		FilterMode: loader.FilterAll,
 	})
-	// This might error or might skip - both are acceptable behaviors
-	// The important thing is it doesn't crash
-	_ = err
-	_ = tests
+	if !errors.Is(err, loader.ErrParse) {
+		t.Errorf("Expected loader.ErrParse for invalid JSON, got %v", err)
+	}
+	if _, err := LoadCompatibleTests(invalidDir, cfg); !errors.Is(err, loader.ErrParse) {
+		t.Errorf("Expected LoadCompatibleTests to wrap loader.ErrParse, got %v", err)
+	}
 }
 
 func TestCrossPackage_ConfigValidation(t *testing.T) {
@@ -528,3 +476,40 @@ func TestCrossPackage_ConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// The generator and loader must share one types.TestCase; this fails to
+// compile if either package imports types from another module path
+var _ = func(gen *generator.FlatGenerator, tl *loader.TestLoader, test types.TestCase) []types.TestCase {
+	flat, _ := gen.TransformSourceToFlat(test)
+	return tl.FilterCompatibleTests(flat)
+}
+
+func TestCrossPackage_NoLegacyModuleImports(t *testing.T) {
+	const legacy = "github.com/tylerbu/ccl-test-lib"
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (strings.HasPrefix(d.Name(), ".") && path != "." || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if importPath == legacy || strings.HasPrefix(importPath, legacy+"/") {
+				t.Errorf("%s imports %s; use github.com/CatConfLang/ccl-test-lib", fset.Position(spec.Pos()), importPath)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan source tree: %v", err)
+	}
+}
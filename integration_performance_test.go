@@ -1,9 +1,7 @@
 package ccl_test_lib
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -11,6 +9,7 @@ import (
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/generator"
 	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
@@ -22,17 +21,6 @@ func TestIntegration_LargeDatasetPerformance(t *testing.T) {
 		t.Skip("Skipping large dataset test in short mode")
 	}
 
-	tmpDir := t.TempDir()
-	sourceDir := filepath.Join(tmpDir, "source")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
-		t.Fatalf("Failed to create source directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create large dataset (1000 source tests, each with 5 validations = 5000 flat tests)
 	const numTests = 1000
 	const validationsPerTest = 5
@@ -43,7 +31,7 @@ func TestIntegration_LargeDatasetPerformance(t *testing.T) {
 	for i := 0; i < numTests; i++ {
 		sourceTests[i] = loader.CompactTest{
 			Name:     fmt.Sprintf("large_test_%d", i),
-			Input:    fmt.Sprintf("key_%d = value_%d\ncount_%d = %d\nflag_%d = true", i, i, i, i, i),
+			Inputs:   []string{fmt.Sprintf("key_%d = value_%d\ncount_%d = %d\nflag_%d = true", i, i, i, i, i)},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{
@@ -83,24 +71,14 @@ func TestIntegration_LargeDatasetPerformance(t *testing.T) {
 
 	// Write source data in chunks to avoid memory issues
 	const chunkSize = 100
+	fixture := testsupport.NewFixture(t)
 	for chunk := 0; chunk < numTests; chunk += chunkSize {
-		end := chunk + chunkSize
-		if end > numTests {
-			end = numTests
-		}
-
-		chunkData := sourceTests[chunk:end]
-		// Wrap chunk in CompactTestFile structure for correct parsing
-		chunkTestFile := loader.CompactTestFile{
-			Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-			Tests:  chunkData,
-		}
-		sourceData, _ := json.MarshalIndent(chunkTestFile, "", "  ")
-		sourceFile := filepath.Join(sourceDir, fmt.Sprintf("large_chunk_%d.json", chunk/chunkSize))
-		if err := os.WriteFile(sourceFile, sourceData, 0644); err != nil {
-			t.Fatalf("Failed to write source chunk %d: %v", chunk/chunkSize, err)
-		}
+		end := min(chunk+chunkSize, numTests)
+		fixture.WithCompactFile(fmt.Sprintf("large_chunk_%d.json", chunk/chunkSize), sourceTests[chunk:end]...)
 	}
+	tmpDir := fixture.Build()
+	sourceDir := filepath.Join(tmpDir, testsupport.SourceDir)
+	generatedDir := filepath.Join(tmpDir, testsupport.GeneratedDir)
 
 	// Measure generation performance
 	start := time.Now()
@@ -196,17 +174,6 @@ func TestIntegration_MemoryUsagePattern(t *testing.T) {
 		t.Skip("Skipping memory test in short mode")
 	}
 
-	tmpDir := t.TempDir()
-	sourceDir := filepath.Join(tmpDir, "source")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
-		t.Fatalf("Failed to create source directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create tests with large string content to test memory handling
 	largeContent := make([]byte, 1024*10) // 10KB string
 	for i := range largeContent {
@@ -217,7 +184,7 @@ func TestIntegration_MemoryUsagePattern(t *testing.T) {
 	sourceTests := []loader.CompactTest{
 		{
 			Name:     "large_content_test",
-			Input:    fmt.Sprintf("large_key = %s\nother_key = small_value", largeString),
+			Inputs:   []string{fmt.Sprintf("large_key = %s\nother_key = small_value", largeString)},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{
@@ -243,24 +210,8 @@ func TestIntegration_MemoryUsagePattern(t *testing.T) {
 		},
 	}
 
-	// Wrap in CompactTestFile structure for correct parsing
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  sourceTests,
-	}
-	sourceData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	if err := os.WriteFile(filepath.Join(sourceDir, "large_content.json"), sourceData, 0644); err != nil {
-		t.Fatalf("Failed to write source file: %v", err)
-	}
-
 	// Test generation with large content
-	gen := generator.NewFlatGenerator(sourceDir, generatedDir, generator.GenerateOptions{
-		SourceFormat: generator.FormatCompact,
-	})
-
-	if err := gen.GenerateAll(); err != nil {
-		t.Fatalf("Generation failed: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).WithCompactFile("large_content.json", sourceTests...).WithGenerated().Build()
 
 	// Test loading with large content
 	cfg := config.ImplementationConfig{
@@ -300,22 +251,11 @@ func TestIntegration_MemoryUsagePattern(t *testing.T) {
 }
 
 func TestIntegration_ConcurrentOperations(t *testing.T) {
-	tmpDir := t.TempDir()
-	sourceDir := filepath.Join(tmpDir, "source")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
-		t.Fatalf("Failed to create source directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create test data
 	sourceTests := []loader.CompactTest{
 		{
 			Name:     "concurrent_test",
-			Input:    "key = value",
+			Inputs:   []string{"key = value"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "key", "value": "value"}}},
@@ -324,23 +264,7 @@ func TestIntegration_ConcurrentOperations(t *testing.T) {
 		},
 	}
 
-	// Wrap in CompactTestFile structure for correct parsing
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  sourceTests,
-	}
-	sourceData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	if err := os.WriteFile(filepath.Join(sourceDir, "concurrent.json"), sourceData, 0644); err != nil {
-		t.Fatalf("Failed to write source file: %v", err)
-	}
-
-	// Generate flat format
-	gen := generator.NewFlatGenerator(sourceDir, generatedDir, generator.GenerateOptions{
-		SourceFormat: generator.FormatCompact,
-	})
-	if err := gen.GenerateAll(); err != nil {
-		t.Fatalf("Generation failed: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).WithCompactFile("concurrent.json", sourceTests...).WithGenerated().Build()
 
 	cfg := config.ImplementationConfig{
 		Name:    "concurrent-test",
@@ -435,22 +359,11 @@ func TestIntegration_ConcurrentOperations(t *testing.T) {
 }
 
 func TestIntegration_MixedFormatHandling(t *testing.T) {
-	tmpDir := t.TempDir()
-	testsDir := filepath.Join(tmpDir, "source_tests")
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(testsDir, 0755); err != nil {
-		t.Fatalf("Failed to create tests directory: %v", err)
-	}
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		t.Fatalf("Failed to create generated_tests directory: %v", err)
-	}
-
 	// Create compact format data
 	compactTests := []loader.CompactTest{
 		{
 			Name:     "compact_format_test",
-			Input:    "compact_key = compact_value",
+			Inputs:   []string{"compact_key = compact_value"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "compact_key", "value": "compact_value"}}},
@@ -458,21 +371,11 @@ func TestIntegration_MixedFormatHandling(t *testing.T) {
 		},
 	}
 
-	// Wrap in CompactTestFile structure for correct parsing
-	compactTestFile := loader.CompactTestFile{
-		Schema: "https://schemas.ccl.example.com/compact-format/v1.0.json",
-		Tests:  compactTests,
-	}
-	compactData, _ := json.MarshalIndent(compactTestFile, "", "  ")
-	if err := os.WriteFile(filepath.Join(testsDir, "compact.json"), compactData, 0644); err != nil {
-		t.Fatalf("Failed to write compact file: %v", err)
-	}
-
 	// Create flat format data directly
 	flatTests := []types.TestCase{
 		{
 			Name:       "flat_format_test",
-			Input:      "flat_key = flat_value",
+			Inputs:     []string{"flat_key = flat_value"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "flat_key", "value": "flat_value"}},
 			Functions:  []string{"parse"},
@@ -483,10 +386,10 @@ func TestIntegration_MixedFormatHandling(t *testing.T) {
 		},
 	}
 
-	flatData, _ := json.MarshalIndent(flatTests, "", "  ")
-	if err := os.WriteFile(filepath.Join(generatedDir, "flat.json"), flatData, 0644); err != nil {
-		t.Fatalf("Failed to write flat file: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(t).
+		WithCompactFile("compact.json", compactTests...).
+		WithFlatFile("flat.json", flatTests...).
+		Build()
 
 	cfg := config.ImplementationConfig{
 		Name:               "mixed-format-test",
@@ -552,20 +455,13 @@ func TestIntegration_MixedFormatHandling(t *testing.T) {
 // Benchmark tests for performance regression detection
 
 func BenchmarkIntegration_LoadingPerformance(b *testing.B) {
-	tmpDir := b.TempDir()
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		b.Fatalf("Failed to create directory: %v", err)
-	}
-
 	// Create moderate-sized test dataset
 	const numTests = 100
 	flatTests := make([]types.TestCase, numTests)
 	for i := 0; i < numTests; i++ {
 		flatTests[i] = types.TestCase{
 			Name:       fmt.Sprintf("bench_test_%d", i),
-			Input:      fmt.Sprintf("key_%d = value_%d", i, i),
+			Inputs:     []string{fmt.Sprintf("key_%d = value_%d", i, i)},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": fmt.Sprintf("key_%d", i), "value": fmt.Sprintf("value_%d", i)}},
 			Functions:  []string{"parse"},
@@ -576,10 +472,7 @@ func BenchmarkIntegration_LoadingPerformance(b *testing.B) {
 		}
 	}
 
-	flatData, _ := json.MarshalIndent(flatTests, "", "  ")
-	if err := os.WriteFile(filepath.Join(generatedDir, "bench.json"), flatData, 0644); err != nil {
-		b.Fatalf("Failed to write test file: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(b).WithFlatFile("bench.json", flatTests...).Build()
 
 	cfg := config.ImplementationConfig{
 		Name:               "bench-test",
@@ -603,20 +496,13 @@ func BenchmarkIntegration_LoadingPerformance(b *testing.B) {
 }
 
 func BenchmarkIntegration_StatisticsPerformance(b *testing.B) {
-	tmpDir := b.TempDir()
-	generatedDir := filepath.Join(tmpDir, "generated_tests")
-
-	if err := os.MkdirAll(generatedDir, 0755); err != nil {
-		b.Fatalf("Failed to create directory: %v", err)
-	}
-
 	// Create test dataset for statistics
 	const numTests = 50
 	flatTests := make([]types.TestCase, numTests)
 	for i := 0; i < numTests; i++ {
 		flatTests[i] = types.TestCase{
 			Name:       fmt.Sprintf("stats_test_%d", i),
-			Input:      fmt.Sprintf("key_%d = value_%d", i, i),
+			Inputs:     []string{fmt.Sprintf("key_%d = value_%d", i, i)},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": fmt.Sprintf("key_%d", i), "value": fmt.Sprintf("value_%d", i)}},
 			Functions:  []string{"parse"},
@@ -627,10 +513,7 @@ func BenchmarkIntegration_StatisticsPerformance(b *testing.B) {
 		}
 	}
 
-	flatData, _ := json.MarshalIndent(flatTests, "", "  ")
-	if err := os.WriteFile(filepath.Join(generatedDir, "stats.json"), flatData, 0644); err != nil {
-		b.Fatalf("Failed to write test file: %v", err)
-	}
+	tmpDir := testsupport.NewFixture(b).WithFlatFile("stats.json", flatTests...).Build()
 
 	cfg := config.ImplementationConfig{
 		Name:               "stats-bench-test",
@@ -75,7 +75,7 @@ func TestIntegration_RealCCLTestData(t *testing.T) {
 		if test.Expected == nil {
 			t.Error("Real test data should have expected field")
 		}
-		if test.Input == "" {
+		if len(test.Inputs) == 0 {
 			t.Error("Real test data should have input field")
 		}
 
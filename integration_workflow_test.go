@@ -8,7 +8,11 @@ import (
 
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/lint"
 	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+	"github.com/CatConfLang/ccl-test-lib/runner"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
@@ -36,7 +40,7 @@ func TestWorkflow_NewCCLImplementationDevelopment(t *testing.T) {
 	sourceTests := []loader.CompactTest{
 		{
 			Name:     "basic_parsing",
-			Input:    "name = John\nage = 30",
+			Inputs:   []string{"name = John\nage = 30"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -47,7 +51,7 @@ func TestWorkflow_NewCCLImplementationDevelopment(t *testing.T) {
 		},
 		{
 			Name:     "object_construction",
-			Input:    "user.name = Alice\nuser.age = 25",
+			Inputs:   []string{"user.name = Alice\nuser.age = 25"},
 			Features: []string{"experimental_dotted_keys"},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -64,7 +68,7 @@ func TestWorkflow_NewCCLImplementationDevelopment(t *testing.T) {
 		},
 		{
 			Name:     "typed_access",
-			Input:    "count = 42\nflag = true\nrate = 3.14\nname = John",
+			Inputs:   []string{"count = 42\nflag = true\nrate = 3.14\nname = John"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "get_int", Args: []string{"count"}, Expect: 42},
@@ -75,7 +79,7 @@ func TestWorkflow_NewCCLImplementationDevelopment(t *testing.T) {
 		},
 		{
 			Name:     "comments_support",
-			Input:    "key = value\n/= This is a comment\nother = data",
+			Inputs:   []string{"key = value\n/= This is a comment\nother = data"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -90,7 +94,7 @@ func TestWorkflow_NewCCLImplementationDevelopment(t *testing.T) {
 		},
 		{
 			Name:     "advanced_features",
-			Input:    "list.0 = first\nlist.1 = second\nmultiline = line1\\nline2",
+			Inputs:   []string{"list.0 = first\nlist.1 = second\nmultiline = line1\\nline2"},
 			Features: []string{"experimental_dotted_keys", "multiline"},
 			Tests: []loader.CompactValidation{
 				{Function: "get_list", Args: []string{"list"}, Expect: []interface{}{"first", "second"}},
@@ -388,7 +392,7 @@ func TestWorkflow_TestDataMaintenance(t *testing.T) {
 	initialTests := []loader.CompactTest{
 		{
 			Name:     "version1_test",
-			Input:    "key = value",
+			Inputs:   []string{"key = value"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "key", "value": "value"}}},
@@ -435,7 +439,7 @@ func TestWorkflow_TestDataMaintenance(t *testing.T) {
 	// Step 2: Add new tests (simulating test data expansion)
 	expandedTests := append(initialTests, loader.CompactTest{
 		Name:     "version2_test",
-		Input:    "new_key = new_value\ncount = 10",
+		Inputs:   []string{"new_key = new_value\ncount = 10"},
 		Features: []string{},
 		Tests: []loader.CompactValidation{
 			{Function: "parse", Expect: []map[string]interface{}{
@@ -567,7 +571,7 @@ func TestWorkflow_MultiProjectCompatibility(t *testing.T) {
 	sharedTests := []loader.CompactTest{
 		{
 			Name:     "compatibility_test",
-			Input:    "basic = true\nadvanced = false\ncount = 42",
+			Inputs:   []string{"basic = true\nadvanced = false\ncount = 42"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -582,7 +586,7 @@ func TestWorkflow_MultiProjectCompatibility(t *testing.T) {
 		},
 		{
 			Name:     "feature_test",
-			Input:    "key = value\n/= comment\nother = data",
+			Inputs:   []string{"key = value\n/= comment\nother = data"},
 			Features: []string{"comments"},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -735,12 +739,21 @@ func TestWorkflow_MultiProjectCompatibility(t *testing.T) {
 	}
 
 	// Generate compatibility report
-	t.Logf("Compatibility Report:")
-	for _, impl := range implementations {
-		stats := results[impl.name]
-		percentage := float64(stats.CompatibleTests) / float64(stats.TotalTests) * 100
-		t.Logf("  %s: %.1f%% (%d/%d tests)", impl.name, percentage, stats.CompatibleTests, stats.TotalTests)
+	configs := make([]config.ImplementationConfig, len(implementations))
+	for i, impl := range implementations {
+		configs[i] = impl.config
+	}
+	matrix, err := RunMatrix(sharedTestDataDir, configs, nil)
+	if err != nil {
+		t.Fatalf("Failed to build compatibility matrix: %v", err)
 	}
+	for i, entry := range matrix.Entries {
+		if entry.Statistics.CompatibleTests != results[implementations[i].name].CompatibleTests {
+			t.Errorf("%s: matrix reports %d compatible tests, stats report %d", implementations[i].name,
+				entry.Statistics.CompatibleTests, results[implementations[i].name].CompatibleTests)
+		}
+	}
+	t.Logf("Compatibility Report:\n%s", report.MatrixMarkdown(matrix))
 }
 
 func TestWorkflow_ContinuousIntegrationSimulation(t *testing.T) {
@@ -761,7 +774,7 @@ func TestWorkflow_ContinuousIntegrationSimulation(t *testing.T) {
 	baselineTests := []loader.CompactTest{
 		{
 			Name:     "baseline_test",
-			Input:    "key = value",
+			Inputs:   []string{"key = value"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{{"key": "key", "value": "value"}}},
@@ -827,7 +840,7 @@ func TestWorkflow_ContinuousIntegrationSimulation(t *testing.T) {
 		// Add new test (simulating PR changes)
 		expandedTests := append(baselineTests, loader.CompactTest{
 			Name:     "pr_addition",
-			Input:    "new_key = new_value\ncount = 5",
+			Inputs:   []string{"new_key = new_value\ncount = 5"},
 			Features: []string{},
 			Tests: []loader.CompactValidation{
 				{Function: "parse", Expect: []map[string]interface{}{
@@ -920,7 +933,7 @@ func TestWorkflow_ContinuousIntegrationSimulation(t *testing.T) {
 		invalidTests := []loader.CompactTest{
 			{
 				Name:     "invalid_test",
-				Input:    "",                              // Invalid: empty input
+				Inputs:   []string{""},                    // Invalid: empty input
 				Features: []string{"nonexistent_feature"}, // Invalid feature
 				Tests: []loader.CompactValidation{
 					{Function: "nonexistent_function", Expect: "something"}, // Invalid function
@@ -953,6 +966,272 @@ func TestWorkflow_ContinuousIntegrationSimulation(t *testing.T) {
 
 // Utility functions
 
+// TestWorkflow_DuplicateKeys takes a source file whose expectations repeat
+// a key through generation, loading, and a run against the toy implementation
+func TestWorkflow_DuplicateKeys(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	os.MkdirAll(sourceDir, 0755)
+	source := `{"tests": [{
+		"name": "repeated_servers",
+		"inputs": ["servers = a\nservers = b\nport = 80"],
+		"tests": [
+			{"function": "parse", "expect": [{"key": "servers", "value": "a"}, {"key": "servers", "value": "b"}, {"key": "port", "value": "80"}]},
+			{"function": "filter", "expect": [{"key": "servers", "value": "a"}, {"key": "servers", "value": "b"}, {"key": "port", "value": "80"}]}
+		]
+	}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "duplicate_keys.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	err := GenerateFlat(sourceDir, filepath.Join(root, "generated_tests"),
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	cfg := config.ImplementationConfig{
+		Name:               "toy",
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionFilter},
+		SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+	}
+	tests, err := LoadCompatibleTests(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(tests))
+	}
+	for _, test := range tests {
+		expected, ok := test.Expected.([]interface{})
+		if !ok || len(expected) != 3 {
+			t.Errorf("%s: expected 3 entries with both servers, got %v", test.Name, test.Expected)
+		}
+	}
+
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+	if results.Passed != 2 {
+		t.Errorf("Expected both tests to pass, got:\n%s", report.ResultsMarkdown(results))
+	}
+}
+
+// TestWorkflow_Formatting takes pretty_print and canonical_format source
+// tests through generation, loading, and a run against the toy
+// implementation, with and without trailing newline normalization
+func TestWorkflow_Formatting(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	os.MkdirAll(sourceDir, 0755)
+	source := `{"tests": [{
+		"name": "render_pairs",
+		"inputs": ["a =   b\nc = d"],
+		"tests": [
+			{"function": "pretty_print", "expect": "a = b\nc = d\n"},
+			{"function": "canonical_format", "expect": "a = b\nc = d"}
+		]
+	}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "formatting.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	generatedDir := filepath.Join(root, "generated_tests")
+	err := GenerateFlat(sourceDir, generatedDir,
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	if issues, err := loader.ValidateTestFile(filepath.Join(generatedDir, "formatting.json")); err != nil || len(issues) > 0 {
+		t.Fatalf("Expected the generated file to validate, got %v, %v", issues, err)
+	}
+
+	cfg := config.ImplementationConfig{
+		Name:               "toy",
+		SupportedFunctions: []config.CCLFunction{config.FunctionPrettyPrint, config.FunctionCanonicalFormat},
+	}
+	tests, err := LoadCompatibleTests(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(tests))
+	}
+	for _, test := range tests {
+		if _, ok := test.ExpectedString(); !ok {
+			t.Errorf("%s: expected a string expectation, got %#v", test.Name, test.Expected)
+		}
+	}
+
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+	if results.Passed != 1 || results.Failed != 1 {
+		t.Errorf("Expected only pretty_print to pass exactly, got:\n%s", report.ResultsMarkdown(results))
+	}
+	results = runner.Run(toyccl.New(), tests, runner.RunOptions{IgnoreTrailingNewline: true})
+	if results.Passed != 2 {
+		t.Errorf("Expected both tests to pass ignoring trailing newlines, got:\n%s", report.ResultsMarkdown(results))
+	}
+}
+
+// TestWorkflow_Combine takes a two-document combine source test through
+// generation, validation, loading, and a run against the toy implementation
+func TestWorkflow_Combine(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	os.MkdirAll(sourceDir, 0755)
+	source := `{"tests": [{
+		"name": "merge_documents",
+		"inputs": ["host = a\nport = 80", "port = 8080"],
+		"tests": [
+			{"function": "combine", "expect": [{"key": "host", "value": "a"}, {"key": "port", "value": "80"}, {"key": "port", "value": "8080"}]}
+		]
+	}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "combine.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	generatedDir := filepath.Join(root, "generated_tests")
+	err := GenerateFlat(sourceDir, generatedDir,
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	if issues, err := loader.ValidateTestFile(filepath.Join(generatedDir, "combine.json")); err != nil || len(issues) > 0 {
+		t.Fatalf("Expected the generated file to validate, got %v, %v", issues, err)
+	}
+
+	cfg := config.ImplementationConfig{
+		Name:               "toy",
+		SupportedFunctions: []config.CCLFunction{config.FunctionCombine},
+	}
+	tests, err := LoadCompatibleTests(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 1 {
+		t.Fatalf("Expected 1 test, got %d", len(tests))
+	}
+	if len(tests[0].Inputs) != 2 {
+		t.Errorf("Expected both documents as inputs, got %q", tests[0].Inputs)
+	}
+	if expected, ok := tests[0].Expected.([]interface{}); !ok || len(expected) != 3 {
+		t.Errorf("Expected 3 merged entries, got %v", tests[0].Expected)
+	}
+
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+	if results.Passed != 1 {
+		t.Errorf("Expected the combine test to pass, got:\n%s", report.ResultsMarkdown(results))
+	}
+}
+
+// TestWorkflow_ParseError takes an expect_parse_error source test through
+// generation, validation, loading, and a run against the toy implementation
+func TestWorkflow_ParseError(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	os.MkdirAll(sourceDir, 0755)
+	source := `{"tests": [
+		{"name": "missing_equals", "inputs": ["key without value"], "expect_parse_error": true},
+		{"name": "valid_pair", "inputs": ["a = b"], "expect_parse_error": true}
+	]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "errors.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	if findings, err := lint.LintDir(sourceDir, lint.Options{}); err != nil || len(findings) > 0 {
+		t.Fatalf("Expected the source file to lint clean, got %v, %v", findings, err)
+	}
+
+	generatedDir := filepath.Join(root, "generated_tests")
+	err := GenerateFlat(sourceDir, generatedDir,
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	if issues, err := loader.ValidateTestFile(filepath.Join(generatedDir, "errors.json")); err != nil || len(issues) > 0 {
+		t.Fatalf("Expected the generated file to validate, got %v, %v", issues, err)
+	}
+
+	cfg := config.ImplementationConfig{
+		Name:               "toy",
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse},
+	}
+	tests, err := LoadCompatibleTests(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(tests))
+	}
+	for _, test := range tests {
+		if test.Validation != "parse" || !test.ExpectError {
+			t.Errorf("%s: expected a parse test expecting an error, got %+v", test.Name, test)
+		}
+	}
+
+	// The toy parser rejects a line without '=' but accepts a valid pair
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+	want := map[string]runner.Status{"missing_equals_parse": runner.StatusPass, "valid_pair_parse": runner.StatusFail}
+	for _, result := range results.Results {
+		if result.Status != want[result.Name] {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, want[result.Name], result.Status, result.Message)
+		}
+	}
+}
+
+// TestWorkflow_Comments takes a source test asserting both the filtered
+// document and the extracted comments through generation, loading, and a
+// run against the toy implementation
+func TestWorkflow_Comments(t *testing.T) {
+	root := t.TempDir()
+	sourceDir := filepath.Join(root, "source_tests")
+	os.MkdirAll(sourceDir, 0755)
+	source := `{"tests": [{
+		"name": "comments_both_ways",
+		"inputs": ["/= first note\na = b\n/= second note\nc = d"],
+		"tests": [
+			{"function": "filter", "expect": [{"key": "a", "value": "b"}, {"key": "c", "value": "d"}]},
+			{"function": "extract_comments", "expect": [{"key": "/", "value": "first note"}, {"key": "/", "value": "second note"}]}
+		]
+	}]}`
+	if err := os.WriteFile(filepath.Join(sourceDir, "comments.json"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	generatedDir := filepath.Join(root, "generated_tests")
+	err := GenerateFlat(sourceDir, generatedDir,
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	if issues, err := loader.ValidateTestFile(filepath.Join(generatedDir, "comments.json")); err != nil || len(issues) > 0 {
+		t.Fatalf("Expected the generated file to validate, got %v, %v", issues, err)
+	}
+
+	// Both validations need the comments feature
+	cfg := config.ImplementationConfig{
+		Name:               "toy",
+		SupportedFunctions: []config.CCLFunction{config.FunctionFilter, config.FunctionExtractComments},
+	}
+	if tests, err := LoadCompatibleTests(root, cfg, WithAllowEmpty()); err != nil || len(tests) != 0 {
+		t.Fatalf("Expected no tests without the comments feature, got %d (%v)", len(tests), err)
+	}
+	cfg.SupportedFeatures = []config.CCLFeature{config.FeatureComments}
+	tests, err := LoadCompatibleTests(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(tests))
+	}
+	for _, test := range tests {
+		if test.SourceTest != "comments_both_ways" {
+			t.Errorf("%s: expected source test comments_both_ways, got %q", test.Name, test.SourceTest)
+		}
+	}
+
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+	if results.Passed != 2 {
+		t.Errorf("Expected filter and extract_comments to pass, got:\n%s", report.ResultsMarkdown(results))
+	}
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -0,0 +1,249 @@
+package schemasimplify
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CombinatorSummary records what flattenCombinators merged, dropped, and
+// could not reconcile, by JSON pointer
+type CombinatorSummary struct {
+	Merged    []string
+	Dropped   []string
+	Conflicts []string
+}
+
+// String lists the summary one entry per line
+func (s *CombinatorSummary) String() string {
+	var b strings.Builder
+	for _, line := range s.Merged {
+		fmt.Fprintf(&b, "merged:   %s\n", line)
+	}
+	for _, line := range s.Dropped {
+		fmt.Fprintf(&b, "dropped:  %s\n", line)
+	}
+	for _, line := range s.Conflicts {
+		fmt.Fprintf(&b, "conflict: %s\n", line)
+	}
+	return b.String()
+}
+
+// flattenCombinators rewrites allOf/anyOf/oneOf into plain object schemas
+// that go-jsonschema understands. allOf members are merged into the parent;
+// anyOf/oneOf of object schemas become a superset object with every
+// property optional. Combinators that cannot be flattened are dropped.
+func flattenCombinators(obj interface{}, path string, summary *CombinatorSummary) interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = flattenCombinators(value, path+"/"+escapePointer(key), summary)
+		}
+
+		if members, ok := result["allOf"].([]interface{}); ok {
+			delete(result, "allOf")
+			mergeAllOf(result, members, path, summary)
+		}
+		for _, key := range []string{"anyOf", "oneOf"} {
+			if members, ok := result[key].([]interface{}); ok {
+				delete(result, key)
+				mergeAlternatives(result, key, members, path, summary)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = flattenCombinators(item, fmt.Sprintf("%s/%d", path, i), summary)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// mergeAllOf merges every allOf member into the parent schema
+func mergeAllOf(parent map[string]interface{}, members []interface{}, path string, summary *CombinatorSummary) {
+	for i, member := range members {
+		schema, ok := member.(map[string]interface{})
+		if !ok {
+			summary.Dropped = append(summary.Dropped, fmt.Sprintf("%s/allOf/%d (not a schema object)", path, i))
+			continue
+		}
+		memberPath := fmt.Sprintf("%s/allOf/%d", path, i)
+		keys := sortedKeys(schema)
+		for _, key := range keys {
+			value := schema[key]
+			switch key {
+			case "properties":
+				mergeProperties(parent, value, memberPath, summary)
+			case "required":
+				parent["required"] = unionStrings(parent["required"], value)
+			default:
+				existing, exists := parent[key]
+				if !exists {
+					parent[key] = value
+				} else if !reflect.DeepEqual(existing, value) {
+					summary.Conflicts = append(summary.Conflicts, fmt.Sprintf("%s/%s (kept parent value)", memberPath, escapePointer(key)))
+				}
+			}
+		}
+	}
+	summary.Merged = append(summary.Merged, fmt.Sprintf("%s/allOf (%d members)", path, len(members)))
+}
+
+// mergeAlternatives turns anyOf/oneOf of object schemas into a superset
+// object. Alternatives that are not all objects are dropped.
+func mergeAlternatives(parent map[string]interface{}, key string, members []interface{}, path string, summary *CombinatorSummary) {
+	if values, typ, ok := enumAlternatives(members); ok {
+		parent["enum"] = unionValues(parent["enum"], values)
+		if _, exists := parent["type"]; !exists && typ != nil {
+			parent["type"] = typ
+		}
+		summary.Merged = append(summary.Merged, fmt.Sprintf("%s/%s (%d members, enum values merged)", path, key, len(members)))
+		return
+	}
+
+	for _, member := range members {
+		if !isObjectSchema(member) {
+			summary.Dropped = append(summary.Dropped, fmt.Sprintf("%s/%s (%d members, not all object schemas)", path, key, len(members)))
+			return
+		}
+	}
+
+	for i, member := range members {
+		schema := member.(map[string]interface{})
+		mergeProperties(parent, schema["properties"], fmt.Sprintf("%s/%s/%d", path, key, i), summary)
+	}
+	if _, ok := parent["type"]; !ok {
+		parent["type"] = "object"
+	}
+	summary.Merged = append(summary.Merged, fmt.Sprintf("%s/%s (%d members, properties optional)", path, key, len(members)))
+}
+
+// mergeProperties adds properties to the parent, keeping the parent's
+// definition when both define a property differently
+func mergeProperties(parent map[string]interface{}, value interface{}, memberPath string, summary *CombinatorSummary) {
+	properties, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	target, ok := parent["properties"].(map[string]interface{})
+	if !ok {
+		target = make(map[string]interface{}, len(properties))
+		parent["properties"] = target
+	}
+	for _, name := range sortedKeys(properties) {
+		existing, exists := target[name]
+		if !exists {
+			target[name] = properties[name]
+		} else if !reflect.DeepEqual(existing, properties[name]) {
+			summary.Conflicts = append(summary.Conflicts, fmt.Sprintf("%s/properties/%s (kept first definition)", memberPath, escapePointer(name)))
+		}
+	}
+}
+
+// enumAlternatives collects the values of alternatives that are each only
+// an enum or const, with the type they share (nil when none is given)
+func enumAlternatives(members []interface{}) ([]interface{}, interface{}, bool) {
+	var values []interface{}
+	var typ interface{}
+	for i, member := range members {
+		schema, ok := member.(map[string]interface{})
+		if !ok || !isEnumerated(schema) {
+			return nil, nil, false
+		}
+		for key, value := range schema {
+			switch key {
+			case "enum":
+				items, ok := value.([]interface{})
+				if !ok {
+					return nil, nil, false
+				}
+				values = append(values, items...)
+			case "const":
+				values = append(values, value)
+			case "type":
+				if i > 0 && !reflect.DeepEqual(typ, value) {
+					return nil, nil, false
+				}
+				typ = value
+			case "title", "description":
+			default:
+				return nil, nil, false
+			}
+		}
+		if _, hasType := schema["type"]; !hasType && typ != nil {
+			return nil, nil, false
+		}
+	}
+	return values, typ, true
+}
+
+// unionValues appends the values of b missing from a, keeping order
+func unionValues(a interface{}, b []interface{}) []interface{} {
+	result, _ := a.([]interface{})
+	result = append([]interface{}(nil), result...)
+	for _, value := range b {
+		found := false
+		for _, existing := range result {
+			if reflect.DeepEqual(existing, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// isObjectSchema reports whether a combinator member describes an object
+func isObjectSchema(member interface{}) bool {
+	schema, ok := member.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if schema["type"] == "object" {
+		return true
+	}
+	_, hasType := schema["type"]
+	_, hasProperties := schema["properties"]
+	return !hasType && hasProperties
+}
+
+// unionStrings appends the strings of b missing from a, keeping order
+func unionStrings(a, b interface{}) []interface{} {
+	var result []interface{}
+	seen := make(map[string]bool)
+	for _, list := range []interface{}{a, b} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			if name, ok := item.(string); ok {
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+			}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a JSON pointer reference token
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
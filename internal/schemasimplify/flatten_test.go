@@ -0,0 +1,153 @@
+package schemasimplify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// flattenJSON runs flattenCombinators on a schema string and returns the
+// indented result
+func flattenJSON(t *testing.T, input string) (string, *CombinatorSummary) {
+	t.Helper()
+	var schema interface{}
+	if err := json.Unmarshal([]byte(input), &schema); err != nil {
+		t.Fatalf("Invalid test schema: %v", err)
+	}
+	summary := &CombinatorSummary{}
+	output, err := json.MarshalIndent(flattenCombinators(schema, "", summary), "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	return string(output), summary
+}
+
+func TestFlattenCombinators_AllOf(t *testing.T) {
+	input := `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"name": {"type": "string"}},
+		"allOf": [
+			{"required": ["count"], "properties": {"count": {"type": "integer"}}},
+			{"required": ["name", "tags"], "properties": {"tags": {"type": "array"}}, "type": "array"}
+		]
+	}`
+	golden := `{
+  "properties": {
+    "count": {
+      "type": "integer"
+    },
+    "name": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "array"
+    }
+  },
+  "required": [
+    "name",
+    "count",
+    "tags"
+  ],
+  "type": "object"
+}`
+
+	got, summary := flattenJSON(t, input)
+	if got != golden {
+		t.Errorf("Flattened allOf mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if len(summary.Merged) != 1 || summary.Merged[0] != "/allOf (2 members)" {
+		t.Errorf("Unexpected merged summary: %v", summary.Merged)
+	}
+	if len(summary.Conflicts) != 1 || summary.Conflicts[0] != "/allOf/1/type (kept parent value)" {
+		t.Errorf("Expected type conflict reported, got %v", summary.Conflicts)
+	}
+}
+
+func TestFlattenCombinators_OneOfObjects(t *testing.T) {
+	input := `{
+		"properties": {
+			"expected": {
+				"oneOf": [
+					{"type": "object", "required": ["count"], "properties": {"count": {"type": "integer"}, "value": {"type": "string"}}},
+					{"type": "object", "required": ["error"], "properties": {"error": {"type": "boolean"}, "value": {"type": "number"}}}
+				]
+			}
+		}
+	}`
+	golden := `{
+  "properties": {
+    "expected": {
+      "properties": {
+        "count": {
+          "type": "integer"
+        },
+        "error": {
+          "type": "boolean"
+        },
+        "value": {
+          "type": "string"
+        }
+      },
+      "type": "object"
+    }
+  }
+}`
+
+	got, summary := flattenJSON(t, input)
+	if got != golden {
+		t.Errorf("Flattened oneOf mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if len(summary.Merged) != 1 || summary.Merged[0] != "/properties/expected/oneOf (2 members, properties optional)" {
+		t.Errorf("Unexpected merged summary: %v", summary.Merged)
+	}
+	if len(summary.Conflicts) != 1 || summary.Conflicts[0] != "/properties/expected/oneOf/1/properties/value (kept first definition)" {
+		t.Errorf("Expected value conflict reported, got %v", summary.Conflicts)
+	}
+}
+
+func TestFlattenCombinators_AnyOfNonObjectsDropped(t *testing.T) {
+	input := `{
+		"type": "array",
+		"items": {
+			"type": "string",
+			"anyOf": [{"enum": ["comments", "unicode"]}, {"pattern": "^experimental_"}]
+		}
+	}`
+	golden := `{
+  "items": {
+    "type": "string"
+  },
+  "type": "array"
+}`
+
+	got, summary := flattenJSON(t, input)
+	if got != golden {
+		t.Errorf("Dropped anyOf mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if len(summary.Dropped) != 1 || !strings.HasPrefix(summary.Dropped[0], "/items/anyOf (2 members") {
+		t.Errorf("Expected anyOf reported as dropped, got %v", summary.Dropped)
+	}
+	if len(summary.Merged) != 0 {
+		t.Errorf("Expected nothing merged, got %v", summary.Merged)
+	}
+}
+
+func TestFlattenCombinators_NestedAllOf(t *testing.T) {
+	input := `{"allOf": [{"allOf": [{"properties": {"a/b": {"type": "string"}}}]}]}`
+	golden := `{
+  "properties": {
+    "a/b": {
+      "type": "string"
+    }
+  }
+}`
+
+	got, summary := flattenJSON(t, input)
+	if got != golden {
+		t.Errorf("Nested allOf mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if len(summary.Merged) != 2 || summary.Merged[0] != "/allOf/0/allOf (1 members)" {
+		t.Errorf("Expected inner allOf merged first, got %v", summary.Merged)
+	}
+}
@@ -0,0 +1,283 @@
+package schemasimplify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// definitionContainers hold reusable schemas referenced by local $refs
+var definitionContainers = []string{"definitions", "$defs"}
+
+// RefSummary records what resolveRefs inlined, kept, and could not resolve
+type RefSummary struct {
+	Inlined  map[string]int // Local refs inlined, with the number of uses
+	Kept     []string       // Refs left in place because they are shared
+	External []string       // Refs outside this document, left untouched
+}
+
+// String lists the summary one entry per line
+func (s *RefSummary) String() string {
+	var b strings.Builder
+	refs := make([]string, 0, len(s.Inlined))
+	for ref := range s.Inlined {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		fmt.Fprintf(&b, "inlined:  %s (%d uses)\n", ref, s.Inlined[ref])
+	}
+	for _, ref := range s.Kept {
+		fmt.Fprintf(&b, "kept:     %s\n", ref)
+	}
+	for _, ref := range s.External {
+		fmt.Fprintf(&b, "external: %s\n", ref)
+	}
+	return b.String()
+}
+
+// refResolver inlines local $refs against a root document
+type refResolver struct {
+	root     map[string]interface{}
+	keepOver int
+	counts   map[string]int
+	kept     map[string]bool
+	stack    []string
+	summary  *RefSummary
+}
+
+// resolveRefs inlines local $refs. Refs used more than keepOver times stay
+// in place with their definitions when keepOver is positive; definitions
+// nothing refers to any more are removed. A reference cycle is an error.
+func resolveRefs(root map[string]interface{}, keepOver int, summary *RefSummary) (map[string]interface{}, error) {
+	r := &refResolver{
+		root:     root,
+		keepOver: keepOver,
+		counts:   make(map[string]int),
+		kept:     make(map[string]bool),
+		summary:  summary,
+	}
+	if summary.Inlined == nil {
+		summary.Inlined = make(map[string]int)
+	}
+	countRefs(root, r.counts)
+
+	result := make(map[string]interface{}, len(root))
+	for key, value := range root {
+		if isDefinitionContainer(key) {
+			continue
+		}
+		resolved, err := r.resolve(value, "/"+escapePointer(key))
+		if err != nil {
+			return nil, err
+		}
+		result[key] = resolved
+	}
+
+	// Resolving a kept definition can keep further definitions
+	done := make(map[string]bool)
+	for {
+		var pending []string
+		for ref := range r.kept {
+			if !done[ref] {
+				pending = append(pending, ref)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		sort.Strings(pending)
+		for _, ref := range pending {
+			done[ref] = true
+			if err := r.keepDefinition(result, ref); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	kept := make([]string, 0, len(r.kept))
+	for ref := range r.kept {
+		kept = append(kept, ref)
+	}
+	sort.Strings(kept)
+	summary.Kept = append(summary.Kept, kept...)
+	return result, nil
+}
+
+func (r *refResolver) resolve(obj interface{}, path string) (interface{}, error) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			return r.resolveRef(v, ref, path)
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			resolved, err := r.resolve(value, path+"/"+escapePointer(key))
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := r.resolve(item, fmt.Sprintf("%s/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+// resolveRef replaces a $ref node with a copy of its target. Sibling
+// keywords of the $ref are kept and take precedence over the target's.
+func (r *refResolver) resolveRef(node map[string]interface{}, ref, path string) (interface{}, error) {
+	siblings := make(map[string]interface{}, len(node))
+	for key, value := range node {
+		if key == "$ref" {
+			continue
+		}
+		resolved, err := r.resolve(value, path+"/"+escapePointer(key))
+		if err != nil {
+			return nil, err
+		}
+		siblings[key] = resolved
+	}
+
+	if !strings.HasPrefix(ref, "#/") {
+		r.summary.External = append(r.summary.External, fmt.Sprintf("%s at %s", ref, path))
+		siblings["$ref"] = ref
+		return siblings, nil
+	}
+	if r.keepOver > 0 && r.counts[ref] > r.keepOver {
+		r.kept[ref] = true
+		siblings["$ref"] = ref
+		return siblings, nil
+	}
+
+	for i, active := range r.stack {
+		if active == ref {
+			cycle := append(append([]string{}, r.stack[i:]...), ref)
+			return nil, fmt.Errorf("$ref cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	target, err := lookupPointer(r.root, ref)
+	if err != nil {
+		return nil, fmt.Errorf("unresolved $ref %s at %s: %w", ref, path, err)
+	}
+
+	r.stack = append(r.stack, ref)
+	resolved, err := r.resolve(target, path)
+	r.stack = r.stack[:len(r.stack)-1]
+	if err != nil {
+		return nil, err
+	}
+	r.summary.Inlined[ref]++
+
+	schema, ok := resolved.(map[string]interface{})
+	if !ok {
+		return resolved, nil
+	}
+	for key, value := range siblings {
+		schema[key] = value
+	}
+	return schema, nil
+}
+
+// keepDefinition copies the definition a kept ref points into back into
+// the result, resolving its own refs
+func (r *refResolver) keepDefinition(result map[string]interface{}, ref string) error {
+	tokens := pointerTokens(ref)
+	if len(tokens) < 2 || !isDefinitionContainer(tokens[0]) {
+		return nil
+	}
+	container, name := tokens[0], tokens[1]
+	definitions, ok := r.root[container].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unresolved $ref %s", ref)
+	}
+	definition, ok := definitions[name]
+	if !ok {
+		return fmt.Errorf("unresolved $ref %s", ref)
+	}
+
+	target, ok := result[container].(map[string]interface{})
+	if !ok {
+		target = make(map[string]interface{})
+		result[container] = target
+	}
+	if _, done := target[name]; done {
+		return nil
+	}
+	resolved, err := r.resolve(definition, "/"+escapePointer(container)+"/"+escapePointer(name))
+	if err != nil {
+		return err
+	}
+	target[name] = resolved
+	return nil
+}
+
+// countRefs counts each $ref value in the document
+func countRefs(obj interface{}, counts map[string]int) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			counts[ref]++
+		}
+		for _, value := range v {
+			countRefs(value, counts)
+		}
+	case []interface{}:
+		for _, item := range v {
+			countRefs(item, counts)
+		}
+	}
+}
+
+// lookupPointer finds the value a "#/..." JSON pointer refers to
+func lookupPointer(root interface{}, ref string) (interface{}, error) {
+	current := root
+	for _, token := range pointerTokens(ref) {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("no %q", token)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("no index %q", token)
+			}
+			current = v[index]
+		default:
+			return nil, fmt.Errorf("cannot descend into %q", token)
+		}
+	}
+	return current, nil
+}
+
+// pointerTokens splits and unescapes a "#/..." JSON pointer
+func pointerTokens(ref string) []string {
+	tokens := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func isDefinitionContainer(key string) bool {
+	for _, container := range definitionContainers {
+		if key == container {
+			return true
+		}
+	}
+	return false
+}
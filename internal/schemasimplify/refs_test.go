@@ -0,0 +1,194 @@
+package schemasimplify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// resolveJSON runs resolveRefs on a schema string and returns the indented
+// result
+func resolveJSON(t *testing.T, input string, keepOver int) (string, *RefSummary, error) {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(input), &schema); err != nil {
+		t.Fatalf("Invalid test schema: %v", err)
+	}
+	summary := &RefSummary{}
+	resolved, err := resolveRefs(schema, keepOver, summary)
+	if err != nil {
+		return "", summary, err
+	}
+	output, err := json.MarshalIndent(resolved, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	return string(output), summary, nil
+}
+
+func TestResolveRefs_Simple(t *testing.T) {
+	input := `{
+		"type": "array",
+		"items": {"$ref": "#/definitions/entry", "description": "An entry"},
+		"definitions": {
+			"entry": {"type": "object", "properties": {"key": {"type": "string"}}}
+		}
+	}`
+	golden := `{
+  "items": {
+    "description": "An entry",
+    "properties": {
+      "key": {
+        "type": "string"
+      }
+    },
+    "type": "object"
+  },
+  "type": "array"
+}`
+
+	got, summary, err := resolveJSON(t, input, 0)
+	if err != nil {
+		t.Fatalf("resolveRefs failed: %v", err)
+	}
+	if got != golden {
+		t.Errorf("Resolved schema mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if summary.Inlined["#/definitions/entry"] != 1 {
+		t.Errorf("Expected one inlined use, got %v", summary.Inlined)
+	}
+}
+
+func TestResolveRefs_Nested(t *testing.T) {
+	input := `{
+		"properties": {
+			"first": {"$ref": "#/$defs/pair"},
+			"second": {"$ref": "#/$defs/pair"}
+		},
+		"$defs": {
+			"pair": {"type": "object", "properties": {"value": {"$ref": "#/$defs/value"}}},
+			"value": {"type": "string", "enum": ["a", "b"]}
+		}
+	}`
+	golden := `{
+  "properties": {
+    "first": {
+      "properties": {
+        "value": {
+          "enum": [
+            "a",
+            "b"
+          ],
+          "type": "string"
+        }
+      },
+      "type": "object"
+    },
+    "second": {
+      "properties": {
+        "value": {
+          "enum": [
+            "a",
+            "b"
+          ],
+          "type": "string"
+        }
+      },
+      "type": "object"
+    }
+  }
+}`
+
+	got, summary, err := resolveJSON(t, input, 0)
+	if err != nil {
+		t.Fatalf("resolveRefs failed: %v", err)
+	}
+	if got != golden {
+		t.Errorf("Resolved schema mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if summary.Inlined["#/$defs/pair"] != 2 || summary.Inlined["#/$defs/value"] != 2 {
+		t.Errorf("Unexpected inline counts: %v", summary.Inlined)
+	}
+}
+
+func TestResolveRefs_Cycle(t *testing.T) {
+	input := `{
+		"properties": {"root": {"$ref": "#/definitions/node"}},
+		"definitions": {
+			"node": {"type": "object", "properties": {"child": {"$ref": "#/definitions/branch"}}},
+			"branch": {"type": "object", "properties": {"node": {"$ref": "#/definitions/node"}}}
+		}
+	}`
+
+	_, _, err := resolveJSON(t, input, 0)
+	if err == nil {
+		t.Fatal("Expected cycle error")
+	}
+	want := "$ref cycle: #/definitions/node -> #/definitions/branch -> #/definitions/node"
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestResolveRefs_KeepShared(t *testing.T) {
+	input := `{
+		"properties": {
+			"a": {"$ref": "#/definitions/shared"},
+			"b": {"$ref": "#/definitions/shared"},
+			"c": {"$ref": "#/definitions/once"}
+		},
+		"definitions": {
+			"shared": {"type": "string"},
+			"once": {"type": "integer"},
+			"unused": {"type": "boolean"}
+		}
+	}`
+	golden := `{
+  "definitions": {
+    "shared": {
+      "type": "string"
+    }
+  },
+  "properties": {
+    "a": {
+      "$ref": "#/definitions/shared"
+    },
+    "b": {
+      "$ref": "#/definitions/shared"
+    },
+    "c": {
+      "type": "integer"
+    }
+  }
+}`
+
+	got, summary, err := resolveJSON(t, input, 1)
+	if err != nil {
+		t.Fatalf("resolveRefs failed: %v", err)
+	}
+	if got != golden {
+		t.Errorf("Resolved schema mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if len(summary.Kept) != 1 || summary.Kept[0] != "#/definitions/shared" {
+		t.Errorf("Expected shared ref kept, got %v", summary.Kept)
+	}
+}
+
+func TestResolveRefs_ExternalAndMissing(t *testing.T) {
+	input := `{"properties": {"remote": {"$ref": "other.json#/definitions/x"}}}`
+	got, summary, err := resolveJSON(t, input, 0)
+	if err != nil {
+		t.Fatalf("resolveRefs failed: %v", err)
+	}
+	if !strings.Contains(got, `"$ref": "other.json#/definitions/x"`) {
+		t.Errorf("Expected external ref untouched, got:\n%s", got)
+	}
+	if len(summary.External) != 1 || summary.External[0] != "other.json#/definitions/x at /properties/remote" {
+		t.Errorf("Expected external ref reported, got %v", summary.External)
+	}
+
+	_, _, err = resolveJSON(t, `{"items": {"$ref": "#/definitions/missing"}}`, 0)
+	if err == nil || !strings.Contains(err.Error(), "#/definitions/missing at /items") {
+		t.Errorf("Expected unresolved ref error naming the location, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+// Package schemasimplify removes go-jsonschema incompatible features from
+// JSON schemas: it inlines local $refs, flattens combinators, and strips
+// keywords that go-jsonschema cannot use.
+package schemasimplify
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule is a keyword removed during simplification
+type Rule struct {
+	Keyword string
+	Reason  string
+}
+
+// DefaultRemovals are the keywords removed unless kept with Options.Keep
+var DefaultRemovals = []Rule{
+	{"allOf", "combinators left unflattened - go-jsonschema can't handle these well"},
+	{"anyOf", "combinators left unflattened - go-jsonschema can't handle these well"},
+	{"oneOf", "combinators left unflattened - go-jsonschema can't handle these well"},
+	{"if", "conditional validation - incompatible with go-jsonschema"},
+	{"then", "conditional validation - incompatible with go-jsonschema"},
+	{"else", "conditional validation - incompatible with go-jsonschema"},
+	{"additionalProperties", "can cause issues in some contexts"},
+	{"pattern", "regex patterns - not needed for type generation"},
+	{"description", "reduces output size"},
+	{"default", "go-jsonschema doesn't use them"},
+}
+
+// namedSchemas are keywords whose object keys are names mapping to schemas
+var namedSchemas = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"definitions":       true,
+	"$defs":             true,
+}
+
+// enumKeywords are kept together in any schema with enum or const, so
+// go-jsonschema still generates a distinct Go enum type for it
+var enumKeywords = map[string]bool{
+	"enum":  true,
+	"const": true,
+	"type":  true,
+}
+
+// isEnumerated reports whether a schema restricts values with enum or const
+func isEnumerated(schema map[string]interface{}) bool {
+	_, hasEnum := schema["enum"]
+	_, hasConst := schema["const"]
+	return hasEnum || hasConst
+}
+
+// Options controls simplification
+type Options struct {
+	Keep            []string // Keywords retained even when removed by default
+	Remove          []string // Keywords removed in addition to the defaults (never enum/const/type beside enum or const)
+	DropCombinators bool     // Drop allOf/anyOf/oneOf instead of flattening them
+	KeepSharedRefs  int      // Keep $refs used more than this many times (0 inlines all)
+}
+
+// Simplifier simplifies schemas and records what it changed
+type Simplifier struct {
+	opts     Options
+	removals map[string]string
+
+	Refs        RefSummary
+	Combinators CombinatorSummary
+	Removed     []Removal
+}
+
+// Removal is a keyword stripped from the schema at Path, a JSON pointer
+// fragment ("#" for the root) of the flattened schema
+type Removal struct {
+	Path    string
+	Keyword string
+}
+
+// New creates a simplifier with DefaultRemovals adjusted by opts
+func New(opts Options) *Simplifier {
+	removals := make(map[string]string, len(DefaultRemovals)+len(opts.Remove))
+	for _, rule := range DefaultRemovals {
+		removals[rule.Keyword] = rule.Reason
+	}
+	for _, keyword := range opts.Remove {
+		removals[keyword] = "removed by request"
+	}
+	for _, keyword := range opts.Keep {
+		delete(removals, keyword)
+	}
+	return &Simplifier{opts: opts, removals: removals}
+}
+
+// Removals lists the keywords this simplifier removes, sorted
+func (s *Simplifier) Removals() []string {
+	keywords := make([]string, 0, len(s.removals))
+	for keyword := range s.removals {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+// Simplify returns a simplified copy of a parsed schema
+func (s *Simplifier) Simplify(schema map[string]interface{}) (interface{}, error) {
+	// Inline local $refs before anything moves their targets
+	resolved, err := resolveRefs(schema, s.opts.KeepSharedRefs, &s.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flatten combinators so their properties survive simplification
+	var flattened interface{} = resolved
+	if !s.opts.DropCombinators {
+		flattened = flattenCombinators(resolved, "", &s.Combinators)
+	}
+
+	return s.removeKeywords(flattened, "#"), nil
+}
+
+// SimplifyJSON simplifies schema JSON and returns the indented result. Object
+// keys are sorted, so the output does not depend on input key order.
+func (s *Simplifier) SimplifyJSON(data []byte) ([]byte, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	simplified, err := s.Simplify(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := json.MarshalIndent(simplified, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return output, nil
+}
+
+// Summary describes the refs and combinators handled so far, one per line
+func (s *Simplifier) Summary() string {
+	return s.Refs.String() + s.Combinators.String()
+}
+
+// removeKeywords drops removed keywords at every level of the schema
+func (s *Simplifier) removeKeywords(obj interface{}, path string) interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		enumerated := isEnumerated(v)
+		for key, value := range v {
+			if _, remove := s.removals[key]; remove && !(enumerated && enumKeywords[key]) {
+				s.Removed = append(s.Removed, Removal{Path: path, Keyword: key})
+				continue
+			}
+			if namedSchemas[key] {
+				// Keys here are property or definition names, not keywords
+				result[key] = s.removeFromNamed(value, path+"/"+escapePointer(key))
+				continue
+			}
+			result[key] = s.removeKeywords(value, path+"/"+escapePointer(key))
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = s.removeKeywords(item, fmt.Sprintf("%s/%d", path, i))
+		}
+		return result
+	default:
+		// Primitive values (string, number, bool) - keep as-is
+		return v
+	}
+}
+
+func (s *Simplifier) removeFromNamed(obj interface{}, path string) interface{} {
+	named, ok := obj.(map[string]interface{})
+	if !ok {
+		return s.removeKeywords(obj, path)
+	}
+	result := make(map[string]interface{}, len(named))
+	for name, schema := range named {
+		result[name] = s.removeKeywords(schema, path+"/"+escapePointer(name))
+	}
+	return result
+}
+
+// sortedRemovals returns the removals ordered by path, then keyword
+func (s *Simplifier) sortedRemovals() []Removal {
+	removals := append([]Removal(nil), s.Removed...)
+	sort.Slice(removals, func(i, j int) bool {
+		if removals[i].Path != removals[j].Path {
+			return removals[i].Path < removals[j].Path
+		}
+		return removals[i].Keyword < removals[j].Keyword
+	})
+	return removals
+}
+
+// RemovalReport lists every removed keyword by path, then counts per
+// keyword. The output is deterministic for a given schema and options.
+func (s *Simplifier) RemovalReport() string {
+	var b strings.Builder
+	removals := s.sortedRemovals()
+	fmt.Fprintf(&b, "Removed %d keywords:\n", len(removals))
+	counts := make(map[string]int)
+	for _, removal := range removals {
+		fmt.Fprintf(&b, "  %s: %s\n", removal.Path, removal.Keyword)
+		counts[removal.Keyword]++
+	}
+
+	keywords := make([]string, 0, len(counts))
+	for keyword := range counts {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	if len(keywords) > 0 {
+		fmt.Fprintln(&b, "Counts:")
+	}
+	for _, keyword := range keywords {
+		fmt.Fprintf(&b, "  %s: %d\n", keyword, counts[keyword])
+	}
+	return b.String()
+}
+
+// ProtectedRemovals returns the removals whose keyword matches pattern
+func (s *Simplifier) ProtectedRemovals(pattern *regexp.Regexp) []Removal {
+	var matched []Removal
+	for _, removal := range s.sortedRemovals() {
+		if pattern.MatchString(removal.Keyword) {
+			matched = append(matched, removal)
+		}
+	}
+	return matched
+}
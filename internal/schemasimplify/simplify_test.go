@@ -0,0 +1,306 @@
+package schemasimplify
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+const keywordSchema = `{
+  "type": "object",
+  "description": "Root",
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "description": "Name", "pattern": "^[a-z]+$", "minLength": 1},
+    "count": {"type": "integer", "default": 0},
+    "description": {"type": "string", "default": ""}
+  }
+}`
+
+func TestSimplifier_KeepAndRemove(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		golden string
+	}{
+		{"defaults", Options{}, `{
+  "properties": {
+    "count": {
+      "type": "integer"
+    },
+    "description": {
+      "type": "string"
+    },
+    "name": {
+      "minLength": 1,
+      "type": "string"
+    }
+  },
+  "type": "object"
+}`},
+		{"keep description and default", Options{Keep: []string{"description", "default"}}, `{
+  "description": "Root",
+  "properties": {
+    "count": {
+      "default": 0,
+      "type": "integer"
+    },
+    "description": {
+      "default": "",
+      "type": "string"
+    },
+    "name": {
+      "description": "Name",
+      "minLength": 1,
+      "type": "string"
+    }
+  },
+  "type": "object"
+}`},
+		{"remove minLength", Options{Remove: []string{"minLength"}}, `{
+  "properties": {
+    "count": {
+      "type": "integer"
+    },
+    "description": {
+      "type": "string"
+    },
+    "name": {
+      "type": "string"
+    }
+  },
+  "type": "object"
+}`},
+		{"keep wins over remove", Options{Keep: []string{"pattern"}, Remove: []string{"pattern", "minLength"}}, `{
+  "properties": {
+    "count": {
+      "type": "integer"
+    },
+    "description": {
+      "type": "string"
+    },
+    "name": {
+      "pattern": "^[a-z]+$",
+      "type": "string"
+    }
+  },
+  "type": "object"
+}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New(tt.opts).SimplifyJSON([]byte(keywordSchema))
+			if err != nil {
+				t.Fatalf("SimplifyJSON failed: %v", err)
+			}
+			if string(output) != tt.golden {
+				t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", output, tt.golden)
+			}
+		})
+	}
+}
+
+func TestSimplifier_DropCombinators(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"description": "removed",
+		"allOf": [{"required": ["count"], "properties": {"count": {"type": "integer", "default": 0}}}]
+	}`
+
+	tests := []struct {
+		name            string
+		dropCombinators bool
+		golden          string
+	}{
+		{"flatten", false, `{
+  "properties": {
+    "count": {
+      "type": "integer"
+    }
+  },
+  "required": [
+    "count"
+  ],
+  "type": "object"
+}`},
+		{"drop", true, `{
+  "type": "object"
+}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := New(Options{DropCombinators: tt.dropCombinators}).SimplifyJSON([]byte(schema))
+			if err != nil {
+				t.Fatalf("SimplifyJSON failed: %v", err)
+			}
+			if string(output) != tt.golden {
+				t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", output, tt.golden)
+			}
+		})
+	}
+}
+
+func TestSimplifier_Removals(t *testing.T) {
+	got := New(Options{Keep: []string{"description", "if", "then", "else"}, Remove: []string{"minLength"}}).Removals()
+	want := []string{"additionalProperties", "allOf", "anyOf", "default", "minLength", "oneOf", "pattern"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected removals %v, got %v", want, got)
+	}
+}
+
+func TestSimplifier_InvalidJSON(t *testing.T) {
+	if _, err := New(Options{}).SimplifyJSON([]byte(`{"type": `)); err == nil {
+		t.Error("Expected parse error")
+	}
+}
+
+func TestSimplifier_RemovalReport(t *testing.T) {
+	schema := `{
+		"description": "Root",
+		"properties": {
+			"a/b": {"type": "object", "properties": {"inner": {"type": "string", "pattern": "x", "description": "Inner"}}},
+			"list": {"type": "array", "items": [{"type": "string", "default": ""}]},
+			"pattern": {"type": "string", "pattern": "y"}
+		}
+	}`
+	golden := `Removed 5 keywords:
+  #: description
+  #/properties/a~1b/properties/inner: description
+  #/properties/a~1b/properties/inner: pattern
+  #/properties/list/items/0: default
+  #/properties/pattern: pattern
+Counts:
+  default: 1
+  description: 2
+  pattern: 2
+`
+
+	for i := 0; i < 3; i++ {
+		simplifier := New(Options{})
+		if _, err := simplifier.SimplifyJSON([]byte(schema)); err != nil {
+			t.Fatalf("SimplifyJSON failed: %v", err)
+		}
+		if got := simplifier.RemovalReport(); got != golden {
+			t.Fatalf("Report mismatch on run %d.\nGot:\n%s\nWant:\n%s", i, got, golden)
+		}
+	}
+}
+
+func TestSimplifier_ProtectedRemovals(t *testing.T) {
+	simplifier := New(Options{Remove: []string{"required", "requiredness"}})
+	schema := `{"required": ["a"], "properties": {"a": {"required": ["b"], "requiredness": 1, "type": "object"}}}`
+	if _, err := simplifier.SimplifyJSON([]byte(schema)); err != nil {
+		t.Fatalf("SimplifyJSON failed: %v", err)
+	}
+
+	got := simplifier.ProtectedRemovals(regexp.MustCompile("^(?:required)$"))
+	want := []Removal{{Path: "#", Keyword: "required"}, {Path: "#/properties/a", Keyword: "required"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// behaviorsSnippet is the behaviors property from generated-format.json
+const behaviorsSnippet = `{
+  "type": "object",
+  "properties": {
+    "behaviors": {
+      "type": "array",
+      "description": "Implementation behavior choices",
+      "items": {
+        "type": "string",
+        "enum": [
+          "boolean_strict", "boolean_lenient",
+          "crlf_preserve_literal", "crlf_normalize_to_lf",
+          "tabs_as_content", "tabs_as_whitespace",
+          "indent_spaces", "indent_tabs",
+          "list_coercion_enabled", "list_coercion_disabled",
+          "array_order_insertion", "array_order_lexicographic"
+        ]
+      },
+      "uniqueItems": true
+    }
+  }
+}`
+
+func TestSimplifier_BehaviorsEnumRetained(t *testing.T) {
+	for _, opts := range []Options{{}, {Remove: []string{"type", "enum", "const"}}} {
+		output, err := New(opts).SimplifyJSON([]byte(behaviorsSnippet))
+		if err != nil {
+			t.Fatalf("SimplifyJSON failed: %v", err)
+		}
+
+		var schema struct {
+			Properties struct {
+				Behaviors struct {
+					Items struct {
+						Type string   `json:"type"`
+						Enum []string `json:"enum"`
+					} `json:"items"`
+				} `json:"behaviors"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal(output, &schema); err != nil {
+			t.Fatalf("Invalid output: %v", err)
+		}
+		items := schema.Properties.Behaviors.Items
+		if items.Type != "string" {
+			t.Errorf("Options %+v: expected enum items to keep type string, got %q", opts, items.Type)
+		}
+		distinct := make(map[string]bool)
+		for _, value := range items.Enum {
+			distinct[value] = true
+		}
+		if len(distinct) != 12 {
+			t.Errorf("Options %+v: expected 12 distinct behaviors, got %v", opts, items.Enum)
+		}
+	}
+}
+
+func TestSimplifier_OneOfConstsBecomeEnum(t *testing.T) {
+	schema := `{"oneOf": [
+		{"const": "insertion", "type": "string", "description": "Keep order"},
+		{"const": "lexicographic", "type": "string"},
+		{"enum": ["insertion", "reverse"], "type": "string"}
+	]}`
+	golden := `{
+  "enum": [
+    "insertion",
+    "lexicographic",
+    "reverse"
+  ],
+  "type": "string"
+}`
+
+	simplifier := New(Options{})
+	output, err := simplifier.SimplifyJSON([]byte(schema))
+	if err != nil {
+		t.Fatalf("SimplifyJSON failed: %v", err)
+	}
+	if string(output) != golden {
+		t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", output, golden)
+	}
+	if len(simplifier.Combinators.Merged) != 1 {
+		t.Errorf("Expected oneOf merged, got %v", simplifier.Combinators)
+	}
+}
+
+func TestSimplifier_OutputIndependentOfKeyOrder(t *testing.T) {
+	a := `{"type": "object", "required": ["b", "a"], "properties": {"b": {"enum": ["y", "x"], "type": "string"}, "a": {"type": "integer"}}}`
+	b := `{"properties": {"a": {"type": "integer"}, "b": {"type": "string", "enum": ["y", "x"]}}, "required": ["b", "a"], "type": "object"}`
+
+	first, err := New(Options{}).SimplifyJSON([]byte(a))
+	if err != nil {
+		t.Fatalf("SimplifyJSON failed: %v", err)
+	}
+	second, err := New(Options{}).SimplifyJSON([]byte(b))
+	if err != nil {
+		t.Fatalf("SimplifyJSON failed: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("Expected identical output for reordered input.\nFirst:\n%s\nSecond:\n%s", first, second)
+	}
+}
@@ -0,0 +1,399 @@
+// Package toyccl is a deliberately small CCL implementation used to exercise
+// the runner in this repository's own tests. It is not a reference parser.
+package toyccl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Implementation is the toy CCL implementation
+type Implementation struct{}
+
+// New creates a toy implementation
+func New() *Implementation {
+	return &Implementation{}
+}
+
+// Config returns the capabilities the toy implementation actually has
+func Config() config.ImplementationConfig {
+	return config.ImplementationConfig{
+		Name:    "toyccl",
+		Version: "v0.0.0",
+		SupportedFunctions: []config.CCLFunction{
+			config.FunctionParse,
+			config.FunctionParseIndented,
+			config.FunctionFilter,
+			config.FunctionExtractComments,
+			config.FunctionCombine,
+			config.FunctionBuildHierarchy,
+			config.FunctionGetString,
+			config.FunctionGetInt,
+			config.FunctionGetBool,
+			config.FunctionGetFloat,
+			config.FunctionGetList,
+			config.FunctionPrettyPrint,
+			config.FunctionCanonicalFormat,
+		},
+		SupportedFeatures: []config.CCLFeature{
+			config.FeatureComments,
+			config.FeatureEmptyKeys,
+			config.FeatureMultiline,
+			config.FeatureUnicode,
+			config.FeatureWhitespace,
+		},
+		BehaviorChoices: []config.CCLBehavior{
+			config.BehaviorCRLFNormalize,
+			config.BehaviorTabsAsWhitespace,
+			config.BehaviorIndentSpaces,
+			config.BehaviorBooleanStrict,
+			config.BehaviorListCoercionOff,
+		},
+		VariantChoice: config.VariantReference,
+	}
+}
+
+// Parse splits input into key/value entries. A line containing '=' starts an
+// entry; following lines indented deeper than the entry are continuation lines.
+func (impl *Implementation) Parse(input string) ([]types.Entry, error) {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	lines := strings.Split(input, "\n")
+
+	baseIndent := -1
+	var entries []types.Entry
+	var current *types.Entry
+	for lineNum, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+
+		if trimmed == "" {
+			if current != nil {
+				current.Value += "\n"
+			}
+			continue
+		}
+		if baseIndent < 0 {
+			baseIndent = indent
+		}
+
+		if current != nil && indent > baseIndent {
+			current.Value += "\n" + line
+			continue
+		}
+
+		eq := strings.Index(trimmed, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: missing '=' in %q", lineNum+1, trimmed)
+		}
+		entries = append(entries, types.Entry{
+			Key:   strings.TrimSpace(trimmed[:eq]),
+			Value: strings.TrimLeft(trimmed[eq+1:], " \t"),
+		})
+		current = &entries[len(entries)-1]
+	}
+
+	for i := range entries {
+		entries[i].Value = strings.TrimRight(entries[i].Value, " \t\n")
+	}
+	return entries, nil
+}
+
+// ParseIndented parses input whose entries share a common leading indentation.
+// Parse already measures indentation relative to the first entry.
+func (impl *Implementation) ParseIndented(input string) ([]types.Entry, error) {
+	return impl.Parse(input)
+}
+
+// Filter parses input and drops comment entries (key "/")
+func (impl *Implementation) Filter(input string) ([]types.Entry, error) {
+	entries, err := impl.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]types.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Key != "/" {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+// ExtractComments parses input and keeps only the comment entries Filter
+// drops
+func (impl *Implementation) ExtractComments(input string) ([]types.Entry, error) {
+	entries, err := impl.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]types.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Key == "/" {
+			comments = append(comments, entry)
+		}
+	}
+	return comments, nil
+}
+
+// Combine parses both documents and concatenates their entries, a's first
+func (impl *Implementation) Combine(a, b string) ([]types.Entry, error) {
+	first, err := impl.Parse(a)
+	if err != nil {
+		return nil, err
+	}
+	second, err := impl.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// ExpandDotted is not supported by the toy implementation
+func (impl *Implementation) ExpandDotted(input string) ([]types.Entry, error) {
+	return nil, fmt.Errorf("expand_dotted: not supported")
+}
+
+// BuildHierarchy recursively parses values containing '=' into nested objects.
+// Repeated keys with scalar values become lists.
+func (impl *Implementation) BuildHierarchy(input string) (map[string]interface{}, error) {
+	entries, err := impl.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildObject(entries)
+}
+
+func (impl *Implementation) buildObject(entries []types.Entry) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, entry := range entries {
+		var value interface{} = entry.Value
+		if strings.Contains(entry.Value, "=") {
+			nestedEntries, err := impl.Parse(strings.TrimPrefix(entry.Value, "\n"))
+			if err != nil {
+				return nil, err
+			}
+			nested, err := impl.buildObject(nestedEntries)
+			if err != nil {
+				return nil, err
+			}
+			value = nested
+		}
+
+		existing, exists := result[entry.Key]
+		if !exists {
+			result[entry.Key] = value
+			continue
+		}
+		switch prior := existing.(type) {
+		case []interface{}:
+			result[entry.Key] = append(prior, value)
+		default:
+			result[entry.Key] = []interface{}{prior, value}
+		}
+	}
+	return result, nil
+}
+
+// lookup navigates the hierarchy along the given key path
+func (impl *Implementation) lookup(input string, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("no key path provided")
+	}
+	obj, err := impl.BuildHierarchy(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var current interface{} = obj
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("path %v: %q is not an object", path, key)
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, fmt.Errorf("path %v: key %q not found", path, key)
+		}
+	}
+	return current, nil
+}
+
+func (impl *Implementation) lookupString(input string, path []string) (string, error) {
+	value, err := impl.lookup(input, path)
+	if err != nil {
+		return "", err
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("path %v: value is not a string", path)
+	}
+	return str, nil
+}
+
+// GetString returns the string value at the key path
+func (impl *Implementation) GetString(input string, args []string) (string, error) {
+	return impl.lookupString(input, args)
+}
+
+// GetInt returns the integer value at the key path
+func (impl *Implementation) GetInt(input string, args []string) (int, error) {
+	str, err := impl.lookupString(input, args)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(str)
+}
+
+// GetBool returns the boolean value at the key path, accepting only true/false
+func (impl *Implementation) GetBool(input string, args []string) (bool, error) {
+	str, err := impl.lookupString(input, args)
+	if err != nil {
+		return false, err
+	}
+	return parseBool(str, args)
+}
+
+func parseBool(str string, path []string) (bool, error) {
+	switch str {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("path %v: %q is not a boolean", path, str)
+	}
+}
+
+// GetFloat returns the float value at the key path
+func (impl *Implementation) GetFloat(input string, args []string) (float64, error) {
+	str, err := impl.lookupString(input, args)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// GetList returns the list at the key path. Lists come from repeated keys or
+// from objects whose keys are all empty; single values are not coerced.
+func (impl *Implementation) GetList(input string, args []string) ([]string, error) {
+	value, err := impl.lookup(input, args)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		return toStringList(v, args)
+	case map[string]interface{}:
+		// Bare list syntax ("= a\n= b") nests items under the empty key
+		if item, ok := v[""]; ok && len(v) == 1 {
+			switch inner := item.(type) {
+			case string:
+				return []string{inner}, nil
+			case []interface{}:
+				return toStringList(inner, args)
+			}
+		}
+	}
+	return nil, fmt.Errorf("path %v: value is not a list", args)
+}
+
+func toStringList(items []interface{}, path []string) ([]string, error) {
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("path %v: list contains a non-string item", path)
+		}
+		list = append(list, str)
+	}
+	return list, nil
+}
+
+// GetWithDefault reads the key path with the typed accessor for fn, or
+// returns fallback converted to fn's type if a key along the path is absent
+func (impl *Implementation) GetWithDefault(fn config.CCLFunction, input string, path []string, fallback string) (interface{}, bool, error) {
+	obj, err := impl.BuildHierarchy(input)
+	if err != nil {
+		return nil, false, err
+	}
+	var current interface{} = obj
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			break // The typed accessor reports the non-object
+		}
+		if current, ok = m[key]; !ok {
+			value, err := convertDefault(fn, fallback, path)
+			return value, true, err
+		}
+	}
+
+	switch fn {
+	case config.FunctionGetString:
+		value, err := impl.GetString(input, path)
+		return value, false, err
+	case config.FunctionGetInt:
+		value, err := impl.GetInt(input, path)
+		return value, false, err
+	case config.FunctionGetBool:
+		value, err := impl.GetBool(input, path)
+		return value, false, err
+	case config.FunctionGetFloat:
+		value, err := impl.GetFloat(input, path)
+		return value, false, err
+	case config.FunctionGetList:
+		value, err := impl.GetList(input, path)
+		return value, false, err
+	default:
+		return nil, false, fmt.Errorf("%s does not take a default", fn)
+	}
+}
+
+// convertDefault converts a default value to fn's type; a list default is a
+// single item
+func convertDefault(fn config.CCLFunction, fallback string, path []string) (interface{}, error) {
+	switch fn {
+	case config.FunctionGetString:
+		return fallback, nil
+	case config.FunctionGetInt:
+		return strconv.Atoi(fallback)
+	case config.FunctionGetBool:
+		return parseBool(fallback, path)
+	case config.FunctionGetFloat:
+		return strconv.ParseFloat(fallback, 64)
+	case config.FunctionGetList:
+		return []string{fallback}, nil
+	default:
+		return nil, fmt.Errorf("%s does not take a default", fn)
+	}
+}
+
+// PrettyPrint renders parsed entries in canonical "key = value" form
+func (impl *Implementation) PrettyPrint(input string) (string, error) {
+	entries, err := impl.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, entry := range entries {
+		sb.WriteString(entry.Key)
+		sb.WriteString(" =")
+		if !strings.HasPrefix(entry.Value, "\n") {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(entry.Value)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// CanonicalFormat renders input the way PrettyPrint does; the toy has a
+// single canonical form
+func (impl *Implementation) CanonicalFormat(input string) (string, error) {
+	return impl.PrettyPrint(input)
+}
@@ -0,0 +1,99 @@
+package toyccl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestParse_ContinuationLines(t *testing.T) {
+	entries, err := New().Parse("name = Alice\nserver =\n  host = localhost\n  port = 8080\r\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []types.Entry{
+		{Key: "name", Value: "Alice"},
+		{Key: "server", Value: "\n  host = localhost\n  port = 8080"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries)
+	}
+}
+
+func TestParse_MissingEquals(t *testing.T) {
+	if _, err := New().Parse("just text"); err == nil {
+		t.Error("Expected error for line without '='")
+	}
+}
+
+func TestBuildHierarchy_NestedAndRepeated(t *testing.T) {
+	obj, err := New().BuildHierarchy("server =\n  host = localhost\nitems = a\nitems = b")
+	if err != nil {
+		t.Fatalf("BuildHierarchy failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"server": map[string]interface{}{"host": "localhost"},
+		"items":  []interface{}{"a", "b"},
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("Expected %v, got %v", expected, obj)
+	}
+}
+
+func TestTypedAccess(t *testing.T) {
+	impl := New()
+	input := "count = 42\nflag = true\nrate = 3.5\nitems = a\nitems = b"
+
+	if v, err := impl.GetInt(input, []string{"count"}); err != nil || v != 42 {
+		t.Errorf("GetInt = %v, %v", v, err)
+	}
+	if v, err := impl.GetBool(input, []string{"flag"}); err != nil || !v {
+		t.Errorf("GetBool = %v, %v", v, err)
+	}
+	if v, err := impl.GetFloat(input, []string{"rate"}); err != nil || v != 3.5 {
+		t.Errorf("GetFloat = %v, %v", v, err)
+	}
+	if v, err := impl.GetList(input, []string{"items"}); err != nil || !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("GetList = %v, %v", v, err)
+	}
+	if _, err := impl.GetString(input, []string{"missing"}); err == nil {
+		t.Error("Expected error for missing key")
+	}
+}
+
+func TestGetWithDefault(t *testing.T) {
+	impl := New()
+	input := "count = 42\nflag = true\nserver =\n  port = 80"
+
+	if v, used, err := impl.GetWithDefault(config.FunctionGetInt, input, []string{"count"}, "7"); err != nil || v != 42 || used {
+		t.Errorf("present key = %v, %t, %v", v, used, err)
+	}
+	if v, used, err := impl.GetWithDefault(config.FunctionGetInt, input, []string{"missing"}, "7"); err != nil || v != 7 || !used {
+		t.Errorf("absent key = %v, %t, %v", v, used, err)
+	}
+	if v, used, err := impl.GetWithDefault(config.FunctionGetList, input, []string{"missing"}, "a"); err != nil || !reflect.DeepEqual(v, []string{"a"}) || !used {
+		t.Errorf("absent list = %v, %t, %v", v, used, err)
+	}
+	if _, _, err := impl.GetWithDefault(config.FunctionGetBool, input, []string{"missing"}, "yes"); err == nil {
+		t.Error("Expected error for a default that is not a boolean")
+	}
+	if v, used, err := impl.GetWithDefault(config.FunctionGetString, input, []string{"server", "port"}, "8080"); err != nil || v != "80" || used {
+		t.Errorf("present nested key = %v, %t, %v", v, used, err)
+	}
+	if v, used, err := impl.GetWithDefault(config.FunctionGetString, input, []string{"server", "host"}, "localhost"); err != nil || v != "localhost" || !used {
+		t.Errorf("absent nested key = %v, %t, %v", v, used, err)
+	}
+	if _, used, err := impl.GetWithDefault(config.FunctionGetString, input, []string{"count", "x"}, "d"); err == nil || used {
+		t.Errorf("Expected a path through a string to fail rather than use the default, got %t, %v", used, err)
+	}
+}
+
+func TestConfig_IsValid(t *testing.T) {
+	if err := Config().IsValid(); err != nil {
+		t.Errorf("Toy config should be valid: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Package version holds the library version, so packages below the root
+// package can stamp it into their output
+package version
+
+import "strings"
+
+// Version of the ccl-test-lib module, re-exported as ccl_test_lib.Version
+const Version = "v0.1.0"
+
+// Major returns the major component of a "vX.Y.Z" version, such as "v0",
+// or "" if v is not of that form
+func Major(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		return ""
+	}
+	major, _, _ := strings.Cut(v, ".")
+	if len(major) < 2 || strings.Trim(major[1:], "0123456789") != "" {
+		return ""
+	}
+	return major
+}
@@ -0,0 +1,20 @@
+package version
+
+import "testing"
+
+func TestMajor(t *testing.T) {
+	tests := map[string]string{
+		"v0.1.0":  "v0",
+		"v2.3.4":  "v2",
+		"v10.0.0": "v10",
+		"v1":      "v1",
+		"1.2.3":   "",
+		"vx.1.0":  "",
+		"":        "",
+	}
+	for v, want := range tests {
+		if got := Major(v); got != want {
+			t.Errorf("Major(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+// Package lint checks CCL test data for quality problems that the JSON
+// schemas cannot catch, such as duplicate names or flat tests whose source
+// test no longer exists. Every rule can be disabled individually.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Severity says whether a finding fails the lint run
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs for suite-level checks; per-test rule IDs are the types.Code* values
+const (
+	RuleDuplicateName    = "duplicate-name"
+	RuleOrphanSourceTest = "orphan-source-test"
+	RuleRedundantFileTag = "redundant-file-tag"
+	RuleLegacyTags       = "legacy-tags"
+	RuleInputBudget      = "input-budget"
+	RuleFileBudget       = "file-budget"
+	RuleSizeBudget       = "size-budget"
+	RuleParseErrorExpect = "parse-error-expect"
+)
+
+// Rule describes one lint check
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+var rules = []Rule{
+	{types.CodeEmptyName, SeverityError, "test has no name"},
+	{types.CodeEmptyInput, SeverityError, "test has no input but does not expect an error"},
+	{types.CodeMissingArgs, SeverityError, "typed access validation without a key path (one or more args)"},
+	{types.CodeUnexpectedArgs, SeverityWarning, "args on a validation that takes none (typed access has no upper limit)"},
+	{types.CodeUnknownFeature, SeverityError, "feature not known to this library"},
+	{types.CodeUnknownBehavior, SeverityError, "behavior neither defined by this library nor registered"},
+	{types.CodeLevelRange, SeverityError, "level tag outside the supported range"},
+	{types.CodeExpectedShape, SeverityError, "source expect value that does not match its validation"},
+	{types.CodeNegativeTolerance, SeverityError, "get_float tolerance below zero"},
+	{types.CodeInvalidSkip, SeverityError, "skip without a reason or with a malformed until date"},
+	{types.CodeDefaultWithError, SeverityError, "typed access test with a default that expects an error"},
+	{types.CodeInputCount, SeverityError, "combine test without exactly two inputs"},
+	{types.CodeTimeoutFloor, SeverityError, "limits.timeout_ms below types.MinTimeoutMS"},
+	{RuleDuplicateName, SeverityError, "test name used more than once"},
+	{RuleOrphanSourceTest, SeverityWarning, "flat test refers to a source test that does not exist"},
+	{RuleRedundantFileTag, SeverityWarning, "test repeats a feature, behavior, or variant declared at the file level"},
+	{RuleLegacyTags, SeverityError, "legacy function:/feature:/behavior:/variant: tags disagree with the typed metadata"},
+	{RuleInputBudget, SeverityError, "test inputs larger than max_input_bytes"},
+	{RuleFileBudget, SeverityError, "file with more tests than max_tests_per_file"},
+	{RuleSizeBudget, SeverityError, "test files together larger than max_generated_bytes"},
+	{RuleParseErrorExpect, SeverityError, "expect_parse_error test that also carries validations with expect values"},
+}
+
+// Rules returns every lint rule in reporting order
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// LookupRule returns the rule with the given ID
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Finding is one rule violation
+type Finding struct {
+	File     string   `json:"file"`
+	Test     string   `json:"test"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s %s: %s", f.File, f.Test, f.Severity, f.Rule, f.Message)
+}
+
+// Options controls which rules run
+type Options struct {
+	Disabled  []string        // Rule IDs to skip
+	SourceDir string          // Source tests for orphan-source-test, in addition to any in the linted directory
+	Budgets   *config.Budgets // Limits for the budget rules (config.DefaultBudgets() if nil)
+
+	// LenientBehaviors reports unknown-behavior as a warning, for
+	// exploratory data trying out behaviors not yet registered
+	LenientBehaviors bool
+}
+
+// HasErrors reports whether any finding has error severity
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// linter accumulates findings across the files of one run
+type linter struct {
+	disabled    map[string]bool
+	findings    []Finding
+	sourceNames map[string]bool
+	flatNames   map[string]string // Flat test name -> first file
+	sourceFiles map[string]string // Source test name -> first file
+	orphans     []orphan          // Held until every source name is known
+	budgets     config.Budgets
+	fileTests   int // Tests seen so far in the current file
+	lenient     bool
+}
+
+// orphan is a flat test's source_test reference awaiting resolution
+type orphan struct {
+	finding Finding
+	source  string
+}
+
+// LintDir lints every *.json test file in dir, in name order. Flat and
+// source files may be mixed; the format is detected per file.
+func LintDir(dir string, opts Options) ([]Finding, error) {
+	l := &linter{
+		disabled:    make(map[string]bool),
+		sourceNames: make(map[string]bool),
+		flatNames:   make(map[string]string),
+		sourceFiles: make(map[string]string),
+		budgets:     config.DefaultBudgets(),
+		lenient:     opts.LenientBehaviors,
+	}
+	if opts.Budgets != nil {
+		l.budgets = *opts.Budgets
+	}
+	for _, id := range opts.Disabled {
+		if _, ok := LookupRule(id); !ok {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+		l.disabled[id] = true
+	}
+
+	files, err := testFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no test files in %s", dir)
+	}
+	for _, file := range files {
+		if err := l.lintFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.checkSizeBudget(files); err != nil {
+		return nil, err
+	}
+
+	if opts.SourceDir != "" {
+		sources, err := testFiles(opts.SourceDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range sources {
+			tests, err := readSourceFile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, test := range tests {
+				l.sourceNames[test.Name] = true
+			}
+		}
+	}
+
+	// Without any source names every reference would look orphaned
+	if len(l.sourceNames) > 0 {
+		for _, ref := range l.orphans {
+			if !l.sourceNames[ref.source] {
+				l.findings = append(l.findings, ref.finding)
+			}
+		}
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].File < l.findings[j].File
+	})
+	return l.findings, nil
+}
+
+// testFiles lists the *.json files in dir, sorted
+func testFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (l *linter) lintFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	file := filepath.Base(path)
+
+	sections, err := loader.SplitSuites(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	l.fileTests = 0
+	for _, section := range sections {
+		if err := l.lintData(file, section); err != nil {
+			return err
+		}
+	}
+	if problem := l.budgets.CheckTestCount(l.fileTests); problem != nil {
+		l.add(file, "", RuleFileBudget, problem.Error())
+	}
+	return nil
+}
+
+// lintData lints the contents of a single-suite test file
+func (l *linter) lintData(file string, data []byte) error {
+	format, err := loader.DetectFormat(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	if format == loader.FormatCompact {
+		source, err := parseSourceFile(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		l.fileTests += len(source.Tests)
+		for _, test := range source.Tests {
+			l.checkInputBudget(file, test.Name, test.Inputs)
+			l.sourceNames[test.Name] = true
+			l.checkDuplicate(l.sourceFiles, file, test.Name)
+			l.checkSourceTest(file, test)
+			l.checkRedundantTags(file, test.Name, source.FileRequirements, test.Features, test.Behaviors, test.Variants)
+		}
+		return nil
+	}
+
+	var suite types.TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return fmt.Errorf("%s: failed to parse flat tests: %w", file, err)
+	}
+	var reqs loader.FileRequirements
+	json.Unmarshal(data, &reqs)
+	// Generated tests carry limits at the top level rather than in meta
+	var limits struct {
+		Tests []struct {
+			Limits *types.Limits `json:"limits"`
+		} `json:"tests"`
+	}
+	json.Unmarshal(data, &limits)
+	l.fileTests += len(suite.Tests)
+	for i, test := range suite.Tests {
+		if i < len(limits.Tests) && limits.Tests[i].Limits != nil {
+			test.Meta.Limits = limits.Tests[i].Limits
+		}
+		l.checkInputBudget(file, test.Name, test.Inputs)
+		l.checkDuplicate(l.flatNames, file, test.Name)
+		l.checkRedundantTags(file, test.Name, reqs, test.Features, test.Behaviors, test.Variants)
+		l.checkLegacyTags(file, test)
+		if tolerance := types.ExtractTolerance(test.Expected); tolerance != 0 {
+			test.Tolerance = tolerance
+		}
+		for _, problem := range test.Validate() {
+			l.add(file, test.Name, problem.Code, problem.Message)
+		}
+		if test.SourceTest != "" && !l.disabled[RuleOrphanSourceTest] {
+			rule, _ := LookupRule(RuleOrphanSourceTest)
+			l.orphans = append(l.orphans, orphan{
+				finding: Finding{
+					File:     file,
+					Test:     test.Name,
+					Rule:     rule.ID,
+					Severity: rule.Severity,
+					Message:  fmt.Sprintf("source test %q does not exist", test.SourceTest),
+				},
+				source: test.SourceTest,
+			})
+		}
+	}
+	return nil
+}
+
+// checkSourceTest validates each validation of a source test as the flat
+// test it generates, reporting each problem once per source test
+func (l *linter) checkSourceTest(file string, test loader.CompactTest) {
+	if test.ExpectParseError && len(test.Tests) > 0 {
+		l.add(file, test.Name, RuleParseErrorExpect,
+			fmt.Sprintf("expects a parse error, so its %d validations would never be generated", len(test.Tests)))
+	}
+
+	reported := make(map[types.ValidationError]bool)
+	for _, validation := range test.Validations() {
+		flat := types.TestCase{
+			Name:        test.Name,
+			Inputs:      test.Inputs,
+			Validation:  validation.Function,
+			Args:        validation.Args,
+			ExpectError: validation.Error,
+			Tolerance:   validation.Tolerance,
+			Default:     validation.Default,
+			Features:    test.Features,
+			Behaviors:   test.Behaviors,
+			Meta:        types.TestMetadata{Limits: test.Limits},
+		}
+		problems := flat.Validate()
+		if !validation.Error {
+			if problem := types.CheckExpectedShape(validation.Function, validation.Expect); problem != nil {
+				problems = append(problems, *problem)
+			}
+		}
+		for _, problem := range problems {
+			if !reported[problem] {
+				reported[problem] = true
+				l.add(file, test.Name, problem.Code, problem.Message)
+			}
+		}
+	}
+}
+
+// checkRedundantTags reports per-test tags that the file-level
+// requirements already supply
+func (l *linter) checkRedundantTags(file, name string, reqs loader.FileRequirements, features, behaviors, variants []string) {
+	for _, tags := range []struct {
+		kind      string
+		file, own []string
+	}{
+		{"feature", reqs.Features, features},
+		{"behavior", reqs.Behaviors, behaviors},
+		{"variant", reqs.Variants, variants},
+	} {
+		for _, tag := range tags.own {
+			if slices.Contains(tags.file, tag) {
+				l.add(file, name, RuleRedundantFileTag, fmt.Sprintf("%s %q is already declared for the whole file", tags.kind, tag))
+			}
+		}
+	}
+}
+
+// checkLegacyTags reports a flat test whose legacy tags, if it has any, do
+// not describe the same metadata as its typed fields
+func (l *linter) checkLegacyTags(file string, test types.TestCase) {
+	if !slices.ContainsFunc(test.Meta.Tags, generator.IsLegacyTag) {
+		return
+	}
+	functions, features, behaviors, variants := generator.ExtractMetadataFromTags(test.Meta.Tags)
+	for _, kind := range []struct {
+		name        string
+		tags, typed []string
+	}{
+		{"functions", functions, test.Functions},
+		{"features", features, test.Features},
+		{"behaviors", behaviors, test.Behaviors},
+		{"variants", variants, test.Variants},
+	} {
+		if !sameSet(kind.tags, kind.typed) {
+			l.add(file, test.Name, RuleLegacyTags, fmt.Sprintf("tags list %s %v but the test has %v", kind.name, kind.tags, kind.typed))
+		}
+	}
+}
+
+// sameSet reports whether a and b hold the same values, ignoring order and
+// repeats
+func sameSet(a, b []string) bool {
+	for _, value := range a {
+		if !slices.Contains(b, value) {
+			return false
+		}
+	}
+	for _, value := range b {
+		if !slices.Contains(a, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkInputBudget reports a test whose inputs exceed max_input_bytes
+func (l *linter) checkInputBudget(file, name string, inputs []string) {
+	if problem := l.budgets.CheckInputs(inputs); problem != nil {
+		l.add(file, name, RuleInputBudget, problem.Error())
+	}
+}
+
+// checkSizeBudget reports test files that together exceed
+// max_generated_bytes, against the largest of them
+func (l *linter) checkSizeBudget(files []string) error {
+	var total, largestSize int64
+	largest := ""
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		total += info.Size()
+		if info.Size() > largestSize {
+			largest, largestSize = filepath.Base(file), info.Size()
+		}
+	}
+	if problem := l.budgets.CheckGeneratedSize(total); problem != nil {
+		l.add(largest, "", RuleSizeBudget, fmt.Sprintf("%v; this is the largest file (%d bytes)", problem, largestSize))
+	}
+	return nil
+}
+
+// checkDuplicate reports a name already seen in this or an earlier file
+func (l *linter) checkDuplicate(seen map[string]string, file, name string) {
+	if name == "" {
+		return
+	}
+	if first, ok := seen[name]; ok {
+		l.add(file, name, RuleDuplicateName, "also defined in "+first)
+		return
+	}
+	seen[name] = file
+}
+
+// add records a finding unless its rule is disabled
+func (l *linter) add(file, test, ruleID, message string) {
+	if l.disabled[ruleID] {
+		return
+	}
+	rule, _ := LookupRule(ruleID)
+	if ruleID == types.CodeUnknownBehavior && l.lenient {
+		rule.Severity = SeverityWarning
+	}
+	l.findings = append(l.findings, Finding{
+		File:     file,
+		Test:     test,
+		Rule:     rule.ID,
+		Severity: rule.Severity,
+		Message:  message,
+	})
+}
+
+func readSourceFile(path string) ([]loader.CompactTest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	source, err := parseSourceFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return source.Tests, nil
+}
+
+func parseSourceFile(data []byte) (loader.CompactTestFile, error) {
+	var file loader.CompactTestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("failed to parse source tests: %w", err)
+	}
+	return file, nil
+}
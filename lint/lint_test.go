@@ -0,0 +1,279 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// writeFiles writes test files into a new directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// findingRules returns "file:test:rule" for each finding
+func findingRules(findings []Finding) []string {
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.File+":"+finding.Test+":"+finding.Rule)
+	}
+	return got
+}
+
+const cleanSource = `{"tests": [
+	{"name": "basic", "inputs": ["a = b"], "tests": [
+		{"function": "parse", "expect": [{"key": "a", "value": "b"}]},
+		{"function": "get_string", "args": ["a"], "expect": "b"}
+	]},
+	{"name": "empty_error", "inputs": [""], "tests": [{"function": "parse", "expect": [], "error": true}]}
+]}`
+
+const cleanFlat = `{"tests": [
+	{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"], "source_test": "basic"},
+	{"name": "basic_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "b"}, "source_test": "basic"}
+]}`
+
+func TestLintDir_Clean(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"api.json": cleanSource, "flat.json": cleanFlat})
+	findings, err := LintDir(dir, Options{})
+	if err != nil {
+		t.Fatalf("LintDir failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+}
+
+func TestLintDir_Rules(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{"empty-name", map[string]string{
+			"a.json": `{"tests": [{"name": "", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}}]}`,
+		}, []string{"a.json::empty-name"}},
+		{"empty-input", map[string]string{
+			"a.json": `{"tests": [{"name": "blank", "inputs": [""], "tests": [{"function": "parse", "expect": []}, {"function": "filter", "expect": []}]}]}`,
+		}, []string{"a.json:blank:empty-input"}},
+		{"missing-args", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "get_int", "expect": 1}]}]}`,
+		}, []string{"a.json:x:missing-args"}},
+		{"missing-args in a suite", map[string]string{
+			"a.json": `{"suites": [{"suite": "core", "tests": [{"name": "y", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": []}]}]}, {"suite": "typed", "tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "get_int", "expect": 1}]}]}]}`,
+		}, []string{"a.json:x:missing-args"}},
+		{"unexpected-args", map[string]string{
+			"a.json": `{"tests": [{"name": "x_parse", "inputs": ["a = 1"], "validation": "parse", "args": ["a"], "expected": {"count": 1}}]}`,
+			"b.json": `{"tests": [{"name": "y", "inputs": ["a = 1"], "tests": [{"function": "parse", "args": ["a"], "expect": []}]}]}`,
+		}, []string{"a.json:x_parse:unexpected-args", "b.json:y:unexpected-args"}},
+		{"unknown-feature", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "features": ["telepathy"], "tests": [{"function": "parse", "expect": []}]}]}`,
+		}, []string{"a.json:x:unknown-feature"}},
+		{"unknown-behavior", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "behaviors": ["boolean_leniant"], "tests": [{"function": "parse", "expect": []}, {"function": "filter", "expect": []}]}]}`,
+			"b.json": `{"tests": [{"name": "y_parse", "inputs": ["a = 1"], "validation": "parse", "behaviors": ["tabs_as_content", "crlf_preserve"], "expected": {"count": 1}}]}`,
+		}, []string{"a.json:x:unknown-behavior", "b.json:y_parse:unknown-behavior"}},
+		{"expected-shape", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": "a = 1"}, {"function": "parse", "expect": "oops", "error": true}]}]}`,
+		}, []string{"a.json:x:expected-shape"}},
+		{"negative-tolerance", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1.5"], "tests": [{"function": "get_float", "args": ["a"], "expect": 1.5, "tolerance": -0.1}]}]}`,
+			"b.json": `{"tests": [{"name": "y_get_float", "inputs": ["a = 1.5"], "validation": "get_float", "args": ["a"], "expected": {"count": 1, "value": 1.5, "tolerance": -0.1}}]}`,
+		}, []string{"a.json:x:negative-tolerance", "b.json:y_get_float:negative-tolerance"}},
+		{"default-with-error", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "get_string", "args": ["b"], "default": "2", "error": true}, {"function": "get_string", "args": ["b"], "default": "2", "expect": "2"}, {"function": "get_string", "args": ["a", "b"], "error": true}]}]}`,
+			"b.json": `{"tests": [{"name": "y_get_string", "inputs": ["a = 1"], "validation": "get_string", "args": ["b"], "default": "2", "expect_error": true, "expected": {"count": 0, "error": true}}]}`,
+		}, []string{"a.json:x:default-with-error", "b.json:y_get_string:default-with-error"}},
+		{"input-count", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "combine", "expect": [{"key": "a", "value": "1"}]}]}]}`,
+			"b.json": `{"tests": [{"name": "y_combine", "inputs": ["a = 1", "b = 2"], "validation": "combine", "expected": {"count": 2}}]}`,
+		}, []string{"a.json:x:input-count"}},
+		{"timeout-floor", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "limits": {"timeout_ms": 5}, "tests": [{"function": "parse", "expect": []}, {"function": "filter", "expect": []}]}]}`,
+			"b.json": `{"tests": [{"name": "y_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "limits": {"timeout_ms": 50}}, {"name": "z_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "limits": {"timeout_ms": 5000, "slow": true}}]}`,
+		}, []string{"a.json:x:timeout-floor", "b.json:y_parse:timeout-floor"}},
+		{"invalid-skip", map[string]string{
+			"a.json": `{"tests": [{"name": "x_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "skip": {"reason": "dispute", "until": "soon"}}]}`,
+		}, []string{"a.json:x_parse:invalid-skip"}},
+		{"level-range", map[string]string{
+			"a.json": `{"tests": [{"name": "x_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "meta": {"tags": ["level:9"]}}]}`,
+		}, []string{"a.json:x_parse:level-range"}},
+		{"redundant-file-tag", map[string]string{
+			"a.json": `{"features": ["comments"], "tests": [{"name": "x", "inputs": ["a = 1"], "features": ["comments"], "tests": [{"function": "parse", "expect": []}]}]}`,
+		}, []string{"a.json:x:redundant-file-tag"}},
+		{"legacy-tags", map[string]string{
+			"a.json": `{"tests": [
+				{"name": "synced_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "functions": ["parse"], "features": ["comments"], "meta": {"tags": ["feature:comments", "function:parse", "level:1"]}},
+				{"name": "untagged_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "functions": ["parse"], "meta": {"tags": ["level:1"]}},
+				{"name": "stale_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "functions": ["parse"], "meta": {"tags": ["function:parse", "feature:comments"]}}
+			]}`,
+		}, []string{"a.json:stale_parse:legacy-tags"}},
+		{"parse-error-expect", map[string]string{
+			"a.json": `{"tests": [
+				{"name": "x", "inputs": ["no equals"], "expect_parse_error": true},
+				{"name": "y", "inputs": ["no equals"], "expect_parse_error": true, "tests": [{"function": "parse", "expect": []}]}
+			]}`,
+		}, []string{"a.json:y:parse-error-expect"}},
+		{"duplicate-name across files", map[string]string{
+			"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": []}]}]}`,
+			"b.json": `{"tests": [{"name": "x", "inputs": ["b = 1"], "tests": [{"function": "parse", "expect": []}]}]}`,
+		}, []string{"b.json:x:duplicate-name"}},
+		{"orphan-source-test", map[string]string{
+			"api.json":  cleanSource,
+			"flat.json": `{"tests": [{"name": "gone_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "source_test": "gone"}]}`,
+		}, []string{"flat.json:gone_parse:orphan-source-test"}},
+		{"orphan-source-test without sources", map[string]string{
+			"flat.json": `{"tests": [{"name": "gone_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "source_test": "gone"}]}`,
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := LintDir(writeFiles(t, tt.files), Options{})
+			if err != nil {
+				t.Fatalf("LintDir failed: %v", err)
+			}
+			if got := findingRules(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLintDir_SourceDir(t *testing.T) {
+	sourceDir := writeFiles(t, map[string]string{"api.json": cleanSource})
+	flat := `{"tests": [
+		{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "source_test": "basic"},
+		{"name": "gone_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "source_test": "gone"}
+	]}`
+	dir := writeFiles(t, map[string]string{"flat.json": flat})
+
+	findings, err := LintDir(dir, Options{SourceDir: sourceDir})
+	if err != nil {
+		t.Fatalf("LintDir failed: %v", err)
+	}
+	want := []string{"flat.json:gone_parse:orphan-source-test"}
+	if got := findingRules(findings); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if HasErrors(findings) {
+		t.Error("Expected orphan-source-test to be a warning")
+	}
+}
+
+func TestLintDir_Disabled(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"tests": [{"name": "x", "inputs": [""], "features": ["telepathy"], "tests": [{"function": "get_int", "expect": 1}]}]}`,
+	})
+
+	findings, err := LintDir(dir, Options{Disabled: []string{"empty-input", "unknown-feature"}})
+	if err != nil {
+		t.Fatalf("LintDir failed: %v", err)
+	}
+	want := []string{"a.json:x:missing-args"}
+	if got := findingRules(findings); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := LintDir(dir, Options{Disabled: []string{"no-such-rule"}}); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+}
+
+func TestLintDir_LenientBehaviors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"tests": [{"name": "x", "inputs": ["a = 1"], "behaviors": ["boolean_leniant"], "tests": [{"function": "parse", "expect": []}]}]}`,
+	})
+
+	findings, err := LintDir(dir, Options{LenientBehaviors: true})
+	if err != nil {
+		t.Fatalf("LintDir failed: %v", err)
+	}
+	want := `a.json: x: warning unknown-behavior: unknown behavior "boolean_leniant" (did you mean "boolean_lenient"?)`
+	if len(findings) != 1 || findings[0].String() != want {
+		t.Fatalf("Expected one warning %q, got %v", want, findings)
+	}
+	if HasErrors(findings) {
+		t.Error("Expected no errors with LenientBehaviors")
+	}
+}
+
+func TestLintDir_Budgets(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"api.json": cleanSource, "flat.json": cleanFlat})
+	info, err := os.Stat(filepath.Join(dir, "flat.json"))
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+	sourceInfo, err := os.Stat(filepath.Join(dir, "api.json"))
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+	total := info.Size() + sourceInfo.Size()
+
+	tests := []struct {
+		name    string
+		budgets config.Budgets
+		want    []string
+	}{
+		{"at every limit", config.Budgets{MaxInputBytes: 5, MaxTestsPerFile: 2, MaxGeneratedBytes: total}, nil},
+		// "a = b" is 5 bytes, in two source and two flat tests
+		{"input", config.Budgets{MaxInputBytes: 4}, []string{"api.json:basic:input-budget", "flat.json:basic_parse:input-budget", "flat.json:basic_get_string:input-budget"}},
+		{"tests per file", config.Budgets{MaxTestsPerFile: 1}, []string{"api.json::file-budget", "flat.json::file-budget"}},
+		{"total size", config.Budgets{MaxGeneratedBytes: total - 1}, []string{largest(sourceInfo, info) + "::size-budget"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := LintDir(dir, Options{Budgets: &tt.budgets})
+			if err != nil {
+				t.Fatalf("LintDir failed: %v", err)
+			}
+			if got := findingRules(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	findings, err := LintDir(dir, Options{Budgets: &config.Budgets{MaxTestsPerFile: 1}})
+	if err != nil {
+		t.Fatalf("LintDir failed: %v", err)
+	}
+	if want := "2 tests, over max_tests_per_file (1)"; len(findings) == 0 || findings[0].Message != want {
+		t.Errorf("Expected message %q, got %v", want, findings)
+	}
+}
+
+// largest returns the name of the larger file
+func largest(a, b os.FileInfo) string {
+	if a.Size() >= b.Size() {
+		return a.Name()
+	}
+	return b.Name()
+}
+
+func TestFinding_String(t *testing.T) {
+	finding := Finding{File: "a.json", Test: "x", Rule: "missing-args", Severity: SeverityError, Message: "get_int requires args"}
+	if got := finding.String(); got != "a.json: x: error missing-args: get_int requires args" {
+		t.Errorf("Unexpected finding string %q", got)
+	}
+}
+
+func TestRules_AllLookupable(t *testing.T) {
+	for _, rule := range Rules() {
+		if found, ok := LookupRule(rule.ID); !ok || found != rule {
+			t.Errorf("LookupRule(%q) = %v, %v", rule.ID, found, ok)
+		}
+	}
+	if _, ok := LookupRule("no-such-rule"); ok {
+		t.Error("Expected unknown rule lookup to fail")
+	}
+}
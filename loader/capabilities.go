@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"slices"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// CapabilityList is the capabilities of one kind a set of tests exercises
+type CapabilityList struct {
+	Names  []string       `json:"names"`  // Sorted; empty rather than nil
+	Counts map[string]int `json:"counts"` // Name -> tests exercising it
+}
+
+// CapabilitySummary describes what a set of tests covers, e.g. a vendored
+// export
+type CapabilitySummary struct {
+	Tests     int            `json:"tests"`
+	Functions CapabilityList `json:"functions"` // Validations and the functions tests declare
+	Features  CapabilityList `json:"features"`
+	Behaviors CapabilityList `json:"behaviors"`
+	Variants  CapabilityList `json:"variants"`
+}
+
+// SummarizeCapabilities lists the functions, features, behaviors and
+// variants tests exercise, with the number of tests exercising each. A test
+// naming a capability more than once counts once.
+func SummarizeCapabilities(tests []types.TestCase) CapabilitySummary {
+	functions, features, behaviors, variants := make(capabilityCounter), make(capabilityCounter), make(capabilityCounter), make(capabilityCounter)
+	for _, test := range tests {
+		names := slices.Clone(test.Functions)
+		if test.Validation != "" {
+			names = append(names, test.Validation)
+		}
+		functions.add(names)
+		features.add(test.Features)
+		behaviors.add(test.Behaviors)
+		variants.add(test.Variants)
+	}
+	return CapabilitySummary{
+		Tests:     len(tests),
+		Functions: functions.list(),
+		Features:  features.list(),
+		Behaviors: behaviors.list(),
+		Variants:  variants.list(),
+	}
+}
+
+// capabilityCounter counts the tests exercising each capability of one kind
+type capabilityCounter map[string]int
+
+// add counts one test exercising names
+func (c capabilityCounter) add(names []string) {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			c[name]++
+		}
+	}
+}
+
+func (c capabilityCounter) list() CapabilityList {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return CapabilityList{Names: names, Counts: c}
+}
@@ -0,0 +1,53 @@
+package loader
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestSummarizeCapabilities(t *testing.T) {
+	summary := SummarizeCapabilities([]types.TestCase{
+		{Name: "parse_basic", Validation: "parse", Functions: []string{"parse"}},
+		{Name: "get_string_comments", Validation: "get_string", Functions: []string{"parse", "build_hierarchy", "get_string"}, Features: []string{"comments"}},
+		{Name: "crlf_unicode", Validation: "parse", Features: []string{"unicode", "comments"}, Behaviors: []string{"crlf_normalize_to_lf"}},
+	})
+
+	want := CapabilitySummary{
+		Tests: 3,
+		Functions: CapabilityList{
+			Names:  []string{"build_hierarchy", "get_string", "parse"},
+			Counts: map[string]int{"build_hierarchy": 1, "get_string": 1, "parse": 3},
+		},
+		Features: CapabilityList{
+			Names:  []string{"comments", "unicode"},
+			Counts: map[string]int{"comments": 2, "unicode": 1},
+		},
+		Behaviors: CapabilityList{
+			Names:  []string{"crlf_normalize_to_lf"},
+			Counts: map[string]int{"crlf_normalize_to_lf": 1},
+		},
+		Variants: CapabilityList{Names: []string{}, Counts: map[string]int{}},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("Unexpected summary.\nGot:  %+v\nWant: %+v", summary, want)
+	}
+}
+
+func TestSummarizeCapabilities_EmptyCategories(t *testing.T) {
+	data, err := json.Marshal(SummarizeCapabilities(nil))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	empty := `{"names":[],"counts":{}}`
+	want := `{"tests":0,"functions":` + empty + `,"features":` + empty + `,"behaviors":` + empty + `,"variants":` + empty + `}`
+	if string(data) != want {
+		t.Errorf("Expected every category present.\nGot:  %s\nWant: %s", data, want)
+	}
+	if strings.Contains(string(data), "null") {
+		t.Errorf("Expected no null categories, got %s", data)
+	}
+}
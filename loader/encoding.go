@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// StripBOM returns data without a leading UTF-8 byte order mark
+func StripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// InvalidUTF8Error reports a test input that is not valid UTF-8. Decoding
+// would silently replace the invalid bytes with U+FFFD.
+type InvalidUTF8Error struct {
+	File   string
+	Test   string
+	Input  int // Index into the test's inputs
+	Offset int // Byte offset of the first invalid byte in the file
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("%s: test %s: input %d has invalid UTF-8 at byte %d", e.File, e.Test, e.Input, e.Offset)
+}
+
+// invalidInputs returns an InvalidUTF8Error for each test input in data that
+// is not valid UTF-8. Other fields are not checked.
+func invalidInputs(filename string, data []byte) []*InvalidUTF8Error {
+	if utf8.Valid(data) {
+		return nil
+	}
+
+	var tests []map[string]json.RawMessage
+	var file struct {
+		Tests []map[string]json.RawMessage `json:"tests"`
+	}
+	if json.Unmarshal(data, &file) == nil {
+		tests = file.Tests
+	} else if json.Unmarshal(data, &tests) != nil {
+		return nil // Decoding reports the syntax error
+	}
+
+	var problems []*InvalidUTF8Error
+	searchFrom := 0
+	for _, test := range tests {
+		var inputs []json.RawMessage
+		if json.Unmarshal(test["inputs"], &inputs) != nil {
+			continue
+		}
+		var name string
+		json.Unmarshal(test["name"], &name)
+		for i, input := range inputs {
+			if utf8.Valid(input) {
+				continue
+			}
+			start := bytes.Index(data[searchFrom:], input)
+			if start < 0 {
+				continue
+			}
+			start += searchFrom
+			searchFrom = start + len(input)
+			problems = append(problems, &InvalidUTF8Error{
+				File:   filename,
+				Test:   name,
+				Input:  i,
+				Offset: start + firstInvalid(input),
+			})
+		}
+	}
+	return problems
+}
+
+// firstInvalid returns the index of the first byte of s that is not valid UTF-8
+func firstInvalid(s []byte) int {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRune(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return len(s)
+}
@@ -0,0 +1,84 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+func TestStripBOM(t *testing.T) {
+	if got := string(StripBOM([]byte("\xEF\xBB\xBF{}"))); got != "{}" {
+		t.Errorf("Expected the BOM stripped, got %q", got)
+	}
+	if got := string(StripBOM([]byte("{}"))); got != "{}" {
+		t.Errorf("Expected data without a BOM unchanged, got %q", got)
+	}
+}
+
+func TestTestLoader_LoadTestFile_BOM(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := map[string]struct {
+		content string
+		format  TestFormat
+	}{
+		"flat.json":    {`{"tests": [{"name": "a", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}}]}`, FormatFlat},
+		"compact.json": {`{"tests": [{"name": "a", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": []}]}]}`, FormatCompact},
+	}
+	loader := NewTestLoader(dir, config.ImplementationConfig{})
+	for name, fixture := range fixtures {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, append([]byte("\xEF\xBB\xBF"), fixture.content...), 0644)
+
+		suite, err := loader.LoadTestFile(path, LoadOptions{Format: fixture.format})
+		if err != nil {
+			t.Fatalf("%s: expected a BOM-prefixed file to load, got %v", name, err)
+		}
+		if len(suite.Tests) != 1 || suite.Tests[0].Name != "a" {
+			t.Errorf("%s: expected test a, got %+v", name, suite.Tests)
+		}
+		issues, err := ValidateTestFile(path)
+		if err != nil {
+			t.Fatalf("%s: ValidateTestFile failed: %v", name, err)
+		}
+		for _, issue := range issues {
+			if strings.Contains(issue.Message, "failed to parse") {
+				t.Errorf("%s: expected validation to read a BOM-prefixed file, got %v", name, issue)
+			}
+		}
+	}
+}
+
+func TestTestLoader_LoadTestFile_InvalidUTF8(t *testing.T) {
+	dir := t.TempDir()
+	content := "{\"tests\": [\n" +
+		"{\"name\": \"fine\", \"inputs\": [\"caf\xC3\xA9 = 1\"], \"validation\": \"parse\", \"expected\": {\"count\": 1}},\n" +
+		"{\"name\": \"broken\", \"inputs\": [\"a = 1\", \"b = \xFF\"], \"validation\": \"parse\", \"expected\": {\"count\": 1}}\n" +
+		"]}"
+	path := filepath.Join(dir, "unicode.json")
+	os.WriteFile(path, []byte(content), 0644)
+	loader := NewTestLoader(dir, config.ImplementationConfig{})
+
+	_, err := loader.LoadTestFile(path, LoadOptions{Format: FormatFlat})
+	var invalid *InvalidUTF8Error
+	if !errors.As(err, &invalid) || !errors.Is(err, ErrParse) {
+		t.Fatalf("Expected an InvalidUTF8Error wrapping ErrParse, got %v", err)
+	}
+	if invalid.Test != "broken" || invalid.Input != 1 || invalid.Offset != strings.Index(content, "\xFF") {
+		t.Errorf("Expected test broken, input 1, offset %d, got %+v", strings.Index(content, "\xFF"), invalid)
+	}
+
+	suite, err := loader.LoadTestFile(path, LoadOptions{Format: FormatFlat, LenientUTF8: true})
+	if err != nil {
+		t.Fatalf("Expected lenient loading to succeed, got %v", err)
+	}
+	if got := suite.Tests[1].Inputs[1]; got != "b = �" {
+		t.Errorf("Expected the invalid byte replaced, got %q", got)
+	}
+	if got := suite.Tests[0].Inputs[0]; got != "café = 1" {
+		t.Errorf("Expected valid UTF-8 kept, got %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// FileRequirements are requirements a test file declares once for all of
+// its tests, in the top-level "features", "behaviors", and "variants" arrays
+type FileRequirements struct {
+	Features  []string `json:"features,omitempty"`
+	Behaviors []string `json:"behaviors,omitempty"`
+	Variants  []string `json:"variants,omitempty"`
+}
+
+// fileRequirements reads the file-level requirements of a test file; bare
+// arrays of tests have none
+func fileRequirements(data []byte) FileRequirements {
+	var reqs FileRequirements
+	json.Unmarshal(data, &reqs)
+	return reqs
+}
+
+// Apply merges the file-level requirements into a test. Features are
+// added; a behavior is added unless the test already chooses one from the
+// same conflict group or lists it as a conflict; variants apply only to
+// tests that declare none.
+func (r FileRequirements) Apply(test *types.TestCase) {
+	for _, feature := range r.Features {
+		if !slices.Contains(test.Features, feature) {
+			test.Features = append(test.Features, feature)
+		}
+	}
+	for _, behavior := range r.Behaviors {
+		if !testDecidesBehavior(*test, behavior) {
+			test.Behaviors = append(test.Behaviors, behavior)
+		}
+	}
+	if len(test.Variants) == 0 && len(r.Variants) > 0 {
+		test.Variants = append([]string(nil), r.Variants...)
+	}
+}
+
+// testDecidesBehavior reports whether a test already requires or conflicts
+// with behavior, or requires another behavior from its conflict group
+func testDecidesBehavior(test types.TestCase, behavior string) bool {
+	if slices.Contains(test.Behaviors, behavior) {
+		return true
+	}
+	if test.Conflicts != nil && slices.Contains(test.Conflicts.Behaviors, behavior) {
+		return true
+	}
+	group, ok := config.BehaviorGroup(config.CCLBehavior(behavior))
+	if !ok {
+		return false
+	}
+	for _, chosen := range test.Behaviors {
+		if other, ok := config.BehaviorGroup(config.CCLBehavior(chosen)); ok && other == group {
+			return true
+		}
+	}
+	return false
+}
+
+// FileGeneratorOptions are generation settings a source file declares for
+// itself in its top-level "generator" block. They add to the generator's
+// own options for that file only.
+type FileGeneratorOptions struct {
+	SkipFunctions []string `json:"skip_functions,omitempty"` // Validations never flattened from this file
+}
+
+// ReadFileGeneratorOptions reads the generator block of a source file.
+// Files without one, and bare arrays of tests, have none.
+func ReadFileGeneratorOptions(data []byte) (FileGeneratorOptions, error) {
+	if trimmed := bytes.TrimSpace(StripBOM(data)); len(trimmed) > 0 && trimmed[0] == '[' {
+		return FileGeneratorOptions{}, nil
+	}
+	var header struct {
+		Generator *FileGeneratorOptions `json:"generator"`
+	}
+	if err := json.Unmarshal(StripBOM(data), &header); err != nil {
+		return FileGeneratorOptions{}, fmt.Errorf("failed to parse generator block: %w", err)
+	}
+	if header.Generator == nil {
+		return FileGeneratorOptions{}, nil
+	}
+	return *header.Generator, nil
+}
+
+// Functions returns SkipFunctions as functions, or an error naming the
+// first one that is not a known CCL function
+func (o FileGeneratorOptions) Functions() ([]config.CCLFunction, error) {
+	var functions []config.CCLFunction
+	for _, name := range o.SkipFunctions {
+		fn := config.CCLFunction(name)
+		if !slices.Contains(config.AllFunctions(), fn) {
+			return nil, fmt.Errorf("generator.skip_functions: unknown function %q", name)
+		}
+		functions = append(functions, fn)
+	}
+	return functions, nil
+}
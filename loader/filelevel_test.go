@@ -0,0 +1,130 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestFileRequirements_Apply(t *testing.T) {
+	reqs := FileRequirements{
+		Features:  []string{"unicode"},
+		Behaviors: []string{"crlf_normalize_to_lf", "boolean_strict"},
+		Variants:  []string{"reference_compliant"},
+	}
+	tests := []struct {
+		name string
+		test types.TestCase
+		want types.TestCase
+	}{
+		{
+			"untagged test inherits everything",
+			types.TestCase{},
+			types.TestCase{
+				Features:  []string{"unicode"},
+				Behaviors: []string{"crlf_normalize_to_lf", "boolean_strict"},
+				Variants:  []string{"reference_compliant"},
+			},
+		},
+		{
+			"test choices win within a group",
+			types.TestCase{
+				Features:  []string{"comments", "unicode"},
+				Behaviors: []string{"boolean_lenient"},
+				Variants:  []string{"proposed_behavior"},
+				Conflicts: &types.ConflictSet{Behaviors: []string{"crlf_normalize_to_lf"}},
+			},
+			types.TestCase{
+				Features:  []string{"comments", "unicode"},
+				Behaviors: []string{"boolean_lenient"},
+				Variants:  []string{"proposed_behavior"},
+				Conflicts: &types.ConflictSet{Behaviors: []string{"crlf_normalize_to_lf"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := tt.test
+			reqs.Apply(&test)
+			if !reflect.DeepEqual(test, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, test)
+			}
+		})
+	}
+}
+
+func TestTestLoader_LoadAllTests_FileRequirements(t *testing.T) {
+	for _, format := range []struct {
+		name   string
+		dir    string
+		format TestFormat
+		data   string
+	}{
+		{"compact", "source_tests", FormatCompact, `{"features": ["unicode"], "tests": [
+			{"name": "one", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]},
+			{"name": "two", "inputs": ["b = 2"], "features": ["comments"], "tests": [{"function": "parse", "expect": [{"key": "b", "value": "2"}]}]}
+		]}`},
+		{"flat", "generated_tests", FormatFlat, `{"features": ["unicode"], "tests": [
+			{"name": "one_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+			{"name": "two_parse", "inputs": ["b = 2"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"]}
+		]}`},
+	} {
+		t.Run(format.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.MkdirAll(filepath.Join(dir, format.dir), 0755)
+			os.WriteFile(filepath.Join(dir, format.dir, "api.json"), []byte(format.data), 0644)
+			loader := NewTestLoader(dir, createTestConfig())
+
+			all, err := loader.LoadAllTests(LoadOptions{Format: format.format, FilterMode: FilterAll})
+			if err != nil {
+				t.Fatalf("Failed to load tests: %v", err)
+			}
+			if len(all) != 2 {
+				t.Fatalf("Expected 2 tests, got %d", len(all))
+			}
+			for _, test := range all {
+				if !reflect.DeepEqual(test.Features[len(test.Features)-1:], []string{"unicode"}) {
+					t.Errorf("Expected %s to inherit the unicode feature, got %v", test.Name, test.Features)
+				}
+			}
+
+			// The config lacks unicode, so every test in the file is filtered out
+			compatible, err := loader.LoadAllTests(LoadOptions{Format: format.format, FilterMode: FilterCompatible})
+			if err != nil {
+				t.Fatalf("Failed to load tests: %v", err)
+			}
+			if len(compatible) != 0 {
+				t.Errorf("Expected no compatible tests, got %d", len(compatible))
+			}
+		})
+	}
+}
+
+func TestReadFileGeneratorOptions(t *testing.T) {
+	opts, err := ReadFileGeneratorOptions([]byte(`{"generator": {"skip_functions": ["get_string", "get_int"]}, "tests": []}`))
+	if err != nil {
+		t.Fatalf("ReadFileGeneratorOptions failed: %v", err)
+	}
+	functions, err := opts.Functions()
+	if err != nil || !reflect.DeepEqual(functions, []config.CCLFunction{config.FunctionGetString, config.FunctionGetInt}) {
+		t.Errorf("Expected get_string and get_int, got %v (%v)", functions, err)
+	}
+
+	for _, data := range []string{`{"tests": []}`, `[{"name": "a"}]`} {
+		if opts, err := ReadFileGeneratorOptions([]byte(data)); err != nil || len(opts.SkipFunctions) != 0 {
+			t.Errorf("Expected no options for %s, got %+v (%v)", data, opts, err)
+		}
+	}
+	if _, err := ReadFileGeneratorOptions([]byte(`{"generator": {"skip_functions": "parse"}}`)); err == nil {
+		t.Error("Expected an error for a malformed generator block")
+	}
+	if _, err := (FileGeneratorOptions{SkipFunctions: []string{"parse", "diagnose"}}).Functions(); err == nil || !strings.Contains(err.Error(), `"diagnose"`) {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Directory names under a test data root
+const (
+	DefaultSourceDir    = "source_tests"    // Compact source tests
+	DefaultGeneratedDir = "generated_tests" // Flat tests generated from them
+	LegacySourceDir     = "tests"           // Source tests in older checkouts, used when there is no source_tests
+)
+
+// Layout is where a test data root keeps its test files. All paths are
+// absolute.
+type Layout struct {
+	Root         string
+	SourceDir    string // DefaultSourceDir or LegacySourceDir; "" if the root has neither
+	GeneratedDir string // DefaultGeneratedDir; "" if the root has none
+}
+
+// LayoutError is returned by ResolveLayout for a directory with none of
+// the known test directories. It matches ErrNoTests and fs.ErrNotExist.
+type LayoutError struct {
+	Root string
+}
+
+func (e *LayoutError) Error() string {
+	return fmt.Sprintf("%s is not a test data directory: it has no %s/, %s/ or %s/ directory",
+		e.Root, DefaultSourceDir, LegacySourceDir, DefaultGeneratedDir)
+}
+
+func (e *LayoutError) Unwrap() []error {
+	return []error{ErrNoTests, fs.ErrNotExist}
+}
+
+// ResolveLayout finds the source and generated test directories under root,
+// preferring DefaultSourceDir to LegacySourceDir. When root has neither
+// kind it returns a *LayoutError and a layout with only Root set.
+func ResolveLayout(root string) (Layout, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return Layout{}, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	layout := Layout{Root: abs}
+	for _, name := range []string{DefaultSourceDir, LegacySourceDir} {
+		if isDir(filepath.Join(abs, name)) {
+			layout.SourceDir = filepath.Join(abs, name)
+			break
+		}
+	}
+	if isDir(filepath.Join(abs, DefaultGeneratedDir)) {
+		layout.GeneratedDir = filepath.Join(abs, DefaultGeneratedDir)
+	}
+	if layout.SourceDir == "" && layout.GeneratedDir == "" {
+		return layout, &LayoutError{Root: abs}
+	}
+	return layout, nil
+}
+
+// Dir returns the directory holding format's test files, or where it would
+// be under the default names when the root has none
+func (l Layout) Dir(format TestFormat) string {
+	if format == FormatCompact {
+		if l.SourceDir != "" {
+			return l.SourceDir
+		}
+		return filepath.Join(l.Root, DefaultSourceDir)
+	}
+	if l.GeneratedDir != "" {
+		return l.GeneratedDir
+	}
+	return filepath.Join(l.Root, DefaultGeneratedDir)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
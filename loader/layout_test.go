@@ -0,0 +1,92 @@
+package loader
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveLayout(t *testing.T) {
+	tests := []struct {
+		name          string
+		dirs          []string
+		wantSource    string
+		wantGenerated string
+	}{
+		{"source only", []string{DefaultSourceDir}, DefaultSourceDir, ""},
+		{"legacy source", []string{LegacySourceDir}, LegacySourceDir, ""},
+		{"generated only", []string{DefaultGeneratedDir}, "", DefaultGeneratedDir},
+		{"full checkout", []string{DefaultSourceDir, DefaultGeneratedDir}, DefaultSourceDir, DefaultGeneratedDir},
+		{"legacy checkout", []string{LegacySourceDir, DefaultGeneratedDir}, LegacySourceDir, DefaultGeneratedDir},
+		{"both source names", []string{LegacySourceDir, DefaultSourceDir}, DefaultSourceDir, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range tt.dirs {
+				os.MkdirAll(filepath.Join(root, dir), 0755)
+			}
+			resolved := func(name string) string {
+				if name == "" {
+					return ""
+				}
+				return filepath.Join(root, name)
+			}
+
+			layout, err := ResolveLayout(root)
+			if err != nil {
+				t.Fatalf("ResolveLayout failed: %v", err)
+			}
+			if layout.Root != root || layout.SourceDir != resolved(tt.wantSource) || layout.GeneratedDir != resolved(tt.wantGenerated) {
+				t.Errorf("Unexpected layout %+v", layout)
+			}
+			if layout.Dir(FormatCompact) != filepath.Join(root, orDefault(tt.wantSource, DefaultSourceDir)) ||
+				layout.Dir(FormatFlat) != filepath.Join(root, DefaultGeneratedDir) {
+				t.Errorf("Unexpected directories %s, %s", layout.Dir(FormatCompact), layout.Dir(FormatFlat))
+			}
+		})
+	}
+}
+
+func orDefault(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func TestResolveLayout_NoMatch(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, DefaultSourceDir), []byte("not a directory"), 0644)
+
+	_, err := ResolveLayout(root)
+	var layoutErr *LayoutError
+	if !errors.As(err, &layoutErr) || layoutErr.Root != root {
+		t.Fatalf("Expected a LayoutError for %s, got %v", root, err)
+	}
+	if !errors.Is(err, ErrNoTests) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the error to match ErrNoTests and fs.ErrNotExist, got %v", err)
+	}
+	want := root + " is not a test data directory: it has no source_tests/, tests/ or generated_tests/ directory"
+	if err.Error() != want {
+		t.Errorf("Got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestTestLoader_LoadAllTests_LegacySourceDir(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, LegacySourceDir), 0755)
+	os.WriteFile(filepath.Join(root, LegacySourceDir, "api.json"), []byte(`{"tests": [
+		{"name": "basic", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "b"}]}]}
+	]}`), 0644)
+
+	tests, err := NewTestLoader(root, createTestConfig()).LoadAllTests(LoadOptions{Format: FormatCompact, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Name != "basic" {
+		t.Errorf("Expected the test from %s/, got %v", LegacySourceDir, tests)
+	}
+}
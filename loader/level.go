@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// ClassifyLevel estimates how far into an implementation a test is, from
+// its content alone. The first matching rule wins:
+//
+//   - 4: the test requires a behavior or variant, or expects an error
+//   - 3: the test requires a feature (comments, multiline, ...)
+//   - 1: every validation is parse and every input is one line of ASCII
+//   - 2: anything else, such as hierarchy building and typed access
+//
+// Levels stay within types.MinLevel and types.MaxLevel; level 5 is only
+// assigned by hand.
+func ClassifyLevel(test types.TestCase) int {
+	switch {
+	case len(test.Behaviors) > 0 || len(test.Variants) > 0 || test.ExpectsError():
+		return 4
+	case len(test.Features) > 0:
+		return 3
+	case onlyParse(test) && simpleInputs(test.Inputs):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// TestLevel returns a test's level: Meta.Level if set, then a valid
+// "level:N" tag, then ClassifyLevel
+func TestLevel(test types.TestCase) int {
+	if test.Meta.Level != 0 {
+		return test.Meta.Level
+	}
+	for _, tag := range test.Meta.Tags {
+		value, ok := strings.CutPrefix(tag, "level:")
+		if !ok {
+			continue
+		}
+		if level, err := strconv.Atoi(value); err == nil && level >= types.MinLevel && level <= types.MaxLevel {
+			return level
+		}
+	}
+	return ClassifyLevel(test)
+}
+
+// backfillLevels sets Meta.Level on tests that have none
+func backfillLevels(tests []types.TestCase) {
+	for i := range tests {
+		tests[i].Meta.Level = TestLevel(tests[i])
+	}
+}
+
+// onlyParse reports whether parse is the only function a test exercises
+func onlyParse(test types.TestCase) bool {
+	functions := testFunctions(test)
+	for _, fn := range functions {
+		if fn != string(config.FunctionParse) {
+			return false
+		}
+	}
+	return len(functions) > 0
+}
+
+// testFunctions lists a test's validation, its Functions metadata, and for
+// source tests each validation in Validations
+func testFunctions(test types.TestCase) []string {
+	functions := append([]string(nil), test.Functions...)
+	if test.Validation != "" {
+		functions = append(functions, test.Validation)
+	}
+	if test.Validations != nil {
+		v := reflect.ValueOf(*test.Validations)
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).IsNil() {
+				name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+				functions = append(functions, name)
+			}
+		}
+	}
+	return functions
+}
+
+// simpleInputs reports whether every input is a single line of ASCII
+func simpleInputs(inputs []string) bool {
+	nonASCII := NonASCII()
+	for _, input := range inputs {
+		if strings.ContainsAny(input, "\r\n") || nonASCII(input) {
+			return false
+		}
+	}
+	return true
+}
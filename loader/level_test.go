@@ -0,0 +1,102 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestClassifyLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		test types.TestCase
+		want int
+	}{
+		{"single-line parse", types.TestCase{Validation: "parse", Inputs: []string{"a = b"}}, 1},
+		{"parse of several single-line inputs", types.TestCase{Validation: "parse", Inputs: []string{"a = b", "c = d"}}, 1},
+		{"multi-line parse", types.TestCase{Validation: "parse", Inputs: []string{"a = b\nc = d"}}, 2},
+		{"non-ASCII parse", types.TestCase{Validation: "parse", Inputs: []string{"café = 1"}}, 2},
+		{"hierarchy", types.TestCase{Validation: "build_hierarchy", Inputs: []string{"a = b"}}, 2},
+		{"typed access", types.TestCase{Validation: "get_int", Args: []string{"a"}, Inputs: []string{"a = 1"}}, 2},
+		{"parse with another function in metadata", types.TestCase{Validation: "parse", Functions: []string{"parse", "filter"}, Inputs: []string{"a = b"}}, 2},
+		{"source test with parse only", types.TestCase{Inputs: []string{"a = b"}, Validations: &types.ValidationSet{Parse: []interface{}{}}}, 1},
+		{"source test with typed access", types.TestCase{Inputs: []string{"a = b"}, Validations: &types.ValidationSet{Parse: []interface{}{}, GetString: "b"}}, 2},
+		{"comments", types.TestCase{Validation: "parse", Inputs: []string{"a = b"}, Features: []string{"comments"}}, 3},
+		{"multiline typed access", types.TestCase{Validation: "get_string", Inputs: []string{"a =\n  b"}, Features: []string{"multiline"}}, 3},
+		{"behavior", types.TestCase{Validation: "get_bool", Inputs: []string{"a = yes"}, Features: []string{"comments"}, Behaviors: []string{"boolean_lenient"}}, 4},
+		{"variant", types.TestCase{Validation: "parse", Inputs: []string{"a = b"}, Variants: []string{"proposed_behavior"}}, 4},
+		{"error", types.TestCase{Validation: "parse", Inputs: []string{"a"}, ExpectError: true}, 4},
+	}
+	for _, tt := range tests {
+		if got := ClassifyLevel(tt.test); got != tt.want {
+			t.Errorf("%s: expected level %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestTestLevel(t *testing.T) {
+	simple := types.TestCase{Validation: "parse", Inputs: []string{"a = b"}}
+
+	tagged := simple
+	tagged.Meta.Tags = []string{"level:5"}
+	if got := TestLevel(tagged); got != 5 {
+		t.Errorf("Expected the level tag to win over classification, got %d", got)
+	}
+
+	set := tagged
+	set.Meta.Level = 3
+	if got := TestLevel(set); got != 3 {
+		t.Errorf("Expected Meta.Level to win over the tag, got %d", got)
+	}
+
+	badTag := simple
+	badTag.Meta.Tags = []string{"level:9"}
+	if got := TestLevel(badTag); got != 1 {
+		t.Errorf("Expected an out-of-range tag to be ignored, got %d", got)
+	}
+}
+
+func TestLoadTestFile_BackfillsLevel(t *testing.T) {
+	dir := t.TempDir()
+	flat := `{"tests": [
+		{"name": "a_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "a_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "b"}, "level": 5},
+		{"name": "c_parse", "inputs": ["/= c"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"]}
+	]}`
+	path := filepath.Join(dir, "levels.json")
+	if err := os.WriteFile(path, []byte(flat), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tl := NewTestLoader(dir, config.ImplementationConfig{})
+	for _, lazy := range []bool{false, true} {
+		suite, err := tl.LoadTestFile(path, LoadOptions{Format: FormatFlat, LazyExpected: lazy})
+		if err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+		var got []int
+		for _, test := range suite.Tests {
+			got = append(got, test.Meta.Level)
+		}
+		if want := []int{1, 5, 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("LazyExpected %v: expected levels %v, got %v", lazy, want, got)
+		}
+	}
+}
+
+func TestGetTestStatistics_ByLevel(t *testing.T) {
+	tests := []types.TestCase{
+		{Validation: "parse", Inputs: []string{"a = b"}},
+		{Validation: "parse", Inputs: []string{"a = b"}},
+		{Validation: "get_int", Inputs: []string{"a = 1"}},
+		{Validation: "parse", Inputs: []string{"a = b"}, Meta: types.TestMetadata{Level: 5}},
+	}
+	stats := NewTestLoader("", config.ImplementationConfig{}).GetTestStatistics(tests)
+	if want := map[int]int{1: 2, 2: 1, 5: 1}; !reflect.DeepEqual(stats.ByLevel, want) {
+		t.Errorf("Expected ByLevel %v, got %v", want, stats.ByLevel)
+	}
+}
@@ -0,0 +1,82 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// DanglingLink is a flat test whose SourceTest names no source test
+type DanglingLink struct {
+	Test       string `json:"test"`
+	SourceTest string `json:"source_test"`
+}
+
+// SourceLinkReport lists broken traceability between flat and source tests
+type SourceLinkReport struct {
+	Dangling []DanglingLink `json:"dangling,omitempty"` // Flat tests referring to a missing source test
+	Orphaned []string       `json:"orphaned,omitempty"` // Source tests no flat test derives from
+}
+
+// Empty reports whether every link is intact
+func (r SourceLinkReport) Empty() bool {
+	return len(r.Dangling) == 0 && len(r.Orphaned) == 0
+}
+
+// Lines describes each broken link on its own line
+func (r SourceLinkReport) Lines() []string {
+	lines := make([]string, 0, len(r.Dangling)+len(r.Orphaned))
+	for _, link := range r.Dangling {
+		lines = append(lines, fmt.Sprintf("%s: source test %q does not exist", link.Test, link.SourceTest))
+	}
+	for _, name := range r.Orphaned {
+		lines = append(lines, fmt.Sprintf("%s: no generated tests", name))
+	}
+	return lines
+}
+
+// CheckSourceLinks compares the SourceTest references of flat tests with
+// the names of source tests. Flat tests without a SourceTest are not
+// checked. Both lists in the report are sorted by test name.
+func CheckSourceLinks(sources, flat []types.TestCase) SourceLinkReport {
+	var report SourceLinkReport
+	sourceNames := make(map[string]bool, len(sources))
+	for _, test := range sources {
+		sourceNames[test.Name] = true
+	}
+	derived := make(map[string]bool)
+	for _, test := range flat {
+		if test.SourceTest == "" {
+			continue
+		}
+		derived[test.SourceTest] = true
+		if !sourceNames[test.SourceTest] {
+			report.Dangling = append(report.Dangling, DanglingLink{Test: test.Name, SourceTest: test.SourceTest})
+		}
+	}
+	for name := range sourceNames {
+		if !derived[name] {
+			report.Orphaned = append(report.Orphaned, name)
+		}
+	}
+	sort.Slice(report.Dangling, func(i, j int) bool { return report.Dangling[i].Test < report.Dangling[j].Test })
+	sort.Strings(report.Orphaned)
+	return report
+}
+
+// VerifySourceLinks loads the source_tests and generated_tests under
+// testDataPath and checks the SourceTest links between them
+func VerifySourceLinks(testDataPath string) (SourceLinkReport, error) {
+	tl := NewTestLoader(testDataPath, config.ImplementationConfig{})
+	sources, err := tl.LoadAllTests(LoadOptions{Format: FormatCompact, FilterMode: FilterAll})
+	if err != nil {
+		return SourceLinkReport{}, fmt.Errorf("failed to load source tests: %w", err)
+	}
+	flat, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, LazyExpected: true})
+	if err != nil {
+		return SourceLinkReport{}, fmt.Errorf("failed to load generated tests: %w", err)
+	}
+	return CheckSourceLinks(sources, flat), nil
+}
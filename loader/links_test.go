@@ -0,0 +1,46 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerifySourceLinks(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "source_tests"), 0755)
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	os.WriteFile(filepath.Join(dir, "source_tests", "api.json"), []byte(`{"tests": [
+		{"name": "kept", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]},
+		{"name": "forgotten", "inputs": ["b = 2"], "tests": [{"function": "parse", "expect": [{"key": "b", "value": "2"}]}]}
+	]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(`{"tests": [
+		{"name": "kept_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "source_test": "kept"},
+		{"name": "renamed_parse", "inputs": ["c = 3"], "validation": "parse", "expected": {"count": 1}, "source_test": "renamed"},
+		{"name": "handwritten", "inputs": ["d = 4"], "validation": "parse", "expected": {"count": 1}}
+	]}`), 0644)
+
+	links, err := VerifySourceLinks(dir)
+	if err != nil {
+		t.Fatalf("VerifySourceLinks failed: %v", err)
+	}
+	want := SourceLinkReport{
+		Dangling: []DanglingLink{{Test: "renamed_parse", SourceTest: "renamed"}},
+		Orphaned: []string{"forgotten"},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("Expected %+v, got %+v", want, links)
+	}
+	if links.Empty() {
+		t.Error("Expected broken links to make the report non-empty")
+	}
+	wantLines := []string{`renamed_parse: source test "renamed" does not exist`, "forgotten: no generated tests"}
+	if got := links.Lines(); !reflect.DeepEqual(got, wantLines) {
+		t.Errorf("Expected lines %v, got %v", wantLines, got)
+	}
+
+	if empty := CheckSourceLinks(nil, nil); !empty.Empty() {
+		t.Errorf("Expected no broken links without tests, got %+v", empty)
+	}
+}
@@ -3,30 +3,79 @@
 package loader
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 
 	"github.com/CatConfLang/ccl-test-lib/config"
 	"github.com/CatConfLang/ccl-test-lib/types"
 )
 
+// Sentinel errors returned (wrapped) by the loader; test with errors.Is
+var (
+	// ErrParse is returned when a test file cannot be decoded
+	ErrParse = errors.New("invalid test file")
+	// ErrNoTests is returned when a test directory is missing or has no test files
+	ErrNoTests = errors.New("no tests found")
+)
+
 // TestLoader handles both source and flat format loading with type-safe filtering
 type TestLoader struct {
 	TestDataPath string
 	Config       config.ImplementationConfig
 	UseFlat      bool // true = generated flat format, false = source format
+
+	// ReadFile reads test files (os.ReadFile if nil)
+	ReadFile func(name string) ([]byte, error)
+
+	// Logger receives file, parse, and filtering events (silent if nil)
+	Logger *slog.Logger
+
+	// Flatten returns the generated flat file for a source test file. If
+	// set, LoadAllTests in FormatFlat falls back to flattening source_tests
+	// when generated_tests is missing or empty (see generator.FlattenFile).
+	Flatten func(sourceFile string) ([]byte, error)
+
+	// Defaults are the options used by methods passed the zero LoadOptions
+	// (see NewTestLoaderWithOptions)
+	Defaults LoadOptions
 }
 
-// LoadOptions controls test loading behavior
+// LoadOptions controls test loading behavior. The zero value loads source
+// tests compatible with the loader's config, unless the loader has
+// Defaults, which replace it.
 type LoadOptions struct {
-	Format       TestFormat                // Source or Flat
-	FilterMode   FilterMode                // Compatible, All, or Custom
-	CustomFilter func(types.TestCase) bool // Custom filtering function
+	Format          TestFormat                // Source or Flat
+	FilterMode      FilterMode                // Compatible, All, or Custom
+	CustomFilter    func(types.TestCase) bool // Custom filtering function
+	LazyExpected    bool                      // Flat format: keep Expected undecoded in RawExpected until ExpectedValue is called
+	EntryProvenance bool                      // Flat format: keep generated entries' "src_index" fields in Meta.EntrySources (they are stripped either way)
+	Dedup           bool                      // Name lookups: use the first of several same-named tests instead of failing as ambiguous
+	OverridesPath   string                    // Overrides file applied after loading (see LoadOverrides)
+	NormalizeInput  InputNormalization        // Line ending normalization applied to Inputs after decoding
+	LenientUTF8     bool                      // Replace invalid UTF-8 in inputs with U+FFFD and log a warning instead of failing
+	StrictMetadata  bool                      // Fail a file using features, behaviors, or variants this library does not define instead of flagging the tests (see UnknownMetadata)
+	Scan            *ScanOptions              // Which files in the test directory are loaded (DefaultScanOptions if nil)
+	IncludeTags     []string                  // Keep only tests with at least one of these Meta.Tags (applied after FilterMode)
+	ExcludeTags     []string                  // Drop tests with any of these Meta.Tags (applied after FilterMode)
+	InputPredicates []InputPredicate          // Keep only tests with an input matching all of these (applied after FilterMode)
+	SkipSlow        bool                      // Drop tests whose limits mark them slow (applied after FilterMode)
+	ShardIndex      int                       // Keep only the tests in this shard, from 0 to ShardCount-1 (see ShardOf)
+	ShardCount      int                       // Split the filtered tests into this many shards (0 for no sharding)
+	SampleSize      int                       // LoadAllTests: keep a stratified sample of this many tests, by validation, after filtering and sharding (0 keeps all; see SampleTests)
+	SampleSeed      int64                     // Seed choosing the SampleSize sample
+	Progress        ProgressFunc              // Called with StageLoad as LoadAllTests reads each file (optional)
 }
 
-// TestFormat specifies which test format to load
+// TestFormat specifies which test format to load. FormatCompact is the
+// zero value, so options that leave Format unset load source tests.
 type TestFormat int
 
 const (
@@ -34,7 +83,9 @@ const (
 	FormatFlat                      // generated_tests/ (implementation-friendly)
 )
 
-// FilterMode specifies how tests should be filtered
+// FilterMode specifies how tests should be filtered. FilterCompatible is
+// the zero value, so options that leave FilterMode unset drop tests the
+// config cannot run; ask for FilterAll to keep them.
 type FilterMode int
 
 const (
@@ -52,67 +103,249 @@ func NewTestLoader(testDataPath string, cfg config.ImplementationConfig) *TestLo
 	}
 }
 
-// LoadAllTests loads all tests from the configured test data path
-func (tl *TestLoader) LoadAllTests(opts LoadOptions) ([]types.TestCase, error) {
-	var testDir string
-	var pattern string
-
-	switch opts.Format {
-	case FormatCompact:
-		testDir = filepath.Join(tl.TestDataPath, "source_tests")
-		pattern = "*.json"
-	case FormatFlat:
-		testDir = filepath.Join(tl.TestDataPath, "generated_tests")
-		pattern = "*.json"
-	default:
-		return nil, fmt.Errorf("unsupported test format: %v", opts.Format)
+// NewTestLoaderWithOptions creates a test loader whose methods use defaults
+// when passed the zero LoadOptions
+func NewTestLoaderWithOptions(testDataPath string, cfg config.ImplementationConfig, defaults LoadOptions) *TestLoader {
+	tl := NewTestLoader(testDataPath, cfg)
+	tl.Defaults = defaults
+	return tl
+}
+
+// WithOptions returns a copy of the loader with defaults as its Defaults
+func (tl *TestLoader) WithOptions(defaults LoadOptions) *TestLoader {
+	derived := *tl
+	derived.Defaults = defaults
+	return &derived
+}
+
+// options returns opts, or tl.Defaults if opts is the zero LoadOptions.
+// Options are used whole, never merged with the defaults field by field.
+func (tl *TestLoader) options(opts LoadOptions) LoadOptions {
+	if reflect.ValueOf(opts).IsZero() {
+		return tl.Defaults
 	}
+	return opts
+}
 
-	files, err := filepath.Glob(filepath.Join(testDir, pattern))
+// logger returns tl.Logger, or a logger that discards everything
+func (tl *TestLoader) logger() *slog.Logger {
+	if tl.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return tl.Logger
+}
+
+// LoadAllTests loads all tests from the configured test data path. It returns
+// ErrNoTests if the format's directory is missing (also matching
+// fs.ErrNotExist) or holds no test files, and ErrParse if a file is invalid.
+func (tl *TestLoader) LoadAllTests(opts LoadOptions) ([]types.TestCase, error) {
+	opts = tl.options(opts)
+	if err := CheckShard(opts.ShardIndex, opts.ShardCount); err != nil {
+		return nil, err
+	}
+	files, err := tl.testFiles(opts)
+	fallback := false
+	if errors.Is(err, ErrNoTests) && opts.Format == FormatFlat && tl.Flatten != nil {
+		sourceOpts := opts
+		sourceOpts.Format = FormatCompact
+		if sources, sourceErr := tl.testFiles(sourceOpts); sourceErr == nil {
+			tl.logger().Info("no generated tests, flattening source tests", "files", len(sources))
+			files, err, fallback = sources, nil, true
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find test files: %w", err)
+		return nil, err
 	}
 
 	var allTests []types.TestCase
-	for _, file := range files {
-		suite, err := tl.LoadTestFile(file, opts)
+	opts.Progress.Report(StageLoad, 0, len(files))
+	for i, file := range files {
+		var suite *types.TestSuite
+		if fallback {
+			suite, err = tl.loadFlattened(file, opts)
+		} else {
+			suite, err = tl.LoadTestFile(file, opts)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to load %s: %w", file, err)
 		}
 		allTests = append(allTests, suite.Tests...)
+		opts.Progress.Report(StageLoad, i+1, len(files))
+	}
+
+	if opts.OverridesPath != "" {
+		overrides, err := LoadOverrides(opts.OverridesPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := ApplyOverrides(allTests, overrides); err != nil {
+			return nil, err
+		}
 	}
 
-	return tl.applyFiltering(allTests, opts), nil
+	allTests = tl.applyFiltering(allTests, opts)
+	if opts.SampleSize > 0 {
+		allTests = SampleTests(allTests, opts.SampleSize, opts.SampleSeed, nil)
+	}
+	return allTests, nil
+}
+
+// testDir returns the directory holding the test files for format (see
+// ResolveLayout)
+func (tl *TestLoader) testDir(format TestFormat) (string, error) {
+	if format != FormatCompact && format != FormatFlat {
+		return "", fmt.Errorf("unsupported test format: %v", format)
+	}
+	// A root with no test directories still resolves to the default names,
+	// which testFiles reports as missing
+	layout, err := ResolveLayout(tl.TestDataPath)
+	var layoutErr *LayoutError
+	if err != nil && !errors.As(err, &layoutErr) {
+		return "", err
+	}
+	return layout.Dir(format), nil
 }
 
-// LoadTestFile loads a single test file
+// testFiles lists the test files for opts.Format in name order
+func (tl *TestLoader) testFiles(opts LoadOptions) ([]string, error) {
+	testDir, err := tl.testDir(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(testDir); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoTests, err)
+	}
+	files, err := ScanDir(testDir, scanOptions(opts.Scan))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoTests, testDir)
+	}
+	return files, nil
+}
+
+// GetStatisticsByFile computes GetTestStatistics for the tests of each
+// test file separately, in file name order, after the same filtering as
+// LoadAllTests. Source tests are not flattened when there are no generated
+// tests. Errors are as for LoadAllTests.
+func (tl *TestLoader) GetStatisticsByFile(opts LoadOptions) ([]types.FileStatistics, error) {
+	opts = tl.options(opts)
+	if err := CheckShard(opts.ShardIndex, opts.ShardCount); err != nil {
+		return nil, err
+	}
+	files, err := tl.testFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+	testDir, _ := tl.testDir(opts.Format) // testFiles checked the format
+
+	stats := make([]types.FileStatistics, 0, len(files))
+	opts.Progress.Report(StageLoad, 0, len(files))
+	for i, file := range files {
+		suite, err := tl.LoadTestFile(file, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", file, err)
+		}
+		name, err := filepath.Rel(testDir, file)
+		if err != nil {
+			name = filepath.Base(file)
+		}
+		stats = append(stats, types.FileStatistics{
+			File:  filepath.ToSlash(name),
+			Stats: tl.GetTestStatistics(tl.applyFiltering(suite.Tests, opts)),
+		})
+		opts.Progress.Report(StageLoad, i+1, len(files))
+	}
+	return stats, nil
+}
+
+// LoadTestFile loads a single test file. Decoding failures wrap ErrParse;
+// files in an unknown format version return UnsupportedFormatVersionError.
 func (tl *TestLoader) LoadTestFile(filename string, opts LoadOptions) (*types.TestSuite, error) {
-	data, err := os.ReadFile(filename)
+	opts = tl.options(opts)
+	data, err := tl.readTestFile(filename)
 	if err != nil {
+		return nil, err
+	}
+	return tl.decodeTestFile(filename, data, opts)
+}
+
+// loadFlattened loads a source test file as the flat tests tl.Flatten
+// generates from it, marking each with Meta.Flattened
+func (tl *TestLoader) loadFlattened(sourceFile string, opts LoadOptions) (*types.TestSuite, error) {
+	data, err := tl.Flatten(sourceFile)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := tl.decodeTestFile(sourceFile, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suite.Tests {
+		suite.Tests[i].Meta.Flattened = true
+	}
+	return suite, nil
+}
+
+// readTestFile reads a test file with tl.ReadFile
+func (tl *TestLoader) readTestFile(filename string) ([]byte, error) {
+	readFile := tl.ReadFile
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+	log := tl.logger().With("file", filename)
+	log.Debug("opening test file")
+	data, err := readFile(filename)
+	if err != nil {
+		log.Warn("failed to read test file", "reason", err)
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return data, nil
+}
 
+// decodeTestFile decodes the contents of a test file in opts.Format
+func (tl *TestLoader) decodeTestFile(filename string, data []byte, opts LoadOptions) (*types.TestSuite, error) {
+	log := tl.logger().With("file", filename)
 	var suite types.TestSuite
+	var err error
+
+	data = StripBOM(data)
+	sections, multiSuite, err := splitSuites(data)
+	if err != nil {
+		log.Warn("failed to parse test file", "reason", err)
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	if multiSuite {
+		return tl.decodeSuites(filename, sections, opts)
+	}
+
+	for _, problem := range invalidInputs(filename, data) {
+		if !opts.LenientUTF8 {
+			log.Warn("failed to parse test file", "reason", problem)
+			return nil, fmt.Errorf("%w: %w", ErrParse, problem)
+		}
+		log.Warn("replaced invalid UTF-8 in input", "test", problem.Test, "input", problem.Input, "offset", problem.Offset)
+	}
 
 	// Handle format detection
 	if opts.Format == FormatFlat {
 		// Flat format - can be either array of TestCase or object with tests array
 		var tests []types.TestCase
-
-		// Try to unmarshal as TestSuite first (object with "tests" field)
-		var testSuite types.TestSuite
-		if err := json.Unmarshal(data, &testSuite); err == nil && len(testSuite.Tests) > 0 {
-			tests = testSuite.Tests
-		} else {
-			// Fallback: try as array of TestCase
-			if err := json.Unmarshal(data, &tests); err != nil {
-				return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
-			}
+		data, err = upgradeFlat(data)
+		if err == nil && opts.LazyExpected {
+			tests, err = decodeFlatLazy(data, opts.EntryProvenance)
+		} else if err == nil {
+			tests, err = decodeFlat(data, opts.EntryProvenance)
 		}
-
-		// Convert structured Expected objects to simple values for flat format tests
-		for i := range tests {
-			tests[i].Expected = tl.extractExpectedValue(tests[i].Validation, tests[i].Expected)
+		if err != nil {
+			log.Warn("failed to parse test file", "reason", err)
+			var unsupported *UnsupportedFormatVersionError
+			if errors.As(err, &unsupported) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %w", ErrParse, err)
 		}
 
 		suite = types.TestSuite{
@@ -120,11 +353,13 @@ func (tl *TestLoader) LoadTestFile(filename string, opts LoadOptions) (*types.Te
 			Version: "1.0",
 			Tests:   tests,
 		}
+		suite.Generator, _ = ReadGeneratorInfo(data)
 	} else {
 		// Compact format - array of compact test objects
 		tests, err := tl.loadCompactFormat(data)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse compact format: %w", err)
+			log.Warn("failed to parse test file", "reason", err)
+			return nil, fmt.Errorf("%w: failed to parse compact format: %w", ErrParse, err)
 		}
 		suite = types.TestSuite{
 			Suite:   "Compact Format",
@@ -133,9 +368,171 @@ func (tl *TestLoader) LoadTestFile(filename string, opts LoadOptions) (*types.Te
 		}
 	}
 
+	if reqs := fileRequirements(data); len(reqs.Features)+len(reqs.Behaviors)+len(reqs.Variants) > 0 {
+		for i := range suite.Tests {
+			reqs.Apply(&suite.Tests[i])
+		}
+	}
+	backfillLevels(suite.Tests)
+	for i := range suite.Tests {
+		suite.Tests[i].Normalize()
+	}
+	if err := flagUnknownMetadata(log, filename, suite.Tests, opts.StrictMetadata); err != nil {
+		log.Warn("failed to parse test file", "reason", err)
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+	NormalizeInputs(suite.Tests, opts.NormalizeInput)
+	log.Debug("parsed test file", "tests", len(suite.Tests))
 	return &suite, nil
 }
 
+// decodeSuites decodes each suite of a multi-suite file as its own file
+// and joins their tests, recording each test's suite name
+func (tl *TestLoader) decodeSuites(filename string, sections []suiteSection, opts LoadOptions) (*types.TestSuite, error) {
+	var joined *types.TestSuite
+	for _, section := range sections {
+		suite, err := tl.decodeTestFile(filename, section.Data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("suite %q: %w", section.Name, err)
+		}
+		for i := range suite.Tests {
+			suite.Tests[i].Suite = section.Name
+		}
+		if joined == nil {
+			joined = suite
+		} else {
+			joined.Tests = append(joined.Tests, suite.Tests...)
+		}
+	}
+	if joined == nil {
+		joined = &types.TestSuite{Suite: "Compact Format", Version: "1.0"}
+		if opts.Format == FormatFlat {
+			joined.Suite = "Flat Format"
+		}
+	}
+	return joined, nil
+}
+
+// decodeFlat decodes flat tests, converting structured Expected objects to
+// the simple values runners compare against. With entryProvenance the
+// entries' source indices are kept in Meta.EntrySources.
+func decodeFlat(data []byte, entryProvenance bool) ([]types.TestCase, error) {
+	var flat []flatTest
+
+	// Try to unmarshal as an object with a "tests" field first
+	var testSuite struct {
+		Tests []flatTest `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &testSuite); err == nil {
+		flat = testSuite.Tests
+	} else {
+		// Fallback: try as array of TestCase
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
+		}
+	}
+
+	tests := make([]types.TestCase, len(flat))
+	for i, test := range flat {
+		tests[i] = test.testCase()
+		if tolerance := types.ExtractTolerance(tests[i].Expected); tolerance != 0 {
+			tests[i].Tolerance = tolerance
+		}
+		if data, err := json.Marshal(tests[i].Expected); err == nil && tests[i].Expected != nil {
+			tests[i].Meta.ExpectedBytes = len(data) // Measured before ExtractExpected, like RawExpected
+		}
+		if entryProvenance {
+			structured, _ := tests[i].Expected.(map[string]interface{})
+			tests[i].Meta.EntrySources = entrySources(structured["entries"])
+		}
+		tests[i].Expected = types.ExtractExpected(tests[i].Validation, tests[i].Expected)
+	}
+	return tests, nil
+}
+
+// flatTest is a generated test, whose level and limits TestCase keeps in Meta
+type flatTest struct {
+	types.TestCase
+	Level  int           `json:"level,omitempty"`
+	Limits *types.Limits `json:"limits,omitempty"`
+}
+
+func (t flatTest) testCase() types.TestCase {
+	if t.Level != 0 {
+		t.TestCase.Meta.Level = t.Level
+	}
+	if t.Limits != nil {
+		t.TestCase.Meta.Limits = t.Limits
+	}
+	return t.TestCase
+}
+
+// lazyFlatTest shadows TestCase.Expected so it is kept as raw JSON
+type lazyFlatTest struct {
+	flatTest
+	Expected json.RawMessage `json:"expected,omitempty"`
+}
+
+// decodeFlatLazy decodes flat tests without decoding Expected
+func decodeFlatLazy(data []byte, entryProvenance bool) ([]types.TestCase, error) {
+	var lazy []lazyFlatTest
+
+	var testSuite struct {
+		Tests []lazyFlatTest `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &testSuite); err == nil {
+		lazy = testSuite.Tests
+	} else if err := json.Unmarshal(data, &lazy); err != nil {
+		return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
+	}
+
+	tests := make([]types.TestCase, len(lazy))
+	for i, test := range lazy {
+		tests[i] = test.testCase()
+		tests[i].RawExpected = test.Expected
+		var compact bytes.Buffer
+		if json.Compact(&compact, test.Expected) == nil {
+			tests[i].Meta.ExpectedBytes = compact.Len()
+		}
+
+		// Tolerance is needed for comparison, so read it without decoding the rest
+		var structured struct {
+			Tolerance *float64 `json:"tolerance"`
+		}
+		if json.Unmarshal(test.Expected, &structured) == nil && structured.Tolerance != nil {
+			tests[i].Tolerance = *structured.Tolerance
+		}
+		if entryProvenance {
+			var entries struct {
+				Entries []interface{} `json:"entries"`
+			}
+			if json.Unmarshal(test.Expected, &entries) == nil {
+				tests[i].Meta.EntrySources = entrySources(entries.Entries)
+			}
+		}
+	}
+	return tests, nil
+}
+
+// entrySources reads the source index of each generated expected entry, or
+// nil unless every entry has one
+func entrySources(expected interface{}) []int {
+	entries, _ := expected.([]interface{})
+	if len(entries) == 0 {
+		return nil
+	}
+	sources := make([]int, len(entries))
+	for i, entry := range entries {
+		entryMap, _ := entry.(map[string]interface{})
+		index, ok := entryMap[types.EntrySourceKey].(float64)
+		if !ok {
+			return nil
+		}
+		sources[i] = int(index)
+	}
+	return sources
+}
+
 // LoadTestsByFunction loads tests filtered by CCL function
 func (tl *TestLoader) LoadTestsByFunction(fn config.CCLFunction, opts LoadOptions) ([]types.TestCase, error) {
 	allTests, err := tl.LoadAllTests(opts)
@@ -163,8 +560,30 @@ func (tl *TestLoader) LoadTestsByFunction(fn config.CCLFunction, opts LoadOption
 	return filtered, nil
 }
 
-// applyFiltering applies the appropriate filtering based on options
+// applyFiltering applies FilterMode, then the tags, input predicates,
+// SkipSlow, and sharding
 func (tl *TestLoader) applyFiltering(tests []types.TestCase, opts LoadOptions) []types.TestCase {
+	tests = tl.filterByMode(tests, opts)
+	if len(opts.IncludeTags) == 0 && len(opts.ExcludeTags) == 0 && len(opts.InputPredicates) == 0 && !opts.SkipSlow && opts.ShardCount <= 1 {
+		return tests
+	}
+	var filtered []types.TestCase
+	for _, test := range tests {
+		if opts.SkipSlow && test.Meta.Limits.IsSlow() {
+			continue
+		}
+		if !inShard(test, opts) {
+			continue
+		}
+		if matchesTags(test, opts.IncludeTags, opts.ExcludeTags) && matchesInputs(test, opts.InputPredicates) {
+			filtered = append(filtered, test)
+		}
+	}
+	return filtered
+}
+
+// filterByMode applies the filtering selected by FilterMode
+func (tl *TestLoader) filterByMode(tests []types.TestCase, opts LoadOptions) []types.TestCase {
 	switch opts.FilterMode {
 	case FilterCompatible:
 		return tl.FilterCompatibleTests(tests)
@@ -188,22 +607,92 @@ func (tl *TestLoader) applyFiltering(tests []types.TestCase, opts LoadOptions) [
 
 // FilterCompatibleTests filters tests based on implementation capabilities
 func (tl *TestLoader) FilterCompatibleTests(tests []types.TestCase) []types.TestCase {
+	log := tl.logger()
 	var compatible []types.TestCase
 	for _, test := range tests {
-		if tl.IsTestCompatible(test) {
-			compatible = append(compatible, test)
+		if reason := tl.ExplainCompatibility(test); reason != "" {
+			log.Debug("rejected incompatible test", "test", test.Name, "reason", reason)
+			continue
 		}
+		compatible = append(compatible, test)
+	}
+	return compatible
+}
+
+// IncompatibleTest is a test an implementation cannot run and the reason
+// ExplainCompatibility gives for it
+type IncompatibleTest struct {
+	Test   types.TestCase
+	Reason string
+	Kind   RejectionKind
+}
+
+// RejectionKind classifies why a test is incompatible, separating what a
+// config change can fix from what it cannot
+type RejectionKind string
+
+const (
+	RejectionUnsupported RejectionKind = "unsupported" // The implementation lacks a required function or feature
+	RejectionRequirement RejectionKind = "requirement" // A required behavior or variant was not chosen
+	RejectionConflict    RejectionKind = "conflict"    // The test conflicts with a behavior or variant that was chosen
+	RejectionUnknown     RejectionKind = "unknown"     // The test uses metadata this library version does not define (see UnknownMetadata)
+)
+
+// Rejection is why a test is incompatible with an implementation
+type Rejection struct {
+	Kind   RejectionKind
+	Name   string // The function, feature, behavior, or variant at fault
+	Reason string // As returned by ExplainCompatibility
+}
+
+// IncompatibleTests returns the tests FilterCompatibleTests would drop, in
+// the same order, each with its reason
+func (tl *TestLoader) IncompatibleTests(tests []types.TestCase) []IncompatibleTest {
+	var incompatible []IncompatibleTest
+	for _, test := range tests {
+		if rejection, rejected := tl.ExplainRejection(test); rejected {
+			incompatible = append(incompatible, IncompatibleTest{Test: test, Reason: rejection.Reason, Kind: rejection.Kind})
+		}
+	}
+	return incompatible
+}
+
+// Compatibility reports IsTestCompatible for each test, in the same order,
+// so callers that group tests several ways check each test only once
+func (tl *TestLoader) Compatibility(tests []types.TestCase) []bool {
+	compatible := make([]bool, len(tests))
+	for i, test := range tests {
+		compatible[i] = tl.IsTestCompatible(test)
 	}
 	return compatible
 }
 
 // IsTestCompatible checks if a test is compatible with the implementation
 func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
+	return tl.ExplainCompatibility(test) == ""
+}
+
+// ExplainCompatibility returns why a test is incompatible with the
+// implementation, or "" if it is compatible
+func (tl *TestLoader) ExplainCompatibility(test types.TestCase) string {
+	rejection, _ := tl.ExplainRejection(test)
+	return rejection.Reason
+}
+
+// ExplainRejection returns why a test is incompatible with the
+// implementation and whether it is
+func (tl *TestLoader) ExplainRejection(test types.TestCase) (Rejection, bool) {
+	// No config can choose what this library does not define
+	if len(test.Meta.UnknownMetadata) > 0 {
+		unknown := test.Meta.UnknownMetadata[0]
+		return Rejection{RejectionUnknown, unknown, "uses unknown " + unknown + "; upgrade ccl-test-lib to run it"}, true
+	}
+
 	// Check function requirements
 	if test.Validation != "" {
 		fn := config.CCLFunction(test.Validation)
 		if !tl.Config.HasFunction(fn) {
-			return false
+			return Rejection{RejectionUnsupported, test.Validation, "unsupported function " + test.Validation}, true
 		}
 	}
 
@@ -211,7 +700,7 @@ func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
 	for _, fnStr := range test.Functions {
 		fn := config.CCLFunction(fnStr)
 		if !tl.Config.HasFunction(fn) {
-			return false
+			return Rejection{RejectionUnsupported, fnStr, "unsupported function " + fnStr}, true
 		}
 	}
 
@@ -219,7 +708,7 @@ func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
 	for _, featureStr := range test.Features {
 		feature := config.CCLFeature(featureStr)
 		if !tl.Config.HasFeature(feature) {
-			return false
+			return Rejection{RejectionUnsupported, featureStr, "unsupported feature " + featureStr}, true
 		}
 	}
 
@@ -228,14 +717,14 @@ func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
 		for _, behaviorStr := range test.Conflicts.Behaviors {
 			behavior := config.CCLBehavior(behaviorStr)
 			if tl.Config.HasBehavior(behavior) {
-				return false // This test conflicts with our behavior choice
+				return Rejection{RejectionConflict, behaviorStr, "conflicts with behavior " + behaviorStr}, true // This test conflicts with our behavior choice
 			}
 		}
 
 		for _, variantStr := range test.Conflicts.Variants {
 			variant := config.CCLVariant(variantStr)
 			if tl.Config.HasVariant(variant) {
-				return false // This test conflicts with our variant choice
+				return Rejection{RejectionConflict, variantStr, "conflicts with variant " + variantStr}, true // This test conflicts with our variant choice
 			}
 		}
 	}
@@ -244,7 +733,10 @@ func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
 	for _, behaviorStr := range test.Behaviors {
 		behavior := config.CCLBehavior(behaviorStr)
 		if !tl.Config.HasBehavior(behavior) {
-			return false
+			if group, ok := config.BehaviorGroup(behavior); ok && slices.Contains(tl.Config.UndecidedGroups(), group) {
+				return Rejection{RejectionRequirement, behaviorStr, "requires behavior " + behaviorStr + " but the config chooses no " + group + " behavior"}, true
+			}
+			return Rejection{RejectionRequirement, behaviorStr, "requires behavior " + behaviorStr}, true
 		}
 	}
 
@@ -252,70 +744,86 @@ func (tl *TestLoader) IsTestCompatible(test types.TestCase) bool {
 	for _, variantStr := range test.Variants {
 		variant := config.CCLVariant(variantStr)
 		if !tl.Config.HasVariant(variant) {
-			return false
+			return Rejection{RejectionRequirement, variantStr, "requires variant " + variantStr}, true
 		}
 	}
 
-	return true
+	return Rejection{}, false
 }
 
-// FilterByTags provides legacy tag-based filtering for backward compatibility
+// FilterByTags keeps tests that have none of excludeTags and, if
+// includeTags is non-empty, at least one of includeTags.
+//
+// Deprecated: set LoadOptions.IncludeTags and ExcludeTags instead, which
+// are applied while loading.
 func (tl *TestLoader) FilterByTags(tests []types.TestCase, includeTags, excludeTags []string) []types.TestCase {
 	var filtered []types.TestCase
-
-testLoop:
 	for _, test := range tests {
-		// Check exclude tags first
-		for _, excludeTag := range excludeTags {
-			for _, testTag := range test.Meta.Tags {
-				if testTag == excludeTag {
-					continue testLoop
-				}
-			}
+		if matchesTags(test, includeTags, excludeTags) {
+			filtered = append(filtered, test)
 		}
+	}
+	return filtered
+}
 
-		// Check include tags (if any specified, at least one must match)
-		if len(includeTags) > 0 {
-			hasIncludeTag := false
-			for _, includeTag := range includeTags {
-				for _, testTag := range test.Meta.Tags {
-					if testTag == includeTag {
-						hasIncludeTag = true
-						break
-					}
-				}
-				if hasIncludeTag {
-					break
-				}
-			}
-			if !hasIncludeTag {
-				continue testLoop
-			}
+// matchesTags reports whether a test passes the include and exclude tags
+func matchesTags(test types.TestCase, includeTags, excludeTags []string) bool {
+	for _, tag := range test.Meta.Tags {
+		if slices.Contains(excludeTags, tag) {
+			return false
 		}
-
-		filtered = append(filtered, test)
 	}
-
-	return filtered
+	if len(includeTags) == 0 {
+		return true
+	}
+	for _, tag := range test.Meta.Tags {
+		if slices.Contains(includeTags, tag) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetTestStatistics provides comprehensive test suite analysis
 func (tl *TestLoader) GetTestStatistics(tests []types.TestCase) types.TestStatistics {
 	stats := types.TestStatistics{
-		TotalTests:      len(tests),
-		TotalAssertions: len(tests), // Each test case is one assertion in flat format
-		ByFunction:      make(map[string]int),
-		ByFeature:       make(map[string]int),
+		TotalTests:   len(tests),
+		ByValidation: make(map[string]int),
+		ByFunction:   make(map[string]int),
+		ByFeature:    make(map[string]int),
+		BySuite:      make(map[string]int),
+		ByLevel:      make(map[int]int),
 	}
 
-	compatibleTests := tl.FilterCompatibleTests(tests)
-	stats.CompatibleTests = len(compatibleTests)
-	stats.CompatibleAsserts = len(compatibleTests)
-
+	conflicts := make(map[Rejection]conflictCount)
 	for _, test := range tests {
+		assertions := test.AssertionCount()
+		stats.TotalAssertions += assertions
+		if test.Skip != nil {
+			stats.SkippedTests++
+		}
+		if test.Meta.Flattened {
+			stats.SourceFallback = true
+		}
+		rejection, rejected := tl.ExplainRejection(test)
+		switch {
+		case !rejected:
+			stats.CompatibleTests++
+			stats.CompatibleAsserts += assertions
+		case rejection.Kind == RejectionUnknown:
+			stats.UnknownTests++
+		case rejection.Kind != RejectionUnsupported:
+			key := Rejection{Kind: rejection.Kind, Name: rejection.Name}
+			conflicts[key] = conflictCount{tests: conflicts[key].tests + 1, assertions: conflicts[key].assertions + assertions}
+		}
+	}
+	stats.ConflictingSets = conflictSummaries(conflicts)
+	stats.LargestExpected = largestExpected(tests, LargestExpectedCount)
 
+	for _, test := range tests {
 		// Function statistics
 		if test.Validation != "" {
+			stats.ByValidation[test.Validation]++
 			stats.ByFunction[test.Validation]++
 		}
 		for _, fn := range test.Functions {
@@ -326,65 +834,109 @@ func (tl *TestLoader) GetTestStatistics(tests []types.TestCase) types.TestStatis
 		for _, feature := range test.Features {
 			stats.ByFeature[feature]++
 		}
+
+		if test.Suite != "" {
+			stats.BySuite[test.Suite]++
+		}
+		stats.ByLevel[TestLevel(test)]++
 	}
 
 	return stats
 }
 
+// LargestExpectedCount is how many tests TestStatistics.LargestExpected lists
+const LargestExpectedCount = 10
+
+// largestExpected returns the limit tests with the largest expected values,
+// largest first and in load order among equal sizes
+func largestExpected(tests []types.TestCase, limit int) []types.ExpectedSize {
+	var sizes []types.ExpectedSize
+	for _, test := range tests {
+		if bytes := test.ExpectedBytes(); bytes > 0 {
+			sizes = append(sizes, types.ExpectedSize{Test: test.Name, Suite: test.Suite, Bytes: bytes})
+		}
+	}
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].Bytes > sizes[j].Bytes })
+	return sizes[:min(limit, len(sizes))]
+}
+
+// conflictCount is the tests and assertions rejected for one reason
+type conflictCount struct {
+	tests      int
+	assertions int
+}
+
+// conflictSummaries turns counts of behavior and variant rejections into
+// ConflictSummary values, requirements before conflicts, then by name
+func conflictSummaries(counts map[Rejection]conflictCount) []types.ConflictSummary {
+	var summaries []types.ConflictSummary
+	for rejection, count := range counts {
+		summaries = append(summaries, types.ConflictSummary{
+			ConflictType:  string(rejection.Kind),
+			ConflictsWith: []string{rejection.Name},
+			TestCount:     count.tests,
+			AssertCount:   count.assertions,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].ConflictType != summaries[j].ConflictType {
+			return summaries[i].ConflictType == string(RejectionRequirement)
+		}
+		return summaries[i].ConflictsWith[0] < summaries[j].ConflictsWith[0]
+	})
+	return summaries
+}
+
 // GetCapabilityCoverage analyzes test coverage against implementation capabilities
 func (tl *TestLoader) GetCapabilityCoverage() CapabilityCoverage {
-	allTests, _ := tl.LoadAllTests(LoadOptions{
-		Format:     FormatFlat,
-		FilterMode: FilterAll,
+	allTests, err := tl.LoadAllTests(LoadOptions{
+		Format:       FormatFlat,
+		FilterMode:   FilterAll,
+		LazyExpected: true, // Coverage never reads Expected
 	})
+	if err != nil {
+		tl.logger().Warn("coverage computed without tests that failed to load", "reason", err)
+	}
+	return tl.CoverageOf(allTests)
+}
 
+// CoverageOf analyzes the coverage of tests against implementation
+// capabilities, checking each test's compatibility once
+func (tl *TestLoader) CoverageOf(tests []types.TestCase) CapabilityCoverage {
 	coverage := CapabilityCoverage{
 		Functions: make(map[config.CCLFunction]CoverageInfo),
 		Features:  make(map[config.CCLFeature]CoverageInfo),
 	}
-
-	// Analyze function coverage
 	for _, fn := range tl.Config.SupportedFunctions {
-		fnTests := 0
-		var functionSpecificTests []types.TestCase
-		fnStr := string(fn)
-		for _, test := range allTests {
-			if test.Validation == fnStr {
-				fnTests++
-				functionSpecificTests = append(functionSpecificTests, test)
-				continue
-			}
-			for _, testFn := range test.Functions {
-				if testFn == fnStr {
-					fnTests++
-					functionSpecificTests = append(functionSpecificTests, test)
-					break
-				}
-			}
-		}
-		coverage.Functions[fn] = CoverageInfo{
-			Available:  fnTests,
-			Compatible: len(tl.FilterCompatibleTests(functionSpecificTests)),
-		}
+		coverage.Functions[fn] = CoverageInfo{}
 	}
-
-	// Analyze feature coverage
 	for _, feature := range tl.Config.SupportedFeatures {
-		featureTests := 0
-		var featureSpecificTests []types.TestCase
-		featureStr := string(feature)
-		for _, test := range allTests {
-			for _, testFeature := range test.Features {
-				if testFeature == featureStr {
-					featureTests++
-					featureSpecificTests = append(featureSpecificTests, test)
-					break
-				}
+		coverage.Features[feature] = CoverageInfo{}
+	}
+
+	compatible := tl.Compatibility(tests)
+	covered := make(map[string]bool)
+	for i, test := range tests {
+		// A test counts once per function, whether named by its validation or its functions
+		clear(covered)
+		covered[test.Validation] = true
+		for _, fn := range test.Functions {
+			covered[fn] = true
+		}
+		for fn := range covered {
+			if info, ok := coverage.Functions[config.CCLFunction(fn)]; ok {
+				coverage.Functions[config.CCLFunction(fn)] = info.add(compatible[i])
 			}
 		}
-		coverage.Features[feature] = CoverageInfo{
-			Available:  featureTests,
-			Compatible: len(tl.FilterCompatibleTests(featureSpecificTests)),
+
+		clear(covered)
+		for _, feature := range test.Features {
+			covered[feature] = true
+		}
+		for feature := range covered {
+			if info, ok := coverage.Features[config.CCLFeature(feature)]; ok {
+				coverage.Features[config.CCLFeature(feature)] = info.add(compatible[i])
+			}
 		}
 	}
 
@@ -403,10 +955,21 @@ type CoverageInfo struct {
 	Compatible int // Tests compatible with this implementation
 }
 
+// add counts one more available test
+func (c CoverageInfo) add(compatible bool) CoverageInfo {
+	c.Available++
+	if compatible {
+		c.Compatible++
+	}
+	return c
+}
+
 // CompactTestFile represents the top-level structure of source test files with $schema support
 type CompactTestFile struct {
-	Schema string        `json:"$schema,omitempty"`
-	Tests  []CompactTest `json:"tests"`
+	Schema    string                `json:"$schema,omitempty"`
+	Tests     []CompactTest         `json:"tests"`
+	Generator *FileGeneratorOptions `json:"generator,omitempty"` // Per-file generation settings
+	FileRequirements
 }
 
 // CompactTest represents a test in compact format (source_tests/ files)
@@ -418,14 +981,57 @@ type CompactTest struct {
 	Behaviors []string            `json:"behaviors,omitempty"`
 	Variants  []string            `json:"variants,omitempty"`
 	Conflicts *types.ConflictSet  `json:"conflicts,omitempty"`
+	Skip      *types.SkipInfo     `json:"skip,omitempty"`
+	Limits    *types.Limits       `json:"limits,omitempty"` // Carried into TestCase.Meta.Limits
+
+	// ExpectParseError makes the test a single parse validation that must
+	// fail; Tests is ignored and should be empty
+	ExpectParseError bool   `json:"expect_parse_error,omitempty"`
+	ErrorType        string `json:"error_type,omitempty"` // Kind of parse error expected; optional
+
+	UnicodeNormalization types.UnicodeNormalization `json:"unicode_normalization,omitempty"`
+}
+
+// Validations returns the validations the test generates: Tests, or a
+// single parse validation expecting an error for an ExpectParseError test
+func (t CompactTest) Validations() []CompactValidation {
+	if t.ExpectParseError {
+		return []CompactValidation{{Function: string(config.FunctionParse), Error: true}}
+	}
+	return t.Tests
 }
 
 // CompactValidation represents a single validation in compact format
 type CompactValidation struct {
-	Function string      `json:"function"`
-	Expect   interface{} `json:"expect"`
-	Args     []string    `json:"args,omitempty"`
-	Error    bool        `json:"error,omitempty"`
+	Function  string      `json:"function"`
+	Expect    interface{} `json:"expect"`
+	Args      []string    `json:"args,omitempty"`
+	Error     bool        `json:"error,omitempty"`
+	Tolerance float64     `json:"tolerance,omitempty"` // get_float only
+	Default   *string     `json:"default,omitempty"`   // Typed access only: returned when the key path is absent
+}
+
+// UnmarshalJSON decodes a compact validation, keeping the key order of
+// build_hierarchy expectations in a types.OrderedMap
+func (v *CompactValidation) UnmarshalJSON(data []byte) error {
+	type plain CompactValidation
+	var raw struct {
+		plain
+		Expect json.RawMessage `json:"expect"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*v = CompactValidation(raw.plain)
+	if len(raw.Expect) == 0 {
+		return nil
+	}
+	if v.Function == string(config.FunctionBuildHierarchy) {
+		expect, err := types.DecodeOrdered(raw.Expect)
+		v.Expect = expect
+		return err
+	}
+	return json.Unmarshal(raw.Expect, &v.Expect)
 }
 
 // loadCompactFormat parses compact format and converts to TestCase array
@@ -454,36 +1060,30 @@ func (tl *TestLoader) loadCompactFormat(data []byte) ([]types.TestCase, error) {
 			}
 		}
 
-		// Ensure all slice fields are never nil
-		features := compact.Features
-		if features == nil {
-			features = make([]string, 0)
-		}
-		behaviors := compact.Behaviors
-		if behaviors == nil {
-			behaviors = make([]string, 0)
-		}
-		variants := compact.Variants
-		if variants == nil {
-			variants = make([]string, 0)
-		}
-
 		testCase := types.TestCase{
 			Name:      compact.Name,
 			Inputs:    compact.Inputs,
-			Features:  features,
-			Behaviors: behaviors,
-			Variants:  variants,
+			Features:  compact.Features,
+			Behaviors: compact.Behaviors,
+			Variants:  compact.Variants,
 			Conflicts: conflicts,
-			Meta:      types.TestMetadata{},
+			Skip:      compact.Skip,
+			Meta:      types.TestMetadata{Limits: compact.Limits},
+
+			UnicodeNormalization: compact.UnicodeNormalization,
 		}
 
-		// Create ValidationSet from compact tests array
+		// Create ValidationSet from compact tests array, counting every
+		// validation even when a function repeats and the set keeps the last
 		validations := &types.ValidationSet{}
+		assertions := 0
 
-		for _, test := range compact.Tests {
+		for _, test := range compact.Validations() {
 			// Create validation object with expect and args fields if present
 			validationValue := createValidationObject(test)
+			if compact.ExpectParseError && compact.ErrorType != "" {
+				validationValue["error_type"] = compact.ErrorType
+			}
 
 			switch test.Function {
 			case "parse":
@@ -492,6 +1092,8 @@ func (tl *TestLoader) loadCompactFormat(data []byte) ([]types.TestCase, error) {
 				validations.ParseIndented = validationValue
 			case "filter":
 				validations.Filter = validationValue
+			case "extract_comments":
+				validations.ExtractComments = validationValue
 			case "combine":
 				validations.Combine = validationValue
 			case "expand_dotted":
@@ -520,85 +1122,40 @@ func (tl *TestLoader) loadCompactFormat(data []byte) ([]types.TestCase, error) {
 				validations.IdentityLeft = validationValue
 			case "identity_right":
 				validations.IdentityRight = validationValue
+			default:
+				continue
 			}
+			assertions++
 		}
 
 		testCase.Validations = validations
+		testCase.Meta.Assertions = assertions
 		testCases = append(testCases, testCase)
 	}
 
 	return testCases, nil
 }
 
-// createValidationObject creates a validation object that preserves both expect and args fields
-func createValidationObject(test CompactValidation) interface{} {
-	// Only typed access functions need args field
-	typedAccessFunctions := map[string]bool{
-		"get_string": true,
-		"get_int":    true,
-		"get_bool":   true,
-		"get_float":  true,
-		"get_list":   true,
-	}
-
+// createValidationObject creates a validation object that preserves the
+// expect, args, and error fields
+func createValidationObject(test CompactValidation) map[string]interface{} {
 	validationObj := map[string]interface{}{
 		"expect": test.Expect,
 	}
 
-	// Only include args for typed access functions
-	if typedAccessFunctions[test.Function] {
+	// Include args for functions that take them, and stray args so validation can report them
+	if types.ArityOf(test.Function).TakesArgs() || len(test.Args) > 0 {
 		validationObj["args"] = test.Args
 	}
-
-	return validationObj
-}
-
-// extractExpectedValue extracts the appropriate value from a structured Expected object
-// based on the validation type, handling both simple values and structured objects
-func (tl *TestLoader) extractExpectedValue(validation string, expected interface{}) interface{} {
-	// If it's already a simple value, return as-is
-	if expected == nil {
-		return nil
+	if test.Error {
+		validationObj["error"] = true
 	}
-
-	// Check if it's a structured Expected object with Count/Value/Object/etc fields
-	expectedMap, isMap := expected.(map[string]interface{})
-	if !isMap {
-		// Simple value, return as-is
-		return expected
+	if test.Tolerance != 0 {
+		validationObj["tolerance"] = test.Tolerance
 	}
-
-	// Check if it has the structured format fields
-	_, hasCount := expectedMap["count"]
-	if !hasCount {
-		// Not a structured format, return as-is
-		return expected
-	}
-
-	// Extract the appropriate field based on validation type
-	switch validation {
-	case "parse", "parse_indented", "filter", "compose", "expand_dotted":
-		// These expect entries
-		if entries, ok := expectedMap["entries"]; ok {
-			return entries
-		}
-	case "build_hierarchy":
-		// Expects an object
-		if object, ok := expectedMap["object"]; ok {
-			return object
-		}
-	case "get_string", "get_int", "get_bool", "get_float":
-		// Typed access expects a single value
-		if value, ok := expectedMap["value"]; ok {
-			return value
-		}
-	case "get_list":
-		// List access expects a list
-		if list, ok := expectedMap["list"]; ok {
-			return list
-		}
+	if test.Default != nil {
+		validationObj["default"] = *test.Default
 	}
 
-	// Fallback: return the original expected value
-	return expected
+	return validationObj
 }
@@ -1,9 +1,17 @@
 package loader
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/CatConfLang/ccl-test-lib/config"
@@ -28,7 +36,7 @@ func setupTestData(t *testing.T) string {
 	compactTests := []CompactTest{
 		{
 			Name:     "test_parse",
-			Input:    "key = value",
+			Inputs:   []string{"key = value"},
 			Features: []string{"comments"},
 			Tests: []CompactValidation{
 				{
@@ -43,7 +51,7 @@ func setupTestData(t *testing.T) string {
 		},
 		{
 			Name:     "test_typed_access",
-			Input:    "count = 42\nflag = true",
+			Inputs:   []string{"count = 42\nflag = true"},
 			Features: []string{},
 			Tests: []CompactValidation{
 				{
@@ -74,7 +82,7 @@ func setupTestData(t *testing.T) string {
 	flatTests := []types.TestCase{
 		{
 			Name:       "test_parse_parse",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "parse",
 			Expected:   []map[string]interface{}{{"key": "key", "value": "value"}},
 			Functions:  []string{"parse"},
@@ -85,7 +93,7 @@ func setupTestData(t *testing.T) string {
 		},
 		{
 			Name:       "test_parse_build_hierarchy",
-			Input:      "key = value",
+			Inputs:     []string{"key = value"},
 			Validation: "build_hierarchy",
 			Expected:   map[string]interface{}{"key": "value"},
 			Functions:  []string{"build_hierarchy"},
@@ -96,7 +104,7 @@ func setupTestData(t *testing.T) string {
 		},
 		{
 			Name:       "test_typed_access_get_int",
-			Input:      "count = 42\nflag = true",
+			Inputs:     []string{"count = 42\nflag = true"},
 			Validation: "get_int",
 			Expected:   42,
 			Args:       []string{"count"},
@@ -190,6 +198,43 @@ func TestTestLoader_LoadTestFile_FlatFormat(t *testing.T) {
 	}
 }
 
+func TestTestLoader_GetTestStatistics_CompactAssertions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "assertions.json")
+	content := `{"tests": [
+		{"name": "typed", "inputs": ["a = 1"], "tests": [
+			{"function": "parse", "expect": [{"key": "a", "value": "1"}]},
+			{"function": "build_hierarchy", "expect": {"a": "1"}},
+			{"function": "get_string", "args": ["a"], "expect": "1"},
+			{"function": "get_int", "args": ["a"], "expect": 1}
+		]},
+		{"name": "strict", "inputs": ["a = yes"], "behaviors": ["boolean_strict"], "tests": [
+			{"function": "get_bool", "args": ["a"], "error": true},
+			{"function": "get_bool", "args": ["b", "false"], "expect": false}
+		]}
+	]}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	loader := NewTestLoader(dir, createTestConfig())
+	suite, err := loader.LoadTestFile(file, LoadOptions{Format: FormatCompact, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("Failed to load compact file: %v", err)
+	}
+
+	stats := loader.GetTestStatistics(suite.Tests)
+	if stats.TotalTests != 2 || stats.TotalAssertions != 6 {
+		t.Errorf("Expected 2 tests with 6 assertions, got %d with %d", stats.TotalTests, stats.TotalAssertions)
+	}
+	if stats.CompatibleTests != 1 || stats.CompatibleAsserts != 4 {
+		t.Errorf("Expected 1 compatible test with 4 assertions, got %d with %d", stats.CompatibleTests, stats.CompatibleAsserts)
+	}
+	if len(stats.ConflictingSets) != 1 || stats.ConflictingSets[0].TestCount != 1 || stats.ConflictingSets[0].AssertCount != 2 {
+		t.Errorf("Expected the strict test's 2 assertions in one conflicting set, got %+v", stats.ConflictingSets)
+	}
+}
+
 func TestTestLoader_LoadTestFile_CompactFormat(t *testing.T) {
 	tmpDir := setupTestData(t)
 	cfg := createTestConfig()
@@ -226,6 +271,42 @@ func TestTestLoader_LoadTestFile_CompactFormat(t *testing.T) {
 	}
 }
 
+func TestTestLoader_LoadTestFile_NormalizedSlices(t *testing.T) {
+	dir := t.TempDir()
+	flatFile := filepath.Join(dir, "flat.json")
+	os.WriteFile(flatFile, []byte(`{"tests": [
+		{"name": "bare_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "functions": [], "args": []}
+	]}`), 0644)
+	compactFile := filepath.Join(dir, "compact.json")
+	os.WriteFile(compactFile, []byte(`{"tests": [
+		{"name": "bare", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": []}]}
+	]}`), 0644)
+	tl := NewTestLoader(dir, createTestConfig())
+
+	for _, tt := range []struct {
+		file   string
+		format TestFormat
+	}{{flatFile, FormatFlat}, {compactFile, FormatCompact}} {
+		suite, err := tl.LoadTestFile(tt.file, LoadOptions{Format: tt.format, FilterMode: FilterAll})
+		if err != nil {
+			t.Fatalf("%s: failed to load: %v", filepath.Base(tt.file), err)
+		}
+		data, _ := json.Marshal(suite.Tests[0])
+		var fields map[string]json.RawMessage
+		json.Unmarshal(data, &fields)
+		for _, name := range []string{"features", "behaviors", "variants"} {
+			if string(fields[name]) != "[]" {
+				t.Errorf("%s: expected %q to be written as [], got %s", filepath.Base(tt.file), name, fields[name])
+			}
+		}
+		for _, name := range []string{"functions", "args"} {
+			if _, present := fields[name]; present {
+				t.Errorf("%s: expected empty %q to be omitted, got %s", filepath.Base(tt.file), name, fields[name])
+			}
+		}
+	}
+}
+
 func TestTestLoader_LoadAllTests_FlatFormat(t *testing.T) {
 	tmpDir := setupTestData(t)
 	cfg := createTestConfig()
@@ -332,6 +413,75 @@ func TestTestLoader_FilterCompatibleTests(t *testing.T) {
 	}
 }
 
+// recordingHandler is a slog.Handler that keeps every record it handles
+type recordingHandler struct {
+	attrs   []slog.Attr
+	records *[]slog.Record
+}
+
+func (h recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(h.attrs...)
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return recordingHandler{attrs: append(slices.Clip(h.attrs), attrs...), records: h.records}
+}
+
+func (h recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// recordAttrs returns a record's attributes as strings
+func recordAttrs(r slog.Record) map[string]string {
+	attrs := make(map[string]string)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	return attrs
+}
+
+func TestTestLoader_Logger(t *testing.T) {
+	tmpDir := setupTestData(t)
+	var records []slog.Record
+	loader := NewTestLoader(tmpDir, createTestConfig())
+	loader.Logger = slog.New(recordingHandler{records: &records})
+
+	file := filepath.Join(tmpDir, "generated_tests", "test-basic.json")
+	if _, err := loader.LoadTestFile(file, LoadOptions{Format: FormatFlat}); err != nil {
+		t.Fatalf("Failed to load test file: %v", err)
+	}
+	tests := []types.TestCase{
+		{Name: "supported", Validation: "parse"},
+		{Name: "needs_filter", Validation: "filter"},
+	}
+	if compatible := loader.FilterCompatibleTests(tests); len(compatible) != 1 {
+		t.Fatalf("Expected 1 compatible test, got %d", len(compatible))
+	}
+
+	var messages []string
+	for _, r := range records {
+		messages = append(messages, r.Message)
+	}
+	want := []string{"opening test file", "parsed test file", "rejected incompatible test"}
+	if !slices.Equal(messages, want) {
+		t.Fatalf("Expected records %v, got %v", want, messages)
+	}
+	if attrs := recordAttrs(records[1]); attrs["file"] != file || attrs["tests"] == "" {
+		t.Errorf("Unexpected parse attributes: %v", attrs)
+	}
+	rejected := records[2]
+	if rejected.Level != slog.LevelDebug {
+		t.Errorf("Expected rejection at debug, got %v", rejected.Level)
+	}
+	if attrs := recordAttrs(rejected); attrs["test"] != "needs_filter" || attrs["reason"] != "unsupported function filter" {
+		t.Errorf("Unexpected rejection attributes: %v", attrs)
+	}
+}
+
 func TestTestLoader_IsTestCompatible_Function(t *testing.T) {
 	cfg := createTestConfig()
 	loader := NewTestLoader("", cfg)
@@ -405,6 +555,68 @@ func TestTestLoader_IsTestCompatible_Behavior(t *testing.T) {
 	}
 }
 
+func TestTestLoader_ExplainCompatibility_UndecidedGroup(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.BehaviorChoices = []config.CCLBehavior{config.BehaviorBooleanLenient}
+	loader := NewTestLoader("", cfg)
+
+	test := types.TestCase{Validation: "parse", Behaviors: []string{"crlf_preserve_literal"}}
+	want := "requires behavior crlf_preserve_literal but the config chooses no crlf_handling behavior"
+	if got := loader.ExplainCompatibility(test); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	test.Behaviors = []string{"boolean_strict"}
+	if got := loader.ExplainCompatibility(test); got != "requires behavior boolean_strict" {
+		t.Errorf("Expected a plain reason for a decided group, got %q", got)
+	}
+	test.Behaviors = nil
+	if got := loader.ExplainCompatibility(test); got != "" {
+		t.Errorf("Expected a compatible test to have no reason, got %q", got)
+	}
+}
+
+func TestTestLoader_ExplainRejection_Kinds(t *testing.T) {
+	loader := NewTestLoader("", createTestConfig())
+	tests := []types.TestCase{
+		{Name: "requirement", Validation: "parse", Behaviors: []string{"boolean_strict"}},
+		{Name: "conflict", Validation: "parse", Conflicts: &types.ConflictSet{Behaviors: []string{"crlf_normalize_to_lf"}}},
+		{Name: "variant_conflict", Validation: "parse", Conflicts: &types.ConflictSet{Variants: []string{"proposed_behavior"}}},
+		{Name: "unsupported", Validation: "pretty_print"},
+		{Name: "compatible", Validation: "parse"},
+	}
+
+	want := []Rejection{
+		{RejectionRequirement, "boolean_strict", "requires behavior boolean_strict"},
+		{RejectionConflict, "crlf_normalize_to_lf", "conflicts with behavior crlf_normalize_to_lf"},
+		{RejectionConflict, "proposed_behavior", "conflicts with variant proposed_behavior"},
+		{RejectionUnsupported, "pretty_print", "unsupported function pretty_print"},
+	}
+	for i, test := range tests[:4] {
+		if got, rejected := loader.ExplainRejection(test); !rejected || got != want[i] {
+			t.Errorf("%s: expected %+v, got %+v", test.Name, want[i], got)
+		}
+	}
+	if got, rejected := loader.ExplainRejection(tests[4]); rejected || got != (Rejection{}) {
+		t.Errorf("Expected a compatible test not to be rejected, got %+v", got)
+	}
+
+	incompatible := loader.IncompatibleTests(tests)
+	if len(incompatible) != 4 || incompatible[0].Kind != RejectionRequirement || incompatible[1].Kind != RejectionConflict {
+		t.Errorf("Expected kinds on incompatible tests, got %+v", incompatible)
+	}
+
+	stats := loader.GetTestStatistics(tests)
+	wantSets := []types.ConflictSummary{
+		{ConflictType: "requirement", ConflictsWith: []string{"boolean_strict"}, TestCount: 1, AssertCount: 1},
+		{ConflictType: "conflict", ConflictsWith: []string{"crlf_normalize_to_lf"}, TestCount: 1, AssertCount: 1},
+		{ConflictType: "conflict", ConflictsWith: []string{"proposed_behavior"}, TestCount: 1, AssertCount: 1},
+	}
+	if stats.CompatibleTests != 1 || !reflect.DeepEqual(stats.ConflictingSets, wantSets) {
+		t.Errorf("Expected 1 compatible test and sets %+v, got %d and %+v", wantSets, stats.CompatibleTests, stats.ConflictingSets)
+	}
+}
+
 func TestTestLoader_IsTestCompatible_Variant(t *testing.T) {
 	cfg := createTestConfig()
 	loader := NewTestLoader("", cfg)
@@ -486,15 +698,52 @@ func TestTestLoader_GetTestStatistics(t *testing.T) {
 		t.Errorf("Expected 3 compatible tests, got %d", stats.CompatibleTests)
 	}
 
-	// Check function distribution (may have multiple due to Functions metadata)
-	if stats.ByFunction["parse"] < 1 {
-		t.Errorf("Expected at least 1 parse test, got %d", stats.ByFunction["parse"])
+	// ByValidation counts each test once; ByFunction also counts Functions metadata
+	wantByValidation := map[string]int{"parse": 1, "build_hierarchy": 1, "get_int": 1}
+	if !reflect.DeepEqual(stats.ByValidation, wantByValidation) {
+		t.Errorf("Expected ByValidation %v, got %v", wantByValidation, stats.ByValidation)
+	}
+	sum := 0
+	for _, count := range stats.ByValidation {
+		sum += count
+	}
+	if sum != stats.TotalTests {
+		t.Errorf("Expected ByValidation to sum to %d, got %d", stats.TotalTests, sum)
+	}
+	for fn, count := range stats.ByValidation {
+		if stats.ByFunction[fn] < count {
+			t.Errorf("Expected ByFunction[%s] to include its %d validations, got %d", fn, count, stats.ByFunction[fn])
+		}
+	}
+}
+
+func TestTestLoader_GetTestStatistics_LargestExpected(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	huge := strings.Repeat("x", 1<<20)
+	content := `{"tests": [
+		{"name": "small_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "b"}]}},
+		{"name": "huge_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "` + huge + `"}},
+		{"name": "error_parse", "inputs": ["a"], "validation": "parse", "expect_error": true}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "sizes.json"), []byte(content), 0644)
+
+	tl := NewTestLoader(dir, config.ImplementationConfig{})
+	var results [][]types.ExpectedSize
+	for _, lazy := range []bool{false, true} {
+		tests, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, LazyExpected: lazy})
+		if err != nil {
+			t.Fatalf("LoadAllTests failed: %v", err)
+		}
+		results = append(results, tl.GetTestStatistics(tests).LargestExpected)
 	}
-	if stats.ByFunction["build_hierarchy"] < 1 {
-		t.Errorf("Expected at least 1 build_hierarchy test, got %d", stats.ByFunction["build_hierarchy"])
+
+	want := []types.ExpectedSize{{Test: "huge_get_string", Bytes: 1<<20 + 22}, {Test: "small_parse", Bytes: 47}}
+	if !reflect.DeepEqual(results[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, results[0])
 	}
-	if stats.ByFunction["get_int"] < 1 {
-		t.Errorf("Expected at least 1 get_int test, got %d", stats.ByFunction["get_int"])
+	if !reflect.DeepEqual(results[1], results[0]) {
+		t.Errorf("Expected lazy loading to measure the same sizes, got %+v and %+v", results[0], results[1])
 	}
 }
 
@@ -621,6 +870,66 @@ func TestLoadOptions_FilterModes(t *testing.T) {
 	}
 }
 
+func TestNewTestLoaderWithOptions(t *testing.T) {
+	tmpDir := setupTestData(t)
+	cfg := createTestConfig()
+	cfg.SupportedFunctions = []config.CCLFunction{config.FunctionParse}
+	loader := NewTestLoaderWithOptions(tmpDir, cfg, LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+
+	// The zero options use the defaults
+	tests, err := loader.LoadAllTests(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	if len(tests) != 3 {
+		t.Errorf("Expected all 3 flat tests from the defaults, got %d", len(tests))
+	}
+	suite, err := loader.LoadTestFile(filepath.Join(tmpDir, "generated_tests", "test-basic.json"), LoadOptions{})
+	if err != nil || len(suite.Tests) != 3 {
+		t.Errorf("Expected LoadTestFile to decode with the defaults, got %v", err)
+	}
+	if _, ok, err := loader.GetTestByName("test_typed_access_get_int", LoadOptions{}); err != nil || !ok {
+		t.Errorf("Expected the default FilterAll to find an incompatible test, got %v, %v", ok, err)
+	}
+
+	// Explicit options replace the defaults entirely
+	tests, err = loader.LoadAllTests(LoadOptions{Format: FormatFlat})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Validation != "parse" {
+		t.Errorf("Expected explicit options to filter to the parse test, got %d tests", len(tests))
+	}
+
+	// Without defaults the zero options load source tests
+	plain, err := NewTestLoader(tmpDir, cfg).LoadAllTests(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	if len(plain) == 0 || plain[0].Validations == nil {
+		t.Errorf("Expected source tests without defaults, got %d tests", len(plain))
+	}
+}
+
+func TestTestLoader_WithOptions(t *testing.T) {
+	tmpDir := setupTestData(t)
+	base := NewTestLoader(tmpDir, createTestConfig())
+	derived := base.WithOptions(LoadOptions{Format: FormatFlat, FilterMode: FilterCustom, CustomFilter: func(test types.TestCase) bool {
+		return test.Validation == "get_int"
+	}})
+
+	tests, err := derived.LoadAllTests(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Name != "test_typed_access_get_int" {
+		t.Errorf("Expected the derived loader's custom filter, got %d tests", len(tests))
+	}
+	if !reflect.ValueOf(base.Defaults).IsZero() {
+		t.Error("Expected WithOptions to leave the original loader unchanged")
+	}
+}
+
 // Test error conditions
 
 func TestTestLoader_LoadTestFile_NonexistentFile(t *testing.T) {
@@ -655,8 +964,13 @@ func TestTestLoader_LoadTestFile_InvalidJSON(t *testing.T) {
 	}
 
 	_, err := loader.LoadTestFile(invalidFile, opts)
-	if err == nil {
-		t.Error("Expected error for invalid JSON")
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("Expected ErrParse for invalid JSON, got %v", err)
+	}
+
+	opts.Format = FormatCompact
+	if _, err := loader.LoadTestFile(invalidFile, opts); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected ErrParse for invalid compact JSON, got %v", err)
 	}
 }
 
@@ -670,13 +984,56 @@ func TestTestLoader_LoadAllTests_NoTestFiles(t *testing.T) {
 		FilterMode: FilterAll,
 	}
 
-	tests, err := loader.LoadAllTests(opts)
+	_, err := loader.LoadAllTests(opts)
+	if !errors.Is(err, ErrNoTests) || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected ErrNoTests for a missing directory, got %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "generated_tests"), 0755); err != nil {
+		t.Fatalf("Failed to create generated_tests: %v", err)
+	}
+	_, err = loader.LoadAllTests(opts)
+	if !errors.Is(err, ErrNoTests) || errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected ErrNoTests for an empty directory, got %v", err)
+	}
+}
+
+func TestTestLoader_LoadAllTests_Flatten(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceDir := filepath.Join(tmpDir, "source_tests")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatalf("Failed to create source_tests: %v", err)
+	}
+	os.WriteFile(filepath.Join(sourceDir, "api_a.json"), []byte(`{"tests": []}`), 0644)
+
+	tl := NewTestLoader(tmpDir, createTestConfig())
+	var flattened []string
+	tl.Flatten = func(sourceFile string) ([]byte, error) {
+		flattened = append(flattened, filepath.Base(sourceFile))
+		return []byte(`{"tests": [{"name": "a_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}}]}`), nil
+	}
+
+	tests, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
 	if err != nil {
-		t.Fatalf("LoadAllTests should not error when no files found: %v", err)
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Name != "a_parse" || !tests[0].Meta.Flattened {
+		t.Errorf("Expected one flattened test, got %+v", tests)
+	}
+	if len(flattened) != 1 || flattened[0] != "api_a.json" {
+		t.Errorf("Expected api_a.json to be flattened, got %v", flattened)
+	}
+
+	// Flatten errors are reported like load errors
+	tl.Flatten = func(string) ([]byte, error) { return nil, errors.New("boom") }
+	if _, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected the flatten error, got %v", err)
 	}
 
-	if len(tests) != 0 {
-		t.Errorf("Expected 0 tests when no files exist, got %d", len(tests))
+	// Without Flatten there is no fallback
+	tl.Flatten = nil
+	if _, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat}); !errors.Is(err, ErrNoTests) {
+		t.Errorf("Expected ErrNoTests without Flatten, got %v", err)
 	}
 }
 
@@ -685,7 +1042,7 @@ func TestTestLoader_LoadAllTests_NoTestFiles(t *testing.T) {
 func TestCompactTest_JSONMarshaling(t *testing.T) {
 	compact := CompactTest{
 		Name:     "test_compact",
-		Input:    "key = value",
+		Inputs:   []string{"key = value"},
 		Features: []string{"comments"},
 		Tests: []CompactValidation{
 			{
@@ -793,3 +1150,277 @@ func TestCapabilityCoverage_Structure(t *testing.T) {
 		t.Errorf("Expected 1 feature coverage, got %d", len(unmarshaled.Features))
 	}
 }
+
+// syntheticCorpus builds n flat tests spread over every function and feature
+func syntheticCorpus(n int) []types.TestCase {
+	functions := config.AllFunctions()
+	features := config.AllFeatures()
+	behaviors := []string{string(config.BehaviorCRLFNormalize), string(config.BehaviorCRLFPreserve)}
+	tests := make([]types.TestCase, n)
+	for i := range tests {
+		fn := string(functions[i%len(functions)])
+		tests[i] = types.TestCase{
+			Name:       fmt.Sprintf("test_%d_%s", i, fn),
+			Validation: fn,
+			Functions:  []string{fn, string(functions[(i/3)%len(functions)])},
+			Features:   []string{string(features[i%len(features)])},
+			Behaviors:  []string{behaviors[i%2]},
+		}
+		if i%7 == 0 {
+			tests[i].Conflicts = &types.ConflictSet{Behaviors: []string{behaviors[(i+1)%2]}}
+		}
+	}
+	return tests
+}
+
+// naiveCoverage is the per-capability filtering CoverageOf replaced, kept to
+// check equivalence and as the benchmark baseline
+func naiveCoverage(tl *TestLoader, allTests []types.TestCase) CapabilityCoverage {
+	coverage := CapabilityCoverage{
+		Functions: make(map[config.CCLFunction]CoverageInfo),
+		Features:  make(map[config.CCLFeature]CoverageInfo),
+	}
+	for _, fn := range tl.Config.SupportedFunctions {
+		var matching []types.TestCase
+		for _, test := range allTests {
+			if test.Validation == string(fn) || slices.Contains(test.Functions, string(fn)) {
+				matching = append(matching, test)
+			}
+		}
+		coverage.Functions[fn] = CoverageInfo{Available: len(matching), Compatible: len(tl.FilterCompatibleTests(matching))}
+	}
+	for _, feature := range tl.Config.SupportedFeatures {
+		var matching []types.TestCase
+		for _, test := range allTests {
+			if slices.Contains(test.Features, string(feature)) {
+				matching = append(matching, test)
+			}
+		}
+		coverage.Features[feature] = CoverageInfo{Available: len(matching), Compatible: len(tl.FilterCompatibleTests(matching))}
+	}
+	return coverage
+}
+
+func fullConfig() config.ImplementationConfig {
+	return config.ImplementationConfig{
+		Name:               "full",
+		SupportedFunctions: config.AllFunctions(),
+		SupportedFeatures:  config.AllFeatures(),
+		BehaviorChoices:    []config.CCLBehavior{config.BehaviorCRLFNormalize},
+	}
+}
+
+func TestTestLoader_CoverageOf_MatchesPerCapabilityFiltering(t *testing.T) {
+	tests := syntheticCorpus(500)
+	for _, cfg := range []config.ImplementationConfig{fullConfig(), createTestConfig()} {
+		tl := NewTestLoader("", cfg)
+		if got, want := tl.CoverageOf(tests), naiveCoverage(tl, tests); !reflect.DeepEqual(got, want) {
+			t.Errorf("Config %s: coverage mismatch.\nGot:  %+v\nWant: %+v", cfg.Name, got, want)
+		}
+	}
+}
+
+func BenchmarkCoverage(b *testing.B) {
+	tests := syntheticCorpus(10000)
+	tl := NewTestLoader("", fullConfig())
+
+	b.Run("precomputed", func(b *testing.B) {
+		for b.Loop() {
+			tl.CoverageOf(tests)
+		}
+	})
+	b.Run("per-capability", func(b *testing.B) {
+		for b.Loop() {
+			naiveCoverage(tl, tests)
+		}
+	})
+}
+
+// writeSyntheticFlatDir writes n flat tests with realistic expected entries
+// to <root>/generated_tests and returns root
+func writeSyntheticFlatDir(tb testing.TB, n int) string {
+	tb.Helper()
+	root := tb.TempDir()
+	dir := filepath.Join(root, "generated_tests")
+	os.MkdirAll(dir, 0755)
+
+	var tests []map[string]interface{}
+	for i := 0; i < n; i++ {
+		entries := make([]map[string]interface{}, 8)
+		for j := range entries {
+			entries[j] = map[string]interface{}{"key": fmt.Sprintf("key_%d", j), "value": fmt.Sprintf("value %d of test %d", j, i)}
+		}
+		tests = append(tests, map[string]interface{}{
+			"name":       fmt.Sprintf("test_%d_parse", i),
+			"inputs":     []string{"key = value"},
+			"validation": "parse",
+			"expected":   map[string]interface{}{"count": len(entries), "entries": entries},
+			"features":   []string{"comments"},
+			"behaviors":  []string{},
+			"variants":   []string{},
+		})
+	}
+	data, _ := json.Marshal(map[string]interface{}{"tests": tests})
+	if err := os.WriteFile(filepath.Join(dir, "synthetic.json"), data, 0644); err != nil {
+		tb.Fatalf("Failed to write synthetic tests: %v", err)
+	}
+	return root
+}
+
+func TestTestLoader_LazyExpected(t *testing.T) {
+	root := writeSyntheticFlatDir(t, 20)
+	tl := NewTestLoader(root, createTestConfig())
+
+	eager, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("Eager load failed: %v", err)
+	}
+	lazy, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, LazyExpected: true})
+	if err != nil {
+		t.Fatalf("Lazy load failed: %v", err)
+	}
+
+	if len(lazy) != len(eager) {
+		t.Fatalf("Expected %d tests, got %d", len(eager), len(lazy))
+	}
+	for i := range lazy {
+		if lazy[i].Expected != nil || len(lazy[i].RawExpected) == 0 {
+			t.Fatalf("Expected %s to hold only raw JSON", lazy[i].Name)
+		}
+		lazy[i].ExpectedValue()
+	}
+	if !reflect.DeepEqual(lazy, eager) {
+		t.Error("Expected lazily decoded tests to equal eagerly decoded ones")
+	}
+	if tl.GetTestStatistics(lazy).TotalTests != 20 {
+		t.Error("Expected statistics over lazily loaded tests")
+	}
+}
+
+func TestTestLoader_EntryProvenance(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "parse_later", "inputs": ["a = 1\nb = 2"], "validation": "parse", "expected": {"count": 2, "entries": [{"key": "a", "value": "1", "src_index": 1}, {"key": "b", "value": "2", "src_index": 2}]}}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(flat), 0644)
+	tl := NewTestLoader(dir, createTestConfig())
+	wantEntries := []interface{}{map[string]interface{}{"key": "a", "value": "1"}, map[string]interface{}{"key": "b", "value": "2"}}
+
+	for _, lazy := range []bool{false, true} {
+		for _, provenance := range []bool{false, true} {
+			tests, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, LazyExpected: lazy, EntryProvenance: provenance})
+			if err != nil {
+				t.Fatalf("lazy=%v provenance=%v: LoadAllTests failed: %v", lazy, provenance, err)
+			}
+			if got := tests[0].ExpectedValue(); !reflect.DeepEqual(got, wantEntries) {
+				t.Errorf("lazy=%v provenance=%v: expected src_index stripped, got %v", lazy, provenance, got)
+			}
+			var wantSources []int
+			if provenance {
+				wantSources = []int{1, 2}
+			}
+			if !reflect.DeepEqual(tests[0].Meta.EntrySources, wantSources) {
+				t.Errorf("lazy=%v provenance=%v: expected entry sources %v, got %v", lazy, provenance, wantSources, tests[0].Meta.EntrySources)
+			}
+		}
+	}
+}
+
+func BenchmarkLoadForStatistics(b *testing.B) {
+	root := writeSyntheticFlatDir(b, 2000)
+	tl := NewTestLoader(root, createTestConfig())
+
+	for _, lazy := range []bool{false, true} {
+		b.Run(fmt.Sprintf("lazy=%v", lazy), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				tests, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, LazyExpected: lazy})
+				if err != nil {
+					b.Fatal(err)
+				}
+				tl.GetTestStatistics(tests)
+			}
+		})
+	}
+}
+
+func TestTestLoader_LoadAllTests_Tags(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "parse_basic", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "meta": {"tags": ["level:1"]}},
+		{"name": "parse_slow", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "meta": {"tags": ["level:1", "slow"]}},
+		{"name": "filter_basic", "inputs": ["a = 1"], "validation": "filter", "expected": {"count": 1}, "meta": {"tags": ["level:1"]}},
+		{"name": "get_deep", "inputs": ["a = 1"], "validation": "get_int", "args": ["a"], "expected": {"count": 1, "value": 1}, "meta": {"tags": ["level:3"]}}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(flat), 0644)
+	loader := NewTestLoader(dir, createTestConfig())
+
+	names := func(opts LoadOptions) []string {
+		tests, err := loader.LoadAllTests(opts)
+		if err != nil {
+			t.Fatalf("LoadAllTests failed: %v", err)
+		}
+		var names []string
+		for _, test := range tests {
+			names = append(names, test.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name string
+		opts LoadOptions
+		want []string
+	}{
+		{"compatible then include", LoadOptions{Format: FormatFlat, FilterMode: FilterCompatible, IncludeTags: []string{"level:1"}},
+			[]string{"parse_basic", "parse_slow"}},
+		{"compatible then include and exclude", LoadOptions{Format: FormatFlat, FilterMode: FilterCompatible, IncludeTags: []string{"level:1"}, ExcludeTags: []string{"slow"}},
+			[]string{"parse_basic"}},
+		{"custom then exclude", LoadOptions{Format: FormatFlat, FilterMode: FilterCustom, ExcludeTags: []string{"slow"},
+			CustomFilter: func(test types.TestCase) bool { return test.Validation != "get_int" }},
+			[]string{"parse_basic", "filter_basic"}},
+		{"all with include", LoadOptions{Format: FormatFlat, FilterMode: FilterAll, IncludeTags: []string{"level:3"}},
+			[]string{"get_deep"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTestLoader_LoadAllTests_SkipSlow(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "parse_basic", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "parse_blowup", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "limits": {"timeout_ms": 5000, "slow": true}},
+		{"name": "parse_bounded", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "limits": {"timeout_ms": 2000}}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(flat), 0644)
+	loader := NewTestLoader(dir, createTestConfig())
+
+	all, err := loader.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	if len(all) != 3 || !reflect.DeepEqual(all[1].Meta.Limits, &types.Limits{TimeoutMS: 5000, Slow: true}) {
+		t.Fatalf("Expected the limits carried into Meta, got %+v", all)
+	}
+
+	quick, err := loader.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, SkipSlow: true})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	var names []string
+	for _, test := range quick {
+		names = append(names, test.Name)
+	}
+	if want := []string{"parse_basic", "parse_bounded"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected SkipSlow to leave out only the slow test, got %v", names)
+	}
+}
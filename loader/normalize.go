@@ -0,0 +1,44 @@
+package loader
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// InputNormalization selects how line endings in test inputs are normalized
+// after loading, e.g. to undo git autocrlf on Windows checkouts
+type InputNormalization int
+
+const (
+	NormalizeNone             InputNormalization = iota // Inputs are used as stored
+	NormalizeLFOnly                                     // CRLF becomes LF in every test
+	NormalizePreserveDeclared                           // CRLF becomes LF except in tests whose behaviors pin CRLF handling
+)
+
+// NormalizeInputs rewrites CRLF line endings in test inputs according to
+// mode, setting Meta.InputNormalized on each test whose inputs changed
+func NormalizeInputs(tests []types.TestCase, mode InputNormalization) {
+	if mode == NormalizeNone {
+		return
+	}
+	for i := range tests {
+		if mode == NormalizePreserveDeclared && pinsCRLF(tests[i]) {
+			continue
+		}
+		for j, input := range tests[i].Inputs {
+			if strings.Contains(input, "\r\n") {
+				tests[i].Inputs[j] = strings.ReplaceAll(input, "\r\n", "\n")
+				tests[i].Meta.InputNormalized = true
+			}
+		}
+	}
+}
+
+// pinsCRLF reports whether a test requires a particular CRLF behavior
+func pinsCRLF(test types.TestCase) bool {
+	return slices.Contains(test.Behaviors, string(config.BehaviorCRLFNormalize)) ||
+		slices.Contains(test.Behaviors, string(config.BehaviorCRLFPreserve))
+}
@@ -0,0 +1,60 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func crlfTests() []types.TestCase {
+	return []types.TestCase{
+		{Name: "plain", Inputs: []string{"a = 1\r\nb = 2\r\n", "c = 3\r\n"}},
+		{Name: "pinned", Inputs: []string{"a = 1\r\n"}, Behaviors: []string{string(config.BehaviorCRLFPreserve)}},
+		{Name: "lf", Inputs: []string{"a = 1\n"}},
+	}
+}
+
+func TestNormalizeInputs(t *testing.T) {
+	tests := []struct {
+		mode       InputNormalization
+		inputs     [][]string
+		normalized []bool
+	}{
+		{NormalizeNone, [][]string{{"a = 1\r\nb = 2\r\n", "c = 3\r\n"}, {"a = 1\r\n"}, {"a = 1\n"}}, []bool{false, false, false}},
+		{NormalizeLFOnly, [][]string{{"a = 1\nb = 2\n", "c = 3\n"}, {"a = 1\n"}, {"a = 1\n"}}, []bool{true, true, false}},
+		{NormalizePreserveDeclared, [][]string{{"a = 1\nb = 2\n", "c = 3\n"}, {"a = 1\r\n"}, {"a = 1\n"}}, []bool{true, false, false}},
+	}
+	for _, tt := range tests {
+		cases := crlfTests()
+		NormalizeInputs(cases, tt.mode)
+		for i, test := range cases {
+			if !slices.Equal(test.Inputs, tt.inputs[i]) {
+				t.Errorf("mode %d, %s: expected inputs %q, got %q", tt.mode, test.Name, tt.inputs[i], test.Inputs)
+			}
+			if test.Meta.InputNormalized != tt.normalized[i] {
+				t.Errorf("mode %d, %s: expected InputNormalized %v", tt.mode, test.Name, tt.normalized[i])
+			}
+		}
+	}
+}
+
+func TestTestLoader_LoadTestFile_NormalizeInput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "crlf.json")
+	content := `{"tests": [{"name": "crlf", "inputs": ["a = 1\r\nb = 2"], "validation": "parse", "expected": {"count": 2, "entries": []}}]}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	loader := NewTestLoader("", config.ImplementationConfig{})
+	suite, err := loader.LoadTestFile(file, LoadOptions{Format: FormatFlat, NormalizeInput: NormalizeLFOnly})
+	if err != nil {
+		t.Fatalf("LoadTestFile failed: %v", err)
+	}
+	if test := suite.Tests[0]; test.Inputs[0] != "a = 1\nb = 2" || !test.Meta.InputNormalized {
+		t.Errorf("Expected normalized input, got %q (normalized %v)", test.Inputs[0], test.Meta.InputNormalized)
+	}
+}
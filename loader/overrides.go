@@ -0,0 +1,81 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Override replaces the expected outcome of one test with an
+// implementation's documented choice
+type Override struct {
+	Expected    interface{} `json:"expected,omitempty"`     // Plain or structured expected value
+	ExpectError *bool       `json:"expect_error,omitempty"` // Replaces ExpectError when set
+	Reason      string      `json:"reason"`                 // Required; recorded in Meta.Override
+}
+
+// LoadOverrides reads an overrides file mapping test names to overrides
+func LoadOverrides(path string) (map[string]Override, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides: %w", err)
+	}
+	var overrides map[string]Override
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse overrides %s: %w", ErrParse, path, err)
+	}
+	for name, override := range overrides {
+		if override.Reason == "" {
+			return nil, fmt.Errorf("override for %s has no reason", name)
+		}
+		if override.Expected == nil && override.ExpectError == nil {
+			return nil, fmt.Errorf("override for %s sets neither expected nor expect_error", name)
+		}
+	}
+	return overrides, nil
+}
+
+// ApplyOverrides replaces Expected and ExpectError of the named tests in
+// place and records each override's reason in Meta.Override. It returns an
+// error listing any overrides that name no test.
+func ApplyOverrides(tests []types.TestCase, overrides map[string]Override) error {
+	applied := applyOverrides(tests, overrides)
+	var unknown []string
+	for name := range overrides {
+		if !applied[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("overrides for unknown tests: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// applyOverrides applies the overrides that match tests and returns the
+// names it applied
+func applyOverrides(tests []types.TestCase, overrides map[string]Override) map[string]bool {
+	applied := make(map[string]bool)
+	for i := range tests {
+		override, ok := overrides[tests[i].Name]
+		if !ok {
+			continue
+		}
+		if override.Expected != nil {
+			tests[i].Expected = types.ExtractExpected(tests[i].Validation, override.Expected)
+			tests[i].RawExpected = nil
+			tests[i].Meta.ExpectedBytes = 0
+		}
+		if override.ExpectError != nil {
+			tests[i].ExpectError = *override.ExpectError
+		}
+		tests[i].Meta.Override = override.Reason
+		applied[tests[i].Name] = true
+	}
+	return applied
+}
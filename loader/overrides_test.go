@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func writeOverrides(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write overrides: %v", err)
+	}
+	return path
+}
+
+func TestApplyOverrides(t *testing.T) {
+	overrides, err := LoadOverrides(writeOverrides(t, `{
+		"structured": {"expected": {"count": 1, "value": "x"}, "reason": "documented"},
+		"errors": {"expect_error": true, "reason": "rejects tabs"}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadOverrides failed: %v", err)
+	}
+	tests := []types.TestCase{
+		{Name: "structured", Validation: "get_string", Expected: "y"},
+		{Name: "errors", Validation: "parse", Expected: []interface{}{}},
+		{Name: "untouched", Validation: "parse", Expected: "z"},
+	}
+	if err := ApplyOverrides(tests, overrides); err != nil {
+		t.Fatalf("ApplyOverrides failed: %v", err)
+	}
+
+	if tests[0].Expected != "x" || tests[0].ExpectError || tests[0].Meta.Override != "documented" {
+		t.Errorf("Unexpected structured override: %+v", tests[0])
+	}
+	if !tests[1].ExpectError || tests[1].Meta.Override != "rejects tabs" {
+		t.Errorf("Unexpected expect_error override: %+v", tests[1])
+	}
+	if tests[2].Expected != "z" || tests[2].Meta.Override != "" {
+		t.Errorf("Expected untouched test, got %+v", tests[2])
+	}
+}
+
+func TestApplyOverrides_UnknownTests(t *testing.T) {
+	overrides := map[string]Override{
+		"b_missing": {Expected: "x", Reason: "r"},
+		"a_missing": {Expected: "x", Reason: "r"},
+		"present":   {Expected: "x", Reason: "r"},
+	}
+	err := ApplyOverrides([]types.TestCase{{Name: "present"}}, overrides)
+	if err == nil || err.Error() != "overrides for unknown tests: a_missing, b_missing" {
+		t.Errorf("Expected unknown tests listed, got %v", err)
+	}
+
+	tmpDir := setupTestData(t)
+	_, err = NewTestLoader(tmpDir, createTestConfig()).LoadAllTests(LoadOptions{
+		Format:        FormatFlat,
+		OverridesPath: writeOverrides(t, `{"nope": {"expected": 1, "reason": "r"}}`),
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown tests: nope") {
+		t.Errorf("Expected LoadAllTests to reject unknown overrides, got %v", err)
+	}
+}
+
+func TestLoadOverrides_Invalid(t *testing.T) {
+	for content, want := range map[string]string{
+		`{"a": {"expected": 1}}`: "override for a has no reason",
+		`{"a": {"reason": "r"}}`: "override for a sets neither expected nor expect_error",
+		`{"a": [`:                "invalid test file",
+	} {
+		if _, err := LoadOverrides(writeOverrides(t, content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q, got %v", content, want, err)
+		}
+	}
+}
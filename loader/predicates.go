@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// InputPredicate reports whether one test input has some characteristic.
+// Any func of this type can be used in LoadOptions.InputPredicates
+// alongside the built-ins below.
+type InputPredicate func(input string) bool
+
+// ContainsCRLF matches inputs with a CRLF line ending
+func ContainsCRLF() InputPredicate {
+	return func(input string) bool { return strings.Contains(input, "\r\n") }
+}
+
+// ContainsTabs matches inputs with a tab character
+func ContainsTabs() InputPredicate {
+	return func(input string) bool { return strings.Contains(input, "\t") }
+}
+
+// MinLength matches inputs of at least n bytes
+func MinLength(n int) InputPredicate {
+	return func(input string) bool { return len(input) >= n }
+}
+
+// MaxLength matches inputs of at most n bytes
+func MaxLength(n int) InputPredicate {
+	return func(input string) bool { return len(input) <= n }
+}
+
+// NonASCII matches inputs with a byte outside the ASCII range
+func NonASCII() InputPredicate {
+	return func(input string) bool {
+		for i := 0; i < len(input); i++ {
+			if input[i] >= 0x80 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchesInputs reports whether at least one of a test's inputs satisfies
+// every predicate. With no predicates every test matches; otherwise tests
+// without inputs never do.
+func matchesInputs(test types.TestCase, predicates []InputPredicate) bool {
+	if len(predicates) == 0 {
+		return true
+	}
+	for _, input := range test.Inputs {
+		if matchesAll(input, predicates) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(input string, predicates []InputPredicate) bool {
+	for _, predicate := range predicates {
+		if !predicate(input) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestInputPredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate InputPredicate
+		matches   []string
+		rejects   []string
+	}{
+		{"ContainsCRLF", ContainsCRLF(), []string{"a = 1\r\n", "a = 1\r\nb = 2"}, []string{"a = 1\n", "a = 1\r", "a\n\r"}},
+		{"ContainsTabs", ContainsTabs(), []string{"a =\t1", "\tb = 2"}, []string{"a = 1", "a = \\t"}},
+		{"MinLength", MinLength(5), []string{"a = 1", "a = 12"}, []string{"a = ", ""}},
+		{"MaxLength", MaxLength(5), []string{"a = 1", ""}, []string{"a = 12"}},
+		{"NonASCII", NonASCII(), []string{"caf\u00e9 = 1", "a = \u2603", "a = \xff"}, []string{"a = 1", "~\x7f"}},
+	}
+	for _, tt := range tests {
+		for _, input := range tt.matches {
+			if !tt.predicate(input) {
+				t.Errorf("%s: expected %q to match", tt.name, input)
+			}
+		}
+		for _, input := range tt.rejects {
+			if tt.predicate(input) {
+				t.Errorf("%s: expected %q not to match", tt.name, input)
+			}
+		}
+	}
+}
+
+func TestLoadOptions_InputPredicates(t *testing.T) {
+	tl := NewTestLoader("", config.ImplementationConfig{})
+	all := []types.TestCase{
+		{Name: "crlf", Inputs: []string{"a = 1\r\n"}},
+		{Name: "crlf_and_tab", Inputs: []string{"a = 1\r\n", "b =\t2"}},
+		{Name: "crlf_with_tab", Inputs: []string{"a =\t1\r\n"}},
+		{Name: "long", Inputs: []string{strings.Repeat("x", 100)}},
+		{Name: "no_inputs"},
+	}
+	custom := func(input string) bool { return strings.HasPrefix(input, "b") }
+
+	tests := []struct {
+		predicates []InputPredicate
+		want       []string
+	}{
+		{nil, []string{"crlf", "crlf_and_tab", "crlf_with_tab", "long", "no_inputs"}},
+		{[]InputPredicate{ContainsCRLF()}, []string{"crlf", "crlf_and_tab", "crlf_with_tab"}},
+		// Every predicate must hold for the same input
+		{[]InputPredicate{ContainsCRLF(), ContainsTabs()}, []string{"crlf_with_tab"}},
+		{[]InputPredicate{MinLength(50), MaxLength(100)}, []string{"long"}},
+		{[]InputPredicate{custom}, []string{"crlf_and_tab"}},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, test := range tl.applyFiltering(all, LoadOptions{FilterMode: FilterAll, InputPredicates: tt.predicates}) {
+			names = append(names, test.Name)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("%d predicates: expected %v, got %v", len(tt.predicates), tt.want, names)
+		}
+	}
+}
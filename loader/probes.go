@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// MissingProbesError lists the capabilities SelectProbeTests found no
+// isolated test for
+type MissingProbesError struct {
+	Capabilities []string // e.g. "feature:unicode"; functions and features in config order, then behaviors by name
+}
+
+func (e *MissingProbesError) Error() string {
+	return fmt.Sprintf("no isolated probe test for %s", strings.Join(e.Capabilities, ", "))
+}
+
+// SelectProbeTests picks one probe test per capability, keyed as
+// "function:parse", "feature:comments" or "behavior:boolean_strict". A
+// function's probe has that validation and no features, behaviors or
+// variants; a feature's or behavior's probe requires that one and nothing
+// else. Of the isolated tests the one with the smallest input wins, then the
+// first by name. Skipped tests and tests without inputs are never chosen.
+// Capabilities with no isolated test are reported in a MissingProbesError,
+// returned alongside the probes that were found.
+func SelectProbeTests(tests []types.TestCase) (map[string]types.TestCase, error) {
+	probes := make(map[string]types.TestCase)
+	consider := func(key string, test types.TestCase) {
+		current, ok := probes[key]
+		if !ok || probeLess(test, current) {
+			probes[key] = test
+		}
+	}
+
+	for _, test := range tests {
+		if test.Skip != nil || test.Validation == "" || len(test.Inputs) == 0 || len(test.Variants) > 0 {
+			continue
+		}
+		switch {
+		case len(test.Features) == 0 && len(test.Behaviors) == 0:
+			consider("function:"+test.Validation, test)
+		case len(test.Features) == 1 && len(test.Behaviors) == 0:
+			consider("feature:"+test.Features[0], test)
+		case len(test.Features) == 0 && len(test.Behaviors) == 1:
+			consider("behavior:"+test.Behaviors[0], test)
+		}
+	}
+
+	var missing []string
+	for _, key := range probeCapabilities() {
+		if _, ok := probes[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return probes, &MissingProbesError{Capabilities: missing}
+	}
+	return probes, nil
+}
+
+// probeCapabilities returns the key of every capability that needs a probe
+func probeCapabilities() []string {
+	var keys []string
+	for _, fn := range config.AllFunctions() {
+		keys = append(keys, "function:"+string(fn))
+	}
+	for _, feature := range config.AllFeatures() {
+		keys = append(keys, "feature:"+string(feature))
+	}
+	var behaviors []string
+	for _, group := range config.GetBehaviorConflicts() {
+		for _, behavior := range group {
+			behaviors = append(behaviors, "behavior:"+string(behavior))
+		}
+	}
+	slices.Sort(behaviors) // Map order would make the error message vary
+	return append(keys, behaviors...)
+}
+
+// probeLess orders candidate probes by total input size, then name
+func probeLess(a, b types.TestCase) bool {
+	sizeA, sizeB := 0, 0
+	for _, input := range a.Inputs {
+		sizeA += len(input)
+	}
+	for _, input := range b.Inputs {
+		sizeB += len(input)
+	}
+	if sizeA != sizeB {
+		return sizeA < sizeB
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,96 @@
+package loader
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// probeCorpus has an isolated test for every capability except the
+// multiline feature and the indent_tabs behavior, which only appear
+// combined with something else
+func probeCorpus() []types.TestCase {
+	var tests []types.TestCase
+	for _, fn := range config.AllFunctions() {
+		tests = append(tests, types.TestCase{Name: string(fn) + "_basic", Inputs: []string{"a = b"}, Validation: string(fn)})
+	}
+	for _, feature := range config.AllFeatures() {
+		if feature != config.FeatureMultiline {
+			tests = append(tests, types.TestCase{Name: string(feature) + "_basic", Inputs: []string{"a = b"}, Validation: "parse", Features: []string{string(feature)}})
+		}
+	}
+	for _, group := range config.GetBehaviorConflicts() {
+		for _, behavior := range group {
+			if behavior != config.BehaviorIndentTabs {
+				tests = append(tests, types.TestCase{Name: string(behavior) + "_basic", Inputs: []string{"a = b"}, Validation: "parse", Behaviors: []string{string(behavior)}})
+			}
+		}
+	}
+	return append(tests,
+		// Smaller but not isolated, or not runnable
+		types.TestCase{Name: "multiline_unicode", Inputs: []string{"a"}, Validation: "parse", Features: []string{"multiline", "unicode"}},
+		types.TestCase{Name: "indent_tabs_comments", Inputs: []string{"a"}, Validation: "pretty_print", Features: []string{"comments"}, Behaviors: []string{"indent_tabs"}},
+		types.TestCase{Name: "parse_variant", Inputs: []string{"a"}, Validation: "parse", Variants: []string{"proposed_behavior"}},
+		types.TestCase{Name: "parse_skipped", Inputs: []string{"a"}, Validation: "parse", Skip: &types.SkipInfo{Reason: "flaky"}},
+		// Smaller and isolated: these win, the tie going to the first name
+		types.TestCase{Name: "parse_tiny", Inputs: []string{"a=b"}, Validation: "parse"},
+		types.TestCase{Name: "comments_tiny_b", Inputs: []string{"/="}, Validation: "filter", Features: []string{"comments"}},
+		types.TestCase{Name: "comments_tiny_a", Inputs: []string{"/="}, Validation: "filter", Features: []string{"comments"}},
+	)
+}
+
+func TestSelectProbeTests(t *testing.T) {
+	probes, err := SelectProbeTests(probeCorpus())
+
+	var missing *MissingProbesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Expected a MissingProbesError, got %v", err)
+	}
+	if want := []string{"feature:multiline", "behavior:indent_tabs"}; !reflect.DeepEqual(missing.Capabilities, want) {
+		t.Errorf("Expected missing %v, got %v", want, missing.Capabilities)
+	}
+
+	wantNames := map[string]string{
+		"function:parse":           "parse_tiny",
+		"function:get_string":      "get_string_basic",
+		"feature:comments":         "comments_tiny_a",
+		"feature:unicode":          "unicode_basic",
+		"behavior:boolean_strict":  "boolean_strict_basic",
+		"behavior:tabs_as_content": "tabs_as_content_basic",
+	}
+	for key, name := range wantNames {
+		if probes[key].Name != name {
+			t.Errorf("Expected %s probe %s, got %q", key, name, probes[key].Name)
+		}
+	}
+	if len(probes) != len(probeCapabilities())-2 {
+		t.Errorf("Expected a probe for every other capability, got %d", len(probes))
+	}
+
+	// Input order does not change the selection
+	reversed := probeCorpus()
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	again, _ := SelectProbeTests(reversed)
+	if !reflect.DeepEqual(again, probes) {
+		t.Error("Expected the same probes regardless of test order")
+	}
+}
+
+func TestSelectProbeTests_Complete(t *testing.T) {
+	tests := append(probeCorpus(),
+		types.TestCase{Name: "multiline_basic", Inputs: []string{"a =\n  b"}, Validation: "parse", Features: []string{"multiline"}},
+		types.TestCase{Name: "indent_tabs_basic", Inputs: []string{"a =\n  b"}, Validation: "pretty_print", Behaviors: []string{"indent_tabs"}},
+	)
+	probes, err := SelectProbeTests(tests)
+	if err != nil {
+		t.Fatalf("Expected every capability covered, got %v", err)
+	}
+	if len(probes) != len(probeCapabilities()) {
+		t.Errorf("Expected %d probes, got %d", len(probeCapabilities()), len(probes))
+	}
+}
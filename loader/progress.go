@@ -0,0 +1,21 @@
+package loader
+
+// Progress stages reported by the loader and generator
+const (
+	StageLoad     = "load"     // LoadAllTests, once per test file
+	StageGenerate = "generate" // FlatGenerator.GenerateAll, once per source file
+)
+
+// ProgressFunc receives progress for a long-running operation. It is called
+// once with done 0 after the files are scanned, then after each file with
+// done increasing by one until it equals total. Calls come from the
+// goroutine running the operation, never concurrently, so a ProgressFunc
+// needs no locking of its own.
+type ProgressFunc func(stage string, done, total int)
+
+// Report calls fn, or does nothing if fn is nil
+func (fn ProgressFunc) Report(stage string, done, total int) {
+	if fn != nil {
+		fn(stage, done, total)
+	}
+}
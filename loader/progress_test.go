@@ -0,0 +1,48 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// progressCall is one recorded ProgressFunc invocation
+type progressCall struct {
+	stage       string
+	done, total int
+}
+
+func TestLoadOptions_Progress(t *testing.T) {
+	tmpDir := setupTestData(t)
+	files, err := os.ReadDir(filepath.Join(tmpDir, "generated_tests"))
+	if err != nil {
+		t.Fatalf("Failed to list test files: %v", err)
+	}
+	total := len(files)
+
+	var calls []progressCall
+	opts := LoadOptions{
+		Format:     FormatFlat,
+		FilterMode: FilterAll,
+		Progress: func(stage string, done, total int) {
+			calls = append(calls, progressCall{stage, done, total})
+		},
+	}
+	if _, err := NewTestLoader(tmpDir, createTestConfig()).LoadAllTests(opts); err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+
+	if len(calls) != total+1 {
+		t.Fatalf("Expected %d calls for %d files, got %v", total+1, total, calls)
+	}
+	for i, call := range calls {
+		if call != (progressCall{StageLoad, i, total}) {
+			t.Errorf("Call %d: expected done %d of %d, got %+v", i, i, total, call)
+		}
+	}
+}
+
+func TestProgressFunc_ReportNil(t *testing.T) {
+	var fn ProgressFunc
+	fn.Report(StageLoad, 1, 2) // Must not panic
+}
@@ -0,0 +1,128 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// AmbiguousTestError reports a test name defined more than once
+type AmbiguousTestError struct {
+	Name       string
+	Candidates []string // File of each test with the name, in load order
+}
+
+func (e *AmbiguousTestError) Error() string {
+	return fmt.Sprintf("test %q is ambiguous: found in %s (set Dedup to use the first)", e.Name, strings.Join(e.Candidates, ", "))
+}
+
+// plainName matches names that appear verbatim in any JSON encoding, so a
+// file without the raw bytes cannot contain the test
+var plainName = regexp.MustCompile(`^[A-Za-z0-9_.\- ]+$`)
+
+// GetTestByName loads the test with the given name, applying opts' filtering.
+// Files that cannot contain the name are skipped without being decoded. If
+// several tests share the name an AmbiguousTestError is returned unless
+// opts.Dedup is set, in which case the first in file name order wins.
+func (tl *TestLoader) GetTestByName(name string, opts LoadOptions) (types.TestCase, bool, error) {
+	found, err := tl.GetTestsByNames([]string{name}, opts)
+	if err != nil {
+		return types.TestCase{}, false, err
+	}
+	test, ok := found[name]
+	return test, ok, nil
+}
+
+// GetTestsByNames loads the tests with the given names, keyed by name, as
+// GetTestByName does. Names with no matching test are absent from the result.
+// Overrides from opts.OverridesPath apply to the tests found; overrides naming
+// other tests are not reported here as they are by LoadAllTests.
+func (tl *TestLoader) GetTestsByNames(names []string, opts LoadOptions) (map[string]types.TestCase, error) {
+	opts = tl.options(opts)
+	if err := CheckShard(opts.ShardIndex, opts.ShardCount); err != nil {
+		return nil, err
+	}
+	files, err := tl.testFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var overrides map[string]Override
+	if opts.OverridesPath != "" {
+		if overrides, err = LoadOverrides(opts.OverridesPath); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := make(map[string][]types.TestCase)
+	candidates := make(map[string][]string)
+	for _, file := range files {
+		data, err := tl.readTestFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", file, err)
+		}
+		if !mayContainAny(data, names) {
+			continue
+		}
+		suite, err := tl.decodeTestFile(file, data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", file, err)
+		}
+		applyOverrides(suite.Tests, overrides)
+		for _, test := range tl.applyFiltering(suite.Tests, opts) {
+			if wanted[test.Name] {
+				matches[test.Name] = append(matches[test.Name], test)
+				candidates[test.Name] = append(candidates[test.Name], filepath.Base(file))
+			}
+		}
+	}
+
+	found := make(map[string]types.TestCase, len(matches))
+	for _, name := range names {
+		tests := matches[name]
+		if len(tests) == 0 {
+			continue
+		}
+		if len(tests) > 1 && !opts.Dedup {
+			return nil, &AmbiguousTestError{Name: name, Candidates: candidates[name]}
+		}
+		found[name] = tests[0]
+	}
+	return found, nil
+}
+
+// Index loads the tests opts selects and keys them by TestCase.ID, so
+// results recorded before a test was renamed can still find it. Of several
+// tests with the same content, the first loaded is kept.
+func (tl *TestLoader) Index(opts LoadOptions) (map[string]types.TestCase, error) {
+	tests, err := tl.LoadAllTests(opts)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]types.TestCase, len(tests))
+	for _, test := range tests {
+		id := test.ID()
+		if _, ok := index[id]; !ok {
+			index[id] = test
+		}
+	}
+	return index, nil
+}
+
+// mayContainAny reports whether data might hold a test with one of names
+func mayContainAny(data []byte, names []string) bool {
+	for _, name := range names {
+		if !plainName.MatchString(name) || bytes.Contains(data, []byte(name)) {
+			return true
+		}
+	}
+	return false
+}
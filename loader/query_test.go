@@ -0,0 +1,120 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// setupQueryTestData writes two flat files sharing the test name "shared"
+func setupQueryTestData(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	generated := filepath.Join(dir, "generated_tests")
+	os.MkdirAll(generated, 0755)
+	files := map[string]string{
+		"a.json": `{"tests": [
+			{"name": "only_a", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "1"}]}},
+			{"name": "shared", "inputs": ["s = a"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "s", "value": "a"}]}}
+		]}`,
+		"b.json": `{"tests": [
+			{"name": "shared", "inputs": ["s = b"], "validation": "get_string", "args": ["s"], "expected": {"count": 1, "value": "b"}}
+		]}`,
+		// Never decoded by name lookups, since it cannot contain any test name asked for
+		"c.json": `{"tests": [`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(generated, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestTestLoader_GetTestByName(t *testing.T) {
+	loader := NewTestLoader(setupQueryTestData(t), config.ImplementationConfig{})
+	opts := LoadOptions{Format: FormatFlat, FilterMode: FilterAll}
+
+	test, ok, err := loader.GetTestByName("only_a", opts)
+	if err != nil || !ok {
+		t.Fatalf("Expected only_a to be found, got %v, %v", ok, err)
+	}
+	if test.Inputs[0] != "a = 1" {
+		t.Errorf("Unexpected test: %+v", test)
+	}
+
+	if _, ok, err := loader.GetTestByName("missing", opts); ok || err != nil {
+		t.Errorf("Expected missing not to be found, got %v, %v", ok, err)
+	}
+
+	_, _, err = loader.GetTestByName("shared", opts)
+	var ambiguous *AmbiguousTestError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousTestError, got %v", err)
+	}
+	if want := []string{"a.json", "b.json"}; !reflect.DeepEqual(ambiguous.Candidates, want) {
+		t.Errorf("Expected candidates %v, got %v", want, ambiguous.Candidates)
+	}
+
+	opts.Dedup = true
+	test, ok, err = loader.GetTestByName("shared", opts)
+	if err != nil || !ok || test.Inputs[0] != "s = a" {
+		t.Errorf("Expected the first shared test with Dedup, got %+v, %v, %v", test, ok, err)
+	}
+}
+
+func TestTestLoader_GetTestsByNames(t *testing.T) {
+	loader := NewTestLoader(setupQueryTestData(t), config.ImplementationConfig{
+		SupportedFunctions: []config.CCLFunction{config.FunctionGetString},
+	})
+
+	// Filtering applies before ambiguity, so only b.json's shared test is compatible
+	found, err := loader.GetTestsByNames([]string{"only_a", "shared", "missing"}, LoadOptions{Format: FormatFlat, FilterMode: FilterCompatible})
+	if err != nil {
+		t.Fatalf("GetTestsByNames failed: %v", err)
+	}
+	if len(found) != 1 || found["shared"].Validation != "get_string" {
+		t.Errorf("Expected only the compatible shared test, got %+v", found)
+	}
+
+	// Names JSON may escape disable the raw byte check, so c.json is decoded
+	if _, err := loader.GetTestsByNames([]string{`quote"d`}, LoadOptions{Format: FormatFlat, FilterMode: FilterAll}); !errors.Is(err, ErrParse) {
+		t.Errorf("Expected c.json to be decoded for an escaped name, got %v", err)
+	}
+}
+
+func TestTestLoader_Index(t *testing.T) {
+	write := func(content string) string {
+		dir := t.TempDir()
+		os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+		if err := os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write tests: %v", err)
+		}
+		return dir
+	}
+	test := `"inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "1"}]}`
+	before := write(`{"tests": [{"name": "old_name", ` + test + `}]}`)
+	after := write(`{"tests": [{"name": "new_name", ` + test + `}, {"name": "copy", ` + test + `}]}`)
+
+	opts := LoadOptions{Format: FormatFlat, FilterMode: FilterAll}
+	oldIndex, err := NewTestLoader(before, config.ImplementationConfig{}).Index(opts)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	newIndex, err := NewTestLoader(after, config.ImplementationConfig{}).Index(opts)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if len(oldIndex) != 1 || len(newIndex) != 1 {
+		t.Fatalf("Expected one ID in each version, got %v and %v", oldIndex, newIndex)
+	}
+	for id, old := range oldIndex {
+		if old.Name != "old_name" || newIndex[id].Name != "new_name" {
+			t.Errorf("Expected the renamed test under the same ID, got %q and %q", old.Name, newIndex[id].Name)
+		}
+	}
+}
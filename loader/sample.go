@@ -0,0 +1,109 @@
+package loader
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// SampleTests returns a deterministic stratified sample of n tests, in their
+// original order. stratifyBy names each test's stratum (the validation if
+// nil). Every stratum gets one test when n is at least the number of
+// strata, and the rest of the sample is shared out in proportion to stratum
+// size; with fewer, n strata chosen by seed get one each. The same tests,
+// n and seed always give the same sample, whatever order the tests are in.
+// It returns all tests when n is at least len(tests), and none when n is
+// not positive.
+func SampleTests(tests []types.TestCase, n int, seed int64, stratifyBy func(types.TestCase) string) []types.TestCase {
+	indices := SampleIndices(tests, n, seed, stratifyBy)
+	sample := make([]types.TestCase, len(indices))
+	for i, index := range indices {
+		sample[i] = tests[index]
+	}
+	return sample
+}
+
+// SampleIndices returns the indices into tests, ascending, of the sample
+// SampleTests would return
+func SampleIndices(tests []types.TestCase, n int, seed int64, stratifyBy func(types.TestCase) string) []int {
+	if n <= 0 {
+		return nil
+	}
+	if n >= len(tests) {
+		all := make([]int, len(tests))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	if stratifyBy == nil {
+		stratifyBy = func(test types.TestCase) string { return test.Validation }
+	}
+
+	// Order each stratum by content, not position, before shuffling
+	ids := make([]string, len(tests))
+	strata := make(map[string][]int)
+	for i, test := range tests {
+		ids[i] = test.ID()
+		key := stratifyBy(test)
+		strata[key] = append(strata[key], i)
+	}
+	keys := make([]string, 0, len(strata))
+	for key := range strata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	for _, key := range keys {
+		members := strata[key]
+		slices.SortFunc(members, func(a, b int) int {
+			return cmp.Or(cmp.Compare(ids[a], ids[b]), cmp.Compare(tests[a].Name, tests[b].Name))
+		})
+		rng.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	}
+
+	quotas := sampleQuotas(keys, strata, n, rng)
+	var sample []int
+	for _, key := range keys {
+		sample = append(sample, strata[key][:quotas[key]]...)
+	}
+	slices.Sort(sample)
+	return sample
+}
+
+// sampleQuotas shares n, which is less than the number of tests, among the
+// strata: one each while n allows, the rest by size with the largest
+// remainders rounded up
+func sampleQuotas(keys []string, strata map[string][]int, n int, rng *rand.Rand) map[string]int {
+	quotas := make(map[string]int, len(keys))
+	if n < len(keys) {
+		chosen := slices.Clone(keys)
+		rng.Shuffle(len(chosen), func(i, j int) { chosen[i], chosen[j] = chosen[j], chosen[i] })
+		for _, key := range chosen[:n] {
+			quotas[key] = 1
+		}
+		return quotas
+	}
+
+	spare := -len(keys) // Tests beyond the first of each stratum
+	for _, key := range keys {
+		spare += len(strata[key])
+	}
+	extra := n - len(keys)
+	remainders := make(map[string]int, len(keys))
+	given := 0
+	for _, key := range keys {
+		share := extra * (len(strata[key]) - 1)
+		quotas[key] = 1 + share/spare
+		remainders[key] = share % spare
+		given += share / spare
+	}
+	byRemainder := slices.Clone(keys)
+	slices.SortStableFunc(byRemainder, func(a, b string) int { return remainders[b] - remainders[a] })
+	for _, key := range byRemainder[:extra-given] {
+		quotas[key]++
+	}
+	return quotas
+}
@@ -0,0 +1,129 @@
+package loader
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// sampleCorpus returns 100 tests over four validations of very different
+// sizes: 70 parse, 20 get_string, 9 filter and 1 combine
+func sampleCorpus() []types.TestCase {
+	var tests []types.TestCase
+	for validation, count := range map[string]int{"parse": 70, "get_string": 20, "filter": 9, "combine": 1} {
+		for i := 0; i < count; i++ {
+			tests = append(tests, types.TestCase{
+				Name:       fmt.Sprintf("%s_%d", validation, i),
+				Inputs:     []string{fmt.Sprintf("key = %d", i)},
+				Validation: validation,
+			})
+		}
+	}
+	slices.SortFunc(tests, func(a, b types.TestCase) int { return cmp.Compare(a.Name, b.Name) })
+	return tests
+}
+
+func sampleNames(tests []types.TestCase) []string {
+	names := make([]string, len(tests))
+	for i, test := range tests {
+		names[i] = test.Name
+	}
+	return names
+}
+
+func countBy(tests []types.TestCase) map[string]int {
+	counts := make(map[string]int)
+	for _, test := range tests {
+		counts[test.Validation]++
+	}
+	return counts
+}
+
+func TestSampleTests_Deterministic(t *testing.T) {
+	tests := sampleCorpus()
+	first := sampleNames(SampleTests(tests, 10, 42, nil))
+	if again := sampleNames(SampleTests(tests, 10, 42, nil)); !reflect.DeepEqual(first, again) {
+		t.Errorf("Expected the same sample for the same seed, got %v and %v", first, again)
+	}
+
+	// Input order does not matter
+	reversed := slices.Clone(tests)
+	slices.Reverse(reversed)
+	got := sampleNames(SampleTests(reversed, 10, 42, nil))
+	slices.Sort(got)
+	if want := slices.Sorted(slices.Values(first)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the same sample from reversed input, got %v, want %v", got, want)
+	}
+
+	if other := sampleNames(SampleTests(tests, 10, 7, nil)); reflect.DeepEqual(first, other) {
+		t.Errorf("Expected another seed to choose another sample, got %v for both", first)
+	}
+}
+
+func TestSampleTests_Strata(t *testing.T) {
+	tests := sampleCorpus()
+
+	// 10 tests: one per validation, the other 6 shared by size
+	sample := SampleTests(tests, 10, 1, nil)
+	if len(sample) != 10 {
+		t.Fatalf("Expected exactly 10 tests, got %d", len(sample))
+	}
+	if got, want := countBy(sample), map[string]int{"parse": 5, "get_string": 2, "filter": 2, "combine": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if !slices.IsSortedFunc(sample, func(a, b types.TestCase) int { return cmp.Compare(a.Name, b.Name) }) {
+		t.Errorf("Expected the sample in input order, got %v", sampleNames(sample))
+	}
+
+	// Fewer tests than strata: one test from each of n strata
+	sample = SampleTests(tests, 3, 1, nil)
+	counts := countBy(sample)
+	if len(sample) != 3 || len(counts) != 3 {
+		t.Errorf("Expected 3 tests from 3 validations, got %v", counts)
+	}
+
+	// A custom stratum
+	byParity := func(test types.TestCase) string { return fmt.Sprint(len(test.Name) % 2) }
+	sample = SampleTests(tests, 2, 1, byParity)
+	if len(sample) != 2 || byParity(sample[0]) == byParity(sample[1]) {
+		t.Errorf("Expected one test of each parity, got %v", sampleNames(sample))
+	}
+}
+
+func TestSampleTests_Sizes(t *testing.T) {
+	tests := sampleCorpus()
+	for n := 0; n <= len(tests)+5; n++ {
+		want := min(n, len(tests))
+		sample := SampleTests(tests, n, int64(n), nil)
+		if len(sample) != want {
+			t.Errorf("n=%d: expected %d tests, got %d", n, want, len(sample))
+		}
+		if n >= 4 && len(countBy(sample)) != 4 {
+			t.Errorf("n=%d: expected every validation, got %v", n, countBy(sample))
+		}
+		names := sampleNames(sample)
+		if len(slices.Compact(slices.Clone(names))) != len(names) {
+			t.Errorf("n=%d: expected no test twice, got %v", n, names)
+		}
+	}
+	if got := SampleTests(tests, -1, 0, nil); len(got) != 0 {
+		t.Errorf("Expected no tests for a negative size, got %d", len(got))
+	}
+}
+
+func TestTestLoader_SampleSize(t *testing.T) {
+	tl := NewTestLoader(writeSyntheticFlatDir(t, 50), createTestConfig())
+	opts := LoadOptions{Format: FormatFlat, FilterMode: FilterAll, SampleSize: 5, SampleSeed: 3}
+	first, err := tl.LoadAllTests(opts)
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	second, _ := tl.LoadAllTests(opts)
+	if len(first) != 5 || !reflect.DeepEqual(sampleNames(first), sampleNames(second)) {
+		t.Errorf("Expected the same 5 tests twice, got %v and %v", sampleNames(first), sampleNames(second))
+	}
+}
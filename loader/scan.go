@@ -0,0 +1,61 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScanOptions controls which files a directory scan picks up
+type ScanOptions struct {
+	SkipHidden     bool // Ignore dotfiles such as editor backups (.#foo.json)
+	FollowSymlinks bool // Include symlinked files; broken links and link cycles are skipped
+}
+
+// DefaultScanOptions skips hidden files and symlinks
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{SkipHidden: true}
+}
+
+// ScanDir returns the *.json files in dir, in name order. Each file is
+// returned once even if several symlinks resolve to it.
+func ScanDir(dir string, opts ScanOptions) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".json") || (opts.SkipHidden && strings.HasPrefix(name, ".")) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if entry.Type()&fs.ModeSymlink != 0 && !opts.FollowSymlinks {
+			continue
+		}
+		// Resolving fails for broken links and cycles, which are skipped
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(target); err != nil || !info.Mode().IsRegular() || seen[target] {
+			continue
+		}
+		seen[target] = true
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// scanOptions returns opts, or the defaults if nil
+func scanOptions(opts *ScanOptions) ScanOptions {
+	if opts == nil {
+		return DefaultScanOptions()
+	}
+	return *opts
+}
@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// setupScanDir creates a directory with a regular file, a hidden file, a
+// symlink to a shared fixture, a symlink cycle, and a broken link
+func setupScanDir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "generated_tests")
+	shared := filepath.Join(root, "shared")
+	os.MkdirAll(dir, 0755)
+	os.MkdirAll(shared, 0755)
+
+	test := func(name string) []byte {
+		return []byte(`{"tests": [{"name": "` + name + `", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}}]}`)
+	}
+	os.WriteFile(filepath.Join(dir, "api.json"), test("regular"), 0644)
+	os.WriteFile(filepath.Join(dir, ".#api.json"), test("hidden"), 0644)
+	os.WriteFile(filepath.Join(shared, "fixture.json"), test("linked"), 0644)
+	links := map[string]string{
+		"linked.json": filepath.Join(shared, "fixture.json"),
+		"again.json":  filepath.Join(shared, "fixture.json"),
+		"loop-a.json": filepath.Join(dir, "loop-b.json"),
+		"loop-b.json": filepath.Join(dir, "loop-a.json"),
+		"broken.json": filepath.Join(shared, "missing.json"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+	return root
+}
+
+func TestScanDir(t *testing.T) {
+	root := setupScanDir(t)
+	dir := filepath.Join(root, "generated_tests")
+
+	tests := []struct {
+		name string
+		opts ScanOptions
+		want []string
+	}{
+		{"defaults", DefaultScanOptions(), []string{"api.json"}},
+		{"hidden", ScanOptions{}, []string{".#api.json", "api.json"}},
+		{"symlinks", ScanOptions{SkipHidden: true, FollowSymlinks: true}, []string{"again.json", "api.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := ScanDir(dir, tt.opts)
+			if err != nil {
+				t.Fatalf("ScanDir failed: %v", err)
+			}
+			var got []string
+			for _, file := range files {
+				got = append(got, filepath.Base(file))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ScanDir(filepath.Join(root, "missing"), DefaultScanOptions()); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+func TestTestLoader_LoadAllTests_Scan(t *testing.T) {
+	root := setupScanDir(t)
+	loader := NewTestLoader(root, config.ImplementationConfig{})
+
+	names := func(scan *ScanOptions) []string {
+		tests, err := loader.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, Scan: scan})
+		if err != nil {
+			t.Fatalf("LoadAllTests failed: %v", err)
+		}
+		var names []string
+		for _, test := range tests {
+			names = append(names, test.Name)
+		}
+		return names
+	}
+
+	if got := names(nil); !reflect.DeepEqual(got, []string{"regular"}) {
+		t.Errorf("Expected only the regular file by default, got %v", got)
+	}
+	if got := names(&ScanOptions{FollowSymlinks: true}); !reflect.DeepEqual(got, []string{"hidden", "linked", "regular"}) {
+		t.Errorf("Expected hidden and linked tests once each, got %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package loader
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// ShardOf returns which of count shards test belongs to. Shards are chosen
+// by a hash of types.TestCase.ID, not by position, so adding, removing or
+// reordering other tests never moves a test to another shard.
+func ShardOf(test types.TestCase, count int) int {
+	if count <= 1 {
+		return 0
+	}
+	hash, _ := strconv.ParseUint(test.ID(), 16, 64)
+	return int(hash % uint64(count))
+}
+
+// CheckShard returns an error unless index is a shard of count, or count is
+// zero for no sharding
+func CheckShard(index, count int) error {
+	if count == 0 && index == 0 {
+		return nil
+	}
+	if count < 1 || index < 0 || index >= count {
+		return fmt.Errorf("invalid shard %d of %d: the index must be from 0 to the count minus one", index, count)
+	}
+	return nil
+}
+
+// inShard reports whether test belongs to the shard opts select
+func inShard(test types.TestCase, opts LoadOptions) bool {
+	return opts.ShardCount <= 1 || ShardOf(test, opts.ShardCount) == opts.ShardIndex
+}
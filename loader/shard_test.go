@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// shardTests returns n flat tests with distinct IDs
+func shardTests(n int) []types.TestCase {
+	tests := make([]types.TestCase, n)
+	for i := range tests {
+		tests[i] = types.TestCase{
+			Name:       fmt.Sprintf("test_%d_parse", i),
+			Inputs:     []string{fmt.Sprintf("key = %d", i)},
+			Validation: "parse",
+		}
+	}
+	return tests
+}
+
+func TestTestLoader_Shards(t *testing.T) {
+	root := writeSyntheticFlatDir(t, 200)
+	tl := NewTestLoader(root, createTestConfig())
+	all, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+
+	const count = 4
+	seen := make(map[string]int)
+	for index := 0; index < count; index++ {
+		shard, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll, ShardIndex: index, ShardCount: count})
+		if err != nil {
+			t.Fatalf("Loading shard %d failed: %v", index, err)
+		}
+		// A fair split puts 50 tests in each shard; allow for hash noise
+		if len(shard) < 30 || len(shard) > 70 {
+			t.Errorf("Shard %d has %d of %d tests; expected a roughly even split", index, len(shard), len(all))
+		}
+		for _, test := range shard {
+			if previous, ok := seen[test.Name]; ok {
+				t.Errorf("%s is in shards %d and %d", test.Name, previous, index)
+			}
+			seen[test.Name] = index
+		}
+	}
+	for _, test := range all {
+		if _, ok := seen[test.Name]; !ok {
+			t.Errorf("%s is in no shard", test.Name)
+		}
+	}
+}
+
+func TestShardOf_Stable(t *testing.T) {
+	tests := shardTests(100)
+	before := make(map[string]int)
+	for _, test := range tests {
+		before[test.Name] = ShardOf(test, 4)
+	}
+
+	// Adding a test, even at the front, moves no other test
+	added := append([]types.TestCase{{Name: "new_parse", Inputs: []string{"new = 1"}, Validation: "parse"}}, tests...)
+	for _, test := range added[1:] {
+		if got := ShardOf(test, 4); got != before[test.Name] {
+			t.Errorf("%s moved from shard %d to %d after adding a test", test.Name, before[test.Name], got)
+		}
+	}
+
+	// Renaming keeps the shard, since it follows the content
+	renamed := tests[0]
+	renamed.Name = "renamed_parse"
+	if got := ShardOf(renamed, 4); got != before[tests[0].Name] {
+		t.Errorf("Expected a renamed test to stay in shard %d, got %d", before[tests[0].Name], got)
+	}
+}
+
+func TestShardOf_SingleShard(t *testing.T) {
+	for _, test := range shardTests(10) {
+		if got := ShardOf(test, 1); got != 0 {
+			t.Errorf("Expected shard 0 of 1, got %d", got)
+		}
+	}
+	tl := NewTestLoader("", createTestConfig())
+	if got := tl.applyFiltering(shardTests(10), LoadOptions{FilterMode: FilterAll, ShardCount: 1}); len(got) != 10 {
+		t.Errorf("Expected one shard to keep all 10 tests, got %d", len(got))
+	}
+}
+
+func TestCheckShard(t *testing.T) {
+	tests := []struct {
+		index, count int
+		valid        bool
+	}{
+		{0, 0, true},
+		{0, 1, true},
+		{3, 4, true},
+		{4, 4, false},
+		{-1, 4, false},
+		{1, 0, false},
+		{0, -2, false},
+	}
+	for _, tt := range tests {
+		if err := CheckShard(tt.index, tt.count); (err == nil) != tt.valid {
+			t.Errorf("CheckShard(%d, %d) = %v, want valid %v", tt.index, tt.count, err, tt.valid)
+		}
+	}
+
+	tl := NewTestLoader(writeSyntheticFlatDir(t, 1), createTestConfig())
+	if _, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, ShardIndex: 4, ShardCount: 4}); err == nil {
+		t.Error("Expected LoadAllTests to reject shard 4 of 4")
+	}
+}
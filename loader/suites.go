@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// suiteSection is one named suite of a multi-suite test file, re-encoded
+// as a single-suite file
+type suiteSection struct {
+	Name string
+	Data []byte
+}
+
+// splitSuites splits a test file of the form {"suites": [{"suite": "typed",
+// "tests": [...]}, ...]} into one single-suite file per suite. Each carries
+// the file's other top-level fields, such as $schema and file-level
+// features, unless the suite sets the same field itself. It reports false
+// for single-suite and bare-array files.
+func splitSuites(data []byte) ([]suiteSection, bool, error) {
+	var file map[string]json.RawMessage
+	if json.Unmarshal(data, &file) != nil {
+		return nil, false, nil // Bare arrays, and syntax errors decoding reports
+	}
+	rawSuites, ok := file["suites"]
+	if !ok {
+		return nil, false, nil
+	}
+	if _, ok := file["tests"]; ok {
+		return nil, true, fmt.Errorf("file has both tests and suites")
+	}
+
+	var suites []map[string]json.RawMessage
+	if err := json.Unmarshal(rawSuites, &suites); err != nil {
+		return nil, true, fmt.Errorf("failed to parse suites: %w", err)
+	}
+	sections := make([]suiteSection, 0, len(suites))
+	seen := make(map[string]bool, len(suites))
+	for i, suite := range suites {
+		var name string
+		json.Unmarshal(suite["suite"], &name)
+		if name == "" {
+			return nil, true, fmt.Errorf("suites[%d] has no suite name", i)
+		}
+		if seen[name] {
+			return nil, true, fmt.Errorf("suite %q appears more than once", name)
+		}
+		seen[name] = true
+
+		merged := make(map[string]json.RawMessage, len(file)+len(suite))
+		for key, value := range file {
+			if key != "suites" {
+				merged[key] = value
+			}
+		}
+		for key, value := range suite {
+			if key != "suite" {
+				merged[key] = value
+			}
+		}
+		sectionData, err := json.Marshal(merged)
+		if err != nil {
+			return nil, true, fmt.Errorf("suite %q: %w", name, err)
+		}
+		sections = append(sections, suiteSection{Name: name, Data: sectionData})
+	}
+	return sections, true, nil
+}
+
+// SplitSuites returns each suite of a multi-suite test file as the data of
+// a single-suite file, or data itself for single-suite and bare-array files
+func SplitSuites(data []byte) ([][]byte, error) {
+	sections, multiSuite, err := splitSuites(StripBOM(data))
+	if err != nil {
+		return nil, err
+	}
+	if !multiSuite {
+		return [][]byte{data}, nil
+	}
+	files := make([][]byte, len(sections))
+	for i, section := range sections {
+		files[i] = section.Data
+	}
+	return files, nil
+}
@@ -0,0 +1,143 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+const (
+	flatTestA = `{"name": "a_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "1"}]}, "features": [], "behaviors": [], "variants": []}`
+	flatTestB = `{"name": "b_get_string", "inputs": ["b = 2"], "validation": "get_string", "args": ["b"], "expected": {"count": 1, "value": "2"}, "features": [], "behaviors": [], "variants": []}`
+	sourceA   = `{"name": "a", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]}`
+	sourceB   = `{"name": "b", "inputs": ["b = 2"], "tests": [{"function": "get_string", "args": ["b"], "expect": "2"}]}`
+)
+
+func TestLoadTestFile_SuiteShapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		format TestFormat
+		data   string
+		names  []string
+		suites []string
+	}{
+		{"flat bare array", FormatFlat, `[` + flatTestA + `, ` + flatTestB + `]`, []string{"a_parse", "b_get_string"}, []string{"", ""}},
+		{"flat single suite", FormatFlat, `{"tests": [` + flatTestA + `, ` + flatTestB + `]}`, []string{"a_parse", "b_get_string"}, []string{"", ""}},
+		{"flat single suite with schema", FormatFlat, `{"$schema": "https://schemas.ccl.tylerbutler.com/generated-format-v1.json", "tests": [` + flatTestA + `]}`, []string{"a_parse"}, []string{""}},
+		{"flat suites", FormatFlat, `{"suites": [{"suite": "core", "tests": [` + flatTestA + `]}, {"suite": "typed", "tests": [` + flatTestB + `]}]}`, []string{"a_parse", "b_get_string"}, []string{"core", "typed"}},
+		{"flat suites with schema", FormatFlat, `{"$schema": "https://schemas.ccl.tylerbutler.com/generated-format-v1.json", "suites": [{"suite": "core", "tests": [` + flatTestA + `]}]}`, []string{"a_parse"}, []string{"core"}},
+		{"flat empty suite", FormatFlat, `{"suites": [{"suite": "core", "tests": []}, {"suite": "typed", "tests": [` + flatTestB + `]}]}`, []string{"b_get_string"}, []string{"typed"}},
+		{"flat no suites", FormatFlat, `{"suites": []}`, nil, nil},
+		{"compact single suite", FormatCompact, `{"tests": [` + sourceA + `, ` + sourceB + `]}`, []string{"a", "b"}, []string{"", ""}},
+		{"compact suites", FormatCompact, `{"suites": [{"suite": "core", "tests": [` + sourceA + `]}, {"suite": "typed", "tests": [` + sourceB + `]}]}`, []string{"a", "b"}, []string{"core", "typed"}},
+		{"compact suites with BOM", FormatCompact, "\xef\xbb\xbf" + `{"suites": [{"suite": "core", "tests": [` + sourceA + `]}]}`, []string{"a"}, []string{"core"}},
+	}
+	for _, tt := range tests {
+		for _, lazy := range []bool{false, true} {
+			tl := NewTestLoader("", config.ImplementationConfig{})
+			tl.ReadFile = func(string) ([]byte, error) { return []byte(tt.data), nil }
+			suite, err := tl.LoadTestFile("tests.json", LoadOptions{Format: tt.format, FilterMode: FilterAll, LazyExpected: lazy})
+			if err != nil {
+				t.Errorf("%s (lazy %v): %v", tt.name, lazy, err)
+				continue
+			}
+			var names, suites []string
+			for _, test := range suite.Tests {
+				names = append(names, test.Name)
+				suites = append(suites, test.Suite)
+			}
+			if !reflect.DeepEqual(names, tt.names) || !reflect.DeepEqual(suites, tt.suites) {
+				t.Errorf("%s (lazy %v): expected %v in %q, got %v in %q", tt.name, lazy, tt.names, tt.suites, names, suites)
+			}
+		}
+	}
+}
+
+func TestLoadTestFile_SuitesInheritFileFields(t *testing.T) {
+	data := `{"features": ["comments"], "suites": [
+		{"suite": "inherits", "tests": [` + flatTestA + `]},
+		{"suite": "overrides", "features": ["multiline"], "tests": [` + flatTestB + `]}
+	]}`
+	tl := NewTestLoader("", config.ImplementationConfig{})
+	tl.ReadFile = func(string) ([]byte, error) { return []byte(data), nil }
+	suite, err := tl.LoadTestFile("tests.json", LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("LoadTestFile failed: %v", err)
+	}
+	if got := suite.Tests[0].Features; !reflect.DeepEqual(got, []string{"comments"}) {
+		t.Errorf("Expected the file's features, got %v", got)
+	}
+	if got := suite.Tests[1].Features; !reflect.DeepEqual(got, []string{"multiline"}) {
+		t.Errorf("Expected the suite's own features, got %v", got)
+	}
+}
+
+func TestLoadTestFile_SuiteErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"tests": [], "suites": []}`, "file has both tests and suites"},
+		{`{"suites": [{"tests": []}]}`, "suites[0] has no suite name"},
+		{`{"suites": [{"suite": "a", "tests": []}, {"suite": "a", "tests": []}]}`, `suite "a" appears more than once`},
+		{`{"suites": {"suite": "a"}}`, "failed to parse suites"},
+		{`{"suites": [{"suite": "a", "tests": "nope"}]}`, `suite "a": `},
+	}
+	for _, tt := range tests {
+		tl := NewTestLoader("", config.ImplementationConfig{})
+		tl.ReadFile = func(string) ([]byte, error) { return []byte(tt.data), nil }
+		_, err := tl.LoadTestFile("tests.json", LoadOptions{Format: FormatFlat})
+		if !errors.Is(err, ErrParse) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected parse error %q, got %v", tt.data, tt.want, err)
+		}
+	}
+}
+
+func TestSuites_StatisticsAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api_suites.json"), []byte(`{"$schema": "`+FlatSchema(FormatVersion1)+`", "suites": [
+		{"suite": "core", "tests": [`+flatTestA+`]},
+		{"suite": "typed", "tests": [`+flatTestB+`, `+strings.Replace(flatTestB, "b_get_string", "b_get_string_again", 1)+`]}
+	]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api_plain.json"), []byte(`{"tests": [`+flatTestA+`]}`), 0644)
+
+	tl := NewTestLoader(dir, config.ImplementationConfig{})
+	tests, err := tl.LoadAllTests(LoadOptions{Format: FormatFlat, FilterMode: FilterAll})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	stats := tl.GetTestStatistics(tests)
+	if stats.TotalTests != 4 || !reflect.DeepEqual(stats.BySuite, map[string]int{"core": 1, "typed": 2}) {
+		t.Errorf("Expected suite counts for the multi-suite file only, got %d tests and %v", stats.TotalTests, stats.BySuite)
+	}
+
+	issues, err := ValidateTestFile(filepath.Join(dir, "generated_tests", "api_suites.json"))
+	if err != nil || len(issues) != 0 {
+		t.Errorf("Expected a valid multi-suite file, got %v (%v)", issues, err)
+	}
+	os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"$schema": "`+FlatSchema(FormatVersion1)+`", "suites": [{"suite": "typed", "tests": [`+flatTestB+`, `+flatTestB+`]}]}`), 0644)
+	issues, _ = ValidateTestFile(filepath.Join(dir, "bad.json"))
+	if len(issues) != 1 || issues[0].String() != "bad.json: b_get_string: suite typed: duplicate test name" {
+		t.Errorf("Expected the duplicate reported with its suite, got %v", issues)
+	}
+}
+
+func TestSplitSuites(t *testing.T) {
+	single := []byte(`{"tests": []}`)
+	if files, err := SplitSuites(single); err != nil || len(files) != 1 || string(files[0]) != string(single) {
+		t.Errorf("Expected a single-suite file unchanged, got %q (%v)", files, err)
+	}
+	files, err := SplitSuites([]byte(`{"$schema": "x", "suites": [{"suite": "a", "tests": []}, {"suite": "b", "tests": []}]}`))
+	if err != nil || len(files) != 2 || string(files[1]) != `{"$schema":"x","tests":[]}` {
+		t.Errorf("Expected two single-suite files, got %q (%v)", files, err)
+	}
+	if _, err := SplitSuites([]byte(`{"suites": [{}]}`)); err == nil {
+		t.Error("Expected an error for an unnamed suite")
+	}
+}
@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// UnknownMetadataError is returned with LoadOptions.StrictMetadata for a
+// file whose tests use features, behaviors, or variants this library
+// version does not define
+type UnknownMetadataError struct {
+	File  string
+	Test  string
+	Value string // As recorded in TestMetadata.UnknownMetadata, e.g. "behavior:future_behavior"
+}
+
+func (e *UnknownMetadataError) Error() string {
+	return fmt.Sprintf("%s: test %s: unknown %s (test data newer than this ccl-test-lib?)", e.File, e.Test, e.Value)
+}
+
+// UnknownMetadata returns the features, behaviors, and variants of a test
+// that this library version does not define, as "kind:name". Functions are
+// not included: an unknown function is already rejected as unsupported.
+func UnknownMetadata(test types.TestCase) []string {
+	var unknown []string
+	for _, feature := range test.Features {
+		if !slices.Contains(config.AllFeatures(), config.CCLFeature(feature)) {
+			unknown = append(unknown, "feature:"+feature)
+		}
+	}
+	for _, behavior := range test.Behaviors {
+		if !config.IsKnownBehavior(behavior) {
+			unknown = append(unknown, "behavior:"+behavior)
+		}
+	}
+	for _, variant := range test.Variants {
+		if !slices.Contains(config.AllVariants(), config.CCLVariant(variant)) {
+			unknown = append(unknown, "variant:"+variant)
+		}
+	}
+	return unknown
+}
+
+// flagUnknownMetadata records UnknownMetadata in each test's metadata,
+// warning about each flagged test, or with strict fails on the first
+func flagUnknownMetadata(log *slog.Logger, filename string, tests []types.TestCase, strict bool) error {
+	for i := range tests {
+		unknown := UnknownMetadata(tests[i])
+		if len(unknown) == 0 {
+			continue
+		}
+		if strict {
+			return &UnknownMetadataError{File: filename, Test: tests[i].Name, Value: unknown[0]}
+		}
+		tests[i].Meta.UnknownMetadata = unknown
+		log.Warn("unknown metadata", "test", tests[i].Name, "values", unknown)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package loader
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// writeFutureFile writes a flat test file in which one test uses a behavior
+// added after this library version
+func writeFutureFile(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	content := `{"tests": [
+		{"name": "today", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "functions": ["parse"]},
+		{"name": "tomorrow", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "functions": ["parse"], "behaviors": ["future_behavior"]}
+	]}`
+	path := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTestFile_UnknownMetadata(t *testing.T) {
+	path := writeFutureFile(t)
+	var records []slog.Record
+	loader := NewTestLoader(filepath.Dir(path), config.ImplementationConfig{
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse},
+	})
+	loader.Logger = slog.New(recordingHandler{records: &records})
+
+	suite, err := loader.LoadTestFile(path, LoadOptions{Format: FormatFlat})
+	if err != nil {
+		t.Fatalf("Expected the file to load, got %v", err)
+	}
+	if len(suite.Tests) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(suite.Tests))
+	}
+	if got := suite.Tests[0].Meta.UnknownMetadata; got != nil {
+		t.Errorf("Expected no unknown metadata for today, got %v", got)
+	}
+	if got := suite.Tests[1].Meta.UnknownMetadata; !slices.Equal(got, []string{"behavior:future_behavior"}) {
+		t.Errorf("Expected tomorrow flagged with behavior:future_behavior, got %v", got)
+	}
+
+	var warned bool
+	for _, r := range records {
+		if r.Message == "unknown metadata" && recordAttrs(r)["test"] == "tomorrow" {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Error("Expected an unknown metadata warning for tomorrow")
+	}
+
+	if !loader.IsTestCompatible(suite.Tests[0]) {
+		t.Error("Expected today to stay compatible")
+	}
+	rejection, rejected := loader.ExplainRejection(suite.Tests[1])
+	if !rejected || rejection.Kind != RejectionUnknown || rejection.Name != "behavior:future_behavior" {
+		t.Errorf("Expected tomorrow rejected as unknown, got %+v (rejected %v)", rejection, rejected)
+	}
+	if rejection.Reason != "uses unknown behavior:future_behavior; upgrade ccl-test-lib to run it" {
+		t.Errorf("Unexpected reason %q", rejection.Reason)
+	}
+
+	stats := loader.GetTestStatistics(suite.Tests)
+	if stats.CompatibleTests != 1 || stats.UnknownTests != 1 || len(stats.ConflictingSets) != 0 {
+		t.Errorf("Expected 1 compatible and 1 unknown test and no conflicts, got %+v", stats)
+	}
+}
+
+func TestLoadTestFile_StrictMetadata(t *testing.T) {
+	path := writeFutureFile(t)
+	loader := NewTestLoader(filepath.Dir(path), config.ImplementationConfig{})
+
+	_, err := loader.LoadTestFile(path, LoadOptions{Format: FormatFlat, StrictMetadata: true})
+	var unknown *UnknownMetadataError
+	if !errors.As(err, &unknown) || !errors.Is(err, ErrParse) {
+		t.Fatalf("Expected an UnknownMetadataError wrapping ErrParse, got %v", err)
+	}
+	if unknown.Test != "tomorrow" || unknown.Value != "behavior:future_behavior" {
+		t.Errorf("Expected test tomorrow and behavior:future_behavior, got %+v", unknown)
+	}
+}
+
+func TestUnknownMetadata(t *testing.T) {
+	path := writeFutureFile(t)
+	suite, err := NewTestLoader(filepath.Dir(path), config.ImplementationConfig{}).LoadTestFile(path, LoadOptions{Format: FormatFlat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := suite.Tests[0]
+	test.Features = []string{"comments", "holograms"}
+	test.Behaviors = []string{"crlf_normalize_to_lf"}
+	test.Variants = []string{"reference_compliant", "future_variant"}
+	want := []string{"feature:holograms", "variant:future_variant"}
+	if got := UnknownMetadata(test); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
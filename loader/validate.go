@@ -0,0 +1,188 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+	"github.com/CatConfLang/ccl-test-lib/types/generated"
+)
+
+// ValidationIssue describes one problem found while validating a test file
+type ValidationIssue struct {
+	File    string `json:"file"`
+	Test    string `json:"test,omitempty"` // Empty for file-level issues
+	Message string `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	if i.Test != "" {
+		return fmt.Sprintf("%s: %s: %s", i.File, i.Test, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.File, i.Message)
+}
+
+// DetectFormat reports whether data holds compact source tests or flat generated tests
+func DetectFormat(data []byte) (TestFormat, error) {
+	var file struct {
+		Tests []map[string]json.RawMessage `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return FormatCompact, fmt.Errorf("failed to parse test file: %w", err)
+	}
+	if len(file.Tests) == 0 {
+		return FormatCompact, fmt.Errorf("test file has no tests")
+	}
+	if _, ok := file.Tests[0]["validation"]; ok {
+		return FormatFlat, nil
+	}
+	if _, ok := file.Tests[0]["tests"]; ok {
+		return FormatCompact, nil
+	}
+	if _, ok := file.Tests[0]["expect_parse_error"]; ok {
+		return FormatCompact, nil
+	}
+	return FormatCompact, fmt.Errorf("cannot determine test format")
+}
+
+// ValidateTestFile checks a test file against its format's JSON schema and
+// for structural problems such as duplicate test names. The returned error
+// is only set when the file cannot be read.
+func ValidateTestFile(path string) ([]ValidationIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	data = StripBOM(data)
+	file := filepath.Base(path)
+
+	sections, multiSuite, err := splitSuites(data)
+	if err != nil {
+		return []ValidationIssue{{File: file, Message: err.Error()}}, nil
+	}
+	if !multiSuite {
+		return validateTestData(file, data), nil
+	}
+	var issues []ValidationIssue
+	for _, section := range sections {
+		for _, issue := range validateTestData(file, section.Data) {
+			issue.Message = fmt.Sprintf("suite %s: %s", section.Name, issue.Message)
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// validateTestData validates the contents of a single-suite test file
+func validateTestData(file string, data []byte) []ValidationIssue {
+	format, err := DetectFormat(data)
+	if err != nil {
+		return []ValidationIssue{{File: file, Message: err.Error()}}
+	}
+
+	if format == FormatFlat {
+		var flat generated.GeneratedFormatSimpleJson
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return []ValidationIssue{{File: file, Message: "schema: " + err.Error()}}
+		}
+		return validateFlatStructure(file, flat)
+	}
+
+	var source generated.SourceFormatJson
+	if err := json.Unmarshal(data, &source); err != nil {
+		return []ValidationIssue{{File: file, Message: "schema: " + err.Error()}}
+	}
+	return validateSourceStructure(file, source)
+}
+
+// ValidateTestDir validates every *.json test file in a directory, in name order
+func ValidateTestDir(dir string) ([]ValidationIssue, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	sort.Strings(files)
+
+	var issues []ValidationIssue
+	for _, file := range files {
+		fileIssues, err := ValidateTestFile(file)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
+
+func validateSourceStructure(file string, source generated.SourceFormatJson) []ValidationIssue {
+	var issues []ValidationIssue
+	seen := make(map[string]bool)
+
+	if source.Generator != nil {
+		if _, err := (FileGeneratorOptions{SkipFunctions: source.Generator.SkipFunctions}).Functions(); err != nil {
+			issues = append(issues, ValidationIssue{File: file, Message: err.Error()})
+		}
+	}
+
+	for i, test := range source.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("tests[%d]", i)
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "empty test name"})
+		} else if seen[name] {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "duplicate test name"})
+		}
+		seen[test.Name] = true
+
+		expectParseError := test.ExpectParseError != nil && *test.ExpectParseError
+		if len(test.Tests) == 0 && !expectParseError {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "no validations and no expect_parse_error"})
+		}
+		if test.ErrorType != nil && !expectParseError {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "error_type without expect_parse_error"})
+		}
+
+		functions := make(map[string]bool)
+		for _, validation := range test.Tests {
+			fn := string(validation.Function)
+			if functions[fn] {
+				issues = append(issues, ValidationIssue{File: file, Test: name, Message: "duplicate validation " + fn})
+			}
+			functions[fn] = true
+			if problem := types.CheckArgs(fn, validation.Args); problem != nil {
+				issues = append(issues, ValidationIssue{File: file, Test: name, Message: problem.Message})
+			}
+			if problem := types.CheckInputs(fn, test.Inputs); problem != nil {
+				issues = append(issues, ValidationIssue{File: file, Test: name, Message: problem.Message})
+			}
+		}
+	}
+	return issues
+}
+
+func validateFlatStructure(file string, flat generated.GeneratedFormatSimpleJson) []ValidationIssue {
+	var issues []ValidationIssue
+	seen := make(map[string]bool)
+
+	for i, test := range flat.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("tests[%d]", i)
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "empty test name"})
+		} else if seen[name] {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: "duplicate test name"})
+		}
+		seen[test.Name] = true
+
+		if problem := types.CheckArgs(string(test.Validation), test.Args); problem != nil {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: problem.Message})
+		}
+		if problem := types.CheckInputs(string(test.Validation), test.Inputs); problem != nil {
+			issues = append(issues, ValidationIssue{File: file, Test: name, Message: problem.Message})
+		}
+	}
+	return issues
+}
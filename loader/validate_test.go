@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeValidateFixture(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestDetectFormat(t *testing.T) {
+	compact := `{"tests": [{"name": "a", "inputs": ["a = b"], "tests": []}]}`
+	flat := `{"tests": [{"name": "a", "inputs": ["a = b"], "validation": "parse"}]}`
+
+	if format, err := DetectFormat([]byte(compact)); err != nil || format != FormatCompact {
+		t.Errorf("Expected compact format, got %v (%v)", format, err)
+	}
+	if format, err := DetectFormat([]byte(flat)); err != nil || format != FormatFlat {
+		t.Errorf("Expected flat format, got %v (%v)", format, err)
+	}
+	parseError := `{"tests": [{"name": "a", "inputs": ["a"], "expect_parse_error": true}]}`
+	if format, err := DetectFormat([]byte(parseError)); err != nil || format != FormatCompact {
+		t.Errorf("Expected compact format for a parse error test, got %v (%v)", format, err)
+	}
+	if _, err := DetectFormat([]byte(`{"tests": []}`)); err == nil {
+		t.Error("Expected error for empty tests")
+	}
+}
+
+func TestValidateTestFile_ValidSource(t *testing.T) {
+	path := writeValidateFixture(t, t.TempDir(), "api_basic.json", `{
+  "tests": [
+    {"name": "basic", "inputs": ["a = b"], "tests": [
+      {"function": "parse", "expect": [{"key": "a", "value": "b"}]},
+      {"function": "get_string", "args": ["a"], "expect": "b"}
+    ]}
+  ]
+}`)
+
+	issues, err := ValidateTestFile(path)
+	if err != nil {
+		t.Fatalf("ValidateTestFile failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateTestFile_SchemaViolation(t *testing.T) {
+	path := writeValidateFixture(t, t.TempDir(), "api_bad.json", `{
+  "tests": [
+    {"name": "bad", "inputs": ["a = b"], "tests": [{"function": "not_a_function", "expect": null}]}
+  ]
+}`)
+
+	issues, err := ValidateTestFile(path)
+	if err != nil {
+		t.Fatalf("ValidateTestFile failed: %v", err)
+	}
+	if len(issues) != 1 || !strings.HasPrefix(issues[0].Message, "schema:") {
+		t.Errorf("Expected one schema issue, got %v", issues)
+	}
+}
+
+func TestValidateTestFile_StructuralIssues(t *testing.T) {
+	path := writeValidateFixture(t, t.TempDir(), "api_dupes.json", `{
+  "tests": [
+    {"name": "dup", "inputs": ["a = b"], "tests": [{"function": "get_string", "expect": "b"}]},
+    {"name": "dup", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": []}]}
+  ]
+}`)
+
+	issues, err := ValidateTestFile(path)
+	if err != nil {
+		t.Fatalf("ValidateTestFile failed: %v", err)
+	}
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	expected := []string{
+		"api_dupes.json: dup: get_string requires args",
+		"api_dupes.json: dup: duplicate test name",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected issues:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestValidateTestFile_ParseError(t *testing.T) {
+	path := writeValidateFixture(t, t.TempDir(), "api_errors.json", `{
+  "tests": [
+    {"name": "no_equals", "inputs": ["just a key"], "expect_parse_error": true, "error_type": "syntax"},
+    {"name": "empty", "inputs": ["a = b"]},
+    {"name": "stray_type", "inputs": ["a = b"], "error_type": "syntax", "tests": [{"function": "parse", "expect": []}]}
+  ]
+}`)
+
+	issues, err := ValidateTestFile(path)
+	if err != nil {
+		t.Fatalf("ValidateTestFile failed: %v", err)
+	}
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	expected := []string{
+		"api_errors.json: empty: no validations and no expect_parse_error",
+		"api_errors.json: stray_type: error_type without expect_parse_error",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected issues:\n%s", strings.Join(messages, "\n"))
+	}
+}
+
+func TestValidateTestFile_FileGeneratorOptions(t *testing.T) {
+	path := writeValidateFixture(t, t.TempDir(), "api_internal.json", `{
+  "generator": {"skip_functions": ["get_string", "diagnose"]},
+  "tests": [{"name": "a", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": []}]}]
+}`)
+
+	issues, err := ValidateTestFile(path)
+	if err != nil {
+		t.Fatalf("ValidateTestFile failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].String() != `api_internal.json: generator.skip_functions: unknown function "diagnose"` {
+		t.Errorf("Expected one unknown function issue, got %v", issues)
+	}
+}
+
+func TestValidateTestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeValidateFixture(t, dir, "a.json", `{"tests": [{"name": "x", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": []}]}]}`)
+	writeValidateFixture(t, dir, "b.json", `not json`)
+
+	issues, err := ValidateTestDir(dir)
+	if err != nil {
+		t.Fatalf("ValidateTestDir failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].File != "b.json" {
+		t.Errorf("Expected one issue for b.json, got %v", issues)
+	}
+}
@@ -0,0 +1,136 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Generated format versions the loader can decode
+const (
+	FormatVersion1 = 1 // Tests use "validation" and "expected"
+	FormatVersion2 = 2 // Tests use "function" and "expect"
+)
+
+// SupportedFormatVersions lists the generated format versions the loader decodes
+var SupportedFormatVersions = []int{FormatVersion1, FormatVersion2}
+
+// flatSchemaID is the schema $id generated files reference in $schema
+const flatSchemaID = "ccl-test-flat-format"
+
+var flatSchemaVersion = regexp.MustCompile(regexp.QuoteMeta(flatSchemaID) + `/v(\d+)`)
+
+// FlatSchema returns the $schema value that stamps a generated file with its
+// format version
+func FlatSchema(version int) string {
+	return fmt.Sprintf("%s/v%d", flatSchemaID, version)
+}
+
+// UnsupportedFormatVersionError reports a generated file written in a format
+// version this library does not understand
+type UnsupportedFormatVersionError struct {
+	Version int
+}
+
+func (e *UnsupportedFormatVersionError) Error() string {
+	return fmt.Sprintf("unsupported generated format version %d (supported: %v); upgrade ccl-test-lib", e.Version, SupportedFormatVersions)
+}
+
+// formatAdapters rewrite a test from an older or newer format version into
+// the field names TestCase decodes
+var formatAdapters = map[int]func(test map[string]json.RawMessage){
+	FormatVersion1: nil,
+	FormatVersion2: renameFields(map[string]string{"function": "validation", "expect": "expected"}),
+}
+
+func renameFields(renames map[string]string) func(map[string]json.RawMessage) {
+	return func(test map[string]json.RawMessage) {
+		for from, to := range renames {
+			if value, ok := test[from]; ok {
+				delete(test, from)
+				test[to] = value
+			}
+		}
+	}
+}
+
+// DetectFormatVersion reports the format version of a generated file: its
+// format_version field if set, otherwise the version in its $schema.
+// Unversioned files, including bare test arrays, are version 1.
+func DetectFormatVersion(data []byte) (int, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return FormatVersion1, nil // A bare array of tests
+	}
+	var header struct {
+		Schema        string `json:"$schema"`
+		FormatVersion int    `json:"format_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("failed to parse flat format JSON: %w", err)
+	}
+	if header.FormatVersion != 0 {
+		return header.FormatVersion, nil
+	}
+	if match := flatSchemaVersion.FindStringSubmatch(header.Schema); match != nil {
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid format version in $schema %q", header.Schema)
+		}
+		return version, nil
+	}
+	return FormatVersion1, nil
+}
+
+// ReadGeneratorInfo returns the generator block of a generated file, or nil
+// if it has none
+func ReadGeneratorInfo(data []byte) (*types.GeneratorInfo, error) {
+	if trimmed := bytes.TrimSpace(StripBOM(data)); len(trimmed) > 0 && trimmed[0] == '[' {
+		return nil, nil // A bare array of tests
+	}
+	var header struct {
+		Generator *types.GeneratorInfo `json:"generator"`
+	}
+	if err := json.Unmarshal(StripBOM(data), &header); err != nil {
+		return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
+	}
+	return header.Generator, nil
+}
+
+// upgradeFlat returns data with its tests rewritten to the field names
+// TestCase decodes, or an UnsupportedFormatVersionError
+func upgradeFlat(data []byte) ([]byte, error) {
+	version, err := DetectFormatVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	adapter, ok := formatAdapters[version]
+	if !ok {
+		return nil, &UnsupportedFormatVersionError{Version: version}
+	}
+	if adapter == nil {
+		return data, nil
+	}
+
+	var file map[string]json.RawMessage
+	var tests []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
+	}
+	if err := json.Unmarshal(file["tests"], &tests); err != nil {
+		return nil, fmt.Errorf("failed to parse flat format JSON: %w", err)
+	}
+	for _, test := range tests {
+		adapter(test)
+	}
+	// Keep the other top-level fields, such as file-level requirements
+	adapted, err := json.Marshal(tests)
+	if err != nil {
+		return nil, err
+	}
+	file["tests"] = adapted
+	return json.Marshal(file)
+}
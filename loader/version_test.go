@@ -0,0 +1,98 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+func TestDetectFormatVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"draft-07 schema", `{"$schema": "http://json-schema.org/draft-07/schema#", "tests": []}`, FormatVersion1},
+		{"no schema", `{"tests": []}`, FormatVersion1},
+		{"bare array", ` [{"name": "a"}]`, FormatVersion1},
+		{"versioned schema", `{"$schema": "ccl-test-flat-format/v2", "tests": []}`, FormatVersion2},
+		{"versioned schema URL", `{"$schema": "https://example.com/schemas/ccl-test-flat-format/v3.json", "tests": []}`, 3},
+		{"format_version wins", `{"$schema": "ccl-test-flat-format/v1", "format_version": 2, "tests": []}`, FormatVersion2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectFormatVersion([]byte(tt.data))
+			if err != nil || got != tt.want {
+				t.Errorf("Expected version %d, got %d, %v", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := DetectFormatVersion([]byte(`{"tests": [`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestTestLoader_LoadTestFile_FormatVersions(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := map[string]string{
+		"v1.json": `{"$schema": "ccl-test-flat-format/v1", "tests": [
+			{"name": "get_name", "inputs": ["name = Alice"], "validation": "get_string", "args": ["name"], "expected": {"count": 1, "value": "Alice"}}
+		]}`,
+		"v2.json": `{"$schema": "ccl-test-flat-format/v2", "tests": [
+			{"name": "get_name", "inputs": ["name = Alice"], "function": "get_string", "args": ["name"], "expect": {"count": 1, "value": "Alice"}}
+		]}`,
+		"v99.json": `{"format_version": 99, "tests": [
+			{"name": "get_name", "inputs": ["name = Alice"], "check": "get_string"}
+		]}`,
+	}
+	for name, content := range fixtures {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+
+	loader := NewTestLoader(dir, config.ImplementationConfig{})
+	for _, name := range []string{"v1.json", "v2.json"} {
+		for _, lazy := range []bool{false, true} {
+			suite, err := loader.LoadTestFile(filepath.Join(dir, name), LoadOptions{Format: FormatFlat, LazyExpected: lazy})
+			if err != nil {
+				t.Fatalf("%s: failed to load: %v", name, err)
+			}
+			if len(suite.Tests) != 1 {
+				t.Fatalf("%s: expected 1 test, got %d", name, len(suite.Tests))
+			}
+			test := suite.Tests[0]
+			if test.Validation != "get_string" || test.ExpectedValue() != "Alice" {
+				t.Errorf("%s (lazy %v): expected get_string = Alice, got %s = %v", name, lazy, test.Validation, test.ExpectedValue())
+			}
+		}
+	}
+
+	_, err := loader.LoadTestFile(filepath.Join(dir, "v99.json"), LoadOptions{Format: FormatFlat})
+	var unsupported *UnsupportedFormatVersionError
+	if !errors.As(err, &unsupported) || unsupported.Version != 99 {
+		t.Fatalf("Expected UnsupportedFormatVersionError for v99, got %v", err)
+	}
+	if want := "unsupported generated format version 99 (supported: [1 2]); upgrade ccl-test-lib"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestReadGeneratorInfo(t *testing.T) {
+	info, err := ReadGeneratorInfo([]byte(`{"generator": {"version": "v0.1.0", "options_fingerprint": "abc"}, "tests": []}`))
+	if err != nil || info == nil || info.Version != "v0.1.0" || info.OptionsFingerprint != "abc" {
+		t.Errorf("Unexpected generator info %+v (%v)", info, err)
+	}
+	for _, data := range []string{`{"tests": []}`, `[]`} {
+		if info, err := ReadGeneratorInfo([]byte(data)); err != nil || info != nil {
+			t.Errorf("Expected no generator info in %s, got %+v (%v)", data, info, err)
+		}
+	}
+	if _, err := ReadGeneratorInfo([]byte(`{`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,48 @@
+package ccl_test_lib
+
+import (
+	"os"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// matrixReadFile reads test files for RunMatrix (replaced in tests to count reads)
+var matrixReadFile = os.ReadFile
+
+// RunMatrix computes statistics and runs the compatible tests for each config.
+// The test data is loaded once and shared across configs. If impl is nil only
+// statistics are computed. Loading errors are those of GetTestStats.
+func RunMatrix(testDataPath string, configs []config.ImplementationConfig, impl runner.CCLImplementation, opts ...Option) (*runner.Matrix, error) {
+	defaults := defaultOptions()
+	defaults.load.FilterMode = loader.FilterAll
+	o := applyOptions(defaults, opts)
+
+	sharedLoader := NewLoader(testDataPath, config.ImplementationConfig{}, opts...)
+	sharedLoader.ReadFile = matrixReadFile
+	allTests, err := loadTests(sharedLoader, o)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &runner.Matrix{
+		TotalTests: len(allTests),
+		Entries:    make([]runner.MatrixEntry, 0, len(configs)),
+	}
+	for _, cfg := range configs {
+		// Filtering works on the shared tests; no further file reads
+		configLoader := NewLoader(testDataPath, cfg, opts...)
+		entry := runner.MatrixEntry{
+			Config:     cfg,
+			Statistics: configLoader.GetTestStatistics(allTests),
+		}
+		if impl != nil {
+			compatible := configLoader.FilterCompatibleTests(allTests)
+			entry.Results = runner.Run(impl, compatible, runner.RunOptions{})
+		}
+		matrix.Entries = append(matrix.Entries, entry)
+	}
+
+	return matrix, nil
+}
@@ -0,0 +1,161 @@
+package ccl_test_lib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// setupMatrixTestData writes two compact files and generates their flat format
+func setupMatrixTestData(t *testing.T) string {
+	t.Helper()
+	dataDir := t.TempDir()
+	sourceDir := filepath.Join(dataDir, "tests")
+	generatedDir := filepath.Join(dataDir, "generated_tests")
+	for _, dir := range []string{sourceDir, generatedDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	files := map[string][]loader.CompactTest{
+		"basic.json": {
+			{
+				Name:   "typed_values",
+				Inputs: []string{"basic = true\ncount = 42"},
+				Tests: []loader.CompactValidation{
+					{Function: "parse", Expect: []map[string]interface{}{
+						{"key": "basic", "value": "true"},
+						{"key": "count", "value": "42"},
+					}},
+					{Function: "get_bool", Args: []string{"basic"}, Expect: true},
+					{Function: "get_int", Args: []string{"count"}, Expect: 42},
+				},
+			},
+		},
+		"comments.json": {
+			{
+				Name:     "comment_filter",
+				Inputs:   []string{"key = value\n/= comment"},
+				Features: []string{"comments"},
+				Tests: []loader.CompactValidation{
+					{Function: "filter", Expect: []map[string]interface{}{{"key": "key", "value": "value"}}},
+				},
+			},
+		},
+	}
+	for name, tests := range files {
+		data, _ := json.MarshalIndent(loader.CompactTestFile{Tests: tests}, "", "  ")
+		if err := os.WriteFile(filepath.Join(sourceDir, name), data, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	gen := generator.NewFlatGenerator(sourceDir, generatedDir, generator.GenerateOptions{
+		SourceFormat: generator.FormatCompact,
+	})
+	if err := gen.GenerateAll(); err != nil {
+		t.Fatalf("Generation failed: %v", err)
+	}
+	return dataDir
+}
+
+// createMatrixConfigs returns three profiles, each supporting more than the last
+func createMatrixConfigs() []config.ImplementationConfig {
+	return []config.ImplementationConfig{
+		{
+			Name:               "minimal-ccl",
+			Version:            "v1.0.0",
+			SupportedFunctions: []config.CCLFunction{config.FunctionParse},
+		},
+		{
+			Name:    "typed-ccl",
+			Version: "v1.0.0",
+			SupportedFunctions: []config.CCLFunction{
+				config.FunctionParse, config.FunctionGetBool, config.FunctionGetInt,
+			},
+		},
+		{
+			Name:    "featured-ccl",
+			Version: "v1.0.0",
+			SupportedFunctions: []config.CCLFunction{
+				config.FunctionParse, config.FunctionGetBool, config.FunctionGetInt, config.FunctionFilter,
+			},
+			SupportedFeatures: []config.CCLFeature{config.FeatureComments},
+		},
+	}
+}
+
+func TestRunMatrix_MonotonicCompatibility(t *testing.T) {
+	dataDir := setupMatrixTestData(t)
+
+	matrix, err := RunMatrix(dataDir, createMatrixConfigs(), toyccl.New())
+	if err != nil {
+		t.Fatalf("RunMatrix failed: %v", err)
+	}
+	if len(matrix.Entries) != 3 {
+		t.Fatalf("Expected 3 matrix entries, got %d", len(matrix.Entries))
+	}
+	if matrix.TotalTests != 4 {
+		t.Errorf("Expected 4 total tests, got %d", matrix.TotalTests)
+	}
+
+	expectedCompatible := []int{1, 3, 4}
+	for i, entry := range matrix.Entries {
+		if entry.Statistics.CompatibleTests != expectedCompatible[i] {
+			t.Errorf("%s: expected %d compatible tests, got %d",
+				entry.Config.Name, expectedCompatible[i], entry.Statistics.CompatibleTests)
+		}
+		if i > 0 && entry.Statistics.CompatibleTests < matrix.Entries[i-1].Statistics.CompatibleTests {
+			t.Errorf("%s has fewer compatible tests than %s", entry.Config.Name, matrix.Entries[i-1].Config.Name)
+		}
+		if entry.Results == nil {
+			t.Fatalf("%s: expected run results", entry.Config.Name)
+		}
+		if entry.Results.Passed != entry.Statistics.CompatibleTests {
+			t.Errorf("%s: expected every compatible test to pass, got %d/%d: %+v",
+				entry.Config.Name, entry.Results.Passed, entry.Statistics.CompatibleTests, entry.Results.Failures())
+		}
+	}
+}
+
+func TestRunMatrix_LoadsTestDataOnce(t *testing.T) {
+	dataDir := setupMatrixTestData(t)
+
+	reads := make(map[string]int)
+	original := matrixReadFile
+	matrixReadFile = func(name string) ([]byte, error) {
+		reads[name]++
+		return original(name)
+	}
+	t.Cleanup(func() { matrixReadFile = original })
+
+	if _, err := RunMatrix(dataDir, createMatrixConfigs(), nil); err != nil {
+		t.Fatalf("RunMatrix failed: %v", err)
+	}
+
+	if len(reads) != 2 {
+		t.Errorf("Expected 2 files read, got %d: %v", len(reads), reads)
+	}
+	for name, count := range reads {
+		if count != 1 {
+			t.Errorf("%s read %d times, expected once", filepath.Base(name), count)
+		}
+	}
+}
+
+func TestRunMatrix_StatisticsOnly(t *testing.T) {
+	matrix, err := RunMatrix(setupMatrixTestData(t), createMatrixConfigs()[:1], nil)
+	if err != nil {
+		t.Fatalf("RunMatrix failed: %v", err)
+	}
+	if matrix.Entries[0].Results != nil {
+		t.Error("Expected no run results without an implementation")
+	}
+}
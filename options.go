@@ -0,0 +1,132 @@
+package ccl_test_lib
+
+import (
+	"log/slog"
+
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Option customizes the top-level convenience functions. Options are applied
+// in order, so a later option overrides an earlier one that sets the same field.
+type Option func(*options)
+
+// options collects everything an Option can set
+type options struct {
+	load           loader.LoadOptions
+	generate       generator.GenerateOptions
+	logger         *slog.Logger
+	allowEmpty     bool
+	sourceFallback bool
+	sanitize       *types.SanitizePolicy
+}
+
+// WithFormat selects the test format to load (flat by default)
+func WithFormat(format loader.TestFormat) Option {
+	return func(o *options) { o.load.Format = format }
+}
+
+// WithFilterMode selects how loaded tests are filtered
+func WithFilterMode(mode loader.FilterMode) Option {
+	return func(o *options) { o.load.FilterMode = mode }
+}
+
+// WithLoadOptions replaces the load options entirely
+func WithLoadOptions(opts loader.LoadOptions) Option {
+	return func(o *options) { o.load = opts }
+}
+
+// WithGenerateOptions replaces the generation options entirely, including
+// the Verbose default
+func WithGenerateOptions(opts generator.GenerateOptions) Option {
+	return func(o *options) { o.generate = opts }
+}
+
+// WithLogger sets the logger used by the loader and generator
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithAllowEmpty makes missing or empty test data, and finding no compatible
+// tests, return empty results instead of ErrNoTestData or loader.ErrNoTests
+func WithAllowEmpty() Option {
+	return func(o *options) { o.allowEmpty = true }
+}
+
+// WithSourceFallback makes loading flat tests from a data set without
+// generated_tests flatten its source_tests in memory instead, as the
+// generator would (see TestLoader.Flatten). Such tests have Meta.Flattened
+// set and TestStatistics.SourceFallback reports it.
+func WithSourceFallback() Option {
+	return func(o *options) { o.sourceFallback = true }
+}
+
+// WithDedup makes name lookups use the first of several same-named tests
+// instead of failing as ambiguous
+func WithDedup() Option {
+	return func(o *options) { o.load.Dedup = true }
+}
+
+// WithInputPredicates keeps only tests with an input matching all of the
+// predicates (see loader.InputPredicate)
+func WithInputPredicates(predicates ...loader.InputPredicate) Option {
+	return func(o *options) { o.load.InputPredicates = append(o.load.InputPredicates, predicates...) }
+}
+
+// WithSkipSlow leaves out tests whose limits mark them slow, for quick runs
+func WithSkipSlow() Option {
+	return func(o *options) { o.load.SkipSlow = true }
+}
+
+// WithShard keeps only the tests in shard index of count, for splitting a
+// run across CI jobs (see loader.ShardOf). Shards are numbered from 0.
+func WithShard(index, count int) Option {
+	return func(o *options) {
+		o.load.ShardIndex = index
+		o.load.ShardCount = count
+	}
+}
+
+// WithSample keeps a stratified sample of size tests, at least one per
+// validation when size allows, chosen by seed (see loader.SampleTests)
+func WithSample(size int, seed int64) Option {
+	return func(o *options) {
+		o.load.SampleSize = size
+		o.load.SampleSeed = seed
+	}
+}
+
+// WithProgress reports per-file progress while loading and generating (see
+// loader.ProgressFunc)
+func WithProgress(fn loader.ProgressFunc) Option {
+	return func(o *options) {
+		o.load.Progress = fn
+		o.generate.Progress = fn
+	}
+}
+
+// WithSanitize makes ExportCompatibleTests strip test metadata as policy
+// says before writing (see types.Sanitize)
+func WithSanitize(policy types.SanitizePolicy) Option {
+	return func(o *options) { o.sanitize = &policy }
+}
+
+// applyOptions applies opts over the defaults a function starts from
+func applyOptions(defaults options, opts []Option) options {
+	for _, opt := range opts {
+		opt(&defaults)
+	}
+	if defaults.logger != nil {
+		defaults.generate.Logger = defaults.logger
+	}
+	return defaults
+}
+
+// defaultOptions is how the convenience functions behave without options
+func defaultOptions() options {
+	return options{
+		load:     loader.LoadOptions{Format: loader.FormatFlat, FilterMode: loader.FilterCompatible},
+		generate: generator.GenerateOptions{Verbose: true},
+	}
+}
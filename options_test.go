@@ -0,0 +1,319 @@
+package ccl_test_lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func parseOnlyConfig() config.ImplementationConfig {
+	return config.ImplementationConfig{
+		Name:               "parse-only",
+		Version:            "v1.0.0",
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse},
+	}
+}
+
+func TestOptions_FilterMode(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+
+	compatible, err := LoadCompatibleTests(testDataPath, parseOnlyConfig())
+	if err != nil || len(compatible) != 1 {
+		t.Fatalf("Expected 1 compatible test by default, got %d, %v", len(compatible), err)
+	}
+	all, err := LoadCompatibleTests(testDataPath, parseOnlyConfig(), WithFilterMode(loader.FilterAll))
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected 2 tests with FilterAll, got %d, %v", len(all), err)
+	}
+
+	stats, err := GetTestStats(testDataPath, parseOnlyConfig(), WithFilterMode(loader.FilterCompatible))
+	if err != nil || stats.TotalTests != 1 {
+		t.Errorf("Expected statistics over 1 compatible test, got %d, %v", stats.TotalTests, err)
+	}
+}
+
+func TestOptions_Format(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+
+	tests, err := LoadCompatibleTests(testDataPath, createTestImplementationConfig(),
+		WithFormat(loader.FormatCompact), WithFilterMode(loader.FilterAll))
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) == 0 {
+		t.Fatal("Expected source tests to be loaded")
+	}
+	for _, test := range tests {
+		if !strings.HasPrefix(test.Name, "integration_test_") {
+			t.Errorf("Expected only source tests, got %s", test.Name)
+		}
+	}
+}
+
+func TestOptions_LoadOptions(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+
+	tests, err := LoadCompatibleTests(testDataPath, createTestImplementationConfig(), WithLoadOptions(loader.LoadOptions{
+		Format:       loader.FormatFlat,
+		FilterMode:   loader.FilterCustom,
+		CustomFilter: func(test types.TestCase) bool { return test.Validation == "get_string" },
+	}))
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Name != "flat_test_get_string" {
+		t.Errorf("Expected only flat_test_get_string, got %+v", tests)
+	}
+}
+
+func TestOptions_SkipSlow(t *testing.T) {
+	testDataPath := t.TempDir()
+	os.MkdirAll(filepath.Join(testDataPath, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "quick_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "blowup_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "limits": {"slow": true}}
+	]}`
+	os.WriteFile(filepath.Join(testDataPath, "generated_tests", "api.json"), []byte(flat), 0644)
+
+	tests, err := LoadCompatibleTests(testDataPath, parseOnlyConfig(), WithSkipSlow())
+	if err != nil {
+		t.Fatalf("LoadCompatibleTests failed: %v", err)
+	}
+	if len(tests) != 1 || tests[0].Name != "quick_parse" {
+		t.Errorf("Expected only quick_parse, got %+v", tests)
+	}
+}
+
+func TestOptions_GenerateOptions(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+	outputDir := filepath.Join(t.TempDir(), "generated")
+
+	err := GenerateFlat(filepath.Join(testDataPath, testsupport.SourceDir), outputDir, WithGenerateOptions(generator.GenerateOptions{
+		SourceFormat:  loader.FormatCompact,
+		OnlyFunctions: []config.CCLFunction{config.FunctionGetInt},
+	}))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "integration.json"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	var suite types.TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("Failed to parse generated file: %v", err)
+	}
+	if len(suite.Tests) != 2 {
+		t.Fatalf("Expected the 2 get_int tests, got %d", len(suite.Tests))
+	}
+	for _, test := range suite.Tests {
+		if test.Validation != "get_int" {
+			t.Errorf("Expected only get_int tests, got %s", test.Validation)
+		}
+	}
+}
+
+func TestOptions_SourceFallback(t *testing.T) {
+	sources := func() *testsupport.Fixture {
+		return testsupport.NewFixture(t).
+			WithCompactFile("api_basic.json", loader.CompactTest{
+				Name:   "basic",
+				Inputs: []string{"a = b\nc = d"},
+				Tests: []loader.CompactValidation{
+					{Function: "parse", Expect: []map[string]interface{}{{"key": "a", "value": "b"}, {"key": "c", "value": "d"}}},
+					{Function: "get_string", Args: []string{"c"}, Expect: "d"},
+					{Function: "build_hierarchy", Expect: map[string]interface{}{"a": "b", "c": "d"}},
+				},
+			}).
+			WithCompactFile("api_comments.json", loader.CompactTest{
+				Name:     "comment_filter",
+				Inputs:   []string{"/= note\na = b"},
+				Features: []string{"comments"},
+				Tests: []loader.CompactValidation{
+					{Function: "filter", Expect: []map[string]interface{}{{"key": "a", "value": "b"}}},
+					{Function: "get_int", Args: []string{"a"}, Expect: 1, Error: true},
+				},
+			})
+	}
+	sourceOnly := sources().Build()
+	generated := sources().WithGenerated().Build()
+	cfg := config.ImplementationConfig{
+		Name:               "full",
+		SupportedFunctions: config.AllFunctions(),
+		SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+	}
+
+	if _, err := LoadCompatibleTests(sourceOnly, cfg); !errors.Is(err, ErrNoTestData) {
+		t.Errorf("Expected ErrNoTestData without the fallback, got %v", err)
+	}
+
+	want, err := LoadCompatibleTests(generated, cfg)
+	if err != nil {
+		t.Fatalf("Failed to load generated tests: %v", err)
+	}
+	got, err := LoadCompatibleTests(sourceOnly, cfg, WithSourceFallback())
+	if err != nil {
+		t.Fatalf("Failed to load with the fallback: %v", err)
+	}
+	for i := range got {
+		if !got[i].Meta.Flattened {
+			t.Errorf("Expected %s to be marked as flattened", got[i].Name)
+		}
+		got[i].Meta.Flattened = false
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Fallback tests differ from generated ones:\n got %+v\nwant %+v", got, want)
+	}
+
+	// Existing generated tests are used as they are
+	tests, err := LoadCompatibleTests(generated, cfg, WithSourceFallback())
+	if err != nil || len(tests) != len(want) || tests[0].Meta.Flattened {
+		t.Errorf("Expected generated tests to be loaded directly, got %d tests (%v)", len(tests), err)
+	}
+
+	stats, err := GetTestStats(sourceOnly, cfg, WithSourceFallback())
+	if err != nil {
+		t.Fatalf("GetTestStats failed: %v", err)
+	}
+	if !stats.SourceFallback || stats.TotalTests != len(want) {
+		t.Errorf("Expected fallback statistics over %d tests, got %+v", len(want), stats)
+	}
+	if stats, _ := GetTestStats(generated, cfg); stats.SourceFallback {
+		t.Error("Expected no fallback noted for generated tests")
+	}
+}
+
+func TestOptions_Logger(t *testing.T) {
+	testDataPath := setupIntegrationTestData(t)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if _, err := GetTestStats(testDataPath, parseOnlyConfig(), WithLogger(logger)); err != nil {
+		t.Fatalf("GetTestStats failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), "msg=\"parsed test file\"") {
+		t.Errorf("Expected the loader to log, got:\n%s", logs.String())
+	}
+
+	logs.Reset()
+	err := GenerateFlat(filepath.Join(testDataPath, testsupport.SourceDir), t.TempDir(),
+		WithGenerateOptions(generator.GenerateOptions{SourceFormat: loader.FormatCompact}), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("GenerateFlat failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), "msg=\"generated flat file\"") {
+		t.Errorf("Expected the generator to log, got:\n%s", logs.String())
+	}
+
+	if NewLoader(testDataPath, parseOnlyConfig(), WithLogger(logger)).Logger != logger {
+		t.Error("Expected NewLoader to use the logger")
+	}
+}
+
+// setupLoaderDefaultsData writes flat tests telling each load option apart,
+// with a_parse in two files, and one source test
+func setupLoaderDefaultsData(t *testing.T) string {
+	t.Helper()
+	testDataPath := t.TempDir()
+	os.MkdirAll(filepath.Join(testDataPath, loader.DefaultGeneratedDir), 0755)
+	os.MkdirAll(filepath.Join(testDataPath, loader.DefaultSourceDir), 0755)
+	files := map[string]string{
+		filepath.Join(loader.DefaultGeneratedDir, "a.json"): `{"tests": [
+			{"name": "a_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+			{"name": "b_get_string", "inputs": ["a = x"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "x"}},
+			{"name": "c_slow_parse", "inputs": ["a = 2"], "validation": "parse", "expected": {"count": 1}, "limits": {"slow": true}},
+			{"name": "d_tabs_parse", "inputs": ["a =\tb"], "validation": "parse", "expected": {"count": 1}}
+		]}`,
+		filepath.Join(loader.DefaultGeneratedDir, "b.json"): `{"tests": [
+			{"name": "a_parse", "inputs": ["a = 3"], "validation": "parse", "expected": {"count": 1}}
+		]}`,
+		filepath.Join(loader.DefaultSourceDir, "source.json"): `{"tests": [
+			{"name": "source_pair", "inputs": ["a = 1"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "1"}]}]}
+		]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(testDataPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return testDataPath
+}
+
+func TestOptions_NewLoaderDefaults(t *testing.T) {
+	testDataPath := setupLoaderDefaultsData(t)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"defaults", nil, []string{"a_parse", "a_parse", "c_slow_parse", "d_tabs_parse"}},
+		{"filter mode", []Option{WithFilterMode(loader.FilterAll)}, []string{"a_parse", "a_parse", "b_get_string", "c_slow_parse", "d_tabs_parse"}},
+		{"format", []Option{WithFormat(loader.FormatCompact), WithFilterMode(loader.FilterAll)}, []string{"source_pair"}},
+		{"load options", []Option{WithLoadOptions(loader.LoadOptions{
+			Format:       loader.FormatFlat,
+			FilterMode:   loader.FilterCustom,
+			CustomFilter: func(test types.TestCase) bool { return test.Validation == "get_string" },
+		})}, []string{"b_get_string"}},
+		{"skip slow", []Option{WithSkipSlow()}, []string{"a_parse", "a_parse", "d_tabs_parse"}},
+		{"input predicates", []Option{WithInputPredicates(loader.ContainsTabs())}, []string{"d_tabs_parse"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := NewLoader(testDataPath, parseOnlyConfig(), tt.opts...).LoadAllTests(loader.LoadOptions{})
+			if err != nil {
+				t.Fatalf("LoadAllTests failed: %v", err)
+			}
+			var names []string
+			for _, test := range loaded {
+				names = append(names, test.Name)
+			}
+			slices.Sort(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+
+	// Shards split the default tests between them
+	total := 0
+	for index := range 2 {
+		shard, err := NewLoader(testDataPath, parseOnlyConfig(), WithShard(index, 2)).LoadAllTests(loader.LoadOptions{})
+		if err != nil {
+			t.Fatalf("LoadAllTests failed for shard %d: %v", index, err)
+		}
+		if len(shard) == 4 {
+			t.Errorf("Expected shard %d to hold part of the tests, got all of them", index)
+		}
+		total += len(shard)
+	}
+	if total != 4 {
+		t.Errorf("Expected the shards to hold 4 tests together, got %d", total)
+	}
+
+	sample, err := NewLoader(testDataPath, parseOnlyConfig(), WithSample(1, 7)).LoadAllTests(loader.LoadOptions{})
+	if err != nil || len(sample) != 1 {
+		t.Errorf("Expected a sample of 1 test, got %d, %v", len(sample), err)
+	}
+
+	if _, _, err := NewLoader(testDataPath, parseOnlyConfig()).GetTestByName("a_parse", loader.LoadOptions{}); err == nil {
+		t.Error("Expected an ambiguous name without WithDedup")
+	}
+	if test, ok, err := NewLoader(testDataPath, parseOnlyConfig(), WithDedup()).GetTestByName("a_parse", loader.LoadOptions{}); err != nil || !ok || test.Inputs[0] != "a = 1" {
+		t.Errorf("Expected WithDedup to pick the first a_parse, got %+v, %t, %v", test, ok, err)
+	}
+}
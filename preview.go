@@ -0,0 +1,56 @@
+package ccl_test_lib
+
+import (
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// ConfigPreview is how a config change would change the tests an
+// implementation runs
+type ConfigPreview struct {
+	Config       config.ConfigDiff `json:"config"`
+	Gained       []PreviewTest     `json:"gained"`       // Compatible only with the new config
+	Lost         []PreviewTest     `json:"lost"`         // Compatible only with the old config
+	Unchanged    int               `json:"unchanged"`    // Compatible with both
+	Incompatible int               `json:"incompatible"` // Compatible with neither
+}
+
+// PreviewTest is a test whose compatibility a config change flips
+type PreviewTest struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"` // Why the config that cannot run it rejects it
+}
+
+// PreviewConfigChange reports which tests switching from the before config
+// to after would start and stop running, without running anything. Tests
+// are loaded once for both configs. Errors are as for GetTestStats.
+func PreviewConfigChange(testDataPath string, before, after config.ImplementationConfig, opts ...Option) (*ConfigPreview, error) {
+	defaults := defaultOptions()
+	defaults.load.FilterMode = loader.FilterAll
+	defaults.load.LazyExpected = true
+	o := applyOptions(defaults, opts)
+
+	beforeLoader := NewLoader(testDataPath, before, opts...)
+	tests, err := loadTests(beforeLoader, o)
+	if err != nil {
+		return nil, err
+	}
+	afterLoader := NewLoader(testDataPath, after, opts...)
+
+	preview := &ConfigPreview{Config: config.Diff(before, after), Gained: []PreviewTest{}, Lost: []PreviewTest{}}
+	for _, test := range tests {
+		beforeReason := beforeLoader.ExplainCompatibility(test)
+		afterReason := afterLoader.ExplainCompatibility(test)
+		switch {
+		case beforeReason == "" && afterReason == "":
+			preview.Unchanged++
+		case beforeReason != "" && afterReason != "":
+			preview.Incompatible++
+		case afterReason == "":
+			preview.Gained = append(preview.Gained, PreviewTest{Name: test.Name, Reason: beforeReason})
+		default:
+			preview.Lost = append(preview.Lost, PreviewTest{Name: test.Name, Reason: afterReason})
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+)
+
+// matrixReadFile reads test files for BuildMatrix; tests count the reads
+var matrixReadFile = os.ReadFile
+
+// CapabilityMatrix compares several implementations capability by capability
+type CapabilityMatrix struct {
+	TotalTests      int                    `json:"total_tests"`
+	Implementations []ImplementationColumn `json:"implementations"` // Sorted by name
+	Capabilities    []CapabilityRow        `json:"capabilities"`
+}
+
+// ImplementationColumn summarizes one implementation across all tests
+type ImplementationColumn struct {
+	Name       string  `json:"name"`
+	Compatible int     `json:"compatible"`
+	Percent    float64 `json:"percent"`
+}
+
+// CapabilityRow is one known function or feature. Every capability is
+// listed, including those no implementation supports.
+type CapabilityRow struct {
+	Name  string                    `json:"name"`
+	Kind  string                    `json:"kind"`  // "function" or "feature"
+	Tests int                       `json:"tests"` // Tests exercising the capability
+	Cells map[string]CapabilityCell `json:"cells"` // By implementation name
+}
+
+// CapabilityCell is one implementation's coverage of one capability
+type CapabilityCell struct {
+	Supported  bool    `json:"supported"`
+	Compatible int     `json:"compatible"` // Of the row's tests, those the implementation can run
+	Percent    float64 `json:"percent"`
+}
+
+// BuildMatrix loads the flat tests under testDataPath once and compares
+// the capability coverage of each named implementation config
+func BuildMatrix(testDataPath string, configs map[string]config.ImplementationConfig) (*CapabilityMatrix, error) {
+	sharedLoader := loader.NewTestLoader(testDataPath, config.ImplementationConfig{})
+	sharedLoader.ReadFile = matrixReadFile
+	tests, err := sharedLoader.LoadAllTests(loader.LoadOptions{
+		Format:       loader.FormatFlat,
+		FilterMode:   loader.FilterAll,
+		LazyExpected: true, // Coverage never reads Expected
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Available counts come from a config claiming every capability
+	everything := loader.NewTestLoader(testDataPath, config.ImplementationConfig{
+		SupportedFunctions: config.AllFunctions(),
+		SupportedFeatures:  config.AllFeatures(),
+	}).CoverageOf(tests)
+	matrix := &CapabilityMatrix{TotalTests: len(tests)}
+	for _, fn := range config.AllFunctions() {
+		matrix.Capabilities = append(matrix.Capabilities, CapabilityRow{
+			Name: string(fn), Kind: "function", Tests: everything.Functions[fn].Available, Cells: make(map[string]CapabilityCell),
+		})
+	}
+	for _, feature := range config.AllFeatures() {
+		matrix.Capabilities = append(matrix.Capabilities, CapabilityRow{
+			Name: string(feature), Kind: "feature", Tests: everything.Features[feature].Available, Cells: make(map[string]CapabilityCell),
+		})
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		configLoader := loader.NewTestLoader(testDataPath, configs[name])
+		compatible := 0
+		for _, ok := range configLoader.Compatibility(tests) {
+			if ok {
+				compatible++
+			}
+		}
+		matrix.Implementations = append(matrix.Implementations, ImplementationColumn{
+			Name: name, Compatible: compatible, Percent: percent(compatible, len(tests)),
+		})
+
+		coverage := configLoader.CoverageOf(tests)
+		for i, row := range matrix.Capabilities {
+			var info loader.CoverageInfo
+			var supported bool
+			if row.Kind == "function" {
+				info, supported = coverage.Functions[config.CCLFunction(row.Name)]
+			} else {
+				info, supported = coverage.Features[config.CCLFeature(row.Name)]
+			}
+			matrix.Capabilities[i].Cells[name] = CapabilityCell{
+				Supported:  supported,
+				Compatible: info.Compatible,
+				Percent:    percent(info.Compatible, row.Tests),
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// CapabilityMatrixMarkdown renders a capability matrix as a markdown table
+// with one column per implementation; "-" marks an unsupported capability
+func CapabilityMatrixMarkdown(matrix *CapabilityMatrix) string {
+	var b strings.Builder
+	b.WriteString("| Capability | Kind | Tests |")
+	for _, impl := range matrix.Implementations {
+		fmt.Fprintf(&b, " %s |", escapeCell(impl.Name))
+	}
+	b.WriteString("\n|---|---|---:|" + strings.Repeat("---:|", len(matrix.Implementations)) + "\n")
+
+	fmt.Fprintf(&b, "| All tests | - | %d |", matrix.TotalTests)
+	for _, impl := range matrix.Implementations {
+		fmt.Fprintf(&b, " %d (%.1f%%) |", impl.Compatible, impl.Percent)
+	}
+	b.WriteString("\n")
+	for _, row := range matrix.Capabilities {
+		fmt.Fprintf(&b, "| %s | %s | %d |", escapeCell(row.Name), row.Kind, row.Tests)
+		for _, impl := range matrix.Implementations {
+			cell := row.Cells[impl.Name]
+			if !cell.Supported {
+				b.WriteString(" - |")
+				continue
+			}
+			fmt.Fprintf(&b, " %d (%.1f%%) |", cell.Compatible, cell.Percent)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// percent returns part as a percentage of whole, or 0 when whole is 0
+func percent(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// setupCapabilityData writes four flat tests: two parse, one get_string,
+// and one comments parse test
+func setupCapabilityData(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(`{"tests": [
+		{"name": "a_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "b_parse", "inputs": ["b = 2"], "validation": "parse", "expected": {"count": 1}},
+		{"name": "c_get_string", "inputs": ["c = 3"], "validation": "get_string", "args": ["c"], "expected": {"count": 1, "value": "3"}}
+	]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "generated_tests", "comments.json"), []byte(`{"tests": [
+		{"name": "d_parse", "inputs": ["/= x\nd = 4"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"]}
+	]}`), 0644)
+	return dir
+}
+
+func createCapabilityConfigs() map[string]config.ImplementationConfig {
+	return map[string]config.ImplementationConfig{
+		"ccl-go": {
+			SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionGetString},
+			SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+		},
+		"ccl-rs": {
+			SupportedFunctions: []config.CCLFunction{config.FunctionParse},
+		},
+	}
+}
+
+func TestBuildMatrix(t *testing.T) {
+	dir := setupCapabilityData(t)
+	reads := make(map[string]int)
+	original := matrixReadFile
+	matrixReadFile = func(name string) ([]byte, error) {
+		reads[name]++
+		return original(name)
+	}
+	t.Cleanup(func() { matrixReadFile = original })
+
+	matrix, err := BuildMatrix(dir, createCapabilityConfigs())
+	if err != nil {
+		t.Fatalf("BuildMatrix failed: %v", err)
+	}
+	for name, count := range reads {
+		if count != 1 {
+			t.Errorf("%s read %d times, expected once", filepath.Base(name), count)
+		}
+	}
+
+	if matrix.TotalTests != 4 || len(matrix.Implementations) != 2 {
+		t.Fatalf("Expected 4 tests and 2 implementations, got %+v", matrix)
+	}
+	if impl := matrix.Implementations[1]; impl.Name != "ccl-rs" || impl.Compatible != 2 || impl.Percent != 50 {
+		t.Errorf("Expected ccl-rs to run 2 of 4 tests, got %+v", impl)
+	}
+
+	rows := make(map[string]CapabilityRow)
+	for _, row := range matrix.Capabilities {
+		rows[row.Name] = row
+	}
+	tests := []struct {
+		capability, impl string
+		want             CapabilityCell
+	}{
+		{"parse", "ccl-go", CapabilityCell{Supported: true, Compatible: 3, Percent: 100}},
+		{"parse", "ccl-rs", CapabilityCell{Supported: true, Compatible: 2, Percent: percent(2, 3)}},
+		{"get_string", "ccl-rs", CapabilityCell{}},
+		{"comments", "ccl-go", CapabilityCell{Supported: true, Compatible: 1, Percent: 100}},
+		{"comments", "ccl-rs", CapabilityCell{}},
+		{"pretty_print", "ccl-go", CapabilityCell{}},
+	}
+	for _, tt := range tests {
+		if got := rows[tt.capability].Cells[tt.impl]; got != tt.want {
+			t.Errorf("%s/%s: expected %+v, got %+v", tt.capability, tt.impl, tt.want, got)
+		}
+	}
+	// Supported by neither implementation and without tests, but still listed
+	if row, ok := rows["pretty_print"]; !ok || row.Tests != 0 || len(row.Cells) != 2 {
+		t.Errorf("Expected an empty pretty_print row, got %+v", row)
+	}
+}
+
+func TestCapabilityMatrixMarkdown(t *testing.T) {
+	matrix, err := BuildMatrix(setupCapabilityData(t), createCapabilityConfigs())
+	if err != nil {
+		t.Fatalf("BuildMatrix failed: %v", err)
+	}
+	got := CapabilityMatrixMarkdown(matrix)
+	for _, want := range []string{
+		"| Capability | Kind | Tests | ccl-go | ccl-rs |\n|---|---|---:|---:|---:|\n",
+		"| All tests | - | 4 | 4 (100.0%) | 2 (50.0%) |\n",
+		"| parse | function | 3 | 3 (100.0%) | 2 (66.7%) |\n",
+		"| get_string | function | 1 | 1 (100.0%) | - |\n",
+		"| pretty_print | function | 0 | - | - |\n",
+		"| comments | feature | 1 | 1 (100.0%) | - |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+
+	data, err := json.Marshal(matrix)
+	if err != nil {
+		t.Fatalf("Failed to marshal matrix: %v", err)
+	}
+	if !strings.Contains(string(data), `"name":"comments","kind":"feature","tests":1,"cells":{"ccl-go":{"supported":true,"compatible":1,"percent":100},"ccl-rs":{"supported":false,"compatible":0,"percent":0}}`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,224 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Changelog describes how the flat tests changed between two snapshots of a
+// generated tests directory, test by test and capability by capability
+type Changelog struct {
+	OldTests   int                `json:"old_tests"`
+	NewTests   int                `json:"new_tests"`
+	Tests      TestDiff           `json:"tests"`
+	Introduced []CapabilityChange `json:"introduced"` // Capabilities only the new snapshot exercises
+	Retired    []CapabilityChange `json:"retired"`    // Capabilities only the old snapshot exercises
+	Functions  []FunctionDelta    `json:"functions"`  // Functions with any added, removed, or changed test
+}
+
+// CapabilityChange is a function, feature, behavior, or variant that one
+// snapshot's tests exercise and the other's do not
+type CapabilityChange struct {
+	Kind  string `json:"kind"` // "function", "feature", "behavior", or "variant"
+	Name  string `json:"name"`
+	Tests int    `json:"tests"` // Tests exercising it in the snapshot that has it
+}
+
+// FunctionDelta counts one function's tests in each snapshot and how they
+// changed. A test counts toward every function it lists.
+type FunctionDelta struct {
+	Function string `json:"function"`
+	Before   int    `json:"before"`
+	After    int    `json:"after"`
+	Added    int    `json:"added"`
+	Removed  int    `json:"removed"`
+	Changed  int    `json:"changed"`
+}
+
+// Delta is the change in the function's test count
+func (d FunctionDelta) Delta() int {
+	return d.After - d.Before
+}
+
+// capabilityKinds are the capability kinds in reporting order
+var capabilityKinds = []struct {
+	name  string
+	names func(FlatTest) []string
+}{
+	{"function", testFunctions},
+	{"feature", func(t FlatTest) []string { return t.Features }},
+	{"behavior", func(t FlatTest) []string { return t.Behaviors }},
+	{"variant", func(t FlatTest) []string { return t.Variants }},
+}
+
+// DataChangelog loads the flat tests in oldDir and newDir and summarizes
+// what changed between them
+func DataChangelog(oldDir, newDir string) (Changelog, error) {
+	before, err := LoadFlatDir(oldDir)
+	if err != nil {
+		return Changelog{}, err
+	}
+	after, err := LoadFlatDir(newDir)
+	if err != nil {
+		return Changelog{}, err
+	}
+	return BuildChangelog(before, after), nil
+}
+
+// BuildChangelog summarizes the changes between two sets of flat tests
+func BuildChangelog(before, after []FlatTest) Changelog {
+	changelog := Changelog{
+		OldTests:   len(before),
+		NewTests:   len(after),
+		Tests:      DiffTests(before, after),
+		Introduced: []CapabilityChange{},
+		Retired:    []CapabilityChange{},
+		Functions:  []FunctionDelta{},
+	}
+
+	for _, kind := range capabilityKinds {
+		oldCounts, newCounts := countNames(before, kind.names), countNames(after, kind.names)
+		changelog.Introduced = append(changelog.Introduced, onlyIn(kind.name, newCounts, oldCounts)...)
+		changelog.Retired = append(changelog.Retired, onlyIn(kind.name, oldCounts, newCounts)...)
+	}
+
+	deltas := make(map[string]*FunctionDelta)
+	delta := func(fn string) *FunctionDelta {
+		if deltas[fn] == nil {
+			deltas[fn] = &FunctionDelta{Function: fn}
+		}
+		return deltas[fn]
+	}
+	for fn, count := range countNames(before, testFunctions) {
+		delta(fn).Before = count
+	}
+	for fn, count := range countNames(after, testFunctions) {
+		delta(fn).After = count
+	}
+	oldByKey, newByKey := indexTests(before), indexTests(after)
+	for _, entry := range changelog.Tests.Added {
+		for _, fn := range testFunctions(newByKey[entryKey(entry)]) {
+			delta(fn).Added++
+		}
+	}
+	for _, entry := range changelog.Tests.Removed {
+		for _, fn := range testFunctions(oldByKey[entryKey(entry)]) {
+			delta(fn).Removed++
+		}
+	}
+	for _, entry := range changelog.Tests.Changed {
+		for _, fn := range testFunctions(newByKey[entryKey(entry)]) {
+			delta(fn).Changed++
+		}
+	}
+	for _, d := range deltas {
+		if d.Added > 0 || d.Removed > 0 || d.Changed > 0 {
+			changelog.Functions = append(changelog.Functions, *d)
+		}
+	}
+	sort.Slice(changelog.Functions, func(i, j int) bool {
+		return changelog.Functions[i].Function < changelog.Functions[j].Function
+	})
+	return changelog
+}
+
+// Markdown renders the changelog with capability changes first, then
+// per-function deltas, then the individual tests
+func (c Changelog) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Test data changelog\n\n")
+	fmt.Fprintf(&b, "%d tests before, %d after: %d added, %d removed, %d changed\n",
+		c.OldTests, c.NewTests, len(c.Tests.Added), len(c.Tests.Removed), len(c.Tests.Changed))
+
+	writeCapabilityChanges(&b, "New capabilities", c.Introduced)
+	writeCapabilityChanges(&b, "Retired capabilities", c.Retired)
+
+	if len(c.Functions) > 0 {
+		b.WriteString("\n## Functions\n\n")
+		b.WriteString("| Function | Before | After | Delta | Added | Removed | Changed |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+		for _, d := range c.Functions {
+			fmt.Fprintf(&b, "| %s | %d | %d | %+d | %d | %d | %d |\n",
+				escapeCell(d.Function), d.Before, d.After, d.Delta(), d.Added, d.Removed, d.Changed)
+		}
+	}
+
+	writeTestEntries(&b, "Added tests", c.Tests.Added)
+	writeTestEntries(&b, "Removed tests", c.Tests.Removed)
+	writeTestEntries(&b, "Changed tests", c.Tests.Changed)
+	return b.String()
+}
+
+func writeCapabilityChanges(b *strings.Builder, title string, changes []CapabilityChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n| Kind | Name | Tests |\n|---|---|---:|\n", title)
+	for _, change := range changes {
+		fmt.Fprintf(b, "| %s | %s | %d |\n", change.Kind, escapeCell(change.Name), change.Tests)
+	}
+}
+
+func writeTestEntries(b *strings.Builder, title string, entries []DiffEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	for _, entry := range entries {
+		fmt.Fprintf(b, "- `%s` (%s) %s", entry.Name, entry.Validation, entry.File)
+		if len(entry.Fields) > 0 {
+			fmt.Fprintf(b, ": %s", strings.Join(entry.Fields, ", "))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// testFunctions lists the functions a test exercises, falling back to its
+// validation for tests that do not list them
+func testFunctions(test FlatTest) []string {
+	if len(test.Functions) > 0 {
+		return test.Functions
+	}
+	if test.Validation == "" {
+		return nil
+	}
+	return []string{test.Validation}
+}
+
+// countNames counts the tests listing each name
+func countNames(tests []FlatTest, names func(FlatTest) []string) map[string]int {
+	counts := make(map[string]int)
+	for _, test := range tests {
+		for _, name := range names(test) {
+			counts[name]++
+		}
+	}
+	return counts
+}
+
+// onlyIn lists the names counted in have but not in lack, sorted by name
+func onlyIn(kind string, have, lack map[string]int) []CapabilityChange {
+	var changes []CapabilityChange
+	for name, count := range have {
+		if _, ok := lack[name]; !ok {
+			changes = append(changes, CapabilityChange{Kind: kind, Name: name, Tests: count})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func indexTests(tests []FlatTest) map[string]FlatTest {
+	index := make(map[string]FlatTest, len(tests))
+	for _, test := range tests {
+		index[test.key()] = test
+	}
+	return index
+}
+
+func entryKey(entry DiffEntry) string {
+	return FlatTest{TestCase: types.TestCase{Name: entry.Name, Validation: entry.Validation}}.key()
+}
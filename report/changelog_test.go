@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// changelogOld and changelogNew are two snapshots of a generated tests
+// directory: a test is added with a new feature, one is removed along with
+// the only behavior it used, and one changes its expected value
+var changelogOld = map[string]string{
+	"api_core.json": `{"tests": [
+		{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "functions": ["parse"], "expected": {"count": 1}},
+		{"name": "basic_get_string", "inputs": ["a = b"], "validation": "get_string", "functions": ["parse", "get_string"], "args": ["a"], "expected": {"count": 1, "value": "b"}},
+		{"name": "crlf_parse", "inputs": ["a = b\r\n"], "validation": "parse", "functions": ["parse"], "behaviors": ["crlf_preserve_literal"], "expected": {"count": 1}}
+	]}`,
+}
+
+var changelogNew = map[string]string{
+	"api_core.json": `{"tests": [
+		{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "functions": ["parse"], "expected": {"count": 1}},
+		{"name": "basic_get_string", "inputs": ["a = b"], "validation": "get_string", "functions": ["parse", "get_string"], "args": ["a"], "expected": {"count": 1, "value": "c"}}
+	]}`,
+	"api_comments.json": `{"tests": [
+		{"name": "comment_filter", "inputs": ["/= note\na = b"], "validation": "filter", "functions": ["parse", "filter"], "features": ["comments"], "expected": {"count": 1}}
+	]}`,
+}
+
+const changelogGolden = "# Test data changelog\n" + `
+3 tests before, 3 after: 1 added, 1 removed, 1 changed
+
+## New capabilities
+
+| Kind | Name | Tests |
+|---|---|---:|
+| function | filter | 1 |
+| feature | comments | 1 |
+
+## Retired capabilities
+
+| Kind | Name | Tests |
+|---|---|---:|
+| behavior | crlf_preserve_literal | 1 |
+
+## Functions
+
+| Function | Before | After | Delta | Added | Removed | Changed |
+|---|---:|---:|---:|---:|---:|---:|
+| filter | 0 | 1 | +1 | 1 | 0 | 0 |
+| get_string | 1 | 1 | +0 | 0 | 0 | 1 |
+| parse | 3 | 3 | +0 | 1 | 1 | 1 |
+
+## Added tests
+
+- ` + "`comment_filter`" + ` (filter) api_comments.json
+
+## Removed tests
+
+- ` + "`crlf_parse`" + ` (parse) api_core.json
+
+## Changed tests
+
+- ` + "`basic_get_string`" + ` (get_string) api_core.json: expected
+`
+
+func TestDataChangelog_Golden(t *testing.T) {
+	changelog, err := DataChangelog(writeFlatDir(t, changelogOld), writeFlatDir(t, changelogNew))
+	if err != nil {
+		t.Fatalf("DataChangelog failed: %v", err)
+	}
+
+	if got := changelog.Markdown(); got != changelogGolden {
+		t.Errorf("Markdown mismatch.\nGot:\n%s\nWant:\n%s", got, changelogGolden)
+	}
+
+	data, err := json.Marshal(changelog)
+	if err != nil {
+		t.Fatalf("Failed to marshal changelog: %v", err)
+	}
+	const goldenJSON = `{"old_tests":3,"new_tests":3,` +
+		`"tests":{"added":[{"name":"comment_filter","validation":"filter","file":"api_comments.json"}],` +
+		`"removed":[{"name":"crlf_parse","validation":"parse","file":"api_core.json"}],` +
+		`"changed":[{"name":"basic_get_string","validation":"get_string","file":"api_core.json","fields":["expected"]}]},` +
+		`"introduced":[{"kind":"function","name":"filter","tests":1},{"kind":"feature","name":"comments","tests":1}],` +
+		`"retired":[{"kind":"behavior","name":"crlf_preserve_literal","tests":1}],` +
+		`"functions":[{"function":"filter","before":0,"after":1,"added":1,"removed":0,"changed":0},` +
+		`{"function":"get_string","before":1,"after":1,"added":0,"removed":0,"changed":1},` +
+		`{"function":"parse","before":3,"after":3,"added":1,"removed":1,"changed":1}]}`
+	if string(data) != goldenJSON {
+		t.Errorf("JSON mismatch.\nGot:  %s\nWant: %s", data, goldenJSON)
+	}
+}
+
+func TestDataChangelog_Unchanged(t *testing.T) {
+	dir := writeFlatDir(t, changelogOld)
+	changelog, err := DataChangelog(dir, dir)
+	if err != nil {
+		t.Fatalf("DataChangelog failed: %v", err)
+	}
+	if !changelog.Tests.Empty() || len(changelog.Introduced) != 0 || len(changelog.Retired) != 0 || len(changelog.Functions) != 0 {
+		t.Errorf("Expected an empty changelog, got %+v", changelog)
+	}
+	if want := "# Test data changelog\n\n3 tests before, 3 after: 0 added, 0 removed, 0 changed\n"; changelog.Markdown() != want {
+		t.Errorf("Expected %q, got %q", want, changelog.Markdown())
+	}
+}
+
+func TestDataChangelog_MissingDir(t *testing.T) {
+	if _, err := DataChangelog(t.TempDir(), writeFlatDir(t, changelogNew)); err == nil {
+		t.Error("Expected an error for a directory without test files")
+	}
+}
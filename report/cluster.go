@@ -0,0 +1,167 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// FailureCluster is a group of failures that probably share a root cause
+type FailureCluster struct {
+	Validation    string            `json:"validation,omitempty"`     // Empty for a source test cluster spanning validations
+	Message       string            `json:"message"`                  // Failure message with quoted values and numbers masked
+	DiffSignature string            `json:"diff_signature,omitempty"` // Shape of the diff, e.g. "entries:changed,missing"
+	SourceTest    string            `json:"source_test,omitempty"`    // Set when the failures were grouped by their source test
+	Tests         []string          `json:"tests"`                    // Names of the failed tests, in result order
+	Example       runner.TestResult `json:"example"`                  // The first failure of the cluster
+}
+
+// Size returns the number of failures in the cluster
+func (c FailureCluster) Size() int {
+	return len(c.Tests)
+}
+
+// ClusterFailures groups the failed results by validation, normalized
+// message and diff signature. Failures left alone in their group are then
+// grouped by source test when several share one. Clusters are sorted by
+// size, largest first, then by the order of their first failure.
+func ClusterFailures(results []runner.TestResult) []FailureCluster {
+	type key struct{ validation, message, signature string }
+	var clusters []*FailureCluster
+	byKey := make(map[key]*FailureCluster)
+	for _, result := range results {
+		if result.Status != runner.StatusFail {
+			continue
+		}
+		k := key{result.Validation, normalizeMessage(result.Message), diffSignature(result)}
+		cluster, ok := byKey[k]
+		if !ok {
+			cluster = &FailureCluster{Validation: k.validation, Message: k.message, DiffSignature: k.signature, Example: result}
+			byKey[k] = cluster
+			clusters = append(clusters, cluster)
+		}
+		cluster.Tests = append(cluster.Tests, result.Name)
+	}
+
+	// A source test failing differently in each validation usually has one
+	// bad input or expectation behind it
+	sourceCounts := make(map[string]int)
+	for _, cluster := range clusters {
+		if cluster.Size() == 1 && cluster.Example.SourceTest != "" {
+			sourceCounts[cluster.Example.SourceTest]++
+		}
+	}
+	merged := make([]*FailureCluster, 0, len(clusters))
+	bySource := make(map[string]*FailureCluster)
+	for _, cluster := range clusters {
+		source := cluster.Example.SourceTest
+		if cluster.Size() > 1 || sourceCounts[source] < 2 {
+			merged = append(merged, cluster)
+			continue
+		}
+		if existing, ok := bySource[source]; ok {
+			existing.Tests = append(existing.Tests, cluster.Tests...)
+			continue
+		}
+		cluster.Validation, cluster.DiffSignature, cluster.SourceTest = "", "", source
+		bySource[source] = cluster
+		merged = append(merged, cluster)
+	}
+
+	sorted := make([]FailureCluster, len(merged))
+	for i, cluster := range merged {
+		sorted[i] = *cluster
+	}
+	slices.SortStableFunc(sorted, func(a, b FailureCluster) int { return b.Size() - a.Size() })
+	return sorted
+}
+
+var (
+	quotedPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	numberPattern = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+	spacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// normalizeMessage keeps the first line of a failure message, the summary
+// above any diff rows, with the values that differ between tests masked
+func normalizeMessage(message string) string {
+	message, _, _ = strings.Cut(message, "\n")
+	message = quotedPattern.ReplaceAllString(message, `"…"`)
+	message = numberPattern.ReplaceAllString(message, "N")
+	return strings.TrimSpace(spacePattern.ReplaceAllString(message, " "))
+}
+
+// diffSignature describes how a failure's actual value differs from the
+// expected one: the diff kind and the kinds of change, without the values.
+// It is empty when there is no actual value to compare, as after an error.
+func diffSignature(result runner.TestResult) string {
+	if result.Actual == nil || result.Expected == nil {
+		return ""
+	}
+	diff, err := runner.ComputeDiff(result.Expected, result.Actual)
+	if err != nil || diff.Equal() {
+		return ""
+	}
+	var ops []string
+	for _, change := range diff.Entries {
+		ops = append(ops, string(change.Op))
+	}
+	for _, change := range diff.Paths {
+		ops = append(ops, string(change.Op))
+	}
+	slices.Sort(ops)
+	ops = slices.Compact(ops)
+	if len(ops) == 0 {
+		return string(diff.Kind)
+	}
+	return string(diff.Kind) + ":" + strings.Join(ops, ",")
+}
+
+// ClustersMarkdown renders failure clusters as a table, largest first, with
+// each cluster's example test
+func ClustersMarkdown(clusters []FailureCluster) string {
+	if len(clusters) == 0 {
+		return "No failures\n"
+	}
+	var b strings.Builder
+	b.WriteString("| Failures | Function | Cause | Example |\n")
+	b.WriteString("|---:|---|---|---|\n")
+	for _, cluster := range clusters {
+		function, cause := cluster.Validation, cluster.Message
+		if cluster.SourceTest != "" {
+			function = "(several)"
+			cause = "source test " + cluster.SourceTest
+		} else if cluster.DiffSignature != "" {
+			cause += " [" + cluster.DiffSignature + "]"
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s |\n",
+			cluster.Size(),
+			escapeCell(function),
+			escapeCell(cause),
+			escapeCell(cluster.Example.Name),
+		)
+	}
+	return b.String()
+}
+
+// ClustersJSON renders failure clusters as indented JSON, with each
+// cluster's size
+func ClustersJSON(clusters []FailureCluster) (string, error) {
+	type sized struct {
+		Size int `json:"size"`
+		FailureCluster
+	}
+	out := make([]sized, len(clusters))
+	for i, cluster := range clusters {
+		out[i] = sized{cluster.Size(), cluster}
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal clusters: %w", err)
+	}
+	return string(data) + "\n", nil
+}
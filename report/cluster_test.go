@@ -0,0 +1,124 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/runner"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// plantedFailures has two root causes among passing tests: parse drops the
+// last entry (5 failures) and get_int cannot find nested keys (3 failures)
+func plantedFailures() []runner.TestResult {
+	var results []runner.TestResult
+	for i := 0; i < 5; i++ {
+		expected := []types.Entry{{Key: "a", Value: fmt.Sprint(i)}, {Key: fmt.Sprintf("k%d", i), Value: "v"}}
+		results = append(results,
+			runner.TestResult{Name: fmt.Sprintf("ok_%d_parse", i), Validation: "parse", Status: runner.StatusPass},
+			runner.TestResult{
+				Name:       fmt.Sprintf("drop_%d_parse", i),
+				Validation: "parse",
+				Status:     runner.StatusFail,
+				Message:    fmt.Sprintf("entry lists differ: expected 2 entries, got 1\n  %d  -  missing  %s = v", i, expected[1].Key),
+				Expected:   expected,
+				Actual:     expected[:1],
+			})
+	}
+	for i, key := range []string{"server.port", "db.pool.size", "x.y"} {
+		results = append(results, runner.TestResult{
+			Name:       fmt.Sprintf("nested_%d_get_int", i),
+			Validation: "get_int",
+			Status:     runner.StatusFail,
+			Message:    fmt.Sprintf("unexpected error: key %q not found at depth %d", key, i+2),
+		})
+	}
+	return append(results, runner.TestResult{Name: "not_run", Validation: "parse", Status: runner.StatusNotRun, Message: "not run"})
+}
+
+func TestClusterFailures_PlantedCauses(t *testing.T) {
+	clusters := ClusterFailures(plantedFailures())
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+
+	parse, getInt := clusters[0], clusters[1]
+	if parse.Size() != 5 || parse.Validation != "parse" || parse.DiffSignature != "entries:missing" {
+		t.Errorf("Expected 5 parse failures with a missing entry first, got %+v", parse)
+	}
+	if parse.Message != "entry lists differ: expected N entries, got N" || parse.Example.Name != "drop_0_parse" {
+		t.Errorf("Unexpected parse cluster message or example: %q, %s", parse.Message, parse.Example.Name)
+	}
+	if getInt.Size() != 3 || getInt.Validation != "get_int" || getInt.DiffSignature != "" {
+		t.Errorf("Expected 3 get_int failures second, got %+v", getInt)
+	}
+	if getInt.Message != `unexpected error: key "…" not found at depth N` {
+		t.Errorf("Unexpected get_int cluster message %q", getInt.Message)
+	}
+}
+
+func TestClusterFailures_SourceTest(t *testing.T) {
+	results := []runner.TestResult{
+		{Name: "bad_parse", Validation: "parse", SourceTest: "bad", Status: runner.StatusFail, Message: "entry lists differ: expected 1 entries, got 0"},
+		{Name: "bad_get_string", Validation: "get_string", SourceTest: "bad", Status: runner.StatusFail, Message: `expected "x", got "y"`},
+		{Name: "bad_filter", Validation: "filter", SourceTest: "bad", Status: runner.StatusFail, Message: "unexpected error: boom"},
+		{Name: "lone_parse", Validation: "parse", SourceTest: "lone", Status: runner.StatusFail, Message: "unexpected error: boom"},
+	}
+
+	clusters := ClusterFailures(results)
+	got := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		got[i] = fmt.Sprintf("%d %s %s %v", cluster.Size(), cluster.Validation, cluster.SourceTest, cluster.Tests)
+	}
+	want := []string{
+		"3  bad [bad_parse bad_get_string bad_filter]",
+		"1 parse  [lone_parse]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestClustersMarkdown(t *testing.T) {
+	results := append(plantedFailures(),
+		runner.TestResult{Name: "src_a_parse", Validation: "parse", SourceTest: "src", Status: runner.StatusFail, Message: "unexpected error: a"},
+		runner.TestResult{Name: "src_b_filter", Validation: "filter", SourceTest: "src", Status: runner.StatusFail, Message: "unexpected error: b"},
+	)
+	golden := `| Failures | Function | Cause | Example |
+|---:|---|---|---|
+| 5 | parse | entry lists differ: expected N entries, got N [entries:missing] | drop_0_parse |
+| 3 | get_int | unexpected error: key "…" not found at depth N | nested_0_get_int |
+| 2 | (several) | source test src | src_a_parse |
+`
+	if got := ClustersMarkdown(ClusterFailures(results)); got != golden {
+		t.Errorf("Output mismatch.\nGot:\n%s\nWant:\n%s", got, golden)
+	}
+	if got := ClustersMarkdown(nil); got != "No failures\n" {
+		t.Errorf("Expected no failures, got %q", got)
+	}
+}
+
+func TestClustersJSON(t *testing.T) {
+	out, err := ClustersJSON(ClusterFailures(plantedFailures()))
+	if err != nil {
+		t.Fatalf("ClustersJSON failed: %v", err)
+	}
+	var decoded []struct {
+		Size          int      `json:"size"`
+		Validation    string   `json:"validation"`
+		DiffSignature string   `json:"diff_signature"`
+		Tests         []string `json:"tests"`
+		Example       struct {
+			Name string `json:"name"`
+		} `json:"example"`
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if len(decoded) != 2 || decoded[0].Size != 5 || len(decoded[0].Tests) != 5 || decoded[0].DiffSignature != "entries:missing" ||
+		decoded[1].Size != 3 || decoded[1].Example.Name != "nested_0_get_int" {
+		t.Errorf("Unexpected clusters: %s", out)
+	}
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// FlatTest is a flat test with the file it was loaded from
+type FlatTest struct {
+	types.TestCase
+	File string
+}
+
+// key identifies a flat test across regenerations
+func (t FlatTest) key() string {
+	return t.Name + "\x00" + t.Validation
+}
+
+// LoadFlatDir reads every *.json flat test file in dir, in name order
+func LoadFlatDir(dir string) ([]FlatTest, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no test files in %s", dir)
+	}
+	sort.Strings(files)
+
+	var tests []FlatTest
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		var suite types.TestSuite
+		if err := json.Unmarshal(data, &suite); err != nil {
+			return nil, fmt.Errorf("%s: failed to parse flat tests: %w", filepath.Base(file), err)
+		}
+		for _, test := range suite.Tests {
+			tests = append(tests, FlatTest{TestCase: test, File: filepath.Base(file)})
+		}
+	}
+	return tests, nil
+}
+
+// diffFields are the compared parts of a flat test, in reporting order
+var diffFields = []struct {
+	name  string
+	value func(FlatTest) interface{}
+}{
+	{"file", func(t FlatTest) interface{} { return t.File }},
+	{"inputs", func(t FlatTest) interface{} { return t.Inputs }},
+	{"expected", func(t FlatTest) interface{} { return t.Expected }},
+	{"args", func(t FlatTest) interface{} { return t.Args }},
+	{"expect_error", func(t FlatTest) interface{} { return t.ExpectError }},
+	{"error_type", func(t FlatTest) interface{} { return t.ErrorType }},
+	{"functions", func(t FlatTest) interface{} { return t.Functions }},
+	{"features", func(t FlatTest) interface{} { return t.Features }},
+	{"behaviors", func(t FlatTest) interface{} { return t.Behaviors }},
+	{"variants", func(t FlatTest) interface{} { return t.Variants }},
+	{"conflicts", func(t FlatTest) interface{} { return t.Conflicts }},
+	{"meta", func(t FlatTest) interface{} { return t.Meta }},
+	{"source_test", func(t FlatTest) interface{} { return t.SourceTest }},
+}
+
+// DiffEntry is one added, removed, or changed test
+type DiffEntry struct {
+	Name       string   `json:"name"`
+	Validation string   `json:"validation"`
+	File       string   `json:"file"`
+	Fields     []string `json:"fields,omitempty"` // Changed fields only
+}
+
+// TestDiff classifies the differences between two sets of flat tests
+type TestDiff struct {
+	Added   []DiffEntry `json:"added"`
+	Removed []DiffEntry `json:"removed"`
+	Changed []DiffEntry `json:"changed"`
+}
+
+// Empty reports whether the two sets of tests are identical
+func (d TestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the diff for humans, one test per line
+func (d TestDiff) String() string {
+	var b strings.Builder
+	for _, entry := range d.Removed {
+		fmt.Fprintf(&b, "- %s (%s) %s\n", entry.Name, entry.Validation, entry.File)
+	}
+	for _, entry := range d.Added {
+		fmt.Fprintf(&b, "+ %s (%s) %s\n", entry.Name, entry.Validation, entry.File)
+	}
+	for _, entry := range d.Changed {
+		fmt.Fprintf(&b, "~ %s (%s) %s: %s\n", entry.Name, entry.Validation, entry.File, strings.Join(entry.Fields, ", "))
+	}
+	fmt.Fprintf(&b, "%d added, %d removed, %d changed\n", len(d.Added), len(d.Removed), len(d.Changed))
+	return b.String()
+}
+
+// DiffTests matches tests by name and validation and classifies each
+func DiffTests(before, after []FlatTest) TestDiff {
+	diff := TestDiff{Added: []DiffEntry{}, Removed: []DiffEntry{}, Changed: []DiffEntry{}}
+
+	old := make(map[string]FlatTest, len(before))
+	for _, test := range before {
+		old[test.key()] = test
+	}
+	seen := make(map[string]bool, len(after))
+	for _, test := range after {
+		seen[test.key()] = true
+		previous, ok := old[test.key()]
+		if !ok {
+			diff.Added = append(diff.Added, newDiffEntry(test))
+			continue
+		}
+		if fields := changedFields(previous, test); len(fields) > 0 {
+			entry := newDiffEntry(test)
+			entry.Fields = fields
+			diff.Changed = append(diff.Changed, entry)
+		}
+	}
+	for _, test := range before {
+		if !seen[test.key()] {
+			diff.Removed = append(diff.Removed, newDiffEntry(test))
+		}
+	}
+
+	for _, entries := range [][]DiffEntry{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Name != entries[j].Name {
+				return entries[i].Name < entries[j].Name
+			}
+			return entries[i].Validation < entries[j].Validation
+		})
+	}
+	return diff
+}
+
+func newDiffEntry(test FlatTest) DiffEntry {
+	return DiffEntry{Name: test.Name, Validation: test.Validation, File: test.File}
+}
+
+// changedFields lists the fields whose JSON differs, treating null and
+// empty collections as equal
+func changedFields(a, b FlatTest) []string {
+	var fields []string
+	for _, field := range diffFields {
+		if jsonValue(field.value(a)) != jsonValue(field.value(b)) {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+func jsonValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	switch s := string(data); s {
+	case "[]", "{}":
+		return "null"
+	default:
+		return s
+	}
+}
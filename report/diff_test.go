@@ -0,0 +1,69 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFlatDir writes flat test files into a new directory and returns it
+func writeFlatDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const diffBefore = `{"tests": [
+	{"name": "same_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1, "entries": [{"key": "a", "value": "b"}]}},
+	{"name": "gone_parse", "inputs": ["x = y"], "validation": "parse", "expected": {"count": 1}},
+	{"name": "value_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "b"}},
+	{"name": "meta_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_preserve_literal"], "features": []}
+]}`
+
+const diffAfter = `{"tests": [
+	{"name": "same_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"entries": [{"key": "a", "value": "b"}], "count": 1}},
+	{"name": "new_parse", "inputs": ["n = 1"], "validation": "parse", "expected": {"count": 1}},
+	{"name": "value_get_string", "inputs": ["a = b"], "validation": "get_string", "args": ["b"], "expected": {"count": 1, "value": "c"}},
+	{"name": "meta_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}, "behaviors": ["crlf_normalize_to_lf"]}
+]}`
+
+func TestDiffTests_Classification(t *testing.T) {
+	before, err := LoadFlatDir(writeFlatDir(t, map[string]string{"api.json": diffBefore}))
+	if err != nil {
+		t.Fatalf("LoadFlatDir failed: %v", err)
+	}
+	after, err := LoadFlatDir(writeFlatDir(t, map[string]string{"api.json": diffAfter}))
+	if err != nil {
+		t.Fatalf("LoadFlatDir failed: %v", err)
+	}
+
+	diff := DiffTests(before, after)
+	want := TestDiff{
+		Added:   []DiffEntry{{Name: "new_parse", Validation: "parse", File: "api.json"}},
+		Removed: []DiffEntry{{Name: "gone_parse", Validation: "parse", File: "api.json"}},
+		Changed: []DiffEntry{
+			{Name: "meta_parse", Validation: "parse", File: "api.json", Fields: []string{"behaviors"}},
+			{Name: "value_get_string", Validation: "get_string", File: "api.json", Fields: []string{"expected", "args"}},
+		},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff mismatch.\nGot:  %+v\nWant: %+v", diff, want)
+	}
+}
+
+func TestDiffTests_MovedBetweenFiles(t *testing.T) {
+	test := `{"tests": [{"name": "x_parse", "inputs": ["a = b"], "validation": "parse", "expected": {"count": 1}}]}`
+	before, _ := LoadFlatDir(writeFlatDir(t, map[string]string{"a.json": test}))
+	after, _ := LoadFlatDir(writeFlatDir(t, map[string]string{"b.json": test}))
+
+	diff := DiffTests(before, after)
+	if len(diff.Changed) != 1 || !reflect.DeepEqual(diff.Changed[0].Fields, []string{"file"}) {
+		t.Errorf("Expected a file change, got %+v", diff)
+	}
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+)
+
+// CapabilityReference loads the flat tests in dir and renders markdown
+// documenting every known function, feature, behavior, and variant with
+// its description from the config package and the number of tests that
+// exercise it. Behaviors also list their conflict group and the functions
+// generated tests tag them on.
+func CapabilityReference(dir string) (string, error) {
+	tests, err := LoadFlatDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return BuildCapabilityReference(tests), nil
+}
+
+// BuildCapabilityReference renders CapabilityReference for a set of flat tests
+func BuildCapabilityReference(tests []FlatTest) string {
+	counts := make(map[string]map[string]int, len(capabilityKinds))
+	for _, kind := range capabilityKinds {
+		counts[kind.name] = countNames(tests, kind.names)
+	}
+
+	var b strings.Builder
+	b.WriteString("# CCL capability reference\n\n")
+	fmt.Fprintf(&b, "Test counts are of the %d flat tests in the data directory.\n", len(tests))
+
+	b.WriteString("\n## Functions\n\n| Function | Tests | Description |\n|---|---:|---|\n")
+	for _, fn := range config.AllFunctions() {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", fn, counts["function"][string(fn)], escapeCell(fn.Description()))
+	}
+
+	b.WriteString("\n## Features\n\n| Feature | Tests | Description |\n|---|---:|---|\n")
+	for _, feature := range config.AllFeatures() {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", feature, counts["feature"][string(feature)], escapeCell(feature.Description()))
+	}
+
+	b.WriteString("\n## Behaviors\n\nAn implementation chooses at most one behavior from each group.\n\n")
+	b.WriteString("| Behavior | Group | Applies to | Tests | Description |\n|---|---|---|---:|---|\n")
+	for _, behavior := range config.AllBehaviors() {
+		group, ok := config.BehaviorGroup(behavior)
+		if !ok {
+			group = "(registered)"
+		}
+		appliesTo := "all functions"
+		if functions, ok := generator.BehaviorFunctions(string(behavior)); ok {
+			appliesTo = strings.Join(functions, ", ")
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n", behavior, group, escapeCell(appliesTo), counts["behavior"][string(behavior)], escapeCell(behavior.Description()))
+	}
+
+	b.WriteString("\n## Variants\n\n| Variant | Tests | Description |\n|---|---:|---|\n")
+	for _, variant := range config.AllVariants() {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", variant, counts["variant"][string(variant)], escapeCell(variant.Description()))
+	}
+	return b.String()
+}
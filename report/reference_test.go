@@ -0,0 +1,92 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+// referenceTests exercise a few capabilities of each kind
+var referenceTests = map[string]string{
+	"api_core.json": `{"tests": [
+		{"name": "basic_parse", "inputs": ["a = b"], "validation": "parse", "functions": ["parse"], "expected": {"count": 1}},
+		{"name": "crlf_parse", "inputs": ["a = b\r\n"], "validation": "parse", "functions": ["parse"], "behaviors": ["crlf_normalize_to_lf"], "variants": ["reference_compliant"], "expected": {"count": 1}},
+		{"name": "lenient_get_bool", "inputs": ["a = yes"], "validation": "get_bool", "functions": ["parse", "get_bool"], "behaviors": ["boolean_lenient"], "args": ["a"], "expected": {"count": 1, "value": true}}
+	]}`,
+	"api_comments.json": `{"tests": [
+		{"name": "comment_filter", "inputs": ["/= note\na = b"], "validation": "filter", "functions": ["parse", "filter"], "features": ["comments"], "expected": {"count": 1}}
+	]}`,
+}
+
+const referenceGolden = "# CCL capability reference\n" + `
+Test counts are of the 4 flat tests in the data directory.
+
+## Functions
+
+| Function | Tests | Description |
+|---|---:|---|
+| parse | 4 | Parse CCL text into a flat list of key-value entries, in input order |
+| parse_indented | 0 | Parse CCL text whose entries share a common indentation, removing it first |
+| filter | 1 | Remove comment entries (key "/") from a list of entries |
+| extract_comments | 0 | Keep only the comment entries (key "/") of a list of entries; the inverse of filter |
+| combine | 0 | Concatenate two entry lists, the monoid operation of CCL |
+| expand_dotted | 0 | Expand dotted keys such as a.b = c into nested entries |
+| build_hierarchy | 0 | Build nested objects from entries by recursively parsing their values |
+| get_string | 0 | Read a string value at a key path of the hierarchy |
+| get_int | 0 | Read an integer value at a key path of the hierarchy |
+| get_bool | 1 | Read a boolean value at a key path of the hierarchy |
+| get_float | 0 | Read a floating-point value at a key path of the hierarchy |
+| get_list | 0 | Read a list at a key path, from repeated keys or empty-key entries |
+| pretty_print | 0 | Render entries back to CCL text that parses to the same entries |
+| canonical_format | 0 | Render a hierarchy as CCL text in the canonical layout |
+
+## Features
+
+| Feature | Tests | Description |
+|---|---:|---|
+| comments | 1 | Entries with the key "/" are comments, which filter removes and extract_comments keeps |
+| experimental_dotted_keys | 0 | Keys containing dots are paths into the hierarchy (expand_dotted) |
+| empty_keys | 0 | Entries with an empty key, as in "= value", used for list items |
+| multiline | 0 | Values continued over indented lines, and nested CCL inside values |
+| unicode | 0 | Keys and values with non-ASCII text, compared as UTF-8 |
+| whitespace | 0 | Spaces and tabs around keys and values, and blank lines, are trimmed as the spec says |
+
+## Behaviors
+
+An implementation chooses at most one behavior from each group.
+
+| Behavior | Group | Applies to | Tests | Description |
+|---|---|---|---:|---|
+| boolean_strict | boolean | get_bool | 0 | Only true and false are booleans |
+| boolean_lenient | boolean | get_bool | 1 | Common spellings beyond true and false, such as yes and no, are booleans too |
+| crlf_normalize_to_lf | crlf_handling | parse, parse_indented, canonical_format, load | 1 | CRLF line endings in the input are read as LF |
+| crlf_preserve_literal | crlf_handling | parse, parse_indented, canonical_format, load | 0 | CRLF line endings are kept in values as written |
+| indent_spaces | indent_output | canonical_format, print, round_trip | 0 | Nested output is indented with spaces |
+| indent_tabs | indent_output | canonical_format, print, round_trip | 0 | Nested output is indented with tabs |
+| list_coercion_enabled | list_coercion | get_list | 0 | A single value reads as a one-item list |
+| list_coercion_disabled | list_coercion | get_list | 0 | Only repeated keys or empty-key entries form a list |
+| tabs_as_content | tab_handling | parse, parse_indented, canonical_format, load, build_hierarchy | 0 | Tabs are part of values rather than whitespace to trim |
+| tabs_as_whitespace | tab_handling | parse, parse_indented, canonical_format, load, build_hierarchy | 0 | Tabs are whitespace, trimmed and counted as indentation like spaces |
+
+## Variants
+
+| Variant | Tests | Description |
+|---|---:|---|
+| proposed_behavior | 0 | Behavior proposed for the CCL specification, which may differ from the reference implementation |
+| reference_compliant | 1 | Behavior of the OCaml reference implementation |
+`
+
+func TestCapabilityReference_Golden(t *testing.T) {
+	got, err := CapabilityReference(writeFlatDir(t, referenceTests))
+	if err != nil {
+		t.Fatalf("CapabilityReference failed: %v", err)
+	}
+	if got != referenceGolden {
+		t.Errorf("Markdown mismatch.\nGot:\n%s\nWant:\n%s", got, referenceGolden)
+	}
+}
+
+func TestCapabilityReference_NoTests(t *testing.T) {
+	if _, err := CapabilityReference(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no test files") {
+		t.Errorf("Expected an error for a directory without tests, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package report renders test statistics and run results for humans and CI logs.
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// MatrixMarkdown renders a conformance matrix as a markdown table with one row per config
+func MatrixMarkdown(matrix *runner.Matrix) string {
+	var b strings.Builder
+	b.WriteString("| Implementation | Version | Compatible | Coverage | Passed | Failed |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|\n")
+
+	for _, entry := range matrix.Entries {
+		passed, failed := "-", "-"
+		if entry.Results != nil {
+			passed = fmt.Sprintf("%d", entry.Results.Passed)
+			failed = fmt.Sprintf("%d", entry.Results.Failed)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d/%d | %.1f%% | %s | %s |\n",
+			escapeCell(entry.Config.Name),
+			escapeCell(entry.Config.Version),
+			entry.Statistics.CompatibleTests,
+			entry.Statistics.TotalTests,
+			entry.CompatibleRatio()*100,
+			passed,
+			failed,
+		)
+	}
+
+	return b.String()
+}
+
+// escapeCell keeps a value from breaking the table layout
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// ResultsMarkdown renders a run summary, the achieved conformance tier if
+// any, and a table of the tests that did not
+// pass or whose expected outcome was overridden
+func ResultsMarkdown(results *runner.RunResults) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d passed, %d failed, %d not run", results.Passed, results.Failed, results.NotRun)
+	if results.Skipped > 0 {
+		fmt.Fprintf(&b, ", %d skipped", results.Skipped)
+	}
+	b.WriteString("\n")
+	if tier := ComputeAchievedTier(results); tier.Level > 0 {
+		fmt.Fprintf(&b, "\nConformance: %s (%s)\n", tier.Name(), tier.Summary)
+	}
+
+	var rows []runner.TestResult
+	for _, result := range results.Results {
+		if !result.Passed() || result.Override != "" {
+			rows = append(rows, result)
+		}
+	}
+	if len(rows) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n| Test | Function | Status | Notes |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, result := range rows {
+		var notes []string
+		if result.Override != "" {
+			notes = append(notes, "override: "+result.Override)
+		}
+		if result.Message != "" {
+			notes = append(notes, result.Message)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			escapeCell(result.Name),
+			escapeCell(result.Validation),
+			result.Status,
+			escapeCell(strings.Join(notes, "; ")),
+		)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestMatrixMarkdown(t *testing.T) {
+	matrix := &runner.Matrix{
+		TotalTests: 8,
+		Entries: []runner.MatrixEntry{
+			{
+				Config:     config.ImplementationConfig{Name: "minimal-ccl", Version: "v1.0.0"},
+				Statistics: types.TestStatistics{TotalTests: 8, CompatibleTests: 2},
+				Results:    &runner.RunResults{Passed: 2},
+			},
+			{
+				Config:     config.ImplementationConfig{Name: "odd|name", Version: "v2.0.0"},
+				Statistics: types.TestStatistics{TotalTests: 8, CompatibleTests: 5},
+			},
+		},
+	}
+
+	expected := `| Implementation | Version | Compatible | Coverage | Passed | Failed |
+|---|---|---:|---:|---:|---:|
+| minimal-ccl | v1.0.0 | 2/8 | 25.0% | 2 | 0 |
+| odd\|name | v2.0.0 | 5/8 | 62.5% | - | - |
+`
+	if got := MatrixMarkdown(matrix); got != expected {
+		t.Errorf("MatrixMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+}
+
+func TestMatrixMarkdown_Empty(t *testing.T) {
+	got := MatrixMarkdown(&runner.Matrix{})
+	expected := "| Implementation | Version | Compatible | Coverage | Passed | Failed |\n|---|---|---:|---:|---:|---:|\n"
+	if got != expected {
+		t.Errorf("Expected header only, got:\n%s", got)
+	}
+}
+
+func TestResultsMarkdown_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	flat := `{"tests": [
+		{"name": "spaced_key", "inputs": ["a b = c"], "validation": "get_string", "args": ["a b"], "expected": {"count": 1, "value": "spec choice"}},
+		{"name": "plain", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "b"}},
+		{"name": "wrong", "inputs": ["a = b"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "x"}}
+	]}`
+	os.WriteFile(filepath.Join(dir, "generated_tests", "api.json"), []byte(flat), 0644)
+	overridesPath := filepath.Join(dir, "overrides.json")
+	os.WriteFile(overridesPath, []byte(`{"spaced_key": {"expected": "c", "reason": "keys keep inner whitespace"}}`), 0644)
+
+	tests, err := loader.NewTestLoader(dir, config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{
+		Format:        loader.FormatFlat,
+		FilterMode:    loader.FilterAll,
+		OverridesPath: overridesPath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	results := runner.Run(toyccl.New(), tests, runner.RunOptions{})
+
+	expected := `2 passed, 1 failed, 0 not run
+
+| Test | Function | Status | Notes |
+|---|---|---|---|
+| spaced_key | get_string | pass | override: keys keep inner whitespace |
+| wrong | get_string | fail | expected "x", got "b" |
+`
+	if got := ResultsMarkdown(results); got != expected {
+		t.Errorf("ResultsMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+}
@@ -0,0 +1,287 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// StatisticsMarkdown renders test statistics as markdown tables
+func StatisticsMarkdown(stats types.TestStatistics) string {
+	var b strings.Builder
+	if stats.SourceFallback {
+		b.WriteString("> No generated tests were found; these were flattened from the source tests in memory.\n\n")
+	}
+	b.WriteString("| Metric | Count |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Total tests | %d |\n", stats.TotalTests)
+	fmt.Fprintf(&b, "| Compatible tests | %d |\n", stats.CompatibleTests)
+	if stats.SkippedTests > 0 {
+		fmt.Fprintf(&b, "| Skipped tests | %d |\n", stats.SkippedTests)
+	}
+	if stats.UnknownTests > 0 {
+		fmt.Fprintf(&b, "| Tests with unknown metadata | %d |\n", stats.UnknownTests)
+	}
+
+	writeCountTable(&b, "Function", functionCounts(stats))
+	writeCountTable(&b, "Feature", stats.ByFeature)
+	writeCountTable(&b, "Suite", stats.BySuite)
+	writeCountTable(&b, "Level", levelCounts(stats))
+	if len(stats.ConflictingSets) > 0 {
+		b.WriteString("\n| Behavior or variant | Rejection | Tests |\n|---|---|---:|\n")
+		for _, set := range stats.ConflictingSets {
+			fmt.Fprintf(&b, "| %s | %s | %d |\n", escapeCell(strings.Join(set.ConflictsWith, ", ")), set.ConflictType, set.TestCount)
+		}
+	}
+	return b.String()
+}
+
+// StatisticsByFile groups test statistics by the file the tests come from
+type StatisticsByFile struct {
+	Overall types.TestStatistics   `json:"overall"`
+	Files   []types.FileStatistics `json:"files"`
+}
+
+// StatisticsByFileMarkdown renders a table of per-file subtotals with the
+// overall totals, then a collapsible section of StatisticsMarkdown tables
+// for each file. Files are listed in name order.
+func StatisticsByFileMarkdown(stats StatisticsByFile) string {
+	files := slices.Clone(stats.Files)
+	slices.SortStableFunc(files, func(a, b types.FileStatistics) int { return strings.Compare(a.File, b.File) })
+	skipped := stats.Overall.SkippedTests > 0
+
+	var b strings.Builder
+	if skipped {
+		b.WriteString("| File | Tests | Compatible | Skipped |\n|---|---:|---:|---:|\n")
+	} else {
+		b.WriteString("| File | Tests | Compatible |\n|---|---:|---:|\n")
+	}
+	row := func(name string, stats types.TestStatistics) {
+		fmt.Fprintf(&b, "| %s | %d | %d |", name, stats.TotalTests, stats.CompatibleTests)
+		if skipped {
+			fmt.Fprintf(&b, " %d |", stats.SkippedTests)
+		}
+		b.WriteString("\n")
+	}
+	for _, file := range files {
+		row(escapeCell(file.File), file.Stats)
+	}
+	row("**Total**", stats.Overall)
+
+	for _, file := range files {
+		fmt.Fprintf(&b, "\n<details>\n<summary>%s: %d of %d tests compatible</summary>\n\n", html.EscapeString(file.File), file.Stats.CompatibleTests, file.Stats.TotalTests)
+		b.WriteString(StatisticsMarkdown(file.Stats))
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+// ConfigWarningsMarkdown renders the config's lint warnings as a markdown
+// quote to print above a report, or "" if there are none
+func ConfigWarningsMarkdown(cfg config.ImplementationConfig) string {
+	warnings := cfg.Lint()
+	if len(warnings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("> **Config warnings**\n")
+	for _, warning := range warnings {
+		fmt.Fprintf(&b, "> - %s\n", warning)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// BlockedMarkdown renders the limit most common reasons tests are
+// incompatible as a markdown table, most blocking first, or "" if none are
+func BlockedMarkdown(incompatible []loader.IncompatibleTest, limit int) string {
+	counts := make(map[string]int)
+	for _, test := range incompatible {
+		counts[test.Reason]++
+	}
+	reasons := sortedKeys(counts)
+	sort.SliceStable(reasons, func(i, j int) bool { return counts[reasons[i]] > counts[reasons[j]] })
+	if len(reasons) > limit {
+		reasons = reasons[:limit]
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n## Top blocked capabilities\n\n| Blocked by | Tests |\n|---|---:|\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(&b, "| %s | %d |\n", escapeCell(reason), counts[reason])
+	}
+	return b.String()
+}
+
+// StatisticsCSV renders test statistics as category,name,count rows
+func StatisticsCSV(stats types.TestStatistics) string {
+	rows := [][]string{
+		{"category", "name", "count"},
+		{"summary", "total_tests", strconv.Itoa(stats.TotalTests)},
+		{"summary", "compatible_tests", strconv.Itoa(stats.CompatibleTests)},
+	}
+	if stats.SkippedTests > 0 {
+		rows = append(rows, []string{"summary", "skipped_tests", strconv.Itoa(stats.SkippedTests)})
+	}
+	if stats.SourceFallback {
+		rows = append(rows, []string{"summary", "source_fallback", "1"})
+	}
+	if stats.UnknownTests > 0 {
+		rows = append(rows, []string{"summary", "unknown_tests", strconv.Itoa(stats.UnknownTests)})
+	}
+	byFunction := functionCounts(stats)
+	for _, name := range sortedKeys(byFunction) {
+		rows = append(rows, []string{"function", name, strconv.Itoa(byFunction[name])})
+	}
+	for _, name := range sortedKeys(stats.ByFeature) {
+		rows = append(rows, []string{"feature", name, strconv.Itoa(stats.ByFeature[name])})
+	}
+	for _, name := range sortedKeys(stats.BySuite) {
+		rows = append(rows, []string{"suite", name, strconv.Itoa(stats.BySuite[name])})
+	}
+	byLevel := levelCounts(stats)
+	for _, level := range sortedKeys(byLevel) {
+		rows = append(rows, []string{"level", level, strconv.Itoa(byLevel[level])})
+	}
+	for _, set := range stats.ConflictingSets {
+		rows = append(rows, []string{set.ConflictType, strings.Join(set.ConflictsWith, " "), strconv.Itoa(set.TestCount)})
+	}
+	return writeCSV(rows)
+}
+
+// CoverageMarkdown renders capability coverage as a markdown table
+func CoverageMarkdown(coverage loader.CapabilityCoverage) string {
+	var b strings.Builder
+	b.WriteString("| Capability | Kind | Available | Compatible |\n|---|---|---:|---:|\n")
+	for _, row := range coverageRows(coverage) {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", row[0], row[1], row[2], row[3])
+	}
+	return b.String()
+}
+
+// CoverageCSV renders capability coverage as name,kind,available,compatible rows
+func CoverageCSV(coverage loader.CapabilityCoverage) string {
+	rows := append([][]string{{"name", "kind", "available", "compatible"}}, coverageRows(coverage)...)
+	return writeCSV(rows)
+}
+
+// coverageRows returns functions then features, each sorted by name
+func coverageRows(coverage loader.CapabilityCoverage) [][]string {
+	var rows [][]string
+	functions := make([]string, 0, len(coverage.Functions))
+	for fn := range coverage.Functions {
+		functions = append(functions, string(fn))
+	}
+	sort.Strings(functions)
+	for _, fn := range functions {
+		info := coverage.Functions[config.CCLFunction(fn)]
+		rows = append(rows, []string{fn, "function", strconv.Itoa(info.Available), strconv.Itoa(info.Compatible)})
+	}
+
+	features := make([]string, 0, len(coverage.Features))
+	for feature := range coverage.Features {
+		features = append(features, string(feature))
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		info := coverage.Features[config.CCLFeature(feature)]
+		rows = append(rows, []string{feature, "feature", strconv.Itoa(info.Available), strconv.Itoa(info.Compatible)})
+	}
+	return rows
+}
+
+// functionCounts returns the per-function counts to report: one per test
+// from ByValidation, or the ByFunction union for source suites
+func functionCounts(stats types.TestStatistics) map[string]int {
+	if len(stats.ByValidation) > 0 {
+		return stats.ByValidation
+	}
+	return stats.ByFunction
+}
+
+// levelCounts keys ByLevel by the level's digits, which sort in level
+// order since levels are single digits
+func levelCounts(stats types.TestStatistics) map[string]int {
+	counts := make(map[string]int, len(stats.ByLevel))
+	for level, count := range stats.ByLevel {
+		counts[strconv.Itoa(level)] = count
+	}
+	return counts
+}
+
+func writeCountTable(b *strings.Builder, heading string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n| %s | Tests |\n|---|---:|\n", heading)
+	for _, name := range sortedKeys(counts) {
+		fmt.Fprintf(b, "| %s | %d |\n", escapeCell(name), counts[name])
+	}
+}
+
+func writeCSV(rows [][]string) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.WriteAll(rows) // Writes to a buffer cannot fail
+	return buf.String()
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// StatisticsRegressions lists the counts in current that fell below
+// baseline: total tests and each function and feature in the baseline.
+// Functions compare by ByValidation, or by ByFunction against a baseline
+// saved without it.
+func StatisticsRegressions(baseline, current types.TestStatistics) []string {
+	var regressions []string
+	if current.TotalTests < baseline.TotalTests {
+		regressions = append(regressions, fmt.Sprintf("total tests: %d -> %d", baseline.TotalTests, current.TotalTests))
+	}
+	before, after := baseline.ByFunction, current.ByFunction
+	if len(baseline.ByValidation) > 0 {
+		before, after = baseline.ByValidation, current.ByValidation
+	}
+	for _, name := range sortedKeys(before) {
+		if after[name] < before[name] {
+			regressions = append(regressions, fmt.Sprintf("function %s: %d -> %d", name, before[name], after[name]))
+		}
+	}
+	for _, name := range sortedKeys(baseline.ByFeature) {
+		if current.ByFeature[name] < baseline.ByFeature[name] {
+			regressions = append(regressions, fmt.Sprintf("feature %s: %d -> %d", name, baseline.ByFeature[name], current.ByFeature[name]))
+		}
+	}
+	return regressions
+}
+
+// ExpectedSizesMarkdown renders the limit largest expected values as a
+// markdown table, or "" if there are none
+func ExpectedSizesMarkdown(sizes []types.ExpectedSize, limit int) string {
+	if len(sizes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n## Largest expected values\n\n| Test | Suite | Bytes |\n|---|---|---:|\n")
+	for _, size := range sizes[:min(limit, len(sizes))] {
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", escapeCell(size.Test), escapeCell(size.Suite), size.Bytes)
+	}
+	return b.String()
+}
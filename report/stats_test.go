@@ -0,0 +1,349 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func createReportStatistics() types.TestStatistics {
+	return types.TestStatistics{
+		TotalTests:      10,
+		CompatibleTests: 7,
+		ByValidation:    map[string]int{"parse": 6, "get_string": 4},
+		ByFunction:      map[string]int{"parse": 12, "get_string": 8},
+		ByFeature:       map[string]int{"comments": 2},
+		BySuite:         map[string]int{"core": 6},
+		ByLevel:         map[int]int{1: 6, 2: 4},
+		ConflictingSets: []types.ConflictSummary{
+			{ConflictType: "requirement", ConflictsWith: []string{"boolean_strict"}, TestCount: 2, AssertCount: 2},
+			{ConflictType: "conflict", ConflictsWith: []string{"crlf_preserve_literal"}, TestCount: 1, AssertCount: 1},
+		},
+	}
+}
+
+func TestStatisticsMarkdown(t *testing.T) {
+	expected := `| Metric | Count |
+|---|---:|
+| Total tests | 10 |
+| Compatible tests | 7 |
+
+| Function | Tests |
+|---|---:|
+| get_string | 4 |
+| parse | 6 |
+
+| Feature | Tests |
+|---|---:|
+| comments | 2 |
+
+| Suite | Tests |
+|---|---:|
+| core | 6 |
+
+| Level | Tests |
+|---|---:|
+| 1 | 6 |
+| 2 | 4 |
+
+| Behavior or variant | Rejection | Tests |
+|---|---|---:|
+| boolean_strict | requirement | 2 |
+| crlf_preserve_literal | conflict | 1 |
+`
+	if got := StatisticsMarkdown(createReportStatistics()); got != expected {
+		t.Errorf("StatisticsMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+}
+
+func TestStatistics_SourceFallback(t *testing.T) {
+	stats := types.TestStatistics{TotalTests: 2, CompatibleTests: 2, SourceFallback: true}
+	if got := StatisticsMarkdown(stats); !strings.HasPrefix(got, "> No generated tests were found") {
+		t.Errorf("Expected a fallback note first, got:\n%s", got)
+	}
+	if got := StatisticsCSV(stats); !strings.Contains(got, "summary,source_fallback,1\n") {
+		t.Errorf("Expected a source_fallback row, got:\n%s", got)
+	}
+}
+
+func TestConfigWarningsMarkdown(t *testing.T) {
+	cfg := config.ImplementationConfig{BehaviorChoices: []config.CCLBehavior{
+		config.BehaviorCRLFNormalize, config.BehaviorTabsAsContent, config.BehaviorIndentSpaces, config.BehaviorBooleanStrict,
+	}}
+	expected := `> **Config warnings**
+> - no list_coercion behavior chosen (list_coercion_enabled or list_coercion_disabled); tests requiring either are incompatible
+
+`
+	if got := ConfigWarningsMarkdown(cfg); got != expected {
+		t.Errorf("ConfigWarningsMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+
+	cfg.BehaviorChoices = append(cfg.BehaviorChoices, config.BehaviorListCoercionOff)
+	if got := ConfigWarningsMarkdown(cfg); got != "" {
+		t.Errorf("Expected no warnings, got:\n%s", got)
+	}
+}
+
+func TestBlockedMarkdown(t *testing.T) {
+	incompatible := []loader.IncompatibleTest{
+		{Test: types.TestCase{Name: "a"}, Reason: "unsupported feature comments"},
+		{Test: types.TestCase{Name: "b"}, Reason: "unsupported function filter"},
+		{Test: types.TestCase{Name: "c"}, Reason: "unsupported function filter"},
+		{Test: types.TestCase{Name: "d"}, Reason: "requires behavior boolean_strict"},
+	}
+	expected := `
+## Top blocked capabilities
+
+| Blocked by | Tests |
+|---|---:|
+| unsupported function filter | 2 |
+| requires behavior boolean_strict | 1 |
+`
+	if got := BlockedMarkdown(incompatible, 2); got != expected {
+		t.Errorf("BlockedMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+	if got := BlockedMarkdown(nil, 10); got != "" {
+		t.Errorf("Expected nothing without incompatible tests, got:\n%s", got)
+	}
+}
+
+func TestStatisticsCSV(t *testing.T) {
+	expected := `category,name,count
+summary,total_tests,10
+summary,compatible_tests,7
+function,get_string,4
+function,parse,6
+feature,comments,2
+suite,core,6
+level,1,6
+level,2,4
+requirement,boolean_strict,2
+conflict,crlf_preserve_literal,1
+`
+	if got := StatisticsCSV(createReportStatistics()); got != expected {
+		t.Errorf("StatisticsCSV mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+}
+
+func TestCoverageMarkdownAndCSV(t *testing.T) {
+	coverage := loader.CapabilityCoverage{
+		Functions: map[config.CCLFunction]loader.CoverageInfo{
+			config.FunctionParse:     {Available: 6, Compatible: 6},
+			config.FunctionGetString: {Available: 4, Compatible: 3},
+		},
+		Features: map[config.CCLFeature]loader.CoverageInfo{
+			config.FeatureComments: {Available: 2, Compatible: 1},
+		},
+	}
+
+	expectedMarkdown := `| Capability | Kind | Available | Compatible |
+|---|---|---:|---:|
+| get_string | function | 4 | 3 |
+| parse | function | 6 | 6 |
+| comments | feature | 2 | 1 |
+`
+	if got := CoverageMarkdown(coverage); got != expectedMarkdown {
+		t.Errorf("CoverageMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expectedMarkdown)
+	}
+
+	expectedCSV := `name,kind,available,compatible
+get_string,function,4,3
+parse,function,6,6
+comments,feature,2,1
+`
+	if got := CoverageCSV(coverage); got != expectedCSV {
+		t.Errorf("CoverageCSV mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expectedCSV)
+	}
+}
+
+func TestStatisticsRegressions(t *testing.T) {
+	baseline := createReportStatistics()
+	if got := StatisticsRegressions(baseline, baseline); len(got) != 0 {
+		t.Errorf("Expected no regressions against itself, got %v", got)
+	}
+
+	current := types.TestStatistics{
+		TotalTests:   12,
+		ByValidation: map[string]int{"parse": 5, "get_string": 7, "get_int": 1},
+		ByFunction:   map[string]int{"parse": 10, "get_string": 14, "get_int": 2},
+		ByFeature:    map[string]int{},
+	}
+	want := []string{"function parse: 6 -> 5", "feature comments: 2 -> 0"}
+	if got := StatisticsRegressions(baseline, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Baselines saved before ByValidation existed compare the union
+	baseline.ByValidation = nil
+	want = []string{"function parse: 12 -> 10", "feature comments: 2 -> 0"}
+	if got := StatisticsRegressions(baseline, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestExpectedSizesMarkdown(t *testing.T) {
+	sizes := []types.ExpectedSize{{Test: "huge", Bytes: 2<<20 + 2}, {Test: "tiny", Suite: "typed", Bytes: 12}}
+
+	got := ExpectedSizesMarkdown(sizes, 1)
+	if got != "\n## Largest expected values\n\n| Test | Suite | Bytes |\n|---|---|---:|\n| huge |  | 2097154 |\n" {
+		t.Errorf("Unexpected markdown:\n%s", got)
+	}
+	if ExpectedSizesMarkdown(nil, 5) != "" {
+		t.Error("Expected no section without sizes")
+	}
+}
+
+// statisticsByFileFixture writes three generated test files and returns
+// their statistics for a parse and get_string implementation
+func statisticsByFileFixture(t *testing.T) StatisticsByFile {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "generated_tests"), 0755)
+	files := map[string]string{
+		"parsing.json": `{"tests": [
+			{"name": "pair_parse", "inputs": ["a = 1"], "validation": "parse", "expected": {"count": 1}, "level": 1},
+			{"name": "pairs_parse", "inputs": ["a = 1\nb = 2"], "validation": "parse", "expected": {"count": 2}, "level": 1}
+		]}`,
+		"typed.json": `{"tests": [
+			{"name": "text_get_string", "inputs": ["a = x"], "validation": "get_string", "args": ["a"], "expected": {"count": 1, "value": "x"}, "level": 2},
+			{"name": "number_get_int", "inputs": ["a = 1"], "validation": "get_int", "args": ["a"], "expected": {"count": 1, "value": 1}, "level": 2}
+		]}`,
+		"features.json": `{"tests": [
+			{"name": "comment_parse", "inputs": ["/= note"], "validation": "parse", "expected": {"count": 1}, "features": ["comments"], "level": 3}
+		]}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, "generated_tests", name), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+
+	cfg := config.ImplementationConfig{
+		SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionGetString},
+		SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+	}
+	testLoader := loader.NewTestLoader(dir, cfg)
+	opts := loader.LoadOptions{Format: loader.FormatFlat, FilterMode: loader.FilterAll}
+	tests, err := testLoader.LoadAllTests(opts)
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	byFile, err := testLoader.GetStatisticsByFile(opts)
+	if err != nil {
+		t.Fatalf("GetStatisticsByFile failed: %v", err)
+	}
+	return StatisticsByFile{Overall: testLoader.GetTestStatistics(tests), Files: byFile}
+}
+
+func TestStatisticsByFileMarkdown(t *testing.T) {
+	expected := `| File | Tests | Compatible |
+|---|---:|---:|
+| features.json | 1 | 1 |
+| parsing.json | 2 | 2 |
+| typed.json | 2 | 1 |
+| **Total** | 5 | 4 |
+
+<details>
+<summary>features.json: 1 of 1 tests compatible</summary>
+
+| Metric | Count |
+|---|---:|
+| Total tests | 1 |
+| Compatible tests | 1 |
+
+| Function | Tests |
+|---|---:|
+| parse | 1 |
+
+| Feature | Tests |
+|---|---:|
+| comments | 1 |
+
+| Level | Tests |
+|---|---:|
+| 3 | 1 |
+
+</details>
+
+<details>
+<summary>parsing.json: 2 of 2 tests compatible</summary>
+
+| Metric | Count |
+|---|---:|
+| Total tests | 2 |
+| Compatible tests | 2 |
+
+| Function | Tests |
+|---|---:|
+| parse | 2 |
+
+| Level | Tests |
+|---|---:|
+| 1 | 2 |
+
+</details>
+
+<details>
+<summary>typed.json: 1 of 2 tests compatible</summary>
+
+| Metric | Count |
+|---|---:|
+| Total tests | 2 |
+| Compatible tests | 1 |
+
+| Function | Tests |
+|---|---:|
+| get_int | 1 |
+| get_string | 1 |
+
+| Level | Tests |
+|---|---:|
+| 2 | 2 |
+
+</details>
+`
+	stats := statisticsByFileFixture(t)
+	if got := StatisticsByFileMarkdown(stats); got != expected {
+		t.Errorf("StatisticsByFileMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, expected)
+	}
+
+	// Input order does not change the output
+	stats.Files[0], stats.Files[2] = stats.Files[2], stats.Files[0]
+	if got := StatisticsByFileMarkdown(stats); got != expected {
+		t.Errorf("Expected files sorted by name, got:\n%s", got)
+	}
+}
+
+func TestStatisticsByFile_JSON(t *testing.T) {
+	data, err := json.Marshal(statisticsByFileFixture(t))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded struct {
+		Overall struct{ TotalTests int } `json:"overall"`
+		Files   []struct {
+			File  string `json:"file"`
+			Stats struct{ TotalTests, CompatibleTests int }
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	var files []string
+	for _, file := range decoded.Files {
+		files = append(files, file.File)
+	}
+	if !reflect.DeepEqual(files, []string{"features.json", "parsing.json", "typed.json"}) || decoded.Overall.TotalTests != 5 {
+		t.Errorf("Unexpected grouping: %s", data)
+	}
+	if typed := decoded.Files[2].Stats; typed.TotalTests != 2 || typed.CompatibleTests != 1 {
+		t.Errorf("Unexpected typed.json subtotals: %+v", typed)
+	}
+}
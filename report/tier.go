@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// ComputeAchievedTier returns the highest tier whose functions and features
+// the run proved: each has at least one passing test and no failing test.
+// Skipped and not-run tests prove nothing either way, so a config can
+// declare a higher tier (config.ComputeTier) than its results achieve.
+func ComputeAchievedTier(results *runner.RunResults) config.Tier {
+	passed := make(map[string]bool)
+	failed := make(map[string]bool)
+	for _, result := range results.Results {
+		var outcome map[string]bool
+		switch result.Status {
+		case runner.StatusPass:
+			outcome = passed
+		case runner.StatusFail:
+			outcome = failed
+		default:
+			continue
+		}
+		outcome["function:"+result.Validation] = true
+		for _, feature := range result.Features {
+			outcome["feature:"+feature] = true
+		}
+	}
+	proven := func(key string) bool { return passed[key] && !failed[key] }
+	return config.HighestTier(
+		func(fn config.CCLFunction) bool { return proven("function:" + string(fn)) },
+		func(feature config.CCLFeature) bool { return proven("feature:" + string(feature)) },
+	)
+}
+
+// Badge is a shields.io endpoint badge
+// (https://shields.io/badges/endpoint-badge)
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// tierColors are the badge colors by tier level
+var tierColors = []string{"lightgrey", "yellow", "green", "brightgreen"}
+
+// TierBadge returns the badge for a conformance tier
+func TierBadge(tier config.Tier) Badge {
+	color := tierColors[min(tier.Level, len(tierColors)-1)]
+	return Badge{SchemaVersion: 1, Label: "CCL conformance", Message: tier.Name(), Color: color}
+}
+
+// JSON renders the badge for a shields.io endpoint
+func (b Badge) JSON() string {
+	data, _ := json.Marshal(b)
+	return string(data)
+}
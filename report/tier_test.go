@@ -0,0 +1,78 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/runner"
+)
+
+// tierResults passes one test of each function and feature tier 3 needs
+func tierResults() []runner.TestResult {
+	var results []runner.TestResult
+	for _, fn := range []string{"parse", "build_hierarchy", "get_string", "get_int", "get_bool", "get_float", "get_list"} {
+		results = append(results, runner.TestResult{Name: fn + "_test", Validation: fn, Status: runner.StatusPass})
+	}
+	return append(results, runner.TestResult{
+		Name:       "features_parse",
+		Validation: "parse",
+		Features:   []string{"comments", "multiline", "unicode", "whitespace"},
+		Status:     runner.StatusPass,
+	})
+}
+
+func TestComputeAchievedTier_Boundaries(t *testing.T) {
+	all := tierResults()
+	failing := func(result runner.TestResult) runner.TestResult {
+		result.Name += "_broken"
+		result.Status = runner.StatusFail
+		return result
+	}
+	skipped := all[2]
+	skipped.Status = runner.StatusSkipped
+
+	tests := []struct {
+		name    string
+		results []runner.TestResult
+		want    int
+	}{
+		{"no results", nil, 0},
+		{"parse only", all[:1], 0},
+		{"tier 1 exactly", all[:2], 1},
+		{"tier 1 with a failing hierarchy test", append(all[:2:2], failing(all[1])), 0},
+		{"tier 2 less get_list", all[:6], 1},
+		{"tier 2 exactly", all[:7], 2},
+		{"tier 2 with get_string only skipped", append(append(all[:2:2], skipped), all[3:7]...), 1},
+		{"tier 3 exactly", all, 3},
+		{"tier 3 with a failing unicode test", append(all[:8:8], failing(runner.TestResult{Validation: "filter", Features: []string{"unicode"}})), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeAchievedTier(&runner.RunResults{Results: tt.results}); got.Level != tt.want {
+				t.Errorf("Expected tier %d, got %d", tt.want, got.Level)
+			}
+		})
+	}
+}
+
+func TestTierBadge(t *testing.T) {
+	tier := ComputeAchievedTier(&runner.RunResults{Results: tierResults()[:7]})
+	want := `{"schemaVersion":1,"label":"CCL conformance","message":"Tier 2","color":"green"}`
+	if got := TierBadge(tier).JSON(); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got := TierBadge(ComputeAchievedTier(&runner.RunResults{})); got.Message != "No tier" || got.Color != "lightgrey" {
+		t.Errorf("Unexpected badge without a tier: %+v", got)
+	}
+}
+
+func TestResultsMarkdown_Tier(t *testing.T) {
+	results := &runner.RunResults{Results: tierResults()[:2], Passed: 2}
+	want := "2 passed, 0 failed, 0 not run\n\nConformance: Tier 1 (parsing and hierarchy)\n"
+	if got := ResultsMarkdown(results); got != want {
+		t.Errorf("ResultsMarkdown mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+	if strings.Contains(ResultsMarkdown(&runner.RunResults{}), "Conformance") {
+		t.Error("Expected no tier line without a tier")
+	}
+}
@@ -0,0 +1,197 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// checkResult compares an implementation's output against a test's expectation.
+// It returns an empty string when the result matches, or a failure message.
+// Entry lists are compared positionally, or as multisets with
+// UnorderedEntries. Numbers are compared within the test's tolerance,
+// exactly when it is zero. Strings are normalized first when the test or
+// the options ask for it. Formatting output ignores trailing newlines with
+// IgnoreTrailingNewline. build_hierarchy objects are walked path by path,
+// with typed leaves matched as HierarchyLeaves says. Tests with a default
+// may not expect an error. ErrNotSupported fails every test, including
+// error tests. An error test with an ErrorType fails on an error of another
+// type, when the implementation reports one; any error matches otherwise.
+func checkResult(test types.TestCase, actual interface{}, err error, opts RunOptions) string {
+	if test.HasDefault() && test.ExpectsError() {
+		return "a test with a default must not expect an error; a missing key returns the default"
+	}
+	if errors.Is(err, ErrNotSupported) {
+		return fmt.Sprintf("not supported: %v", err)
+	}
+	if test.ExpectError {
+		if err == nil {
+			return "expected an error, got none"
+		}
+		var subErr *SubprocessError
+		if test.ErrorType != "" && errors.As(err, &subErr) && subErr.ErrorType != "" && subErr.ErrorType != test.ErrorType {
+			return fmt.Sprintf("expected a %s error, got %v", test.ErrorType, err)
+		}
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf("unexpected error: %v", err)
+	}
+
+	decoded, decodeErr := test.DecodeExpected()
+	if decodeErr != nil {
+		return fmt.Sprintf("invalid expected value: %v", decodeErr)
+	}
+	expected, normErr := normalizeValue(decoded)
+	if normErr != nil {
+		return fmt.Sprintf("invalid expected value: %v", normErr)
+	}
+	got, normErr := normalizeValue(actual)
+	if normErr != nil {
+		return fmt.Sprintf("invalid actual value: %v", normErr)
+	}
+
+	normalization := opts.UnicodeNormalization
+	if test.UnicodeNormalization != "" {
+		normalization = test.UnicodeNormalization
+	}
+	if !normalization.Known() {
+		return fmt.Sprintf("unsupported unicode normalization %q", normalization)
+	}
+	expected = normalizeStrings(expected, normalization)
+	got = normalizeStrings(got, normalization)
+	if opts.IgnoreTrailingNewline && isFormatting(test.Validation) {
+		expected = trimTrailingNewlines(expected)
+		got = trimTrailingNewlines(got)
+	}
+
+	limit := opts.DiffMaxValueLength
+	if limit == 0 {
+		limit = DiffMaxValueLength
+	}
+	if test.Validation == string(config.FunctionBuildHierarchy) {
+		if !opts.HierarchyLeaves.Known() {
+			return fmt.Sprintf("unknown hierarchy leaf mode %q", opts.HierarchyLeaves)
+		}
+		_, expObj := expected.(map[string]interface{})
+		_, gotObj := got.(map[string]interface{})
+		if expObj && gotObj {
+			diff := Diff{Kind: DiffHierarchy, Expected: expected, Actual: got}
+			diffPaths("", expected, got, leavesEqual(opts.HierarchyLeaves), &diff.Paths)
+			if diff.Equal() {
+				return ""
+			}
+			maxPaths := opts.HierarchyMaxPaths
+			if maxPaths == 0 {
+				maxPaths = DiffMaxPaths
+			}
+			return formatHierarchyDiff(diff, limit, maxPaths)
+		}
+	}
+
+	if opts.UnorderedEntries && sameEntryMultiset(expected, got) {
+		return ""
+	}
+	if test.Tolerance > 0 {
+		expNum, expOK := expected.(float64)
+		gotNum, gotOK := got.(float64)
+		if expOK && gotOK {
+			if math.Abs(expNum-gotNum) <= test.Tolerance {
+				return ""
+			}
+			return fmt.Sprintf("expected %v (±%v), got %v", expNum, test.Tolerance, gotNum)
+		}
+	}
+	if !reflect.DeepEqual(expected, got) {
+		return FormatDiffLimit(diffNormalized(expected, got), limit)
+	}
+	return ""
+}
+
+// isFormatting reports whether a validation renders text
+func isFormatting(validation string) bool {
+	return validation == string(config.FunctionPrettyPrint) || validation == string(config.FunctionCanonicalFormat)
+}
+
+// trimTrailingNewlines removes trailing line endings from a string value
+func trimTrailingNewlines(value interface{}) interface{} {
+	if text, ok := value.(string); ok {
+		return strings.TrimRight(text, "\r\n")
+	}
+	return value
+}
+
+// sameEntryMultiset reports whether two normalized values are entry lists
+// holding the same entries, in any order. Repeated keys count separately, so
+// a duplicate entry must appear as many times on both sides.
+func sameEntryMultiset(expected, actual interface{}) bool {
+	expEntries, expOK := asEntryList(expected)
+	gotEntries, gotOK := asEntryList(actual)
+	if !expOK || !gotOK || len(expEntries) != len(gotEntries) {
+		return false
+	}
+	counts := make(map[DiffEntry]int, len(expEntries))
+	for _, entry := range expEntries {
+		counts[entry]++
+	}
+	for _, entry := range gotEntries {
+		if counts[entry] == 0 {
+			return false
+		}
+		counts[entry]--
+	}
+	return true
+}
+
+// normalizeValue converts a value to its generic JSON representation so that
+// Go-typed implementation results compare equal to JSON-decoded expectations
+// (e.g. []types.Entry vs []interface{} of maps, int vs float64).
+func normalizeValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// normalizeStrings applies a Unicode normalization to every string, key,
+// and value in a normalized value
+func normalizeStrings(value interface{}, normalization types.UnicodeNormalization) interface{} {
+	if normalization == "" || normalization == types.NormalizationNone {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return normalization.Apply(v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeStrings(item, normalization)
+		}
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[normalization.Apply(key)] = normalizeStrings(item, normalization)
+		}
+		return normalized
+	}
+	return value
+}
+
+// formatValue renders a normalized value as compact JSON for messages
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// SizeBucket groups test inputs by size for benchmarking
+type SizeBucket string
+
+const (
+	BucketSmall  SizeBucket = "small"  // Inputs under 256 bytes
+	BucketMedium SizeBucket = "medium" // Inputs under 4 KiB
+	BucketLarge  SizeBucket = "large"  // Inputs of 4 KiB or more
+)
+
+// Size bucket upper bounds in bytes
+const (
+	smallInputLimit  = 256
+	mediumInputLimit = 4096
+)
+
+// AllSizeBuckets returns the size buckets in ascending order
+func AllSizeBuckets() []SizeBucket {
+	return []SizeBucket{BucketSmall, BucketMedium, BucketLarge}
+}
+
+// BucketForSize returns the size bucket for an input of the given length in bytes
+func BucketForSize(size int) SizeBucket {
+	switch {
+	case size < smallInputLimit:
+		return BucketSmall
+	case size < mediumInputLimit:
+		return BucketMedium
+	default:
+		return BucketLarge
+	}
+}
+
+// BucketTests groups tests by the size of their inputs
+func BucketTests(tests []types.TestCase) map[SizeBucket][]types.TestCase {
+	buckets := make(map[SizeBucket][]types.TestCase)
+	for _, test := range tests {
+		bucket := BucketForSize(inputSize(test))
+		buckets[bucket] = append(buckets[bucket], test)
+	}
+	return buckets
+}
+
+// LargestInputs returns the n tests with the largest inputs, largest first,
+// or none when n is not positive. Ties are broken by test name so the
+// selection is deterministic.
+func LargestInputs(tests []types.TestCase, n int) []types.TestCase {
+	if n <= 0 {
+		return nil
+	}
+	sorted := make([]types.TestCase, len(tests))
+	copy(sorted, tests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := inputSize(sorted[i]), inputSize(sorted[j])
+		if si != sj {
+			return si > sj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// BenchmarkImplementation benchmarks one CCL function of an implementation
+// over the corpus. Tests are filtered to the given function and grouped into
+// size buckets, each run as a sub-benchmark reporting bytes processed.
+// The benchmark is skipped when the implementation does not support fn.
+func BenchmarkImplementation(b *testing.B, impl CCLImplementation, tests []types.TestCase, fn config.CCLFunction) {
+	var fnTests []types.TestCase
+	for _, test := range tests {
+		if test.Validation == string(fn) && len(test.Inputs) > 0 {
+			fnTests = append(fnTests, test)
+		}
+	}
+	if len(fnTests) == 0 {
+		b.Skipf("no tests for function %s", fn)
+	}
+
+	// Probe once so unsupported functions don't produce meaningless numbers
+	if _, err := invoke(impl, fn, fnTests[0].Inputs, fnTests[0].Args); errors.Is(err, ErrNotSupported) {
+		b.Skipf("implementation does not support %s", fn)
+	}
+
+	buckets := BucketTests(fnTests)
+	for _, bucket := range AllSizeBuckets() {
+		bucketTests := buckets[bucket]
+		if len(bucketTests) == 0 {
+			continue
+		}
+
+		totalBytes := 0
+		for _, test := range bucketTests {
+			totalBytes += inputSize(test)
+		}
+
+		b.Run(string(bucket), func(b *testing.B) {
+			b.SetBytes(int64(totalBytes))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, test := range bucketTests {
+					// Errors are expected for error tests; only speed matters here
+					_, _ = invoke(impl, fn, test.Inputs, test.Args)
+				}
+			}
+		})
+	}
+}
+
+// inputSize returns the total size in bytes of a test's inputs
+func inputSize(test types.TestCase) int {
+	size := 0
+	for _, input := range test.Inputs {
+		size += len(input)
+	}
+	return size
+}
@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// parseOnlyImplementation supports parse and counts every call
+type parseOnlyImplementation struct {
+	UnimplementedImplementation
+	calls int
+}
+
+func (p *parseOnlyImplementation) Parse(input string) ([]types.Entry, error) {
+	p.calls++
+	return []types.Entry{{Key: "key", Value: "value"}}, nil
+}
+
+func (p *parseOnlyImplementation) BuildHierarchy(input string) (map[string]interface{}, error) {
+	p.calls++
+	return p.UnimplementedImplementation.BuildHierarchy(input)
+}
+
+func createBenchmarkTests() []types.TestCase {
+	return []types.TestCase{
+		{Name: "small_parse", Inputs: []string{"key = value"}, Validation: "parse"},
+		{Name: "medium_parse", Inputs: []string{strings.Repeat("key = value\n", 50)}, Validation: "parse"},
+		{Name: "large_parse", Inputs: []string{strings.Repeat("key = value\n", 500)}, Validation: "parse"},
+		{Name: "small_hierarchy", Inputs: []string{"a =\n  b = c"}, Validation: "build_hierarchy"},
+	}
+}
+
+func TestBucketForSize(t *testing.T) {
+	tests := []struct {
+		size     int
+		expected SizeBucket
+	}{
+		{0, BucketSmall},
+		{255, BucketSmall},
+		{256, BucketMedium},
+		{4095, BucketMedium},
+		{4096, BucketLarge},
+		{1 << 20, BucketLarge},
+	}
+
+	for _, tt := range tests {
+		if got := BucketForSize(tt.size); got != tt.expected {
+			t.Errorf("BucketForSize(%d) = %s, expected %s", tt.size, got, tt.expected)
+		}
+	}
+}
+
+func TestBucketTests(t *testing.T) {
+	buckets := BucketTests(createBenchmarkTests())
+
+	if len(buckets[BucketSmall]) != 2 {
+		t.Errorf("Expected 2 small tests, got %d", len(buckets[BucketSmall]))
+	}
+	if len(buckets[BucketMedium]) != 1 || buckets[BucketMedium][0].Name != "medium_parse" {
+		t.Errorf("Expected medium_parse in medium bucket, got %v", buckets[BucketMedium])
+	}
+	if len(buckets[BucketLarge]) != 1 || buckets[BucketLarge][0].Name != "large_parse" {
+		t.Errorf("Expected large_parse in large bucket, got %v", buckets[BucketLarge])
+	}
+}
+
+func TestLargestInputs(t *testing.T) {
+	largest := LargestInputs(createBenchmarkTests(), 2)
+
+	if len(largest) != 2 {
+		t.Fatalf("Expected 2 tests, got %d", len(largest))
+	}
+	if largest[0].Name != "large_parse" || largest[1].Name != "medium_parse" {
+		t.Errorf("Expected [large_parse medium_parse], got [%s %s]", largest[0].Name, largest[1].Name)
+	}
+
+	// Requesting more than available returns everything
+	if all := LargestInputs(createBenchmarkTests(), 10); len(all) != 4 {
+		t.Errorf("Expected 4 tests, got %d", len(all))
+	}
+
+	for _, n := range []int{0, -1} {
+		if none := LargestInputs(createBenchmarkTests(), n); len(none) != 0 {
+			t.Errorf("Expected no tests for n = %d, got %d", n, len(none))
+		}
+	}
+}
+
+func TestBenchmarkImplementation_RunsSupportedFunction(t *testing.T) {
+	impl := toyccl.New()
+	// A single bucket keeps the benchmark run short
+	tests := createBenchmarkTests()[:1]
+
+	result := testing.Benchmark(func(b *testing.B) {
+		BenchmarkImplementation(b, impl, tests, config.FunctionParse)
+	})
+
+	if result.N == 0 {
+		t.Error("Expected parse benchmark to run")
+	}
+}
+
+func TestBenchmarkImplementation_SkipsUnsupportedFunction(t *testing.T) {
+	impl := &parseOnlyImplementation{}
+
+	testing.Benchmark(func(b *testing.B) {
+		BenchmarkImplementation(b, impl, createBenchmarkTests(), config.FunctionBuildHierarchy)
+	})
+
+	// Only the probe call should have reached the implementation
+	if impl.calls != 1 {
+		t.Errorf("Expected 1 probe call for unsupported function, got %d", impl.calls)
+	}
+}
+
+func TestBenchmarkImplementation_SkipsFunctionWithoutTests(t *testing.T) {
+	impl := &parseOnlyImplementation{}
+
+	testing.Benchmark(func(b *testing.B) {
+		BenchmarkImplementation(b, impl, createBenchmarkTests(), config.FunctionGetInt)
+	})
+
+	if impl.calls != 0 {
+		t.Errorf("Expected no calls without get_int tests, got %d", impl.calls)
+	}
+}
@@ -0,0 +1,149 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// RunComparison describes how the results of two runs differ, pairing each
+// result with the one for the same test in the other run
+type RunComparison struct {
+	Fixed     []ResultChange `json:"fixed"`              // Failed before, pass now
+	Regressed []ResultChange `json:"regressed"`          // Passed before, fail now
+	Renamed   []ResultChange `json:"renamed"`            // Same test ID under a different name
+	Added     []TestResult   `json:"added"`              // Only in the later run
+	Removed   []TestResult   `json:"removed"`            // Only in the earlier run
+	Warnings  []string       `json:"warnings,omitempty"` // Results paired by name because no ID matched
+}
+
+// ResultChange is one test's result in each run
+type ResultChange struct {
+	Before TestResult `json:"before"`
+	After  TestResult `json:"after"`
+}
+
+// CompareRuns pairs the results of two runs by test ID, so renamed tests
+// still line up, and pairs the rest by name. Name pairs are reported in
+// Warnings: the test's content changed, or a run predates result IDs.
+func CompareRuns(before, after *RunResults) RunComparison {
+	comparison := RunComparison{
+		Fixed:     []ResultChange{},
+		Regressed: []ResultChange{},
+		Renamed:   []ResultChange{},
+		Added:     []TestResult{},
+		Removed:   []TestResult{},
+	}
+
+	matches, byName := matchResults(resultKeys(after.Results), resultKeys(before.Results))
+	paired := make(map[int]bool)
+	for i, result := range after.Results {
+		j := matches[i]
+		if j < 0 {
+			comparison.Added = append(comparison.Added, result)
+			continue
+		}
+		paired[j] = true
+		change := ResultChange{Before: before.Results[j], After: result}
+		if byName[i] {
+			comparison.Warnings = append(comparison.Warnings, nameFallbackWarning(result.Name))
+		}
+		if change.Before.Name != result.Name {
+			comparison.Renamed = append(comparison.Renamed, change)
+		}
+		switch {
+		case change.Before.Status == StatusFail && result.Status == StatusPass:
+			comparison.Fixed = append(comparison.Fixed, change)
+		case change.Before.Status == StatusPass && result.Status == StatusFail:
+			comparison.Regressed = append(comparison.Regressed, change)
+		}
+	}
+	for j, result := range before.Results {
+		if !paired[j] {
+			comparison.Removed = append(comparison.Removed, result)
+		}
+	}
+	return comparison
+}
+
+// MatchTests returns the result recorded for each test, keyed by the test's
+// current name. A result matches the test with its ID; results left over
+// match by name, with a warning for each such fallback.
+func (r *RunResults) MatchTests(tests []types.TestCase) (map[string]TestResult, []string) {
+	keys := make([]resultKey, len(tests))
+	for i, test := range tests {
+		keys[i] = resultKey{id: test.ID(), name: test.Name}
+	}
+
+	matches, byName := matchResults(keys, resultKeys(r.Results))
+	found := make(map[string]TestResult)
+	var warnings []string
+	for i, test := range tests {
+		if matches[i] < 0 {
+			continue
+		}
+		found[test.Name] = r.Results[matches[i]]
+		if byName[i] {
+			warnings = append(warnings, nameFallbackWarning(test.Name))
+		}
+	}
+	return found, warnings
+}
+
+func nameFallbackWarning(name string) string {
+	return fmt.Sprintf("%s matched a recorded result by name: its content changed or the result has no ID", name)
+}
+
+// resultKey identifies a test or result for matching
+type resultKey struct {
+	id   string
+	name string
+}
+
+func resultKeys(results []TestResult) []resultKey {
+	keys := make([]resultKey, len(results))
+	for i, result := range results {
+		keys[i] = resultKey{id: result.ID, name: result.Name}
+	}
+	return keys
+}
+
+// matchResults pairs each of want with at most one of have: by ID where
+// both have one, then by name among those left. matches[i] is the index in
+// have, or -1; byName[i] marks a name pair.
+func matchResults(want, have []resultKey) (matches []int, byName []bool) {
+	byID := make(map[string][]int)
+	names := make(map[string][]int)
+	for j, key := range have {
+		if key.id != "" {
+			byID[key.id] = append(byID[key.id], j)
+		}
+		names[key.name] = append(names[key.name], j)
+	}
+
+	matches = make([]int, len(want))
+	byName = make([]bool, len(want))
+	used := make(map[int]bool)
+	take := func(candidates []int) int {
+		for _, j := range candidates {
+			if !used[j] {
+				used[j] = true
+				return j
+			}
+		}
+		return -1
+	}
+	for i, key := range want {
+		matches[i] = -1
+		if key.id != "" {
+			matches[i] = take(byID[key.id])
+		}
+	}
+	for i, key := range want {
+		if matches[i] < 0 {
+			matches[i] = take(names[key.name])
+			byName[i] = matches[i] >= 0
+		}
+	}
+	return matches, byName
+}
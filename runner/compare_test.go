@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// renameVersions returns two versions of the test data: the second renames
+// parse_old to parse_new and fixes the expectation of get_value
+func renameVersions() (before, after []types.TestCase) {
+	before = []types.TestCase{
+		{Name: "parse_old", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")},
+		{Name: "get_value", Inputs: []string{"a = b"}, Validation: "get_string", Args: []string{"a"}, Expected: "wrong"},
+		{Name: "parse_dropped", Inputs: []string{"x = y"}, Validation: "parse", Expected: entries("x", "y")},
+	}
+	after = []types.TestCase{
+		{Name: "parse_new", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")},
+		{Name: "get_value", Inputs: []string{"a = b"}, Validation: "get_string", Args: []string{"a"}, Expected: "b"},
+		{Name: "parse_dropped_old", Inputs: []string{"x = z"}, Validation: "parse", Expected: entries("x", "z")},
+	}
+	return before, after
+}
+
+func TestCompareRuns_MatchesRenamedTestsByID(t *testing.T) {
+	before, after := renameVersions()
+	old := Run(toyccl.New(), before, RunOptions{})
+	current := Run(toyccl.New(), after, RunOptions{})
+
+	comparison := CompareRuns(old, current)
+	if len(comparison.Renamed) != 1 || comparison.Renamed[0].Before.Name != "parse_old" || comparison.Renamed[0].After.Name != "parse_new" {
+		t.Errorf("Expected parse_old renamed to parse_new, got %+v", comparison.Renamed)
+	}
+	if len(comparison.Fixed) != 1 || comparison.Fixed[0].After.Name != "get_value" {
+		t.Errorf("Expected get_value fixed, got %+v", comparison.Fixed)
+	}
+	if len(comparison.Added) != 1 || comparison.Added[0].Name != "parse_dropped_old" ||
+		len(comparison.Removed) != 1 || comparison.Removed[0].Name != "parse_dropped" {
+		t.Errorf("Expected one added and one removed test, got %+v and %+v", comparison.Added, comparison.Removed)
+	}
+	// get_value's content changed, so it could only be paired by name
+	if len(comparison.Warnings) != 1 || !strings.HasPrefix(comparison.Warnings[0], "get_value matched a recorded result by name") {
+		t.Errorf("Expected one name fallback warning, got %v", comparison.Warnings)
+	}
+}
+
+func TestCompareRuns_WithoutIDs(t *testing.T) {
+	before, after := renameVersions()
+	old := Run(toyccl.New(), before, RunOptions{})
+	for i := range old.Results {
+		old.Results[i].ID = "" // As recorded before results carried IDs
+	}
+	current := Run(toyccl.New(), after, RunOptions{})
+
+	// By name alone the renamed test looks removed and re-added
+	comparison := CompareRuns(old, current)
+	if len(comparison.Renamed) != 0 || len(comparison.Added) != 2 || len(comparison.Removed) != 2 {
+		t.Errorf("Expected the rename to be missed without IDs, got %+v", comparison)
+	}
+}
+
+func TestRunResults_MatchTests(t *testing.T) {
+	before, after := renameVersions()
+	old := Run(toyccl.New(), before, RunOptions{})
+
+	matched, warnings := old.MatchTests(after)
+	if matched["parse_new"].Name != "parse_old" || matched["get_value"].Name != "get_value" {
+		t.Errorf("Expected results matched to the renamed and the changed test, got %+v", matched)
+	}
+	if _, ok := matched["parse_dropped_old"]; ok {
+		t.Error("Expected no result for a test with a new name and content")
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected a warning for the name match, got %v", warnings)
+	}
+}
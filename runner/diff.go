@@ -0,0 +1,433 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DiffMaxPaths is the number of divergent hierarchy paths FormatDiff shows
+const DiffMaxPaths = 10
+
+// DiffMaxValueLength is the length in characters at which FormatDiff
+// truncates values
+const DiffMaxValueLength = 60
+
+// DiffKind identifies the shape of the values being compared
+type DiffKind string
+
+const (
+	DiffValue     DiffKind = "value"     // Scalars or mismatched shapes
+	DiffEntries   DiffKind = "entries"   // Key/value entry lists
+	DiffHierarchy DiffKind = "hierarchy" // Nested objects
+)
+
+// ChangeOp describes how one row or path differs
+type ChangeOp string
+
+const (
+	ChangeChanged ChangeOp = "changed"
+	ChangeMissing ChangeOp = "missing" // Present in expected only
+	ChangeExtra   ChangeOp = "extra"   // Present in actual only
+)
+
+// DiffEntry is a key/value pair in an entry list diff
+type DiffEntry struct {
+	Key   string
+	Value string
+}
+
+// EntryChange is one divergent row of an entry list. Indices are -1 when
+// the entry is absent on that side.
+type EntryChange struct {
+	Op            ChangeOp
+	ExpectedIndex int
+	ActualIndex   int
+	Expected      DiffEntry
+	Actual        DiffEntry
+}
+
+// PathChange is one divergent JSON-pointer path of a hierarchy
+type PathChange struct {
+	Op       ChangeOp
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// Diff describes how an actual result differs from the expected one
+type Diff struct {
+	Kind          DiffKind
+	Expected      interface{} // Normalized expected value
+	Actual        interface{} // Normalized actual value
+	ExpectedCount int         // Entry counts (DiffEntries only)
+	ActualCount   int
+	Entries       []EntryChange
+	Paths         []PathChange
+}
+
+// Equal reports whether the diff found no differences
+func (d Diff) Equal() bool {
+	switch d.Kind {
+	case DiffEntries:
+		return len(d.Entries) == 0
+	case DiffHierarchy:
+		return len(d.Paths) == 0
+	default:
+		return reflect.DeepEqual(d.Expected, d.Actual)
+	}
+}
+
+// ComputeDiff compares two values after normalizing them to their JSON form
+func ComputeDiff(expected, actual interface{}) (Diff, error) {
+	exp, err := normalizeValue(expected)
+	if err != nil {
+		return Diff{}, fmt.Errorf("invalid expected value: %w", err)
+	}
+	got, err := normalizeValue(actual)
+	if err != nil {
+		return Diff{}, fmt.Errorf("invalid actual value: %w", err)
+	}
+	return diffNormalized(exp, got), nil
+}
+
+// diffNormalized picks the diff shape for two normalized values
+func diffNormalized(expected, actual interface{}) Diff {
+	diff := Diff{Kind: DiffValue, Expected: expected, Actual: actual}
+
+	expEntries, expOK := asEntryList(expected)
+	gotEntries, gotOK := asEntryList(actual)
+	if expOK && gotOK {
+		diff.Kind = DiffEntries
+		diff.ExpectedCount = len(expEntries)
+		diff.ActualCount = len(gotEntries)
+		diff.Entries = diffEntryLists(expEntries, gotEntries)
+		return diff
+	}
+
+	_, expObj := expected.(map[string]interface{})
+	_, gotObj := actual.(map[string]interface{})
+	if expObj && gotObj {
+		diff.Kind = DiffHierarchy
+		diffPaths("", expected, actual, leavesEqual(LeavesExact), &diff.Paths)
+	}
+	return diff
+}
+
+// asEntryList reports whether a normalized value is a list of key/value objects
+func asEntryList(value interface{}) ([]DiffEntry, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := make([]DiffEntry, 0, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok || len(obj) != 2 {
+			return nil, false
+		}
+		key, keyOK := obj["key"].(string)
+		val, valOK := obj["value"].(string)
+		if !keyOK || !valOK {
+			return nil, false
+		}
+		result = append(result, DiffEntry{Key: key, Value: val})
+	}
+	return result, true
+}
+
+// diffEntryLists aligns two entry lists on their longest common subsequence.
+// Within each gap between aligned entries, rows are paired up as changed and
+// any remainder is reported as missing or extra.
+func diffEntryLists(expected, actual []DiffEntry) []EntryChange {
+	var matches [][2]int
+	alignEntries(expected, actual, 0, 0, &matches)
+
+	var changes []EntryChange
+	gap := func(expFrom, expTo, gotFrom, gotTo int) {
+		paired := min(expTo-expFrom, gotTo-gotFrom)
+		for k := 0; k < paired; k++ {
+			i, j := expFrom+k, gotFrom+k
+			changes = append(changes, EntryChange{Op: ChangeChanged, ExpectedIndex: i, ActualIndex: j, Expected: expected[i], Actual: actual[j]})
+		}
+		for i := expFrom + paired; i < expTo; i++ {
+			changes = append(changes, EntryChange{Op: ChangeMissing, ExpectedIndex: i, ActualIndex: -1, Expected: expected[i]})
+		}
+		for j := gotFrom + paired; j < gotTo; j++ {
+			changes = append(changes, EntryChange{Op: ChangeExtra, ExpectedIndex: -1, ActualIndex: j, Actual: actual[j]})
+		}
+	}
+
+	i, j := 0, 0
+	for _, match := range matches {
+		gap(i, match[0], j, match[1])
+		i, j = match[0]+1, match[1]+1
+	}
+	gap(i, len(expected), j, len(actual))
+	return changes
+}
+
+// alignMaxWork caps the LCS table cells alignEntries computes for one
+// split; larger gaps between common prefixes and suffixes align by position
+const alignMaxWork = 1 << 24
+
+// alignEntries appends to matches the index pairs of a longest common
+// subsequence of a and b, offset by aOff and bOff, in order. It splits a in
+// half and b where the halves' LCS lengths sum highest (Hirschberg), so it
+// needs space linear in the list lengths rather than a full table. Past
+// alignMaxWork, it matches equal entries at the same position instead.
+func alignEntries(a, b []DiffEntry, aOff, bOff int, matches *[][2]int) {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		*matches = append(*matches, [2]int{aOff, bOff})
+		a, b, aOff, bOff = a[1:], b[1:], aOff+1, bOff+1
+	}
+	suffix := 0
+	for suffix < min(len(a), len(b)) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+	defer func() {
+		for k := range suffix {
+			*matches = append(*matches, [2]int{aOff + len(a) + k, bOff + len(b) + k})
+		}
+	}()
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return
+	case len(a)*len(b) > alignMaxWork:
+		for k := range min(len(a), len(b)) {
+			if a[k] == b[k] {
+				*matches = append(*matches, [2]int{aOff + k, bOff + k})
+			}
+		}
+		return
+	case len(a) == 1:
+		for j := range b {
+			if a[0] == b[j] {
+				*matches = append(*matches, [2]int{aOff, bOff + j})
+				return
+			}
+		}
+		return
+	}
+
+	mid := len(a) / 2
+	forward := lcsLengths(a[:mid], b, false)
+	backward := lcsLengths(a[mid:], b, true)
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if total := forward[k] + backward[len(b)-k]; total > best {
+			split, best = k, total
+		}
+	}
+	alignEntries(a[:mid], b[:split], aOff, bOff, matches)
+	alignEntries(a[mid:], b[split:], aOff+mid, bOff+split, matches)
+}
+
+// lcsLengths returns, for each k, the LCS length of a and the first k
+// entries of b, or with reverse set of a and the last k entries of b, both
+// read back to front. It keeps two rows of the LCS table.
+func lcsLengths(a, b []DiffEntry, reverse bool) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reverse {
+			x = a[len(a)-1-i]
+		}
+		for k := 1; k <= len(b); k++ {
+			y := b[k-1]
+			if reverse {
+				y = b[len(b)-k]
+			}
+			if x == y {
+				cur[k] = prev[k-1] + 1
+			} else {
+				cur[k] = max(prev[k], cur[k-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// diffPaths walks two normalized values and records the paths where they
+// diverge, comparing leaves and mismatched shapes with equal
+func diffPaths(path string, expected, actual interface{}, equal func(expected, actual interface{}) bool, out *[]PathChange) {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		got, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(exp)+len(got))
+		for key := range exp {
+			keys = append(keys, key)
+		}
+		for key := range got {
+			if _, seen := exp[key]; !seen {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "/" + escapePointer(key)
+			expVal, expOK := exp[key]
+			gotVal, gotOK := got[key]
+			switch {
+			case !gotOK:
+				*out = append(*out, PathChange{Op: ChangeMissing, Path: child, Expected: expVal})
+			case !expOK:
+				*out = append(*out, PathChange{Op: ChangeExtra, Path: child, Actual: gotVal})
+			default:
+				diffPaths(child, expVal, gotVal, equal, out)
+			}
+		}
+		return
+	case []interface{}:
+		got, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(exp), len(got)); i++ {
+			child := path + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(got):
+				*out = append(*out, PathChange{Op: ChangeMissing, Path: child, Expected: exp[i]})
+			case i >= len(exp):
+				*out = append(*out, PathChange{Op: ChangeExtra, Path: child, Actual: got[i]})
+			default:
+				diffPaths(child, exp[i], got[i], equal, out)
+			}
+		}
+		return
+	}
+
+	if !equal(expected, actual) {
+		if path == "" {
+			path = "/"
+		}
+		*out = append(*out, PathChange{Op: ChangeChanged, Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+// escapePointer escapes a key for use in a JSON pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// FormatDiff renders a diff as a human-readable failure message
+func FormatDiff(diff Diff) string {
+	return FormatDiffLimit(diff, DiffMaxValueLength)
+}
+
+// FormatDiffLimit renders a diff like FormatDiff, truncating values longer
+// than maxValueLength characters. Truncated values show their length and a
+// hash of the full value, so two values can still be told apart.
+func FormatDiffLimit(diff Diff, maxValueLength int) string {
+	switch diff.Kind {
+	case DiffEntries:
+		return formatEntryDiff(diff, maxValueLength)
+	case DiffHierarchy:
+		return formatHierarchyDiff(diff, maxValueLength, DiffMaxPaths)
+	default:
+		return fmt.Sprintf("expected %s, got %s",
+			truncateValue(formatValue(diff.Expected), maxValueLength), truncateValue(formatValue(diff.Actual), maxValueLength))
+	}
+}
+
+func formatEntryDiff(diff Diff, limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "entry lists differ: expected %d entries, got %d", diff.ExpectedCount, diff.ActualCount)
+
+	rows := make([][4]string, 0, len(diff.Entries))
+	for _, change := range diff.Entries {
+		row := [4]string{formatIndex(change.ExpectedIndex), formatIndex(change.ActualIndex), string(change.Op)}
+		switch change.Op {
+		case ChangeChanged:
+			row[3] = formatEntry(change.Expected, limit) + " -> " + formatEntry(change.Actual, limit)
+		case ChangeMissing:
+			row[3] = formatEntry(change.Expected, limit)
+		case ChangeExtra:
+			row[3] = formatEntry(change.Actual, limit)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := [3]int{len("exp"), len("act"), len(ChangeChanged)}
+	for _, row := range rows {
+		for col := range widths {
+			widths[col] = max(widths[col], len(row[col]))
+		}
+	}
+	fmt.Fprintf(&b, "\n  %-*s  %-*s", widths[0], "exp", widths[1], "act")
+	for _, row := range rows {
+		fmt.Fprintf(&b, "\n  %-*s  %-*s  %-*s  %s", widths[0], row[0], widths[1], row[1], widths[2], row[2], row[3])
+	}
+	return b.String()
+}
+
+func formatHierarchyDiff(diff Diff, limit, maxPaths int) string {
+	var b strings.Builder
+	count := len(diff.Paths)
+	if count == 1 {
+		b.WriteString("hierarchy differs at 1 path")
+	} else {
+		fmt.Fprintf(&b, "hierarchy differs at %d paths", count)
+	}
+
+	for i, change := range diff.Paths {
+		if i == maxPaths {
+			fmt.Fprintf(&b, "\n  ... and %d more", count-maxPaths)
+			break
+		}
+		switch change.Op {
+		case ChangeChanged:
+			fmt.Fprintf(&b, "\n  %s: expected %s, got %s", change.Path,
+				truncateValue(formatValue(change.Expected), limit), truncateValue(formatValue(change.Actual), limit))
+		case ChangeMissing:
+			fmt.Fprintf(&b, "\n  %s: missing, expected %s", change.Path, truncateValue(formatValue(change.Expected), limit))
+		case ChangeExtra:
+			fmt.Fprintf(&b, "\n  %s: unexpected %s", change.Path, truncateValue(formatValue(change.Actual), limit))
+		}
+	}
+	return b.String()
+}
+
+func formatIndex(index int) string {
+	if index < 0 {
+		return "-"
+	}
+	return "[" + strconv.Itoa(index) + "]"
+}
+
+func formatEntry(entry DiffEntry, limit int) string {
+	return truncateValue(strconv.Quote(entry.Key), limit) + " = " + truncateValue(strconv.Quote(entry.Value), limit)
+}
+
+// truncateValue shortens a rendered value longer than limit characters,
+// noting its length and the start of its SHA-256. It slices s in place, so
+// a huge value is not copied.
+func truncateValue(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	chars := utf8.RuneCountInString(s)
+	if chars <= limit {
+		return s
+	}
+	cut := 0
+	for range limit {
+		_, size := utf8.DecodeRuneInString(s[cut:])
+		cut += size
+	}
+	hash := sha256.New()
+	io.WriteString(hash, s)
+	return fmt.Sprintf("%s… (%d chars, sha256 %s)", s[:cut], chars, hex.EncodeToString(hash.Sum(nil))[:12])
+}
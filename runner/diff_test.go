@@ -0,0 +1,254 @@
+package runner
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func TestFormatDiff_EntryListGolden(t *testing.T) {
+	expected := []types.Entry{
+		{Key: "name", Value: "app"},
+		{Key: "version", Value: "1.0"},
+		{Key: "port", Value: "8080"},
+		{Key: "host", Value: "localhost"},
+		{Key: "debug", Value: "false"},
+		{Key: "description", Value: strings.Repeat("x", 80)},
+	}
+	actual := []types.Entry{
+		{Key: "name", Value: "app"},
+		{Key: "version", Value: "2.0"},
+		{Key: "port", Value: "8080"},
+		{Key: "timeout", Value: "30"},
+		{Key: "host", Value: "localhost"},
+		{Key: "description", Value: strings.Repeat("x", 80)},
+	}
+
+	diff, err := ComputeDiff(expected, actual)
+	if err != nil {
+		t.Fatalf("ComputeDiff failed: %v", err)
+	}
+	if diff.Kind != DiffEntries {
+		t.Fatalf("Expected entries diff, got %s", diff.Kind)
+	}
+
+	golden := `entry lists differ: expected 6 entries, got 6
+  exp  act
+  [1]  [1]  changed  "version" = "1.0" -> "version" = "2.0"
+  -    [3]  extra    "timeout" = "30"
+  [4]  -    missing  "debug" = "false"`
+	if got := FormatDiff(diff); got != golden {
+		t.Errorf("FormatDiff mismatch\n--- got ---\n%s\n--- want ---\n%s", got, golden)
+	}
+}
+
+func TestDiffEntryLists_MatchesFullTable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randomEntries := func() []DiffEntry {
+		entries := make([]DiffEntry, rng.IntN(12))
+		for i := range entries {
+			entries[i] = DiffEntry{Key: string(rune('a' + rng.IntN(4))), Value: "v"}
+		}
+		return entries
+	}
+
+	for range 500 {
+		expected, actual := randomEntries(), randomEntries()
+		changes := diffEntryLists(expected, actual)
+
+		// Every row not listed as a change is aligned, and aligned rows match
+		unchanged := len(expected)
+		for _, change := range changes {
+			if change.ExpectedIndex >= 0 {
+				unchanged--
+			}
+		}
+		if want := fullTableLCS(expected, actual); unchanged != want {
+			t.Fatalf("%v vs %v: aligned %d entries, want LCS %d (changes %+v)", expected, actual, unchanged, want, changes)
+		}
+	}
+}
+
+// fullTableLCS is the LCS length from the full quadratic table
+func fullTableLCS(a, b []DiffEntry) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+	return table[0][0]
+}
+
+func TestDiffEntryLists_ShiftedLists(t *testing.T) {
+	// Within alignMaxWork an insertion shifts the rest without changing it
+	expected := make([]DiffEntry, 2000)
+	for i := range expected {
+		expected[i] = DiffEntry{Key: strconv.Itoa(i), Value: "v"}
+	}
+	actual := slices.Concat(expected[:10], []DiffEntry{{Key: "new", Value: "v"}}, expected[10:1000], expected[1001:])
+
+	changes := diffEntryLists(expected, actual)
+	want := []EntryChange{
+		{Op: ChangeExtra, ExpectedIndex: -1, ActualIndex: 10, Actual: actual[10]},
+		{Op: ChangeMissing, ExpectedIndex: 1000, ActualIndex: -1, Expected: expected[1000]},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %+v, got %+v", want, changes)
+	}
+}
+
+func TestDiffEntryLists_LargeLists(t *testing.T) {
+	// A full table for these lists would take several gigabytes, and even
+	// two rows of it too long to fill, so the middle aligns by position
+	const size = 30000
+	expected := make([]DiffEntry, size)
+	actual := make([]DiffEntry, size)
+	for i := range expected {
+		expected[i] = DiffEntry{Key: strconv.Itoa(i), Value: "v"}
+		actual[i] = DiffEntry{Key: strconv.Itoa(i), Value: "v"}
+	}
+	actual[100].Value = "changed"
+	actual = append(actual[:size-100], actual[size-99:]...)
+
+	changes := diffEntryLists(expected, actual)
+	want := []EntryChange{
+		{Op: ChangeChanged, ExpectedIndex: 100, ActualIndex: 100, Expected: expected[100], Actual: actual[100]},
+		{Op: ChangeMissing, ExpectedIndex: size - 100, ActualIndex: -1, Expected: expected[size-100]},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %+v, got %+v", want, changes)
+	}
+}
+
+func TestFormatDiff_HierarchyGolden(t *testing.T) {
+	expected := map[string]interface{}{
+		"server": map[string]interface{}{
+			"tls": map[string]interface{}{
+				"cert":    "/etc/cert.pem",
+				"ciphers": []interface{}{"a", "b"},
+			},
+			"port": "443",
+		},
+		"a/b":     "slash",
+		"comment": strings.Repeat("long ", 20),
+	}
+	actual := map[string]interface{}{
+		"server": map[string]interface{}{
+			"tls": map[string]interface{}{
+				"cert":    "/etc/other.pem",
+				"ciphers": []interface{}{"a"},
+			},
+			"port":  "443",
+			"extra": map[string]interface{}{"x": "y"},
+		},
+		"a/b":     "slash",
+		"comment": strings.Repeat("LONG ", 20),
+	}
+
+	diff, err := ComputeDiff(expected, actual)
+	if err != nil {
+		t.Fatalf("ComputeDiff failed: %v", err)
+	}
+
+	golden := `hierarchy differs at 4 paths
+  /comment: expected "long long long long long long long long long long long long… (102 chars, sha256 d372e6c9cd0c), got "LONG LONG LONG LONG LONG LONG LONG LONG LONG LONG LONG LONG… (102 chars, sha256 405d8f98f821)
+  /server/extra: unexpected {"x":"y"}
+  /server/tls/cert: expected "/etc/cert.pem", got "/etc/other.pem"
+  /server/tls/ciphers/1: missing, expected "b"`
+	if got := FormatDiff(diff); got != golden {
+		t.Errorf("FormatDiff mismatch\n--- got ---\n%s\n--- want ---\n%s", got, golden)
+	}
+}
+
+func TestFormatDiff_LimitsHierarchyPaths(t *testing.T) {
+	expected := make(map[string]interface{})
+	actual := make(map[string]interface{})
+	for i := 0; i < DiffMaxPaths+5; i++ {
+		key := string(rune('a' + i))
+		expected[key] = "1"
+		actual[key] = "2"
+	}
+
+	diff, err := ComputeDiff(expected, actual)
+	if err != nil {
+		t.Fatalf("ComputeDiff failed: %v", err)
+	}
+	if len(diff.Paths) != DiffMaxPaths+5 {
+		t.Errorf("Expected all paths in the diff, got %d", len(diff.Paths))
+	}
+
+	formatted := FormatDiff(diff)
+	if !strings.HasSuffix(formatted, "... and 5 more") {
+		t.Errorf("Expected truncation note, got:\n%s", formatted)
+	}
+	if strings.Contains(formatted, "/"+string(rune('a'+DiffMaxPaths))+":") {
+		t.Errorf("Expected paths beyond the limit to be omitted, got:\n%s", formatted)
+	}
+}
+
+func TestFormatDiff_EscapesPointerKeys(t *testing.T) {
+	diff, err := ComputeDiff(map[string]interface{}{"a/b~c": "1"}, map[string]interface{}{"a/b~c": "2"})
+	if err != nil {
+		t.Fatalf("ComputeDiff failed: %v", err)
+	}
+	if len(diff.Paths) != 1 || diff.Paths[0].Path != "/a~1b~0c" {
+		t.Errorf("Expected escaped pointer /a~1b~0c, got %+v", diff.Paths)
+	}
+}
+
+func TestFormatDiff_ScalarValues(t *testing.T) {
+	diff, err := ComputeDiff("b", "c")
+	if err != nil {
+		t.Fatalf("ComputeDiff failed: %v", err)
+	}
+	if diff.Kind != DiffValue || diff.Equal() {
+		t.Errorf("Expected unequal value diff, got %+v", diff)
+	}
+	if got := FormatDiff(diff); got != `expected "b", got "c"` {
+		t.Errorf("Unexpected scalar message: %s", got)
+	}
+}
+
+func TestRunTest_UsesDiffMessage(t *testing.T) {
+	result := RunTest(&parseOnlyImplementation{}, types.TestCase{
+		Name:       "entries",
+		Inputs:     []string{"a = b"},
+		Validation: "parse",
+		Expected:   entries("a", "b", "c", "d"),
+	})
+	if result.Passed() {
+		t.Fatal("Expected failure")
+	}
+	if !strings.HasPrefix(result.Message, "entry lists differ") {
+		t.Errorf("Expected entry diff message, got %q", result.Message)
+	}
+}
+
+func TestTruncateValue(t *testing.T) {
+	if got := truncateValue("short", 10); got != "short" {
+		t.Errorf("Expected a short value unchanged, got %q", got)
+	}
+	if got := truncateValue("ääää", 4); got != "ääää" {
+		t.Errorf("Expected the limit counted in characters, got %q", got)
+	}
+	got := truncateValue("äöüxyz", 3)
+	if !strings.HasPrefix(got, "äöü… (6 chars, sha256 ") || len(got) != len("äöü… (6 chars, sha256 )")+12 {
+		t.Errorf("Unexpected truncation %q", got)
+	}
+	if truncateValue("äöüxyz", 3) == truncateValue("äöüxyZ", 3) {
+		t.Error("Expected different values to hash differently")
+	}
+}
@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"sort"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// SelectProbes picks a minimal probe set from a loaded corpus: for each
+// function, feature, behavior and variant, the smallest-input test that
+// exercises it with as few other requirements as possible. Ties are broken
+// by test name so the selection is deterministic.
+func SelectProbes(tests []types.TestCase) []types.TestCase {
+	candidates := make([]types.TestCase, 0, len(tests))
+	for _, test := range tests {
+		if test.Validation != "" && len(test.Inputs) > 0 {
+			candidates = append(candidates, test)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := requirementCount(candidates[i]), requirementCount(candidates[j])
+		if ri != rj {
+			return ri < rj
+		}
+		si, sj := inputSize(candidates[i]), inputSize(candidates[j])
+		if si != sj {
+			return si < sj
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	selected := make(map[string]bool)
+	var probes []types.TestCase
+	pick := func(matches func(types.TestCase) bool) {
+		for _, test := range candidates {
+			if matches(test) {
+				if !selected[test.Name] {
+					selected[test.Name] = true
+					probes = append(probes, test)
+				}
+				return
+			}
+		}
+	}
+
+	for _, fn := range config.AllFunctions() {
+		pick(func(test types.TestCase) bool { return test.Validation == string(fn) })
+	}
+	for _, feature := range config.AllFeatures() {
+		pick(func(test types.TestCase) bool { return containsString(test.Features, string(feature)) })
+	}
+	for _, behaviors := range config.GetBehaviorConflicts() {
+		for _, behavior := range behaviors {
+			pick(func(test types.TestCase) bool { return containsString(test.Behaviors, string(behavior)) })
+		}
+	}
+	for _, variant := range config.AllVariants() {
+		pick(func(test types.TestCase) bool { return containsString(test.Variants, string(variant)) })
+	}
+
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Name < probes[j].Name })
+	return probes
+}
+
+// DiscoverCapabilities runs the probe tests against an implementation and
+// declares support for whatever passes: a function or feature is supported
+// when at least one probe exercising it passes, and for each behavior
+// conflict group the behavior whose probes pass is chosen when the other
+// side's probes fail. The returned config always passes IsValid.
+func DiscoverCapabilities(impl CCLImplementation, probeTests []types.TestCase) (config.ImplementationConfig, error) {
+	passedFunctions := make(map[string]bool)
+	passedFeatures := make(map[string]bool)
+	behaviorPassed := make(map[string]bool)
+	behaviorFailed := make(map[string]bool)
+	variantPassed := make(map[string]bool)
+	variantFailed := make(map[string]bool)
+
+	for _, test := range probeTests {
+		passed := RunTest(impl, test).Passed()
+
+		if passed {
+			passedFunctions[test.Validation] = true
+			for _, feature := range test.Features {
+				passedFeatures[feature] = true
+			}
+		}
+		for _, behavior := range test.Behaviors {
+			if passed {
+				behaviorPassed[behavior] = true
+			} else {
+				behaviorFailed[behavior] = true
+			}
+		}
+		for _, variant := range test.Variants {
+			if passed {
+				variantPassed[variant] = true
+			} else {
+				variantFailed[variant] = true
+			}
+		}
+	}
+
+	cfg := config.ImplementationConfig{
+		Name:               "discovered",
+		SupportedFunctions: []config.CCLFunction{},
+		SupportedFeatures:  []config.CCLFeature{},
+		BehaviorChoices:    []config.CCLBehavior{},
+	}
+
+	for _, fn := range config.AllFunctions() {
+		if passedFunctions[string(fn)] {
+			cfg.SupportedFunctions = append(cfg.SupportedFunctions, fn)
+		}
+	}
+	for _, feature := range config.AllFeatures() {
+		if passedFeatures[string(feature)] {
+			cfg.SupportedFeatures = append(cfg.SupportedFeatures, feature)
+		}
+	}
+
+	// Choose a behavior only when its side of the group is unambiguous
+	groups := config.GetBehaviorConflicts()
+	groupNames := make([]string, 0, len(groups))
+	for group := range groups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+	for _, group := range groupNames {
+		var chosen []config.CCLBehavior
+		for _, behavior := range groups[group] {
+			if behaviorPassed[string(behavior)] && !behaviorFailed[string(behavior)] {
+				chosen = append(chosen, behavior)
+			}
+		}
+		if len(chosen) == 1 {
+			cfg.BehaviorChoices = append(cfg.BehaviorChoices, chosen[0])
+		}
+	}
+
+	var variants []config.CCLVariant
+	for _, variant := range config.AllVariants() {
+		if variantPassed[string(variant)] && !variantFailed[string(variant)] {
+			variants = append(variants, variant)
+		}
+	}
+	if len(variants) == 1 {
+		cfg.VariantChoice = variants[0]
+	}
+
+	if err := cfg.IsValid(); err != nil {
+		return config.ImplementationConfig{}, err
+	}
+	return cfg, nil
+}
+
+// requirementCount counts the features, behaviors and variants a test requires
+func requirementCount(test types.TestCase) int {
+	return len(test.Features) + len(test.Behaviors) + len(test.Variants)
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package runner
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+func entries(pairs ...string) []interface{} {
+	result := make([]interface{}, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		result = append(result, map[string]interface{}{"key": pairs[i], "value": pairs[i+1]})
+	}
+	return result
+}
+
+// createProbeCorpus builds a corpus with probes for both sides of several
+// behavior groups, plus padding tests that SelectProbes should not choose
+func createProbeCorpus() []types.TestCase {
+	return []types.TestCase{
+		// Function probes
+		{Name: "parse_basic", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")},
+		{Name: "parse_basic_longer", Inputs: []string{"a = b\nc = d"}, Validation: "parse", Expected: entries("a", "b", "c", "d")},
+		{Name: "parse_indented_basic", Inputs: []string{"  a = b"}, Validation: "parse_indented", Expected: entries("a", "b")},
+		{Name: "hierarchy_basic", Inputs: []string{"a =\n  b = c"}, Validation: "build_hierarchy", Expected: map[string]interface{}{"a": map[string]interface{}{"b": "c"}}},
+		{Name: "get_string_basic", Inputs: []string{"a = b"}, Validation: "get_string", Args: []string{"a"}, Expected: "b"},
+		{Name: "get_int_basic", Inputs: []string{"a = 1"}, Validation: "get_int", Args: []string{"a"}, Expected: 1},
+		{Name: "get_float_basic", Inputs: []string{"a = 1.5"}, Validation: "get_float", Args: []string{"a"}, Expected: 1.5},
+		{Name: "get_list_basic", Inputs: []string{"a = x\na = y"}, Validation: "get_list", Args: []string{"a"}, Expected: []interface{}{"x", "y"}},
+		{Name: "combine_basic", Inputs: []string{"a = b", "c = d"}, Validation: "combine", Expected: entries("a", "b", "c", "d")},
+		{Name: "pretty_print_basic", Inputs: []string{"a = b"}, Validation: "pretty_print", Expected: "a = b\n"},
+		{Name: "canonical_format_basic", Inputs: []string{"a =   b"}, Validation: "canonical_format", Expected: "a = b\n"},
+		{Name: "expand_dotted_basic", Inputs: []string{"a.b = c"}, Validation: "expand_dotted", Expected: entries("a", "b = c")},
+
+		// Feature probes
+		{Name: "comments_filter", Inputs: []string{"/= note\na = b"}, Validation: "filter", Expected: entries("a", "b"), Features: []string{"comments"}},
+		{Name: "comments_extract", Inputs: []string{"/= note\na = b"}, Validation: "extract_comments", Expected: entries("/", "note"), Features: []string{"comments"}},
+		{Name: "multiline_value", Inputs: []string{"a = b\n  c"}, Validation: "parse", Expected: entries("a", "b\n  c"), Features: []string{"multiline"}},
+		{Name: "unicode_key", Inputs: []string{"名前 = 値"}, Validation: "parse", Expected: entries("名前", "値"), Features: []string{"unicode"}},
+		{Name: "empty_key_list", Inputs: []string{"= a"}, Validation: "parse", Expected: entries("", "a"), Features: []string{"empty_keys"}},
+		{Name: "whitespace_trim", Inputs: []string{"a   =   b"}, Validation: "parse", Expected: entries("a", "b"), Features: []string{"whitespace"}},
+
+		// Behavior probes for both sides of each group
+		{Name: "bool_strict", Inputs: []string{"a = yes"}, Validation: "get_bool", Args: []string{"a"}, ExpectError: true, Behaviors: []string{"boolean_strict"}},
+		{Name: "bool_lenient", Inputs: []string{"a = yes"}, Validation: "get_bool", Args: []string{"a"}, Expected: true, Behaviors: []string{"boolean_lenient"}},
+		{Name: "crlf_normalize", Inputs: []string{"a = b\r\n"}, Validation: "parse", Expected: entries("a", "b"), Behaviors: []string{"crlf_normalize_to_lf"}},
+		{Name: "crlf_preserve", Inputs: []string{"a = b\r\n"}, Validation: "parse", Expected: entries("a", "b\r"), Behaviors: []string{"crlf_preserve_literal"}},
+		{Name: "tabs_whitespace", Inputs: []string{"a =\tb"}, Validation: "parse", Expected: entries("a", "b"), Behaviors: []string{"tabs_as_whitespace"}},
+		{Name: "tabs_content", Inputs: []string{"a =\tb"}, Validation: "parse", Expected: entries("a", "\tb"), Behaviors: []string{"tabs_as_content"}},
+		{Name: "indent_spaces_print", Inputs: []string{"a =\n  b = c"}, Validation: "pretty_print", Expected: "a =\n  b = c\n", Behaviors: []string{"indent_spaces"}},
+		{Name: "indent_tabs_print", Inputs: []string{"a =\n  b = c"}, Validation: "pretty_print", Expected: "a =\n\tb = c\n", Behaviors: []string{"indent_tabs"}},
+		{Name: "list_coercion_off", Inputs: []string{"a = x"}, Validation: "get_list", Args: []string{"a"}, ExpectError: true, Behaviors: []string{"list_coercion_disabled"}},
+		{Name: "list_coercion_on", Inputs: []string{"a = x"}, Validation: "get_list", Args: []string{"a"}, Expected: []interface{}{"x"}, Behaviors: []string{"list_coercion_enabled"}},
+
+		// Variant probes
+		{Name: "duplicates_as_list", Inputs: []string{"a = 1\na = 2"}, Validation: "build_hierarchy", Expected: map[string]interface{}{"a": []interface{}{"1", "2"}}, Variants: []string{"reference_compliant"}},
+		{Name: "duplicates_last_wins", Inputs: []string{"a = 1\na = 2"}, Validation: "build_hierarchy", Expected: map[string]interface{}{"a": "2"}, Variants: []string{"proposed_behavior"}},
+	}
+}
+
+func TestRunTest_PassAndFail(t *testing.T) {
+	impl := toyccl.New()
+
+	pass := RunTest(impl, types.TestCase{Name: "ok", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")})
+	if !pass.Passed() {
+		t.Errorf("Expected pass, got %s: %s", pass.Status, pass.Message)
+	}
+
+	fail := RunTest(impl, types.TestCase{Name: "bad", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "c")})
+	if fail.Passed() || fail.Message == "" {
+		t.Errorf("Expected failure with message, got %s: %q", fail.Status, fail.Message)
+	}
+
+	errTest := RunTest(impl, types.TestCase{Name: "err", Inputs: []string{"no equals"}, Validation: "parse", ExpectError: true})
+	if !errTest.Passed() {
+		t.Errorf("Expected error test to pass, got %s: %s", errTest.Status, errTest.Message)
+	}
+}
+
+func TestSelectProbes_PrefersMinimalTests(t *testing.T) {
+	probes := SelectProbes(createProbeCorpus())
+
+	names := make(map[string]bool)
+	for _, probe := range probes {
+		names[probe.Name] = true
+	}
+
+	if !names["parse_basic"] {
+		t.Error("Expected parse_basic as the parse probe")
+	}
+	if names["parse_basic_longer"] {
+		t.Error("Did not expect the larger parse test to be selected")
+	}
+	for _, expected := range []string{"comments_filter", "bool_strict", "bool_lenient", "duplicates_last_wins"} {
+		if !names[expected] {
+			t.Errorf("Expected probe %s to be selected", expected)
+		}
+	}
+
+	// Selection is deterministic
+	again := SelectProbes(createProbeCorpus())
+	if len(again) != len(probes) {
+		t.Fatalf("Probe count changed between runs: %d vs %d", len(probes), len(again))
+	}
+	for i := range probes {
+		if probes[i].Name != again[i].Name {
+			t.Errorf("Probe order changed at %d: %s vs %s", i, probes[i].Name, again[i].Name)
+		}
+	}
+}
+
+func TestDiscoverCapabilities_MatchesToyConfig(t *testing.T) {
+	discovered, err := DiscoverCapabilities(toyccl.New(), SelectProbes(createProbeCorpus()))
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities failed: %v", err)
+	}
+	if err := discovered.IsValid(); err != nil {
+		t.Errorf("Discovered config is invalid: %v", err)
+	}
+
+	known := toyccl.Config()
+
+	if got, want := sortedStrings(discovered.SupportedFunctions), sortedStrings(known.SupportedFunctions); !equalStrings(got, want) {
+		t.Errorf("Functions mismatch:\n  discovered %v\n  known      %v", got, want)
+	}
+	if got, want := sortedStrings(discovered.SupportedFeatures), sortedStrings(known.SupportedFeatures); !equalStrings(got, want) {
+		t.Errorf("Features mismatch:\n  discovered %v\n  known      %v", got, want)
+	}
+	if got, want := sortedStrings(discovered.BehaviorChoices), sortedStrings(known.BehaviorChoices); !equalStrings(got, want) {
+		t.Errorf("Behaviors mismatch:\n  discovered %v\n  known      %v", got, want)
+	}
+	if discovered.VariantChoice != known.VariantChoice {
+		t.Errorf("Variant mismatch: discovered %s, known %s", discovered.VariantChoice, known.VariantChoice)
+	}
+}
+
+func TestDiscoverCapabilities_AmbiguousGroupLeftUndecided(t *testing.T) {
+	// Both sides pass when neither probe is sensitive to the behavior
+	probes := []types.TestCase{
+		{Name: "a", Inputs: []string{"a = true"}, Validation: "get_bool", Args: []string{"a"}, Expected: true, Behaviors: []string{"boolean_strict"}},
+		{Name: "b", Inputs: []string{"a = true"}, Validation: "get_bool", Args: []string{"a"}, Expected: true, Behaviors: []string{"boolean_lenient"}},
+	}
+
+	discovered, err := DiscoverCapabilities(toyccl.New(), probes)
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities failed: %v", err)
+	}
+	if len(discovered.BehaviorChoices) != 0 {
+		t.Errorf("Expected no behavior choice for ambiguous group, got %v", discovered.BehaviorChoices)
+	}
+	if !discovered.HasFunction(config.FunctionGetBool) {
+		t.Error("Expected get_bool to be discovered")
+	}
+}
+
+// parseOnly provides only Parse
+type parseOnly struct {
+	UnimplementedImplementation
+}
+
+func (parseOnly) Parse(input string) ([]types.Entry, error) {
+	return toyccl.New().Parse(input)
+}
+
+func TestDiscoverCapabilities_NotSupportedFailsErrorProbes(t *testing.T) {
+	discovered, err := DiscoverCapabilities(parseOnly{}, SelectProbes(createProbeCorpus()))
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities failed: %v", err)
+	}
+	if got := sortedStrings(discovered.SupportedFunctions); !equalStrings(got, []string{"parse"}) {
+		t.Errorf("Expected only parse, got %v", got)
+	}
+	if discovered.HasFunction(config.FunctionGetBool) || discovered.HasBehavior(config.BehaviorBooleanStrict) {
+		t.Errorf("Expected neither get_bool nor boolean_strict, got %+v", discovered)
+	}
+	if result := RunTest(parseOnly{}, types.TestCase{Name: "e", Inputs: []string{"a = yes"}, Validation: "get_bool", Args: []string{"a"}, ExpectError: true}); result.Passed() {
+		t.Error("Expected ErrNotSupported to fail an error test")
+	}
+}
+
+func sortedStrings[T ~string](values []T) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = string(v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// HierarchyLeaves selects how build_hierarchy results are compared with
+// their expected object, whose leaves are the strings CCL values parse to.
+// Implementations often return typed leaves instead; the modes say which
+// typed leaves still match. Objects and arrays are always walked key by
+// key and element by element, so only the leaves are affected.
+type HierarchyLeaves string
+
+const (
+	// LeavesCoerce matches a bool leaf to "true" or "false", as
+	// strconv.FormatBool writes it. Other leaves must equal the expected
+	// string exactly. It is the default.
+	LeavesCoerce HierarchyLeaves = ""
+
+	// LeavesNumeric is LeavesCoerce, and also matches a number leaf to an
+	// expected string strconv.ParseFloat reads as the same value, so 8080
+	// matches "8080" and 1.5 matches "1.50"
+	LeavesNumeric HierarchyLeaves = "numeric"
+
+	// LeavesExact requires every leaf to be the expected string itself
+	LeavesExact HierarchyLeaves = "exact"
+)
+
+// Known reports whether the mode is one of the defined ones
+func (l HierarchyLeaves) Known() bool {
+	return l == LeavesCoerce || l == LeavesNumeric || l == LeavesExact
+}
+
+// CompareHierarchy walks expected and actual after normalizing them to
+// their JSON form, as results are compared, and returns every path where
+// they diverge with leaves compared as leaves says. Structs and typed maps
+// in actual compare as the objects they marshal to.
+func CompareHierarchy(expected, actual interface{}, leaves HierarchyLeaves) ([]PathChange, error) {
+	if !leaves.Known() {
+		return nil, fmt.Errorf("unknown hierarchy leaf mode %q", leaves)
+	}
+	exp, err := normalizeValue(expected)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expected value: %w", err)
+	}
+	got, err := normalizeValue(actual)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actual value: %w", err)
+	}
+	var paths []PathChange
+	diffPaths("", exp, got, leavesEqual(leaves), &paths)
+	return paths, nil
+}
+
+// leavesEqual returns the comparison of normalized leaves for a mode
+func leavesEqual(leaves HierarchyLeaves) func(expected, actual interface{}) bool {
+	return func(expected, actual interface{}) bool {
+		if reflect.DeepEqual(expected, actual) {
+			return true
+		}
+		want, ok := expected.(string)
+		if !ok || leaves == LeavesExact {
+			return false
+		}
+		switch got := actual.(type) {
+		case bool:
+			return want == strconv.FormatBool(got)
+		case float64:
+			if leaves != LeavesNumeric {
+				return false
+			}
+			parsed, err := strconv.ParseFloat(want, 64)
+			return err == nil && parsed == got
+		}
+		return false
+	}
+}
@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// server is a typed value an implementation might put in a hierarchy
+type server struct {
+	Host    string   `json:"host"`
+	Port    int      `json:"port"`
+	Debug   bool     `json:"debug"`
+	Aliases []string `json:"aliases"`
+}
+
+func object(pairs ...interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	for i := 0; i < len(pairs); i += 2 {
+		m[pairs[i].(string)] = pairs[i+1]
+	}
+	return m
+}
+
+func TestCompareHierarchy(t *testing.T) {
+	expectedServer := object("server", object("host", "localhost", "port", "8080", "debug", "true", "aliases", []interface{}{"a", "b"}))
+	typedServer := map[string]any{"server": server{Host: "localhost", Port: 8080, Debug: true, Aliases: []string{"a", "b"}}}
+
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		leaves   HierarchyLeaves
+		want     []string // "op path"
+	}{
+		{"equal strings", object("a", object("b", "c")), object("a", object("b", "c")), LeavesExact, nil},
+		{"bool leaf coerced", object("a", "true"), map[string]any{"a": true}, LeavesCoerce, nil},
+		{"bool leaf mismatch", object("a", "false"), map[string]any{"a": true}, LeavesCoerce, []string{"changed /a"}},
+		{"bool leaf exact", object("a", "true"), map[string]any{"a": true}, LeavesExact, []string{"changed /a"}},
+		{"bool spelling is strict", object("a", "True"), map[string]any{"a": true}, LeavesCoerce, []string{"changed /a"}},
+		{"number needs numeric mode", object("port", "8080"), map[string]any{"port": 8080}, LeavesCoerce, []string{"changed /port"}},
+		{"number leaf coerced", object("port", "8080"), map[string]any{"port": 8080}, LeavesNumeric, nil},
+		{"float leaf coerced", object("ratio", "1.50"), map[string]any{"ratio": 1.5}, LeavesNumeric, nil},
+		{"number value mismatch", object("port", "8080"), map[string]any{"port": 8081}, LeavesNumeric, []string{"changed /port"}},
+		{"non-numeric string", object("port", "http"), map[string]any{"port": 80}, LeavesNumeric, []string{"changed /port"}},
+		{"number leaf exact", object("port", "8080"), map[string]any{"port": 8080}, LeavesExact, []string{"changed /port"}},
+		{"null leaf", object("a", ""), map[string]any{"a": nil}, LeavesNumeric, []string{"changed /a"}},
+		{"typed struct", expectedServer, typedServer, LeavesNumeric, nil},
+		{"typed struct without numbers", expectedServer, typedServer, LeavesCoerce, []string{"changed /server/port"}},
+		{"array leaves coerced", object("ports", []interface{}{"1", "2"}), map[string]any{"ports": []int{1, 2}}, LeavesNumeric, nil},
+		{"array length", object("a", []interface{}{"1", "2", "3"}), map[string]any{"a": []string{"1"}}, LeavesCoerce, []string{"missing /a/1", "missing /a/2"}},
+		{"array of objects", object("a", []interface{}{object("b", "1")}), map[string]any{"a": []any{map[string]any{"b": 1}}}, LeavesNumeric, nil},
+		{"array for leaf", object("a", "1"), map[string]any{"a": []string{"1"}}, LeavesNumeric, []string{"changed /a"}},
+		{"object for leaf", object("a", "1"), map[string]any{"a": map[string]any{"b": "1"}}, LeavesNumeric, []string{"changed /a"}},
+		{"leaf for object", object("a", object("b", "1")), map[string]any{"a": "1"}, LeavesNumeric, []string{"changed /a"}},
+		{"missing and extra keys", object("a", "1", "b", "2"), map[string]any{"b": 2, "c": 3}, LeavesNumeric, []string{"missing /a", "extra /c"}},
+		{"nested divergence", object("a", object("b", object("c", "x", "d", "true"))), map[string]any{"a": map[string]any{"b": map[string]any{"c": "y", "d": true}}}, LeavesCoerce, []string{"changed /a/b/c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := CompareHierarchy(tt.expected, tt.actual, tt.leaves)
+			if err != nil {
+				t.Fatalf("CompareHierarchy failed: %v", err)
+			}
+			var got []string
+			for _, change := range paths {
+				got = append(got, string(change.Op)+" "+change.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := CompareHierarchy(object(), object(), "loose"); err == nil {
+		t.Error("Expected an error for an unknown leaf mode")
+	}
+}
+
+// typedHierarchy returns build_hierarchy results with typed leaves
+type typedHierarchy struct {
+	UnimplementedImplementation
+	result map[string]interface{}
+}
+
+func (h typedHierarchy) BuildHierarchy(string) (map[string]interface{}, error) {
+	return h.result, nil
+}
+
+func TestRun_HierarchyLeaves(t *testing.T) {
+	test := types.TestCase{
+		Name:       "server_build_hierarchy",
+		Inputs:     []string{"server =\n  port = 8080\n  debug = true"},
+		Validation: "build_hierarchy",
+		Expected:   object("server", object("port", "8080", "debug", "true")),
+	}
+	impl := typedHierarchy{result: map[string]interface{}{"server": map[string]interface{}{"port": 8080, "debug": true}}}
+
+	tests := []struct {
+		leaves  HierarchyLeaves
+		message string
+	}{
+		{LeavesNumeric, ""},
+		{LeavesCoerce, "hierarchy differs at 1 path\n  /server/port: expected \"8080\", got 8080"},
+		{LeavesExact, "hierarchy differs at 2 paths\n  /server/debug: expected \"true\", got true\n  /server/port: expected \"8080\", got 8080"},
+		{"loose", `unknown hierarchy leaf mode "loose"`},
+	}
+	for _, tt := range tests {
+		result := Run(impl, []types.TestCase{test}, RunOptions{HierarchyLeaves: tt.leaves}).Results[0]
+		if result.Message != tt.message {
+			t.Errorf("%q: expected message %q, got %q", tt.leaves, tt.message, result.Message)
+		}
+	}
+}
+
+func TestRun_HierarchyMaxPaths(t *testing.T) {
+	expected, actual := object(), map[string]interface{}{}
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("k%d", i)
+		expected[key] = "1"
+		actual[key] = "2"
+	}
+	test := types.TestCase{Name: "many_build_hierarchy", Inputs: []string{"k0 = 1"}, Validation: "build_hierarchy", Expected: expected}
+
+	result := Run(typedHierarchy{result: actual}, []types.TestCase{test}, RunOptions{HierarchyMaxPaths: 2}).Results[0]
+	want := "hierarchy differs at 5 paths\n  /k0: expected \"1\", got \"2\"\n  /k1: expected \"1\", got \"2\"\n  ... and 3 more"
+	if result.Message != want {
+		t.Errorf("Expected %q, got %q", want, result.Message)
+	}
+	if lines := strings.Count(RunTest(typedHierarchy{result: actual}, test).Message, "\n"); lines != 5 {
+		t.Errorf("Expected all 5 paths under the default limit, got %d lines", lines)
+	}
+}
@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Matrix holds conformance results for one implementation across several configs
+type Matrix struct {
+	TotalTests int           `json:"total_tests"`
+	Entries    []MatrixEntry `json:"entries"`
+}
+
+// MatrixEntry is one config's row of a conformance matrix
+type MatrixEntry struct {
+	Config     config.ImplementationConfig `json:"config"`
+	Statistics types.TestStatistics        `json:"statistics"`
+	Results    *RunResults                 `json:"results,omitempty"` // Nil when no implementation was run
+}
+
+// CompatibleRatio returns the fraction of all tests compatible with the config
+func (e MatrixEntry) CompatibleRatio() float64 {
+	if e.Statistics.TotalTests == 0 {
+		return 0
+	}
+	return float64(e.Statistics.CompatibleTests) / float64(e.Statistics.TotalTests)
+}
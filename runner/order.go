@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// OrderDependentTest is a test whose outcome depends on the order tests
+// run in, such as one passing only after another warmed a cache. Each seed
+// reproduces its outcome as RunOptions.ShuffleSeed; seed 0 is name order.
+type OrderDependentTest struct {
+	Name        string  `json:"name"`
+	ID          string  `json:"id,omitempty"`
+	PassedSeeds []int64 `json:"passed_seeds"`
+	FailedSeeds []int64 `json:"failed_seeds"`
+}
+
+// DetectOrderDependence runs tests in name order and then shuffled with
+// seeds 1 to trials, returning the tests that passed in some orders and
+// failed in others, by name. Skipped and not run tests are ignored. All
+// runs share impl, so state one run leaves behind is seen by the next.
+func DetectOrderDependence(impl CCLImplementation, tests []types.TestCase, trials int) []OrderDependentTest {
+	outcomes := make(map[string]*OrderDependentTest)
+	for seed := int64(0); seed <= int64(trials); seed++ {
+		for _, result := range Run(impl, tests, RunOptions{ShuffleSeed: seed}).Results {
+			key := result.ID
+			if key == "" {
+				key = result.Name
+			}
+			outcome := outcomes[key]
+			if outcome == nil {
+				outcome = &OrderDependentTest{Name: result.Name, ID: result.ID}
+				outcomes[key] = outcome
+			}
+			switch result.Status {
+			case StatusPass:
+				outcome.PassedSeeds = append(outcome.PassedSeeds, seed)
+			case StatusFail:
+				outcome.FailedSeeds = append(outcome.FailedSeeds, seed)
+			}
+		}
+	}
+
+	var dependent []OrderDependentTest
+	for _, outcome := range outcomes {
+		if len(outcome.PassedSeeds) > 0 && len(outcome.FailedSeeds) > 0 {
+			dependent = append(dependent, *outcome)
+		}
+	}
+	slices.SortFunc(dependent, func(a, b OrderDependentTest) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return dependent
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// cachingImpl answers get_string from the last document it parsed without
+// checking the input, so get_string passes only when no other document was
+// parsed since the matching one
+type cachingImpl struct {
+	*toyccl.Implementation
+	cached []types.Entry
+}
+
+func (impl *cachingImpl) Parse(input string) ([]types.Entry, error) {
+	entries, err := impl.Implementation.Parse(input)
+	impl.cached = entries
+	return entries, err
+}
+
+func (impl *cachingImpl) GetString(input string, args []string) (string, error) {
+	if impl.cached == nil {
+		return impl.Implementation.GetString(input, args)
+	}
+	for _, entry := range impl.cached {
+		if len(args) == 1 && entry.Key == args[0] {
+			return entry.Value, nil
+		}
+	}
+	return impl.Implementation.GetString(input, args)
+}
+
+// createOrderTests returns tests where c_get passes against cachingImpl
+// only if a_parse was the last parse before it; d_int never depends on order
+func createOrderTests() []types.TestCase {
+	return []types.TestCase{
+		{Name: "a_parse", Inputs: []string{"a = 1"}, Validation: "parse", Expected: entries("a", "1")},
+		{Name: "b_parse", Inputs: []string{"a = 2"}, Validation: "parse", Expected: entries("a", "2")},
+		{Name: "c_get", Inputs: []string{"a = 1"}, Validation: "get_string", Args: []string{"a"}, Expected: "1"},
+		{Name: "d_int", Inputs: []string{"x = 3"}, Validation: "get_int", Args: []string{"x"}, Expected: 3},
+	}
+}
+
+func TestRun_ShuffleSeed(t *testing.T) {
+	tests := createRunTests()
+	first := Run(toyccl.New(), tests, RunOptions{ShuffleSeed: 42})
+	if first.ShuffleSeed != 42 {
+		t.Errorf("Expected the seed recorded, got %d", first.ShuffleSeed)
+	}
+
+	reversed := slices.Clone(tests)
+	slices.Reverse(reversed)
+	if got, want := resultNames(Run(toyccl.New(), reversed, RunOptions{ShuffleSeed: 42})), resultNames(first); !equalStrings(got, want) {
+		t.Errorf("Expected seed 42 to give %v whatever the input order, got %v", want, got)
+	}
+
+	nameOrder := resultNames(Run(toyccl.New(), tests, RunOptions{}))
+	var shuffled bool
+	for seed := int64(1); seed <= 5; seed++ {
+		if got := resultNames(Run(toyccl.New(), tests, RunOptions{ShuffleSeed: seed})); !equalStrings(got, nameOrder) {
+			shuffled = true
+		}
+	}
+	if !shuffled {
+		t.Error("Expected some seed to change the order")
+	}
+}
+
+func TestRun_ShuffleSeedKeepsPriorFailuresFirst(t *testing.T) {
+	prior := &RunResults{Results: []TestResult{
+		{Name: "e_pass", Status: StatusFail},
+		{Name: "c_pass", Status: StatusFail},
+	}}
+	for seed := int64(1); seed <= 5; seed++ {
+		names := resultNames(Run(toyccl.New(), createRunTests(), RunOptions{PriorResults: prior, ShuffleSeed: seed}))
+		first := names[:2]
+		slices.Sort(first)
+		if !equalStrings(first, []string{"c_pass", "e_pass"}) {
+			t.Errorf("Seed %d: expected prior failures first, got %v", seed, names)
+		}
+	}
+}
+
+func TestDetectOrderDependence(t *testing.T) {
+	dependent := DetectOrderDependence(&cachingImpl{Implementation: toyccl.New()}, createOrderTests(), 10)
+
+	if len(dependent) != 1 || dependent[0].Name != "c_get" {
+		t.Fatalf("Expected only c_get to depend on order, got %+v", dependent)
+	}
+	// In name order b_parse runs just before c_get and leaves "a = 2" cached
+	if !slices.Contains(dependent[0].FailedSeeds, 0) {
+		t.Errorf("Expected c_get to fail in name order, got %+v", dependent[0])
+	}
+
+	// Each seed reproduces its outcome
+	for _, seed := range dependent[0].PassedSeeds {
+		results := Run(&cachingImpl{Implementation: toyccl.New()}, createOrderTests(), RunOptions{ShuffleSeed: seed})
+		for _, result := range results.Results {
+			if result.Name == "c_get" && !result.Passed() {
+				t.Errorf("Expected seed %d to reproduce c_get passing, got %s", seed, result.Message)
+			}
+		}
+	}
+}
+
+func TestDetectOrderDependence_StatelessImplementation(t *testing.T) {
+	if dependent := DetectOrderDependence(toyccl.New(), createRunTests(), 5); len(dependent) != 0 {
+		t.Errorf("Expected no order dependence, got %+v", dependent)
+	}
+}
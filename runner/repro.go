@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/version"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Repro is a self-contained report of one failing test, for filing against
+// the implementation: the source test, the flat test that ran, and the
+// implementation's outcome
+type Repro struct {
+	LibraryVersion    string             `json:"library_version"`
+	ConfigFingerprint string             `json:"config_fingerprint,omitempty"`
+	Source            loader.CompactTest `json:"source"`
+	Flat              types.TestCase     `json:"flat"`
+	Result            TestResult         `json:"result"` // Actual holds the implementation's output, Message its error
+}
+
+// ExportRepro writes a Repro for result as indented JSON. sourceLookup
+// returns the source test with the given name; the flat test is rebuilt
+// from the source validation matching the result's.
+func ExportRepro(result TestResult, sourceLookup func(name string) (loader.CompactTest, bool), w io.Writer) error {
+	name := sourceTestName(types.TestCase{Name: result.Name, Validation: result.Validation})
+	source, ok := sourceLookup(name)
+	if !ok {
+		return fmt.Errorf("source test %q for %s not found", name, result.Name)
+	}
+	validation, ok := reproValidation(source, result)
+	if !ok {
+		return fmt.Errorf("source test %q has no %s validation", name, result.Validation)
+	}
+
+	var errorType string
+	if source.ExpectParseError {
+		errorType = source.ErrorType
+	}
+
+	repro := Repro{
+		LibraryVersion:    version.Version,
+		ConfigFingerprint: result.ConfigFingerprint,
+		Source:            source,
+		Flat: types.TestCase{
+			Name:                 result.Name,
+			Inputs:               source.Inputs,
+			Validation:           result.Validation,
+			Expected:             result.Expected,
+			Args:                 validation.Args,
+			ExpectError:          validation.Error,
+			ErrorType:            errorType,
+			Tolerance:            validation.Tolerance,
+			Default:              validation.Default,
+			Features:             source.Features,
+			Behaviors:            source.Behaviors,
+			Variants:             source.Variants,
+			Conflicts:            source.Conflicts,
+			UnicodeNormalization: source.UnicodeNormalization,
+			SourceTest:           source.Name,
+			Meta:                 types.TestMetadata{Override: result.Override},
+		},
+		Result: result,
+	}
+
+	data, err := json.MarshalIndent(repro, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal repro: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write repro: %w", err)
+	}
+	return nil
+}
+
+// reproValidation finds the source validation a result ran. When a function
+// repeats, the one whose expectation matches the result's is preferred.
+func reproValidation(source loader.CompactTest, result TestResult) (loader.CompactValidation, bool) {
+	var found []loader.CompactValidation
+	for _, validation := range source.Validations() {
+		if validation.Function == result.Validation {
+			found = append(found, validation)
+		}
+	}
+	if len(found) == 0 {
+		return loader.CompactValidation{}, false
+	}
+	for _, validation := range found {
+		if sameJSON(validation.Expect, result.Expected) {
+			return validation, true
+		}
+	}
+	return found[0], true
+}
+
+// sameJSON reports whether two values marshal to the same JSON
+func sameJSON(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(dataA) == string(dataB)
+}
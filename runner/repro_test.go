@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/internal/version"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+var reproSource = loader.CompactTest{
+	Name:      "port",
+	Inputs:    []string{"port = 8080"},
+	Behaviors: []string{"boolean_strict"},
+	Tests: []loader.CompactValidation{
+		{Function: "parse", Expect: []interface{}{map[string]interface{}{"key": "port", "value": "8080"}}},
+		{Function: "get_int", Args: []string{"port"}, Expect: float64(80)},
+	},
+}
+
+func lookupSource(name string) (loader.CompactTest, bool) {
+	return reproSource, name == reproSource.Name
+}
+
+func TestExportRepro(t *testing.T) {
+	cfg := toyccl.Config()
+	flat := types.TestCase{Name: "port_get_int", Inputs: reproSource.Inputs, Validation: "get_int", Args: []string{"port"}, Expected: float64(80), SourceTest: "port"}
+	results := Run(toyccl.New(), []types.TestCase{flat}, RunOptions{Config: &cfg})
+	result := results.Results[0]
+	if result.Passed() {
+		t.Fatalf("Expected the test to fail, got %+v", result)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportRepro(result, lookupSource, &buf); err != nil {
+		t.Fatalf("ExportRepro failed: %v", err)
+	}
+
+	var repro Repro
+	if err := json.Unmarshal(buf.Bytes(), &repro); err != nil {
+		t.Fatalf("Repro is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if repro.LibraryVersion != version.Version || repro.ConfigFingerprint != cfg.Fingerprint() {
+		t.Errorf("Expected version %s and fingerprint %s, got %s and %s", version.Version, cfg.Fingerprint(), repro.LibraryVersion, repro.ConfigFingerprint)
+	}
+	if repro.Source.Name != "port" || len(repro.Source.Tests) != 2 {
+		t.Errorf("Expected the whole source test, got %+v", repro.Source)
+	}
+	if !reflect.DeepEqual(repro.Flat.Args, []string{"port"}) || !reflect.DeepEqual(repro.Flat.Behaviors, []string{"boolean_strict"}) {
+		t.Errorf("Expected the flat test rebuilt from the source, got %+v", repro.Flat)
+	}
+	if repro.Result.Actual != float64(8080) || repro.Result.Status != StatusFail {
+		t.Errorf("Expected the failing result with its actual output, got %+v", repro.Result)
+	}
+
+	// The flat test alone reproduces the failure
+	rerun := RunTest(toyccl.New(), repro.Flat)
+	if rerun.Passed() || rerun.Message != result.Message {
+		t.Errorf("Expected the repro to fail the same way, got %+v", rerun)
+	}
+}
+
+func TestExportRepro_SourceMissing(t *testing.T) {
+	result := TestResult{Name: "other_parse", Validation: "parse", Status: StatusFail}
+	err := ExportRepro(result, lookupSource, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), `source test "other" for other_parse not found`) {
+		t.Errorf("Expected a missing source error, got %v", err)
+	}
+
+	result = TestResult{Name: "port_get_bool", Validation: "get_bool", Status: StatusFail}
+	if err := ExportRepro(result, lookupSource, &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for a validation the source test lacks")
+	}
+}
+
+func TestRun_ConfigFingerprint(t *testing.T) {
+	cfg := config.ImplementationConfig{Name: "toy"}
+	results := Run(toyccl.New(), createRunTests(), RunOptions{Config: &cfg})
+	for _, result := range results.Results {
+		if result.ConfigFingerprint != cfg.Fingerprint() {
+			t.Errorf("%s: expected fingerprint %s, got %q", result.Name, cfg.Fingerprint(), result.ConfigFingerprint)
+		}
+	}
+	if Run(toyccl.New(), createRunTests(), RunOptions{}).Results[0].ConfigFingerprint != "" {
+		t.Error("Expected no fingerprint without a config")
+	}
+}
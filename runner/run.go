@@ -0,0 +1,256 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// now is the clock skip until dates are checked against; tests replace it
+var now = time.Now
+
+// RunOptions controls test execution behavior
+type RunOptions struct {
+	PriorResults *RunResults // Run tests that failed previously first
+	FailFast     int         // Stop after this many failures (0 = run everything)
+
+	// ShuffleSeed, when nonzero, runs the tests in an order shuffled by the
+	// seed instead of by name; the same seed gives the same order, and
+	// RunResults records it (see DetectOrderDependence)
+	ShuffleSeed int64
+
+	// Timeout fails a test whose call takes longer; a test's
+	// limits.timeout_ms overrides it. Zero waits indefinitely.
+	Timeout time.Duration
+
+	// UnorderedEntries compares entry lists as multisets: order is ignored,
+	// but each repeated key must appear as often as expected
+	UnorderedEntries bool
+
+	// UnicodeNormalization normalizes expected and actual strings before
+	// comparing them; a test's own setting takes precedence
+	UnicodeNormalization types.UnicodeNormalization
+
+	// DiffMaxValueLength truncates values longer than this many characters
+	// in failure messages (DiffMaxValueLength if zero); see FormatDiffLimit
+	DiffMaxValueLength int
+
+	// IgnoreTrailingNewline compares pretty_print and canonical_format
+	// output with trailing newlines removed from both sides
+	IgnoreTrailingNewline bool
+
+	// HierarchyLeaves says which typed leaves of a build_hierarchy result
+	// match the expected strings (LeavesCoerce if empty)
+	HierarchyLeaves HierarchyLeaves
+
+	// HierarchyMaxPaths is the number of divergent paths a build_hierarchy
+	// failure message lists (DiffMaxPaths if zero)
+	HierarchyMaxPaths int
+
+	// RunSkipped runs skipped tests too. They are still reported as
+	// skipped, and flagged as stale when they pass after their until date.
+	RunSkipped bool
+
+	// UpdateExpected rewrites the source expected values of failing tests
+	// with their actual results (see UpdateExpected)
+	UpdateExpected *UpdateExpectedOptions
+
+	// Config is the implementation's config; when set, its fingerprint is
+	// recorded in each result so a repro can name it (see ExportRepro)
+	Config *config.ImplementationConfig
+}
+
+// RunResults holds the outcome of a run, in execution order
+type RunResults struct {
+	Results []TestResult `json:"results"`
+	Passed  int          `json:"passed"`
+	Failed  int          `json:"failed"`
+	NotRun  int          `json:"not_run"`
+	Skipped int          `json:"skipped"`
+
+	ShuffleSeed int64 `json:"shuffle_seed,omitempty"` // RunOptions.ShuffleSeed, to reproduce the order
+
+	Update   *UpdateSummary `json:"update,omitempty"`   // Set when UpdateExpected was requested
+	Warnings []string       `json:"warnings,omitempty"` // PriorResults matched to tests by name (see MatchTests)
+}
+
+// Run executes tests against the implementation in name order, or shuffled
+// by ShuffleSeed. When PriorResults is set, tests that failed previously
+// run first.
+// When FailFast is reached, the remaining tests are recorded as not run.
+// Any update error is recorded in the results' update summary.
+func Run(impl CCLImplementation, tests []types.TestCase, opts RunOptions) *RunResults {
+	ordered, warnings := orderTests(tests, opts.PriorResults, opts.ShuffleSeed)
+
+	results := &RunResults{Results: make([]TestResult, 0, len(ordered)), ShuffleSeed: opts.ShuffleSeed, Warnings: warnings}
+	for _, test := range ordered {
+		if opts.FailFast > 0 && results.Failed >= opts.FailFast {
+			results.Results = append(results.Results, TestResult{
+				Name:       test.Name,
+				ID:         test.ID(),
+				Validation: test.Validation,
+				Features:   test.Features,
+				SourceTest: test.SourceTest,
+				Status:     StatusNotRun,
+				Message:    fmt.Sprintf("not run: stopped after %d failures", opts.FailFast),
+				Override:   test.Meta.Override,
+			})
+			results.NotRun++
+			continue
+		}
+
+		if test.Skip != nil {
+			results.Results = append(results.Results, skipTest(impl, test, opts))
+			results.Skipped++
+			continue
+		}
+
+		result := runTest(impl, test, opts)
+		results.Results = append(results.Results, result)
+		if result.Passed() {
+			results.Passed++
+		} else {
+			results.Failed++
+		}
+	}
+
+	if opts.Config != nil {
+		fingerprint := opts.Config.Fingerprint()
+		for i := range results.Results {
+			results.Results[i].ConfigFingerprint = fingerprint
+		}
+	}
+
+	if opts.UpdateExpected != nil {
+		summary, err := UpdateExpected(ordered, results, *opts.UpdateExpected)
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		results.Update = summary
+	}
+
+	return results
+}
+
+// skipTest records a skipped test, running it first if RunSkipped is set
+func skipTest(impl CCLImplementation, test types.TestCase, opts RunOptions) TestResult {
+	message := "skipped: " + test.Skip.Reason
+	if !opts.RunSkipped {
+		return TestResult{
+			Name:       test.Name,
+			ID:         test.ID(),
+			Validation: test.Validation,
+			Features:   test.Features,
+			SourceTest: test.SourceTest,
+			Status:     StatusSkipped,
+			Message:    message,
+			Override:   test.Meta.Override,
+		}
+	}
+
+	result := runTest(impl, test, opts)
+	switch {
+	case result.Passed() && test.Skip.Expired(now()):
+		result.StaleSkip = true
+		message = fmt.Sprintf("skipped test now passes (skipped until %s): %s", test.Skip.Until, test.Skip.Reason)
+	case result.Message != "":
+		message += "; " + result.Message
+	}
+	result.Status = StatusSkipped
+	result.Message = message
+	return result
+}
+
+// StaleSkips returns the skipped tests that pass after their until date
+func (r *RunResults) StaleSkips() []TestResult {
+	var stale []TestResult
+	for _, result := range r.Results {
+		if result.StaleSkip {
+			stale = append(stale, result)
+		}
+	}
+	return stale
+}
+
+// orderTests returns tests in execution order: previously failed tests
+// first, then the rest, each group sorted by name or shuffled by a nonzero
+// seed. Prior results are matched to tests as MatchTests does, returning
+// its warnings.
+func orderTests(tests []types.TestCase, prior *RunResults, seed int64) ([]types.TestCase, []string) {
+	ordered := make([]types.TestCase, len(tests))
+	copy(ordered, tests)
+
+	failedBefore := make(map[string]bool)
+	var warnings []string
+	if prior != nil {
+		var matched map[string]TestResult
+		matched, warnings = prior.MatchTests(tests)
+		for name, result := range matched {
+			if result.Status == StatusFail {
+				failedBefore[name] = true
+			}
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		fi, fj := failedBefore[ordered[i].Name], failedBefore[ordered[j].Name]
+		if fi != fj {
+			return fi
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+	if seed != 0 {
+		// Shuffle from name order, so the input order does not matter
+		rng := rand.New(rand.NewPCG(uint64(seed), 0))
+		failed := 0
+		for failed < len(ordered) && failedBefore[ordered[failed].Name] {
+			failed++
+		}
+		for _, group := range [][]types.TestCase{ordered[:failed], ordered[failed:]} {
+			rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		}
+	}
+	return ordered, warnings
+}
+
+// Failures returns the failed results
+func (r *RunResults) Failures() []TestResult {
+	var failures []TestResult
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// SaveResults writes run results as JSON
+func SaveResults(path string, results *RunResults) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results file: %w", err)
+	}
+	return nil
+}
+
+// LoadResults reads run results previously written by SaveResults
+func LoadResults(path string) (*RunResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+	var results RunResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse results file: %w", err)
+	}
+	return &results, nil
+}
@@ -0,0 +1,494 @@
+package runner
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// createRunTests returns tests where "b_fail" and "d_fail" fail against the toy implementation
+func createRunTests() []types.TestCase {
+	return []types.TestCase{
+		{Name: "c_pass", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")},
+		{Name: "a_pass", Inputs: []string{"a = b"}, Validation: "get_string", Args: []string{"a"}, Expected: "b"},
+		{Name: "d_fail", Inputs: []string{"a = b"}, Validation: "get_string", Args: []string{"a"}, Expected: "c"},
+		{Name: "b_fail", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "x")},
+		{Name: "e_pass", Inputs: []string{"a = 1"}, Validation: "get_int", Args: []string{"a"}, Expected: 1},
+	}
+}
+
+func resultNames(results *RunResults) []string {
+	names := make([]string, len(results.Results))
+	for i, result := range results.Results {
+		names[i] = result.Name
+	}
+	return names
+}
+
+func TestRun_NameOrderWithoutPriorResults(t *testing.T) {
+	results := Run(toyccl.New(), createRunTests(), RunOptions{})
+
+	expected := []string{"a_pass", "b_fail", "c_pass", "d_fail", "e_pass"}
+	if got := resultNames(results); !equalStrings(got, expected) {
+		t.Errorf("Expected order %v, got %v", expected, got)
+	}
+	if results.Passed != 3 || results.Failed != 2 || results.NotRun != 0 {
+		t.Errorf("Unexpected counts: passed=%d failed=%d not_run=%d", results.Passed, results.Failed, results.NotRun)
+	}
+}
+
+func TestRun_RecordsSourceTest(t *testing.T) {
+	tests := createRunTests()
+	for i := range tests {
+		tests[i].SourceTest = "source_" + tests[i].Name
+	}
+	for _, result := range Run(toyccl.New(), tests, RunOptions{FailFast: 1}).Results {
+		if result.SourceTest != "source_"+result.Name {
+			t.Errorf("Expected %s to record its source test, got %q", result.Name, result.SourceTest)
+		}
+	}
+}
+
+func TestRun_PreviouslyFailedFirst(t *testing.T) {
+	prior := &RunResults{Results: []TestResult{
+		{Name: "e_pass", Status: StatusFail},
+		{Name: "c_pass", Status: StatusFail},
+		{Name: "a_pass", Status: StatusPass},
+	}}
+
+	results := Run(toyccl.New(), createRunTests(), RunOptions{PriorResults: prior})
+
+	// Prior failures by name, then everything else by name
+	expected := []string{"c_pass", "e_pass", "a_pass", "b_fail", "d_fail"}
+	if got := resultNames(results); !equalStrings(got, expected) {
+		t.Errorf("Expected order %v, got %v", expected, got)
+	}
+	// The prior results have no IDs, so each was matched by name
+	if len(results.Warnings) != 3 {
+		t.Errorf("Expected a warning per name match, got %v", results.Warnings)
+	}
+}
+
+func TestRun_FailFastMarksRemainderNotRun(t *testing.T) {
+	results := Run(toyccl.New(), createRunTests(), RunOptions{FailFast: 1})
+
+	// b_fail is the first failure; c_pass, d_fail and e_pass are not run
+	if results.Failed != 1 {
+		t.Errorf("Expected 1 failure, got %d", results.Failed)
+	}
+	if results.NotRun != 3 {
+		t.Errorf("Expected 3 not run, got %d", results.NotRun)
+	}
+	if len(results.Results) != 5 {
+		t.Fatalf("Expected every test in results, got %d", len(results.Results))
+	}
+	for _, result := range results.Results[2:] {
+		if result.Status != StatusNotRun {
+			t.Errorf("Expected %s to be not run, got %s", result.Name, result.Status)
+		}
+	}
+}
+
+func TestRun_FailFastCountsFailures(t *testing.T) {
+	results := Run(toyccl.New(), createRunTests(), RunOptions{FailFast: 2})
+
+	if results.Failed != 2 || results.NotRun != 1 {
+		t.Errorf("Expected 2 failures and 1 not run, got failed=%d not_run=%d", results.Failed, results.NotRun)
+	}
+}
+
+func TestRun_FailFastWithPriorResults(t *testing.T) {
+	prior := &RunResults{Results: []TestResult{{Name: "d_fail", Status: StatusFail}}}
+
+	results := Run(toyccl.New(), createRunTests(), RunOptions{PriorResults: prior, FailFast: 1})
+
+	// The known failure runs first, so nothing else runs
+	if results.Results[0].Name != "d_fail" || results.Results[0].Status != StatusFail {
+		t.Errorf("Expected d_fail to run first and fail, got %+v", results.Results[0])
+	}
+	if results.NotRun != 4 {
+		t.Errorf("Expected 4 not run, got %d", results.NotRun)
+	}
+}
+
+func TestSaveAndLoadResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	results := Run(toyccl.New(), createRunTests(), RunOptions{})
+
+	if err := SaveResults(path, results); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+	loaded, err := LoadResults(path)
+	if err != nil {
+		t.Fatalf("LoadResults failed: %v", err)
+	}
+
+	if loaded.Passed != results.Passed || loaded.Failed != results.Failed {
+		t.Errorf("Counts not preserved: %+v vs %+v", loaded, results)
+	}
+	if len(loaded.Failures()) != 2 {
+		t.Errorf("Expected 2 failures after reload, got %d", len(loaded.Failures()))
+	}
+}
+
+func TestRun_DuplicateKeys(t *testing.T) {
+	input := []string{"servers = a\nservers = b"}
+	tests := []types.TestCase{
+		{Name: "both", Inputs: input, Validation: "parse", Expected: entries("servers", "a", "servers", "b")},
+		{Name: "swapped", Inputs: input, Validation: "parse", Expected: entries("servers", "b", "servers", "a")},
+		{Name: "collapsed", Inputs: input, Validation: "parse", Expected: entries("servers", "b")},
+		{Name: "doubled", Inputs: input, Validation: "parse", Expected: entries("servers", "a", "servers", "a")},
+	}
+
+	status := func(opts RunOptions) map[string]Status {
+		got := make(map[string]Status)
+		for _, result := range Run(toyccl.New(), tests, opts).Results {
+			got[result.Name] = result.Status
+		}
+		return got
+	}
+
+	ordered := status(RunOptions{})
+	want := map[string]Status{"both": StatusPass, "swapped": StatusFail, "collapsed": StatusFail, "doubled": StatusFail}
+	for name, s := range want {
+		if ordered[name] != s {
+			t.Errorf("Positional %s: expected %s, got %s", name, s, ordered[name])
+		}
+	}
+
+	unordered := status(RunOptions{UnorderedEntries: true})
+	want["swapped"] = StatusPass
+	for name, s := range want {
+		if unordered[name] != s {
+			t.Errorf("Multiset %s: expected %s, got %s", name, s, unordered[name])
+		}
+	}
+}
+
+func TestRun_FloatTolerance(t *testing.T) {
+	input := []string{"pi = 3.14159"}
+	tests := []types.TestCase{
+		{Name: "within", Inputs: input, Validation: "get_float", Args: []string{"pi"}, Expected: 3.14, Tolerance: 0.01},
+		{Name: "outside", Inputs: input, Validation: "get_float", Args: []string{"pi"}, Expected: 3.14, Tolerance: 0.001},
+		{Name: "exact_default", Inputs: input, Validation: "get_float", Args: []string{"pi"}, Expected: 3.14},
+		{Name: "exact_match", Inputs: input, Validation: "get_float", Args: []string{"pi"}, Expected: 3.14159},
+	}
+
+	want := map[string]Status{"within": StatusPass, "outside": StatusFail, "exact_default": StatusFail, "exact_match": StatusPass}
+	for _, result := range Run(toyccl.New(), tests, RunOptions{}).Results {
+		if result.Status != want[result.Name] {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, want[result.Name], result.Status, result.Message)
+		}
+	}
+}
+
+// slowImplementation delays parsing inputs that start with "slow"
+type slowImplementation struct {
+	*toyccl.Implementation
+	delay time.Duration
+}
+
+func (s slowImplementation) Parse(input string) ([]types.Entry, error) {
+	if strings.HasPrefix(input, "slow") {
+		time.Sleep(s.delay)
+	}
+	return s.Implementation.Parse(input)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	impl := slowImplementation{toyccl.New(), 100 * time.Millisecond}
+	tests := []types.TestCase{
+		{Name: "fast", Inputs: []string{"a = b"}, Validation: "parse", Expected: entries("a", "b")},
+		{Name: "slow_global", Inputs: []string{"slow = b"}, Validation: "parse", Expected: entries("slow", "b")},
+		{Name: "slow_error", Inputs: []string{"slow"}, Validation: "parse", ExpectError: true},
+		{Name: "slow_own_timeout", Inputs: []string{"slow = b"}, Validation: "parse", Expected: entries("slow", "b"),
+			Meta: types.TestMetadata{Limits: &types.Limits{TimeoutMS: 5000}}},
+	}
+
+	want := map[string]Status{"fast": StatusPass, "slow_global": StatusFail, "slow_error": StatusFail, "slow_own_timeout": StatusPass}
+	for _, result := range Run(impl, tests, RunOptions{Timeout: 20 * time.Millisecond}).Results {
+		if result.Status != want[result.Name] {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, want[result.Name], result.Status, result.Message)
+		}
+		if want[result.Name] == StatusFail && result.Message != "timed out after 20ms" {
+			t.Errorf("%s: expected a timeout message, got %q", result.Name, result.Message)
+		}
+	}
+
+	// Without a global timeout, calls are not bounded
+	if results := Run(impl, tests[:2], RunOptions{}); results.Passed != 2 {
+		t.Errorf("Expected both tests to pass without a timeout, got %+v", results.Results)
+	}
+}
+
+func TestRun_DefaultValues(t *testing.T) {
+	input := []string{"port = 8080\nname = app\nserver =\n  port = 80"}
+	fallback := func(value string) *string { return &value }
+	tests := []types.TestCase{
+		{Name: "present_key", Inputs: input, Validation: "get_int", Args: []string{"port"}, Default: fallback("80"), Expected: 8080},
+		{Name: "absent_with_default", Inputs: input, Validation: "get_string", Args: []string{"host"}, Default: fallback("localhost"), Expected: "localhost"},
+		{Name: "absent_no_default", Inputs: input, Validation: "get_string", Args: []string{"host"}, ExpectError: true},
+		{Name: "default_expecting_error", Inputs: input, Validation: "get_string", Args: []string{"host"}, Default: fallback("localhost"), ExpectError: true},
+		{Name: "nested_absent_with_default", Inputs: input, Validation: "get_string", Args: []string{"server", "host"}, Default: fallback("localhost"), Expected: "localhost"},
+	}
+
+	want := map[string]struct {
+		status      Status
+		defaultUsed bool
+	}{
+		"present_key":                {StatusPass, false},
+		"absent_with_default":        {StatusPass, true},
+		"absent_no_default":          {StatusPass, false},
+		"default_expecting_error":    {StatusFail, true},
+		"nested_absent_with_default": {StatusPass, true},
+	}
+	for _, result := range Run(toyccl.New(), tests, RunOptions{}).Results {
+		expected := want[result.Name]
+		if result.Status != expected.status || result.DefaultUsed != expected.defaultUsed {
+			t.Errorf("%s: expected %s with DefaultUsed %t, got %s with %t (%s)",
+				result.Name, expected.status, expected.defaultUsed, result.Status, result.DefaultUsed, result.Message)
+		}
+	}
+}
+
+// plainImplementation hides the toy implementation's DefaultReporter
+type plainImplementation struct {
+	CCLImplementation
+}
+
+// Two args are a key path, never a key and a default
+func TestRun_TwoSegmentPath(t *testing.T) {
+	input := []string{"a =\n  b = c"}
+	tests := []types.TestCase{
+		{Name: "nested_read", Inputs: input, Validation: "get_string", Args: []string{"a", "b"}, Expected: "c"},
+		{Name: "nested_missing", Inputs: input, Validation: "get_string", Args: []string{"a", "x"}, ExpectError: true},
+	}
+	for _, test := range tests {
+		if problems := test.Validate(); len(problems) != 0 {
+			t.Errorf("%s: expected a valid test, got %v", test.Name, problems)
+		}
+		if result := RunTest(toyccl.New(), test); !result.Passed() || result.DefaultUsed {
+			t.Errorf("%s: expected a pass without a default, got %+v", test.Name, result)
+		}
+	}
+}
+
+func TestRun_DefaultValuesWithoutReporter(t *testing.T) {
+	input := []string{"server =\n  port = 80"}
+	fallback := "8080"
+	tests := []types.TestCase{
+		{Name: "present_with_default", Inputs: input, Validation: "get_string", Args: []string{"server", "port"}, Default: &fallback, Expected: "80"},
+		{Name: "absent_with_default", Inputs: input, Validation: "get_string", Args: []string{"server", "host"}, Default: &fallback, Expected: "8080"},
+		{Name: "no_default", Inputs: input, Validation: "get_string", Args: []string{"server", "port"}, Expected: "80"},
+	}
+
+	for _, result := range Run(plainImplementation{toyccl.New()}, tests, RunOptions{}).Results {
+		if result.Name == "no_default" {
+			if result.Status != StatusPass {
+				t.Errorf("%s: expected pass, got %s (%s)", result.Name, result.Status, result.Message)
+			}
+			continue
+		}
+		if result.Status != StatusFail || !strings.HasPrefix(result.Message, "not supported") || result.DefaultUsed {
+			t.Errorf("%s: expected a not supported failure, got %s (%s)", result.Name, result.Status, result.Message)
+		}
+	}
+}
+
+func TestRun_Skip(t *testing.T) {
+	original := now
+	now = func() time.Time { return time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = original })
+
+	input := []string{"a = 1"}
+	tests := []types.TestCase{
+		{Name: "expired_passing", Inputs: input, Validation: "get_string", Args: []string{"a"}, Expected: "1", Skip: &types.SkipInfo{Reason: "dispute", Until: "2025-06-01"}},
+		{Name: "current_passing", Inputs: input, Validation: "get_string", Args: []string{"a"}, Expected: "1", Skip: &types.SkipInfo{Reason: "dispute", Until: "2025-06-02"}},
+		{Name: "expired_failing", Inputs: input, Validation: "get_string", Args: []string{"a"}, Expected: "2", Skip: &types.SkipInfo{Reason: "dispute", Until: "2025-06-01"}},
+		{Name: "plain", Inputs: input, Validation: "get_string", Args: []string{"a"}, Expected: "1"},
+	}
+
+	results := Run(toyccl.New(), tests, RunOptions{})
+	if results.Passed != 1 || results.Skipped != 3 || results.Failed != 0 {
+		t.Fatalf("Expected 1 passed and 3 skipped, got %+v", results)
+	}
+	for _, result := range results.Results {
+		if result.Name != "plain" && (result.Status != StatusSkipped || result.Message != "skipped: dispute" || result.Actual != nil) {
+			t.Errorf("%s: expected skipped without running, got %+v", result.Name, result)
+		}
+	}
+
+	results = Run(toyccl.New(), tests, RunOptions{RunSkipped: true})
+	if results.Passed != 1 || results.Skipped != 3 || results.Failed != 0 {
+		t.Fatalf("Expected skipped tests to stay skipped when run, got %+v", results)
+	}
+	stale := results.StaleSkips()
+	if len(stale) != 1 || stale[0].Name != "expired_passing" || stale[0].Message != "skipped test now passes (skipped until 2025-06-01): dispute" {
+		t.Errorf("Expected only expired_passing flagged, got %+v", stale)
+	}
+	for _, result := range results.Results {
+		if result.Name == "expired_failing" && !strings.HasPrefix(result.Message, "skipped: dispute; ") {
+			t.Errorf("Expected the failure after the skip reason, got %q", result.Message)
+		}
+	}
+}
+
+func TestRun_UnicodeNormalization(t *testing.T) {
+	composed, decomposed := "caf\u00e9", "cafe\u0301"
+	input := []string{composed + " = " + composed}
+	tests := []types.TestCase{
+		{Name: "get_string", Inputs: input, Validation: "get_string", Args: []string{composed}, Expected: decomposed},
+		{Name: "parse", Inputs: input, Validation: "parse", Expected: entries(decomposed, decomposed)},
+		{Name: "opted_out", Inputs: input, Validation: "get_string", Args: []string{composed}, Expected: decomposed, UnicodeNormalization: types.NormalizationNone},
+	}
+
+	status := func(opts RunOptions) map[string]Status {
+		got := make(map[string]Status)
+		for _, result := range Run(toyccl.New(), tests, opts).Results {
+			got[result.Name] = result.Status
+		}
+		return got
+	}
+
+	strict := status(RunOptions{})
+	for _, name := range []string{"get_string", "parse", "opted_out"} {
+		if strict[name] != StatusFail {
+			t.Errorf("Default %s: expected fail, got %s", name, strict[name])
+		}
+	}
+
+	nfc := status(RunOptions{UnicodeNormalization: types.NormalizationNFC})
+	want := map[string]Status{"get_string": StatusPass, "parse": StatusPass, "opted_out": StatusFail}
+	for name, s := range want {
+		if nfc[name] != s {
+			t.Errorf("NFC %s: expected %s, got %s", name, s, nfc[name])
+		}
+	}
+
+	tests[0].UnicodeNormalization = types.NormalizationNFC
+	if got := status(RunOptions{})["get_string"]; got != StatusPass {
+		t.Errorf("Per-test NFC: expected pass, got %s", got)
+	}
+}
+
+func TestRun_IgnoreTrailingNewline(t *testing.T) {
+	input := []string{"a = b"}
+	tests := []types.TestCase{
+		{Name: "pretty_print", Inputs: input, Validation: "pretty_print", Expected: "a = b"},
+		{Name: "canonical_format", Inputs: input, Validation: "canonical_format", Expected: "a = b\n\n"},
+		{Name: "get_string", Inputs: []string{"a = b\n  "}, Validation: "get_string", Args: []string{"a"}, Expected: "b"},
+	}
+
+	status := func(opts RunOptions) map[string]Status {
+		got := make(map[string]Status)
+		for _, result := range Run(toyccl.New(), tests, opts).Results {
+			got[result.Name] = result.Status
+		}
+		return got
+	}
+
+	strict := status(RunOptions{})
+	for _, name := range []string{"pretty_print", "canonical_format"} {
+		if strict[name] != StatusFail {
+			t.Errorf("Default %s: expected fail, got %s", name, strict[name])
+		}
+	}
+
+	lenient := status(RunOptions{IgnoreTrailingNewline: true})
+	for _, name := range []string{"pretty_print", "canonical_format"} {
+		if lenient[name] != StatusPass {
+			t.Errorf("Ignoring trailing newlines, %s: expected pass, got %s", name, lenient[name])
+		}
+	}
+	if strict["get_string"] != lenient["get_string"] {
+		t.Errorf("Expected the option not to affect get_string, got %s and %s", strict["get_string"], lenient["get_string"])
+	}
+}
+
+func TestRun_Combine(t *testing.T) {
+	tests := []types.TestCase{
+		{Name: "merged", Inputs: []string{"a = 1\nb = 2", "a = 3"}, Validation: "combine", Expected: entries("a", "1", "b", "2", "a", "3")},
+		{Name: "reordered", Inputs: []string{"a = 1", "b = 2"}, Validation: "combine", Expected: entries("b", "2", "a", "1")},
+		{Name: "one_input", Inputs: []string{"a = 1"}, Validation: "combine", Expected: entries("a", "1")},
+	}
+
+	results := Run(toyccl.New(), tests, RunOptions{})
+	got := make(map[string]TestResult)
+	for _, result := range results.Results {
+		got[result.Name] = result
+	}
+	if !got["merged"].Passed() {
+		t.Errorf("Expected the merged entries to match, got %s", got["merged"].Message)
+	}
+	if got["reordered"].Passed() {
+		t.Error("Expected merged entries to be compared in order")
+	}
+	if msg := got["one_input"].Message; !strings.Contains(msg, "combine takes 2 inputs, got 1") {
+		t.Errorf("Expected an input count failure, got %q", msg)
+	}
+
+	results = Run(toyccl.New(), tests[1:2], RunOptions{UnorderedEntries: true})
+	if results.Passed != 1 {
+		t.Errorf("Expected unordered comparison to accept reordered entries, got %+v", results.Results)
+	}
+}
+
+func TestRun_HugeExpectedValue(t *testing.T) {
+	huge := strings.Repeat("0123456789abcdef", 3<<16) // 3 MiB
+	changed := huge[:len(huge)-1] + "X"
+	tests := []types.TestCase{
+		{Name: "same", Inputs: []string{"a = " + huge}, Validation: "get_string", Args: []string{"a"}, Expected: huge},
+		{Name: "changed", Inputs: []string{"a = " + huge}, Validation: "get_string", Args: []string{"a"}, Expected: changed},
+	}
+
+	results := Run(toyccl.New(), tests, RunOptions{})
+	if results.Passed != 1 || results.Failed != 1 {
+		t.Fatalf("Expected the equal value to pass and the changed one to fail, got %+v", results)
+	}
+	message := results.Results[0].Message
+	if len(message) > 400 || !strings.Contains(message, "(3145730 chars, sha256 ") {
+		t.Errorf("Expected a short message with the length and hash, got %d bytes: %.400s", len(message), message)
+	}
+	if strings.Count(message, "sha256") != 2 {
+		t.Errorf("Expected both values hashed, got %s", message)
+	}
+
+	results = Run(toyccl.New(), tests[1:], RunOptions{DiffMaxValueLength: 5})
+	if message := results.Results[0].Message; !strings.HasPrefix(message, `expected "0123… (3145730 chars, sha256 `) {
+		t.Errorf("Expected the configured limit, got %.200s", message)
+	}
+}
+
+func TestRun_LazyExpected(t *testing.T) {
+	dir := testsupport.Minimal(t).Build()
+	tl := loader.NewTestLoader(dir, toyccl.Config())
+	tests, err := tl.LoadAllTests(loader.LoadOptions{Format: loader.FormatFlat, FilterMode: loader.FilterCompatible, LazyExpected: true})
+	if err != nil {
+		t.Fatalf("LoadAllTests failed: %v", err)
+	}
+	if len(tests) == 0 || tests[0].Expected != nil {
+		t.Fatalf("Expected lazily loaded tests, got %+v", tests)
+	}
+
+	results := Run(toyccl.New(), tests, RunOptions{})
+	if results.Failed != 0 || results.Passed != len(tests) {
+		t.Errorf("Expected every lazily loaded test to pass, got %+v", results.Results)
+	}
+	for _, result := range results.Results {
+		if result.Expected == nil {
+			t.Errorf("%s: expected the decoded expectation in the result", result.Name)
+		}
+	}
+
+	corrupt := types.TestCase{Name: "corrupt", Inputs: []string{"a = b"}, Validation: "parse", RawExpected: json.RawMessage(`{"count": 1, "entries": [`)}
+	if result := RunTest(toyccl.New(), corrupt); result.Passed() || !strings.Contains(result.Message, "invalid expected value") {
+		t.Errorf("Expected an invalid expected value failure, got %s: %s", result.Status, result.Message)
+	}
+}
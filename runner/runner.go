@@ -0,0 +1,279 @@
+// Package runner provides infrastructure for executing CCL test cases
+// against an implementation under test.
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// ErrNotSupported is returned by implementations for functions they do not provide
+var ErrNotSupported = errors.New("function not supported")
+
+// CCLImplementation is the interface an implementation under test provides.
+// Each method corresponds to one CCL function; implementations that lack a
+// function should return ErrNotSupported (embedding UnimplementedImplementation
+// does this automatically).
+type CCLImplementation interface {
+	Parse(input string) ([]types.Entry, error)
+	ParseIndented(input string) ([]types.Entry, error)
+	Filter(input string) ([]types.Entry, error)
+	ExtractComments(input string) ([]types.Entry, error)
+	ExpandDotted(input string) ([]types.Entry, error)
+	Combine(a, b string) ([]types.Entry, error)
+	BuildHierarchy(input string) (map[string]interface{}, error)
+	GetString(input string, args []string) (string, error)
+	GetInt(input string, args []string) (int, error)
+	GetBool(input string, args []string) (bool, error)
+	GetFloat(input string, args []string) (float64, error)
+	GetList(input string, args []string) ([]string, error)
+	PrettyPrint(input string) (string, error)
+	CanonicalFormat(input string) (string, error)
+}
+
+// DefaultReporter is optionally implemented by implementations whose typed
+// accessors take a default value. For typed access tests with a default
+// (types.TestCase.HasDefault) the runner calls GetWithDefault instead of the
+// typed accessor, and records in TestResult.DefaultUsed whether the key was
+// absent and the default returned. Implementations without it fail such
+// tests as not supported.
+type DefaultReporter interface {
+	GetWithDefault(fn config.CCLFunction, input string, path []string, fallback string) (value interface{}, defaultUsed bool, err error)
+}
+
+// UnimplementedImplementation returns ErrNotSupported for every function.
+// Embed it to implement only the functions an implementation supports.
+type UnimplementedImplementation struct{}
+
+func (UnimplementedImplementation) Parse(string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) ParseIndented(string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) Filter(string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) ExtractComments(string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) ExpandDotted(string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) Combine(string, string) ([]types.Entry, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) BuildHierarchy(string) (map[string]interface{}, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) GetString(string, []string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (UnimplementedImplementation) GetInt(string, []string) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (UnimplementedImplementation) GetBool(string, []string) (bool, error) {
+	return false, ErrNotSupported
+}
+
+func (UnimplementedImplementation) GetFloat(string, []string) (float64, error) {
+	return 0, ErrNotSupported
+}
+
+func (UnimplementedImplementation) GetList(string, []string) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+func (UnimplementedImplementation) PrettyPrint(string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (UnimplementedImplementation) CanonicalFormat(string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// invoke calls the implementation method matching fn with the given inputs and args
+func invoke(impl CCLImplementation, fn config.CCLFunction, inputs []string, args []string) (interface{}, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no input provided for %s", fn)
+	}
+	input := inputs[0]
+
+	switch fn {
+	case config.FunctionParse:
+		return impl.Parse(input)
+	case config.FunctionParseIndented:
+		return impl.ParseIndented(input)
+	case config.FunctionFilter:
+		return impl.Filter(input)
+	case config.FunctionExtractComments:
+		return impl.ExtractComments(input)
+	case config.FunctionExpandDotted:
+		return impl.ExpandDotted(input)
+	case config.FunctionCombine:
+		if len(inputs) != 2 {
+			return nil, fmt.Errorf("%s takes 2 inputs, got %d", fn, len(inputs))
+		}
+		return impl.Combine(inputs[0], inputs[1])
+	case config.FunctionBuildHierarchy:
+		return impl.BuildHierarchy(input)
+	case config.FunctionGetString:
+		return impl.GetString(input, args)
+	case config.FunctionGetInt:
+		return impl.GetInt(input, args)
+	case config.FunctionGetBool:
+		return impl.GetBool(input, args)
+	case config.FunctionGetFloat:
+		return impl.GetFloat(input, args)
+	case config.FunctionGetList:
+		return impl.GetList(input, args)
+	case config.FunctionPrettyPrint:
+		return impl.PrettyPrint(input)
+	case config.FunctionCanonicalFormat:
+		return impl.CanonicalFormat(input)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNotSupported, fn)
+	}
+}
+
+// Status is the outcome of running a single test
+type Status string
+
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusNotRun  Status = "not_run" // Not executed, e.g. after a fail-fast cutoff
+	StatusSkipped Status = "skipped" // Quarantined by the test's skip metadata
+)
+
+// TestResult records the outcome of running one flat test against an implementation
+type TestResult struct {
+	Name        string        `json:"name"`
+	ID          string        `json:"id,omitempty"` // The test's types.TestCase.ID; results match tests by it first
+	Validation  string        `json:"validation"`
+	Features    []string      `json:"features,omitempty"`    // The test's required features
+	SourceTest  string        `json:"source_test,omitempty"` // The source test the flat test was generated from
+	Status      Status        `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	Expected    interface{}   `json:"expected,omitempty"`
+	Actual      interface{}   `json:"actual,omitempty"`
+	Duration    time.Duration `json:"duration_ns"`
+	Override    string        `json:"override,omitempty"`     // Reason an override replaced the expected outcome
+	StaleSkip   bool          `json:"stale_skip,omitempty"`   // Skipped, but run and passing after the skip's until date
+	DefaultUsed bool          `json:"default_used,omitempty"` // The key was absent and the test's default returned
+
+	ConfigFingerprint string `json:"config_fingerprint,omitempty"` // RunOptions.Config's fingerprint
+}
+
+// Passed reports whether the test passed
+func (r TestResult) Passed() bool {
+	return r.Status == StatusPass
+}
+
+// RunTest runs a single flat test against the implementation and checks the result
+func RunTest(impl CCLImplementation, test types.TestCase) TestResult {
+	return runTest(impl, test, RunOptions{})
+}
+
+// runTest runs one test, comparing results as the run options say
+func runTest(impl CCLImplementation, test types.TestCase, opts RunOptions) TestResult {
+	timeout := opts.Timeout
+	if own := test.Meta.Limits.Timeout(); own > 0 {
+		timeout = own
+	}
+
+	start := time.Now()
+	actual, defaultUsed, err := invokeWithin(impl, test, timeout)
+	duration := time.Since(start)
+
+	result := TestResult{
+		Name:       test.Name,
+		ID:         test.ID(),
+		Validation: test.Validation,
+		Features:   test.Features,
+		SourceTest: test.SourceTest,
+		Expected:   test.ExpectedValue(),
+		Duration:   duration,
+		Override:   test.Meta.Override,
+	}
+	if err == nil {
+		result.Actual = actual
+		result.DefaultUsed = defaultUsed
+	}
+
+	if errors.Is(err, errTimedOut) {
+		// A timeout fails even a test that expects an error
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("timed out after %s", timeout)
+		return result
+	}
+	if msg := checkResult(test, actual, err, opts); msg != "" {
+		result.Status = StatusFail
+		result.Message = msg
+	} else {
+		result.Status = StatusPass
+	}
+	return result
+}
+
+// errTimedOut is returned by invokeWithin when a call outlasts its timeout
+var errTimedOut = errors.New("timed out")
+
+// invokeWithin calls invokeTest, giving up with errTimedOut after timeout
+// when it is positive. The abandoned call is left to finish in the
+// background; a SubprocessImplementation also bounds each call with its
+// own Timeout.
+func invokeWithin(impl CCLImplementation, test types.TestCase, timeout time.Duration) (interface{}, bool, error) {
+	if timeout <= 0 {
+		return invokeTest(impl, test)
+	}
+
+	type outcome struct {
+		actual      interface{}
+		defaultUsed bool
+		err         error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		actual, defaultUsed, err := invokeTest(impl, test)
+		done <- outcome{actual, defaultUsed, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.actual, o.defaultUsed, o.err
+	case <-timer.C:
+		return nil, false, errTimedOut
+	}
+}
+
+// invokeTest calls the implementation for one test, through DefaultReporter
+// when the test passes a default. Implementations that do not report
+// defaults cannot run such tests and fail them as not supported.
+func invokeTest(impl CCLImplementation, test types.TestCase) (interface{}, bool, error) {
+	if test.HasDefault() && len(test.Inputs) > 0 {
+		fn := config.CCLFunction(test.Validation)
+		reporter, ok := impl.(DefaultReporter)
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %s with a default", ErrNotSupported, fn)
+		}
+		return reporter.GetWithDefault(fn, test.Inputs[0], test.Args, *test.Default)
+	}
+	actual, err := invoke(impl, config.CCLFunction(test.Validation), test.Inputs, test.Args)
+	return actual, false, err
+}
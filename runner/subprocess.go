@@ -0,0 +1,429 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Subprocess protocol
+//
+// Implementations written in other languages can be driven over stdin/stdout
+// without cgo. The harness starts the command once and exchanges one JSON
+// object per line:
+//
+//	request:  {"function":"get_string","input":"a = b","args":["a"]}
+//	combine:  {"function":"combine","input":"a = 1","inputs":["a = 1","b = 2"]}
+//	default:  {"function":"get_int","input":"a = 1","args":["port"],"default":"8080"}
+//	success:  {"ok":true,"result":"b"}
+//	fallback: {"ok":true,"result":8080,"default_used":true}
+//	failure:  {"ok":false,"error":"key not found","error_type":"missing_key"}
+//
+// Result shapes by function:
+//   - parse, parse_indented, filter, extract_comments, expand_dotted, combine: [{"key":"...","value":"..."}]
+//   - build_hierarchy: a JSON object
+//   - get_string, pretty_print, canonical_format: a string
+//   - get_int, get_float: a number
+//   - get_bool: a boolean
+//   - get_list: an array of strings
+//
+// A typed access request with "default" returns the default, converted to
+// the function's type, when a key along the path is absent, and sets
+// "default_used". A responder that does not provide a function, or does not
+// take defaults, should answer with error_type "not_supported". Responses
+// must arrive in request order.
+// If the process exits or a call times out, the process is restarted
+// before the next call.
+
+// ErrorTypeNotSupported is the protocol error_type for unsupported functions
+const ErrorTypeNotSupported = "not_supported"
+
+// DefaultSubprocessTimeout is the per-call timeout used when none is set
+const DefaultSubprocessTimeout = 10 * time.Second
+
+// SubprocessRequest is one protocol request line
+type SubprocessRequest struct {
+	Function string   `json:"function"`
+	Input    string   `json:"input"`
+	Inputs   []string `json:"inputs,omitempty"` // Every document, for functions taking more than one
+	Args     []string `json:"args,omitempty"`
+	Default  *string  `json:"default,omitempty"` // Returned for an absent key, for typed access
+}
+
+// SubprocessResponse is one protocol response line
+type SubprocessResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	ErrorType   string          `json:"error_type,omitempty"`
+	DefaultUsed bool            `json:"default_used,omitempty"` // The request's default was returned
+}
+
+// SubprocessError is an error reported by the external implementation
+type SubprocessError struct {
+	Message   string
+	ErrorType string
+}
+
+func (e *SubprocessError) Error() string {
+	if e.ErrorType != "" {
+		return e.ErrorType + ": " + e.Message
+	}
+	return e.Message
+}
+
+// Unwrap maps the not_supported error type to ErrNotSupported
+func (e *SubprocessError) Unwrap() error {
+	if e.ErrorType == ErrorTypeNotSupported {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// SubprocessImplementation drives an external CCL implementation over the
+// line-delimited JSON protocol. It is safe for concurrent use; calls are
+// serialized.
+type SubprocessImplementation struct {
+	Command []string
+	Timeout time.Duration // Per-call timeout (DefaultSubprocessTimeout if zero)
+	Stderr  io.Writer     // Receives the process's stderr (discarded if nil)
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// NewSubprocessImplementation creates an implementation backed by an external command
+func NewSubprocessImplementation(cmd []string) *SubprocessImplementation {
+	return &SubprocessImplementation{
+		Command: cmd,
+		Timeout: DefaultSubprocessTimeout,
+	}
+}
+
+// Close stops the external process if it is running
+func (s *SubprocessImplementation) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop()
+	return nil
+}
+
+// start launches the external process
+func (s *SubprocessImplementation) start() error {
+	if len(s.Command) == 0 {
+		return fmt.Errorf("no subprocess command configured")
+	}
+
+	cmd := exec.Command(s.Command[0], s.Command[1:]...)
+	cmd.Stderr = s.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open subprocess stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open subprocess stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start subprocess: %w", err)
+	}
+
+	s.cmd = cmd
+	s.stdin = stdin
+	s.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// stop kills the external process so the next call restarts it
+func (s *SubprocessImplementation) stop() {
+	if s.cmd == nil {
+		return
+	}
+	s.stdin.Close()
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	s.cmd.Wait()
+	s.cmd = nil
+	s.stdin = nil
+	s.stdout = nil
+}
+
+// call sends one single-input request and waits for its response
+func (s *SubprocessImplementation) call(fn config.CCLFunction, input string, args []string) (json.RawMessage, error) {
+	return s.send(SubprocessRequest{Function: string(fn), Input: input, Args: args})
+}
+
+// send sends one request and waits for its result
+func (s *SubprocessImplementation) send(request SubprocessRequest) (json.RawMessage, error) {
+	response, err := s.exchange(request)
+	if err != nil {
+		return nil, err
+	}
+	return response.Result, nil
+}
+
+// exchange sends one request and waits for its successful response
+func (s *SubprocessImplementation) exchange(request SubprocessRequest) (*SubprocessResponse, error) {
+	fn := request.Function
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cmd == nil {
+		if err := s.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := s.stdin.Write(append(data, '\n')); err != nil {
+		s.stop()
+		return nil, fmt.Errorf("subprocess write failed: %w", err)
+	}
+
+	type readResult struct {
+		line []byte
+		err  error
+	}
+	done := make(chan readResult, 1)
+	stdout := s.stdout
+	go func() {
+		line, err := stdout.ReadBytes('\n')
+		done <- readResult{line, err}
+	}()
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSubprocessTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var line []byte
+	select {
+	case res := <-done:
+		if res.err != nil {
+			s.stop()
+			return nil, fmt.Errorf("subprocess exited during %s: %w", fn, res.err)
+		}
+		line = res.line
+	case <-timer.C:
+		s.stop()
+		return nil, fmt.Errorf("subprocess timed out after %s during %s", timeout, fn)
+	}
+
+	var response SubprocessResponse
+	if err := json.Unmarshal(line, &response); err != nil {
+		s.stop()
+		return nil, fmt.Errorf("invalid subprocess response: %w", err)
+	}
+	if !response.OK {
+		return nil, &SubprocessError{Message: response.Error, ErrorType: response.ErrorType}
+	}
+	return &response, nil
+}
+
+// callInto sends a request and decodes the result into out
+func (s *SubprocessImplementation) callInto(fn config.CCLFunction, input string, args []string, out interface{}) error {
+	return s.sendInto(SubprocessRequest{Function: string(fn), Input: input, Args: args}, out)
+}
+
+// sendInto sends a request and decodes the result into out
+func (s *SubprocessImplementation) sendInto(request SubprocessRequest, out interface{}) error {
+	fn := request.Function
+	result, err := s.send(request)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result, out); err != nil {
+		return fmt.Errorf("invalid %s result: %w", fn, err)
+	}
+	return nil
+}
+
+func (s *SubprocessImplementation) entries(fn config.CCLFunction, input string) ([]types.Entry, error) {
+	var result []types.Entry
+	err := s.callInto(fn, input, nil, &result)
+	return result, err
+}
+
+// CCLImplementation methods forward each call over the protocol
+
+func (s *SubprocessImplementation) Parse(input string) ([]types.Entry, error) {
+	return s.entries(config.FunctionParse, input)
+}
+
+func (s *SubprocessImplementation) ParseIndented(input string) ([]types.Entry, error) {
+	return s.entries(config.FunctionParseIndented, input)
+}
+
+func (s *SubprocessImplementation) Filter(input string) ([]types.Entry, error) {
+	return s.entries(config.FunctionFilter, input)
+}
+
+func (s *SubprocessImplementation) ExtractComments(input string) ([]types.Entry, error) {
+	return s.entries(config.FunctionExtractComments, input)
+}
+
+func (s *SubprocessImplementation) ExpandDotted(input string) ([]types.Entry, error) {
+	return s.entries(config.FunctionExpandDotted, input)
+}
+
+func (s *SubprocessImplementation) BuildHierarchy(input string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := s.callInto(config.FunctionBuildHierarchy, input, nil, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) GetString(input string, args []string) (string, error) {
+	var result string
+	err := s.callInto(config.FunctionGetString, input, args, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) GetInt(input string, args []string) (int, error) {
+	var result int
+	err := s.callInto(config.FunctionGetInt, input, args, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) GetBool(input string, args []string) (bool, error) {
+	var result bool
+	err := s.callInto(config.FunctionGetBool, input, args, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) GetFloat(input string, args []string) (float64, error) {
+	var result float64
+	err := s.callInto(config.FunctionGetFloat, input, args, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) GetList(input string, args []string) ([]string, error) {
+	var result []string
+	err := s.callInto(config.FunctionGetList, input, args, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) PrettyPrint(input string) (string, error) {
+	var result string
+	err := s.callInto(config.FunctionPrettyPrint, input, nil, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) CanonicalFormat(input string) (string, error) {
+	var result string
+	err := s.callInto(config.FunctionCanonicalFormat, input, nil, &result)
+	return result, err
+}
+
+func (s *SubprocessImplementation) Combine(a, b string) ([]types.Entry, error) {
+	var result []types.Entry
+	err := s.sendInto(SubprocessRequest{Function: string(config.FunctionCombine), Input: a, Inputs: []string{a, b}}, &result)
+	return result, err
+}
+
+// GetWithDefault sends a typed access request carrying the default, making
+// the subprocess a DefaultReporter
+func (s *SubprocessImplementation) GetWithDefault(fn config.CCLFunction, input string, path []string, fallback string) (interface{}, bool, error) {
+	response, err := s.exchange(SubprocessRequest{Function: string(fn), Input: input, Args: path, Default: &fallback})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value interface{}
+	switch fn {
+	case config.FunctionGetString:
+		value, err = decodeResult[string](fn, response.Result)
+	case config.FunctionGetInt:
+		value, err = decodeResult[int](fn, response.Result)
+	case config.FunctionGetBool:
+		value, err = decodeResult[bool](fn, response.Result)
+	case config.FunctionGetFloat:
+		value, err = decodeResult[float64](fn, response.Result)
+	case config.FunctionGetList:
+		value, err = decodeResult[[]string](fn, response.Result)
+	default:
+		return nil, false, fmt.Errorf("%w: %s with a default", ErrNotSupported, fn)
+	}
+	return value, response.DefaultUsed, err
+}
+
+// decodeResult decodes a result into fn's Go type
+func decodeResult[T any](fn config.CCLFunction, result json.RawMessage) (T, error) {
+	var value T
+	if err := json.Unmarshal(result, &value); err != nil {
+		return value, fmt.Errorf("invalid %s result: %w", fn, err)
+	}
+	return value, nil
+}
+
+// ServeSubprocessProtocol answers protocol requests read from r using impl,
+// writing responses to w. It is the reference responder: a Go implementation
+// can expose itself to other harnesses by calling it from main with
+// os.Stdin and os.Stdout. It returns nil when r reaches EOF.
+func ServeSubprocessProtocol(impl CCLImplementation, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		var request SubprocessRequest
+		var response SubprocessResponse
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response = SubprocessResponse{Error: err.Error(), ErrorType: "invalid_request"}
+		} else {
+			inputs := request.Inputs
+			if len(inputs) == 0 {
+				inputs = []string{request.Input}
+			}
+			result, defaultUsed, err := serve(impl, request, inputs)
+			switch {
+			case errors.Is(err, ErrNotSupported):
+				response = SubprocessResponse{Error: err.Error(), ErrorType: ErrorTypeNotSupported}
+			case err != nil:
+				response = SubprocessResponse{Error: err.Error(), ErrorType: "error"}
+			default:
+				data, err := json.Marshal(result)
+				if err != nil {
+					response = SubprocessResponse{Error: err.Error(), ErrorType: "invalid_result"}
+				} else {
+					response = SubprocessResponse{OK: true, Result: data, DefaultUsed: defaultUsed}
+				}
+			}
+		}
+
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// serve answers one request, through DefaultReporter when it has a default
+func serve(impl CCLImplementation, request SubprocessRequest, inputs []string) (interface{}, bool, error) {
+	fn := config.CCLFunction(request.Function)
+	if request.Default != nil {
+		reporter, ok := impl.(DefaultReporter)
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %s with a default", ErrNotSupported, fn)
+		}
+		return reporter.GetWithDefault(fn, inputs[0], request.Args, *request.Default)
+	}
+	result, err := invoke(impl, fn, inputs, request.Args)
+	return result, false, err
+}
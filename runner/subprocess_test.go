@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// responderEnv makes the test binary act as an external implementation
+const responderEnv = "CCL_RUNNER_TEST_RESPONDER"
+
+// misbehavingImplementation wraps the toy implementation with inputs that
+// crash or hang the responder process
+type misbehavingImplementation struct {
+	*toyccl.Implementation
+}
+
+func (m misbehavingImplementation) Parse(input string) ([]types.Entry, error) {
+	switch input {
+	case "crash":
+		os.Exit(3)
+	case "hang":
+		time.Sleep(time.Hour)
+	}
+	return m.Implementation.Parse(input)
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(responderEnv) == "1" {
+		impl := misbehavingImplementation{toyccl.New()}
+		if err := ServeSubprocessProtocol(impl, os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestSubprocess(t *testing.T) *SubprocessImplementation {
+	t.Helper()
+	t.Setenv(responderEnv, "1")
+	impl := NewSubprocessImplementation([]string{os.Args[0]})
+	t.Cleanup(func() { impl.Close() })
+	return impl
+}
+
+func TestSubprocessImplementation_ScoresCorpus(t *testing.T) {
+	subprocess := newTestSubprocess(t)
+	direct := toyccl.New()
+
+	for _, test := range createProbeCorpus() {
+		viaSubprocess := RunTest(subprocess, test)
+		viaDirect := RunTest(direct, test)
+		if viaSubprocess.Status != viaDirect.Status {
+			t.Errorf("%s: subprocess %s (%s), direct %s (%s)", test.Name,
+				viaSubprocess.Status, viaSubprocess.Message, viaDirect.Status, viaDirect.Message)
+		}
+	}
+}
+
+func TestSubprocessImplementation_ErrorResponse(t *testing.T) {
+	impl := newTestSubprocess(t)
+
+	_, err := impl.GetString("a = b", []string{"missing"})
+	var subErr *SubprocessError
+	if !errors.As(err, &subErr) {
+		t.Fatalf("Expected SubprocessError, got %v", err)
+	}
+	if subErr.ErrorType != "error" || !strings.Contains(subErr.Message, "missing") {
+		t.Errorf("Unexpected error contents: %+v", subErr)
+	}
+}
+
+func TestRun_ErrorType(t *testing.T) {
+	tests := []types.TestCase{
+		{Name: "any_error", Inputs: []string{"no equals"}, Validation: "parse", ExpectError: true},
+		{Name: "matching_type", Inputs: []string{"no equals"}, Validation: "parse", ExpectError: true, ErrorType: "error"},
+		{Name: "other_type", Inputs: []string{"no equals"}, Validation: "parse", ExpectError: true, ErrorType: "syntax"},
+	}
+
+	want := map[string]Status{"any_error": StatusPass, "matching_type": StatusPass, "other_type": StatusFail}
+	for _, result := range Run(newTestSubprocess(t), tests, RunOptions{}).Results {
+		if result.Status != want[result.Name] {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, want[result.Name], result.Status, result.Message)
+		}
+		if result.Name == "other_type" && !strings.Contains(result.Message, "expected a syntax error") {
+			t.Errorf("Expected the error types in the message, got %q", result.Message)
+		}
+	}
+
+	// Without a reported type, any error matches
+	if result := RunTest(toyccl.New(), tests[2]); result.Status != StatusPass {
+		t.Errorf("Expected an untyped error to match, got %s (%s)", result.Status, result.Message)
+	}
+}
+
+func TestSubprocessImplementation_NotSupported(t *testing.T) {
+	impl := newTestSubprocess(t)
+
+	// The responder's invoke has no mapping for unknown functions
+	_, err := impl.call("extract_nothing", "a = b", nil)
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestSubprocessImplementation_DefaultValues(t *testing.T) {
+	input := []string{"port = 8080\nserver =\n  port = 80"}
+	fallback := func(value string) *string { return &value }
+	tests := []types.TestCase{
+		{Name: "present_key", Inputs: input, Validation: "get_int", Args: []string{"port"}, Default: fallback("80"), Expected: 8080},
+		{Name: "absent_key", Inputs: input, Validation: "get_int", Args: []string{"timeout"}, Default: fallback("30"), Expected: 30},
+		{Name: "nested_absent_key", Inputs: input, Validation: "get_string", Args: []string{"server", "host"}, Default: fallback("localhost"), Expected: "localhost"},
+		{Name: "absent_list", Inputs: input, Validation: "get_list", Args: []string{"hosts"}, Default: fallback("localhost"), Expected: []interface{}{"localhost"}},
+	}
+
+	subprocess := newTestSubprocess(t)
+	for _, test := range tests {
+		viaSubprocess := RunTest(subprocess, test)
+		viaDirect := RunTest(toyccl.New(), test)
+		if viaSubprocess.Status != StatusPass || viaSubprocess.DefaultUsed != viaDirect.DefaultUsed {
+			t.Errorf("%s: subprocess %s with DefaultUsed %t (%s), direct DefaultUsed %t", test.Name,
+				viaSubprocess.Status, viaSubprocess.DefaultUsed, viaSubprocess.Message, viaDirect.DefaultUsed)
+		}
+	}
+}
+
+func TestServeSubprocessProtocol_DefaultWithoutReporter(t *testing.T) {
+	var out strings.Builder
+	request := `{"function":"get_string","input":"a = b","args":["a"],"default":"c"}` + "\n"
+	if err := ServeSubprocessProtocol(plainImplementation{toyccl.New()}, strings.NewReader(request), &out); err != nil {
+		t.Fatalf("ServeSubprocessProtocol failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"error_type":"not_supported"`) {
+		t.Errorf("Expected not_supported response, got %s", out.String())
+	}
+}
+
+func TestSubprocessImplementation_RestartsAfterCrash(t *testing.T) {
+	impl := newTestSubprocess(t)
+
+	if _, err := impl.Parse("crash"); err == nil {
+		t.Fatal("Expected error when the subprocess crashes")
+	}
+
+	entries, err := impl.Parse("a = b")
+	if err != nil {
+		t.Fatalf("Expected restarted subprocess to answer, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].Key != "a" {
+		t.Errorf("Unexpected entries after restart: %v", entries)
+	}
+}
+
+func TestSubprocessImplementation_Timeout(t *testing.T) {
+	impl := newTestSubprocess(t)
+	impl.Timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := impl.Parse("hang")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Timeout took too long: %s", elapsed)
+	}
+
+	if _, err := impl.Parse("a = b"); err != nil {
+		t.Errorf("Expected restarted subprocess to answer after timeout, got %v", err)
+	}
+}
+
+func TestServeSubprocessProtocol_InvalidRequest(t *testing.T) {
+	var out strings.Builder
+	if err := ServeSubprocessProtocol(toyccl.New(), strings.NewReader("not json\n"), &out); err != nil {
+		t.Fatalf("ServeSubprocessProtocol failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"error_type":"invalid_request"`) {
+		t.Errorf("Expected invalid_request response, got %s", out.String())
+	}
+}
@@ -0,0 +1,452 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// UpdateExpectedOptions configures rewriting expected values from actual results.
+// Only failing tests whose validation is in the allowlist are updated.
+type UpdateExpectedOptions struct {
+	SourceDir   string   // Directory of compact source files to rewrite
+	Validations []string // Allowlist of validations; nothing is updated when empty
+}
+
+// ExpectationUpdate records one rewritten expected value
+type ExpectationUpdate struct {
+	File       string      `json:"file"`
+	Suite      string      `json:"suite,omitempty"` // Suite within a multi-suite file
+	Test       string      `json:"test"`
+	Validation string      `json:"validation"`
+	Old        interface{} `json:"old"`
+	New        interface{} `json:"new"`
+}
+
+// SkippedUpdate records a failing test that was deliberately not updated
+type SkippedUpdate struct {
+	Test   string `json:"test"`
+	Reason string `json:"reason"`
+}
+
+// UpdateSummary lists every change made by an expected-value update
+type UpdateSummary struct {
+	Updated []ExpectationUpdate `json:"updated"`
+	Skipped []SkippedUpdate     `json:"skipped,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+// String renders the summary for review
+func (s *UpdateSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "updated %d expected values", len(s.Updated))
+	for _, update := range s.Updated {
+		test := update.Test
+		if update.Suite != "" {
+			test = update.Suite + "/" + test
+		}
+		fmt.Fprintf(&b, "\n  %s: %s (%s)\n    - %s\n    + %s", update.File, test, update.Validation,
+			truncateValue(formatValue(update.Old), DiffMaxValueLength), truncateValue(formatValue(update.New), DiffMaxValueLength))
+	}
+	if len(s.Skipped) > 0 {
+		fmt.Fprintf(&b, "\nskipped %d failing tests", len(s.Skipped))
+		for _, skipped := range s.Skipped {
+			fmt.Fprintf(&b, "\n  %s: %s", skipped.Test, skipped.Reason)
+		}
+	}
+	if s.Error != "" {
+		fmt.Fprintf(&b, "\nerror: %s", s.Error)
+	}
+	return b.String()
+}
+
+// pendingUpdate is an allowed update waiting to be matched to a source validation
+type pendingUpdate struct {
+	test   types.TestCase
+	actual interface{}
+	done   bool
+}
+
+// UpdateExpected writes the actual results of failing tests back into the
+// compact source files as their new expected values. Error-expectation tests
+// and validations outside the allowlist are never updated. Only the expect
+// values are replaced; the rest of each file is left byte-for-byte intact.
+func UpdateExpected(tests []types.TestCase, results *RunResults, opts UpdateExpectedOptions) (*UpdateSummary, error) {
+	summary := &UpdateSummary{Updated: []ExpectationUpdate{}}
+
+	allowed := make(map[string]bool)
+	for _, validation := range opts.Validations {
+		allowed[validation] = true
+	}
+	// Results match tests by name and ID, which tells apart same-named
+	// tests of different suites, or else by name alone. Identical tests of
+	// several suites all match.
+	testsByID := make(map[string][]types.TestCase)
+	testsByName := make(map[string][]types.TestCase)
+	for _, test := range tests {
+		key := test.Name + "\x00" + test.ID()
+		testsByID[key] = append(testsByID[key], test)
+		testsByName[test.Name] = append(testsByName[test.Name], test)
+	}
+
+	// Collect allowed updates keyed by source test and validation
+	pending := make(map[string]*pendingUpdate)
+	for _, result := range results.Results {
+		if result.Status != StatusFail {
+			continue
+		}
+		matches, ok := testsByID[result.Name+"\x00"+result.ID]
+		if !ok {
+			matches, ok = testsByName[result.Name]
+		}
+		var test types.TestCase
+		if ok {
+			test = matches[0]
+		}
+		switch {
+		case !ok:
+			summary.Skipped = append(summary.Skipped, SkippedUpdate{Test: result.Name, Reason: "test not in run"})
+		case !allowed[test.Validation]:
+			summary.Skipped = append(summary.Skipped, SkippedUpdate{Test: result.Name, Reason: "validation not in allowlist"})
+		case test.ExpectError:
+			summary.Skipped = append(summary.Skipped, SkippedUpdate{Test: result.Name, Reason: "error expectation"})
+		case result.Actual == nil:
+			summary.Skipped = append(summary.Skipped, SkippedUpdate{Test: result.Name, Reason: "no actual value"})
+		default:
+			for _, test := range matches {
+				pending[sourceKey(test.Suite, sourceTestName(test), test.Validation)] = &pendingUpdate{test: test, actual: result.Actual}
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return summary, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(opts.SourceDir, "*.json"))
+	if err != nil {
+		return summary, fmt.Errorf("failed to list source files: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		updates, skipped, err := updateSourceFile(file, pending)
+		if err != nil {
+			return summary, err
+		}
+		summary.Updated = append(summary.Updated, updates...)
+		summary.Skipped = append(summary.Skipped, skipped...)
+	}
+
+	var unmatched []string
+	for _, p := range pending {
+		if !p.done {
+			unmatched = append(unmatched, p.test.Name)
+		}
+	}
+	sort.Strings(unmatched)
+	for _, name := range unmatched {
+		summary.Skipped = append(summary.Skipped, SkippedUpdate{Test: name, Reason: "source validation not found"})
+	}
+
+	return summary, nil
+}
+
+// sourceTestName returns the compact test a flat test was generated from
+func sourceTestName(test types.TestCase) string {
+	if test.SourceTest != "" {
+		return test.SourceTest
+	}
+	return strings.TrimSuffix(test.Name, "_"+test.Validation)
+}
+
+func sourceKey(suite, testName, validation string) string {
+	return suite + "\x00" + testName + "\x00" + validation
+}
+
+// updateSourceFile rewrites matching expect values in one compact source file
+func updateSourceFile(path string, pending map[string]*pendingUpdate) ([]ExpectationUpdate, []SkippedUpdate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read source file: %w", err)
+	}
+
+	spans, err := locateExpectations(data)
+	if err != nil {
+		// Not a compact source file; leave it alone
+		return nil, nil, nil
+	}
+
+	type replacement struct {
+		start, end int
+		value      []byte
+	}
+	var replacements []replacement
+	var updates []ExpectationUpdate
+	var skipped []SkippedUpdate
+
+	for _, span := range spans {
+		p, ok := pending[sourceKey(span.suite, span.test, span.function)]
+		if !ok || p.done {
+			continue
+		}
+		p.done = true
+		// The flat test may not carry the source's error flag, so check here too
+		if span.isError {
+			skipped = append(skipped, SkippedUpdate{Test: p.test.Name, Reason: "error expectation"})
+			continue
+		}
+
+		var old interface{}
+		if err := json.Unmarshal(data[span.start:span.end], &old); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode expect value in %s: %w", path, err)
+		}
+		value, err := encodeExpectValue(p.actual, lineIndent(data, span.keyStart))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode new value for %s: %w", p.test.Name, err)
+		}
+		newValue, err := normalizeValue(p.actual)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode new value for %s: %w", p.test.Name, err)
+		}
+
+		replacements = append(replacements, replacement{span.start, span.end, value})
+		updates = append(updates, ExpectationUpdate{
+			File:       filepath.Base(path),
+			Suite:      span.suite,
+			Test:       span.test,
+			Validation: span.function,
+			Old:        old,
+			New:        newValue,
+		})
+	}
+	if len(replacements) == 0 {
+		return nil, skipped, nil
+	}
+
+	// Splice from the end so earlier offsets stay valid
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	for _, r := range replacements {
+		data = append(data[:r.start:r.start], append(r.value, data[r.end:]...)...)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to stat source file: %w", err)
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return nil, nil, fmt.Errorf("failed to write source file: %w", err)
+	}
+	return updates, skipped, nil
+}
+
+// expectSpan locates one validation's expect value within a compact source file
+type expectSpan struct {
+	suite    string // Suite name within a multi-suite file
+	test     string
+	function string
+	isError  bool
+	keyStart int // Offset of the "expect" key, used to match indentation
+	start    int
+	end      int
+}
+
+// sourceTests is the decoded shape of a compact source file's tests array
+type sourceTests []struct {
+	Name  string `json:"name"`
+	Tests []struct {
+		Function string `json:"function"`
+		Error    bool   `json:"error"`
+	} `json:"tests"`
+}
+
+// locateExpectations scans a compact source file, single- or multi-suite,
+// and returns the byte span of every validation's expect value
+func locateExpectations(data []byte) ([]expectSpan, error) {
+	var file struct {
+		Tests  sourceTests `json:"tests"`
+		Suites []struct {
+			Suite string      `json:"suite"`
+			Tests sourceTests `json:"tests"`
+		} `json:"suites"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	// Walk the token stream in lockstep with the decoded structure to record offsets
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var spans []expectSpan
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "tests":
+			if spans, err = locateTests(dec, data, "", file.Tests, spans); err != nil {
+				return nil, err
+			}
+		case "suites":
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for si := 0; dec.More(); si++ {
+				if err := expectDelim(dec, '{'); err != nil {
+					return nil, err
+				}
+				for dec.More() {
+					key, err := dec.Token()
+					if err != nil {
+						return nil, err
+					}
+					if key != "tests" {
+						if err := skipValue(dec); err != nil {
+							return nil, err
+						}
+						continue
+					}
+					suite := file.Suites[si]
+					if spans, err = locateTests(dec, data, suite.Suite, suite.Tests, spans); err != nil {
+						return nil, err
+					}
+				}
+				if err := expectDelim(dec, '}'); err != nil {
+					return nil, err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return spans, nil
+}
+
+// locateTests reads one tests array from dec, appending the span of each
+// validation's expect value to spans
+func locateTests(dec *json.Decoder, data []byte, suite string, tests sourceTests, spans []expectSpan) ([]expectSpan, error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, err
+	}
+	for ti := 0; dec.More(); ti++ {
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if key != "tests" {
+				if err := skipValue(dec); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for vi := 0; dec.More(); vi++ {
+				if err := expectDelim(dec, '{'); err != nil {
+					return nil, err
+				}
+				validation := tests[ti].Tests[vi]
+				for dec.More() {
+					keyEnd := int(dec.InputOffset())
+					key, err := dec.Token()
+					if err != nil {
+						return nil, err
+					}
+					if key != "expect" {
+						if err := skipValue(dec); err != nil {
+							return nil, err
+						}
+						continue
+					}
+					keyStart := bytes.IndexByte(data[keyEnd:], '"') + keyEnd
+					var raw json.RawMessage
+					if err := dec.Decode(&raw); err != nil {
+						return nil, err
+					}
+					end := int(dec.InputOffset())
+					spans = append(spans, expectSpan{
+						suite:    suite,
+						test:     tests[ti].Name,
+						function: validation.Function,
+						isError:  validation.Error,
+						keyStart: keyStart,
+						start:    end - len(raw),
+						end:      end,
+					})
+				}
+				if err := expectDelim(dec, '}'); err != nil {
+					return nil, err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, err
+	}
+	return spans, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
+
+// lineIndent returns the leading whitespace of the line containing offset
+func lineIndent(data []byte, offset int) string {
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := lineStart
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[lineStart:end])
+}
+
+// encodeExpectValue renders a value in the repo's indented JSON style,
+// continuing lines at the given indentation
+func encodeExpectValue(value interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(indent, "  ")
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,260 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/internal/toyccl"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// shoutingImplementation changes the toy's behavior: parsed values are
+// upper-cased and booleans are lenient
+type shoutingImplementation struct {
+	*toyccl.Implementation
+}
+
+func (s shoutingImplementation) Parse(input string) ([]types.Entry, error) {
+	entries, err := s.Implementation.Parse(input)
+	for i := range entries {
+		entries[i].Value = strings.ToUpper(entries[i].Value)
+	}
+	return entries, err
+}
+
+func (s shoutingImplementation) GetBool(input string, args []string) (bool, error) {
+	return true, nil
+}
+
+const updateSource = `{
+  "$schema": "../schema.json",
+  "tests": [
+    {
+      "name": "basic",
+      "inputs": ["a = b\nc = d"],
+      "tests": [
+        {
+          "function": "parse",
+          "expect": [{"key": "a", "value": "b"}, {"key": "c", "value": "d"}]
+        },
+        {"function": "get_string", "args": ["a"], "expect": "b"},
+        {"function": "build_hierarchy", "expect": {"a": "z"}}
+      ],
+      "features": ["whitespace"]
+    },
+    {
+      "name": "strict_bool",
+      "inputs": ["a = yes"],
+      "tests": [
+        {"function": "get_bool", "args": ["a"], "expect": null, "error": true}
+      ],
+      "behaviors": ["boolean_strict"]
+    }
+  ]
+}
+`
+
+const updatedSource = `{
+  "$schema": "../schema.json",
+  "tests": [
+    {
+      "name": "basic",
+      "inputs": ["a = b\nc = d"],
+      "tests": [
+        {
+          "function": "parse",
+          "expect": [
+            {
+              "key": "a",
+              "value": "B"
+            },
+            {
+              "key": "c",
+              "value": "D"
+            }
+          ]
+        },
+        {"function": "get_string", "args": ["a"], "expect": "b"},
+        {"function": "build_hierarchy", "expect": {"a": "z"}}
+      ],
+      "features": ["whitespace"]
+    },
+    {
+      "name": "strict_bool",
+      "inputs": ["a = yes"],
+      "tests": [
+        {"function": "get_bool", "args": ["a"], "expect": null, "error": true}
+      ],
+      "behaviors": ["boolean_strict"]
+    }
+  ]
+}
+`
+
+// loadFlatSource loads a compact source file and flattens it the way the generator does
+func loadFlatSource(t *testing.T, path string) []types.TestCase {
+	t.Helper()
+	suite, err := loader.NewTestLoader("", config.ImplementationConfig{}).LoadTestFile(path, loader.LoadOptions{
+		Format:     loader.FormatCompact,
+		FilterMode: loader.FilterAll,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load source: %v", err)
+	}
+
+	gen := generator.NewFlatGenerator("", "", generator.GenerateOptions{})
+	var flat []types.TestCase
+	for _, test := range suite.Tests {
+		tests, err := gen.TransformSourceToFlat(test)
+		if err != nil {
+			t.Fatalf("Failed to flatten %s: %v", test.Name, err)
+		}
+		flat = append(flat, tests...)
+	}
+	return flat
+}
+
+func TestRun_UpdateExpectedRewritesSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api_basic.json")
+	if err := os.WriteFile(path, []byte(updateSource), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	tests := loadFlatSource(t, path)
+	results := Run(shoutingImplementation{toyccl.New()}, tests, RunOptions{
+		UpdateExpected: &UpdateExpectedOptions{
+			SourceDir:   dir,
+			Validations: []string{"parse", "get_bool"},
+		},
+	})
+
+	summary := results.Update
+	if summary == nil {
+		t.Fatal("Expected an update summary")
+	}
+	if summary.Error != "" {
+		t.Fatalf("Update failed: %s", summary.Error)
+	}
+	if len(summary.Updated) != 1 {
+		t.Fatalf("Expected 1 update, got %d:\n%s", len(summary.Updated), summary)
+	}
+	update := summary.Updated[0]
+	if update.File != "api_basic.json" || update.Test != "basic" || update.Validation != "parse" {
+		t.Errorf("Unexpected update: %+v", update)
+	}
+
+	reasons := make(map[string]string)
+	for _, skipped := range summary.Skipped {
+		reasons[skipped.Test] = skipped.Reason
+	}
+	if reasons["strict_bool_get_bool"] != "error expectation" {
+		t.Errorf("Expected error test to be refused, got %q", reasons["strict_bool_get_bool"])
+	}
+	if reasons["basic_build_hierarchy"] != "validation not in allowlist" {
+		t.Errorf("Expected build_hierarchy to be refused, got %q", reasons["basic_build_hierarchy"])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rewritten source: %v", err)
+	}
+	if string(data) != updatedSource {
+		t.Errorf("Rewritten source mismatch\n--- got ---\n%s\n--- want ---\n%s", data, updatedSource)
+	}
+
+	// The updated expectation now passes
+	rerun := Run(shoutingImplementation{toyccl.New()}, loadFlatSource(t, path), RunOptions{})
+	for _, result := range rerun.Results {
+		if result.Name == "basic_parse" && !result.Passed() {
+			t.Errorf("Expected basic_parse to pass after update: %s", result.Message)
+		}
+	}
+}
+
+func TestUpdateExpected_EmptyAllowlistUpdatesNothing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api_basic.json")
+	if err := os.WriteFile(path, []byte(updateSource), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	tests := loadFlatSource(t, path)
+	results := Run(shoutingImplementation{toyccl.New()}, tests, RunOptions{})
+	summary, err := UpdateExpected(tests, results, UpdateExpectedOptions{SourceDir: dir})
+	if err != nil {
+		t.Fatalf("UpdateExpected failed: %v", err)
+	}
+	if len(summary.Updated) != 0 {
+		t.Errorf("Expected no updates, got %d", len(summary.Updated))
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != updateSource {
+		t.Error("Expected source file to be untouched")
+	}
+}
+
+const multiSuiteUpdateSource = `{
+  "$schema": "../schema.json",
+  "suites": [
+    {
+      "suite": "pairs",
+      "tests": [
+        {"name": "basic", "inputs": ["a = b"], "tests": [{"function": "parse", "expect": [{"key": "a", "value": "b"}]}]}
+      ]
+    },
+    {
+      "tests": [
+        {"name": "basic", "inputs": ["c = d"], "tests": [{"function": "parse", "expect": [{"key": "c", "value": "d"}]}]},
+        {"name": "same", "inputs": ["e = f"], "tests": [{"function": "parse", "expect": [{"key": "e", "value": "f"}]}]}
+      ],
+      "suite": "more_pairs"
+    },
+    {
+      "suite": "copy",
+      "tests": [
+        {"name": "same", "inputs": ["e = f"], "tests": [{"function": "parse", "expect": [{"key": "e", "value": "f"}]}]}
+      ]
+    }
+  ]
+}
+`
+
+func TestUpdateExpected_MultiSuite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "suites.json")
+	if err := os.WriteFile(path, []byte(multiSuiteUpdateSource), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	tests := loadFlatSource(t, path)
+	results := Run(shoutingImplementation{toyccl.New()}, tests, RunOptions{})
+	summary, err := UpdateExpected(tests, results, UpdateExpectedOptions{SourceDir: dir, Validations: []string{"parse"}})
+	if err != nil {
+		t.Fatalf("UpdateExpected failed: %v", err)
+	}
+	if len(summary.Skipped) != 0 {
+		t.Errorf("Expected every suite updated, got skips:\n%s", summary)
+	}
+
+	var updated []string
+	for _, update := range summary.Updated {
+		updated = append(updated, update.Suite+"/"+update.Test)
+	}
+	sort.Strings(updated)
+	if want := []string{"copy/same", "more_pairs/basic", "more_pairs/same", "pairs/basic"}; !equalStrings(updated, want) {
+		t.Errorf("Expected updates %v, got %v", want, updated)
+	}
+
+	rerun := Run(shoutingImplementation{toyccl.New()}, loadFlatSource(t, path), RunOptions{})
+	if rerun.Failed != 0 || rerun.Passed != 4 {
+		t.Errorf("Expected every suite to pass after update, got %d passed and %d failed", rerun.Passed, rerun.Failed)
+	}
+}
@@ -0,0 +1,53 @@
+package ccl_test_lib
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// schemaFiles are the schemas the types in types/generated were built from
+//
+//go:embed schemas/source-format.json schemas/generated-format.json
+var schemaFiles embed.FS
+
+// SchemaNames lists the schema files the library's types depend on
+var SchemaNames = []string{"source-format.json", "generated-format.json"}
+
+// SchemaDrift compares the schemas in dir with the ones this library was
+// built from and describes each that is missing or differs. Formatting and
+// key order are ignored.
+func SchemaDrift(dir string) ([]string, error) {
+	var drift []string
+	for _, name := range SchemaNames {
+		embedded, err := schemaFiles.ReadFile("schemas/" + name)
+		if err != nil {
+			return nil, err
+		}
+		local, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			drift = append(drift, name+": missing")
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+
+		var want, got interface{}
+		if err := json.Unmarshal(embedded, &want); err != nil {
+			return nil, fmt.Errorf("embedded %s: %w", name, err)
+		}
+		if err := json.Unmarshal(local, &got); err != nil {
+			drift = append(drift, fmt.Sprintf("%s: invalid JSON: %v", name, err))
+			continue
+		}
+		if !reflect.DeepEqual(want, got) {
+			drift = append(drift, name+": differs from the schema ccl-test-lib "+Version+" was built from")
+		}
+	}
+	return drift, nil
+}
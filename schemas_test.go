@@ -0,0 +1,28 @@
+package ccl_test_lib
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaDrift(t *testing.T) {
+	if drift, err := SchemaDrift("schemas"); err != nil || len(drift) != 0 {
+		t.Fatalf("Expected the repository schemas to match, got %v, %v", drift, err)
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "source-format.json"), []byte(`{"type": "object"}`), 0644)
+	drift, err := SchemaDrift(dir)
+	if err != nil {
+		t.Fatalf("SchemaDrift failed: %v", err)
+	}
+	want := []string{
+		"source-format.json: differs from the schema ccl-test-lib " + Version + " was built from",
+		"generated-format.json: missing",
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("Expected %v, got %v", want, drift)
+	}
+}
@@ -0,0 +1,129 @@
+package ccl_test_lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// StatsCacheFile is written next to generated_tests by WriteStatsCache
+const StatsCacheFile = "stats-cache.json"
+
+// StatsCache holds the capability metadata of every generated test, enough
+// for GetTestStats to compute statistics for any config without parsing
+// the test files again
+type StatsCache struct {
+	LibraryVersion string           `json:"library_version"`
+	Fingerprint    string           `json:"fingerprint"` // generated_tests the cache was built from
+	Tests          []types.TestCase `json:"tests"`       // Metadata only: no inputs or expectations
+}
+
+// WriteStatsCache records the metadata of the tests in
+// testDataPath/generated_tests in testDataPath/StatsCacheFile. GetTestStats
+// uses the cache until a generated file changes. Errors are as for
+// GetTestStats.
+func WriteStatsCache(testDataPath string) (*StatsCache, error) {
+	fingerprint, err := statsFingerprint(testDataPath)
+	if err != nil {
+		return nil, err
+	}
+	tests, err := loadTests(NewLoader(testDataPath, config.ImplementationConfig{}), statsOptions(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &StatsCache{LibraryVersion: Version, Fingerprint: fingerprint, Tests: make([]types.TestCase, len(tests))}
+	for i, test := range tests {
+		cache.Tests[i] = types.TestCase{
+			Name:       test.Name,
+			Suite:      test.Suite,
+			Validation: test.Validation,
+			Skip:       test.Skip,
+			Functions:  test.Functions,
+			Features:   test.Features,
+			Behaviors:  test.Behaviors,
+			Variants:   test.Variants,
+			Conflicts:  test.Conflicts,
+			Meta: types.TestMetadata{
+				Level:         test.Meta.Level,
+				Flattened:     test.Meta.Flattened,
+				Assertions:    test.Meta.Assertions,
+				ExpectedBytes: test.ExpectedBytes(),
+
+				UnknownMetadata: test.Meta.UnknownMetadata,
+			},
+		}
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stats cache: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDataPath, StatsCacheFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write stats cache: %w", err)
+	}
+	return cache, nil
+}
+
+// cachedStatsTests returns the cached tests if the cache in testDataPath
+// is current and o loads every flat test unchanged. Any problem with the
+// cache means it is not used.
+func cachedStatsTests(testDataPath string, o options) ([]types.TestCase, bool) {
+	if !statsCacheApplies(o) {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(testDataPath, StatsCacheFile))
+	if err != nil {
+		return nil, false
+	}
+	var cache StatsCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.LibraryVersion != Version {
+		return nil, false
+	}
+	fingerprint, err := statsFingerprint(testDataPath)
+	if err != nil || fingerprint != cache.Fingerprint {
+		return nil, false
+	}
+	return cache.Tests, true
+}
+
+// statsCacheApplies reports whether o loads the tests a cache was built
+// from: every flat test with the default scan and no filters or rewrites
+func statsCacheApplies(o options) bool {
+	load := o.load
+	if load.Format != loader.FormatFlat || load.FilterMode != loader.FilterAll || o.sourceFallback {
+		return false
+	}
+	load.Format, load.FilterMode, load.LazyExpected, load.Progress = 0, 0, false, nil
+	return reflect.ValueOf(load).IsZero()
+}
+
+// statsOptions are the options GetTestStats loads tests with
+func statsOptions(opts []Option) options {
+	defaults := defaultOptions()
+	defaults.load.FilterMode = loader.FilterAll
+	defaults.load.LazyExpected = true // Statistics never read Expected
+	return applyOptions(defaults, opts)
+}
+
+// statsFingerprint hashes the generated test files a stats cache covers
+func statsFingerprint(testDataPath string) (string, error) {
+	dir := testLayout(testDataPath).Dir(loader.FormatFlat)
+	files, err := loader.ScanDir(dir, loader.DefaultScanOptions())
+	if err != nil {
+		return "", noTestData(err)
+	}
+	hash := sha256.New()
+	if err := hashFiles(hash, files); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,118 @@
+package ccl_test_lib
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// setupStatsCacheTestData builds flat tests covering everything statistics
+// count: functions, features, behaviors, variants, conflicts, skips, suites
+func setupStatsCacheTestData(t *testing.T) string {
+	t.Helper()
+	return testsupport.NewFixture(t).
+		WithFlatFile("stats.json",
+			types.TestCase{Name: "a_parse", Inputs: []string{"a = b"}, Validation: "parse", Expected: map[string]interface{}{"count": 1}, Functions: []string{"parse"}, Features: []string{"comments"}},
+			types.TestCase{Name: "a_get_string", Inputs: []string{"a = b"}, Validation: "get_string", Expected: "b", Args: []string{"a"}, Functions: []string{"get_string"}, Suite: "typed"},
+			types.TestCase{Name: "b_hierarchy", Inputs: []string{"a = 1"}, Validation: "build_hierarchy", Expected: map[string]interface{}{"a": "1"}, Behaviors: []string{"crlf_normalize_to_lf"}},
+			types.TestCase{Name: "c_conflict", Inputs: []string{"a = 1"}, Validation: "parse", Expected: map[string]interface{}{"count": 1}, Conflicts: &types.ConflictSet{Behaviors: []string{"crlf_preserve_literal"}}, Variants: []string{"reference_compliant"}},
+			types.TestCase{Name: "d_skipped", Inputs: []string{"a = 1"}, Validation: "get_int", Expected: 1, Args: []string{"a"}, Skip: &types.SkipInfo{Reason: "disputed"}, Meta: types.TestMetadata{Level: 3}},
+		).
+		Build()
+}
+
+func TestGetTestStats_Cache(t *testing.T) {
+	testDataPath := setupStatsCacheTestData(t)
+	configs := []config.ImplementationConfig{
+		{Name: "parse-only", SupportedFunctions: []config.CCLFunction{config.FunctionParse}},
+		{
+			Name:               "typed",
+			SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionGetString, config.FunctionGetInt},
+			SupportedFeatures:  []config.CCLFeature{config.FeatureComments},
+			BehaviorChoices:    []config.CCLBehavior{config.BehaviorCRLFPreserve},
+		},
+		{
+			Name:               "hierarchy",
+			SupportedFunctions: []config.CCLFunction{config.FunctionParse, config.FunctionBuildHierarchy},
+			BehaviorChoices:    []config.CCLBehavior{config.BehaviorCRLFNormalize},
+			VariantChoice:      config.VariantReference,
+		},
+	}
+
+	uncached := make([]types.TestStatistics, len(configs))
+	for i, cfg := range configs {
+		stats, err := GetTestStats(testDataPath, cfg)
+		if err != nil {
+			t.Fatalf("GetTestStats failed: %v", err)
+		}
+		uncached[i] = stats
+	}
+	if largest := uncached[0].LargestExpected; len(largest) != 5 || largest[0].Test != "a_parse" || largest[4].Test != "d_skipped" {
+		t.Errorf("Expected the largest expected values of all 5 tests, a_parse first, got %+v", largest)
+	}
+
+	cache, err := WriteStatsCache(testDataPath)
+	if err != nil {
+		t.Fatalf("WriteStatsCache failed: %v", err)
+	}
+	if len(cache.Tests) != 5 || cache.Tests[0].Inputs != nil || cache.Tests[0].Expected != nil {
+		t.Errorf("Expected metadata for 5 tests without bodies, got %+v", cache.Tests)
+	}
+	for i, cfg := range configs {
+		if _, ok := cachedStatsTests(testDataPath, statsOptions(nil)); !ok {
+			t.Fatal("Expected the cache to be used")
+		}
+		stats, err := GetTestStats(testDataPath, cfg)
+		if err != nil {
+			t.Fatalf("GetTestStats failed: %v", err)
+		}
+		if !reflect.DeepEqual(stats, uncached[i]) {
+			t.Errorf("%s: cached statistics differ.\nGot:  %+v\nWant: %+v", cfg.Name, stats, uncached[i])
+		}
+	}
+
+	// Options that change the loaded tests bypass the cache
+	if _, ok := cachedStatsTests(testDataPath, statsOptions([]Option{WithInputPredicates(loader.MinLength(1))})); ok {
+		t.Error("Expected input predicates to bypass the cache")
+	}
+	if _, ok := cachedStatsTests(testDataPath, statsOptions([]Option{WithProgress(func(string, int, int) {})})); !ok {
+		t.Error("Expected progress reporting to keep the cache")
+	}
+}
+
+func TestGetTestStats_StaleCache(t *testing.T) {
+	testDataPath := setupStatsCacheTestData(t)
+	cfg := config.ImplementationConfig{Name: "parse-only", SupportedFunctions: []config.CCLFunction{config.FunctionParse}}
+	if _, err := WriteStatsCache(testDataPath); err != nil {
+		t.Fatalf("WriteStatsCache failed: %v", err)
+	}
+
+	extra := `{"tests": [{"name": "e_parse", "inputs": ["x = y"], "validation": "parse", "expected": {"count": 1}}]}`
+	if err := os.WriteFile(filepath.Join(testDataPath, "generated_tests", "extra.json"), []byte(extra), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, ok := cachedStatsTests(testDataPath, statsOptions(nil)); ok {
+		t.Error("Expected a changed generated_tests to invalidate the cache")
+	}
+	stats, err := GetTestStats(testDataPath, cfg)
+	if err != nil {
+		t.Fatalf("GetTestStats failed: %v", err)
+	}
+	if stats.TotalTests != 6 || stats.CompatibleTests != 1 {
+		t.Errorf("Expected statistics from the files, got %d tests, %d compatible", stats.TotalTests, stats.CompatibleTests)
+	}
+
+	// An unreadable cache is ignored too
+	if err := os.WriteFile(filepath.Join(testDataPath, StatsCacheFile), []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt cache: %v", err)
+	}
+	if again, err := GetTestStats(testDataPath, cfg); err != nil || !reflect.DeepEqual(again, stats) {
+		t.Errorf("Expected a corrupt cache to fall back, got %+v, %v", again, err)
+	}
+}
@@ -0,0 +1,211 @@
+// Package testsupport builds throwaway ccl-test-data style directories for
+// tests of this library and of the harnesses that use it:
+//
+//	root := testsupport.NewFixture(t).
+//		WithCompactTest(loader.CompactTest{Name: "basic", Inputs: []string{"a = b"}, Tests: ...}).
+//		WithGenerated().Build()
+//
+// The root holds source_tests/ and, when there is anything to put in it,
+// generated_tests/, so it can be passed anywhere a test data path is expected.
+package testsupport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// Directories under a fixture root, as the loader expects them
+const (
+	SourceDir    = loader.DefaultSourceDir
+	GeneratedDir = loader.DefaultGeneratedDir
+)
+
+// Files used by WithCompactTest and WithFlatTest
+const (
+	DefaultCompactFile = "api_fixture.json"
+	DefaultFlatFile    = "flat_fixture.json"
+)
+
+// SourceSchema is the $schema written into compact files
+const SourceSchema = "../schemas/source-format.json"
+
+// Fixture accumulates test files until Build writes them. Methods return the
+// fixture so calls can be chained; failures are reported through t.
+type Fixture struct {
+	t        testing.TB
+	compact  map[string][]loader.CompactTest // Source file name -> tests
+	flat     map[string][]types.TestCase     // Generated file name -> tests
+	raw      map[string]string               // Path relative to the root -> content
+	generate bool
+}
+
+// NewFixture starts an empty fixture whose files are removed when t ends
+func NewFixture(t testing.TB) *Fixture {
+	return &Fixture{
+		t:       t,
+		compact: make(map[string][]loader.CompactTest),
+		flat:    make(map[string][]types.TestCase),
+		raw:     make(map[string]string),
+	}
+}
+
+// WithCompactTest adds source tests to source_tests/DefaultCompactFile
+func (f *Fixture) WithCompactTest(tests ...loader.CompactTest) *Fixture {
+	return f.WithCompactFile(DefaultCompactFile, tests...)
+}
+
+// WithCompactFile adds source tests to source_tests/name
+func (f *Fixture) WithCompactFile(name string, tests ...loader.CompactTest) *Fixture {
+	f.compact[name] = append(f.compact[name], tests...)
+	return f
+}
+
+// WithFlatTest adds flat tests to generated_tests/DefaultFlatFile
+func (f *Fixture) WithFlatTest(tests ...types.TestCase) *Fixture {
+	return f.WithFlatFile(DefaultFlatFile, tests...)
+}
+
+// WithFlatFile adds flat tests to generated_tests/name. They are written
+// after generation, so they replace a generated file of the same name.
+func (f *Fixture) WithFlatFile(name string, tests ...types.TestCase) *Fixture {
+	f.flat[name] = append(f.flat[name], tests...)
+	return f
+}
+
+// WithInvalidFile writes content verbatim to path, relative to the root (for
+// example "generated_tests/broken.json"), for tests of error handling
+func (f *Fixture) WithInvalidFile(path, content string) *Fixture {
+	f.raw[path] = content
+	return f
+}
+
+// WithGenerated makes Build run the generator over source_tests into
+// generated_tests, as ccl-test-data does before publishing
+func (f *Fixture) WithGenerated() *Fixture {
+	f.generate = true
+	return f
+}
+
+// Build writes the fixture into a new temporary directory and returns it
+func (f *Fixture) Build() string {
+	f.t.Helper()
+	root := f.t.TempDir()
+
+	for _, name := range sortedKeys(f.compact) {
+		f.writeJSON(filepath.Join(root, SourceDir, name), loader.CompactTestFile{Schema: SourceSchema, Tests: f.compact[name]})
+	}
+	if f.generate {
+		gen := generator.NewFlatGenerator(filepath.Join(root, SourceDir), filepath.Join(root, GeneratedDir),
+			generator.GenerateOptions{SourceFormat: generator.FormatCompact})
+		if err := gen.GenerateAll(); err != nil {
+			f.t.Fatalf("testsupport: failed to generate flat tests: %v", err)
+		}
+	}
+	for _, name := range sortedKeys(f.flat) {
+		tests := slices.Clone(f.flat[name])
+		for i := range tests {
+			tests[i].Normalize()
+		}
+		f.writeJSON(filepath.Join(root, GeneratedDir, name), flatFile{
+			Schema: loader.FlatSchema(generator.FlatFormatVersion),
+			Tests:  tests,
+		})
+	}
+	for _, path := range sortedKeys(f.raw) {
+		f.write(filepath.Join(root, path), []byte(f.raw[path]))
+	}
+	return root
+}
+
+// flatFile is the object form of a generated_tests file
+type flatFile struct {
+	Schema string           `json:"$schema"`
+	Tests  []types.TestCase `json:"tests"`
+}
+
+func (f *Fixture) writeJSON(path string, v interface{}) {
+	f.t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		f.t.Fatalf("testsupport: failed to marshal %s: %v", filepath.Base(path), err)
+	}
+	f.write(path, data)
+}
+
+func (f *Fixture) write(path string, data []byte) {
+	f.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		f.t.Fatalf("testsupport: failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		f.t.Fatalf("testsupport: failed to write %s: %v", filepath.Base(path), err)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Minimal is one source test with parse and get_string validations, plus its
+// generated flat tests
+func Minimal(t testing.TB) *Fixture {
+	return NewFixture(t).WithCompactFile("api_basic.json", basicTest("basic", "a", "b")).WithGenerated()
+}
+
+// MixedFormats has two source files (one needing the comments feature), their
+// generated flat tests, and a hand-written flat file with no source test
+func MixedFormats(t testing.TB) *Fixture {
+	return Minimal(t).
+		WithCompactFile("api_comments.json", loader.CompactTest{
+			Name:     "comment_filter",
+			Inputs:   []string{"/= note\na = b"},
+			Features: []string{"comments"},
+			Tests: []loader.CompactValidation{
+				{Function: "filter", Expect: []map[string]interface{}{{"key": "a", "value": "b"}}},
+			},
+		}).
+		WithFlatFile("flat_handwritten.json", types.TestCase{
+			Name:       "handwritten_get_int",
+			Inputs:     []string{"n = 7"},
+			Validation: "get_int",
+			Args:       []string{"n"},
+			Expected:   map[string]interface{}{"count": 1, "value": 7},
+			Functions:  []string{"get_int"},
+		})
+}
+
+// LargeN has n source tests in one file, each with parse and get_string
+// validations, generated into 2n flat tests
+func LargeN(t testing.TB, n int) *Fixture {
+	tests := make([]loader.CompactTest, n)
+	for i := range tests {
+		tests[i] = basicTest(fmt.Sprintf("large_%d", i), fmt.Sprintf("key_%d", i), fmt.Sprintf("value_%d", i))
+	}
+	return NewFixture(t).WithCompactFile("api_large.json", tests...).WithGenerated()
+}
+
+// basicTest is a source test of "key = value" with parse and get_string
+func basicTest(name, key, value string) loader.CompactTest {
+	return loader.CompactTest{
+		Name:   name,
+		Inputs: []string{key + " = " + value},
+		Tests: []loader.CompactValidation{
+			{Function: "parse", Expect: []map[string]interface{}{{"key": key, "value": value}}},
+			{Function: "get_string", Args: []string{key}, Expect: value},
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package testsupport
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// loadNames loads every test of a format from root and returns the names
+func loadNames(t *testing.T, root string, format loader.TestFormat) []string {
+	t.Helper()
+	tests, err := loader.NewTestLoader(root, config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{Format: format, FilterMode: loader.FilterAll})
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	var names []string
+	for _, test := range tests {
+		names = append(names, test.Name)
+	}
+	return names
+}
+
+// checkValid fails if the loader's validation finds problems in dir
+func checkValid(t *testing.T, dir string) {
+	t.Helper()
+	issues, err := loader.ValidateTestDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to validate %s: %v", dir, err)
+	}
+	for _, issue := range issues {
+		t.Errorf("Unexpected validation issue: %v", issue)
+	}
+}
+
+func TestMinimal(t *testing.T) {
+	root := Minimal(t).Build()
+
+	if got := loadNames(t, root, loader.FormatCompact); len(got) != 1 || got[0] != "basic" {
+		t.Errorf("Expected the basic source test, got %v", got)
+	}
+	if got := loadNames(t, root, loader.FormatFlat); len(got) != 2 {
+		t.Errorf("Expected 2 generated tests, got %v", got)
+	}
+	checkValid(t, filepath.Join(root, SourceDir))
+	checkValid(t, filepath.Join(root, GeneratedDir))
+}
+
+func TestMixedFormats(t *testing.T) {
+	root := MixedFormats(t).Build()
+
+	if got := loadNames(t, root, loader.FormatCompact); len(got) != 2 {
+		t.Errorf("Expected 2 source tests, got %v", got)
+	}
+	want := []string{"basic_parse", "basic_get_string", "comment_filter_filter", "handwritten_get_int"}
+	if got := loadNames(t, root, loader.FormatFlat); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	checkValid(t, filepath.Join(root, SourceDir))
+	checkValid(t, filepath.Join(root, GeneratedDir))
+}
+
+func TestLargeN(t *testing.T) {
+	root := LargeN(t, 25).Build()
+	if got := loadNames(t, root, loader.FormatFlat); len(got) != 50 {
+		t.Errorf("Expected 50 generated tests, got %d", len(got))
+	}
+}
+
+func TestFixture_FlatOnly(t *testing.T) {
+	root := NewFixture(t).WithFlatTest(types.TestCase{
+		Name:       "only_parse",
+		Inputs:     []string{"a = b"},
+		Validation: "parse",
+		Expected:   map[string]interface{}{"count": 1, "entries": []map[string]string{{"key": "a", "value": "b"}}},
+	}).Build()
+
+	if got := loadNames(t, root, loader.FormatFlat); len(got) != 1 || got[0] != "only_parse" {
+		t.Errorf("Expected the flat test, got %v", got)
+	}
+	// Without source tests there is no source_tests directory
+	_, err := loader.NewTestLoader(root, config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{Format: loader.FormatCompact})
+	if !errors.Is(err, loader.ErrNoTests) {
+		t.Errorf("Expected ErrNoTests for source tests, got %v", err)
+	}
+}
+
+func TestFixture_WithInvalidFile(t *testing.T) {
+	root := Minimal(t).WithInvalidFile(filepath.Join(GeneratedDir, "broken.json"), "{not json").Build()
+
+	_, err := loader.NewTestLoader(root, config.ImplementationConfig{}).LoadAllTests(loader.LoadOptions{Format: loader.FormatFlat})
+	if !errors.Is(err, loader.ErrParse) {
+		t.Errorf("Expected ErrParse, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// Unbounded is the Arity.Max of functions that accept any number of args
+const Unbounded = -1
+
+// Arity is the number of args a validation function accepts
+type Arity struct {
+	Min int
+	Max int // Unbounded for no upper limit
+}
+
+// FunctionArity is the arg count each validation function accepts. Args are
+// key path segments, so typed access functions take a path of any depth and
+// every other function takes none; a default is not an arg but
+// TestCase.Default. Validations not listed take no args.
+var FunctionArity = map[config.CCLFunction]Arity{
+	config.FunctionParse:           {0, 0},
+	config.FunctionParseIndented:   {0, 0},
+	config.FunctionFilter:          {0, 0},
+	config.FunctionExtractComments: {0, 0},
+	config.FunctionCombine:         {0, 0},
+	config.FunctionExpandDotted:    {0, 0},
+	config.FunctionBuildHierarchy:  {0, 0},
+	config.FunctionGetString:       {1, Unbounded},
+	config.FunctionGetInt:          {1, Unbounded},
+	config.FunctionGetBool:         {1, Unbounded},
+	config.FunctionGetFloat:        {1, Unbounded},
+	config.FunctionGetList:         {1, Unbounded},
+	config.FunctionPrettyPrint:     {0, 0},
+	config.FunctionCanonicalFormat: {0, 0},
+}
+
+// FunctionInputs is the number of input documents each validation that
+// takes more than one requires. Validations not listed are not checked.
+var FunctionInputs = map[config.CCLFunction]int{
+	config.FunctionCombine: 2,
+}
+
+// CheckInputs returns a ValidationError if validation requires a number of
+// inputs the test does not have, or nil otherwise
+func CheckInputs(validation string, inputs []string) *ValidationError {
+	want, ok := FunctionInputs[config.CCLFunction(validation)]
+	if !ok || len(inputs) == want {
+		return nil
+	}
+	return &ValidationError{Code: CodeInputCount, Message: fmt.Sprintf("%s takes %d inputs, got %d", validation, want, len(inputs))}
+}
+
+// ArityOf returns the arg count a validation accepts
+func ArityOf(validation string) Arity {
+	return FunctionArity[config.CCLFunction(validation)]
+}
+
+// TakesArgs reports whether a validation accepts any args
+func (a Arity) TakesArgs() bool {
+	return a.Max != 0
+}
+
+// CheckArgs returns a ValidationError if args do not fit validation's
+// FunctionArity, or nil if they do. Typed access needs at least one key path
+// segment and has no upper limit; other validations take no args.
+func CheckArgs(validation string, args []string) *ValidationError {
+	arity := ArityOf(validation)
+	switch {
+	case len(args) < arity.Min && len(args) == 0:
+		return &ValidationError{Code: CodeMissingArgs, Message: validation + " requires args"}
+	case len(args) < arity.Min:
+		return &ValidationError{Code: CodeMissingArgs, Message: fmt.Sprintf("%s requires at least %d args, got %d", validation, arity.Min, len(args))}
+	case arity.Max == 0 && len(args) > 0:
+		return &ValidationError{Code: CodeUnexpectedArgs, Message: fmt.Sprintf("%s does not take args, got %v", validation, args)}
+	case arity.Max != Unbounded && len(args) > arity.Max:
+		return &ValidationError{Code: CodeUnexpectedArgs, Message: fmt.Sprintf("%s takes at most %d args, got %d", validation, arity.Max, len(args))}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// argCase is one arg count checked against a function's arity
+type argCase struct {
+	name string
+	args []string
+	want string
+}
+
+func TestCheckArgs(t *testing.T) {
+	// Typed access args are key path segments, so a path of any depth is at
+	// the limit; a default is TestCase.Default, never an extra arg
+	deepPath := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	tests := []struct {
+		fn    config.CCLFunction
+		arity Arity
+	}{
+		{config.FunctionParse, Arity{0, 0}},
+		{config.FunctionParseIndented, Arity{0, 0}},
+		{config.FunctionFilter, Arity{0, 0}},
+		{config.FunctionExtractComments, Arity{0, 0}},
+		{config.FunctionCombine, Arity{0, 0}},
+		{config.FunctionExpandDotted, Arity{0, 0}},
+		{config.FunctionBuildHierarchy, Arity{0, 0}},
+		{config.FunctionGetString, Arity{1, Unbounded}},
+		{config.FunctionGetInt, Arity{1, Unbounded}},
+		{config.FunctionGetBool, Arity{1, Unbounded}},
+		{config.FunctionGetFloat, Arity{1, Unbounded}},
+		{config.FunctionGetList, Arity{1, Unbounded}},
+		{config.FunctionPrettyPrint, Arity{0, 0}},
+		{config.FunctionCanonicalFormat, Arity{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.fn), func(t *testing.T) {
+			if got := ArityOf(string(tt.fn)); got != tt.arity {
+				t.Fatalf("Expected arity %+v, got %+v", tt.arity, got)
+			}
+
+			cases := []argCase{{"at min", deepPath[:tt.arity.Min], ""}}
+			if tt.arity.Min > 0 {
+				cases = append(cases, argCase{"under", deepPath[:tt.arity.Min-1], CodeMissingArgs})
+			}
+			if tt.arity.Max == Unbounded {
+				cases = append(cases, argCase{"deep path", deepPath, ""})
+			} else {
+				cases = append(cases, argCase{"at max", deepPath[:tt.arity.Max], ""}, argCase{"over", deepPath[:tt.arity.Max+1], CodeUnexpectedArgs})
+			}
+
+			for _, c := range cases {
+				got := ""
+				if problem := CheckArgs(string(tt.fn), c.args); problem != nil {
+					got = problem.Code
+				}
+				if got != c.want {
+					t.Errorf("%s (args %v): expected %q, got %q", c.name, c.args, c.want, got)
+				}
+			}
+		})
+	}
+
+	if len(tests) != len(config.AllFunctions()) || len(FunctionArity) != len(config.AllFunctions()) {
+		t.Errorf("Expected a case and an arity for each of the %d functions, got %d and %d", len(config.AllFunctions()), len(tests), len(FunctionArity))
+	}
+}
+
+func TestCheckArgs_Limits(t *testing.T) {
+	FunctionArity["test_pair"] = Arity{Min: 2, Max: 2}
+	defer delete(FunctionArity, "test_pair")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"a"}, "test_pair requires at least 2 args, got 1"},
+		{[]string{"a", "b"}, ""},
+		{[]string{"a", "b", "c"}, "test_pair takes at most 2 args, got 3"},
+		{nil, "test_pair requires args"},
+	}
+	for _, tt := range tests {
+		got := ""
+		if problem := CheckArgs("test_pair", tt.args); problem != nil {
+			got = problem.Message
+		}
+		if got != tt.want {
+			t.Errorf("args %v: expected %q, got %q", tt.args, tt.want, got)
+		}
+	}
+
+	if problem := CheckArgs("round_trip", []string{"a"}); problem == nil || problem.Code != CodeUnexpectedArgs {
+		t.Errorf("Expected unlisted validations to take no args, got %v", problem)
+	}
+}
+
+func TestCheckInputs(t *testing.T) {
+	tests := []struct {
+		validation string
+		inputs     []string
+		want       string
+	}{
+		{"combine", []string{"a = 1", "b = 2"}, ""},
+		{"combine", []string{"a = 1"}, "combine takes 2 inputs, got 1"},
+		{"combine", []string{"a = 1", "b = 2", "c = 3"}, "combine takes 2 inputs, got 3"},
+		{"parse", []string{"a = 1", "b = 2"}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if problem := CheckInputs(tt.validation, tt.inputs); problem != nil {
+			got = problem.Message
+		}
+		if got != tt.want {
+			t.Errorf("%s with %d inputs: expected %q, got %q", tt.validation, len(tt.inputs), tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package types
+
+// Count returns the number of validations that are set
+func (vs *ValidationSet) Count() int {
+	if vs == nil {
+		return 0
+	}
+	count := 0
+	for _, validation := range []interface{}{
+		vs.Parse, vs.ParseIndented, vs.Filter, vs.ExtractComments, vs.Combine, vs.ExpandDotted, vs.BuildHierarchy,
+		vs.GetString, vs.GetInt, vs.GetBool, vs.GetFloat, vs.GetList, vs.PrettyPrint,
+		vs.RoundTrip, vs.Canonical, vs.ComposeAssociative, vs.IdentityLeft, vs.IdentityRight,
+	} {
+		if validation != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// AssertionCount returns the number of checks a test makes: one for a flat
+// test, and for a source test the validations the loader counted in
+// Meta.Assertions, or else those set in Validations
+func (tc TestCase) AssertionCount() int {
+	switch {
+	case tc.Validation != "":
+		return 1
+	case tc.Meta.Assertions > 0:
+		return tc.Meta.Assertions
+	default:
+		return tc.Validations.Count()
+	}
+}
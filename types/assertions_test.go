@@ -0,0 +1,21 @@
+package types
+
+import "testing"
+
+func TestTestCase_AssertionCount(t *testing.T) {
+	tests := []struct {
+		name string
+		tc   TestCase
+		want int
+	}{
+		{"flat", TestCase{Validation: "parse"}, 1},
+		{"source", TestCase{Validations: &ValidationSet{Parse: []interface{}{}, GetString: map[string]interface{}{}, RoundTrip: true}}, 3},
+		{"source counted by the loader", TestCase{Validations: &ValidationSet{GetString: map[string]interface{}{}}, Meta: TestMetadata{Assertions: 2}}, 2},
+		{"no validations", TestCase{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.tc.AssertionCount(); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
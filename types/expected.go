@@ -0,0 +1,193 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExpectedValue returns Expected, decoding RawExpected on first use and
+// caching the result in Expected. It returns nil when RawExpected does not
+// decode; see DecodeExpected.
+func (tc *TestCase) ExpectedValue() interface{} {
+	expected, _ := tc.DecodeExpected()
+	return expected
+}
+
+// DecodeExpected returns Expected like ExpectedValue, or an error when
+// RawExpected does not decode, in which case RawExpected is kept
+func (tc *TestCase) DecodeExpected() (interface{}, error) {
+	if tc.Expected == nil && len(tc.RawExpected) > 0 {
+		var expected interface{}
+		if err := json.Unmarshal(tc.RawExpected, &expected); err != nil {
+			return nil, fmt.Errorf("failed to decode expected value: %w", err)
+		}
+		tc.Expected = ExtractExpected(tc.Validation, expected)
+		tc.RawExpected = nil
+	}
+	return tc.Expected, nil
+}
+
+// ExpectedBytes returns the size of the expected value as compact JSON:
+// Meta.ExpectedBytes, which the loader records for generated tests, or else
+// Expected encoded
+func (tc TestCase) ExpectedBytes() int {
+	if tc.Meta.ExpectedBytes > 0 || tc.Expected == nil {
+		return tc.Meta.ExpectedBytes
+	}
+	data, err := json.Marshal(tc.Expected)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// ExpectedString returns the expected value of a string validation
+func (tc *TestCase) ExpectedString() (string, bool) {
+	value, ok := tc.ExpectedValue().(string)
+	return value, ok
+}
+
+// ExpectedBool returns the expected value of a boolean validation
+func (tc *TestCase) ExpectedBool() (bool, bool) {
+	value, ok := tc.ExpectedValue().(bool)
+	return value, ok
+}
+
+// ExpectedFloat returns the expected value of a numeric validation
+func (tc *TestCase) ExpectedFloat() (float64, bool) {
+	value, ok := tc.ExpectedValue().(float64)
+	return value, ok
+}
+
+// ExpectedList returns the expected value of a get_list validation
+func (tc *TestCase) ExpectedList() ([]string, bool) {
+	items, ok := tc.ExpectedValue().([]interface{})
+	if !ok {
+		return nil, false
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		if list[i], ok = item.(string); !ok {
+			return nil, false
+		}
+	}
+	return list, true
+}
+
+// ExtractExpected unwraps a flat test's structured expected object
+// ({"count": ..., "entries": ...}) to the part its validation compares
+func ExtractExpected(validation string, expected interface{}) interface{} {
+	// If it's already a simple value, return as-is
+	if expected == nil {
+		return nil
+	}
+
+	// Check if it's a structured Expected object with Count/Value/Object/etc fields
+	expectedMap, isMap := expected.(map[string]interface{})
+	if !isMap {
+		// Simple value, return as-is
+		return expected
+	}
+
+	// Check if it has the structured format fields
+	_, hasCount := expectedMap["count"]
+	if !hasCount {
+		// Not a structured format, return as-is
+		return expected
+	}
+
+	// Extract the appropriate field based on validation type
+	switch validation {
+	case "parse", "parse_indented", "filter", "extract_comments", "combine", "compose", "expand_dotted":
+		// These expect entries
+		if entries, ok := expectedMap["entries"]; ok {
+			return stripEntrySources(entries)
+		}
+	case "build_hierarchy":
+		// Expects an object
+		if object, ok := expectedMap["object"]; ok {
+			return object
+		}
+	case "get_string", "get_int", "get_bool", "get_float":
+		// Typed access expects a single value
+		if value, ok := expectedMap["value"]; ok {
+			return value
+		}
+	case "get_list":
+		// List access expects a list
+		if list, ok := expectedMap["list"]; ok {
+			return list
+		}
+	case "pretty_print", "canonical_format":
+		// Formatting expects the rendered text
+		if text, ok := expectedMap["text"]; ok {
+			return text
+		}
+	}
+
+	// Fallback: return the original expected value
+	return expected
+}
+
+// EntrySourceKey is the extension field entry provenance adds to generated
+// expected entries: the entry's index in the source expect array
+const EntrySourceKey = "src_index"
+
+// stripEntrySources returns entries without their EntrySourceKey fields, so
+// provenance never takes part in comparison
+func stripEntrySources(entries interface{}) interface{} {
+	list, ok := entries.([]interface{})
+	if !ok {
+		return entries
+	}
+	stripped := make([]interface{}, len(list))
+	for i, entry := range list {
+		stripped[i] = entry
+		if entryMap, ok := entry.(map[string]interface{}); ok {
+			if _, has := entryMap[EntrySourceKey]; has {
+				clean := make(map[string]interface{}, len(entryMap)-1)
+				for key, value := range entryMap {
+					if key != EntrySourceKey {
+						clean[key] = value
+					}
+				}
+				stripped[i] = clean
+			}
+		}
+	}
+	return stripped
+}
+
+// ExtractTolerance returns the get_float tolerance of a flat test's
+// structured expected object, or zero for exact comparison
+func ExtractTolerance(expected interface{}) float64 {
+	structured, _ := expected.(map[string]interface{})
+	tolerance, _ := structured["tolerance"].(float64)
+	return tolerance
+}
+
+// ExpectedObject returns the expected object of a build_hierarchy
+// validation. Key order is kept when the test was loaded with LazyExpected
+// or from source; otherwise keys are sorted.
+func (tc *TestCase) ExpectedObject() (OrderedMap, bool) {
+	if tc.Expected == nil && len(tc.RawExpected) > 0 {
+		expected, err := DecodeOrdered(tc.RawExpected)
+		if err != nil {
+			return nil, false
+		}
+		if structured, ok := expected.(OrderedMap); ok {
+			if _, hasCount := structured.Get("count"); hasCount {
+				expected, _ = structured.Get("object")
+			}
+		}
+		object, ok := expected.(OrderedMap)
+		return object, ok
+	}
+	switch object := tc.ExpectedValue().(type) {
+	case OrderedMap:
+		return object, true
+	case map[string]interface{}:
+		return orderedFromMap(object), true
+	}
+	return nil, false
+}
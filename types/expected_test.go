@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTestCase_ExpectedValue_DecodesLazily(t *testing.T) {
+	tc := TestCase{
+		Validation:  "parse",
+		RawExpected: json.RawMessage(`{"count": 1, "entries": [{"key": "a", "value": "b"}]}`),
+	}
+
+	want := []interface{}{map[string]interface{}{"key": "a", "value": "b"}}
+	if got := tc.ExpectedValue(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected entries %v, got %v", want, got)
+	}
+	if tc.RawExpected != nil || !reflect.DeepEqual(tc.Expected, want) {
+		t.Errorf("Expected the decoded value cached in Expected, got %+v", tc)
+	}
+}
+
+func TestTestCase_DecodeExpected_KeepsCorruptValue(t *testing.T) {
+	tc := TestCase{Validation: "parse", RawExpected: json.RawMessage(`{"count": 1, "entries": [`)}
+
+	if got, err := tc.DecodeExpected(); err == nil || got != nil {
+		t.Errorf("Expected a decode error, got %v, %v", got, err)
+	}
+	if tc.ExpectedValue() != nil || len(tc.RawExpected) == 0 {
+		t.Errorf("Expected RawExpected kept after a failed decode, got %+v", tc)
+	}
+}
+
+func TestTestCase_ExpectedValue_PrefersExpected(t *testing.T) {
+	tc := TestCase{Expected: "decoded", RawExpected: json.RawMessage(`"raw"`)}
+	if got := tc.ExpectedValue(); got != "decoded" {
+		t.Errorf("Expected the existing value, got %v", got)
+	}
+}
+
+func TestTestCase_TypedExpected(t *testing.T) {
+	str := TestCase{Validation: "get_string", RawExpected: json.RawMessage(`{"count": 1, "value": "x"}`)}
+	if value, ok := str.ExpectedString(); !ok || value != "x" {
+		t.Errorf("ExpectedString = %q, %v", value, ok)
+	}
+	if _, ok := str.ExpectedBool(); ok {
+		t.Error("Expected ExpectedBool to fail on a string")
+	}
+
+	boolean := TestCase{Validation: "get_bool", RawExpected: json.RawMessage(`true`)}
+	if value, ok := boolean.ExpectedBool(); !ok || !value {
+		t.Errorf("ExpectedBool = %v, %v", value, ok)
+	}
+
+	number := TestCase{Validation: "get_int", RawExpected: json.RawMessage(`{"count": 1, "value": 42}`)}
+	if value, ok := number.ExpectedFloat(); !ok || value != 42 {
+		t.Errorf("ExpectedFloat = %v, %v", value, ok)
+	}
+
+	list := TestCase{Validation: "get_list", RawExpected: json.RawMessage(`{"count": 2, "list": ["a", "b"]}`)}
+	if value, ok := list.ExpectedList(); !ok || !reflect.DeepEqual(value, []string{"a", "b"}) {
+		t.Errorf("ExpectedList = %v, %v", value, ok)
+	}
+	mixed := TestCase{Validation: "get_list", RawExpected: json.RawMessage(`["a", 1]`)}
+	if _, ok := mixed.ExpectedList(); ok {
+		t.Error("Expected ExpectedList to fail on non-string items")
+	}
+}
+
+func TestExtractExpected(t *testing.T) {
+	tests := []struct {
+		validation string
+		expected   interface{}
+		want       interface{}
+	}{
+		{"parse", nil, nil},
+		{"get_string", "plain", "plain"},
+		{"build_hierarchy", map[string]interface{}{"count": 1.0, "object": map[string]interface{}{"a": "b"}}, map[string]interface{}{"a": "b"}},
+		{"get_list", map[string]interface{}{"count": 1.0, "list": []interface{}{"x"}}, []interface{}{"x"}},
+		{"pretty_print", map[string]interface{}{"count": 1.0, "text": "a = b\n"}, "a = b\n"},
+		{"extract_comments", map[string]interface{}{"count": 1.0, "entries": []interface{}{map[string]interface{}{"key": "/", "value": "note"}}}, []interface{}{map[string]interface{}{"key": "/", "value": "note"}}},
+		{"canonical_format", map[string]interface{}{"count": 1.0, "text": "a = b"}, "a = b"},
+		{"parse", map[string]interface{}{"a": "b"}, map[string]interface{}{"a": "b"}},
+		{"round_trip", map[string]interface{}{"count": 1.0}, map[string]interface{}{"count": 1.0}},
+		{"parse", map[string]interface{}{"count": 1.0, "entries": []interface{}{map[string]interface{}{"key": "a", "value": "b", "src_index": 2.0}}}, []interface{}{map[string]interface{}{"key": "a", "value": "b"}}},
+	}
+	for _, tt := range tests {
+		if got := ExtractExpected(tt.validation, tt.expected); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExtractExpected(%s, %v) = %v, want %v", tt.validation, tt.expected, got, tt.want)
+		}
+	}
+}
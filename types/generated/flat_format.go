@@ -5,13 +5,55 @@ package generated
 import "encoding/json"
 import "fmt"
 import "reflect"
+import "unicode/utf8"
 
 type GeneratedFormatSimpleJson struct {
 	// Schema corresponds to the JSON schema field "$schema".
 	Schema string `json:"$schema" yaml:"$schema" mapstructure:"$schema"`
 
+	// Behaviors corresponds to the JSON schema field "behaviors".
+	Behaviors []string `json:"behaviors,omitempty" yaml:"behaviors,omitempty" mapstructure:"behaviors,omitempty"`
+
+	// Features corresponds to the JSON schema field "features".
+	Features []string `json:"features,omitempty" yaml:"features,omitempty" mapstructure:"features,omitempty"`
+
+	// Generator corresponds to the JSON schema field "generator".
+	Generator *GeneratedFormatSimpleJsonGenerator `json:"generator,omitempty" yaml:"generator,omitempty" mapstructure:"generator,omitempty"`
+
 	// Tests corresponds to the JSON schema field "tests".
 	Tests []GeneratedFormatSimpleJsonTestsElem `json:"tests" yaml:"tests" mapstructure:"tests"`
+
+	// Variants corresponds to the JSON schema field "variants".
+	Variants []string `json:"variants,omitempty" yaml:"variants,omitempty" mapstructure:"variants,omitempty"`
+}
+
+type GeneratedFormatSimpleJsonGenerator struct {
+	// OptionsFingerprint corresponds to the JSON schema field "options_fingerprint".
+	OptionsFingerprint string `json:"options_fingerprint" yaml:"options_fingerprint" mapstructure:"options_fingerprint"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version string `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *GeneratedFormatSimpleJsonGenerator) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["options_fingerprint"]; raw != nil && !ok {
+		return fmt.Errorf("field options_fingerprint in GeneratedFormatSimpleJsonGenerator: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in GeneratedFormatSimpleJsonGenerator: required")
+	}
+	type Plain GeneratedFormatSimpleJsonGenerator
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = GeneratedFormatSimpleJsonGenerator(plain)
+	return nil
 }
 
 type GeneratedFormatSimpleJsonTestsElem struct {
@@ -24,6 +66,9 @@ type GeneratedFormatSimpleJsonTestsElem struct {
 	// Conflicts corresponds to the JSON schema field "conflicts".
 	Conflicts *GeneratedFormatSimpleJsonTestsElemConflicts `json:"conflicts,omitempty" yaml:"conflicts,omitempty" mapstructure:"conflicts,omitempty"`
 
+	// Default corresponds to the JSON schema field "default".
+	Default *string `json:"default,omitempty" yaml:"default,omitempty" mapstructure:"default,omitempty"`
+
 	// ErrorType corresponds to the JSON schema field "error_type".
 	ErrorType *string `json:"error_type,omitempty" yaml:"error_type,omitempty" mapstructure:"error_type,omitempty"`
 
@@ -42,15 +87,33 @@ type GeneratedFormatSimpleJsonTestsElem struct {
 	// Inputs corresponds to the JSON schema field "inputs".
 	Inputs []string `json:"inputs" yaml:"inputs" mapstructure:"inputs"`
 
+	// Level corresponds to the JSON schema field "level".
+	Level *int `json:"level,omitempty" yaml:"level,omitempty" mapstructure:"level,omitempty"`
+
+	// Limits corresponds to the JSON schema field "limits".
+	Limits *GeneratedFormatSimpleJsonTestsElemLimits `json:"limits,omitempty" yaml:"limits,omitempty" mapstructure:"limits,omitempty"`
+
+	// Meta corresponds to the JSON schema field "meta".
+	Meta *GeneratedFormatSimpleJsonTestsElemMeta `json:"meta,omitempty" yaml:"meta,omitempty" mapstructure:"meta,omitempty"`
+
 	// Name corresponds to the JSON schema field "name".
 	Name string `json:"name" yaml:"name" mapstructure:"name"`
 
 	// Requires corresponds to the JSON schema field "requires".
 	Requires []string `json:"requires,omitempty" yaml:"requires,omitempty" mapstructure:"requires,omitempty"`
 
+	// Skip corresponds to the JSON schema field "skip".
+	Skip *GeneratedFormatSimpleJsonTestsElemSkip `json:"skip,omitempty" yaml:"skip,omitempty" mapstructure:"skip,omitempty"`
+
 	// SourceTest corresponds to the JSON schema field "source_test".
 	SourceTest *string `json:"source_test,omitempty" yaml:"source_test,omitempty" mapstructure:"source_test,omitempty"`
 
+	// Suite corresponds to the JSON schema field "suite".
+	Suite *string `json:"suite,omitempty" yaml:"suite,omitempty" mapstructure:"suite,omitempty"`
+
+	// UnicodeNormalization corresponds to the JSON schema field "unicode_normalization".
+	UnicodeNormalization *GeneratedFormatSimpleJsonTestsElemUnicodeNormalization `json:"unicode_normalization,omitempty" yaml:"unicode_normalization,omitempty" mapstructure:"unicode_normalization,omitempty"`
+
 	// Validation corresponds to the JSON schema field "validation".
 	Validation GeneratedFormatSimpleJsonTestsElemValidation `json:"validation" yaml:"validation" mapstructure:"validation"`
 
@@ -144,6 +207,9 @@ type GeneratedFormatSimpleJsonTestsElemExpected struct {
 	// Text corresponds to the JSON schema field "text".
 	Text *string `json:"text,omitempty" yaml:"text,omitempty" mapstructure:"text,omitempty"`
 
+	// Tolerance corresponds to the JSON schema field "tolerance".
+	Tolerance *float64 `json:"tolerance,omitempty" yaml:"tolerance,omitempty" mapstructure:"tolerance,omitempty"`
+
 	// Value corresponds to the JSON schema field "value".
 	Value interface{} `json:"value,omitempty" yaml:"value,omitempty" mapstructure:"value,omitempty"`
 }
@@ -152,6 +218,9 @@ type GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem struct {
 	// Key corresponds to the JSON schema field "key".
 	Key string `json:"key" yaml:"key" mapstructure:"key"`
 
+	// SrcIndex corresponds to the JSON schema field "src_index".
+	SrcIndex *int `json:"src_index,omitempty" yaml:"src_index,omitempty" mapstructure:"src_index,omitempty"`
+
 	// Value corresponds to the JSON schema field "value".
 	Value string `json:"value" yaml:"value" mapstructure:"value"`
 }
@@ -173,6 +242,9 @@ func (j *GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem) UnmarshalJSON(b
 	if err := json.Unmarshal(b, &plain); err != nil {
 		return err
 	}
+	if plain.SrcIndex != nil && 0 > *plain.SrcIndex {
+		return fmt.Errorf("field %s: must be >= %v", "src_index", 0)
+	}
 	*j = GeneratedFormatSimpleJsonTestsElemExpectedEntriesElem(plain)
 	return nil
 }
@@ -191,6 +263,9 @@ func (j *GeneratedFormatSimpleJsonTestsElemExpected) UnmarshalJSON(b []byte) err
 	if err := json.Unmarshal(b, &plain); err != nil {
 		return err
 	}
+	if plain.Tolerance != nil && 0 > *plain.Tolerance {
+		return fmt.Errorf("field %s: must be >= %v", "tolerance", 0)
+	}
 	*j = GeneratedFormatSimpleJsonTestsElemExpected(plain)
 	return nil
 }
@@ -199,8 +274,10 @@ type GeneratedFormatSimpleJsonTestsElemFunctionsElem string
 
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemBuildHierarchy GeneratedFormatSimpleJsonTestsElemFunctionsElem = "build_hierarchy"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemCanonicalFormat GeneratedFormatSimpleJsonTestsElemFunctionsElem = "canonical_format"
+const GeneratedFormatSimpleJsonTestsElemFunctionsElemCombine GeneratedFormatSimpleJsonTestsElemFunctionsElem = "combine"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemCompose GeneratedFormatSimpleJsonTestsElemFunctionsElem = "compose"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemComposeAssociative GeneratedFormatSimpleJsonTestsElemFunctionsElem = "compose_associative"
+const GeneratedFormatSimpleJsonTestsElemFunctionsElemExtractComments GeneratedFormatSimpleJsonTestsElemFunctionsElem = "extract_comments"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemFilter GeneratedFormatSimpleJsonTestsElemFunctionsElem = "filter"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemGetBool GeneratedFormatSimpleJsonTestsElemFunctionsElem = "get_bool"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemGetFloat GeneratedFormatSimpleJsonTestsElemFunctionsElem = "get_float"
@@ -212,6 +289,7 @@ const GeneratedFormatSimpleJsonTestsElemFunctionsElemIdentityRight GeneratedForm
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemLoad GeneratedFormatSimpleJsonTestsElemFunctionsElem = "load"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemParse GeneratedFormatSimpleJsonTestsElemFunctionsElem = "parse"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemParseIndented GeneratedFormatSimpleJsonTestsElemFunctionsElem = "parse_indented"
+const GeneratedFormatSimpleJsonTestsElemFunctionsElemPrettyPrint GeneratedFormatSimpleJsonTestsElemFunctionsElem = "pretty_print"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemPrint GeneratedFormatSimpleJsonTestsElemFunctionsElem = "print"
 const GeneratedFormatSimpleJsonTestsElemFunctionsElemRoundTrip GeneratedFormatSimpleJsonTestsElemFunctionsElem = "round_trip"
 
@@ -219,6 +297,8 @@ var enumValues_GeneratedFormatSimpleJsonTestsElemFunctionsElem = []interface{}{
 	"parse",
 	"parse_indented",
 	"filter",
+	"extract_comments",
+	"combine",
 	"compose",
 	"build_hierarchy",
 	"get_string",
@@ -227,6 +307,7 @@ var enumValues_GeneratedFormatSimpleJsonTestsElemFunctionsElem = []interface{}{
 	"get_float",
 	"get_list",
 	"print",
+	"pretty_print",
 	"canonical_format",
 	"load",
 	"round_trip",
@@ -255,12 +336,100 @@ func (j *GeneratedFormatSimpleJsonTestsElemFunctionsElem) UnmarshalJSON(b []byte
 	return nil
 }
 
+type GeneratedFormatSimpleJsonTestsElemLimits struct {
+	// Slow corresponds to the JSON schema field "slow".
+	Slow *bool `json:"slow,omitempty" yaml:"slow,omitempty" mapstructure:"slow,omitempty"`
+
+	// TimeoutMs corresponds to the JSON schema field "timeout_ms".
+	TimeoutMs *int `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty" mapstructure:"timeout_ms,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *GeneratedFormatSimpleJsonTestsElemLimits) UnmarshalJSON(b []byte) error {
+	type Plain GeneratedFormatSimpleJsonTestsElemLimits
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if plain.TimeoutMs != nil && 1 > *plain.TimeoutMs {
+		return fmt.Errorf("field %s: must be >= %v", "timeout_ms", 1)
+	}
+	*j = GeneratedFormatSimpleJsonTestsElemLimits(plain)
+	return nil
+}
+
+type GeneratedFormatSimpleJsonTestsElemMeta struct {
+	// Tags corresponds to the JSON schema field "tags".
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty" mapstructure:"tags,omitempty"`
+}
+
+type GeneratedFormatSimpleJsonTestsElemSkip struct {
+	// Reason corresponds to the JSON schema field "reason".
+	Reason string `json:"reason" yaml:"reason" mapstructure:"reason"`
+
+	// Until corresponds to the JSON schema field "until".
+	Until *string `json:"until,omitempty" yaml:"until,omitempty" mapstructure:"until,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *GeneratedFormatSimpleJsonTestsElemSkip) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["reason"]; raw != nil && !ok {
+		return fmt.Errorf("field reason in GeneratedFormatSimpleJsonTestsElemSkip: required")
+	}
+	type Plain GeneratedFormatSimpleJsonTestsElemSkip
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(string(plain.Reason)) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "reason", 1)
+	}
+	*j = GeneratedFormatSimpleJsonTestsElemSkip(plain)
+	return nil
+}
+
+type GeneratedFormatSimpleJsonTestsElemUnicodeNormalization string
+
+const GeneratedFormatSimpleJsonTestsElemUnicodeNormalizationNfc GeneratedFormatSimpleJsonTestsElemUnicodeNormalization = "nfc"
+const GeneratedFormatSimpleJsonTestsElemUnicodeNormalizationNone GeneratedFormatSimpleJsonTestsElemUnicodeNormalization = "none"
+
+var enumValues_GeneratedFormatSimpleJsonTestsElemUnicodeNormalization = []interface{}{
+	"nfc",
+	"none",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *GeneratedFormatSimpleJsonTestsElemUnicodeNormalization) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_GeneratedFormatSimpleJsonTestsElemUnicodeNormalization {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_GeneratedFormatSimpleJsonTestsElemUnicodeNormalization, v)
+	}
+	*j = GeneratedFormatSimpleJsonTestsElemUnicodeNormalization(v)
+	return nil
+}
+
 type GeneratedFormatSimpleJsonTestsElemValidation string
 
 const GeneratedFormatSimpleJsonTestsElemValidationBuildHierarchy GeneratedFormatSimpleJsonTestsElemValidation = "build_hierarchy"
 const GeneratedFormatSimpleJsonTestsElemValidationCanonicalFormat GeneratedFormatSimpleJsonTestsElemValidation = "canonical_format"
+const GeneratedFormatSimpleJsonTestsElemValidationCombine GeneratedFormatSimpleJsonTestsElemValidation = "combine"
 const GeneratedFormatSimpleJsonTestsElemValidationCompose GeneratedFormatSimpleJsonTestsElemValidation = "compose"
 const GeneratedFormatSimpleJsonTestsElemValidationComposeAssociative GeneratedFormatSimpleJsonTestsElemValidation = "compose_associative"
+const GeneratedFormatSimpleJsonTestsElemValidationExtractComments GeneratedFormatSimpleJsonTestsElemValidation = "extract_comments"
 const GeneratedFormatSimpleJsonTestsElemValidationFilter GeneratedFormatSimpleJsonTestsElemValidation = "filter"
 const GeneratedFormatSimpleJsonTestsElemValidationGetBool GeneratedFormatSimpleJsonTestsElemValidation = "get_bool"
 const GeneratedFormatSimpleJsonTestsElemValidationGetFloat GeneratedFormatSimpleJsonTestsElemValidation = "get_float"
@@ -272,6 +441,7 @@ const GeneratedFormatSimpleJsonTestsElemValidationIdentityRight GeneratedFormatS
 const GeneratedFormatSimpleJsonTestsElemValidationLoad GeneratedFormatSimpleJsonTestsElemValidation = "load"
 const GeneratedFormatSimpleJsonTestsElemValidationParse GeneratedFormatSimpleJsonTestsElemValidation = "parse"
 const GeneratedFormatSimpleJsonTestsElemValidationParseIndented GeneratedFormatSimpleJsonTestsElemValidation = "parse_indented"
+const GeneratedFormatSimpleJsonTestsElemValidationPrettyPrint GeneratedFormatSimpleJsonTestsElemValidation = "pretty_print"
 const GeneratedFormatSimpleJsonTestsElemValidationPrint GeneratedFormatSimpleJsonTestsElemValidation = "print"
 const GeneratedFormatSimpleJsonTestsElemValidationRoundTrip GeneratedFormatSimpleJsonTestsElemValidation = "round_trip"
 
@@ -279,6 +449,8 @@ var enumValues_GeneratedFormatSimpleJsonTestsElemValidation = []interface{}{
 	"parse",
 	"parse_indented",
 	"filter",
+	"extract_comments",
+	"combine",
 	"compose",
 	"build_hierarchy",
 	"get_string",
@@ -287,6 +459,7 @@ var enumValues_GeneratedFormatSimpleJsonTestsElemValidation = []interface{}{
 	"get_float",
 	"get_list",
 	"print",
+	"pretty_print",
 	"canonical_format",
 	"load",
 	"round_trip",
@@ -380,6 +553,12 @@ func (j *GeneratedFormatSimpleJsonTestsElem) UnmarshalJSON(b []byte) error {
 	if plain.Inputs != nil && len(plain.Inputs) < 1 {
 		return fmt.Errorf("field %s length: must be >= %d", "inputs", 1)
 	}
+	if plain.Level != nil && 1 > *plain.Level {
+		return fmt.Errorf("field %s: must be >= %v", "level", 1)
+	}
+	if plain.Level != nil && 5 < *plain.Level {
+		return fmt.Errorf("field %s: must be <= %v", "level", 5)
+	}
 	*j = GeneratedFormatSimpleJsonTestsElem(plain)
 	return nil
 }
@@ -5,14 +5,34 @@ package generated
 import "encoding/json"
 import "fmt"
 import "reflect"
+import "unicode/utf8"
 
 // Schema for source test files (api_*.json)
 type SourceFormatJson struct {
 	// JSON Schema reference (relative path to schema file)
 	Schema *string `json:"$schema,omitempty" yaml:"$schema,omitempty" mapstructure:"$schema,omitempty"`
 
+	// Behaviors required by every test in the file, unless a test chooses another
+	// behavior from the same group
+	Behaviors []string `json:"behaviors,omitempty" yaml:"behaviors,omitempty" mapstructure:"behaviors,omitempty"`
+
+	// Features required by every test in the file, merged into each test
+	Features []string `json:"features,omitempty" yaml:"features,omitempty" mapstructure:"features,omitempty"`
+
+	// Generation settings for this file only, added to the generator's own
+	Generator *SourceFormatJsonGenerator `json:"generator,omitempty" yaml:"generator,omitempty" mapstructure:"generator,omitempty"`
+
 	// Array of test cases
 	Tests []SourceFormatJsonTestsElem `json:"tests" yaml:"tests" mapstructure:"tests"`
+
+	// Variants for every test in the file that declares none
+	Variants []string `json:"variants,omitempty" yaml:"variants,omitempty" mapstructure:"variants,omitempty"`
+}
+
+// Generation settings for this file only, added to the generator's own
+type SourceFormatJsonGenerator struct {
+	// Functions whose validations are never flattened from this file
+	SkipFunctions []string `json:"skip_functions,omitempty" yaml:"skip_functions,omitempty" mapstructure:"skip_functions,omitempty"`
 }
 
 type SourceFormatJsonTestsElem struct {
@@ -22,17 +42,35 @@ type SourceFormatJsonTestsElem struct {
 	// Mutually exclusive options by category (optional)
 	Conflicts *SourceFormatJsonTestsElemConflicts `json:"conflicts,omitempty" yaml:"conflicts,omitempty" mapstructure:"conflicts,omitempty"`
 
+	// Kind of parse error expected, as the implementation reports it; requires
+	// expect_parse_error (optional)
+	ErrorType *string `json:"error_type,omitempty" yaml:"error_type,omitempty" mapstructure:"error_type,omitempty"`
+
+	// The inputs must fail to parse; generates a single parse test expecting an
+	// error and no validations (optional)
+	ExpectParseError *bool `json:"expect_parse_error,omitempty" yaml:"expect_parse_error,omitempty" mapstructure:"expect_parse_error,omitempty"`
+
 	// Required language features for this test
 	Features []string `json:"features,omitempty" yaml:"features,omitempty" mapstructure:"features,omitempty"`
 
 	// CCL input text(s) to be tested. Single-input tests use a 1-element array.
 	Inputs []string `json:"inputs" yaml:"inputs" mapstructure:"inputs"`
 
+	// Resource limits for intentionally slow tests (optional)
+	Limits *SourceFormatJsonTestsElemLimits `json:"limits,omitempty" yaml:"limits,omitempty" mapstructure:"limits,omitempty"`
+
 	// Unique test name identifier
 	Name string `json:"name" yaml:"name" mapstructure:"name"`
 
-	// Array of test validations
-	Tests []SourceFormatJsonTestsElemTestsElem `json:"tests" yaml:"tests" mapstructure:"tests"`
+	// Quarantines the test while keeping it in the suite (optional)
+	Skip *SourceFormatJsonTestsElemSkip `json:"skip,omitempty" yaml:"skip,omitempty" mapstructure:"skip,omitempty"`
+
+	// Array of test validations; required unless expect_parse_error is set
+	Tests []SourceFormatJsonTestsElemTestsElem `json:"tests,omitempty" yaml:"tests,omitempty" mapstructure:"tests,omitempty"`
+
+	// Unicode normalization applied to expected and actual strings before
+	// comparison (optional)
+	UnicodeNormalization *SourceFormatJsonTestsElemUnicodeNormalization `json:"unicode_normalization,omitempty" yaml:"unicode_normalization,omitempty" mapstructure:"unicode_normalization,omitempty"`
 
 	// Specification variants (optional)
 	Variants []SourceFormatJsonTestsElemVariantsElem `json:"variants,omitempty" yaml:"variants,omitempty" mapstructure:"variants,omitempty"`
@@ -103,10 +141,67 @@ type SourceFormatJsonTestsElemConflicts struct {
 	Variants []string `json:"variants,omitempty" yaml:"variants,omitempty" mapstructure:"variants,omitempty"`
 }
 
+// Resource limits for intentionally slow tests (optional)
+type SourceFormatJsonTestsElemLimits struct {
+	// The test is slow by design; LoadOptions.SkipSlow leaves it out
+	Slow *bool `json:"slow,omitempty" yaml:"slow,omitempty" mapstructure:"slow,omitempty"`
+
+	// Per-test timeout in milliseconds, overriding the runner's global timeout
+	TimeoutMs *int `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty" mapstructure:"timeout_ms,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SourceFormatJsonTestsElemLimits) UnmarshalJSON(b []byte) error {
+	type Plain SourceFormatJsonTestsElemLimits
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if plain.TimeoutMs != nil && 1 > *plain.TimeoutMs {
+		return fmt.Errorf("field %s: must be >= %v", "timeout_ms", 1)
+	}
+	*j = SourceFormatJsonTestsElemLimits(plain)
+	return nil
+}
+
+// Quarantines the test while keeping it in the suite (optional)
+type SourceFormatJsonTestsElemSkip struct {
+	// Why the test is skipped
+	Reason string `json:"reason" yaml:"reason" mapstructure:"reason"`
+
+	// Date (YYYY-MM-DD) after which the skip should be revisited
+	Until *string `json:"until,omitempty" yaml:"until,omitempty" mapstructure:"until,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SourceFormatJsonTestsElemSkip) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["reason"]; raw != nil && !ok {
+		return fmt.Errorf("field reason in SourceFormatJsonTestsElemSkip: required")
+	}
+	type Plain SourceFormatJsonTestsElemSkip
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(string(plain.Reason)) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "reason", 1)
+	}
+	*j = SourceFormatJsonTestsElemSkip(plain)
+	return nil
+}
+
 type SourceFormatJsonTestsElemTestsElem struct {
-	// Optional arguments for parameterized functions
+	// Key path segments for typed access functions; omitted for others
 	Args []string `json:"args,omitempty" yaml:"args,omitempty" mapstructure:"args,omitempty"`
 
+	// Value a typed access function returns when the key path is absent: expect
+	// is then the default and error must be false (optional)
+	Default *string `json:"default,omitempty" yaml:"default,omitempty" mapstructure:"default,omitempty"`
+
 	// Whether function should produce an error
 	Error bool `json:"error,omitempty" yaml:"error,omitempty" mapstructure:"error,omitempty"`
 
@@ -115,14 +210,20 @@ type SourceFormatJsonTestsElemTestsElem struct {
 
 	// CCL function to test
 	Function SourceFormatJsonTestsElemTestsElemFunction `json:"function" yaml:"function" mapstructure:"function"`
+
+	// Absolute tolerance for comparing a get_float value; omitted for exact
+	// comparison
+	Tolerance *float64 `json:"tolerance,omitempty" yaml:"tolerance,omitempty" mapstructure:"tolerance,omitempty"`
 }
 
 type SourceFormatJsonTestsElemTestsElemFunction string
 
 const SourceFormatJsonTestsElemTestsElemFunctionBuildHierarchy SourceFormatJsonTestsElemTestsElemFunction = "build_hierarchy"
 const SourceFormatJsonTestsElemTestsElemFunctionCanonicalFormat SourceFormatJsonTestsElemTestsElemFunction = "canonical_format"
+const SourceFormatJsonTestsElemTestsElemFunctionCombine SourceFormatJsonTestsElemTestsElemFunction = "combine"
 const SourceFormatJsonTestsElemTestsElemFunctionCompose SourceFormatJsonTestsElemTestsElemFunction = "compose"
 const SourceFormatJsonTestsElemTestsElemFunctionComposeAssociative SourceFormatJsonTestsElemTestsElemFunction = "compose_associative"
+const SourceFormatJsonTestsElemTestsElemFunctionExtractComments SourceFormatJsonTestsElemTestsElemFunction = "extract_comments"
 const SourceFormatJsonTestsElemTestsElemFunctionFilter SourceFormatJsonTestsElemTestsElemFunction = "filter"
 const SourceFormatJsonTestsElemTestsElemFunctionGetBool SourceFormatJsonTestsElemTestsElemFunction = "get_bool"
 const SourceFormatJsonTestsElemTestsElemFunctionGetFloat SourceFormatJsonTestsElemTestsElemFunction = "get_float"
@@ -134,6 +235,7 @@ const SourceFormatJsonTestsElemTestsElemFunctionIdentityRight SourceFormatJsonTe
 const SourceFormatJsonTestsElemTestsElemFunctionLoad SourceFormatJsonTestsElemTestsElemFunction = "load"
 const SourceFormatJsonTestsElemTestsElemFunctionParse SourceFormatJsonTestsElemTestsElemFunction = "parse"
 const SourceFormatJsonTestsElemTestsElemFunctionParseIndented SourceFormatJsonTestsElemTestsElemFunction = "parse_indented"
+const SourceFormatJsonTestsElemTestsElemFunctionPrettyPrint SourceFormatJsonTestsElemTestsElemFunction = "pretty_print"
 const SourceFormatJsonTestsElemTestsElemFunctionPrint SourceFormatJsonTestsElemTestsElemFunction = "print"
 const SourceFormatJsonTestsElemTestsElemFunctionRoundTrip SourceFormatJsonTestsElemTestsElemFunction = "round_trip"
 
@@ -141,6 +243,8 @@ var enumValues_SourceFormatJsonTestsElemTestsElemFunction = []interface{}{
 	"parse",
 	"parse_indented",
 	"filter",
+	"extract_comments",
+	"combine",
 	"compose",
 	"build_hierarchy",
 	"get_string",
@@ -149,6 +253,7 @@ var enumValues_SourceFormatJsonTestsElemTestsElemFunction = []interface{}{
 	"get_float",
 	"get_list",
 	"print",
+	"pretty_print",
 	"canonical_format",
 	"load",
 	"round_trip",
@@ -197,10 +302,43 @@ func (j *SourceFormatJsonTestsElemTestsElem) UnmarshalJSON(b []byte) error {
 	if v, ok := raw["error"]; !ok || v == nil {
 		plain.Error = false
 	}
+	if plain.Tolerance != nil && 0 > *plain.Tolerance {
+		return fmt.Errorf("field %s: must be >= %v", "tolerance", 0)
+	}
 	*j = SourceFormatJsonTestsElemTestsElem(plain)
 	return nil
 }
 
+type SourceFormatJsonTestsElemUnicodeNormalization string
+
+const SourceFormatJsonTestsElemUnicodeNormalizationNfc SourceFormatJsonTestsElemUnicodeNormalization = "nfc"
+const SourceFormatJsonTestsElemUnicodeNormalizationNone SourceFormatJsonTestsElemUnicodeNormalization = "none"
+
+var enumValues_SourceFormatJsonTestsElemUnicodeNormalization = []interface{}{
+	"nfc",
+	"none",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SourceFormatJsonTestsElemUnicodeNormalization) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_SourceFormatJsonTestsElemUnicodeNormalization {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_SourceFormatJsonTestsElemUnicodeNormalization, v)
+	}
+	*j = SourceFormatJsonTestsElemUnicodeNormalization(v)
+	return nil
+}
+
 type SourceFormatJsonTestsElemVariantsElem string
 
 const SourceFormatJsonTestsElemVariantsElemProposedBehavior SourceFormatJsonTestsElemVariantsElem = "proposed_behavior"
@@ -243,9 +381,6 @@ func (j *SourceFormatJsonTestsElem) UnmarshalJSON(b []byte) error {
 	if _, ok := raw["name"]; raw != nil && !ok {
 		return fmt.Errorf("field name in SourceFormatJsonTestsElem: required")
 	}
-	if _, ok := raw["tests"]; raw != nil && !ok {
-		return fmt.Errorf("field tests in SourceFormatJsonTestsElem: required")
-	}
 	type Plain SourceFormatJsonTestsElem
 	var plain Plain
 	if err := json.Unmarshal(b, &plain); err != nil {
@@ -254,6 +389,9 @@ func (j *SourceFormatJsonTestsElem) UnmarshalJSON(b []byte) error {
 	if plain.Inputs != nil && len(plain.Inputs) < 1 {
 		return fmt.Errorf("field %s length: must be >= %d", "inputs", 1)
 	}
+	if plain.ErrorType != nil && utf8.RuneCountInString(string(*plain.ErrorType)) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "error_type", 1)
+	}
 	if plain.Tests != nil && len(plain.Tests) < 1 {
 		return fmt.Errorf("field %s length: must be >= %d", "tests", 1)
 	}
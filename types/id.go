@@ -0,0 +1,43 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+)
+
+// ID identifies a flat test by its content rather than its name, so results
+// recorded against the test still find it after a rename. It covers the
+// inputs, validation, args, expectation, comparison settings and required
+// features, behaviors and variants; names, metadata and skips do not
+// contribute. Tests with the same content share an ID.
+func (tc TestCase) ID() string {
+	sorted := func(values []string) []string {
+		if len(values) == 0 {
+			return nil // Absent and empty lists are the same requirement
+		}
+		values = slices.Clone(values)
+		slices.Sort(values)
+		return values
+	}
+	data, _ := json.Marshal(struct {
+		Inputs               []string
+		Validation           string
+		Args                 []string
+		Default              *string `json:",omitempty"` // Omitted when unset, keeping the IDs of tests without one
+		Expected             interface{}
+		ExpectError          bool
+		ErrorType            string
+		Tolerance            float64
+		UnicodeNormalization UnicodeNormalization
+		Features             []string
+		Behaviors            []string
+		Variants             []string
+	}{
+		tc.Inputs, tc.Validation, tc.Args, tc.Default, tc.ExpectedValue(), tc.ExpectError, tc.ErrorType, tc.Tolerance,
+		tc.UnicodeNormalization, sorted(tc.Features), sorted(tc.Behaviors), sorted(tc.Variants),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTestCase_ID(t *testing.T) {
+	base := TestCase{
+		Name:       "parse_basic_parse",
+		Inputs:     []string{"a = b"},
+		Validation: "parse",
+		Expected:   []interface{}{map[string]interface{}{"key": "a", "value": "b"}},
+		Features:   []string{"unicode", "comments"},
+		Behaviors:  []string{},
+	}
+	id := base.ID()
+
+	same := base
+	same.Name, same.SourceTest, same.Meta = "renamed_parse", "renamed", TestMetadata{Tags: []string{"x"}}
+	same.Features = []string{"comments", "unicode"}
+	same.Behaviors = nil
+	if same.ID() != id {
+		t.Error("Expected names, metadata and requirement order not to change the ID")
+	}
+
+	lazy := base
+	lazy.Expected, lazy.RawExpected = nil, json.RawMessage(`{"count": 1, "entries": [{"value": "b", "key": "a"}]}`)
+	if lazy.ID() != id || lazy.RawExpected == nil {
+		t.Error("Expected a lazily loaded test to share the ID without being decoded")
+	}
+
+	for name, change := range map[string]func(*TestCase){
+		"inputs":     func(tc *TestCase) { tc.Inputs = []string{"a = c"} },
+		"validation": func(tc *TestCase) { tc.Validation = "filter" },
+		"expected":   func(tc *TestCase) { tc.Expected = nil },
+		"behaviors":  func(tc *TestCase) { tc.Behaviors = []string{"crlf_preserve_literal"} },
+	} {
+		changed := base
+		change(&changed)
+		if changed.ID() == id {
+			t.Errorf("Expected a different ID when %s change", name)
+		}
+	}
+}
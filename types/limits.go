@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// MinTimeoutMS is the smallest timeout_ms lint accepts; anything shorter
+// would fail on a loaded machine rather than flag a slow implementation
+const MinTimeoutMS = 100
+
+// Limits annotates a test that is slow by design, such as a
+// quadratic-blowup parse case
+type Limits struct {
+	TimeoutMS int  `json:"timeout_ms,omitempty"` // Per-test timeout, overriding the runner's global timeout
+	Slow      bool `json:"slow,omitempty"`       // Left out by LoadOptions.SkipSlow
+}
+
+// Timeout returns the test's own timeout, or zero if it has none
+func (l *Limits) Timeout() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return time.Duration(l.TimeoutMS) * time.Millisecond
+}
+
+// IsSlow reports whether the limits mark the test as slow
+func (l *Limits) IsSlow() bool {
+	return l != nil && l.Slow
+}
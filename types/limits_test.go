@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	var none *Limits
+	if none.Timeout() != 0 || none.IsSlow() {
+		t.Error("Expected nil limits to have no timeout and not be slow")
+	}
+	limits := &Limits{TimeoutMS: 1500, Slow: true}
+	if limits.Timeout() != 1500*time.Millisecond || !limits.IsSlow() {
+		t.Errorf("Unexpected limits: %v, %t", limits.Timeout(), limits.IsSlow())
+	}
+
+	for _, tt := range []struct {
+		timeoutMS int
+		want      int
+	}{{0, 0}, {MinTimeoutMS, 0}, {MinTimeoutMS - 1, 1}, {-5, 1}} {
+		tc := TestCase{Name: "x", Meta: TestMetadata{Limits: &Limits{TimeoutMS: tt.timeoutMS}}}
+		problems := tc.Validate()
+		if len(problems) != tt.want || (tt.want == 1 && problems[0].Code != CodeTimeoutFloor) {
+			t.Errorf("timeout_ms %d: expected %d timeout-floor problems, got %v", tt.timeoutMS, tt.want, problems)
+		}
+	}
+}
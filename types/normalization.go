@@ -0,0 +1,29 @@
+package types
+
+import "golang.org/x/text/unicode/norm"
+
+// UnicodeNormalization selects how strings are normalized before an
+// expected value is compared with an implementation's result
+type UnicodeNormalization string
+
+const (
+	NormalizationNone UnicodeNormalization = "none" // Compare strings byte for byte
+	NormalizationNFC  UnicodeNormalization = "nfc"  // Compare canonically composed strings
+)
+
+// Known reports whether n is a supported normalization; empty counts as none
+func (n UnicodeNormalization) Known() bool {
+	switch n {
+	case "", NormalizationNone, NormalizationNFC:
+		return true
+	}
+	return false
+}
+
+// Apply normalizes s
+func (n UnicodeNormalization) Apply(s string) string {
+	if n == NormalizationNFC {
+		return norm.NFC.String(s)
+	}
+	return s
+}
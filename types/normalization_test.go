@@ -0,0 +1,21 @@
+package types
+
+import "testing"
+
+func TestUnicodeNormalization_Apply(t *testing.T) {
+	composed, decomposed := "caf\u00e9", "cafe\u0301"
+	if got := NormalizationNFC.Apply(decomposed); got != composed {
+		t.Errorf("Expected NFC to compose %q, got %q", decomposed, got)
+	}
+	if got := NormalizationNone.Apply(decomposed); got != decomposed {
+		t.Errorf("Expected none to leave %q alone, got %q", decomposed, got)
+	}
+	for _, n := range []UnicodeNormalization{"", NormalizationNone, NormalizationNFC} {
+		if !n.Known() {
+			t.Errorf("Expected %q to be known", n)
+		}
+	}
+	if UnicodeNormalization("nfkd").Known() {
+		t.Error("Expected nfkd to be unknown")
+	}
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// OrderedMap is a JSON object that keeps its keys in source order, so
+// build_hierarchy expectations survive generation without being reordered.
+// Objects nested in its values are OrderedMaps too.
+type OrderedMap []OrderedPair
+
+// OrderedPair is one key and value of an OrderedMap
+type OrderedPair struct {
+	Key   string
+	Value interface{}
+}
+
+// Get returns the value for key
+func (m OrderedMap) Get(key string) (interface{}, bool) {
+	for _, pair := range m {
+		if pair.Key == key {
+			return pair.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the keys in order
+func (m OrderedMap) Keys() []string {
+	keys := make([]string, len(m))
+	for i, pair := range m {
+		keys[i] = pair.Key
+	}
+	return keys
+}
+
+// Map returns the object as plain maps, recursively, dropping key order
+func (m OrderedMap) Map() map[string]interface{} {
+	plain := make(map[string]interface{}, len(m))
+	for _, pair := range m {
+		plain[pair.Key] = plainValue(pair.Value)
+	}
+	return plain
+}
+
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case OrderedMap:
+		return v.Map()
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = plainValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+// MarshalJSON writes the object with its keys in order
+func (m OrderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, pair := range m {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(pair.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON reads a JSON object, keeping its key order
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	value, err := DecodeOrdered(data)
+	if err != nil {
+		return err
+	}
+	ordered, ok := value.(OrderedMap)
+	if !ok {
+		return fmt.Errorf("expected a JSON object, got %s", bytes.TrimSpace(data))
+	}
+	*m = ordered
+	return nil
+}
+
+// DecodeOrdered decodes JSON as json.Unmarshal does into an interface{},
+// except that objects become OrderedMaps
+func DecodeOrdered(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	value, err := decodeOrderedValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		m := OrderedMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, OrderedPair{Key: key.(string), Value: value})
+		}
+		_, err := dec.Token() // Closing brace
+		return m, err
+	case json.Delim('['):
+		items := []interface{}{}
+		for dec.More() {
+			item, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := dec.Token() // Closing bracket
+		return items, err
+	}
+	return token, nil
+}
+
+// orderedFromMap converts a plain map to an OrderedMap with sorted keys,
+// since its source order is unknown
+func orderedFromMap(plain map[string]interface{}) OrderedMap {
+	keys := make([]string, 0, len(plain))
+	for key := range plain {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	m := make(OrderedMap, len(keys))
+	for i, key := range keys {
+		m[i] = OrderedPair{Key: key, Value: orderedValue(plain[key])}
+	}
+	return m
+}
+
+func orderedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return orderedFromMap(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = orderedValue(item)
+		}
+		return items
+	}
+	return value
+}
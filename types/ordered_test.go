@@ -0,0 +1,56 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap_RoundTrip(t *testing.T) {
+	input := `{"zeta":1,"alpha":{"y":[{"b":true,"a":null}],"x":"s"},"mid":[1,2]}`
+	var m OrderedMap
+	if err := json.Unmarshal([]byte(input), &m); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"zeta", "alpha", "mid"}) {
+		t.Errorf("Expected source key order, got %v", got)
+	}
+	out, err := json.Marshal(m)
+	if err != nil || string(out) != input {
+		t.Errorf("Expected %s, got %s, %v", input, out, err)
+	}
+
+	var plain map[string]interface{}
+	json.Unmarshal([]byte(input), &plain)
+	if !reflect.DeepEqual(m.Map(), plain) {
+		t.Errorf("Expected Map to match a plain decode, got %v", m.Map())
+	}
+	if value, ok := m.Get("zeta"); !ok || value != 1.0 {
+		t.Errorf("Get(zeta) = %v, %v", value, ok)
+	}
+
+	if err := json.Unmarshal([]byte(`[1]`), &m); err == nil {
+		t.Error("Expected an error for a non-object")
+	}
+	if _, err := DecodeOrdered([]byte(`{} {}`)); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+}
+
+func TestTestCase_ExpectedObject(t *testing.T) {
+	lazy := TestCase{Validation: "build_hierarchy", RawExpected: json.RawMessage(`{"count": 1, "object": {"b": "2", "a": "1"}}`)}
+	object, ok := lazy.ExpectedObject()
+	if !ok || !reflect.DeepEqual(object.Keys(), []string{"b", "a"}) {
+		t.Errorf("Expected keys in source order, got %v, %v", object, ok)
+	}
+
+	decoded := TestCase{Validation: "build_hierarchy", Expected: map[string]interface{}{"b": "2", "a": "1"}}
+	object, ok = decoded.ExpectedObject()
+	if !ok || !reflect.DeepEqual(object.Keys(), []string{"a", "b"}) {
+		t.Errorf("Expected sorted keys for a decoded map, got %v, %v", object, ok)
+	}
+
+	if _, ok := (&TestCase{Validation: "get_string", Expected: "x"}).ExpectedObject(); ok {
+		t.Error("Expected no object for a string expectation")
+	}
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SanitizePolicy controls what Sanitize strips from tests before they are
+// shared outside the project. Inputs and expectations are never changed.
+type SanitizePolicy struct {
+	MetaFields  []string `json:"meta_fields,omitempty"`  // TestMetadata fields to clear, by JSON name: "tags", "conflicts", "feature", "difficulty", "override"
+	TagPrefixes []string `json:"tag_prefixes,omitempty"` // Tags starting with any of these are removed, e.g. "author:"
+	RedactURLs  bool     `json:"redact_urls,omitempty"`  // Replace URLs left in metadata and skip reasons with RedactedURL
+	HashNames   bool     `json:"hash_names,omitempty"`   // Replace Name and SourceTest with a hash; the NameMapping restores them
+	NameSalt    string   `json:"name_salt,omitempty"`    // Mixed into name hashes so they cannot be matched against known names
+}
+
+// RedactedURL replaces each URL when SanitizePolicy.RedactURLs is set
+const RedactedURL = "[redacted]"
+
+// sanitizeMetaFields clears each TestMetadata field SanitizePolicy.MetaFields can name
+var sanitizeMetaFields = map[string]func(*TestMetadata){
+	"tags":       func(m *TestMetadata) { m.Tags = nil },
+	"conflicts":  func(m *TestMetadata) { m.Conflicts = nil },
+	"feature":    func(m *TestMetadata) { m.Feature = "" },
+	"difficulty": func(m *TestMetadata) { m.Difficulty = "" },
+	"override":   func(m *TestMetadata) { m.Override = "" },
+}
+
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// NameMapping maps hashed test names back to the originals
+type NameMapping map[string]string
+
+// Restore returns copies of tests with hashed names replaced by the
+// originals. Names not in the mapping are kept.
+func (m NameMapping) Restore(tests []TestCase) []TestCase {
+	restored := slices.Clone(tests)
+	for i := range restored {
+		if name, ok := m[restored[i].Name]; ok {
+			restored[i].Name = name
+		}
+		if name, ok := m[restored[i].SourceTest]; ok {
+			restored[i].SourceTest = name
+		}
+	}
+	return restored
+}
+
+// Sanitize returns copies of tests with the metadata the policy names
+// removed, leaving tests untouched. The mapping is empty unless
+// policy.HashNames is set. An unknown field in policy.MetaFields is an
+// error rather than being ignored, so a typo cannot leak what it meant to strip.
+func Sanitize(tests []TestCase, policy SanitizePolicy) ([]TestCase, NameMapping, error) {
+	for _, field := range policy.MetaFields {
+		if sanitizeMetaFields[field] == nil {
+			return nil, nil, fmt.Errorf("sanitize: unknown meta field %q", field)
+		}
+	}
+
+	mapping := make(NameMapping)
+	hashName := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(policy.NameSalt + "\x00" + name))
+		hashed := "test_" + hex.EncodeToString(sum[:6])
+		mapping[hashed] = name
+		return hashed
+	}
+	redact := func(s string) string {
+		if !policy.RedactURLs {
+			return s
+		}
+		return urlPattern.ReplaceAllString(s, RedactedURL)
+	}
+	redactAll := func(values []string) []string {
+		for i, value := range values {
+			values[i] = redact(value)
+		}
+		return values
+	}
+
+	sanitized := make([]TestCase, len(tests))
+	for i, test := range tests {
+		test.Meta.Tags = slices.DeleteFunc(slices.Clone(test.Meta.Tags), func(tag string) bool {
+			return slices.ContainsFunc(policy.TagPrefixes, func(prefix string) bool {
+				return strings.HasPrefix(tag, prefix)
+			})
+		})
+		test.Meta.Conflicts = slices.Clone(test.Meta.Conflicts)
+		for _, field := range policy.MetaFields {
+			sanitizeMetaFields[field](&test.Meta)
+		}
+
+		test.Meta.Tags = redactAll(test.Meta.Tags)
+		test.Meta.Conflicts = redactAll(test.Meta.Conflicts)
+		test.Meta.Feature = redact(test.Meta.Feature)
+		test.Meta.Difficulty = redact(test.Meta.Difficulty)
+		test.Meta.Override = redact(test.Meta.Override)
+		if test.Skip != nil {
+			skip := *test.Skip
+			skip.Reason = redact(skip.Reason)
+			test.Skip = &skip
+		}
+
+		if policy.HashNames {
+			test.Name = hashName(test.Name)
+			test.SourceTest = hashName(test.SourceTest)
+		}
+		sanitized[i] = test
+	}
+	return sanitized, mapping, nil
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sanitizeTests() []TestCase {
+	return []TestCase{
+		{
+			Name:       "parse_basic_parse",
+			Inputs:     []string{"see = https://example.com/public"},
+			Validation: "parse",
+			Expected:   map[string]interface{}{"count": 1},
+			SourceTest: "parse_basic",
+			Skip:       &SkipInfo{Reason: "tracked at https://tracker.internal.example/ISSUE-7"},
+			Meta: TestMetadata{
+				Tags:       []string{"author:alice", "function:parse", "owner:team-x"},
+				Feature:    "comments",
+				Difficulty: "basic",
+				Override:   "chosen per https://wiki.internal.example/ccl/decisions",
+			},
+		},
+		{
+			Name:       "parse_basic_get_string",
+			Inputs:     []string{"a = b"},
+			Validation: "get_string",
+			Expected:   "b",
+			Args:       []string{"a"},
+			SourceTest: "parse_basic",
+		},
+	}
+}
+
+func TestSanitize_RemovesRedactedStrings(t *testing.T) {
+	tests := sanitizeTests()
+	sanitized, mapping, err := Sanitize(tests, SanitizePolicy{
+		MetaFields:  []string{"override", "difficulty"},
+		TagPrefixes: []string{"author:", "owner:"},
+		RedactURLs:  true,
+	})
+	if err != nil {
+		t.Fatalf("Sanitize failed: %v", err)
+	}
+	if len(mapping) != 0 {
+		t.Errorf("Expected no name mapping without HashNames, got %v", mapping)
+	}
+
+	data, err := json.Marshal(sanitized)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	for _, redacted := range []string{"alice", "team-x", "wiki.internal", "tracker.internal", "difficulty", "decisions"} {
+		if strings.Contains(string(data), redacted) {
+			t.Errorf("Sanitized output still contains %q: %s", redacted, data)
+		}
+	}
+
+	got := sanitized[0]
+	if !reflect.DeepEqual(got.Meta.Tags, []string{"function:parse"}) || got.Meta.Feature != "comments" {
+		t.Errorf("Expected unlisted metadata kept, got %+v", got.Meta)
+	}
+	if got.Skip.Reason != "tracked at "+RedactedURL {
+		t.Errorf("Expected the skip URL redacted, got %q", got.Skip.Reason)
+	}
+	if got.Inputs[0] != tests[0].Inputs[0] || !reflect.DeepEqual(got.Expected, tests[0].Expected) {
+		t.Error("Expected inputs and expectations unchanged")
+	}
+
+	// The originals are not modified
+	if !reflect.DeepEqual(tests, sanitizeTests()) {
+		t.Errorf("Expected Sanitize to leave its input alone, got %+v", tests)
+	}
+}
+
+func TestSanitize_HashNamesRestores(t *testing.T) {
+	tests := sanitizeTests()
+	sanitized, mapping, err := Sanitize(tests, SanitizePolicy{HashNames: true, NameSalt: "s3"})
+	if err != nil {
+		t.Fatalf("Sanitize failed: %v", err)
+	}
+
+	data, _ := json.Marshal(sanitized)
+	if strings.Contains(string(data), "parse_basic") {
+		t.Errorf("Expected names hashed, got %s", data)
+	}
+	if sanitized[0].SourceTest != sanitized[1].SourceTest || sanitized[0].Name == sanitized[1].Name {
+		t.Errorf("Expected hashing to keep names distinct and shared source names equal: %+v", sanitized)
+	}
+	if len(mapping) != 3 {
+		t.Errorf("Expected 3 mapped names, got %v", mapping)
+	}
+
+	if restored := mapping.Restore(sanitized); !reflect.DeepEqual(restored, tests) {
+		t.Errorf("Expected the mapping to restore the tests.\nGot:  %+v\nWant: %+v", restored, tests)
+	}
+
+	// A different salt gives different hashes
+	other, _, _ := Sanitize(tests, SanitizePolicy{HashNames: true})
+	if other[0].Name == sanitized[0].Name {
+		t.Error("Expected the salt to change the hashes")
+	}
+}
+
+func TestSanitize_UnknownField(t *testing.T) {
+	if _, _, err := Sanitize(sanitizeTests(), SanitizePolicy{MetaFields: []string{"tag"}}); err == nil || !strings.Contains(err.Error(), `"tag"`) {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
@@ -1,6 +1,6 @@
 package types
 
-//go:generate go run ../cmd/simplify-schema/main.go ../schemas/generated-format.json ../schemas/generated-format-simple.json
+//go:generate go run ../cmd/simplify-schema ../schemas/generated-format.json ../schemas/generated-format-simple.json
 //go:generate go-jsonschema -p generated -o generated/source_format.go ../schemas/source-format.json
 //go:generate go-jsonschema -p generated -o generated/flat_format.go ../schemas/generated-format-simple.json
 
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// Shapes a source test's expect value can take
+const (
+	ShapeEntries = "entry array"
+	ShapeObject  = "object"
+	ShapeArray   = "array"
+	ShapeScalar  = "scalar"
+)
+
+// ExpectedShapes is the shape of the expect value each validation produces
+// in source tests. Validations not listed are not checked.
+var ExpectedShapes = map[string]string{
+	string(config.FunctionParse):           ShapeEntries,
+	string(config.FunctionParseIndented):   ShapeEntries,
+	string(config.FunctionFilter):          ShapeEntries,
+	string(config.FunctionExtractComments): ShapeEntries,
+	string(config.FunctionCombine):         ShapeEntries,
+	"compose":                              ShapeEntries,
+	string(config.FunctionExpandDotted):    ShapeEntries,
+	string(config.FunctionBuildHierarchy):  ShapeObject,
+	string(config.FunctionGetString):       ShapeScalar,
+	string(config.FunctionGetInt):          ShapeScalar,
+	string(config.FunctionGetBool):         ShapeScalar,
+	string(config.FunctionGetFloat):        ShapeScalar,
+	string(config.FunctionGetList):         ShapeArray,
+}
+
+// CheckExpectedShape returns a ValidationError if a source test's expect
+// value does not have the shape its validation produces, or nil if it does.
+// Tests expecting an error are not checked.
+func CheckExpectedShape(validation string, expect interface{}) *ValidationError {
+	want, ok := ExpectedShapes[validation]
+	if !ok || shapeMatches(want, expect) {
+		return nil
+	}
+	got := shapeOf(expect)
+	if want == ShapeEntries && got == "array" {
+		got = "array with non-entry elements"
+	}
+	return &ValidationError{
+		Code:    CodeExpectedShape,
+		Message: fmt.Sprintf("%s expects %s, got %s", validation, withArticle(want), got),
+	}
+}
+
+func shapeMatches(want string, value interface{}) bool {
+	switch want {
+	case ShapeEntries:
+		entries, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, entry := range entries {
+			if _, ok := entry.(map[string]interface{}); !ok {
+				return false
+			}
+		}
+		return true
+	case ShapeObject:
+		switch value.(type) {
+		case map[string]interface{}, OrderedMap:
+			return true
+		}
+		return false
+	case ShapeArray:
+		_, ok := value.([]interface{})
+		return ok
+	case ShapeScalar:
+		switch value.(type) {
+		case string, bool, float64, int, int64, json.Number:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// shapeOf describes a decoded JSON value for error messages
+func shapeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}, OrderedMap:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+func withArticle(shape string) string {
+	if shape == ShapeScalar {
+		return "a " + shape
+	}
+	return "an " + shape
+}
@@ -0,0 +1,38 @@
+package types
+
+import "testing"
+
+func TestCheckExpectedShape(t *testing.T) {
+	entries := []interface{}{map[string]interface{}{"key": "a", "value": "1"}}
+	tests := []struct {
+		validation string
+		expect     interface{}
+		want       string
+	}{
+		{"parse", entries, ""},
+		{"parse", []interface{}{}, ""},
+		{"parse", "a = 1", "parse expects an entry array, got string"},
+		{"filter", []interface{}{"a"}, "filter expects an entry array, got array with non-entry elements"},
+		{"build_hierarchy", map[string]interface{}{"a": "1"}, ""},
+		{"build_hierarchy", entries, "build_hierarchy expects an object, got array"},
+		{"get_list", []interface{}{"1"}, ""},
+		{"get_list", "1", "get_list expects an array, got string"},
+		{"get_int", float64(1), ""},
+		{"get_bool", true, ""},
+		{"get_string", map[string]interface{}{}, "get_string expects a scalar, got object"},
+		{"get_float", nil, "get_float expects a scalar, got null"},
+		{"pretty_print", 42.0, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if problem := CheckExpectedShape(tt.validation, tt.expect); problem != nil {
+			got = problem.Message
+			if problem.Code != CodeExpectedShape {
+				t.Errorf("%s: expected code %s, got %s", tt.validation, CodeExpectedShape, problem.Code)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s with %v: expected %q, got %q", tt.validation, tt.expect, tt.want, got)
+		}
+	}
+}
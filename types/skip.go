@@ -0,0 +1,39 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// SkipDateLayout is the format of SkipInfo.Until
+const SkipDateLayout = "2006-01-02"
+
+// SkipInfo quarantines a test, e.g. while a spec dispute is settled, so it
+// stays in the suite instead of being deleted
+type SkipInfo struct {
+	Reason string `json:"reason"`
+	Until  string `json:"until,omitempty"` // Date (YYYY-MM-DD) after which the skip should be revisited
+}
+
+// Check reports what is wrong with the skip, or "" if nothing is
+func (s SkipInfo) Check() string {
+	if s.Reason == "" {
+		return "skip has no reason"
+	}
+	if s.Until != "" {
+		if _, err := time.Parse(SkipDateLayout, s.Until); err != nil {
+			return fmt.Sprintf("skip until %q is not a YYYY-MM-DD date", s.Until)
+		}
+	}
+	return ""
+}
+
+// Expired reports whether now is past the Until date. A skip without a
+// valid Until date never expires.
+func (s SkipInfo) Expired(now time.Time) bool {
+	until, err := time.Parse(SkipDateLayout, s.Until)
+	if err != nil {
+		return false
+	}
+	return !now.Before(until.AddDate(0, 0, 1)) // Skipped through the whole Until day
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkipInfo_Check(t *testing.T) {
+	tests := []struct {
+		skip SkipInfo
+		want string
+	}{
+		{SkipInfo{Reason: "dispute"}, ""},
+		{SkipInfo{Reason: "dispute", Until: "2025-06-01"}, ""},
+		{SkipInfo{Until: "2025-06-01"}, "skip has no reason"},
+		{SkipInfo{Reason: "dispute", Until: "June 1"}, `skip until "June 1" is not a YYYY-MM-DD date`},
+	}
+	for _, tt := range tests {
+		if got := tt.skip.Check(); got != tt.want {
+			t.Errorf("%+v: expected %q, got %q", tt.skip, tt.want, got)
+		}
+	}
+
+	tc := TestCase{Name: "x", Skip: &SkipInfo{}}
+	if problems := tc.Validate(); len(problems) != 1 || problems[0].Code != CodeInvalidSkip {
+		t.Errorf("Expected an invalid-skip problem, got %v", problems)
+	}
+}
+
+func TestSkipInfo_Expired(t *testing.T) {
+	skip := SkipInfo{Reason: "dispute", Until: "2025-06-01"}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, 5, 31, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := skip.Expired(tt.now); got != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.now, tt.want, got)
+		}
+	}
+	if (SkipInfo{Reason: "dispute"}).Expired(time.Now()) {
+		t.Error("Expected a skip without until never to expire")
+	}
+}
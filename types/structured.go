@@ -82,6 +82,7 @@ const (
 	FunctionParse           CCLFunction = "parse"
 	FunctionParseIndented   CCLFunction = "parse_indented"
 	FunctionFilter          CCLFunction = "filter"
+	FunctionExtractComments CCLFunction = "extract_comments"
 	FunctionCompose         CCLFunction = "compose"
 	FunctionExpandDotted    CCLFunction = "expand_dotted"
 	FunctionBuildHierarchy  CCLFunction = "build_hierarchy"
@@ -2,18 +2,29 @@
 // supporting both source and flat formats with type-safe metadata.
 package types
 
+import "encoding/json"
+
 // TestSuite represents both source and generated test suites
 type TestSuite struct {
-	Suite       string     `json:"suite"`
-	Version     string     `json:"version"`
-	Description string     `json:"description,omitempty"`
-	Tests       []TestCase `json:"tests"`
+	Suite       string         `json:"suite"`
+	Version     string         `json:"version"`
+	Description string         `json:"description,omitempty"`
+	Tests       []TestCase     `json:"tests"`
+	Generator   *GeneratorInfo `json:"generator,omitempty"` // What produced a generated file, if recorded
+}
+
+// GeneratorInfo records the library version and options that generated a
+// flat file
+type GeneratorInfo struct {
+	Version            string `json:"version"`
+	OptionsFingerprint string `json:"options_fingerprint"`
 }
 
 // TestCase supports both source (multi-validation) and flat (single-validation) formats
 type TestCase struct {
 	Name   string   `json:"name"`
-	Inputs []string `json:"inputs"` // CCL input text(s) - single-input tests use 1-element array
+	Suite  string   `json:"suite,omitempty"` // Named suite within a multi-suite file
+	Inputs []string `json:"inputs"`          // CCL input text(s) - single-input tests use 1-element array
 
 	// Source format: multiple validations
 	Validations *ValidationSet `json:"validations,omitempty"`
@@ -21,10 +32,26 @@ type TestCase struct {
 	// Flat format: single validation
 	Validation  string      `json:"validation,omitempty"`
 	Expected    interface{} `json:"expected,omitempty"`
-	Args        []string    `json:"args,omitempty"`
+	Args        []string    `json:"args,omitempty"`    // Key path for typed access functions
+	Default     *string     `json:"default,omitempty"` // Returned by a typed access function when the key path is absent; see HasDefault
 	ExpectError bool        `json:"expect_error,omitempty"`
+	ErrorType   string      `json:"error_type,omitempty"` // Kind of error expected, as implementations report it; any error matches when empty
+	Tolerance   float64     `json:"tolerance,omitempty"`  // Absolute epsilon for get_float; zero compares exactly
+
+	// Skip quarantines the test: it is still loaded, but counted apart in
+	// statistics and not run unless RunOptions.RunSkipped is set
+	Skip *SkipInfo `json:"skip,omitempty"`
 
-	// Type-safe metadata (replaces string tag parsing)
+	// UnicodeNormalization overrides RunOptions.UnicodeNormalization for this test
+	UnicodeNormalization UnicodeNormalization `json:"unicode_normalization,omitempty"`
+
+	// RawExpected holds Expected undecoded when loaded lazily; read it
+	// through ExpectedValue or the typed Expected* accessors
+	RawExpected json.RawMessage `json:"-"`
+
+	// Type-safe metadata (replaces string tag parsing). After Normalize,
+	// which loading and generation call, Features, Behaviors and Variants
+	// are never nil and Functions is nil when empty.
 	Functions []string `json:"functions,omitempty"`
 	Features  []string `json:"features"`
 	Behaviors []string `json:"behaviors"`
@@ -39,6 +66,28 @@ type TestCase struct {
 	SourceTest string `json:"source_test,omitempty"`
 }
 
+// Normalize makes the test's empty slices marshal the same however it was
+// built: Features, Behaviors and Variants become empty rather than nil, so
+// they are always written, and Functions and Args become nil when empty, so
+// they are omitted
+func (tc *TestCase) Normalize() {
+	if tc.Features == nil {
+		tc.Features = []string{}
+	}
+	if tc.Behaviors == nil {
+		tc.Behaviors = []string{}
+	}
+	if tc.Variants == nil {
+		tc.Variants = []string{}
+	}
+	if len(tc.Functions) == 0 {
+		tc.Functions = nil
+	}
+	if len(tc.Args) == 0 {
+		tc.Args = nil
+	}
+}
+
 // ConflictSet provides structured conflict resolution
 type ConflictSet struct {
 	Functions []string `json:"functions"`
@@ -52,6 +101,7 @@ type ValidationSet struct {
 	Parse              interface{} `json:"parse,omitempty"`
 	ParseIndented      interface{} `json:"parse_indented,omitempty"`
 	Filter             interface{} `json:"filter,omitempty"`
+	ExtractComments    interface{} `json:"extract_comments,omitempty"`
 	Combine            interface{} `json:"combine,omitempty"`
 	ExpandDotted       interface{} `json:"expand_dotted,omitempty"`
 	BuildHierarchy     interface{} `json:"build_hierarchy,omitempty"`
@@ -74,27 +124,74 @@ type TestMetadata struct {
 	Conflicts  []string `json:"conflicts,omitempty"`
 	Feature    string   `json:"feature,omitempty"`
 	Difficulty string   `json:"difficulty,omitempty"`
+	Level      int      `json:"level,omitempty"`    // MinLevel-MaxLevel; the loader fills it in when a file leaves it out
+	Override   string   `json:"override,omitempty"` // Why an implementation override replaced the expected outcome
+	Limits     *Limits  `json:"limits,omitempty"`   // Timeout and slowness annotations from the test's limits block
+
+	InputNormalized bool `json:"input_normalized,omitempty"` // Line endings in Inputs were normalized at load time
+	Flattened       bool `json:"flattened,omitempty"`        // Generated in memory from source tests because there were no generated tests
+	Assertions      int  `json:"assertions,omitempty"`       // Validations in a compact source test, counted at load time
+	ExpectedBytes   int  `json:"expected_bytes,omitempty"`   // Size of the generated expected value as compact JSON, recorded at load time
+
+	// UnknownMetadata lists the features, behaviors, and variants the
+	// loader found undefined in this library version, as "kind:name";
+	// such tests are incompatible with every config
+	UnknownMetadata []string `json:"unknown_metadata,omitempty"`
+
+	// EntrySources is the source expect index of each expected entry, read
+	// from generated "src_index" fields when LoadOptions.EntryProvenance is set
+	EntrySources []int `json:"entry_sources,omitempty"`
 }
 
 // TestStatistics provides comprehensive test suite analysis
 type TestStatistics struct {
 	TotalTests        int
-	TotalAssertions   int
+	TotalAssertions   int // Sum of TestCase.AssertionCount: one per flat test, one per validation of a source test
 	CompatibleTests   int
-	CompatibleAsserts int
-
+	CompatibleAsserts int  // Assertions of the compatible tests
+	SkippedTests      int  // Tests with Skip set, included in the counts above
+	SourceFallback    bool // Some tests were flattened from source tests in memory (see TestLoader.Flatten)
+	UnknownTests      int  // Tests with Meta.UnknownMetadata, incompatible with every config
+
+	// ByValidation counts each flat test once, under its Validation; its
+	// counts sum to TotalTests for flat suites. Source tests have none.
+	ByValidation map[string]int
+	// ByFunction counts the union of Validation and Functions metadata, so
+	// a test is counted under every function it touches, including its own
+	// validation twice when Functions also lists it
 	ByFunction map[string]int
 	ByFeature  map[string]int
+	BySuite    map[string]int // Tests of multi-suite files, by suite name
+	ByLevel    map[int]int    // Tests by Meta.Level
 
+	// ConflictingSets counts the tests rejected over a behavior or variant,
+	// keeping unmet requirements apart from conflicts with a choice
 	ConflictingSets []ConflictSummary
+
+	// LargestExpected lists the tests with the largest expected values,
+	// largest first
+	LargestExpected []ExpectedSize
+}
+
+// ExpectedSize is the size of one test's expected value
+type ExpectedSize struct {
+	Test  string `json:"test"`
+	Suite string `json:"suite,omitempty"`
+	Bytes int    `json:"bytes"` // Size of the expected value as JSON
+}
+
+// FileStatistics are the statistics of the tests in one test file
+type FileStatistics struct {
+	File  string         `json:"file"` // Path relative to the test directory, with forward slashes
+	Stats TestStatistics `json:"stats"`
 }
 
 // ConflictSummary provides analysis of conflicting test sets
 type ConflictSummary struct {
-	ConflictType  string // "behavior", "variant", "feature"
-	ConflictsWith []string
+	ConflictType  string   // "requirement" (a behavior or variant not chosen) or "conflict" (clashes with one that was)
+	ConflictsWith []string // The behavior or variant
 	TestCount     int
-	AssertCount   int
+	AssertCount   int // Sum of the tests' AssertionCount
 }
 
 // Entry represents a key-value pair from CCL parsing
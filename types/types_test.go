@@ -12,8 +12,8 @@ func TestTestSuite_JSONMarshaling(t *testing.T) {
 		Description: "Test suite description",
 		Tests: []TestCase{
 			{
-				Name:  "test1",
-				Input: "key = value",
+				Name:   "test1",
+				Inputs: []string{"key = value"},
 			},
 		},
 	}
@@ -44,8 +44,8 @@ func TestTestSuite_JSONMarshaling(t *testing.T) {
 
 func TestTestCase_SourceFormat(t *testing.T) {
 	testCase := TestCase{
-		Name:  "source_test",
-		Input: "key = value",
+		Name:   "source_test",
+		Inputs: []string{"key = value"},
 		Validations: &ValidationSet{
 			Parse:          []Entry{{Key: "key", Value: "value"}},
 			BuildHierarchy: map[string]interface{}{"key": "value"},
@@ -86,7 +86,7 @@ func TestTestCase_SourceFormat(t *testing.T) {
 func TestTestCase_FlatFormat(t *testing.T) {
 	testCase := TestCase{
 		Name:        "flat_test",
-		Input:       "key = value",
+		Inputs:      []string{"key = value"},
 		Validation:  "parse",
 		Expected:    []Entry{{Key: "key", Value: "value"}},
 		Args:        []string{},
@@ -157,21 +157,21 @@ func TestConflictSet_JSONHandling(t *testing.T) {
 
 func TestValidationSet_AllFields(t *testing.T) {
 	validations := ValidationSet{
-		Parse:          []Entry{{Key: "key", Value: "value"}},
-		ParseIndented:  "value",
-		Filter:         []Entry{{Key: "key", Value: "value"}},
-		Combine:        []Entry{{Key: "key", Value: "combined"}},
-		ExpandDotted:   []Entry{{Key: "foo.bar", Value: "expanded"}},
-		BuildHierarchy: map[string]interface{}{"foo": map[string]interface{}{"bar": "value"}},
-		GetString:      "string_value",
-		GetInt:         42,
-		GetBool:        true,
-		GetFloat:       3.14,
-		GetList:        []interface{}{"a", "b", "c"},
-		PrettyPrint:    "key = value\n",
-		RoundTrip:      "key = value",
-		Associativity:  true,
-		Canonical:      "canonical_format",
+		Parse:              []Entry{{Key: "key", Value: "value"}},
+		ParseIndented:      "value",
+		Filter:             []Entry{{Key: "key", Value: "value"}},
+		Combine:            []Entry{{Key: "key", Value: "combined"}},
+		ExpandDotted:       []Entry{{Key: "foo.bar", Value: "expanded"}},
+		BuildHierarchy:     map[string]interface{}{"foo": map[string]interface{}{"bar": "value"}},
+		GetString:          "string_value",
+		GetInt:             42,
+		GetBool:            true,
+		GetFloat:           3.14,
+		GetList:            []interface{}{"a", "b", "c"},
+		PrettyPrint:        "key = value\n",
+		RoundTrip:          "key = value",
+		ComposeAssociative: true,
+		Canonical:          "canonical_format",
 	}
 
 	// Test marshaling
@@ -343,7 +343,7 @@ func TestTestMetadata_LegacySupport(t *testing.T) {
 func TestTestCase_EmptySliceFields(t *testing.T) {
 	testCase := TestCase{
 		Name:      "empty_test",
-		Input:     "key = value",
+		Inputs:    []string{"key = value"},
 		Functions: []string{},
 		Features:  []string{},
 		Behaviors: []string{},
@@ -366,10 +366,36 @@ func TestTestCase_EmptySliceFields(t *testing.T) {
 	}
 }
 
+func TestTestCase_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		test TestCase
+		want string
+	}{
+		{"nil slices", TestCase{Name: "n"}, `{"name":"n","inputs":null,"features":[],"behaviors":[],"variants":[],"meta":{}}`},
+		{"empty slices", TestCase{Name: "n", Args: []string{}, Functions: []string{}, Features: []string{}, Behaviors: []string{}, Variants: []string{}},
+			`{"name":"n","inputs":null,"features":[],"behaviors":[],"variants":[],"meta":{}}`},
+		{"values kept", TestCase{Name: "n", Args: []string{"a"}, Functions: []string{"get_string"}, Features: []string{"comments"}},
+			`{"name":"n","inputs":null,"args":["a"],"functions":["get_string"],"features":["comments"],"behaviors":[],"variants":[],"meta":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test.Normalize()
+			data, err := json.Marshal(tt.test)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Got  %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
 func TestTestCase_NilConflicts(t *testing.T) {
 	testCase := TestCase{
 		Name:      "no_conflicts_test",
-		Input:     "key = value",
+		Inputs:    []string{"key = value"},
 		Conflicts: nil, // Explicitly nil
 	}
 
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+)
+
+// Codes identifying the problems reported by TestCase.Validate and the
+// CheckArgs, CheckInputs, CheckBehavior, and CheckExpectedShape source checks
+const (
+	CodeEmptyName         = "empty-name"
+	CodeEmptyInput        = "empty-input"
+	CodeMissingArgs       = "missing-args"
+	CodeUnexpectedArgs    = "unexpected-args"
+	CodeUnknownFeature    = "unknown-feature"
+	CodeUnknownBehavior   = "unknown-behavior"
+	CodeLevelRange        = "level-range"
+	CodeExpectedShape     = "expected-shape"
+	CodeNegativeTolerance = "negative-tolerance"
+	CodeInvalidSkip       = "invalid-skip"
+	CodeDefaultWithError  = "default-with-error"
+	CodeInputCount        = "input-count"
+	CodeTimeoutFloor      = "timeout-floor"
+)
+
+// Test levels accepted in Meta.Level and "level:N" tags
+const (
+	MinLevel = 1
+	MaxLevel = 5
+)
+
+// ValidationError is a problem with a single test case that the JSON
+// schema cannot express
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Validate checks a test case for structural problems. Flat tests are
+// checked against their single validation; source tests only for the
+// fields they share with flat tests. Args follow FunctionArity: typed access
+// takes a key path of one or more segments with no upper limit, reported as
+// missing-args when empty, and every other validation takes none, reported
+// as unexpected-args. A default goes in "default", never in args.
+func (tc TestCase) Validate() []ValidationError {
+	var problems []ValidationError
+	add := func(code, format string, args ...interface{}) {
+		problems = append(problems, ValidationError{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if tc.Name == "" {
+		add(CodeEmptyName, "test has no name")
+	}
+
+	if tc.Validation != "" {
+		if emptyInputs(tc.Inputs) && !tc.ExpectsError() {
+			add(CodeEmptyInput, "empty input with a non-error expectation")
+		}
+		if problem := CheckArgs(tc.Validation, tc.Args); problem != nil {
+			problems = append(problems, *problem)
+		}
+		if problem := CheckInputs(tc.Validation, tc.Inputs); problem != nil {
+			problems = append(problems, *problem)
+		}
+		if tc.HasDefault() && tc.ExpectsError() {
+			add(CodeDefaultWithError, "%s with default %q expects an error; a missing key returns the default", tc.Validation, *tc.Default)
+		}
+	}
+
+	if tc.Tolerance < 0 {
+		add(CodeNegativeTolerance, "tolerance %v is negative", tc.Tolerance)
+	}
+
+	if tc.Skip != nil {
+		if problem := tc.Skip.Check(); problem != "" {
+			add(CodeInvalidSkip, "%s", problem)
+		}
+	}
+
+	if limits := tc.Meta.Limits; limits != nil && limits.TimeoutMS != 0 && limits.TimeoutMS < MinTimeoutMS {
+		add(CodeTimeoutFloor, "timeout_ms %d is below the %d ms floor", limits.TimeoutMS, MinTimeoutMS)
+	}
+
+	known := make(map[string]bool)
+	for _, feature := range config.AllFeatures() {
+		known[string(feature)] = true
+	}
+	for _, feature := range tc.Features {
+		if !known[feature] {
+			add(CodeUnknownFeature, "unknown feature %q", feature)
+		}
+	}
+
+	for _, behavior := range tc.Behaviors {
+		if problem := CheckBehavior(behavior); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	if tc.Meta.Level != 0 && (tc.Meta.Level < MinLevel || tc.Meta.Level > MaxLevel) {
+		add(CodeLevelRange, "level %d outside %d-%d", tc.Meta.Level, MinLevel, MaxLevel)
+	}
+	for _, tag := range tc.Meta.Tags {
+		value, ok := strings.CutPrefix(tag, "level:")
+		if !ok {
+			continue
+		}
+		level, err := strconv.Atoi(value)
+		if err != nil || level < MinLevel || level > MaxLevel {
+			add(CodeLevelRange, "level %q outside %d-%d", value, MinLevel, MaxLevel)
+		}
+	}
+
+	return problems
+}
+
+// CheckBehavior returns a ValidationError if behavior is neither a config
+// constant nor registered with config.RegisterBehavior, suggesting the
+// closest known behavior, or nil otherwise
+func CheckBehavior(behavior string) *ValidationError {
+	if config.IsKnownBehavior(behavior) {
+		return nil
+	}
+	message := fmt.Sprintf("unknown behavior %q", behavior)
+	if suggestion, ok := config.SuggestBehavior(behavior); ok {
+		message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return &ValidationError{Code: CodeUnknownBehavior, Message: message}
+}
+
+// ExpectsError reports whether a flat test expects its validation to fail
+func (tc TestCase) ExpectsError() bool {
+	if tc.ExpectError {
+		return true
+	}
+	expected, ok := tc.Expected.(map[string]interface{})
+	return ok && expected["error"] == true
+}
+
+// HasDefault reports whether a flat test passes a default value: a typed
+// access test with Default set reads the key path Args, and if it is absent
+// expects the default back rather than an error
+func (tc TestCase) HasDefault() bool {
+	return IsTypedAccessFunction(tc.Validation) && tc.Default != nil
+}
+
+// IsTypedAccessFunction reports whether a validation reads a value by key path
+func IsTypedAccessFunction(validation string) bool {
+	switch config.CCLFunction(validation) {
+	case config.FunctionGetString, config.FunctionGetInt, config.FunctionGetBool,
+		config.FunctionGetFloat, config.FunctionGetList:
+		return true
+	}
+	return false
+}
+
+// emptyInputs reports whether there is no input text at all
+func emptyInputs(inputs []string) bool {
+	for _, input := range inputs {
+		if input != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func validationCodes(tc TestCase) []string {
+	var codes []string
+	for _, problem := range tc.Validate() {
+		codes = append(codes, problem.Code)
+	}
+	return codes
+}
+
+func TestTestCase_Validate(t *testing.T) {
+	valid := TestCase{Name: "basic_parse", Inputs: []string{"a = b"}, Validation: "parse", Features: []string{"comments"}}
+	fallback := "1"
+
+	tests := []struct {
+		name   string
+		modify func(*TestCase)
+		want   []string
+	}{
+		{"valid", func(tc *TestCase) {}, nil},
+		{"empty name", func(tc *TestCase) { tc.Name = "" }, []string{CodeEmptyName}},
+		{"empty input", func(tc *TestCase) { tc.Inputs = []string{""} }, []string{CodeEmptyInput}},
+		{"empty input expecting error", func(tc *TestCase) { tc.Inputs = nil; tc.ExpectError = true }, nil},
+		{"empty input with error expectation", func(tc *TestCase) {
+			tc.Inputs = nil
+			tc.Expected = map[string]interface{}{"error": true}
+		}, nil},
+		{"missing args", func(tc *TestCase) { tc.Validation = "get_int" }, []string{CodeMissingArgs}},
+		{"args present", func(tc *TestCase) { tc.Validation = "get_int"; tc.Args = []string{"a"} }, nil},
+		{"nested path", func(tc *TestCase) { tc.Validation = "get_int"; tc.Args = []string{"a", "b"} }, nil},
+		{"nested path expecting error", func(tc *TestCase) {
+			tc.Validation = "get_int"
+			tc.Args = []string{"a", "b"}
+			tc.ExpectError = true
+		}, nil},
+		{"default", func(tc *TestCase) { tc.Validation = "get_int"; tc.Args = []string{"a"}; tc.Default = &fallback }, nil},
+		{"default expecting error", func(tc *TestCase) {
+			tc.Validation = "get_int"
+			tc.Args = []string{"a"}
+			tc.Default = &fallback
+			tc.ExpectError = true
+		}, []string{CodeDefaultWithError}},
+		{"unexpected args", func(tc *TestCase) { tc.Args = []string{"a"} }, []string{CodeUnexpectedArgs}},
+		{"combine with two inputs", func(tc *TestCase) { tc.Validation = "combine"; tc.Inputs = []string{"a = 1", "b = 2"} }, nil},
+		{"combine with one input", func(tc *TestCase) { tc.Validation = "combine" }, []string{CodeInputCount}},
+		{"unknown feature", func(tc *TestCase) { tc.Features = []string{"comments", "telepathy"} }, []string{CodeUnknownFeature}},
+		{"unknown behavior", func(tc *TestCase) { tc.Behaviors = []string{"boolean_strict", "boolean_leniant"} }, []string{CodeUnknownBehavior}},
+		{"level in range", func(tc *TestCase) { tc.Meta.Tags = []string{"level:1", "level:5", "other"} }, nil},
+		{"level out of range", func(tc *TestCase) { tc.Meta.Tags = []string{"level:0", "level:six"} }, []string{CodeLevelRange, CodeLevelRange}},
+		{"meta level out of range", func(tc *TestCase) { tc.Meta.Level = 6 }, []string{CodeLevelRange}},
+		{"source test skips validation checks", func(tc *TestCase) { tc.Validation = ""; tc.Inputs = nil }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := valid
+			tt.modify(&tc)
+			if got := validationCodes(tc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected codes %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckBehavior(t *testing.T) {
+	if problem := CheckBehavior("tabs_as_content"); problem != nil {
+		t.Errorf("Expected a known behavior to pass, got %v", problem)
+	}
+	tests := map[string]string{
+		"boolean_leniant": `unknown-behavior: unknown behavior "boolean_leniant" (did you mean "boolean_lenient"?)`,
+		"yaml_anchors":    `unknown-behavior: unknown behavior "yaml_anchors"`,
+	}
+	for behavior, want := range tests {
+		problem := CheckBehavior(behavior)
+		if problem == nil || problem.Error() != want {
+			t.Errorf("CheckBehavior(%q) = %v, want %s", behavior, problem, want)
+		}
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := ValidationError{Code: CodeMissingArgs, Message: "get_int requires args"}
+	if got := err.Error(); got != "missing-args: get_int requires args" {
+		t.Errorf("Unexpected error string %q", got)
+	}
+}
@@ -0,0 +1,420 @@
+package ccl_test_lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CatConfLang/ccl-test-lib/config"
+	"github.com/CatConfLang/ccl-test-lib/generator"
+	"github.com/CatConfLang/ccl-test-lib/loader"
+	"github.com/CatConfLang/ccl-test-lib/report"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// VerifyCheck names one of the checks VerifyTestData runs
+type VerifyCheck string
+
+const (
+	VerifySources    VerifyCheck = "sources"    // source_tests pass validation
+	VerifyGeneration VerifyCheck = "generation" // generated_tests match a fresh generation from source_tests
+	VerifySchema     VerifyCheck = "schema"     // generated_tests parse and pass validation; schemas have not drifted
+	VerifyDuplicates VerifyCheck = "duplicates" // no test name is used in more than one generated file
+	VerifyManifest   VerifyCheck = "manifest"   // generated files match the test counts and fingerprint in manifest.json
+	VerifyStats      VerifyCheck = "stats"      // statistics have not fallen below a baseline
+	VerifyLinks      VerifyCheck = "links"      // source_test links between sources and generated tests hold
+)
+
+// VerifyChecks returns every check in the order VerifyTestData runs them
+func VerifyChecks() []VerifyCheck {
+	return []VerifyCheck{VerifySources, VerifyGeneration, VerifySchema, VerifyDuplicates, VerifyManifest, VerifyStats, VerifyLinks}
+}
+
+// VerifyResult is the outcome of one check
+type VerifyResult struct {
+	Check    VerifyCheck
+	Summary  string   // What passed, or why the check was skipped
+	Warnings []string // Problems that do not fail the check
+	Skipped  bool     // Disabled, or nothing to check (e.g. no source_tests)
+	Err      error    // Set when the check failed
+}
+
+// VerifyError lists every check VerifyTestData found failing
+type VerifyError struct {
+	Failures []VerifyResult // In check order
+}
+
+func (e *VerifyError) Error() string {
+	lines := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		lines[i] = fmt.Sprintf("%s: %v", failure.Check, failure.Err)
+	}
+	return "test data verification failed:\n" + strings.Join(lines, "\n")
+}
+
+func (e *VerifyError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// VerifyOption customizes VerifyTestData
+type VerifyOption func(*verifyOptions)
+
+type verifyOptions struct {
+	skip      map[VerifyCheck]bool
+	baseline  string
+	schemaDir string
+	fix       bool
+	report    func(VerifyResult)
+}
+
+// WithVerifySkip disables checks
+func WithVerifySkip(checks ...VerifyCheck) VerifyOption {
+	return func(o *verifyOptions) {
+		for _, check := range checks {
+			o.skip[check] = true
+		}
+	}
+}
+
+// WithVerifyBaseline enables the stats check against a statistics snapshot
+// (ccltest stats --format json)
+func WithVerifyBaseline(path string) VerifyOption {
+	return func(o *verifyOptions) { o.baseline = path }
+}
+
+// WithVerifySchemaDir makes the schema check also compare the schemas in dir
+// with the ones this library was built from
+func WithVerifySchemaDir(dir string) VerifyOption {
+	return func(o *verifyOptions) { o.schemaDir = dir }
+}
+
+// WithVerifyFix makes the generation check regenerate stale generated_tests
+// instead of failing
+func WithVerifyFix() VerifyOption {
+	return func(o *verifyOptions) { o.fix = true }
+}
+
+// WithVerifyReport calls fn with the result of each check, including those
+// that pass or are skipped, as it completes
+func WithVerifyReport(fn func(VerifyResult)) VerifyOption {
+	return func(o *verifyOptions) { o.report = fn }
+}
+
+// verifier holds the paths and settings of one VerifyTestData call
+type verifier struct {
+	dir          string
+	sourceDir    string
+	generatedDir string
+	opts         verifyOptions
+}
+
+// VerifyTestData checks that the test data at path is sane before it is
+// used: generated_tests parse, pass validation, and share no test names
+// across files, and agree with manifest.json when there is one. When
+// source_tests is vendored too, the sources must validate, generated_tests
+// must be up to date with them, and source_test links must hold. The stats
+// check runs only with WithVerifyBaseline.
+//
+// Every check runs even after one fails; the returned *VerifyError lists
+// each failure.
+func VerifyTestData(path string, opts ...VerifyOption) error {
+	o := verifyOptions{skip: make(map[VerifyCheck]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for check := range o.skip {
+		if !isVerifyCheck(check) {
+			return fmt.Errorf("unknown verify check %q", check)
+		}
+	}
+
+	layout := testLayout(path)
+	v := &verifier{
+		dir:          path,
+		sourceDir:    layout.Dir(loader.FormatCompact),
+		generatedDir: layout.Dir(loader.FormatFlat),
+		opts:         o,
+	}
+	_, statErr := os.Stat(v.sourceDir)
+	hasSources := statErr == nil
+
+	var failures []VerifyResult
+	for _, check := range VerifyChecks() {
+		result := VerifyResult{Check: check}
+		switch {
+		case o.skip[check]:
+			result.Skipped = true
+		case !hasSources && (check == VerifySources || check == VerifyGeneration || check == VerifyLinks):
+			result.Skipped, result.Summary = true, "no source_tests"
+		default:
+			result = v.run(check)
+		}
+		if result.Err != nil {
+			failures = append(failures, result)
+		}
+		if o.report != nil {
+			o.report(result)
+		}
+	}
+	if len(failures) > 0 {
+		return &VerifyError{Failures: failures}
+	}
+	return nil
+}
+
+func isVerifyCheck(check VerifyCheck) bool {
+	for _, known := range VerifyChecks() {
+		if known == check {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs one enabled check
+func (v *verifier) run(check VerifyCheck) VerifyResult {
+	result := VerifyResult{Check: check}
+	switch check {
+	case VerifySources:
+		result.Summary, result.Err = validateTestDir(v.sourceDir)
+	case VerifyGeneration:
+		result.Summary, result.Warnings, result.Err = v.checkGeneration()
+	case VerifySchema:
+		result.Summary, result.Err = v.checkSchema()
+	case VerifyDuplicates:
+		result.Summary, result.Err = v.checkDuplicates()
+	case VerifyManifest:
+		result.Summary, result.Skipped, result.Err = v.checkManifest()
+	case VerifyStats:
+		if v.opts.baseline == "" {
+			result.Skipped, result.Summary = true, "no baseline given"
+			break
+		}
+		result.Summary, result.Err = v.checkStats()
+	case VerifyLinks:
+		result.Summary, result.Err = v.checkLinks()
+	}
+	return result
+}
+
+// checkGeneration regenerates into a temporary directory and compares the
+// result with generated_tests, replacing it with WithVerifyFix
+func (v *verifier) checkGeneration() (string, []string, error) {
+	fresh, err := os.MkdirTemp("", "ccl-verify-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(fresh)
+
+	gen := generator.NewFlatGenerator(v.sourceDir, fresh, generator.GenerateOptions{SourceFormat: generator.FormatCompact})
+	if err := gen.GenerateAll(); err != nil {
+		return "", nil, err
+	}
+	after, err := report.LoadFlatDir(fresh)
+	if err != nil {
+		return "", nil, err
+	}
+	// A missing or empty generated_tests is stale, not an error
+	before, _ := report.LoadFlatDir(v.generatedDir)
+	warnings, _ := generator.CheckProvenance(v.generatedDir)
+
+	diff := report.DiffTests(before, after)
+	if diff.Empty() {
+		return fmt.Sprintf("%d tests up to date", len(after)), warnings, nil
+	}
+	if !v.opts.fix {
+		return "", warnings, fmt.Errorf("generated_tests is stale; regenerate it with ccltest generate or verify --fix\n%s", strings.TrimSuffix(diff.String(), "\n"))
+	}
+	if err := replaceJSONFiles(fresh, v.generatedDir); err != nil {
+		return "", warnings, fmt.Errorf("failed to regenerate: %w", err)
+	}
+	return fmt.Sprintf("regenerated (%d added, %d removed, %d changed)", len(diff.Added), len(diff.Removed), len(diff.Changed)), warnings, nil
+}
+
+// checkSchema validates the generated tests and, with WithVerifySchemaDir,
+// checks the schemas have not drifted from the library's
+func (v *verifier) checkSchema() (string, error) {
+	summary, err := validateTestDir(v.generatedDir)
+	if err != nil || v.opts.schemaDir == "" {
+		return summary, err
+	}
+	drift, err := SchemaDrift(v.opts.schemaDir)
+	if err != nil {
+		return "", err
+	}
+	if len(drift) > 0 {
+		return "", fmt.Errorf("schemas drifted; run schema-sync\n  %s", strings.Join(drift, "\n  "))
+	}
+	return summary + ", schemas match", nil
+}
+
+// checkDuplicates reports test names defined in more than one generated file.
+// Duplicates within a file are the schema check's concern.
+func (v *verifier) checkDuplicates() (string, error) {
+	tests, err := report.LoadFlatDir(v.generatedDir)
+	if err != nil {
+		return "", err
+	}
+	files := make(map[string][]string)
+	for _, test := range tests {
+		if seen := files[test.Name]; len(seen) == 0 || seen[len(seen)-1] != test.File {
+			files[test.Name] = append(seen, test.File)
+		}
+	}
+	var lines []string
+	for name, in := range files {
+		if len(in) > 1 {
+			lines = append(lines, fmt.Sprintf("%s: in %s", name, strings.Join(in, ", ")))
+		}
+	}
+	if len(lines) > 0 {
+		sort.Strings(lines)
+		return "", fmt.Errorf("%d test names used in several files\n  %s", len(lines), strings.Join(lines, "\n  "))
+	}
+	return fmt.Sprintf("%d test names unique", len(files)), nil
+}
+
+// checkManifest compares generated_tests with manifest.json, in the layout
+// generator.GenerateBundles writes: each listed file must hold the recorded
+// number of tests. A manifest with a fingerprint, as ExportCompatibleTests
+// writes, must also match the hash of the listed files. Without a manifest
+// the check is skipped.
+func (v *verifier) checkManifest() (string, bool, error) {
+	manifest, err := ReadExportManifest(v.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "no " + ExportManifestFile, true, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	names := make([]string, 0, len(manifest.Files))
+	for name := range manifest.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	hash := sha256.New()
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(v.generatedDir, name))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: missing", name))
+			continue
+		}
+		hash.Write([]byte(name + "\x00"))
+		hash.Write(data)
+		var suite struct {
+			Tests []json.RawMessage `json:"tests"`
+		}
+		if err := json.Unmarshal(data, &suite); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		} else if len(suite.Tests) != manifest.Files[name] {
+			problems = append(problems, fmt.Sprintf("%s: %d tests, manifest says %d", name, len(suite.Tests), manifest.Files[name]))
+		}
+	}
+	if len(problems) == 0 && manifest.Fingerprint != "" && hex.EncodeToString(hash.Sum(nil)) != manifest.Fingerprint {
+		problems = append(problems, "fingerprint differs from the manifest's")
+	}
+	if len(problems) > 0 {
+		return "", false, fmt.Errorf("generated_tests does not match %s\n  %s", ExportManifestFile, strings.Join(problems, "\n  "))
+	}
+	return fmt.Sprintf("%d files match %s", len(names), ExportManifestFile), false, nil
+}
+
+// checkStats compares the generated test statistics with the baseline
+func (v *verifier) checkStats() (string, error) {
+	data, err := os.ReadFile(v.opts.baseline)
+	if err != nil {
+		return "", fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline types.TestStatistics
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return "", fmt.Errorf("failed to parse baseline: %w", err)
+	}
+
+	current, err := GetTestStats(v.dir, config.ImplementationConfig{})
+	if err != nil {
+		return "", err
+	}
+	if regressions := report.StatisticsRegressions(baseline, current); len(regressions) > 0 {
+		return "", fmt.Errorf("statistics regressed against %s\n  %s", v.opts.baseline, strings.Join(regressions, "\n  "))
+	}
+	return fmt.Sprintf("%d tests, no regressions", current.TotalTests), nil
+}
+
+// checkLinks checks that generated tests and source tests still refer to
+// each other through source_test
+func (v *verifier) checkLinks() (string, error) {
+	links, err := loader.VerifySourceLinks(v.dir)
+	if err != nil {
+		return "", err
+	}
+	if !links.Empty() {
+		return "", fmt.Errorf("%d broken source links\n  %s", len(links.Dangling)+len(links.Orphaned), strings.Join(links.Lines(), "\n  "))
+	}
+	return "all source links intact", nil
+}
+
+// validateTestDir runs the loader's schema and structural validation over dir
+func validateTestDir(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no test files in %s", dir)
+	}
+	issues, err := loader.ValidateTestDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(issues) > 0 {
+		lines := make([]string, len(issues))
+		for i, issue := range issues {
+			lines[i] = issue.String()
+		}
+		return "", fmt.Errorf("%d issues\n  %s", len(issues), strings.Join(lines, "\n  "))
+	}
+	return fmt.Sprintf("%d files valid", len(files)), nil
+}
+
+// replaceJSONFiles makes the *.json files in dst match those in src
+func replaceJSONFiles(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	existing, err := filepath.Glob(filepath.Join(dst, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range existing {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(src, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dst, filepath.Base(file)), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
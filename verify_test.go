@@ -0,0 +1,127 @@
+package ccl_test_lib
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CatConfLang/ccl-test-lib/testsupport"
+	"github.com/CatConfLang/ccl-test-lib/types"
+)
+
+// collectVerify runs VerifyTestData and returns the summary of each check,
+// prefixed with its outcome
+func collectVerify(t *testing.T, path string, opts ...VerifyOption) (map[VerifyCheck]string, error) {
+	t.Helper()
+	outcomes := make(map[VerifyCheck]string)
+	opts = append(opts, WithVerifyReport(func(result VerifyResult) {
+		switch {
+		case result.Err != nil:
+			outcomes[result.Check] = "FAIL " + result.Err.Error()
+		case result.Skipped:
+			outcomes[result.Check] = "SKIP " + result.Summary
+		default:
+			outcomes[result.Check] = "PASS " + result.Summary
+		}
+	}))
+	err := VerifyTestData(path, opts...)
+	return outcomes, err
+}
+
+// duplicatedFlatData has generated tests only, with one name in two files
+func duplicatedFlatData(t *testing.T) string {
+	test := types.TestCase{
+		Name:       "shared_parse",
+		Inputs:     []string{"a = b"},
+		Validation: "parse",
+		Expected:   map[string]interface{}{"count": 1, "entries": []interface{}{map[string]interface{}{"key": "a", "value": "b"}}},
+		Functions:  []string{"parse"},
+		Features:   []string{},
+		Behaviors:  []string{},
+		Variants:   []string{},
+	}
+	return testsupport.NewFixture(t).WithFlatFile("one.json", test).WithFlatFile("two.json", test).Build()
+}
+
+func TestVerifyTestData_Pass(t *testing.T) {
+	root := testsupport.Minimal(t).Build()
+
+	outcomes, err := collectVerify(t, root)
+	if err != nil {
+		t.Fatalf("Expected verification to pass, got %v", err)
+	}
+	want := map[VerifyCheck]string{
+		VerifySources:    "PASS 1 files valid",
+		VerifyGeneration: "PASS 2 tests up to date",
+		VerifySchema:     "PASS 1 files valid",
+		VerifyDuplicates: "PASS 2 test names unique",
+		VerifyManifest:   "SKIP no manifest.json",
+		VerifyStats:      "SKIP no baseline given",
+		VerifyLinks:      "PASS all source links intact",
+	}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("Unexpected outcomes.\nGot:  %v\nWant: %v", outcomes, want)
+	}
+}
+
+func TestVerifyTestData_SingleFailure(t *testing.T) {
+	root := duplicatedFlatData(t)
+
+	outcomes, err := collectVerify(t, root)
+	var failed *VerifyError
+	if !errors.As(err, &failed) {
+		t.Fatalf("Expected a VerifyError, got %v", err)
+	}
+	if len(failed.Failures) != 1 || failed.Failures[0].Check != VerifyDuplicates {
+		t.Fatalf("Expected only the duplicates check to fail, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "shared_parse: in one.json, two.json") {
+		t.Errorf("Expected the duplicate listed, got %v", err)
+	}
+	// Without sources their checks have nothing to do
+	if outcomes[VerifySources] != "SKIP no source_tests" || outcomes[VerifySchema] != "PASS 2 files valid" {
+		t.Errorf("Unexpected outcomes: %v", outcomes)
+	}
+}
+
+func TestVerifyTestData_SkippedCheck(t *testing.T) {
+	root := duplicatedFlatData(t)
+
+	outcomes, err := collectVerify(t, root, WithVerifySkip(VerifyDuplicates))
+	if err != nil {
+		t.Fatalf("Expected the skipped check not to fail, got %v", err)
+	}
+	if outcomes[VerifyDuplicates] != "SKIP " {
+		t.Errorf("Expected duplicates skipped, got %q", outcomes[VerifyDuplicates])
+	}
+
+	if err := VerifyTestData(root, WithVerifySkip("nonsense")); err == nil || !strings.Contains(err.Error(), `"nonsense"`) {
+		t.Errorf("Expected an unknown check error, got %v", err)
+	}
+}
+
+func TestVerifyTestData_Manifest(t *testing.T) {
+	root := testsupport.Minimal(t).Build()
+	manifest := filepath.Join(root, ExportManifestFile)
+
+	os.WriteFile(manifest, []byte(`{"files": {"api_basic.json": 2}}`), 0644)
+	if outcomes, err := collectVerify(t, root); err != nil || outcomes[VerifyManifest] != "PASS 1 files match manifest.json" {
+		t.Errorf("Expected the manifest to match, got %v: %v", outcomes[VerifyManifest], err)
+	}
+
+	os.WriteFile(manifest, []byte(`{"fingerprint": "0000", "files": {"api_basic.json": 3, "gone.json": 1}}`), 0644)
+	err := VerifyTestData(root)
+	for _, want := range []string{"manifest: generated_tests does not match manifest.json", "api_basic.json: 2 tests, manifest says 3", "gone.json: missing"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+
+	os.WriteFile(manifest, []byte(`{"fingerprint": "0000", "files": {"api_basic.json": 2}}`), 0644)
+	if err := VerifyTestData(root); err == nil || !strings.Contains(err.Error(), "fingerprint differs") {
+		t.Errorf("Expected a fingerprint mismatch, got %v", err)
+	}
+}